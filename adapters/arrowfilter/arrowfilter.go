@@ -0,0 +1,524 @@
+// Package arrowfilter converts where filters into Apache Arrow compute expressions and Parquet
+// row-group filters, so that data-lake readers can push the same user filter down to file scans
+// instead of filtering rows after they are read.
+//
+// The package does not depend on Arrow. ToExpression returns a tree of calls named after Arrow's
+// compute functions, e.g. greater_equal, and_kleene, and is_in, which maps one-to-one onto
+// compute.NewCall, compute.NewFieldRef, and compute.NewLiteral, or onto the expressions of another
+// Arrow implementation. RowGroupFilter returns the filter as an OR of ANDs of column predicates, the
+// form Parquet readers use to skip row groups with min/max statistics.
+package arrowfilter
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/where"
+)
+
+var (
+	// comparisons maps the comparison operators of a filter to Arrow compute functions.
+	comparisons = map[string]string{
+		"=":  "equal",
+		"!=": "not_equal",
+		"<>": "not_equal",
+		"<":  "less",
+		"<=": "less_equal",
+		">":  "greater",
+		">=": "greater_equal",
+	}
+
+	// functions maps filter functions to the Arrow compute functions implementing them.
+	functions = map[string]string{
+		"ABS":      "abs",
+		"CEIL":     "ceil",
+		"COALESCE": "coalesce",
+		"FLOOR":    "floor",
+		"LENGTH":   "utf8_length",
+		"LOWER":    "utf8_lower",
+		"LTRIM":    "utf8_ltrim_whitespace",
+		"ROUND":    "round",
+		"RTRIM":    "utf8_rtrim_whitespace",
+		"TRIM":     "utf8_trim_whitespace",
+		"UPPER":    "utf8_upper",
+	}
+
+	// bitwise maps the bitwise operators of a filter to Arrow compute functions.
+	bitwise = map[string]string{
+		"&":  "bit_wise_and",
+		"|":  "bit_wise_or",
+		"^":  "bit_wise_xor",
+		"<<": "shift_left",
+		">>": "shift_right",
+	}
+)
+
+type (
+	// Option configures the conversion of a filter.
+	Option func(*converter)
+
+	// Expression is an Arrow compute expression: a *FieldRef, a *Literal, or a *Call.
+	Expression interface {
+		String() string
+	}
+
+	// FieldRef references a column, or a nested field of a struct column when Path has more than
+	// one name.
+	FieldRef struct {
+		Path []string
+	}
+
+	// Literal is a scalar value: a string, int64, float64, bool, []byte, or nil for NULL. Values
+	// bound to variables are used as given.
+	Literal struct {
+		Value any
+	}
+
+	// Call is a call to the Arrow compute function named Function. Options is nil, or the
+	// *SetLookupOptions or *MatchSubstringOptions the function takes.
+	Call struct {
+		Function string
+		Args     []Expression
+		Options  any
+	}
+
+	// SetLookupOptions are the options of is_in, holding the values of an IN list.
+	SetLookupOptions struct {
+		ValueSet []any
+	}
+
+	// MatchSubstringOptions are the options of match_like, holding a LIKE pattern, which uses
+	// backslash escapes as filters do.
+	MatchSubstringOptions struct {
+		Pattern    string
+		IgnoreCase bool
+	}
+
+	// converter converts the filter AST into Arrow expressions.
+	converter struct {
+		validator *where.Validator
+		variables map[string]any
+	}
+)
+
+// WithValidator rejects filters using fields or functions the validator does not allow.
+func WithValidator(v *where.Validator) Option {
+	return func(c *converter) {
+		c.validator = v
+	}
+}
+
+// WithVariables supplies the values of the :name variables in a filter template, like
+// where.WithVariables. A slice bound to a variable in an IN list is expanded into its elements.
+func WithVariables(vars map[string]any) Option {
+	return func(c *converter) {
+		for name, value := range vars {
+			c.variables[strings.TrimPrefix(name, ":")] = value
+		}
+	}
+}
+
+// ToExpression converts a filter into an Arrow compute expression with the same meaning. AND, OR,
+// and NOT become and_kleene, or_kleene, and invert, which follow SQL's three-valued logic. LIKE and
+// ILIKE become match_like, IN becomes is_in, and functions without an Arrow equivalent, CAST
+// expressions, and MATCHES and EXISTS conditions are rejected.
+//
+// Example:
+//
+//	filter, _ := where.Parse("age >= 18 AND status IN ('active', 'trial')")
+//	expr, err := arrowfilter.ToExpression(filter)
+//	// and_kleene(greater_equal(age, 18), is_in(status, {value_set=["active", "trial"]}))
+func ToExpression(filter *where.Filter, opts ...Option) (Expression, error) {
+	c := newConverter(opts)
+	if filter == nil || filter.Expression == nil {
+		return nil, errors.New("empty filter")
+	}
+	return c.expression(filter.Expression)
+}
+
+func newConverter(opts []Option) *converter {
+	c := &converter{variables: make(map[string]any)}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *converter) expression(expr *where.Expression) (Expression, error) {
+	if expr == nil || len(expr.Or) == 0 {
+		return nil, errors.New("empty expression")
+	}
+
+	terms := make([]Expression, len(expr.Or))
+	for i, term := range expr.Or {
+		cond, err := c.term(term)
+		if err != nil {
+			return nil, err
+		}
+		terms[i] = cond
+	}
+	return fold("or_kleene", terms), nil
+}
+
+func (c *converter) term(term *where.Term) (Expression, error) {
+	if term == nil || len(term.And) == 0 {
+		return nil, errors.New("empty term")
+	}
+
+	factors := make([]Expression, len(term.And))
+	for i, factor := range term.And {
+		cond, err := c.factor(factor)
+		if err != nil {
+			return nil, err
+		}
+		factors[i] = cond
+	}
+	return fold("and_kleene", factors), nil
+}
+
+func (c *converter) factor(factor *where.Factor) (Expression, error) {
+	var (
+		cond Expression
+		err  error
+	)
+	switch {
+	case factor == nil:
+		return nil, errors.New("empty factor")
+	case factor.Exists != nil:
+		return nil, errors.New("EXISTS conditions are not supported")
+	case factor.SubExpr != nil:
+		cond, err = c.expression(factor.SubExpr)
+	case factor.Predicate != nil:
+		cond, err = c.predicate(factor.Predicate)
+	default:
+		return nil, errors.New("empty factor content")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if factor.Not {
+		return call("invert", cond), nil
+	}
+	return cond, nil
+}
+
+func (c *converter) predicate(pred *where.Predicate) (Expression, error) {
+	if pred.Operation == nil {
+		return nil, errors.New("predicate missing operation")
+	}
+
+	left, err := c.value(pred.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	op := pred.Operation
+	switch {
+	case op.Compare != nil:
+		return c.compare(left, op.Compare)
+	case op.Like != nil:
+		return c.like(left, op.Like)
+	case op.Between != nil:
+		lower, err := c.value(op.Between.Lower)
+		if err != nil {
+			return nil, err
+		}
+		upper, err := c.value(op.Between.Upper)
+		if err != nil {
+			return nil, err
+		}
+		between := call("and_kleene", call("greater_equal", left, lower), call("less_equal", left, upper))
+		return negate(between, op.Between.Not), nil
+	case op.In != nil:
+		values, err := c.list(op.In.Values)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			return nil, errors.New("IN expression requires at least one value")
+		}
+		in := &Call{Function: "is_in", Args: []Expression{left}, Options: &SetLookupOptions{ValueSet: values}}
+		return negate(in, op.In.Not), nil
+	case op.IsNull != nil:
+		if op.IsNull.Not {
+			return call("is_valid", left), nil
+		}
+		return call("is_null", left), nil
+	case op.Match != nil:
+		return nil, errors.New("operator MATCHES is not supported")
+	default:
+		return nil, errors.New("empty operation")
+	}
+}
+
+// compare converts a comparison. NULL-safe equality, which Arrow has no function for, is written as
+// the equality of two values that are not NULL, or else whether both are NULL.
+func (c *converter) compare(left Expression, cmp *where.CompareOp) (Expression, error) {
+	right, err := c.value(cmp.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	operator := cmp.Operator.String()
+	if operator == "<=>" {
+		if lit, ok := right.(*Literal); ok && lit.Value == nil {
+			return call("is_null", left), nil
+		}
+		return call("coalesce", call("equal", left, right), call("and_kleene", call("is_null", left), call("is_null", right))), nil
+	}
+
+	function, ok := comparisons[operator]
+	if !ok {
+		return nil, errors.Errorf("operator %s is not supported", operator)
+	}
+	return call(function, left, right), nil
+}
+
+// like converts a LIKE or ILIKE condition, whose pattern must be a string literal.
+func (c *converter) like(left Expression, like *where.LikeOp) (Expression, error) {
+	pattern, err := c.value(like.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	lit, ok := pattern.(*Literal)
+	if !ok {
+		return nil, errors.New("LIKE pattern must be a string")
+	}
+	text, ok := lit.Value.(string)
+	if !ok {
+		return nil, errors.New("LIKE pattern must be a string")
+	}
+
+	match := &Call{Function: "match_like", Args: []Expression{left}, Options: &MatchSubstringOptions{
+		Pattern:    text,
+		IgnoreCase: strings.EqualFold(like.Type.Operator, "ILIKE"),
+	}}
+	return negate(match, like.Not), nil
+}
+
+// list returns the values of an IN list, which must be literals, expanding slices bound to
+// variables.
+func (c *converter) list(values []*where.Value) ([]any, error) {
+	var items []any
+	for _, val := range values {
+		if val == nil || val.Literal == nil || val.Bitwise != nil {
+			return nil, errors.New("IN values must be literals")
+		}
+
+		value, err := c.literal(val.Literal)
+		if err != nil {
+			return nil, err
+		}
+		if val.Literal.Variable != nil {
+			items = append(items, expand(value)...)
+			continue
+		}
+		items = append(items, value)
+	}
+	return items, nil
+}
+
+func (c *converter) value(val *where.Value) (Expression, error) {
+	switch {
+	case val == nil:
+		return nil, errors.New("empty value")
+	case val.Bitwise != nil:
+		return c.bitwise(val.Bitwise)
+	case val.Field != nil:
+		return c.field(val.Field)
+	case val.Function != nil:
+		return c.function(val.Function)
+	case val.Literal != nil:
+		value, err := c.literal(val.Literal)
+		if err != nil {
+			return nil, err
+		}
+		return &Literal{Value: value}, nil
+	case val.SubExpr != nil:
+		return c.expression(val.SubExpr)
+	default:
+		return nil, errors.New("empty value")
+	}
+}
+
+func (c *converter) field(field *where.FieldRef) (Expression, error) {
+	name := field.String()
+	if c.validator != nil && !c.validator.IsFieldAllowed(name) {
+		return nil, errors.Errorf("field %q is not allowed", name)
+	}
+	return &FieldRef{Path: append([]string(nil), field.Parts...)}, nil
+}
+
+// function converts a function call, including IF expressions, which become if_else.
+func (c *converter) function(fn *where.FunctionCall) (Expression, error) {
+	name := strings.ToUpper(fn.Name)
+	if c.validator != nil && !c.validator.IsFunctionAllowed(name) {
+		return nil, errors.Errorf("function %q is not allowed", name)
+	}
+	if fn.CastAs != nil {
+		return nil, errors.New("CAST expressions are not supported")
+	}
+	if fn.Star {
+		return nil, errors.Errorf("%s(*) is not supported", name)
+	}
+
+	args := make([]Expression, len(fn.Args))
+	for i, arg := range fn.Args {
+		value, err := c.value(arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+
+	if fn.Cond != nil {
+		if len(args) != 2 {
+			return nil, errors.New("IF requires a condition and two values")
+		}
+		cond, err := c.expression(fn.Cond)
+		if err != nil {
+			return nil, err
+		}
+		return call("if_else", cond, args[0], args[1]), nil
+	}
+
+	function, ok := functions[name]
+	if !ok {
+		return nil, errors.Errorf("function %q has no Arrow equivalent", name)
+	}
+	return call(function, args...), nil
+}
+
+// bitwise converts a chain of bitwise operations, which are evaluated left to right.
+func (c *converter) bitwise(expr *where.BitwiseExpr) (Expression, error) {
+	left, err := c.value(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range expr.Ops {
+		function, ok := bitwise[op.Operator]
+		if !ok {
+			return nil, errors.Errorf("operator %s is not supported", op.Operator)
+		}
+
+		right, err := c.value(op.Right)
+		if err != nil {
+			return nil, err
+		}
+		left = call(function, left, right)
+	}
+	return left, nil
+}
+
+// literal returns the Go value of a literal. Numbers written without a fraction or exponent are
+// int64, so that they compare with integer columns without a cast.
+func (c *converter) literal(lit *where.LiteralValue) (any, error) {
+	if lit.Variable != nil {
+		value, ok := c.variables[strings.TrimPrefix(*lit.Variable, ":")]
+		if !ok {
+			return nil, errors.Errorf("missing value for variable %s", *lit.Variable)
+		}
+		return value, nil
+	}
+
+	value := lit.Value()
+	if n, ok := value.(float64); ok && lit.Numeral != nil && !strings.ContainsAny(*lit.Numeral, ".eE") {
+		if n == math.Trunc(n) && math.Abs(n) < 1<<63 {
+			return int64(n), nil
+		}
+	}
+	return value, nil
+}
+
+// String returns the dotted path of the field.
+func (f *FieldRef) String() string {
+	return strings.Join(f.Path, ".")
+}
+
+// String returns the value, with strings quoted.
+func (l *Literal) String() string {
+	return format(l.Value)
+}
+
+// String returns the call in Arrow's notation, e.g. is_in(status, {value_set=["a", "b"]}).
+func (c *Call) String() string {
+	args := make([]string, 0, len(c.Args)+1)
+	for _, arg := range c.Args {
+		args = append(args, arg.String())
+	}
+	if c.Options != nil {
+		args = append(args, fmt.Sprint(c.Options))
+	}
+	return c.Function + "(" + strings.Join(args, ", ") + ")"
+}
+
+// String returns the options in Arrow's notation.
+func (o *SetLookupOptions) String() string {
+	values := make([]string, len(o.ValueSet))
+	for i, v := range o.ValueSet {
+		values[i] = format(v)
+	}
+	return "{value_set=[" + strings.Join(values, ", ") + "]}"
+}
+
+// String returns the options in Arrow's notation.
+func (o *MatchSubstringOptions) String() string {
+	return fmt.Sprintf("{pattern=%s, ignore_case=%t}", strconv.Quote(o.Pattern), o.IgnoreCase)
+}
+
+func call(function string, args ...Expression) *Call {
+	return &Call{Function: function, Args: args}
+}
+
+// fold combines expressions with a binary function from left to right, e.g. and_kleene(and_kleene(a,
+// b), c), since Arrow's logical functions take two arguments.
+func fold(function string, exprs []Expression) Expression {
+	result := exprs[0]
+	for _, expr := range exprs[1:] {
+		result = call(function, result, expr)
+	}
+	return result
+}
+
+func negate(expr Expression, not bool) Expression {
+	if not {
+		return call("invert", expr)
+	}
+	return expr
+}
+
+func format(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	case []byte:
+		return fmt.Sprintf("0x%x", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// expand returns the elements of a slice or array, other than a byte slice, or the value itself.
+func expand(value any) []any {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []any{value}
+	}
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		return []any{value}
+	}
+
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items
+}
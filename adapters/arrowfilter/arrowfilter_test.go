@@ -0,0 +1,108 @@
+package arrowfilter_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/pseudomuto/where/adapters/arrowfilter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToExpression(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   string
+	}{
+		{filter: "age >= 18", want: "greater_equal(age, 18)"},
+		{filter: "score < 7.5 AND name != 'x'", want: `and_kleene(less(score, 7.5), not_equal(name, "x"))`},
+		{filter: "a = 1 OR b = 2 OR c = 3", want: "or_kleene(or_kleene(equal(a, 1), equal(b, 2)), equal(c, 3))"},
+		{filter: "NOT (a = 1 AND b IS NULL)", want: "invert(and_kleene(equal(a, 1), is_null(b)))"},
+		{filter: "b IS NOT NULL", want: "is_valid(b)"},
+		{filter: "status IN ('a', 'b')", want: `is_in(status, {value_set=["a", "b"]})`},
+		{filter: "status NOT IN (1, NULL)", want: "invert(is_in(status, {value_set=[1, null]}))"},
+		{filter: "age BETWEEN 1 AND 9", want: "and_kleene(greater_equal(age, 1), less_equal(age, 9))"},
+		{filter: "age NOT BETWEEN 1 AND 9", want: "invert(and_kleene(greater_equal(age, 1), less_equal(age, 9)))"},
+		{filter: "name LIKE 'j\\_%'", want: `match_like(name, {pattern="j\\_%", ignore_case=false})`},
+		{filter: "name NOT ILIKE '%smith'", want: `invert(match_like(name, {pattern="%smith", ignore_case=true}))`},
+		{filter: "a <=> NULL", want: "is_null(a)"},
+		{filter: "a <=> b", want: "coalesce(equal(a, b), and_kleene(is_null(a), is_null(b)))"},
+		{filter: "LOWER(TRIM(name)) = 'x' AND COALESCE(score, 0) > 1", want: `and_kleene(equal(utf8_lower(utf8_trim_whitespace(name)), "x"), greater(coalesce(score, 0), 1))`},
+		{filter: "flags & 4 = 4", want: "equal(bit_wise_and(flags, 4), 4)"},
+		{filter: "IF(a > 1, b, c) = 2", want: "equal(if_else(greater(a, 1), b, c), 2)"},
+		{filter: "user.address.city = 'x' AND active", want: `and_kleene(equal(user.address.city, "x"), equal(active, true))`},
+		{filter: "data = 0xCAFE", want: "equal(data, 0xcafe)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := where.Parse(tt.filter)
+			require.NoError(t, err)
+
+			expr, err := arrowfilter.ToExpression(filter)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, expr.String())
+		})
+	}
+}
+
+func TestToExpressionTree(t *testing.T) {
+	filter, err := where.Parse("user.age >= 18 AND name ILIKE 'j%'")
+	require.NoError(t, err)
+
+	expr, err := arrowfilter.ToExpression(filter)
+	require.NoError(t, err)
+	require.Equal(t, &arrowfilter.Call{Function: "and_kleene", Args: []arrowfilter.Expression{
+		&arrowfilter.Call{Function: "greater_equal", Args: []arrowfilter.Expression{
+			&arrowfilter.FieldRef{Path: []string{"user", "age"}},
+			&arrowfilter.Literal{Value: int64(18)},
+		}},
+		&arrowfilter.Call{
+			Function: "match_like",
+			Args:     []arrowfilter.Expression{&arrowfilter.FieldRef{Path: []string{"name"}}},
+			Options:  &arrowfilter.MatchSubstringOptions{Pattern: "j%", IgnoreCase: true},
+		},
+	}}, expr)
+}
+
+func TestToExpressionVariables(t *testing.T) {
+	filter, err := where.Parse("level IN (:levels) AND age > :min")
+	require.NoError(t, err)
+
+	_, err = arrowfilter.ToExpression(filter)
+	require.EqualError(t, err, "missing value for variable :levels")
+
+	expr, err := arrowfilter.ToExpression(filter, arrowfilter.WithVariables(map[string]any{
+		"levels": []string{"warn", "error"},
+		"min":    18,
+	}))
+	require.NoError(t, err)
+	require.Equal(t, `and_kleene(is_in(level, {value_set=["warn", "error"]}), greater(age, 18))`, expr.String())
+}
+
+func TestToExpressionErrors(t *testing.T) {
+	validator := arrowfilter.WithValidator(where.NewValidator().AllowFields("age").DenyFunctions("SLEEP"))
+
+	tests := []struct {
+		filter string
+		opts   []arrowfilter.Option
+		err    string
+	}{
+		{filter: "secret = 1", opts: []arrowfilter.Option{validator}, err: `field "secret" is not allowed`},
+		{filter: "SLEEP(age) = 1", opts: []arrowfilter.Option{validator}, err: `function "SLEEP" is not allowed`},
+		{filter: "DATE_TRUNC('day', age) = 1", err: `function "DATE_TRUNC" has no Arrow equivalent`},
+		{filter: "CAST(age AS TEXT) = '1'", err: "CAST expressions are not supported"},
+		{filter: "name MATCHES 'fox'", err: "operator MATCHES is not supported"},
+		{filter: "name LIKE age", err: "LIKE pattern must be a string"},
+		{filter: "age IN (1, name)", err: "IN values must be literals"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := where.Parse(tt.filter)
+			require.NoError(t, err)
+
+			_, err = arrowfilter.ToExpression(filter, tt.opts...)
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package arrowfilter
+
+import (
+	"github.com/pseudomuto/where"
+)
+
+// Row-group predicate operators, as used by Parquet readers such as pyarrow.
+const (
+	OpEqual          = "="
+	OpNotEqual       = "!="
+	OpLess           = "<"
+	OpLessOrEqual    = "<="
+	OpGreater        = ">"
+	OpGreaterOrEqual = ">="
+	OpIn             = "in"
+	OpNotIn          = "not in"
+)
+
+var (
+	// rowGroupOps maps the comparison operators of a filter to row-group predicate operators.
+	rowGroupOps = map[string]string{
+		"=":  OpEqual,
+		"!=": OpNotEqual,
+		"<>": OpNotEqual,
+		"<":  OpLess,
+		"<=": OpLessOrEqual,
+		">":  OpGreater,
+		">=": OpGreaterOrEqual,
+	}
+
+	// negatedOps maps row-group predicate operators to their negation.
+	negatedOps = map[string]string{
+		OpEqual:          OpNotEqual,
+		OpNotEqual:       OpEqual,
+		OpLess:           OpGreaterOrEqual,
+		OpLessOrEqual:    OpGreater,
+		OpGreater:        OpLessOrEqual,
+		OpGreaterOrEqual: OpLess,
+		OpIn:             OpNotIn,
+		OpNotIn:          OpIn,
+	}
+)
+
+// Predicate compares a column with a value, or with the []any values of in and not in.
+type Predicate struct {
+	Column string
+	Op     string
+	Value  any
+}
+
+// RowGroupFilter converts a filter into the row-group filter of a Parquet scan: an OR of ANDs of
+// column predicates, which a reader compares with each row group's min/max statistics to skip row
+// groups that cannot match.
+//
+// Conditions a row-group filter cannot express, such as LIKE, IS NULL, and comparisons of functions,
+// are left out of their AND, so the row groups read are a superset of the matching ones and rows
+// must still be filtered, e.g. with ToExpression. A nil result means no row group can be skipped.
+// The filter is converted to DNF first, so an error is returned if it has too many branches, and
+// errors are returned for filters ToExpression rejects.
+//
+// Example:
+//
+//	filter, _ := where.Parse("year = 2024 AND (month IN (1, 2) OR name LIKE 'a%')")
+//	dnf, err := arrowfilter.RowGroupFilter(filter)
+//	// [[{year = 2024} {month in [1 2]}] [{year = 2024}]]
+func RowGroupFilter(filter *where.Filter, opts ...Option) ([][]Predicate, error) {
+	c := newConverter(opts)
+	if filter == nil || filter.Expression == nil {
+		return nil, nil
+	}
+	if _, err := c.expression(filter.Expression); err != nil {
+		return nil, err
+	}
+
+	dnf, err := filter.ToDNF()
+	if err != nil {
+		return nil, err
+	}
+
+	branches := make([][]Predicate, 0, len(dnf.Expression.Or))
+	for _, term := range dnf.Expression.Or {
+		var branch []Predicate
+		for _, factor := range term.And {
+			branch = append(branch, c.rowGroupPredicates(factor)...)
+		}
+		if len(branch) == 0 {
+			return nil, nil
+		}
+		branches = append(branches, branch)
+	}
+	return branches, nil
+}
+
+// rowGroupPredicates returns the predicates equivalent to a factor of a DNF branch, or none when
+// the factor cannot be expressed. DNF negates a predicate by wrapping it in a group, e.g.
+// NOT (a = 1).
+func (c *converter) rowGroupPredicates(factor *where.Factor) []Predicate {
+	pred := factor.Predicate
+	if sub := factor.SubExpr; sub != nil && len(sub.Or) == 1 && len(sub.Or[0].And) == 1 && !sub.Or[0].And[0].Not {
+		pred = sub.Or[0].And[0].Predicate
+	}
+	if pred == nil || pred.Operation == nil || pred.Left == nil || pred.Left.Field == nil || pred.Left.Bitwise != nil {
+		return nil
+	}
+
+	column := pred.Left.Field.String()
+	op := pred.Operation
+	switch {
+	case op.Compare != nil:
+		value, ok := c.constant(op.Compare.Right)
+		if !ok || value == nil {
+			return nil
+		}
+
+		operator := op.Compare.Operator.String()
+		if operator == "<=>" && !factor.Not {
+			return []Predicate{{Column: column, Op: OpEqual, Value: value}}
+		}
+		rowGroupOp, ok := rowGroupOps[operator]
+		if !ok {
+			return nil
+		}
+		if factor.Not {
+			rowGroupOp = negatedOps[rowGroupOp]
+		}
+		return []Predicate{{Column: column, Op: rowGroupOp, Value: value}}
+	case op.Between != nil:
+		if op.Between.Not != factor.Not {
+			return nil
+		}
+		lower, ok := c.constant(op.Between.Lower)
+		if !ok || lower == nil {
+			return nil
+		}
+		upper, ok := c.constant(op.Between.Upper)
+		if !ok || upper == nil {
+			return nil
+		}
+		return []Predicate{
+			{Column: column, Op: OpGreaterOrEqual, Value: lower},
+			{Column: column, Op: OpLessOrEqual, Value: upper},
+		}
+	case op.In != nil:
+		values, err := c.list(op.In.Values)
+		if err != nil {
+			return nil
+		}
+
+		rowGroupOp := OpIn
+		if op.In.Not != factor.Not {
+			rowGroupOp = OpNotIn
+		}
+
+		// A NULL in the list never matches, so it is dropped from IN, and NOT IN with a NULL is never
+		// true, so it is left out like any condition that cannot be expressed.
+		set := make([]any, 0, len(values))
+		for _, value := range values {
+			if value == nil {
+				if rowGroupOp == OpNotIn {
+					return nil
+				}
+				continue
+			}
+			set = append(set, value)
+		}
+		if len(set) == 0 {
+			return nil
+		}
+		return []Predicate{{Column: column, Op: rowGroupOp, Value: set}}
+	default:
+		return nil
+	}
+}
+
+// constant returns the value of a literal.
+func (c *converter) constant(val *where.Value) (any, bool) {
+	if val == nil || val.Literal == nil || val.Bitwise != nil {
+		return nil, false
+	}
+	value, err := c.literal(val.Literal)
+	return value, err == nil
+}
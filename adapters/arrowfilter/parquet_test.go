@@ -0,0 +1,86 @@
+package arrowfilter_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/pseudomuto/where/adapters/arrowfilter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowGroupFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   [][]arrowfilter.Predicate
+	}{
+		{
+			filter: "year = 2024 AND month >= 6",
+			want: [][]arrowfilter.Predicate{{
+				{Column: "year", Op: arrowfilter.OpEqual, Value: int64(2024)},
+				{Column: "month", Op: arrowfilter.OpGreaterOrEqual, Value: int64(6)},
+			}},
+		},
+		{
+			filter: "year = 2024 AND (month IN (1, 2) OR name LIKE 'a%')",
+			want: [][]arrowfilter.Predicate{
+				{
+					{Column: "year", Op: arrowfilter.OpEqual, Value: int64(2024)},
+					{Column: "month", Op: arrowfilter.OpIn, Value: []any{int64(1), int64(2)}},
+				},
+				{{Column: "year", Op: arrowfilter.OpEqual, Value: int64(2024)}},
+			},
+		},
+		{
+			filter: "NOT (score < 1.5 OR region IN ('eu', NULL))",
+			want: [][]arrowfilter.Predicate{{
+				{Column: "score", Op: arrowfilter.OpGreaterOrEqual, Value: 1.5},
+			}},
+		},
+		{
+			filter: "ts BETWEEN '2024-01-01' AND '2024-02-01' AND kind NOT IN ('a', NULL) AND id <=> 7",
+			want: [][]arrowfilter.Predicate{{
+				{Column: "ts", Op: arrowfilter.OpGreaterOrEqual, Value: "2024-01-01"},
+				{Column: "ts", Op: arrowfilter.OpLessOrEqual, Value: "2024-02-01"},
+				{Column: "id", Op: arrowfilter.OpEqual, Value: int64(7)},
+			}},
+		},
+		{
+			filter: "region IN ('eu', NULL) AND NOT (kind IN ('a'))",
+			want: [][]arrowfilter.Predicate{{
+				{Column: "region", Op: arrowfilter.OpIn, Value: []any{"eu"}},
+				{Column: "kind", Op: arrowfilter.OpNotIn, Value: []any{"a"}},
+			}},
+		},
+		{filter: "year = 2024 OR LOWER(name) = 'x'", want: nil},
+		{filter: "deleted_at IS NULL AND age NOT BETWEEN 1 AND 2 AND a = b", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := where.Parse(tt.filter)
+			require.NoError(t, err)
+
+			got, err := arrowfilter.RowGroupFilter(filter)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRowGroupFilterErrors(t *testing.T) {
+	filter, err := where.Parse("secret = 1 OR day IN (:days)")
+	require.NoError(t, err)
+
+	_, err = arrowfilter.RowGroupFilter(filter, arrowfilter.WithValidator(where.NewValidator().AllowFields("day")))
+	require.EqualError(t, err, `field "secret" is not allowed`)
+
+	_, err = arrowfilter.RowGroupFilter(filter)
+	require.EqualError(t, err, "missing value for variable :days")
+
+	got, err := arrowfilter.RowGroupFilter(filter, arrowfilter.WithVariables(map[string]any{"days": []int{1, 2}}))
+	require.NoError(t, err)
+	require.Equal(t, [][]arrowfilter.Predicate{
+		{{Column: "secret", Op: arrowfilter.OpEqual, Value: int64(1)}},
+		{{Column: "day", Op: arrowfilter.OpIn, Value: []any{1, 2}}},
+	}, got)
+}
@@ -0,0 +1,113 @@
+// Package bunfilter applies where filters to uptrace/bun queries.
+//
+// The filter is built for the where driver matching the query's bun dialect and added to the query
+// with bun's ? placeholders, so bun formats the parameters for its dialect like those of any other
+// condition.
+package bunfilter
+
+import (
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/where"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/schema"
+)
+
+// drivers maps bun dialects to the where drivers building their filters. Dialects without a driver
+// of their own use standard SQL.
+var drivers = map[dialect.Name]string{
+	dialect.PG:     "postgres",
+	dialect.MySQL:  "mysql",
+	dialect.SQLite: "ansi",
+	dialect.MSSQL:  "ansi",
+}
+
+type (
+	// Option configures how a filter is applied.
+	Option func(*applier)
+
+	// Query is a bun query with a WHERE clause, such as *bun.SelectQuery, *bun.UpdateQuery, or
+	// *bun.DeleteQuery.
+	Query[Q any] interface {
+		Where(query string, args ...any) Q
+		Err(err error) Q
+		Dialect() schema.Dialect
+	}
+
+	applier struct {
+		driver  string
+		options []where.BuildOption
+	}
+)
+
+// WithDriver builds filters with the named where driver instead of the one matching the query's
+// dialect. The driver package must be imported.
+func WithDriver(name string) Option {
+	return func(a *applier) {
+		a.driver = name
+	}
+}
+
+// WithBuildOptions sets the options used to build the filter, e.g. where.WithValidator.
+func WithBuildOptions(options ...where.BuildOption) Option {
+	return func(a *applier) {
+		a.options = append(a.options, options...)
+	}
+}
+
+// ApplyWhere adds the filter to the query's WHERE clause, ANDed with its other conditions. If the
+// filter cannot be built, the error is set on the query and returned when it is executed.
+//
+// Example:
+//
+//	import (
+//		_ "github.com/pseudomuto/where/drivers/postgres"
+//		"github.com/pseudomuto/where/adapters/bunfilter"
+//	)
+//
+//	filter, _ := where.Parse("age >= 18 AND name ILIKE 'j%'")
+//	q := db.NewSelect().Model(&users).Where("org_id = ?", orgID)
+//	err := bunfilter.ApplyWhere(q, filter, bunfilter.WithBuildOptions(where.WithValidator(v))).Scan(ctx)
+//	// SELECT ... WHERE (org_id = 1) AND ((age >= 18 AND name ILIKE 'j%'))
+func ApplyWhere[Q Query[Q]](q Q, filter *where.Filter, opts ...Option) Q {
+	a := &applier{}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	sql, args, err := a.build(q.Dialect(), filter)
+	if err != nil {
+		return q.Err(err)
+	}
+	return q.Where(sql, args...)
+}
+
+// Where returns a function adding the filter to a query's WHERE clause like ApplyWhere, for use
+// with the Apply method of bun queries.
+//
+// Example:
+//
+//	err := db.NewSelect().Model(&users).Apply(bunfilter.Where[*bun.SelectQuery](filter)).Scan(ctx)
+func Where[Q Query[Q]](filter *where.Filter, opts ...Option) func(Q) Q {
+	return func(q Q) Q {
+		return ApplyWhere(q, filter, opts...)
+	}
+}
+
+// build builds the filter with ? placeholders, which bun replaces with the formatted parameters.
+func (a *applier) build(d schema.Dialect, filter *where.Filter) (string, []any, error) {
+	driver := a.driver
+	if driver == "" {
+		var ok bool
+		if driver, ok = drivers[d.Name()]; !ok {
+			return "", nil, errors.Errorf("no where driver for bun dialect %s", d.Name())
+		}
+	}
+
+	node, err := filter.ToAST(driver, a.options...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql, args := node.SQL()
+	return sql, args, nil
+}
@@ -0,0 +1,82 @@
+package bunfilter_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/pseudomuto/where/adapters/bunfilter"
+	_ "github.com/pseudomuto/where/drivers/ansi"
+	_ "github.com/pseudomuto/where/drivers/postgres"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+type User struct {
+	bun.BaseModel `bun:"table:users"`
+
+	ID   int64 `bun:"id,pk"`
+	Name string
+}
+
+func TestApplyWhere(t *testing.T) {
+	db := bun.NewDB(nil, pgdialect.New())
+
+	tests := []struct {
+		name       string
+		expression string
+		opts       []bunfilter.Option
+		want       string
+	}{
+		{
+			name:       "combined with other conditions",
+			expression: "age >= 18 AND name ILIKE 'j%'",
+			want:       `SELECT "user"."id", "user"."name" FROM "users" AS "user" WHERE (org_id = 1) AND ((age >= 18 AND name ILIKE 'j%'))`,
+		},
+		{
+			name:       "values are formatted by the dialect",
+			expression: "name = 'O''Brien' OR status IN ('a', 'b')",
+			want:       `SELECT "user"."id", "user"."name" FROM "users" AS "user" WHERE (org_id = 1) AND ((name = 'O''Brien' OR status IN ('a', 'b')))`,
+		},
+		{
+			name:       "driver override",
+			expression: "position > 2",
+			opts:       []bunfilter.Option{bunfilter.WithDriver("ansi")},
+			want:       `SELECT "user"."id", "user"."name" FROM "users" AS "user" WHERE (org_id = 1) AND ("position" > 2)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.expression)
+			require.NoError(t, err)
+
+			q := db.NewSelect().Model((*User)(nil)).Where("org_id = ?", 1)
+			q = bunfilter.ApplyWhere(q, filter, tt.opts...)
+			require.Equal(t, tt.want, q.String())
+		})
+	}
+}
+
+func TestWhere(t *testing.T) {
+	db := bun.NewDB(nil, pgdialect.New())
+
+	filter, err := where.Parse("name = 'x'")
+	require.NoError(t, err)
+
+	q := db.NewDelete().Model((*User)(nil)).Apply(bunfilter.Where[*bun.DeleteQuery](filter))
+	require.Equal(t, `DELETE FROM "users" AS "user" WHERE (name = 'x')`, q.String())
+}
+
+func TestApplyWhereErrors(t *testing.T) {
+	db := bun.NewDB(nil, pgdialect.New())
+
+	filter, err := where.Parse("secret = 1")
+	require.NoError(t, err)
+
+	validator := where.NewValidator().AllowFields("age")
+	q := bunfilter.ApplyWhere(db.NewSelect().Model((*User)(nil)), filter, bunfilter.WithBuildOptions(where.WithValidator(validator)))
+
+	_, err = q.AppendQuery(db.Formatter(), nil)
+	require.EqualError(t, err, `field "secret" is not allowed`)
+}
@@ -0,0 +1,470 @@
+// Package entfilter converts where filters into ent predicates, so that user-supplied filters can be
+// applied to queries on ent-managed schemas.
+//
+// The package does not depend on ent. Instead, the predicate functions ent generates for each field,
+// such as user.AgeGT and user.NameHasPrefix, are mapped to filter fields, and the filter's values
+// are converted to the Go type those functions take. Filters using unmapped fields, functions, or
+// operators without a generated predicate are rejected.
+//
+// LIKE patterns are converted to the string predicates ent generates: 'abc%' to HasPrefix, '%abc'
+// to HasSuffix, '%abc%' to Contains, and a pattern without wildcards to EQ. ILIKE patterns are
+// converted to ContainsFold and EqualFold.
+package entfilter
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/where"
+)
+
+// Predicate operations, named after the ent predicate functions.
+const (
+	opEQ           = "EQ"
+	opNEQ          = "NEQ"
+	opGT           = "GT"
+	opGTE          = "GTE"
+	opLT           = "LT"
+	opLTE          = "LTE"
+	opIn           = "In"
+	opNotIn        = "NotIn"
+	opIsNil        = "IsNil"
+	opNotNil       = "NotNil"
+	opHasPrefix    = "HasPrefix"
+	opHasSuffix    = "HasSuffix"
+	opContains     = "Contains"
+	opEqualFold    = "EqualFold"
+	opContainsFold = "ContainsFold"
+)
+
+// comparisons maps the comparison operators of a filter to predicate operations.
+var comparisons = map[string]string{
+	"=":   opEQ,
+	"<=>": opEQ,
+	"!=":  opNEQ,
+	"<":   opLT,
+	"<=":  opLTE,
+	">":   opGT,
+	">=":  opGTE,
+}
+
+type (
+	// Schema describes how a filter is converted into predicates of type P, e.g. predicate.User.
+	Schema[P any] struct {
+		// Fields maps filter field names to the predicates of the ent field.
+		Fields map[string]Field[P]
+
+		// And, Or, and Not combine predicates, e.g. user.And, user.Or, and user.Not.
+		And func(...P) P
+		Or  func(...P) P
+		Not func(P) P
+	}
+
+	// Field builds the predicates of a field. It is implemented by Predicates.
+	Field[P any] interface {
+		predicate(op string, values []any) (P, error)
+	}
+
+	// Predicates holds the predicate functions ent generates for a field whose Go type is T, e.g.
+	// user.AgeEQ and user.AgeIn for an int field. Functions that are nil, such as HasPrefix for
+	// non-string fields, are unsupported operations for the field.
+	Predicates[P, T any] struct {
+		EQ, NEQ, GT, GTE, LT, LTE func(T) P
+		In, NotIn                 func(...T) P
+		IsNil, NotNil             func() P
+
+		HasPrefix, HasSuffix, Contains func(T) P
+		EqualFold, ContainsFold        func(T) P
+	}
+)
+
+// Convert converts a filter into a predicate, which can be passed to the Where method of an ent
+// query.
+//
+// Example:
+//
+//	schema := entfilter.Schema[predicate.User]{
+//		Fields: map[string]entfilter.Field[predicate.User]{
+//			"age": entfilter.Predicates[predicate.User, int]{
+//				EQ: user.AgeEQ, GT: user.AgeGT, GTE: user.AgeGTE, LT: user.AgeLT, LTE: user.AgeLTE, In: user.AgeIn,
+//			},
+//			"name": entfilter.Predicates[predicate.User, string]{
+//				EQ: user.NameEQ, HasPrefix: user.NameHasPrefix, ContainsFold: user.NameContainsFold,
+//			},
+//		},
+//		And: user.And,
+//		Or:  user.Or,
+//		Not: user.Not,
+//	}
+//
+//	filter, _ := where.Parse("age >= 18 AND name ILIKE '%smith%'")
+//	pred, err := entfilter.Convert(filter, schema)
+//	users, err := client.User.Query().Where(pred).All(ctx)
+func Convert[P any](filter *where.Filter, schema Schema[P]) (P, error) {
+	var zero P
+	if filter == nil || filter.Expression == nil {
+		return zero, errors.New("empty filter")
+	}
+	if schema.And == nil || schema.Or == nil || schema.Not == nil {
+		return zero, errors.New("schema must set And, Or, and Not")
+	}
+
+	c := &converter[P]{schema: schema}
+	return c.expression(filter.Expression)
+}
+
+// converter converts the filter AST into predicates.
+type converter[P any] struct {
+	schema Schema[P]
+}
+
+func (c *converter[P]) expression(expr *where.Expression) (P, error) {
+	var zero P
+	if expr == nil || len(expr.Or) == 0 {
+		return zero, errors.New("empty expression")
+	}
+
+	terms := make([]P, len(expr.Or))
+	for i, term := range expr.Or {
+		pred, err := c.term(term)
+		if err != nil {
+			return zero, err
+		}
+		terms[i] = pred
+	}
+
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return c.schema.Or(terms...), nil
+}
+
+func (c *converter[P]) term(term *where.Term) (P, error) {
+	var zero P
+	if term == nil || len(term.And) == 0 {
+		return zero, errors.New("empty term")
+	}
+
+	factors := make([]P, len(term.And))
+	for i, factor := range term.And {
+		pred, err := c.factor(factor)
+		if err != nil {
+			return zero, err
+		}
+		factors[i] = pred
+	}
+
+	if len(factors) == 1 {
+		return factors[0], nil
+	}
+	return c.schema.And(factors...), nil
+}
+
+func (c *converter[P]) factor(factor *where.Factor) (P, error) {
+	var (
+		zero P
+		pred P
+		err  error
+	)
+	switch {
+	case factor == nil:
+		return zero, errors.New("empty factor")
+	case factor.Exists != nil:
+		return zero, errors.New("EXISTS conditions are not supported")
+	case factor.SubExpr != nil:
+		pred, err = c.expression(factor.SubExpr)
+	case factor.Predicate != nil:
+		pred, err = c.predicate(factor.Predicate)
+	default:
+		return zero, errors.New("empty factor content")
+	}
+	if err != nil {
+		return zero, err
+	}
+
+	if factor.Not {
+		return c.schema.Not(pred), nil
+	}
+	return pred, nil
+}
+
+// predicate converts a predicate on a field compared with literal values.
+func (c *converter[P]) predicate(pred *where.Predicate) (P, error) {
+	var zero P
+	if pred.Left == nil || pred.Left.Field == nil || pred.Left.Bitwise != nil {
+		return zero, errors.New("only fields can be compared")
+	}
+	if pred.Operation == nil {
+		return zero, errors.New("predicate missing operation")
+	}
+
+	name := pred.Left.Field.String()
+	field, ok := c.schema.Fields[name]
+	if !ok {
+		return zero, errors.Errorf("field %q is not allowed", name)
+	}
+
+	op := pred.Operation
+	switch {
+	case op.Compare != nil:
+		return c.compare(name, field, op.Compare)
+	case op.Like != nil:
+		return c.like(name, field, op.Like)
+	case op.Between != nil:
+		values, err := literals(op.Between.Lower, op.Between.Upper)
+		if err != nil {
+			return zero, err
+		}
+		lower, err := c.build(name, field, opGTE, values[0])
+		if err != nil {
+			return zero, err
+		}
+		upper, err := c.build(name, field, opLTE, values[1])
+		if err != nil {
+			return zero, err
+		}
+		between := c.schema.And(lower, upper)
+		if op.Between.Not {
+			return c.schema.Not(between), nil
+		}
+		return between, nil
+	case op.In != nil:
+		if len(op.In.Values) == 0 {
+			return zero, errors.New("IN expression requires at least one value")
+		}
+		values, err := literals(op.In.Values...)
+		if err != nil {
+			return zero, err
+		}
+		if op.In.Not {
+			return c.build(name, field, opNotIn, values...)
+		}
+		return c.build(name, field, opIn, values...)
+	case op.IsNull != nil:
+		if op.IsNull.Not {
+			return c.build(name, field, opNotNil)
+		}
+		return c.build(name, field, opIsNil)
+	case op.Match != nil:
+		return zero, errors.New("operator MATCHES is not supported")
+	default:
+		return zero, errors.New("empty operation")
+	}
+}
+
+// compare converts a comparison. Equality with NULL is converted to IsNil and NotNil.
+func (c *converter[P]) compare(name string, field Field[P], cmp *where.CompareOp) (P, error) {
+	var zero P
+	values, err := literals(cmp.Right)
+	if err != nil {
+		return zero, err
+	}
+
+	operator := cmp.Operator.String()
+	if values[0] == nil {
+		switch operator {
+		case "=", "<=>":
+			return c.build(name, field, opIsNil)
+		case "!=":
+			return c.build(name, field, opNotNil)
+		default:
+			return zero, errors.Errorf("operator %s cannot be used with NULL", operator)
+		}
+	}
+
+	return c.build(name, field, comparisons[operator], values[0])
+}
+
+// like converts a LIKE or ILIKE pattern into a string predicate.
+func (c *converter[P]) like(name string, field Field[P], like *where.LikeOp) (P, error) {
+	var zero P
+	values, err := literals(like.Pattern)
+	if err != nil {
+		return zero, err
+	}
+	pattern, ok := values[0].(string)
+	if !ok {
+		return zero, errors.New("LIKE pattern must be a string")
+	}
+
+	op, text, ok := patternOp(pattern, strings.EqualFold(like.Type.Operator, "ILIKE"))
+	if !ok {
+		return zero, errors.Errorf("pattern %q has no equivalent ent predicate", pattern)
+	}
+
+	pred, err := c.build(name, field, op, text)
+	if err != nil {
+		return zero, err
+	}
+	if like.Not {
+		return c.schema.Not(pred), nil
+	}
+	return pred, nil
+}
+
+func (c *converter[P]) build(name string, field Field[P], op string, values ...any) (P, error) {
+	pred, err := field.predicate(op, values)
+	if err != nil {
+		return pred, errors.Wrapf(err, "field %q", name)
+	}
+	return pred, nil
+}
+
+// patternOp returns the predicate operation matching a LIKE pattern and the text it is applied to.
+// Only patterns with wildcards at the start or end, and without _, have an equivalent.
+func patternOp(pattern string, insensitive bool) (string, string, bool) {
+	var (
+		text              strings.Builder
+		leading, trailing bool
+	)
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		switch {
+		case ch == '\\' && i+1 < len(pattern):
+			i++
+			text.WriteByte(pattern[i])
+		case ch == '%' && i == 0:
+			leading = true
+		case ch == '%' && i == len(pattern)-1:
+			trailing = true
+		case ch == '%' || ch == '_':
+			return "", "", false
+		default:
+			text.WriteByte(ch)
+		}
+	}
+
+	switch {
+	case insensitive && leading && trailing:
+		return opContainsFold, text.String(), true
+	case insensitive && !leading && !trailing:
+		return opEqualFold, text.String(), true
+	case insensitive:
+		return "", "", false
+	case leading && trailing:
+		return opContains, text.String(), true
+	case leading:
+		return opHasSuffix, text.String(), true
+	case trailing:
+		return opHasPrefix, text.String(), true
+	default:
+		return opEQ, text.String(), true
+	}
+}
+
+// literals returns the Go values of literal values.
+func literals(values ...*where.Value) ([]any, error) {
+	items := make([]any, len(values))
+	for i, val := range values {
+		if val == nil || val.Literal == nil || val.Bitwise != nil {
+			return nil, errors.New("fields can only be compared with literal values")
+		}
+		if val.Literal.Variable != nil {
+			return nil, errors.Errorf("missing value for variable %s", *val.Literal.Variable)
+		}
+		items[i] = val.Literal.Value()
+	}
+	return items, nil
+}
+
+func (p Predicates[P, T]) predicate(op string, values []any) (P, error) {
+	var zero P
+
+	single := map[string]func(T) P{
+		opEQ: p.EQ, opNEQ: p.NEQ, opGT: p.GT, opGTE: p.GTE, opLT: p.LT, opLTE: p.LTE,
+		opHasPrefix: p.HasPrefix, opHasSuffix: p.HasSuffix, opContains: p.Contains,
+		opEqualFold: p.EqualFold, opContainsFold: p.ContainsFold,
+	}
+	if fn, ok := single[op]; ok {
+		if fn == nil {
+			return zero, errors.Errorf("%s predicate is not mapped", op)
+		}
+		value, err := convert[T](values[0])
+		if err != nil {
+			return zero, err
+		}
+		return fn(value), nil
+	}
+
+	switch op {
+	case opIn, opNotIn:
+		fn := p.In
+		if op == opNotIn {
+			fn = p.NotIn
+		}
+		if fn == nil {
+			return zero, errors.Errorf("%s predicate is not mapped", op)
+		}
+		items := make([]T, len(values))
+		for i, value := range values {
+			item, err := convert[T](value)
+			if err != nil {
+				return zero, err
+			}
+			items[i] = item
+		}
+		return fn(items...), nil
+	case opIsNil, opNotNil:
+		fn := p.IsNil
+		if op == opNotNil {
+			fn = p.NotNil
+		}
+		if fn == nil {
+			return zero, errors.Errorf("%s predicate is not mapped", op)
+		}
+		return fn(), nil
+	default:
+		return zero, errors.Errorf("unknown predicate %s", op)
+	}
+}
+
+// convert converts a literal value into the Go type of a field. Numbers are converted to integers
+// only when they are whole and in range, and strings are parsed as times for time.Time fields.
+func convert[T any](value any) (T, error) {
+	var zero T
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+
+	target := reflect.TypeOf((*T)(nil)).Elem()
+	if value == nil {
+		return zero, errors.Errorf("cannot use NULL as %s", target)
+	}
+	rv := reflect.ValueOf(value)
+
+	if target == reflect.TypeOf(time.Time{}) {
+		if s, ok := value.(string); ok {
+			for _, layout := range where.DefaultTimeLayouts {
+				if t, err := time.Parse(layout, s); err == nil {
+					return any(t).(T), nil
+				}
+			}
+		}
+		return zero, errors.Errorf("cannot use %v as %s", value, target)
+	}
+
+	if n, ok := value.(float64); ok {
+		switch target.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if n != math.Trunc(n) || math.Abs(n) >= 1<<63 || reflect.Zero(target).OverflowInt(int64(n)) {
+				return zero, errors.Errorf("cannot use %v as %s", value, target)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if n != math.Trunc(n) || n < 0 || n >= 1<<64 || reflect.Zero(target).OverflowUint(uint64(n)) {
+				return zero, errors.Errorf("cannot use %v as %s", value, target)
+			}
+		case reflect.Float32, reflect.Float64:
+		default:
+			return zero, errors.Errorf("cannot use %v as %s", value, target)
+		}
+		return rv.Convert(target).Interface().(T), nil
+	}
+
+	if rv.Kind() == target.Kind() && rv.Type().ConvertibleTo(target) {
+		return rv.Convert(target).Interface().(T), nil
+	}
+	return zero, errors.Errorf("cannot use %v as %s", value, target)
+}
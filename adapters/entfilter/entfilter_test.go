@@ -0,0 +1,145 @@
+package entfilter_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pseudomuto/where"
+	"github.com/pseudomuto/where/adapters/entfilter"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// pred stands in for a generated ent predicate type, rendering the predicate as text.
+	pred string
+
+	status string
+)
+
+func op[T any](field, name string) func(T) pred {
+	return func(v T) pred { return pred(fmt.Sprintf("%s.%s(%#v)", field, name, v)) }
+}
+
+func list[T any](field, name string) func(...T) pred {
+	return func(vs ...T) pred { return pred(fmt.Sprintf("%s.%s(%#v)", field, name, vs)) }
+}
+
+func join(name string) func(...pred) pred {
+	return func(ps ...pred) pred {
+		parts := make([]string, len(ps))
+		for i, p := range ps {
+			parts[i] = string(p)
+		}
+		return pred(name + "(" + strings.Join(parts, ", ") + ")")
+	}
+}
+
+func schema() entfilter.Schema[pred] {
+	return entfilter.Schema[pred]{
+		Fields: map[string]entfilter.Field[pred]{
+			"age": entfilter.Predicates[pred, int]{
+				EQ: op[int]("age", "EQ"), NEQ: op[int]("age", "NEQ"),
+				GTE: op[int]("age", "GTE"), LTE: op[int]("age", "LTE"), GT: op[int]("age", "GT"),
+				In: list[int]("age", "In"),
+			},
+			"name": entfilter.Predicates[pred, string]{
+				EQ: op[string]("name", "EQ"), HasPrefix: op[string]("name", "HasPrefix"),
+				HasSuffix: op[string]("name", "HasSuffix"), Contains: op[string]("name", "Contains"),
+				EqualFold: op[string]("name", "EqualFold"), ContainsFold: op[string]("name", "ContainsFold"),
+				IsNil:  func() pred { return "name.IsNil()" },
+				NotNil: func() pred { return "name.NotNil()" },
+			},
+			"status": entfilter.Predicates[pred, status]{
+				EQ: op[status]("status", "EQ"), NotIn: list[status]("status", "NotIn"),
+			},
+			"active": entfilter.Predicates[pred, bool]{EQ: op[bool]("active", "EQ")},
+			"created_at": entfilter.Predicates[pred, time.Time]{
+				GT: func(t time.Time) pred { return pred("created_at.GT(" + t.Format(time.RFC3339) + ")") },
+			},
+		},
+		And: join("And"),
+		Or:  join("Or"),
+		Not: func(p pred) pred { return "Not(" + p + ")" },
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       pred
+	}{
+		{
+			expression: "age >= 18 AND age != 21",
+			want:       "And(age.GTE(18), age.NEQ(21))",
+		},
+		{
+			expression: "NOT (age IN (1, 2) OR active)",
+			want:       "Not(Or(age.In([]int{1, 2}), active.EQ(true)))",
+		},
+		{
+			expression: "age BETWEEN 18 AND 65",
+			want:       "And(age.GTE(18), age.LTE(65))",
+		},
+		{
+			expression: "status NOT IN ('a', 'b') AND status = 'c'",
+			want:       `And(status.NotIn([]entfilter_test.status{"a", "b"}), status.EQ("c"))`,
+		},
+		{
+			expression: "name IS NULL OR name != NULL",
+			want:       "Or(name.IsNil(), name.NotNil())",
+		},
+		{
+			expression: `name LIKE 'Jo%' AND name LIKE '%son' AND name NOT LIKE '%\%%' AND name LIKE 'Al'`,
+			want:       `And(name.HasPrefix("Jo"), name.HasSuffix("son"), Not(name.Contains("%")), name.EQ("Al"))`,
+		},
+		{
+			expression: "name ILIKE '%smith%' OR name ILIKE 'bob'",
+			want:       `Or(name.ContainsFold("smith"), name.EqualFold("bob"))`,
+		},
+		{
+			expression: "created_at > '2024-01-02'",
+			want:       "created_at.GT(2024-01-02T00:00:00Z)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			filter, err := where.Parse(tt.expression)
+			require.NoError(t, err)
+
+			got, err := entfilter.Convert(filter, schema())
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		err        string
+	}{
+		{expression: "secret = 1", err: `field "secret" is not allowed`},
+		{expression: "age < 18", err: `field "age": LT predicate is not mapped`},
+		{expression: "age = 1.5", err: `field "age": cannot use 1.5 as int`},
+		{expression: "age = 'x'", err: `field "age": cannot use x as int`},
+		{expression: "name LIKE 'a%b'", err: `pattern "a%b" has no equivalent ent predicate`},
+		{expression: "name ILIKE 'a%'", err: `pattern "a%" has no equivalent ent predicate`},
+		{expression: "LOWER(name) = 'x'", err: "only fields can be compared"},
+		{expression: "age = other", err: "fields can only be compared with literal values"},
+		{expression: "created_at > 'yesterday'", err: `field "created_at": cannot use yesterday as time.Time`},
+		{expression: "name > NULL", err: "operator > cannot be used with NULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			filter, err := where.Parse(tt.expression)
+			require.NoError(t, err)
+
+			_, err = entfilter.Convert(filter, schema())
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,451 @@
+// Package goqu converts where filters into goqu expressions, so that a filter takes part in goqu's
+// dialect handling, e.g. identifier quoting, placeholders, and ILIKE, and can be combined with
+// joins and other conditions of a goqu query rather than being added as an opaque literal clause.
+//
+// Functions are rendered as written, since goqu does not translate them between dialects, and
+// MATCHES and EXISTS conditions, which depend on the database, are not supported.
+package goqu
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/where"
+)
+
+var (
+	// comparisons maps the comparison operators of a filter to goqu comparisons, which compare
+	// with NULL and booleans using IS.
+	comparisons = map[string]func(operand, any) exp.BooleanExpression{
+		"=":  operand.Eq,
+		"!=": operand.Neq,
+		"<":  operand.Lt,
+		"<=": operand.Lte,
+		">":  operand.Gt,
+		">=": operand.Gte,
+	}
+
+	// bitwise maps the bitwise operators of a filter to goqu bitwise operations.
+	bitwise = map[string]exp.BitwiseOperation{
+		"&":  exp.BitwiseAndOp,
+		"|":  exp.BitwiseOrOp,
+		"^":  exp.BitwiseXorOp,
+		"<<": exp.BitwiseLeftShiftOp,
+		">>": exp.BitwiseRightShiftOp,
+	}
+)
+
+type (
+	// Option configures the conversion of a filter.
+	Option func(*converter)
+
+	// operand is a goqu expression that can be compared, which every converted value is.
+	operand interface {
+		exp.Expression
+		exp.Comparable
+		exp.Inable
+		exp.Isable
+		exp.Likeable
+		exp.Rangeable
+	}
+
+	// converter converts the filter AST into goqu expressions.
+	converter struct {
+		table     string
+		validator *where.Validator
+		variables map[string]any
+	}
+)
+
+// WithTable qualifies unqualified fields with a table name or alias, so that they are not ambiguous
+// when the query joins tables with columns of the same name.
+func WithTable(table string) Option {
+	return func(c *converter) {
+		c.table = table
+	}
+}
+
+// WithValidator rejects filters using fields or functions the validator does not allow.
+func WithValidator(v *where.Validator) Option {
+	return func(c *converter) {
+		c.validator = v
+	}
+}
+
+// WithVariables supplies the values of the :name variables in a filter template, like
+// where.WithVariables. A slice bound to a variable in an IN list is expanded into its elements.
+func WithVariables(vars map[string]any) Option {
+	return func(c *converter) {
+		for name, value := range vars {
+			c.variables[strings.TrimPrefix(name, ":")] = value
+		}
+	}
+}
+
+// Convert converts a filter into a goqu expression, which can be passed to the Where method of a
+// goqu dataset.
+//
+// Example:
+//
+//	import goquadapter "github.com/pseudomuto/where/adapters/goqu"
+//
+//	filter, _ := where.Parse("age >= 18 AND name ILIKE 'j%'")
+//	cond, _ := goquadapter.Convert(filter, goquadapter.WithTable("u"))
+//	sql, args, _ := goqu.Dialect("postgres").From(goqu.T("users").As("u")).Where(cond).Prepared(true).ToSQL()
+//	// SELECT * FROM "users" AS "u" WHERE (("u"."age" >= $1) AND ("u"."name" ILIKE $2))
+func Convert(filter *where.Filter, opts ...Option) (exp.Expression, error) {
+	c := &converter{variables: make(map[string]any)}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if filter == nil || filter.Expression == nil {
+		return nil, errors.New("empty filter")
+	}
+	return c.expression(filter.Expression)
+}
+
+func (c *converter) expression(expr *where.Expression) (exp.Expression, error) {
+	if expr == nil || len(expr.Or) == 0 {
+		return nil, errors.New("empty expression")
+	}
+
+	terms := make([]exp.Expression, len(expr.Or))
+	for i, term := range expr.Or {
+		cond, err := c.term(term)
+		if err != nil {
+			return nil, err
+		}
+		terms[i] = cond
+	}
+
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return goqu.Or(terms...), nil
+}
+
+func (c *converter) term(term *where.Term) (exp.Expression, error) {
+	if term == nil || len(term.And) == 0 {
+		return nil, errors.New("empty term")
+	}
+
+	factors := make([]exp.Expression, len(term.And))
+	for i, factor := range term.And {
+		cond, err := c.factor(factor)
+		if err != nil {
+			return nil, err
+		}
+		factors[i] = cond
+	}
+
+	if len(factors) == 1 {
+		return factors[0], nil
+	}
+	return goqu.And(factors...), nil
+}
+
+func (c *converter) factor(factor *where.Factor) (exp.Expression, error) {
+	var (
+		cond exp.Expression
+		err  error
+	)
+	switch {
+	case factor == nil:
+		return nil, errors.New("empty factor")
+	case factor.Exists != nil:
+		return nil, errors.New("EXISTS conditions are not supported; use a goqu subquery instead")
+	case factor.SubExpr != nil:
+		cond, err = c.expression(factor.SubExpr)
+	case factor.Predicate != nil:
+		cond, err = c.predicate(factor.Predicate)
+	default:
+		return nil, errors.New("empty factor content")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if factor.Not {
+		return goqu.L("NOT ?", cond), nil
+	}
+	return cond, nil
+}
+
+func (c *converter) predicate(pred *where.Predicate) (exp.Expression, error) {
+	if pred.Operation == nil {
+		return nil, errors.New("predicate missing operation")
+	}
+
+	left, err := c.operand(pred.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	op := pred.Operation
+	switch {
+	case op.Compare != nil:
+		return c.compare(left, op.Compare)
+	case op.Like != nil:
+		return c.like(left, op.Like)
+	case op.Between != nil:
+		lower, err := c.value(op.Between.Lower)
+		if err != nil {
+			return nil, err
+		}
+		upper, err := c.value(op.Between.Upper)
+		if err != nil {
+			return nil, err
+		}
+		if op.Between.Not {
+			return left.NotBetween(goqu.Range(lower, upper)), nil
+		}
+		return left.Between(goqu.Range(lower, upper)), nil
+	case op.In != nil:
+		values, err := c.list(op.In.Values)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			return nil, errors.New("IN expression requires at least one value")
+		}
+		if op.In.Not {
+			return left.NotIn(values...), nil
+		}
+		return left.In(values...), nil
+	case op.IsNull != nil:
+		if op.IsNull.Not {
+			return left.IsNotNull(), nil
+		}
+		return left.IsNull(), nil
+	case op.Match != nil:
+		return nil, errors.New("operator MATCHES is not supported")
+	default:
+		return nil, errors.New("empty operation")
+	}
+}
+
+// compare converts a comparison. NULL-safe equality, which goqu has no operator for, is written as
+// an equality that also matches two NULLs.
+func (c *converter) compare(left operand, cmp *where.CompareOp) (exp.Expression, error) {
+	right, err := c.value(cmp.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	operator := cmp.Operator.String()
+	if operator == "<=>" {
+		rightOperand, ok := right.(operand)
+		if !ok {
+			return left.Eq(right), nil
+		}
+		return goqu.Or(goqu.And(left.IsNull(), rightOperand.IsNull()), left.Eq(right)), nil
+	}
+
+	compare, ok := comparisons[operator]
+	if !ok {
+		return nil, errors.Errorf("operator %s is not supported", operator)
+	}
+	return compare(left, right), nil
+}
+
+func (c *converter) like(left operand, like *where.LikeOp) (exp.Expression, error) {
+	pattern, err := c.value(like.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	switch insensitive := strings.EqualFold(like.Type.Operator, "ILIKE"); {
+	case insensitive && like.Not:
+		return left.NotILike(pattern), nil
+	case insensitive:
+		return left.ILike(pattern), nil
+	case like.Not:
+		return left.NotLike(pattern), nil
+	default:
+		return left.Like(pattern), nil
+	}
+}
+
+// list converts the values of an IN list, expanding slices bound to variables.
+func (c *converter) list(values []*where.Value) ([]any, error) {
+	var items []any
+	for _, val := range values {
+		if val != nil && val.Literal != nil && val.Literal.Variable != nil {
+			value, err := c.variable(val.Literal)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, expand(value)...)
+			continue
+		}
+
+		item, err := c.value(val)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// value converts a value on the right of an operator. Literals become plain Go values, which goqu
+// binds as parameters.
+func (c *converter) value(val *where.Value) (any, error) {
+	if val != nil && val.Literal != nil && val.Bitwise == nil {
+		return c.literal(val.Literal)
+	}
+	return c.operand(val)
+}
+
+// operand converts a value into an expression that can be compared.
+func (c *converter) operand(val *where.Value) (operand, error) {
+	switch {
+	case val == nil:
+		return nil, errors.New("empty value")
+	case val.Bitwise != nil:
+		return c.bitwise(val.Bitwise)
+	case val.Field != nil:
+		return c.field(val.Field)
+	case val.Function != nil:
+		return c.function(val.Function)
+	case val.Literal != nil:
+		value, err := c.literal(val.Literal)
+		if err != nil {
+			return nil, err
+		}
+		return goqu.V(value), nil
+	case val.SubExpr != nil:
+		cond, err := c.expression(val.SubExpr)
+		if err != nil {
+			return nil, err
+		}
+		return goqu.L("?", cond), nil
+	default:
+		return nil, errors.New("empty value")
+	}
+}
+
+func (c *converter) field(field *where.FieldRef) (operand, error) {
+	name := field.String()
+	if c.validator != nil && !c.validator.IsFieldAllowed(name) {
+		return nil, errors.Errorf("field %q is not allowed", name)
+	}
+
+	parts := field.Parts
+	if len(parts) == 1 && c.table != "" {
+		parts = []string{c.table, parts[0]}
+	}
+
+	switch len(parts) {
+	case 1:
+		return goqu.C(parts[0]), nil
+	case 2:
+		return goqu.T(parts[0]).Col(parts[1]), nil
+	case 3:
+		return goqu.S(parts[0]).Table(parts[1]).Col(parts[2]), nil
+	default:
+		return nil, errors.Errorf("field %s has too many parts", name)
+	}
+}
+
+// function converts a function call, including CAST and IF expressions.
+func (c *converter) function(fn *where.FunctionCall) (operand, error) {
+	name := strings.ToUpper(fn.Name)
+	if c.validator != nil && !c.validator.IsFunctionAllowed(name) {
+		return nil, errors.Errorf("function %q is not allowed", name)
+	}
+
+	if fn.CastAs != nil {
+		if len(fn.Args) != 1 {
+			return nil, errors.New("CAST requires exactly one value")
+		}
+		value, err := c.operand(fn.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		return goqu.Cast(value, fn.CastAs.String()), nil
+	}
+
+	args := make([]any, len(fn.Args))
+	for i, arg := range fn.Args {
+		value, err := c.value(arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+
+	switch {
+	case fn.Star:
+		return goqu.Func(name, goqu.Star()), nil
+	case fn.Cond != nil:
+		if len(args) != 2 {
+			return nil, errors.New("IF requires a condition and two values")
+		}
+		cond, err := c.expression(fn.Cond)
+		if err != nil {
+			return nil, err
+		}
+		return goqu.L("?", goqu.Case().When(cond, args[0]).Else(args[1])), nil
+	default:
+		return goqu.Func(name, args...), nil
+	}
+}
+
+// bitwise converts a chain of bitwise operations, which are evaluated left to right.
+func (c *converter) bitwise(expr *where.BitwiseExpr) (operand, error) {
+	left, err := c.operand(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range expr.Ops {
+		operation, ok := bitwise[op.Operator]
+		if !ok {
+			return nil, errors.Errorf("operator %s is not supported", op.Operator)
+		}
+
+		right, err := c.value(op.Right)
+		if err != nil {
+			return nil, err
+		}
+		left = exp.NewBitwiseExpression(operation, left, right)
+	}
+	return left, nil
+}
+
+func (c *converter) literal(lit *where.LiteralValue) (any, error) {
+	if lit.Variable != nil {
+		return c.variable(lit)
+	}
+	return lit.Value(), nil
+}
+
+func (c *converter) variable(lit *where.LiteralValue) (any, error) {
+	value, ok := c.variables[strings.TrimPrefix(*lit.Variable, ":")]
+	if !ok {
+		return nil, errors.Errorf("missing value for variable %s", *lit.Variable)
+	}
+	return value, nil
+}
+
+// expand returns the elements of a slice or array, other than a byte slice, or the value itself.
+func expand(value any) []any {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []any{value}
+	}
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		return []any{value}
+	}
+
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items
+}
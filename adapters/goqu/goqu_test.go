@@ -0,0 +1,152 @@
+package goqu_test
+
+import (
+	"testing"
+
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/mysql"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+	"github.com/pseudomuto/where"
+	goquadapter "github.com/pseudomuto/where/adapters/goqu"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		opts       []goquadapter.Option
+		wantSQL    string
+		wantArgs   []any
+	}{
+		{
+			name:       "comparisons",
+			expression: "age >= 18 AND status != 'closed'",
+			wantSQL:    `SELECT * FROM "users" WHERE (("age" >= $1) AND ("status" != $2))`,
+			wantArgs:   []any{float64(18), "closed"},
+		},
+		{
+			name:       "disjunction and negation",
+			expression: "NOT (role = 'admin' OR role = 'owner') AND deleted_at IS NULL",
+			wantSQL:    `SELECT * FROM "users" WHERE (NOT (("role" = $1) OR ("role" = $2)) AND ("deleted_at" IS NULL))`,
+			wantArgs:   []any{"admin", "owner"},
+		},
+		{
+			name:       "IN, BETWEEN, and LIKE",
+			expression: "status IN ('a', 'b') AND age NOT BETWEEN 1 AND 9 AND name ILIKE 'j%'",
+			wantSQL:    `SELECT * FROM "users" WHERE (("status" IN ($1, $2)) AND ("age" NOT BETWEEN $3 AND $4) AND ("name" ILIKE $5))`,
+			wantArgs:   []any{"a", "b", float64(1), float64(9), "j%"},
+		},
+		{
+			name:       "boolean fields",
+			expression: "active AND verified = false",
+			wantSQL:    `SELECT * FROM "users" WHERE (("active" IS TRUE) AND ("verified" IS FALSE))`,
+			wantArgs:   []any{},
+		},
+		{
+			name:       "functions and casts",
+			expression: "LOWER(email) = 'x' AND CAST(score AS INTEGER) > 1",
+			wantSQL:    `SELECT * FROM "users" WHERE ((LOWER("email") = $1) AND (CAST("score" AS INTEGER) > $2))`,
+			wantArgs:   []any{"x", float64(1)},
+		},
+		{
+			name:       "COUNT(*)",
+			expression: "COUNT(*) > 1",
+			wantSQL:    `SELECT * FROM "users" WHERE (COUNT(*) > $1)`,
+			wantArgs:   []any{float64(1)},
+		},
+		{
+			name:       "qualified fields",
+			expression: "orgs.name = 'acme' AND id = 1",
+			opts:       []goquadapter.Option{goquadapter.WithTable("u")},
+			wantSQL:    `SELECT * FROM "users" WHERE (("orgs"."name" = $1) AND ("u"."id" = $2))`,
+			wantArgs:   []any{"acme", float64(1)},
+		},
+		{
+			name:       "bitwise operators",
+			expression: "flags & 4 = 4",
+			wantSQL:    `SELECT * FROM "users" WHERE (("flags" & $1) = $2)`,
+			wantArgs:   []any{float64(4), float64(4)},
+		},
+		{
+			name:       "variables",
+			expression: "status IN (:statuses) AND created_at > :since",
+			opts: []goquadapter.Option{goquadapter.WithVariables(map[string]any{
+				"statuses": []string{"a", "b"},
+				":since":   "2024-01-01",
+			})},
+			wantSQL:  `SELECT * FROM "users" WHERE (("status" IN ($1, $2)) AND ("created_at" > $3))`,
+			wantArgs: []any{"a", "b", "2024-01-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.expression)
+			require.NoError(t, err)
+
+			cond, err := goquadapter.Convert(filter, tt.opts...)
+			require.NoError(t, err)
+
+			sql, args, err := goqu.Dialect("postgres").From("users").Where(cond).Prepared(true).ToSQL()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestConvertUsesDialect(t *testing.T) {
+	filter, err := where.Parse("name ILIKE 'j%' AND email = 'x'")
+	require.NoError(t, err)
+
+	cond, err := goquadapter.Convert(filter)
+	require.NoError(t, err)
+
+	sql, args, err := goqu.Dialect("mysql").From("users").Where(cond).Prepared(true).ToSQL()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM `users` WHERE ((`name` LIKE ?) AND (`email` = ?))", sql)
+	require.Equal(t, []any{"j%", "x"}, args)
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		opts       []goquadapter.Option
+		err        string
+	}{
+		{
+			name:       "field not allowed",
+			expression: "secret = 1",
+			opts:       []goquadapter.Option{goquadapter.WithValidator(where.NewValidator().AllowFields("age"))},
+			err:        `field "secret" is not allowed`,
+		},
+		{
+			name:       "function not allowed",
+			expression: "SLEEP(10) = 0",
+			opts:       []goquadapter.Option{goquadapter.WithValidator(where.NewValidator().AllowAll().DenyFunctions("SLEEP"))},
+			err:        `function "SLEEP" is not allowed`,
+		},
+		{
+			name:       "missing variable",
+			expression: "created_at > :since",
+			err:        "missing value for variable :since",
+		},
+		{
+			name:       "full-text search",
+			expression: "body MATCHES 'fox'",
+			err:        "operator MATCHES is not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.expression)
+			require.NoError(t, err)
+
+			_, err = goquadapter.Convert(filter, tt.opts...)
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package where
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Advice rule names reported in Advice.Rule.
+const (
+	AdviceLeadingWildcard = "leading-wildcard"
+	AdviceOrAcrossColumns = "or-across-columns"
+	AdviceFunctionOnIndex = "function-on-index"
+	AdviceLowSelectivity  = "low-selectivity"
+	AdviceFullScan        = "full-scan"
+)
+
+// lowCardinality is the number of distinct values at or below which an index rarely helps.
+const lowCardinality = 2
+
+type (
+	// FieldMeta describes the storage of a field for Advise.
+	FieldMeta struct {
+		// Indexed is true if the field is the leading column of an index.
+		Indexed bool
+
+		// Cardinality is the approximate number of distinct values in the field, or 0 if unknown.
+		Cardinality int64
+	}
+
+	// FieldMetadata maps field names, as written in filters, to their metadata. Names are
+	// case-insensitive.
+	FieldMetadata map[string]FieldMeta
+
+	// Advice describes a part of a filter that is likely to make the query expensive.
+	Advice struct {
+		// Rule is the name of the rule that produced the advice.
+		Rule string
+
+		// Message is a human readable description of the problem.
+		Message string
+
+		// Expression is the offending part of the filter, formatted on a single line.
+		Expression string
+	}
+
+	// advisor collects advice while walking a filter.
+	advisor struct {
+		fm     *formatter
+		meta   FieldMetadata
+		advice []Advice
+	}
+)
+
+// String returns the advice formatted as "rule: message (expression)".
+func (a Advice) String() string {
+	return fmt.Sprintf("%s: %s (%s)", a.Rule, a.Message, a.Expression)
+}
+
+// Advise reports conditions in the filter that are likely to prevent the database from using an
+// index, so users can get feedback before running an expensive query. meta describes the fields,
+// e.g. from a schema or the database's statistics; fields missing from it are treated as unindexed.
+//
+// Advice is given for LIKE patterns starting with a wildcard, OR groups whose branches constrain
+// different fields, function calls on indexed fields, conditions on indexed fields with at most two
+// distinct values (such as booleans), and filters where no condition can use an index when meta
+// lists an indexed field. The advice is a heuristic and does not depend on the driver.
+//
+// Example:
+//
+//	filter, _ := where.Parse("name LIKE '%smith' OR email = 'x'")
+//	for _, advice := range where.Advise(filter, where.FieldMetadata{"email": {Indexed: true}}) {
+//		fmt.Println(advice)
+//	}
+//	// or-across-columns: OR across different fields prevents index use (name LIKE '%smith' OR email = 'x')
+//	// leading-wildcard: pattern starts with a wildcard on unindexed field name (name LIKE '%smith')
+//	// full-scan: no condition can use an index (name LIKE '%smith' OR email = 'x')
+func Advise(filter *Filter, meta FieldMetadata) []Advice {
+	if filter == nil || filter.Expression == nil {
+		return nil
+	}
+
+	a := &advisor{fm: &formatter{opts: &formatOptions{compact: true}}, meta: make(FieldMetadata, len(meta))}
+	anyIndexed := false
+	for name, m := range meta {
+		a.meta[strings.ToLower(name)] = m
+		anyIndexed = anyIndexed || m.Indexed
+	}
+
+	a.expression(filter.Expression)
+	if anyIndexed && !a.usesIndex(filter.Expression) {
+		a.advise(AdviceFullScan, "no condition can use an index", a.fm.expression(filter.Expression, ""))
+	}
+	return a.advice
+}
+
+func (a *advisor) advise(rule, message, expression string) {
+	a.advice = append(a.advice, Advice{Rule: rule, Message: message, Expression: expression})
+}
+
+// field returns the metadata for the field.
+func (a *advisor) field(ref *FieldRef) FieldMeta {
+	return a.meta[strings.ToLower(ref.String())]
+}
+
+func (a *advisor) expression(expr *Expression) {
+	if expr == nil {
+		return
+	}
+
+	if len(expr.Or) > 1 && !sharesField(expr) {
+		a.advise(AdviceOrAcrossColumns, "OR across different fields prevents index use", a.fm.expression(expr, ""))
+	}
+
+	for _, term := range expr.Or {
+		if term == nil {
+			continue
+		}
+		for _, factor := range term.And {
+			if factor == nil {
+				continue
+			}
+			if factor.SubExpr != nil {
+				a.expression(factor.SubExpr)
+				continue
+			}
+			a.predicate(factor.Predicate)
+		}
+	}
+}
+
+func (a *advisor) predicate(pred *Predicate) {
+	if pred == nil || pred.Operation == nil || pred.Left == nil {
+		return
+	}
+
+	text := a.fm.predicate(pred)
+	if pred.Left.Function != nil {
+		walkValue(pred.Left, func(val *Value) {
+			if val.Field != nil && a.field(val.Field).Indexed {
+				a.advise(AdviceFunctionOnIndex, fmt.Sprintf("function call on indexed field %s prevents index use", val.Field), text)
+			}
+		})
+		return
+	}
+	if pred.Left.Field == nil {
+		return
+	}
+
+	field := pred.Left.Field
+	meta := a.field(field)
+	op := pred.Operation
+	if op.Like != nil && hasLeadingWildcard(op.Like.Pattern) {
+		if meta.Indexed {
+			a.advise(AdviceLeadingWildcard, fmt.Sprintf("pattern starts with a wildcard and cannot use the index on %s", field), text)
+		} else {
+			a.advise(AdviceLeadingWildcard, fmt.Sprintf("pattern starts with a wildcard on unindexed field %s", field), text)
+		}
+	}
+
+	if meta.Indexed && meta.Cardinality > 0 && meta.Cardinality <= lowCardinality {
+		a.advise(AdviceLowSelectivity, fmt.Sprintf("field %s has %d distinct values, so its index is rarely used", field, meta.Cardinality), text)
+	}
+}
+
+// usesIndex reports whether every OR branch of the expression has a condition that can use an index.
+func (a *advisor) usesIndex(expr *Expression) bool {
+	for _, term := range expr.Or {
+		if term == nil {
+			return false
+		}
+
+		indexed := false
+		for _, factor := range conjuncts(term.And) {
+			if a.indexable(factor) {
+				indexed = true
+				break
+			}
+		}
+		if !indexed {
+			return false
+		}
+	}
+	return true
+}
+
+// indexable reports whether the factor is a condition that an index on its field can satisfy.
+func (a *advisor) indexable(factor *Factor) bool {
+	switch {
+	case factor.Not || factor.Exists != nil:
+		return false
+	case factor.SubExpr != nil:
+		return a.usesIndex(factor.SubExpr)
+	case factor.Predicate == nil || factor.Predicate.Left == nil || factor.Predicate.Left.Field == nil:
+		return false
+	}
+
+	pred := factor.Predicate
+	meta := a.field(pred.Left.Field)
+	if !meta.Indexed || (meta.Cardinality > 0 && meta.Cardinality <= lowCardinality) {
+		return false
+	}
+
+	op := pred.Operation
+	switch {
+	case op.Compare != nil:
+		sqlOp := op.Compare.Operator.String()
+		return sqlOp != "!=" && sqlOp != "<>"
+	case op.Like != nil:
+		return !op.Like.Not && !hasLeadingWildcard(op.Like.Pattern)
+	case op.Between != nil:
+		return !op.Between.Not
+	case op.In != nil:
+		return !op.In.Not
+	default:
+		return op.IsNull != nil
+	}
+}
+
+// sharesField reports whether every OR branch of the expression constrains a common field.
+func sharesField(expr *Expression) bool {
+	var common map[string]bool
+	for _, term := range expr.Or {
+		fields := make(map[string]bool)
+		if term != nil {
+			for _, factor := range conjuncts(term.And) {
+				if !factor.Not && factor.Predicate != nil && factor.Predicate.Left != nil && factor.Predicate.Left.Field != nil {
+					fields[strings.ToLower(factor.Predicate.Left.Field.String())] = true
+				}
+			}
+		}
+
+		if common == nil {
+			common = fields
+			continue
+		}
+		for field := range common {
+			if !fields[field] {
+				delete(common, field)
+			}
+		}
+	}
+	return len(common) > 0
+}
@@ -0,0 +1,117 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdvise(t *testing.T) {
+	meta := where.FieldMetadata{
+		"Email":     {Indexed: true, Cardinality: 100000},
+		"tenant_id": {Indexed: true},
+		"active":    {Indexed: true, Cardinality: 2},
+		"name":      {Cardinality: 5000},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []where.Advice
+	}{
+		{
+			name:  "indexed equality",
+			input: "tenant_id = 7 AND name = 'x'",
+		},
+		{
+			name:  "indexed prefix pattern",
+			input: "email LIKE 'bob%'",
+		},
+		{
+			name:  "OR on the same field",
+			input: "(tenant_id = 1 AND name = 'a') OR (tenant_id = 2 AND age > 3)",
+		},
+		{
+			name:  "leading wildcard on unindexed field",
+			input: "tenant_id = 7 AND name LIKE '%smith'",
+			want: []where.Advice{{
+				Rule:       where.AdviceLeadingWildcard,
+				Message:    "pattern starts with a wildcard on unindexed field name",
+				Expression: "name LIKE '%smith'",
+			}},
+		},
+		{
+			name:  "leading wildcard on indexed field",
+			input: "email ILIKE '_ob@example.com'",
+			want: []where.Advice{
+				{
+					Rule:       where.AdviceLeadingWildcard,
+					Message:    "pattern starts with a wildcard and cannot use the index on email",
+					Expression: "email ILIKE '_ob@example.com'",
+				},
+				{Rule: where.AdviceFullScan, Message: "no condition can use an index", Expression: "email ILIKE '_ob@example.com'"},
+			},
+		},
+		{
+			name:  "OR across columns",
+			input: "tenant_id = 7 AND (email = 'x' OR name = 'y')",
+			want: []where.Advice{{
+				Rule:       where.AdviceOrAcrossColumns,
+				Message:    "OR across different fields prevents index use",
+				Expression: "email = 'x' OR name = 'y'",
+			}},
+		},
+		{
+			name:  "function on indexed field",
+			input: "LOWER(email) = 'x'",
+			want: []where.Advice{
+				{
+					Rule:       where.AdviceFunctionOnIndex,
+					Message:    "function call on indexed field email prevents index use",
+					Expression: "LOWER(email) = 'x'",
+				},
+				{Rule: where.AdviceFullScan, Message: "no condition can use an index", Expression: "LOWER(email) = 'x'"},
+			},
+		},
+		{
+			name:  "low selectivity",
+			input: "active = TRUE AND tenant_id = 7",
+			want: []where.Advice{{
+				Rule:       where.AdviceLowSelectivity,
+				Message:    "field active has 2 distinct values, so its index is rarely used",
+				Expression: "active = TRUE",
+			}},
+		},
+		{
+			name:  "negated conditions",
+			input: "tenant_id != 7 AND email NOT IN ('x')",
+			want: []where.Advice{{
+				Rule:       where.AdviceFullScan,
+				Message:    "no condition can use an index",
+				Expression: "tenant_id != 7 AND email NOT IN ('x')",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, where.Advise(filter, meta))
+		})
+	}
+}
+
+func TestAdviseWithoutIndexes(t *testing.T) {
+	filter, err := where.Parse("age > 18")
+	require.NoError(t, err)
+
+	require.Empty(t, where.Advise(filter, nil))
+	require.Empty(t, where.Advise(nil, nil))
+}
+
+func TestAdviceString(t *testing.T) {
+	advice := where.Advice{Rule: where.AdviceFullScan, Message: "no condition can use an index", Expression: "age > 18"}
+	require.Equal(t, "full-scan: no condition can use an index (age > 18)", advice.String())
+}
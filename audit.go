@@ -0,0 +1,146 @@
+package where
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnsafeSQL is returned by builders created with WithAudit when the generated SQL fails the audit.
+var ErrUnsafeSQL = errors.New("unsafe SQL")
+
+// WithAudit returns a BuildOption that checks the generated SQL before returning it and fails with an
+// error wrapping ErrUnsafeSQL if, outside of quoted identifiers, it contains a statement separator, a
+// comment, or a string literal, or if an identifier quoted by the driver is not a name, or names
+// joined by dots, with every quote inside it escaped. Literals from the filter are always bound as parameters
+// and identifiers are quoted by the driver, so a failed audit points to a bug in the builder or a
+// driver rather than a malicious filter. The audit is a safety net for those bugs, not a proof that
+// the SQL is safe: it cannot tell injected keywords from generated ones, e.g. if a custom function
+// translation writes its arguments unescaped. Trusted SQL from WithFragments and Exists subqueries
+// is not audited.
+func WithAudit() BuildOption {
+	return func(b *SQLBuilder) {
+		b.audit = true
+	}
+}
+
+// trust records SQL supplied by the application so that the audit skips it.
+func (b *SQLBuilder) trust(sql string) {
+	if b.audit {
+		b.trusted = append(b.trusted, sql)
+	}
+}
+
+// quoted records an identifier quoted by the driver so that the audit can check it, and returns it.
+func (b *SQLBuilder) quoted(identifier string) string {
+	if b.audit {
+		b.identifiers = append(b.identifiers, identifier)
+	}
+	return identifier
+}
+
+// auditSQL checks each of the generated SQL strings when auditing is enabled.
+func (b *SQLBuilder) auditSQL(sqls ...string) error {
+	if !b.audit {
+		return nil
+	}
+
+	open, closing := identifierQuotes(b.driver)
+	for _, sql := range sqls {
+		for _, trusted := range b.trusted {
+			sql = strings.ReplaceAll(sql, trusted, " ")
+		}
+		if err := auditSQL(sql, open, closing); err != nil {
+			return err
+		}
+	}
+	for _, identifier := range b.identifiers {
+		if err := auditIdentifier(identifier, open, closing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// identifierQuotes returns the characters the driver quotes identifiers with, e.g. " for PostgreSQL.
+func identifierQuotes(driver Driver) (open, closing byte) {
+	quoted := driver.QuoteIdentifier("a b")
+	if len(quoted) < 2 || quoted[0] == 'a' {
+		return '"', '"'
+	}
+	return quoted[0], quoted[len(quoted)-1]
+}
+
+func auditSQL(sql string, open, closing byte) error {
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		next := byte(0)
+		if i+1 < len(sql) {
+			next = sql[i+1]
+		}
+
+		switch {
+		case ch == open:
+			end := closingQuote(sql, i+1, closing)
+			if end < 0 {
+				return fmt.Errorf("%w: unterminated identifier at offset %d", ErrUnsafeSQL, i)
+			}
+			i = end
+		case ch == '\'':
+			return fmt.Errorf("%w: string literal at offset %d", ErrUnsafeSQL, i)
+		case ch == ';':
+			return fmt.Errorf("%w: statement separator at offset %d", ErrUnsafeSQL, i)
+		case ch == '#', ch == '-' && next == '-', ch == '/' && next == '*', ch == '*' && next == '/':
+			return fmt.Errorf("%w: comment at offset %d", ErrUnsafeSQL, i)
+		}
+	}
+	return nil
+}
+
+// auditIdentifier returns an error unless the identifier is a plain or quoted name, or several
+// separated by dots, so that a quote the driver failed to escape cannot end a name early and let the
+// rest of it be read as SQL.
+func auditIdentifier(identifier string, open, closing byte) error {
+	for i := 0; ; i++ {
+		start := i
+		if i < len(identifier) && identifier[i] == open {
+			i = closingQuote(identifier, i+1, closing)
+			if i < 0 {
+				return fmt.Errorf("%w: unterminated identifier %s", ErrUnsafeSQL, identifier)
+			}
+			i++
+		} else {
+			for i < len(identifier) && isPlainIdentifierChar(identifier[i]) {
+				i++
+			}
+		}
+
+		if i == start || (i < len(identifier) && identifier[i] != '.') {
+			return fmt.Errorf("%w: malformed identifier %s", ErrUnsafeSQL, identifier)
+		}
+		if i == len(identifier) {
+			return nil
+		}
+	}
+}
+
+func isPlainIdentifierChar(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+// closingQuote returns the index of the quote ending the identifier starting at start, skipping
+// doubled quotes used as escapes, or -1 if the identifier is not terminated.
+func closingQuote(sql string, start int, quote byte) int {
+	for i := start; i < len(sql); i++ {
+		if sql[i] != quote {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return -1
+}
@@ -0,0 +1,138 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/where"
+	_ "github.com/pseudomuto/where/drivers/clickhouse"
+	_ "github.com/pseudomuto/where/drivers/mysql"
+	_ "github.com/pseudomuto/where/drivers/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+// injectionPayloads are filters crafted to smuggle SQL through literals, identifiers, and functions.
+// Each one must either be rejected by the parser or produce SQL that passes the audit.
+var injectionPayloads = []string{
+	"name = 'x'; DROP TABLE users; --'",
+	"name = 'x' OR '1'='1'",
+	"name = 'x'' OR ''1''=''1'",
+	`name = 'x\'; DROP TABLE users; --'`,
+	`name = "x"; DROP TABLE users`,
+	"name = 'a' -- comment",
+	"name = 'a' /* comment */",
+	"name = 'a' # comment",
+	"name = '/* not a comment */' AND note = '-- nor this'",
+	"id = 1; DELETE FROM users",
+	"id = 1 UNION SELECT password FROM users",
+	"id IN (1, 2); DROP TABLE users; --)",
+	"id IN ('a'); --', 'b')",
+	"`users; DROP TABLE users; --` = 1",
+	"`a' OR '1'='1` = 1",
+	"`a/*` = 1 AND `*/b` = 2",
+	"`x\"; DROP TABLE t; --` = 1",
+	"users.`id; --` = 1",
+	"LOWER(`name'--`) = 'x'",
+	"SLEEP(10) = 0",
+	"name = CHAR(39)",
+	"name = 0x27; DROP TABLE users",
+	"name = 0x273B2044524F50",
+	"name LIKE '%'; --'",
+	"name = 'x\x00'; DROP'",
+	"CAST(id AS INTEGER); DROP TABLE t; --) = 1",
+	"id BETWEEN 1 AND 2; --",
+	"(id = 1) OR (1 = 1)",
+	"name = '\\'; DROP TABLE users; --'",
+	"`\"a\" OR 1=1 OR \"b\"` = 1",
+	"`\"a\"` = 1 AND `a\"\"b` = 2",
+	"` \"` = 1",
+}
+
+func TestAuditInjectionPayloads(t *testing.T) {
+	for _, payload := range injectionPayloads {
+		filter, err := where.Parse(payload)
+		if err != nil {
+			continue
+		}
+
+		for _, driver := range []string{"postgres", "mysql", "clickhouse"} {
+			t.Run(driver+"/"+payload, func(t *testing.T) {
+				_, _, err := filter.ToSQL(driver, where.WithAudit())
+				require.NoError(t, err)
+			})
+		}
+	}
+}
+
+func TestAudit(t *testing.T) {
+	where.RegisterDriver("audit-test", &MockDriver{name: "audit-test"})
+
+	tests := []struct {
+		name    string
+		filter  *where.Filter
+		driver  string
+		options []where.BuildOption
+		wantErr string
+	}{
+		{
+			name:   "trusted fragments",
+			filter: mustParse(t, "full_name = 'x'"),
+			driver: "postgres",
+			options: []where.BuildOption{where.WithFragments(map[string]string{
+				"full_name": "first_name || ' ' || last_name",
+			})},
+		},
+		{
+			name:   "trusted EXISTS subqueries",
+			filter: where.Exists("SELECT 1 FROM orders WHERE status = 'paid' AND user_id = ?", 1),
+			driver: "mysql",
+		},
+		{
+			name:   "required filters",
+			filter: mustParse(t, "age > 18"),
+			driver: "clickhouse",
+			options: []where.BuildOption{
+				where.WithRequiredFilter(mustParse(t, "tenant_id = 'acme'")),
+			},
+		},
+		{
+			name:    "identifiers the driver does not escape",
+			filter:  mustParse(t, "`a]; DROP TABLE t; --` = 1"),
+			driver:  "audit-test",
+			wantErr: "unsafe SQL: statement separator at offset 3",
+		},
+		{
+			name:    "identifiers that end early",
+			filter:  mustParse(t, "`a] OR 1=1 OR [b` = 1"),
+			driver:  "audit-test",
+			wantErr: "unsafe SQL: malformed identifier [a] OR 1=1 OR [b]",
+		},
+		{
+			name:   "qualified identifiers",
+			filter: mustParse(t, "users.`first name` = 'x'"),
+			driver: "audit-test",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]where.BuildOption{where.WithAudit()}, tt.options...)
+			_, _, err := tt.filter.ToSQL(tt.driver, options...)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				require.True(t, errors.Is(err, where.ErrUnsafeSQL))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAuditPrewhere(t *testing.T) {
+	filter := mustParse(t, "event_date = '2024-01-01' AND `url;` = 'x'")
+
+	prewhere, rest, _, err := filter.ToPrewhereSQL("clickhouse", where.WithAudit())
+	require.NoError(t, err)
+	require.Equal(t, "(event_date = ? AND `url;` = ?)", prewhere)
+	require.Empty(t, rest)
+}
@@ -0,0 +1,136 @@
+package where
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// BatchError reports the items of a batch that failed, in order. It is returned by ParseAll and
+	// ToSQLBatch, whose results hold the items that succeeded.
+	BatchError struct {
+		// Total is the number of items in the batch.
+		Total int
+
+		// Errors holds an error for each item that failed.
+		Errors []BatchItemError
+	}
+
+	// BatchItemError is the error for one item of a batch.
+	BatchItemError struct {
+		// Index is the position of the item in the batch.
+		Index int
+
+		// Err is the error for the item.
+		Err error
+	}
+
+	// BatchResult holds the SQL and parameters built for one filter of a batch.
+	BatchResult struct {
+		SQL    string
+		Params []any
+	}
+)
+
+// Error returns the number of failed items followed by the error of each.
+func (e *BatchError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, item := range e.Errors {
+		msgs[i] = item.Error()
+	}
+	return fmt.Sprintf("%d of %d filters failed: %s", len(e.Errors), e.Total, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the error of each failed item, so errors.Is and errors.As find them.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, item := range e.Errors {
+		errs[i] = item.Err
+	}
+	return errs
+}
+
+// Error returns the error prefixed with the item's index.
+func (e BatchItemError) Error() string {
+	return fmt.Sprintf("filter %d: %v", e.Index, e.Err)
+}
+
+// ParseAll parses each input with the parser. Every input is parsed even if some fail, so all of
+// the problems can be reported at once, e.g. when validating saved filters on startup. The returned
+// slice has a filter for each input, which is nil for inputs that failed, and the error is a
+// *BatchError if any did.
+func (p *Parser) ParseAll(inputs []string) ([]*Filter, error) {
+	filters := make([]*Filter, len(inputs))
+	batchErr := &BatchError{Total: len(inputs)}
+	for i, input := range inputs {
+		filter, err := p.Parse(input)
+		if err != nil {
+			batchErr.Errors = append(batchErr.Errors, BatchItemError{Index: i, Err: err})
+			continue
+		}
+		filters[i] = filter
+	}
+	return filters, batchErr.orNil()
+}
+
+// ParseAll parses each input with a default parser, which is created once for the whole batch. See
+// Parser.ParseAll.
+//
+// Example:
+//
+//	filters, err := where.ParseAll(saved)
+//	var batchErr *where.BatchError
+//	if errors.As(err, &batchErr) {
+//		for _, item := range batchErr.Errors {
+//			log.Printf("saved filter %d is invalid: %v", item.Index, item.Err)
+//		}
+//	}
+func ParseAll(inputs []string) ([]*Filter, error) {
+	parser, err := NewParser()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create parser")
+	}
+	return parser.ParseAll(inputs)
+}
+
+// ToSQLBatch converts each filter to SQL for the driver with the same options, as ToSQL does. An
+// unknown driver fails the batch as a whole rather than each filter. Otherwise every filter is built
+// even if some fail; the returned slice has a result for each filter, which is empty for filters
+// that failed, and the error is a *BatchError if any did.
+//
+// Example:
+//
+//	results, err := where.ToSQLBatch("postgres", filters, where.WithValidator(validator))
+//	// results[i].SQL, results[i].Params
+func ToSQLBatch(driverName string, filters []*Filter, options ...BuildOption) ([]BatchResult, error) {
+	// The options are applied to find the registry, which may be set with WithRegistry.
+	probe := &SQLBuilder{registry: defaultRegistry}
+	for _, opt := range options {
+		opt(probe)
+	}
+	if _, err := probe.registry.Get(driverName); err != nil {
+		return nil, errors.Wrapf(err, "failed to get driver %q", driverName)
+	}
+
+	results := make([]BatchResult, len(filters))
+	batchErr := &BatchError{Total: len(filters)}
+	for i, filter := range filters {
+		builder, sql, err := filter.build(driverName, options)
+		if err != nil {
+			batchErr.Errors = append(batchErr.Errors, BatchItemError{Index: i, Err: err})
+			continue
+		}
+		results[i] = BatchResult{SQL: sql, Params: builder.params}
+	}
+	return results, batchErr.orNil()
+}
+
+// orNil returns nil if no item failed, so the result can be returned as an error.
+func (e *BatchError) orNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,75 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/where"
+	_ "github.com/pseudomuto/where/drivers/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAll(t *testing.T) {
+	filters, err := where.ParseAll([]string{"age > 18", "", "status = 'open'", "age > > 1"})
+	require.Len(t, filters, 4)
+	require.Equal(t, "age > 18", filters[0].String())
+	require.Nil(t, filters[1])
+	require.Equal(t, "status = 'open'", filters[2].String())
+	require.Nil(t, filters[3])
+
+	var batchErr *where.BatchError
+	require.True(t, errors.As(err, &batchErr))
+	require.Equal(t, 4, batchErr.Total)
+	require.Len(t, batchErr.Errors, 2)
+	require.Equal(t, 1, batchErr.Errors[0].Index)
+	require.Equal(t, 3, batchErr.Errors[1].Index)
+	require.EqualError(t, err, "2 of 4 filters failed: filter 1: empty filter expression; "+
+		`filter 3: failed to parse filter expression: 1:7: unexpected token ">" (expected <number>)`)
+
+	var msgErr *where.MessageError
+	require.True(t, errors.As(err, &msgErr))
+	require.Equal(t, where.MsgEmptyFilter, msgErr.Key)
+}
+
+func TestParserParseAll(t *testing.T) {
+	parser, err := where.NewParser(where.WithMaxINItems(2))
+	require.NoError(t, err)
+
+	filters, err := parser.ParseAll([]string{"id IN (1, 2)", "name = 'x'"})
+	require.NoError(t, err)
+	require.Len(t, filters, 2)
+
+	_, err = parser.ParseAll([]string{"id IN (1, 2, 3)"})
+	require.EqualError(t, err, "1 of 1 filters failed: filter 0: filter validation failed: IN expression exceeds maximum of 2 items")
+
+	filters, err = parser.ParseAll(nil)
+	require.NoError(t, err)
+	require.Empty(t, filters)
+}
+
+func TestToSQLBatch(t *testing.T) {
+	validator := where.NewValidator().AllowFields("age", "status")
+	filters := []*where.Filter{
+		mustParse(t, "age > 18"),
+		mustParse(t, "secret = 1"),
+		mustParse(t, "status IN ('a', 'b')"),
+	}
+
+	results, err := where.ToSQLBatch("postgres", filters, where.WithValidator(validator))
+	require.EqualError(t, err, `1 of 3 filters failed: filter 1: field "secret" is not allowed`)
+	require.Equal(t, []where.BatchResult{
+		{SQL: "age > $1", Params: []any{18.0}},
+		{},
+		{SQL: "status IN ($1, $2)", Params: []any{"a", "b"}},
+	}, results)
+
+	results, err = where.ToSQLBatch("postgres", filters[:1])
+	require.NoError(t, err)
+	require.Equal(t, []where.BatchResult{{SQL: "age > $1", Params: []any{18.0}}}, results)
+
+	_, err = where.ToSQLBatch("nope", filters)
+	require.ErrorContains(t, err, `failed to get driver "nope"`)
+
+	_, err = where.ToSQLBatch("postgres", filters, where.WithRegistry(where.NewRegistry()))
+	require.ErrorContains(t, err, `failed to get driver "postgres"`)
+}
@@ -0,0 +1,159 @@
+package where_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pseudomuto/where"
+)
+
+var (
+	simpleFilter  = "age >= 18 AND status = 'active'"
+	complexFilter = `(age BETWEEN 18 AND 65 OR is_verified = true) AND
+		email NOT LIKE '%spam%' AND
+		status IN ('active', 'premium', 'vip') AND
+		LOWER(name) ILIKE '%john%' AND
+		NOT (country = 'XX' OR ip_address IS NULL) AND
+		created_at >= '2024-01-01' AND COALESCE(score, 0) > 10.5`
+)
+
+func BenchmarkParse(b *testing.B) {
+	benchmarks := []struct {
+		name  string
+		input string
+	}{
+		{"simple", simpleFilter},
+		{"complex", complexFilter},
+		{"in_100", inFilter(100)},
+		{"in_1000", inFilter(1000)},
+		{"nested_10", strings.Repeat("(", 10) + simpleFilter + strings.Repeat(")", 10)},
+	}
+
+	parser, err := where.NewParser()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(bm.input)))
+			for b.Loop() {
+				if _, err := parser.Parse(bm.input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkNewParser(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := where.NewParser(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkToSQL(b *testing.B) {
+	benchmarks := []struct {
+		name    string
+		input   string
+		driver  string
+		options []where.BuildOption
+	}{
+		{"simple/postgres", simpleFilter, "postgres", nil},
+		{"simple/mysql", simpleFilter, "mysql", nil},
+		{"simple/clickhouse", simpleFilter, "clickhouse", nil},
+		{"complex/postgres", complexFilter, "postgres", nil},
+		{"complex/mysql", complexFilter, "mysql", nil},
+		{"complex/clickhouse", complexFilter, "clickhouse", nil},
+		{"complex/validator", complexFilter, "postgres", []where.BuildOption{
+			where.WithValidator(where.NewValidator().
+				AllowFields("age", "is_verified", "email", "status", "name", "country", "ip_address", "created_at", "score").
+				AllowFunctions("LOWER", "COALESCE")),
+		}},
+		{"in_1000/postgres", inFilter(1000), "postgres", nil},
+		{"in_1000/dedup", inFilter(1000), "postgres", []where.BuildOption{where.WithParamDeduplication()}},
+		{"in_1000/array", inFilter(1000), "postgres", []where.BuildOption{where.WithArrayBinding()}},
+	}
+
+	for _, bm := range benchmarks {
+		filter, err := where.Parse(bm.input)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, _, err := filter.ToSQL(bm.driver, bm.options...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseAndToSQL(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		filter, err := where.Parse(complexFilter)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, _, err := filter.ToSQL("postgres"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := where.Format(complexFilter); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompiledMatch(b *testing.B) {
+	event := map[string]any{
+		"age":         42,
+		"is_verified": true,
+		"email":       "john@example.com",
+		"status":      "premium",
+		"name":        "John Smith",
+		"country":     "CA",
+		"ip_address":  "10.0.0.1",
+		"created_at":  "2024-06-01",
+		"score":       12.0,
+	}
+
+	filter, err := where.Parse(complexFilter)
+	if err != nil {
+		b.Fatal(err)
+	}
+	match, err := filter.Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+	if !match(event) {
+		b.Fatal("event does not match")
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		match(event)
+	}
+}
+
+func inFilter(n int) string {
+	values := make([]string, n)
+	for i := range values {
+		values[i] = fmt.Sprint(i)
+	}
+	return "id IN (" + strings.Join(values, ", ") + ")"
+}
@@ -0,0 +1,168 @@
+package where
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Bind returns the filter simplified for rows whose fields have the given values, e.g. the tenant_id
+// of the request, so a smaller filter is left for the database. Predicates that only use bound
+// fields and literals are evaluated as Compile does, and conditions that become constant are
+// removed: true conditions are dropped from AND, false ones from OR, and an AND with a false
+// condition or an OR with a true one collapses. Qualified fields are bound as a key of that name or
+// through nested maps.
+//
+// A nil filter is returned when every row with the values matches, and the filter 1 = 0 when none
+// can. Predicates using unbound fields, EXISTS, or anything Compile does not support, such as CAST,
+// are kept, as are predicates that are neither true nor false because they compare a bound NULL.
+// The returned filter shares AST nodes with f.
+//
+// Example:
+//
+//	filter, _ := where.Parse("(tenant_id = 7 AND status = 'open') OR (tenant_id = 8 AND public)")
+//	bound, _ := filter.Bind(map[string]any{"tenant_id": 7})
+//	// status = 'open'
+func (f *Filter) Bind(values map[string]any, opts ...CompileOption) (*Filter, error) {
+	options := &compileOptions{variables: make(map[string]any), timeLayouts: DefaultTimeLayouts}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if f == nil || f.Expression == nil {
+		return nil, errors.New("empty filter")
+	}
+
+	b := &binder{options: options, values: values}
+	expr, result := b.expression(f.Expression)
+	switch result {
+	case truthTrue:
+		return nil, nil
+	case truthFalse:
+		return Parse("1 = 0")
+	default:
+		return &Filter{Pos: f.Pos, Expression: expr}, nil
+	}
+}
+
+// binder simplifies a filter for bound field values. Its methods return truthTrue or truthFalse for
+// conditions that became constant, and truthUnknown with the remaining condition otherwise.
+type binder struct {
+	options *compileOptions
+	values  map[string]any
+}
+
+func (b *binder) expression(expr *Expression) (*Expression, truth) {
+	var terms []*Term
+	for _, term := range expr.Or {
+		t, result := b.term(term)
+		switch result {
+		case truthTrue:
+			return nil, truthTrue
+		case truthUnknown:
+			terms = append(terms, t)
+		}
+	}
+
+	if len(terms) == 0 {
+		return nil, truthFalse
+	}
+	return &Expression{Or: terms}, truthUnknown
+}
+
+func (b *binder) term(term *Term) (*Term, truth) {
+	var factors []*Factor
+	for _, factor := range term.And {
+		f, result := b.factor(factor)
+		switch result {
+		case truthFalse:
+			return nil, truthFalse
+		case truthUnknown:
+			factors = append(factors, f)
+		}
+	}
+
+	if len(factors) == 0 {
+		return nil, truthTrue
+	}
+	return &Term{And: factors}, truthUnknown
+}
+
+func (b *binder) factor(factor *Factor) (*Factor, truth) {
+	switch {
+	case factor.SubExpr != nil:
+		expr, result := b.expression(factor.SubExpr)
+		if result != truthUnknown {
+			return nil, negateTruth(factor.Not, result)
+		}
+
+		// A group left with a single condition is replaced by it, e.g. NOT (a = 1 OR b = 2) with
+		// b bound to 3 becomes NOT a = 1.
+		if len(expr.Or) == 1 && len(expr.Or[0].And) == 1 {
+			inner := *expr.Or[0].And[0]
+			inner.Not = inner.Not != factor.Not
+			return &inner, truthUnknown
+		}
+		return &Factor{Not: factor.Not, SubExpr: expr}, truthUnknown
+	case factor.Predicate != nil:
+		result := b.predicate(factor.Predicate)
+		if result == truthUnknown {
+			return factor, truthUnknown
+		}
+		return nil, negateTruth(factor.Not, result)
+	default:
+		return factor, truthUnknown
+	}
+}
+
+// predicate evaluates a predicate whose fields are all bound, or returns truthUnknown.
+func (b *binder) predicate(pred *Predicate) truth {
+	bound := true
+	walkPredicateValues(pred, func(val *Value) {
+		if val.Field != nil && !hasField(b.values, val.Field.Parts) {
+			bound = false
+		}
+	})
+	if !bound {
+		return truthUnknown
+	}
+
+	cond, err := b.options.predicate(pred)
+	if err != nil {
+		return truthUnknown
+	}
+	return cond(b.values)
+}
+
+// hasField returns true if the field is a key of values, or is found through nested maps.
+func hasField(values map[string]any, parts []string) bool {
+	if _, ok := values[strings.Join(parts, ".")]; ok {
+		return true
+	}
+
+	current := values
+	for i, part := range parts {
+		v, ok := current[part]
+		if !ok {
+			return false
+		}
+		if i == len(parts)-1 {
+			return true
+		}
+		if current, ok = v.(map[string]any); !ok {
+			return false
+		}
+	}
+	return false
+}
+
+// negateTruth returns the truth value negated when not is true.
+func negateTruth(not bool, t truth) truth {
+	if !not || t == truthUnknown {
+		return t
+	}
+	if t == truthTrue {
+		return truthFalse
+	}
+	return truthTrue
+}
@@ -0,0 +1,174 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBind(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		values map[string]any
+		want   string
+	}{
+		{
+			name:   "drops true condition",
+			input:  "tenant_id = 7 AND status = 'open'",
+			values: map[string]any{"tenant_id": 7},
+			want:   "status = 'open'",
+		},
+		{
+			name:   "false condition collapses AND",
+			input:  "tenant_id = 7 AND status = 'open'",
+			values: map[string]any{"tenant_id": 8},
+			want:   "1 = 0",
+		},
+		{
+			name:   "true condition collapses OR",
+			input:  "tenant_id = 7 OR status = 'open'",
+			values: map[string]any{"tenant_id": 7},
+			want:   "",
+		},
+		{
+			name:   "drops false branch",
+			input:  "(tenant_id = 7 AND status = 'open') OR (tenant_id = 8 AND public)",
+			values: map[string]any{"tenant_id": 7},
+			want:   "status = 'open'",
+		},
+		{
+			name:   "keeps remaining group",
+			input:  "tenant_id IN (1, 2) AND (status = 'open' OR priority > 3) AND region = 'eu'",
+			values: map[string]any{"tenant_id": 2, "region": "eu"},
+			want:   "(status = 'open' OR priority > 3)",
+		},
+		{
+			name:   "negated group",
+			input:  "NOT (tenant_id = 7 OR status = 'closed')",
+			values: map[string]any{"tenant_id": 8},
+			want:   "NOT status = 'closed'",
+		},
+		{
+			name:   "negated constant",
+			input:  "NOT (tenant_id = 7 AND deleted) AND name LIKE 'a%'",
+			values: map[string]any{"tenant_id": 7, "deleted": false},
+			want:   "name LIKE 'a%'",
+		},
+		{
+			name:   "nested field",
+			input:  "user.role = 'admin' OR owner_id = 3",
+			values: map[string]any{"user": map[string]any{"role": "viewer"}},
+			want:   "owner_id = 3",
+		},
+		{
+			name:   "partially bound predicate is kept",
+			input:  "tenant_id = owner_id AND tenant_id > 0",
+			values: map[string]any{"tenant_id": 7},
+			want:   "tenant_id = owner_id",
+		},
+		{
+			name:   "unsupported function is kept",
+			input:  "DATE_TRUNC('day', created_at) = '2024-01-01' AND tenant_id = 7",
+			values: map[string]any{"tenant_id": 7, "created_at": "2024-01-01"},
+			want:   "DATE_TRUNC('day', created_at) = '2024-01-01'",
+		},
+		{
+			name:   "null comparison is kept",
+			input:  "parent_id = 3 AND status = 'open'",
+			values: map[string]any{"parent_id": nil},
+			want:   "parent_id = 3 AND status = 'open'",
+		},
+		{
+			name:   "null check",
+			input:  "parent_id IS NULL AND status = 'open'",
+			values: map[string]any{"parent_id": nil},
+			want:   "status = 'open'",
+		},
+		{
+			name:   "constant comparison",
+			input:  "1 = 1 AND status = 'open'",
+			values: nil,
+			want:   "status = 'open'",
+		},
+		{
+			name:   "nothing bound",
+			input:  "status = 'open' OR (priority > 3 AND NOT archived)",
+			values: map[string]any{"tenant_id": 7},
+			want:   "status = 'open' OR (priority > 3 AND NOT archived)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+			original := filter.String()
+
+			bound, err := filter.Bind(tt.values)
+			require.NoError(t, err)
+			if tt.want == "" {
+				require.Nil(t, bound)
+				return
+			}
+			require.NotNil(t, bound)
+			require.Equal(t, tt.want, bound.String())
+
+			// The original filter is not modified.
+			require.Equal(t, original, filter.String())
+		})
+	}
+}
+
+func TestBindEquivalence(t *testing.T) {
+	filter, err := where.Parse("(tenant_id = 7 AND status = 'open') OR (tenant_id = 8 AND NOT (priority < 3 OR tenant_id = 8))")
+	require.NoError(t, err)
+
+	events := []map[string]any{
+		{"status": "open", "priority": 1},
+		{"status": "closed", "priority": 5},
+		{"status": "open", "priority": 4},
+	}
+
+	for _, tenant := range []int{7, 8, 9} {
+		bound, err := filter.Bind(map[string]any{"tenant_id": tenant})
+		require.NoError(t, err)
+
+		match, err := filter.Compile()
+		require.NoError(t, err)
+
+		for _, event := range events {
+			withTenant := map[string]any{"tenant_id": tenant}
+			for k, v := range event {
+				withTenant[k] = v
+			}
+
+			// A nil filter matches every event.
+			got := true
+			if bound != nil {
+				boundMatch, err := bound.Compile()
+				require.NoError(t, err)
+				got = boundMatch(event)
+			}
+			require.Equal(t, match(withTenant), got, "tenant %d, event %v", tenant, event)
+		}
+	}
+}
+
+func TestBindCompileOptions(t *testing.T) {
+	filter, err := where.Parse("tenant_id = :tenant AND status = 'open'")
+	require.NoError(t, err)
+
+	// Without a value for the variable, the predicate cannot be evaluated.
+	bound, err := filter.Bind(map[string]any{"tenant_id": 7})
+	require.NoError(t, err)
+	require.Equal(t, "tenant_id = :tenant AND status = 'open'", bound.String())
+
+	bound, err = filter.Bind(map[string]any{"tenant_id": 7}, where.WithCompileVariables(map[string]any{"tenant": 7}))
+	require.NoError(t, err)
+	require.Equal(t, "status = 'open'", bound.String())
+
+	_, err = (&where.Filter{}).Bind(nil)
+	require.EqualError(t, err, "empty filter")
+}
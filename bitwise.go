@@ -0,0 +1,90 @@
+package where
+
+// resolveBitwise moves the first operand of each bitwise expression into BitwiseExpr.Left. The
+// grammar parses a chain such as a & b | c with each operator holding the rest of the chain, so the
+// operations are also flattened to be evaluated left to right. A parenthesized bitwise expression,
+// as in flags & (1 | 2), is kept as a single operand.
+func resolveBitwise(expr *Expression) {
+	walkValues(expr, func(val *Value) {
+		if val.SubExpr != nil {
+			if inner := bareValue(val.SubExpr); inner != nil && inner.Bitwise != nil {
+				resolveBitwiseValue(inner)
+				val.SubExpr, val.Bitwise = nil, inner.Bitwise
+			}
+		}
+		resolveBitwiseValue(val)
+	})
+}
+
+func resolveBitwiseValue(val *Value) {
+	if val.Bitwise == nil || val.Bitwise.Left != nil {
+		return
+	}
+
+	val.Bitwise.Left = &Value{Function: val.Function, Field: val.Field, Literal: val.Literal, SubExpr: val.SubExpr}
+	val.Function, val.Field, val.Literal, val.SubExpr = nil, nil, nil, nil
+	val.Bitwise.Ops = flattenBitwise(val.Bitwise.Ops)
+}
+
+// flattenBitwise returns the operations with the operations parsed into their right operands
+// appended after them.
+func flattenBitwise(ops []*BitwiseOp) []*BitwiseOp {
+	flat := make([]*BitwiseOp, 0, len(ops))
+	for _, op := range ops {
+		rest := op.Right.Bitwise
+		if rest == nil || rest.Left != nil {
+			flat = append(flat, op)
+			continue
+		}
+
+		op.Right.Bitwise = nil
+		flat = append(flat, op)
+		flat = append(flat, flattenBitwise(rest.Ops)...)
+	}
+	return flat
+}
+
+// buildBitwise renders a bitwise expression, parenthesizing each intermediate result so it is
+// evaluated left to right whatever the database's operator precedence. Drivers without the BITWISE
+// feature reject it, and TranslateOperator spells the operators, e.g. ^ is # in PostgreSQL.
+func (b *SQLBuilder) buildBitwise(bw *BitwiseExpr) (string, error) {
+	if !b.driver.SupportsFeature("BITWISE") {
+		return "", newMessage(MsgOperatorNotSupported, "operator", bw.Ops[0].Operator, "driver", b.driver.Name())
+	}
+
+	// Masks are integers, so integer literals are bound as integers.
+	integers := b.integers
+	b.integers = true
+	defer func() { b.integers = integers }()
+
+	sql, err := b.bitwiseOperand(bw.Left)
+	if err != nil {
+		return "", err
+	}
+
+	for i, op := range bw.Ops {
+		operator, supported := b.driver.TranslateOperator(op.Operator)
+		if !supported {
+			return "", newMessage(MsgOperatorNotSupported, "operator", op.Operator, "driver", b.driver.Name())
+		}
+
+		right, err := b.bitwiseOperand(op.Right)
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			sql = "(" + sql + ")"
+		}
+		sql += " " + operator + " " + right
+	}
+	return sql, nil
+}
+
+// bitwiseOperand renders an operand of a bitwise expression, parenthesizing nested expressions.
+func (b *SQLBuilder) bitwiseOperand(val *Value) (string, error) {
+	sql, err := b.buildValue(val)
+	if err != nil || val.Bitwise == nil {
+		return sql, err
+	}
+	return "(" + sql + ")", nil
+}
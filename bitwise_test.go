@@ -0,0 +1,137 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitwise(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       map[string]string
+		wantParams []any
+	}{
+		{
+			name:  "flag mask",
+			input: "flags & 4 = 4",
+			want: map[string]string{
+				"postgres": "flags & $1 = $2",
+				"mysql":    "flags & ? = ?",
+			},
+			wantParams: []any{int64(4), float64(4)},
+		},
+		{
+			name:  "or",
+			input: "perm | 1 > 0 AND status = 'active'",
+			want: map[string]string{
+				"postgres": "(perm | $1 > $2 AND status = $3)",
+			},
+			wantParams: []any{int64(1), float64(0), "active"},
+		},
+		{
+			name:  "xor",
+			input: "mask ^ 255 != 0",
+			want: map[string]string{
+				"postgres": "mask # $1 != $2",
+				"mysql":    "mask ^ ? != ?",
+			},
+			wantParams: []any{int64(255), float64(0)},
+		},
+		{
+			name:  "left to right",
+			input: "a & 12 >> 2 | b = 3",
+			want: map[string]string{
+				"postgres": "((a & $1) >> $2) | b = $3",
+			},
+			wantParams: []any{int64(12), int64(2), float64(3)},
+		},
+		{
+			name:  "grouped operand",
+			input: "flags & (1 | 2) IN (1, 2, 3)",
+			want: map[string]string{
+				"mysql": "flags & (? | ?) IN (?, ?, ?)",
+			},
+			wantParams: []any{int64(1), int64(2), float64(1), float64(2), float64(3)},
+		},
+		{
+			name:  "functions and variables",
+			input: "ABS(flags << :shift) & mask > 0",
+			want: map[string]string{
+				"postgres": "ABS(flags << $1) & mask > $2",
+			},
+			wantParams: []any{2, float64(0)},
+		},
+	}
+
+	for _, tt := range tests {
+		filter, err := where.Parse(tt.input)
+		require.NoError(t, err)
+
+		for driver, want := range tt.want {
+			t.Run(tt.name+"/"+driver, func(t *testing.T) {
+				sql, params, err := filter.ToSQL(driver, where.WithVariables(map[string]any{"shift": 2}))
+				require.NoError(t, err)
+				require.Equal(t, want, sql)
+				require.Equal(t, tt.wantParams, params)
+			})
+		}
+	}
+}
+
+func TestBitwiseNumberTypes(t *testing.T) {
+	filter, err := where.Parse("flags & 4 = 4.5")
+	require.NoError(t, err)
+
+	_, params, err := filter.ToSQL("postgres", where.WithNumberType(where.NumberString))
+	require.NoError(t, err)
+	require.Equal(t, []any{"4", "4.5"}, params)
+}
+
+func TestBitwiseErrors(t *testing.T) {
+	filter, err := where.Parse("flags & 4 = 4")
+	require.NoError(t, err)
+
+	_, _, err = filter.ToSQL("clickhouse")
+	require.EqualError(t, err, "operator & not supported by driver clickhouse")
+
+	_, err = where.Parse("flags & 4")
+	require.ErrorContains(t, err, "flags & 4 is not a condition")
+
+	_, err = where.Parse("flags & = 4")
+	require.ErrorContains(t, err, "failed to parse filter expression")
+}
+
+func TestBitwiseAnalysis(t *testing.T) {
+	filter, err := where.Parse("flags&4=4 AND a & (b|c) << 1 > 0")
+	require.NoError(t, err)
+
+	require.Equal(t, "flags & 4 = 4 AND a & (b | c) << 1 > 0", filter.String())
+	require.Equal(t, filter.String(), filter.Clone().String())
+
+	reparsed, err := where.Parse(filter.String())
+	require.NoError(t, err)
+	require.True(t, filter.Equal(reparsed))
+
+	values, ok := where.ExtractEqualityValues(filter, "flags")
+	require.False(t, ok)
+	require.Empty(t, values)
+
+	require.Equal(t, `AND
+  PREDICATE =
+    BITWISE &
+      FIELD flags
+      LITERAL 4 (number)
+    LITERAL 4 (number)
+  PREDICATE >
+    BITWISE <<
+      BITWISE &
+        FIELD a
+        BITWISE |
+          FIELD b
+          FIELD c
+      LITERAL 1 (number)
+    LITERAL 0 (number)`, filter.Explain().String())
+}
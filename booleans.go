@@ -0,0 +1,56 @@
+package where
+
+import "fmt"
+
+// resolveBooleans completes boolean predicates, a field or function call used as a condition on
+// its own, as a comparison with TRUE, e.g. is_active becomes is_active = TRUE. The TRUE is marked
+// implicit so the predicate is formatted as written and built as a bare column where the driver
+// supports it.
+func resolveBooleans(expr *Expression) error {
+	// A function's first argument parses as a condition when it is a single value, which is only
+	// an argument.
+	walkValues(expr, func(val *Value) {
+		if fn := val.Function; fn != nil && fn.Cond != nil {
+			if arg := bareValue(fn.Cond); arg != nil {
+				fn.Cond, fn.Args = nil, append([]*Value{arg}, fn.Args...)
+			}
+		}
+	})
+
+	return walkFactors(expr, func(factor *Factor) error {
+		pred := factor.Predicate
+		if pred == nil || pred.Operation != nil {
+			return nil
+		}
+		if pred.Left == nil || (pred.Left.Field == nil && pred.Left.Function == nil) {
+			fm := &formatter{opts: &formatOptions{compact: true}}
+			return fmt.Errorf("%s is not a condition", fm.value(pred.Left))
+		}
+
+		pred.Operation = &Operation{Compare: &CompareOp{
+			Operator: CompareOperator{Type: "="},
+			Right:    &Value{Literal: &LiteralValue{Boolean: &BooleanLit{True: true}, implicit: true}},
+		}}
+		return nil
+	})
+}
+
+// isBooleanPredicate returns true if the predicate is a boolean predicate completed by
+// resolveBooleans.
+func isBooleanPredicate(pred *Predicate) bool {
+	op := pred.Operation
+	return op != nil && op.Compare != nil && op.Compare.Operator.String() == "=" &&
+		op.Compare.Right != nil && op.Compare.Right.Literal != nil && op.Compare.Right.Literal.implicit
+}
+
+// bareValue returns the value if the expression is a single value without an operation.
+func bareValue(expr *Expression) *Value {
+	if len(expr.Or) != 1 || len(expr.Or[0].And) != 1 {
+		return nil
+	}
+	factor := expr.Or[0].And[0]
+	if factor.Not || factor.Predicate == nil || factor.Predicate.Operation != nil {
+		return nil
+	}
+	return factor.Predicate.Left
+}
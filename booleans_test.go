@@ -0,0 +1,131 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+// noBooleanDriver is a MockDriver without bare boolean predicates.
+type noBooleanDriver struct {
+	MockDriver
+}
+
+func (d *noBooleanDriver) SupportsFeature(feature string) bool { return feature != "BOOLEAN" }
+
+func TestBooleanPredicates(t *testing.T) {
+	where.RegisterDriver("no-boolean", &noBooleanDriver{MockDriver{name: "no-boolean"}})
+
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{
+			name:  "field",
+			input: "is_active",
+			want: map[string]string{
+				"postgres":   "is_active",
+				"mysql":      "is_active",
+				"no-boolean": "[is_active] = TRUE",
+			},
+		},
+		{
+			name:  "negated",
+			input: "is_active AND NOT is_deleted",
+			want: map[string]string{
+				"postgres":   "(is_active AND NOT (is_deleted))",
+				"no-boolean": "([is_active] = TRUE AND NOT ([is_deleted] = TRUE))",
+			},
+		},
+		{
+			name:  "mixed",
+			input: "(verified OR role = 'admin') AND users.enabled",
+			want: map[string]string{
+				"postgres":   "((verified OR role = $1) AND users.enabled)",
+				"no-boolean": "(([verified] = TRUE OR [role] = ?) AND [users].[enabled] = TRUE)",
+			},
+		},
+		{
+			name:  "function",
+			input: "startsWith(name, 'a') AND NOT empty(tags)",
+			want: map[string]string{
+				"clickhouse": "(startsWith(name, ?) AND NOT (empty(tags)))",
+				"no-boolean": "(startsWith([name], ?) = TRUE AND NOT (empty([tags]) = TRUE))",
+			},
+		},
+		{
+			name:  "reserved word",
+			input: "`order`",
+			want: map[string]string{
+				"postgres": `"order"`,
+				"mysql":    "`order`",
+			},
+		},
+		{
+			name:  "negated predicate",
+			input: "NOT age > 21",
+			want: map[string]string{
+				"postgres": "NOT (age > $1)",
+			},
+		},
+		{
+			name:  "IF argument",
+			input: "IF(is_active, 1, 0) = 1 AND archived",
+			want: map[string]string{
+				"postgres": "(CASE WHEN is_active THEN $1 ELSE $2 END = $3 AND archived)",
+				"mysql":    "(IF(is_active, ?, ?) = ? AND archived)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		filter, err := where.Parse(tt.input)
+		require.NoError(t, err)
+
+		for driver, want := range tt.want {
+			t.Run(tt.name+"/"+driver, func(t *testing.T) {
+				sql, _, err := filter.ToSQL(driver)
+				require.NoError(t, err)
+				require.Equal(t, want, sql)
+			})
+		}
+	}
+}
+
+func TestBooleanPredicateErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{input: "'yes' AND a = 1", wantErr: "'yes' is not a condition"},
+		{input: "a = 1 OR 42", wantErr: "42 is not a condition"},
+		{input: "NOT", wantErr: "failed to parse filter expression"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := where.Parse(tt.input)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBooleanPredicateAnalysis(t *testing.T) {
+	filter, err := where.Parse("is_active AND NOT is_deleted")
+	require.NoError(t, err)
+
+	require.Equal(t, "is_active AND NOT is_deleted", filter.String())
+	require.Equal(t, filter.String(), filter.Clone().String())
+	require.Equal(t, "is_active AND is_deleted != TRUE", filter.ToNNF().String())
+
+	values, ok := where.ExtractEqualityValues(filter, "is_active")
+	require.True(t, ok)
+	require.Equal(t, []any{true}, values)
+
+	explicit, err := where.Parse("is_active = TRUE AND NOT is_deleted = TRUE")
+	require.NoError(t, err)
+	require.True(t, filter.Equal(explicit))
+	require.Empty(t, filter.Lint())
+}
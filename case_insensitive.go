@@ -0,0 +1,83 @@
+package where
+
+import "strings"
+
+// WithCaseInsensitiveEquality returns a BuildOption that compares strings ignoring case in =, !=,
+// IN, and NOT IN conditions on the given fields, or on every field if none are given. Both sides are
+// lowercased, e.g. email = 'Ann@example.com' becomes LOWER(email) = LOWER($1). Only conditions
+// comparing a field with string literals, or variables holding strings, are affected, and fields
+// with a declared type (see WithFieldTypes) are left unchanged. Field names are case-insensitive.
+//
+// An index on the field is not used unless it is an expression index on its lowercased value.
+//
+// Example:
+//
+//	filter, _ := where.Parse("email = 'Ann@example.com'")
+//	sql, _, _ := filter.ToSQL("postgres", where.WithCaseInsensitiveEquality("email"))
+//	// LOWER(email) = LOWER($1)
+func WithCaseInsensitiveEquality(fields ...string) BuildOption {
+	return func(b *SQLBuilder) {
+		b.foldCase = true
+		for _, field := range fields {
+			if b.foldFields == nil {
+				b.foldFields = make(map[string]bool, len(fields))
+			}
+			b.foldFields[strings.ToLower(field)] = true
+		}
+	}
+}
+
+// foldsCase returns true if the predicate is compared ignoring case.
+func (b *SQLBuilder) foldsCase(pred *Predicate) bool {
+	if !b.foldCase || b.fieldType != "" || pred.Left == nil || pred.Left.Field == nil {
+		return false
+	}
+	if len(b.foldFields) > 0 && !b.foldFields[strings.ToLower(pred.Left.Field.String())] {
+		return false
+	}
+
+	op := pred.Operation
+	switch {
+	case op.Compare != nil:
+		operator := op.Compare.Operator.String()
+		return (operator == "=" || isNotEqual(operator)) && b.isString(op.Compare.Right)
+	case op.In != nil:
+		if len(op.In.Values) == 0 {
+			return false
+		}
+		for _, val := range op.In.Values {
+			if !b.isString(val) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// isString returns true if the value is a string literal or a variable holding strings.
+func (b *SQLBuilder) isString(val *Value) bool {
+	if val == nil || val.Literal == nil {
+		return false
+	}
+	if val.Literal.Variable == nil {
+		return val.Literal.Type() == "string"
+	}
+
+	values := expandValues([]any{b.variables[val.Literal.variableName()]})
+	for _, value := range values {
+		if _, ok := value.(string); !ok {
+			return false
+		}
+	}
+	return len(values) > 0
+}
+
+// lower returns SQL that lowercases expr, using the driver's function if it implements Lowercaser.
+func (b *SQLBuilder) lower(expr string) string {
+	if lowercaser, ok := DriverAs[Lowercaser](b.driver); ok {
+		return lowercaser.Lower(expr)
+	}
+	return "LOWER(" + expr + ")"
+}
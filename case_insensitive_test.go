@@ -0,0 +1,102 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCaseInsensitiveEquality(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		driver  string
+		options []where.BuildOption
+		want    string
+		params  []any
+	}{
+		{
+			name:    "equality",
+			input:   "email = 'Ann@Example.com'",
+			driver:  "postgres",
+			options: []where.BuildOption{where.WithCaseInsensitiveEquality()},
+			want:    "LOWER(email) = LOWER($1)",
+			params:  []any{"Ann@Example.com"},
+		},
+		{
+			name:    "inequality and IN",
+			input:   "email != 'a@x.com' AND role NOT IN ('Admin', 'Owner')",
+			driver:  "mysql",
+			options: []where.BuildOption{where.WithCaseInsensitiveEquality()},
+			want:    "(LOWER(email) != LOWER(?) AND LOWER(role) NOT IN (LOWER(?), LOWER(?)))",
+			params:  []any{"a@x.com", "Admin", "Owner"},
+		},
+		{
+			name:    "driver lowercasing",
+			input:   "name IN ('Zoë', 'Åsa')",
+			driver:  "clickhouse",
+			options: []where.BuildOption{where.WithCaseInsensitiveEquality()},
+			want:    "lowerUTF8(name) IN (lowerUTF8(?), lowerUTF8(?))",
+			params:  []any{"Zoë", "Åsa"},
+		},
+		{
+			name:    "listed fields only",
+			input:   "Email = 'A@x.com' AND name = 'Ann'",
+			driver:  "postgres",
+			options: []where.BuildOption{where.WithCaseInsensitiveEquality("email")},
+			want:    "(LOWER(Email) = LOWER($1) AND name = $2)",
+			params:  []any{"A@x.com", "Ann"},
+		},
+		{
+			name:    "other conditions unchanged",
+			input:   "age = 30 AND name LIKE 'A%' AND code > 'B' AND id IN ('a', 1) AND 'x' = tag AND a = b",
+			driver:  "postgres",
+			options: []where.BuildOption{where.WithCaseInsensitiveEquality()},
+			want:    "(age = $1 AND name LIKE $2 AND code > $3 AND id IN ($4, $5) AND $6 = tag AND a = b)",
+			params:  []any{float64(30), "A%", "B", "a", float64(1), "x"},
+		},
+		{
+			name:   "variables",
+			input:  "email = :email AND tag IN (:tags) AND age = :age",
+			driver: "postgres",
+			options: []where.BuildOption{
+				where.WithCaseInsensitiveEquality(),
+				where.WithVariables(map[string]any{"email": "A@x.com", "tags": []string{"X", "Y"}, "age": 30}),
+			},
+			want:   "(LOWER(email) = LOWER($1) AND LOWER(tag) IN (LOWER($2), LOWER($3)) AND age = $4)",
+			params: []any{"A@x.com", "X", "Y", 30},
+		},
+		{
+			name:   "typed fields unchanged",
+			input:  "id = 'A0EEBC99-9C0B-4EF8-BB6D-6BB9BD380A11'",
+			driver: "postgres",
+			options: []where.BuildOption{
+				where.WithCaseInsensitiveEquality(),
+				where.WithFieldTypes(map[string]where.FieldType{"id": where.FieldTypeUUID}),
+			},
+			want:   "id = $1",
+			params: []any{"a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+		},
+		{
+			name:    "not bound as an array",
+			input:   "status IN ('A', 'B')",
+			driver:  "postgres",
+			options: []where.BuildOption{where.WithCaseInsensitiveEquality(), where.WithArrayBinding()},
+			want:    "LOWER(status) IN (LOWER($1), LOWER($2))",
+			params:  []any{"A", "B"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			sql, params, err := filter.ToSQL(tt.driver, tt.options...)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, sql)
+			require.Equal(t, tt.params, params)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package where
+
+import "strings"
+
+// checkCast validates the form of CAST(value AS type) expressions.
+func checkCast(fn *FunctionCall) error {
+	if !fn.isCast() {
+		return nil
+	}
+	if !strings.EqualFold(fn.Name, "CAST") {
+		return newMessage(MsgCastAs, "function", fn.Name)
+	}
+	if len(fn.Args) != 1 {
+		return newMessage(MsgCastArgs)
+	}
+	return nil
+}
+
+// buildCast renders a CAST expression, translating the type name for drivers implementing TypeMapper.
+func (b *SQLBuilder) buildCast(fn *FunctionCall) (string, error) {
+	if err := checkCast(fn); err != nil {
+		return "", err
+	}
+
+	value, err := b.buildValue(fn.Args[0])
+	if err != nil {
+		return "", err
+	}
+
+	typ := fn.CastAs.String()
+	if mapper, ok := DriverAs[TypeMapper](b.driver); ok {
+		name := strings.ToUpper(strings.Join(fn.CastAs.Name, " "))
+		if mapped, ok := mapper.MapType(name, fn.CastAs.Params); ok {
+			typ = mapped
+		}
+	}
+
+	return "CAST(" + value + " AS " + typ + ")", nil
+}
@@ -0,0 +1,109 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCast(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{
+			name:  "integer",
+			input: "CAST(age AS INTEGER) > 18",
+			want: map[string]string{
+				"postgres":   "CAST(age AS INTEGER) > $1",
+				"mysql":      "CAST(age AS SIGNED) > ?",
+				"clickhouse": "CAST(age AS Int32) > ?",
+			},
+		},
+		{
+			name:  "parameters",
+			input: "cast(price as decimal(10, 2)) >= 9.99",
+			want: map[string]string{
+				"postgres":   "CAST(price AS decimal(10, 2)) >= $1",
+				"mysql":      "CAST(price AS decimal(10, 2)) >= ?",
+				"clickhouse": "CAST(price AS Decimal(10, 2)) >= ?",
+			},
+		},
+		{
+			name:  "multi-word type",
+			input: "CAST(score AS DOUBLE PRECISION) < 0.5",
+			want: map[string]string{
+				"postgres":   "CAST(score AS DOUBLE PRECISION) < $1",
+				"mysql":      "CAST(score AS DOUBLE PRECISION) < ?",
+				"clickhouse": "CAST(score AS Float64) < ?",
+			},
+		},
+		{
+			name:  "string types",
+			input: "CAST(code AS VARCHAR(10)) = 'x' AND CAST(ts AS TIMESTAMP) > NOW()",
+			want: map[string]string{
+				"postgres":   "(CAST(code AS VARCHAR(10)) = $1 AND CAST(ts AS TIMESTAMP) > NOW())",
+				"mysql":      "(CAST(code AS CHAR(10)) = ? AND CAST(ts AS DATETIME) > NOW())",
+				"clickhouse": "(CAST(code AS String) = ? AND CAST(ts AS DateTime) > NOW())",
+			},
+		},
+		{
+			name:  "nested value",
+			input: "CAST(TRIM(amount) AS BIGINT) IN (1, 2)",
+			want: map[string]string{
+				"postgres":   "CAST(TRIM(amount) AS BIGINT) IN ($1, $2)",
+				"mysql":      "CAST(TRIM(amount) AS SIGNED) IN (?, ?)",
+				"clickhouse": "CAST(TRIM(amount) AS Int64) IN (?, ?)",
+			},
+		},
+		{
+			name:  "function form unchanged",
+			input: "CAST(age, 'INTEGER') > 18",
+			want: map[string]string{
+				"postgres": "CAST(age, $1) > $2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		filter, err := where.Parse(tt.input)
+		require.NoError(t, err)
+
+		for driver, want := range tt.want {
+			t.Run(tt.name+"/"+driver, func(t *testing.T) {
+				sql, _, err := filter.ToSQL(driver)
+				require.NoError(t, err)
+				require.Equal(t, want, sql)
+			})
+		}
+	}
+}
+
+func TestCastErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{input: "LOWER(name AS TEXT) = 'x'", wantErr: "filter validation failed: AS is only valid in CAST expressions, not LOWER"},
+		{input: "CAST(a, b AS INTEGER) = 1", wantErr: "filter validation failed: CAST requires exactly one value"},
+		{input: "CAST(a AS) = 1", wantErr: "failed to parse filter expression"},
+		{input: "CAST(a AS INT; DROP TABLE x) = 1", wantErr: "failed to parse filter expression"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := where.Parse(tt.input)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCastFormatAndExplain(t *testing.T) {
+	filter, err := where.Parse("cast(age as int) > 18")
+	require.NoError(t, err)
+
+	require.Equal(t, "cast(age AS int) > 18", filter.String())
+	require.Equal(t, "PREDICATE >\n  FUNCTION cast AS int\n    FIELD age\n  LITERAL 18 (number)", filter.Explain().String())
+}
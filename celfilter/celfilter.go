@@ -0,0 +1,520 @@
+// Package celfilter converts boolean expressions written in a subset of Google's Common Expression
+// Language (CEL) into where filters, so that policies written in CEL can be pushed down to the
+// database as WHERE clauses instead of filtering rows in memory.
+//
+// The supported subset is the one with a direct SQL equivalent: &&, ||, !, the comparison operators,
+// in with a list, has(), and the startsWith, endsWith, and contains string methods. Comparisons
+// with null become IS NULL and IS NOT NULL conditions.
+package celfilter
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/where"
+)
+
+var (
+	// comparisons maps CEL comparison operators to SQL operators.
+	comparisons = map[string]string{
+		operators.Equals:        "=",
+		operators.NotEquals:     "!=",
+		operators.Less:          "<",
+		operators.LessEquals:    "<=",
+		operators.Greater:       ">",
+		operators.GreaterEquals: ">=",
+	}
+
+	// patterns maps CEL string methods to the LIKE pattern matching them, with the argument
+	// inserted between the prefix and suffix.
+	patterns = map[string][2]string{
+		"startsWith": {"", "%"},
+		"endsWith":   {"%", ""},
+		"contains":   {"%", "%"},
+	}
+
+	// likeEscaper escapes the LIKE wildcards in a string matched literally.
+	likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+)
+
+type (
+	// Option configures the conversion of a CEL expression.
+	Option func(*converter)
+
+	// converter converts CEL expressions into the filter AST.
+	converter struct {
+		root     string
+		bindings map[string]any
+	}
+)
+
+// WithRoot sets the variable holding the row being filtered, e.g. "resource", so that
+// resource.owner is written as the field owner. Without a root, every variable is a field. With a
+// root, other variables must be bound with WithBindings.
+func WithRoot(name string) Option {
+	return func(c *converter) {
+		c.root = name
+	}
+}
+
+// WithBindings sets the values of variables and attributes known when the query is built, such as
+// those of the request, keyed by their path, e.g. "request.auth.user_id". They are bound as
+// parameters. Slices can be used on the right of in.
+func WithBindings(bindings map[string]any) Option {
+	return func(c *converter) {
+		for path, value := range bindings {
+			c.bindings[path] = value
+		}
+	}
+}
+
+// Parse parses a CEL expression and converts it into a filter. Variables do not need to be
+// declared, since the expression is not type-checked.
+//
+// Example:
+//
+//	filter, err := celfilter.Parse(`resource.owner == request.user && resource.status in ["open", "pending"]`,
+//		celfilter.WithRoot("resource"),
+//		celfilter.WithBindings(map[string]any{"request.user": userID}),
+//	)
+//	sql, params, _ := filter.ToSQL("postgres")
+//	// (owner = $1 AND status IN ($2, $3)) with params [<userID> open pending]
+func Parse(expr string, opts ...Option) (*where.Filter, error) {
+	env, err := cel.NewEnv()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CEL environment")
+	}
+
+	parsed, iss := env.Parse(expr)
+	if iss.Err() != nil {
+		return nil, errors.Wrap(iss.Err(), "failed to parse CEL expression")
+	}
+	return Convert(parsed, opts...)
+}
+
+// Convert converts a parsed or checked CEL AST into a filter. It returns an error for expressions
+// outside the supported subset, such as arithmetic, macros other than has, and function calls.
+func Convert(a *cel.Ast, opts ...Option) (*where.Filter, error) {
+	c := &converter{bindings: make(map[string]any)}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	expr, err := c.expression(a.NativeRep().Expr())
+	if err != nil {
+		return nil, err
+	}
+	return &where.Filter{Expression: expr}, nil
+}
+
+// expression converts e, flattening || into the terms of the expression.
+func (c *converter) expression(e ast.Expr) (*where.Expression, error) {
+	expr := &where.Expression{}
+	for _, operand := range flatten(e, operators.LogicalOr) {
+		term, err := c.term(operand)
+		if err != nil {
+			return nil, err
+		}
+		expr.Or = append(expr.Or, term)
+	}
+	return expr, nil
+}
+
+// term converts e, flattening && into the factors of the term.
+func (c *converter) term(e ast.Expr) (*where.Term, error) {
+	term := &where.Term{}
+	for _, operand := range flatten(e, operators.LogicalAnd) {
+		factor, err := c.factor(operand)
+		if err != nil {
+			return nil, err
+		}
+		term.And = append(term.And, factor)
+	}
+	return term, nil
+}
+
+// factor converts a negation, a group, or a condition.
+func (c *converter) factor(e ast.Expr) (*where.Factor, error) {
+	switch call(e) {
+	case operators.LogicalNot:
+		inner, err := c.factor(e.AsCall().Args()[0])
+		if err != nil {
+			return nil, err
+		}
+		if inner.Not {
+			return &where.Factor{Not: true, SubExpr: group(inner)}, nil
+		}
+		inner.Not = true
+		return inner, nil
+	case operators.LogicalAnd, operators.LogicalOr:
+		expr, err := c.expression(e)
+		if err != nil {
+			return nil, err
+		}
+		return &where.Factor{SubExpr: expr}, nil
+	}
+
+	pred, err := c.predicate(e)
+	if err != nil {
+		return nil, err
+	}
+	return &where.Factor{Predicate: pred}, nil
+}
+
+// predicate converts a condition.
+func (c *converter) predicate(e ast.Expr) (*where.Predicate, error) {
+	if e.Kind() == ast.SelectKind && e.AsSelect().IsTestOnly() {
+		sel := e.AsSelect()
+		left, err := c.value(sel.Operand())
+		if err != nil {
+			return nil, err
+		}
+		if left.Field == nil {
+			return nil, errors.New("has() requires a field")
+		}
+		left.Field.Parts = append(left.Field.Parts, sel.FieldName())
+		return &where.Predicate{Left: left, Operation: isNull(true)}, nil
+	}
+
+	if e.Kind() == ast.IdentKind || e.Kind() == ast.SelectKind {
+		left, err := c.value(e)
+		if err != nil {
+			return nil, err
+		}
+		return compare(left, "=", boolean(true)), nil
+	}
+
+	if e.Kind() != ast.CallKind {
+		return nil, unsupported(e)
+	}
+
+	fn := e.AsCall()
+	name := fn.FunctionName()
+	if op, ok := comparisons[name]; ok {
+		return c.comparison(op, fn.Args()[0], fn.Args()[1])
+	}
+	if name == operators.In {
+		return c.in(fn.Args()[0], fn.Args()[1])
+	}
+	if affixes, ok := patterns[name]; ok && fn.IsMemberFunction() && len(fn.Args()) == 1 {
+		return c.pattern(name, affixes, fn.Target(), fn.Args()[0])
+	}
+	return nil, unsupported(e)
+}
+
+// comparison converts a comparison, turning comparisons with null into IS NULL conditions.
+func (c *converter) comparison(op string, l, r ast.Expr) (*where.Predicate, error) {
+	left, err := c.value(l)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.value(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if op == "=" || op == "!=" {
+		if right.Literal != nil && right.Literal.Null {
+			return &where.Predicate{Left: left, Operation: isNull(op == "!=")}, nil
+		}
+		if left.Literal != nil && left.Literal.Null {
+			return &where.Predicate{Left: right, Operation: isNull(op == "!=")}, nil
+		}
+	}
+	return compare(left, op, right), nil
+}
+
+// in converts a membership test in a list literal or a bound slice.
+func (c *converter) in(l, r ast.Expr) (*where.Predicate, error) {
+	left, err := c.value(l)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []*where.Value
+	if r.Kind() == ast.ListKind {
+		for _, elem := range r.AsList().Elements() {
+			val, err := c.value(elem)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, val)
+		}
+	} else if bound, ok := c.binding(r); ok {
+		if values, err = listLiteral(bound); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, errors.New("in requires a list")
+	}
+
+	return &where.Predicate{Left: left, Operation: &where.Operation{In: &where.InOp{In: "IN", Values: values}}}, nil
+}
+
+// pattern converts startsWith, endsWith, or contains into a LIKE condition. The argument is matched
+// literally, so its wildcards are escaped.
+func (c *converter) pattern(name string, affixes [2]string, target, arg ast.Expr) (*where.Predicate, error) {
+	left, err := c.value(target)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.value(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	if right.Literal == nil || right.Literal.String == nil {
+		return nil, errors.Errorf("%s requires a string", name)
+	}
+	search, _ := right.Literal.Value().(string)
+
+	pattern := quote(affixes[0] + likeEscaper.Replace(search) + affixes[1])
+	return &where.Predicate{Left: left, Operation: &where.Operation{Like: &where.LikeOp{
+		Type:    where.LikeType{Operator: "LIKE"},
+		Pattern: &where.Value{Literal: &where.LiteralValue{String: pattern}},
+	}}}, nil
+}
+
+// value converts a literal, a bound variable, or a field.
+func (c *converter) value(e ast.Expr) (*where.Value, error) {
+	if e.Kind() == ast.LiteralKind {
+		return constant(e.AsLiteral())
+	}
+
+	if bound, ok := c.binding(e); ok {
+		lit, err := literal(bound)
+		if err != nil {
+			return nil, err
+		}
+		return &where.Value{Literal: lit}, nil
+	}
+
+	parts, ok := path(e)
+	if !ok {
+		return nil, unsupported(e)
+	}
+	if c.root != "" {
+		if parts[0] != c.root {
+			return nil, errors.Errorf("unknown variable %s", parts[0])
+		}
+		if parts = parts[1:]; len(parts) == 0 {
+			return nil, errors.Errorf("%s must be compared by field", c.root)
+		}
+	}
+	return &where.Value{Field: &where.FieldRef{Parts: parts}}, nil
+}
+
+// binding returns the bound value of a variable or attribute.
+func (c *converter) binding(e ast.Expr) (any, bool) {
+	parts, ok := path(e)
+	if !ok {
+		return nil, false
+	}
+	value, ok := c.bindings[strings.Join(parts, ".")]
+	return value, ok
+}
+
+// path returns the names of an identifier or a chain of field selections, e.g. a.b.c.
+func path(e ast.Expr) ([]string, bool) {
+	switch e.Kind() {
+	case ast.IdentKind:
+		return []string{e.AsIdent()}, true
+	case ast.SelectKind:
+		sel := e.AsSelect()
+		if sel.IsTestOnly() {
+			return nil, false
+		}
+		parts, ok := path(sel.Operand())
+		return append(parts, sel.FieldName()), ok
+	}
+	return nil, false
+}
+
+// flatten returns the operands of nested calls to the binary operator, e.g. a, b, and c for
+// a && (b && c).
+func flatten(e ast.Expr, op string) []ast.Expr {
+	if call(e) != op {
+		return []ast.Expr{e}
+	}
+
+	var operands []ast.Expr
+	for _, arg := range e.AsCall().Args() {
+		operands = append(operands, flatten(arg, op)...)
+	}
+	return operands
+}
+
+// call returns the function name of a call expression, or "" for other expressions.
+func call(e ast.Expr) string {
+	if e.Kind() != ast.CallKind {
+		return ""
+	}
+	return e.AsCall().FunctionName()
+}
+
+// unsupported returns the error for an expression outside the supported subset.
+func unsupported(e ast.Expr) error {
+	switch e.Kind() {
+	case ast.CallKind:
+		name := e.AsCall().FunctionName()
+		if symbol, ok := operators.FindReverse(name); ok && symbol != "" {
+			name = symbol
+		}
+		return errors.Errorf("unsupported CEL operator or function %s", name)
+	case ast.ComprehensionKind:
+		return errors.New("unsupported CEL comprehension")
+	case ast.LiteralKind:
+		return errors.New("unsupported CEL literal condition")
+	default:
+		return errors.New("unsupported CEL expression")
+	}
+}
+
+// constant converts a CEL literal.
+func constant(val ref.Val) (*where.Value, error) {
+	var value any
+	switch v := val.(type) {
+	case types.String:
+		value = string(v)
+	case types.Int:
+		value = int64(v)
+	case types.Uint:
+		value = uint64(v)
+	case types.Double:
+		value = float64(v)
+	case types.Bool:
+		value = bool(v)
+	case types.Bytes:
+		value = []byte(v)
+	case types.Null:
+		value = nil
+	default:
+		return nil, errors.Errorf("unsupported CEL literal of type %s", val.Type())
+	}
+
+	lit, err := literal(value)
+	if err != nil {
+		return nil, err
+	}
+	return &where.Value{Literal: lit}, nil
+}
+
+// literal converts a Go value to a literal.
+func literal(value any) (*where.LiteralValue, error) {
+	switch v := value.(type) {
+	case nil:
+		return &where.LiteralValue{Null: true}, nil
+	case string:
+		return &where.LiteralValue{String: quote(v)}, nil
+	case time.Time:
+		return &where.LiteralValue{String: quote(v.Format(time.RFC3339Nano))}, nil
+	case []byte:
+		digits := "0x" + hex.EncodeToString(v)
+		return &where.LiteralValue{Hex: &digits}, nil
+	case bool:
+		return &where.LiteralValue{Boolean: &where.BooleanLit{True: v, False: !v}}, nil
+	case float32:
+		return number(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case float64:
+		return number(strconv.FormatFloat(v, 'g', -1, 64))
+	case int:
+		return number(strconv.FormatInt(int64(v), 10))
+	case int8:
+		return number(strconv.FormatInt(int64(v), 10))
+	case int16:
+		return number(strconv.FormatInt(int64(v), 10))
+	case int32:
+		return number(strconv.FormatInt(int64(v), 10))
+	case int64:
+		return number(strconv.FormatInt(v, 10))
+	case uint:
+		return number(strconv.FormatUint(uint64(v), 10))
+	case uint8:
+		return number(strconv.FormatUint(uint64(v), 10))
+	case uint16:
+		return number(strconv.FormatUint(uint64(v), 10))
+	case uint32:
+		return number(strconv.FormatUint(uint64(v), 10))
+	case uint64:
+		return number(strconv.FormatUint(v, 10))
+	default:
+		return nil, errors.Errorf("cannot bind value of type %T", value)
+	}
+}
+
+// listLiteral converts the elements of a bound slice to literals.
+func listLiteral(value any) ([]*where.Value, error) {
+	var items []any
+	switch v := value.(type) {
+	case []any:
+		items = v
+	case []string:
+		for _, s := range v {
+			items = append(items, s)
+		}
+	case []int:
+		for _, n := range v {
+			items = append(items, n)
+		}
+	case []int64:
+		for _, n := range v {
+			items = append(items, n)
+		}
+	default:
+		return nil, errors.Errorf("in requires a list, got %T", value)
+	}
+	if len(items) == 0 {
+		return nil, errors.New("in requires at least one value")
+	}
+
+	values := make([]*where.Value, len(items))
+	for i, item := range items {
+		lit, err := literal(item)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = &where.Value{Literal: lit}
+	}
+	return values, nil
+}
+
+func number(numeral string) (*where.LiteralValue, error) {
+	n, err := strconv.ParseFloat(numeral, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid number %s", numeral)
+	}
+	return &where.LiteralValue{Numeral: &numeral, Number: &n}, nil
+}
+
+func compare(left *where.Value, op string, right *where.Value) *where.Predicate {
+	return &where.Predicate{Left: left, Operation: &where.Operation{Compare: &where.CompareOp{
+		Operator: where.CompareOperator{Type: op},
+		Right:    right,
+	}}}
+}
+
+func isNull(not bool) *where.Operation {
+	return &where.Operation{IsNull: &where.IsNullOp{Is: "IS", Not: not, Null: "NULL"}}
+}
+
+func boolean(value bool) *where.Value {
+	return &where.Value{Literal: &where.LiteralValue{Boolean: &where.BooleanLit{True: value, False: !value}}}
+}
+
+func group(factor *where.Factor) *where.Expression {
+	return &where.Expression{Or: []*where.Term{{And: []*where.Factor{factor}}}}
+}
+
+// quote returns s as a single-quoted literal with backslash escapes.
+func quote(s string) *string {
+	quoted := "'" + strings.NewReplacer(`\`, `\\`, `'`, `''`).Replace(s) + "'"
+	return &quoted
+}
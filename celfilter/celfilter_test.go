@@ -0,0 +1,115 @@
+package celfilter_test
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/pseudomuto/where"
+	"github.com/pseudomuto/where/celfilter"
+	_ "github.com/pseudomuto/where/drivers/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		opts []celfilter.Option
+		want string
+	}{
+		{name: "comparison", expr: `age >= 18`, want: "age >= 18"},
+		{name: "and or", expr: `age > 18 && status == "active" || admin == true`, want: "age > 18 AND status = 'active' OR admin = TRUE"},
+		{name: "grouping", expr: `(a == 1 || b == 2) && c != 3`, want: "(a = 1 OR b = 2) AND c != 3"},
+		{name: "not", expr: `!(a == 1 || b == 2)`, want: "NOT (a = 1 OR b = 2)"},
+		{name: "double not", expr: `!(!(a == 1))`, want: "NOT (NOT a = 1)"},
+		{name: "null", expr: `deleted_at == null && null != owner`, want: "deleted_at IS NULL AND owner IS NOT NULL"},
+		{name: "in", expr: `status in ["open", "pending"]`, want: "status IN ('open', 'pending')"},
+		{name: "starts with", expr: `name.startsWith("j_")`, want: `name LIKE 'j\\_%'`},
+		{name: "ends with", expr: `email.endsWith("@example.com")`, want: "email LIKE '%@example.com'"},
+		{name: "contains", expr: `title.contains("50%")`, want: `title LIKE '%50\\%%'`},
+		{name: "has", expr: `has(user.email)`, want: "user.email IS NOT NULL"},
+		{name: "boolean field", expr: `active && !user.banned`, want: "active = TRUE AND NOT user.banned = TRUE"},
+		{name: "literals", expr: `score > 1.5 && n < 10u && name == 'it\'s' && data == b"\xca\xfe"`, want: "score > 1.5 AND n < 10 AND name = 'it''s' AND data = 0xcafe"},
+		{
+			name: "root and bindings",
+			expr: `resource.owner == request.user && resource.status in request.statuses`,
+			opts: []celfilter.Option{
+				celfilter.WithRoot("resource"),
+				celfilter.WithBindings(map[string]any{"request.user": "u1", "request.statuses": []string{"open", "closed"}}),
+			},
+			want: "owner = 'u1' AND status IN ('open', 'closed')",
+		},
+		{
+			name: "bound pattern",
+			expr: `name.startsWith(prefix)`,
+			opts: []celfilter.Option{celfilter.WithBindings(map[string]any{"prefix": "a%"})},
+			want: `name LIKE 'a\\%%'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := celfilter.Parse(tt.expr, tt.opts...)
+			require.NoError(t, err)
+
+			want, err := where.Parse(tt.want)
+			require.NoError(t, err)
+
+			wantSQL, wantParams, err := want.ToSQL("postgres")
+			require.NoError(t, err)
+			gotSQL, gotParams, err := filter.ToSQL("postgres")
+			require.NoError(t, err)
+
+			require.Equal(t, wantSQL, gotSQL)
+			require.Equal(t, wantParams, gotParams)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		opts []celfilter.Option
+		err  string
+	}{
+		{name: "syntax", expr: `age >`, err: "failed to parse CEL expression"},
+		{name: "arithmetic", expr: `age + 1 > 18`, err: "unsupported CEL operator or function +"},
+		{name: "function", expr: `size(name) > 3`, err: "unsupported CEL operator or function size"},
+		{name: "comprehension", expr: `tags.exists(t, t == "x")`, err: "unsupported CEL comprehension"},
+		{name: "literal condition", expr: `true`, err: "unsupported CEL literal condition"},
+		{name: "in without list", expr: `status in other`, err: "in requires a list"},
+		{name: "pattern without string", expr: `name.startsWith(1)`, err: "startsWith requires a string"},
+		{name: "unknown variable", expr: `request.user == "x"`, opts: []celfilter.Option{celfilter.WithRoot("resource")}, err: "unknown variable request"},
+		{name: "bare root", expr: `resource == "x"`, opts: []celfilter.Option{celfilter.WithRoot("resource")}, err: "resource must be compared by field"},
+		{
+			name: "unsupported binding",
+			expr: `owner == user`,
+			opts: []celfilter.Option{celfilter.WithBindings(map[string]any{"user": struct{}{}})},
+			err:  "cannot bind value of type struct {}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := celfilter.Parse(tt.expr, tt.opts...)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestConvertCheckedAST(t *testing.T) {
+	env, err := cel.NewEnv(cel.Variable("age", cel.IntType), cel.Variable("name", cel.StringType))
+	require.NoError(t, err)
+
+	ast, iss := env.Compile(`age >= 21 && name.endsWith("son")`)
+	require.NoError(t, iss.Err())
+
+	filter, err := celfilter.Convert(ast)
+	require.NoError(t, err)
+
+	sql, params, err := filter.ToSQL("postgres")
+	require.NoError(t, err)
+	require.Equal(t, "(age >= $1 AND name LIKE $2)", sql)
+	require.Equal(t, []any{float64(21), "%son"}, params)
+}
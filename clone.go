@@ -0,0 +1,277 @@
+package where
+
+import "slices"
+
+// Clone returns a deep copy of the filter that shares no AST nodes with f, so the copy can be
+// modified without affecting f or any goroutine using it. Values bound by In, NotIn, and Exists are
+// copied as is, so slices or pointers among them are shared. Clone is the same as DeepCopy.
+func (f *Filter) Clone() *Filter {
+	return f.DeepCopy()
+}
+
+// DeepCopy returns a copy of the filter that shares no AST nodes with f. The copy is never frozen,
+// so it is how a rewrite starts from a frozen filter. See Clone.
+func (f *Filter) DeepCopy() *Filter {
+	if f == nil {
+		return nil
+	}
+	return &Filter{Pos: f.Pos, Expression: f.Expression.DeepCopy()}
+}
+
+// DeepCopy returns a copy of the expression that shares no AST nodes with e.
+func (e *Expression) DeepCopy() *Expression {
+	if e == nil {
+		return nil
+	}
+
+	c := &Expression{Or: make([]*Term, len(e.Or))}
+	for i, term := range e.Or {
+		c.Or[i] = term.DeepCopy()
+	}
+	return c
+}
+
+// DeepCopy returns a copy of the term that shares no AST nodes with t.
+func (t *Term) DeepCopy() *Term {
+	if t == nil {
+		return nil
+	}
+
+	c := &Term{And: make([]*Factor, len(t.And))}
+	for i, factor := range t.And {
+		c.And[i] = factor.DeepCopy()
+	}
+	return c
+}
+
+// DeepCopy returns a copy of the factor that shares no AST nodes with f.
+func (f *Factor) DeepCopy() *Factor {
+	if f == nil {
+		return nil
+	}
+
+	c := &Factor{
+		Not:       f.Not,
+		SubExpr:   f.SubExpr.DeepCopy(),
+		Predicate: f.Predicate.DeepCopy(),
+		Exists:    f.Exists.DeepCopy(),
+	}
+	if f.Macro != nil {
+		macro := *f.Macro
+		c.Macro = &macro
+	}
+	return c
+}
+
+// DeepCopy returns a copy of the EXISTS condition with its own Args slice. The args themselves are
+// copied as is.
+func (e *ExistsOp) DeepCopy() *ExistsOp {
+	if e == nil {
+		return nil
+	}
+	return &ExistsOp{Query: e.Query, Args: slices.Clone(e.Args)}
+}
+
+// DeepCopy returns a copy of the predicate that shares no AST nodes with p.
+func (p *Predicate) DeepCopy() *Predicate {
+	if p == nil {
+		return nil
+	}
+	return &Predicate{Pos: p.Pos, Left: p.Left.DeepCopy(), Operation: p.Operation.DeepCopy()}
+}
+
+// DeepCopy returns a copy of the operation that shares no AST nodes with op.
+func (op *Operation) DeepCopy() *Operation {
+	if op == nil {
+		return nil
+	}
+
+	return &Operation{
+		Between: op.Between.DeepCopy(),
+		In:      op.In.DeepCopy(),
+		Like:    op.Like.DeepCopy(),
+		Match:   op.Match.DeepCopy(),
+		Compare: op.Compare.DeepCopy(),
+		IsNull:  op.IsNull.DeepCopy(),
+	}
+}
+
+// DeepCopy returns a copy of the comparison that shares no AST nodes with op.
+func (op *CompareOp) DeepCopy() *CompareOp {
+	if op == nil {
+		return nil
+	}
+
+	c := *op
+	c.Right = op.Right.DeepCopy()
+	return &c
+}
+
+// DeepCopy returns a copy of the LIKE operation that shares no AST nodes with op.
+func (op *LikeOp) DeepCopy() *LikeOp {
+	if op == nil {
+		return nil
+	}
+
+	c := *op
+	c.Pattern = op.Pattern.DeepCopy()
+	return &c
+}
+
+// DeepCopy returns a copy of the MATCHES operation that shares no AST nodes with op.
+func (op *MatchOp) DeepCopy() *MatchOp {
+	if op == nil {
+		return nil
+	}
+
+	c := *op
+	c.Query = op.Query.DeepCopy()
+	return &c
+}
+
+// DeepCopy returns a copy of the BETWEEN operation that shares no AST nodes with op.
+func (op *BetweenOp) DeepCopy() *BetweenOp {
+	if op == nil {
+		return nil
+	}
+
+	c := *op
+	c.Lower = op.Lower.DeepCopy()
+	c.Upper = op.Upper.DeepCopy()
+	return &c
+}
+
+// DeepCopy returns a copy of the IN operation that shares no AST nodes with op.
+func (op *InOp) DeepCopy() *InOp {
+	if op == nil {
+		return nil
+	}
+
+	c := *op
+	c.Values = cloneValues(op.Values)
+	return &c
+}
+
+// DeepCopy returns a copy of the IS NULL operation.
+func (op *IsNullOp) DeepCopy() *IsNullOp {
+	if op == nil {
+		return nil
+	}
+
+	c := *op
+	return &c
+}
+
+// DeepCopy returns a copy of the value that shares no AST nodes with v.
+func (v *Value) DeepCopy() *Value {
+	if v == nil {
+		return nil
+	}
+
+	return &Value{
+		Function: v.Function.DeepCopy(),
+		Field:    v.Field.DeepCopy(),
+		Literal:  v.Literal.DeepCopy(),
+		SubExpr:  v.SubExpr.DeepCopy(),
+		Bitwise:  v.Bitwise.DeepCopy(),
+	}
+}
+
+// DeepCopy returns a copy of the bitwise expression that shares no AST nodes with b.
+func (b *BitwiseExpr) DeepCopy() *BitwiseExpr {
+	if b == nil {
+		return nil
+	}
+
+	c := &BitwiseExpr{Left: b.Left.DeepCopy(), Ops: make([]*BitwiseOp, len(b.Ops))}
+	for i, op := range b.Ops {
+		c.Ops[i] = op.DeepCopy()
+	}
+	return c
+}
+
+// DeepCopy returns a copy of the bitwise operator and its right operand.
+func (op *BitwiseOp) DeepCopy() *BitwiseOp {
+	if op == nil {
+		return nil
+	}
+	return &BitwiseOp{Operator: op.Operator, Right: op.Right.DeepCopy()}
+}
+
+// DeepCopy returns a copy of the function call that shares no AST nodes with fn.
+func (fn *FunctionCall) DeepCopy() *FunctionCall {
+	if fn == nil {
+		return nil
+	}
+
+	return &FunctionCall{
+		Pos:    fn.Pos,
+		Name:   fn.Name,
+		Cond:   fn.Cond.DeepCopy(),
+		Star:   fn.Star,
+		Args:   cloneValues(fn.Args),
+		CastAs: fn.CastAs.DeepCopy(),
+	}
+}
+
+// DeepCopy returns a copy of the type name.
+func (t *SQLType) DeepCopy() *SQLType {
+	if t == nil {
+		return nil
+	}
+	return &SQLType{Name: slices.Clone(t.Name), Params: slices.Clone(t.Params)}
+}
+
+// DeepCopy returns a copy of the field reference.
+func (r *FieldRef) DeepCopy() *FieldRef {
+	if r == nil {
+		return nil
+	}
+	return &FieldRef{Pos: r.Pos, Parts: slices.Clone(r.Parts), column: r.column}
+}
+
+// DeepCopy returns a copy of the literal. A Go value bound by In or NotIn is copied as is.
+func (l *LiteralValue) DeepCopy() *LiteralValue {
+	if l == nil {
+		return nil
+	}
+
+	c := *l
+	if l.String != nil {
+		str := *l.String
+		c.String = &str
+	}
+	if l.Hex != nil {
+		hex := *l.Hex
+		c.Hex = &hex
+	}
+	if l.Numeral != nil {
+		numeral := *l.Numeral
+		c.Numeral = &numeral
+	}
+	if l.Number != nil {
+		num := *l.Number
+		c.Number = &num
+	}
+	if l.Boolean != nil {
+		boolean := *l.Boolean
+		c.Boolean = &boolean
+	}
+	if l.Variable != nil {
+		variable := *l.Variable
+		c.Variable = &variable
+	}
+	return &c
+}
+
+func cloneValues(vals []*Value) []*Value {
+	if vals == nil {
+		return nil
+	}
+
+	c := make([]*Value, len(vals))
+	for i, val := range vals {
+		c[i] = val.DeepCopy()
+	}
+	return c
+}
@@ -0,0 +1,118 @@
+package where_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pseudomuto/where"
+	_ "github.com/pseudomuto/where/drivers/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *where.Filter
+	}{
+		{
+			name: "parsed filter",
+			filter: mustParse(t, `(age BETWEEN 18 AND 65 OR verified = true) AND email NOT LIKE '%spam%' AND
+				status IN ('a', 'b') AND CAST(score AS DECIMAL(10, 2)) > 1.5 AND users.deleted_at IS NULL AND
+				data = 0xCAFE AND NOT (LOWER(name) = "x" OR country = NULL)`),
+		},
+		{
+			name:   "constructed filter",
+			filter: where.NotIn("id", 1, 2, 3),
+		},
+		{
+			name:   "exists",
+			filter: where.Exists("SELECT 1 FROM orders WHERE user_id = ?", 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clone := tt.filter.Clone()
+			require.Equal(t, tt.filter, clone)
+			require.NotSame(t, tt.filter.Expression, clone.Expression)
+
+			wantSQL, wantParams, err := tt.filter.ToSQL("postgres")
+			require.NoError(t, err)
+
+			sql, params, err := clone.ToSQL("postgres")
+			require.NoError(t, err)
+			require.Equal(t, wantSQL, sql)
+			require.Equal(t, wantParams, params)
+		})
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	filter := mustParse(t, "status = 'active' AND LOWER(name) IN ('a', 'b') AND age BETWEEN 1 AND 2")
+	want := filter.String()
+
+	clone := filter.Clone()
+	factors := clone.Expression.Or[0].And
+	factors[0].Not = true
+	factors[0].Predicate.Left.Field.Parts[0] = "state"
+	factors[1].Predicate.Left.Function.Args[0].Field.Parts[0] = "email"
+	factors[1].Predicate.Operation.In.Values = factors[1].Predicate.Operation.In.Values[:1]
+	*factors[2].Predicate.Operation.Between.Upper.Literal.Number = 3
+
+	require.Equal(t, want, filter.String())
+	require.Equal(t, "NOT state = 'active' AND LOWER(email) IN ('a') AND age BETWEEN 1 AND 3", clone.String())
+}
+
+func TestCloneNil(t *testing.T) {
+	var filter *where.Filter
+	require.Nil(t, filter.Clone())
+}
+
+func TestFilterConcurrentUse(t *testing.T) {
+	filter := mustParse(t, "age >= 18 AND age <= 65 AND status IN ('a', 'b', 'a') AND created_at > '2024-01-01'")
+	options := []where.BuildOption{
+		where.WithRangeMerge(),
+		where.WithParamDeduplication(),
+		where.WithValidator(where.NewValidator().AllowFields("age", "status", "created_at")),
+	}
+
+	wantSQL, wantParams, err := filter.ToSQL("postgres", options...)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				sql, params, err := filter.ToSQL("postgres", options...)
+				if err != nil || sql != wantSQL || len(params) != len(wantParams) {
+					t.Errorf("concurrent ToSQL = %q, %v, %v", sql, params, err)
+					return
+				}
+				_ = filter.String()
+				_ = filter.Lint()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDeepCopyNodes(t *testing.T) {
+	filter := mustParse(t, "flags & 4 = 4 AND CAST(score AS DECIMAL(10, 2)) NOT BETWEEN 1 AND 2")
+	pred := filter.Expression.Or[0].And[1].Predicate
+
+	c := pred.DeepCopy()
+	require.Equal(t, pred, c)
+	c.Left.Function.CastAs.Params[0] = "12"
+	c.Operation.Between.Not = false
+	require.Equal(t, "10", pred.Left.Function.CastAs.Params[0])
+	require.True(t, pred.Operation.Between.Not)
+
+	bitwise := filter.Expression.Or[0].And[0].Predicate.Left.Bitwise.DeepCopy()
+	bitwise.Ops[0].Operator = "|"
+	require.Equal(t, "flags & 4 = 4 AND CAST(score AS DECIMAL(10, 2)) NOT BETWEEN 1 AND 2", filter.String())
+
+	var value *where.Value
+	require.Nil(t, value.DeepCopy())
+}
@@ -0,0 +1,223 @@
+// Command where parses, validates, formats, and translates filter expressions from the command line.
+//
+// Usage:
+//
+//	where parse [--json] <filter>
+//	where sql --driver=<name> [--json] <filter>
+//	where fmt [--compact] [--lowercase] <filter>
+//	where lint [--driver=<name>] [--allow-fields=a,b] [--allow-functions=f,g] [--strict] <filter>
+//
+// When no filter argument is given (or it is "-"), the filter is read from stdin.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/where"
+	_ "github.com/pseudomuto/where/drivers/ansi"
+	_ "github.com/pseudomuto/where/drivers/clickhouse"
+	_ "github.com/pseudomuto/where/drivers/mysql"
+	_ "github.com/pseudomuto/where/drivers/postgres"
+	_ "github.com/pseudomuto/where/drivers/redshift"
+	_ "github.com/pseudomuto/where/drivers/spanner"
+	_ "github.com/pseudomuto/where/drivers/trino"
+)
+
+const usage = `Usage: where <command> [flags] [filter]
+
+Commands:
+  parse   Print the parsed filter as a tree (or JSON with --json)
+  sql     Translate the filter to SQL and parameters for a driver
+  fmt     Reformat the filter expression
+  lint    Validate the filter against allowlists and report suspicious constructs
+
+When no filter is given (or it is "-"), it is read from stdin.
+`
+
+type command func(args []string, stdin io.Reader, stdout io.Writer) error
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	commands := map[string]command{
+		"parse": parseCmd,
+		"sql":   sqlCmd,
+		"fmt":   fmtCmd,
+		"lint":  lintCmd,
+	}
+
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	if err := cmd(args[1:], stdin, stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 2
+		}
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+func parseCmd(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("parse", stdout)
+	asJSON := fs.Bool("json", false, "print the parsed filter as JSON")
+
+	filter, err := parseArgs(fs, args, stdin)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return writeJSON(stdout, filter.Explain())
+	}
+
+	fmt.Fprintln(stdout, filter.Explain())
+	return nil
+}
+
+func sqlCmd(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("sql", stdout)
+	driver := fs.String("driver", "postgres", "database driver ("+strings.Join(where.ListDrivers(), ", ")+")")
+	asJSON := fs.Bool("json", false, "print the SQL and parameters as JSON")
+
+	filter, err := parseArgs(fs, args, stdin)
+	if err != nil {
+		return err
+	}
+
+	sql, params, err := filter.ToSQL(*driver)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return writeJSON(stdout, map[string]any{"sql": sql, "params": params})
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode params")
+	}
+
+	fmt.Fprintf(stdout, "%s\n%s\n", sql, data)
+	return nil
+}
+
+func fmtCmd(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("fmt", stdout)
+	compact := fs.Bool("compact", false, "print the filter on a single line")
+	lowercase := fs.Bool("lowercase", false, "print keywords in lowercase")
+
+	filter, err := parseArgs(fs, args, stdin)
+	if err != nil {
+		return err
+	}
+
+	var opts []where.FormatOption
+	if *compact {
+		opts = append(opts, where.WithCompact())
+	}
+	if *lowercase {
+		opts = append(opts, where.WithLowercaseKeywords())
+	}
+
+	fmt.Fprintln(stdout, filter.Format(opts...))
+	return nil
+}
+
+func lintCmd(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("lint", stdout)
+	driver := fs.String("driver", "postgres", "database driver used to validate the filter")
+	fields := fs.String("allow-fields", "", "comma separated list of allowed fields (default: all)")
+	functions := fs.String("allow-functions", "", "comma separated list of allowed functions (default: all)")
+	strict := fs.Bool("strict", false, "fail when lint warnings are reported")
+
+	filter, err := parseArgs(fs, args, stdin)
+	if err != nil {
+		return err
+	}
+
+	validator := where.NewValidator()
+	if *fields == "" {
+		validator.AllowFieldPattern("*")
+	} else {
+		validator.AllowFields(splitList(*fields)...)
+	}
+	if *functions == "" {
+		validator.AllowFunctionPattern("*")
+	} else {
+		validator.AllowFunctions(splitList(*functions)...)
+	}
+
+	if _, _, err := filter.ToSQL(*driver, where.WithValidator(validator)); err != nil {
+		return err
+	}
+
+	warnings := filter.Lint()
+	for _, warning := range warnings {
+		fmt.Fprintf(stdout, "warning: %s\n", warning)
+	}
+
+	if len(warnings) == 0 {
+		fmt.Fprintln(stdout, "ok")
+	} else if *strict {
+		return errors.Errorf("%d lint warning(s) reported", len(warnings))
+	}
+	return nil
+}
+
+func newFlagSet(name string, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	return fs
+}
+
+// parseArgs parses the command flags and the filter expression from the remaining arguments or stdin.
+func parseArgs(fs *flag.FlagSet, args []string, stdin io.Reader) (*where.Filter, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	input := strings.Join(fs.Args(), " ")
+	if input == "" || input == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read filter from stdin")
+		}
+		input = strings.TrimSpace(string(data))
+	}
+
+	return where.Parse(input)
+}
+
+func splitList(s string) []string {
+	parts := strings.Split(s, ",")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	return parts
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
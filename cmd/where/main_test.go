@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "parse",
+			args:       []string{"parse", "age > 18"},
+			wantStdout: "PREDICATE >\n  FIELD age\n  LITERAL 18 (number)\n",
+		},
+		{
+			name:       "parse JSON",
+			args:       []string{"parse", "--json", "active = true"},
+			wantStdout: "{\n  \"type\": \"predicate\",\n  \"operator\": \"=\",\n  \"children\": [\n    {\n      \"type\": \"field\",\n      \"name\": \"active\"\n    },\n    {\n      \"type\": \"literal\",\n      \"value\": true,\n      \"valueType\": \"boolean\"\n    }\n  ]\n}\n",
+		},
+		{
+			name:       "sql",
+			args:       []string{"sql", "--driver=mysql", "age > 18 AND name = 'John'"},
+			wantStdout: "(age > ? AND name = ?)\n[18,\"John\"]\n",
+		},
+		{
+			name:       "sql JSON",
+			args:       []string{"sql", "--json", "age > 18"},
+			wantStdout: "{\n  \"params\": [\n    18\n  ],\n  \"sql\": \"age \\u003e $1\"\n}\n",
+		},
+		{
+			name:       "sql unknown driver",
+			args:       []string{"sql", "--driver=oracle", "age > 18"},
+			wantCode:   1,
+			wantStderr: "error: failed to get driver \"oracle\": driver \"oracle\" not registered\n",
+		},
+		{
+			name:       "fmt from stdin",
+			args:       []string{"fmt"},
+			stdin:      "age>18 and   name='x'\n",
+			wantStdout: "age > 18 AND name = 'x'\n",
+		},
+		{
+			name:       "fmt compact lowercase",
+			args:       []string{"fmt", "--compact", "--lowercase", "-"},
+			stdin:      "a = 1 AND (b = 1 OR (c = 1 AND d = 1))",
+			wantStdout: "a = 1 and (b = 1 or (c = 1 and d = 1))\n",
+		},
+		{
+			name:       "lint ok",
+			args:       []string{"lint", "--allow-fields=age,name", "--allow-functions=LOWER", "LOWER(name) = 'x' AND age > 1"},
+			wantStdout: "ok\n",
+		},
+		{
+			name:       "lint disallowed field",
+			args:       []string{"lint", "--allow-fields=age", "password = 'x'"},
+			wantCode:   1,
+			wantStderr: "error: field \"password\" is not allowed\n",
+		},
+		{
+			name:       "lint all allowed by default",
+			args:       []string{"lint", "TRIM(anything) = 'x'"},
+			wantStdout: "ok\n",
+		},
+		{
+			name:       "lint warnings",
+			args:       []string{"lint", "age > 18 OR 1 = 1"},
+			wantStdout: "warning: constant-comparison: comparison between literals is always true or always false (1 = 1)\n",
+		},
+		{
+			name:       "lint warnings strict",
+			args:       []string{"lint", "--strict", "age > 18 OR 1 = 1"},
+			wantCode:   1,
+			wantStderr: "error: 1 lint warning(s) reported\n",
+		},
+		{
+			name:     "parse error",
+			args:     []string{"parse", "age >"},
+			wantCode: 1,
+		},
+		{
+			name:     "no command",
+			wantCode: 2,
+		},
+		{
+			name:     "unknown command",
+			args:     []string{"explode"},
+			wantCode: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+
+			require.Equal(t, tt.wantCode, code, stderr.String())
+			if tt.wantStdout != "" {
+				require.Equal(t, tt.wantStdout, stdout.String())
+			}
+			if tt.wantStderr != "" {
+				require.Equal(t, tt.wantStderr, stderr.String())
+			}
+		})
+	}
+}
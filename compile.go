@@ -0,0 +1,912 @@
+package where
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Truth values of compiled conditions. Like SQL, a condition on a missing or null field is unknown
+// rather than false, so that NOT (status = 'x') does not match events without a status.
+const (
+	truthFalse truth = iota
+	truthTrue
+	truthUnknown
+)
+
+type (
+	// compileOptions holds configuration options for Compile.
+	compileOptions struct {
+		variables   map[string]any
+		timeLayouts []string
+	}
+
+	// CompileOption is a function type for configuring Compile.
+	CompileOption func(*compileOptions)
+
+	truth uint8
+
+	// condition evaluates a compiled condition against an event.
+	condition func(map[string]any) truth
+
+	// getter returns a compiled value for an event, or nil if it is missing or null.
+	getter func(map[string]any) any
+
+	// constant is a literal value with the coercions used to compare it with event values
+	// precomputed.
+	constant struct {
+		value  any
+		num    float64
+		isNum  bool
+		str    string
+		isStr  bool
+		time   time.Time
+		isTime bool
+	}
+)
+
+// WithCompileVariables returns a CompileOption that supplies the values of the :name variables in
+// the filter, like WithVariables does for ToSQL.
+func WithCompileVariables(vars map[string]any) CompileOption {
+	return func(o *compileOptions) {
+		for name, value := range vars {
+			o.variables[strings.TrimPrefix(name, ":")] = value
+		}
+	}
+}
+
+// WithCompileTimeLayouts returns a CompileOption that sets the layouts used to compare string
+// literals with time.Time values in events. DefaultTimeLayouts is used by default.
+func WithCompileTimeLayouts(layouts ...string) CompileOption {
+	return func(o *compileOptions) {
+		o.timeLayouts = layouts
+	}
+}
+
+// Compile compiles the filter into a function reporting whether an event, such as a decoded JSON
+// message, matches it. Field accessors, literal coercions, LIKE patterns, and IN sets are prepared
+// once, so the function is cheap enough to filter streams of millions of events per second, and it
+// may be called concurrently.
+//
+// Qualified fields such as user.id are looked up as a key of that name, or else in nested maps.
+// Numbers of any Go type and json.Number compare as numbers, numeric strings are coerced when
+// compared with numbers, and string literals in the layouts of DefaultTimeLayouts compare with
+// time.Time values as times. As in SQL, comparisons with missing or null fields are neither true nor
+// false, so neither status = 'x' nor NOT (status = 'x') matches an event without a status.
+//
+// The functions LOWER, UPPER, TRIM, LENGTH, ABS, and COALESCE are supported. Filters using other
+// functions, CAST, IF, MATCHES, or EXISTS return an error.
+//
+// Example:
+//
+//	filter, _ := where.Parse("level IN ('warn', 'error') AND service.name LIKE 'api-%' AND latency_ms > 250")
+//	match, _ := filter.Compile()
+//	for event := range events {
+//		if match(event) {
+//			// ...
+//		}
+//	}
+func (f *Filter) Compile(opts ...CompileOption) (func(map[string]any) bool, error) {
+	options := &compileOptions{variables: make(map[string]any), timeLayouts: DefaultTimeLayouts}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if f == nil || f.Expression == nil {
+		return nil, errors.New("empty filter")
+	}
+
+	if err := f.checkFrozen(); err != nil {
+		return nil, err
+	}
+
+	cond, err := options.expression(f.Expression)
+	if err != nil {
+		return nil, err
+	}
+	return func(event map[string]any) bool {
+		return cond(event) == truthTrue
+	}, nil
+}
+
+func (o *compileOptions) expression(expr *Expression) (condition, error) {
+	if expr == nil || len(expr.Or) == 0 {
+		return nil, errors.New("empty expression")
+	}
+
+	terms := make([]condition, len(expr.Or))
+	for i, term := range expr.Or {
+		cond, err := o.term(term)
+		if err != nil {
+			return nil, err
+		}
+		terms[i] = cond
+	}
+
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return func(event map[string]any) truth {
+		result := truthFalse
+		for _, term := range terms {
+			switch term(event) {
+			case truthTrue:
+				return truthTrue
+			case truthUnknown:
+				result = truthUnknown
+			}
+		}
+		return result
+	}, nil
+}
+
+func (o *compileOptions) term(term *Term) (condition, error) {
+	if term == nil || len(term.And) == 0 {
+		return nil, errors.New("empty term")
+	}
+
+	factors := make([]condition, len(term.And))
+	for i, factor := range term.And {
+		cond, err := o.factor(factor)
+		if err != nil {
+			return nil, err
+		}
+		factors[i] = cond
+	}
+
+	if len(factors) == 1 {
+		return factors[0], nil
+	}
+	return func(event map[string]any) truth {
+		result := truthTrue
+		for _, factor := range factors {
+			switch factor(event) {
+			case truthFalse:
+				return truthFalse
+			case truthUnknown:
+				result = truthUnknown
+			}
+		}
+		return result
+	}, nil
+}
+
+func (o *compileOptions) factor(factor *Factor) (condition, error) {
+	var (
+		cond condition
+		err  error
+	)
+	switch {
+	case factor == nil:
+		return nil, errors.New("empty factor")
+	case factor.Exists != nil:
+		return nil, errors.New("EXISTS conditions cannot be compiled")
+	case factor.SubExpr != nil:
+		cond, err = o.expression(factor.SubExpr)
+	case factor.Predicate != nil:
+		cond, err = o.predicate(factor.Predicate)
+	default:
+		return nil, errors.New("empty factor content")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return o.negate(factor.Not, cond), nil
+}
+
+func (o *compileOptions) predicate(pred *Predicate) (condition, error) {
+	if pred.Operation == nil {
+		return nil, errors.New("predicate missing operation")
+	}
+
+	left, err := o.value(pred.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	op := pred.Operation
+	switch {
+	case op.Compare != nil:
+		return o.compare(left, op.Compare)
+	case op.Like != nil:
+		return o.like(left, op.Like)
+	case op.Between != nil:
+		lower, err := o.compare(left, &CompareOp{Operator: CompareOperator{Type: "GreaterOrEqual"}, Right: op.Between.Lower})
+		if err != nil {
+			return nil, err
+		}
+		upper, err := o.compare(left, &CompareOp{Operator: CompareOperator{Type: "LessOrEqual"}, Right: op.Between.Upper})
+		if err != nil {
+			return nil, err
+		}
+		return o.negate(op.Between.Not, func(event map[string]any) truth {
+			switch l, u := lower(event), upper(event); {
+			case l == truthFalse || u == truthFalse:
+				return truthFalse
+			case l == truthUnknown || u == truthUnknown:
+				return truthUnknown
+			default:
+				return truthTrue
+			}
+		}), nil
+	case op.In != nil:
+		cond, err := o.in(left, op.In.Values)
+		if err != nil {
+			return nil, err
+		}
+		return o.negate(op.In.Not, cond), nil
+	case op.IsNull != nil:
+		not := op.IsNull.Not
+		return func(event map[string]any) truth {
+			if (left(event) == nil) != not {
+				return truthTrue
+			}
+			return truthFalse
+		}, nil
+	case op.Match != nil:
+		return nil, errors.New("MATCHES conditions cannot be compiled")
+	default:
+		return nil, errors.New("empty operation")
+	}
+}
+
+// negate returns the condition negated when not is true.
+func (o *compileOptions) negate(not bool, cond condition) condition {
+	if !not {
+		return cond
+	}
+	return func(event map[string]any) truth {
+		switch cond(event) {
+		case truthTrue:
+			return truthFalse
+		case truthFalse:
+			return truthTrue
+		default:
+			return truthUnknown
+		}
+	}
+}
+
+// compare compiles a comparison, specialized for the common case of a literal on the right. NULL-safe
+// equality is true for two nulls and false for one, and inequality is true for values that cannot be
+// compared, such as a string and a bool.
+func (o *compileOptions) compare(left getter, cmp *CompareOp) (condition, error) {
+	operator := cmp.Operator.String()
+	accept, ok := comparisonResults[operator]
+	if !ok {
+		return nil, errors.Errorf("operator %s cannot be compiled", operator)
+	}
+	nullSafe, unequal := operator == "<=>", operator == "!="
+
+	// result returns the truth of the comparison of two values, either of which may be null.
+	result := func(aNull, bNull bool, order int, ok bool) truth {
+		switch {
+		case nullSafe && (aNull || bNull):
+			return truthOf(aNull && bNull)
+		case aNull || bNull:
+			return truthUnknown
+		case !ok:
+			return truthOf(unequal)
+		default:
+			return truthOf(accept(order))
+		}
+	}
+
+	if lit := literalOf(cmp.Right); lit != nil {
+		c, err := o.constant(lit)
+		if err != nil {
+			return nil, err
+		}
+		null := c.value == nil
+
+		return func(event map[string]any) truth {
+			v := left(event)
+			if v == nil || null {
+				return result(v == nil, null, 0, false)
+			}
+			order, ok := c.compare(v)
+			return result(false, false, order, ok)
+		}, nil
+	}
+
+	right, err := o.value(cmp.Right)
+	if err != nil {
+		return nil, err
+	}
+	return func(event map[string]any) truth {
+		a, b := left(event), right(event)
+		if a == nil || b == nil {
+			return result(a == nil, b == nil, 0, false)
+		}
+		order, ok := compareValues(a, b)
+		return result(false, false, order, ok)
+	}, nil
+}
+
+// comparisonResults maps comparison operators to whether they accept the ordering of their operands.
+var comparisonResults = map[string]func(order int) bool{
+	"=":   func(order int) bool { return order == 0 },
+	"<=>": func(order int) bool { return order == 0 },
+	"!=":  func(order int) bool { return order != 0 },
+	"<":   func(order int) bool { return order < 0 },
+	"<=":  func(order int) bool { return order <= 0 },
+	">":   func(order int) bool { return order > 0 },
+	">=":  func(order int) bool { return order >= 0 },
+}
+
+// like compiles a LIKE or ILIKE condition. Patterns with wildcards only at the ends are matched with
+// string functions, and others with a regular expression.
+func (o *compileOptions) like(left getter, like *LikeOp) (condition, error) {
+	lit := literalOf(like.Pattern)
+	if lit == nil {
+		return nil, errors.New("LIKE patterns must be literals to be compiled")
+	}
+	c, err := o.constant(lit)
+	if err != nil {
+		return nil, err
+	}
+	if !c.isStr {
+		return nil, errors.New("LIKE pattern must be a string")
+	}
+
+	insensitive := strings.EqualFold(like.Type.Operator, "ILIKE")
+	pattern := c.str
+	if insensitive {
+		pattern = strings.ToLower(pattern)
+	}
+	match, err := likeMatcher(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.negate(like.Not, func(event map[string]any) truth {
+		v := left(event)
+		if v == nil {
+			return truthUnknown
+		}
+		s, ok := stringOf(v)
+		if !ok {
+			return truthFalse
+		}
+		if insensitive {
+			s = strings.ToLower(s)
+		}
+		return truthOf(match(s))
+	}), nil
+}
+
+// likeMatcher returns a function matching strings against a LIKE pattern, in which \ escapes the
+// next character.
+func likeMatcher(pattern string) (func(string) bool, error) {
+	var (
+		text              strings.Builder
+		re                strings.Builder
+		leading, trailing bool
+		wildcards         bool
+	)
+	re.WriteString("(?s)^")
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		switch {
+		case ch == '\\' && i+1 < len(pattern):
+			i++
+			text.WriteByte(pattern[i])
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case ch == '%':
+			re.WriteString(".*")
+			switch {
+			case i == 0:
+				leading = true
+			case i == len(pattern)-1:
+				trailing = true
+			default:
+				wildcards = true
+			}
+		case ch == '_':
+			re.WriteString(".")
+			wildcards = true
+		default:
+			text.WriteByte(ch)
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	re.WriteString("$")
+
+	literal := text.String()
+	switch {
+	case wildcards:
+		compiled, err := regexp.Compile(re.String())
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid LIKE pattern")
+		}
+		return compiled.MatchString, nil
+	case leading && trailing:
+		return func(s string) bool { return strings.Contains(s, literal) }, nil
+	case leading:
+		return func(s string) bool { return strings.HasSuffix(s, literal) }, nil
+	case trailing:
+		return func(s string) bool { return strings.HasPrefix(s, literal) }, nil
+	default:
+		return func(s string) bool { return s == literal }, nil
+	}
+}
+
+// in compiles an IN condition. Literal strings and numbers are looked up in sets, and other values
+// are compared in turn. As in SQL, a value not in a list containing NULL is unknown.
+func (o *compileOptions) in(left getter, values []*Value) (condition, error) {
+	var (
+		strs    = make(map[string]struct{})
+		nums    = make(map[float64]struct{})
+		others  []*constant
+		fields  []getter
+		hasNull bool
+	)
+	for _, val := range values {
+		lit := literalOf(val)
+		if lit == nil {
+			field, err := o.value(val)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, field)
+			continue
+		}
+
+		items := []any{lit.Value()}
+		if lit.Variable != nil && !lit.bound {
+			value, err := o.variable(lit)
+			if err != nil {
+				return nil, err
+			}
+			items = expandValues([]any{value})
+		}
+		for _, item := range items {
+			c := o.coerce(item)
+			if c.value == nil {
+				hasNull = true
+				continue
+			}
+			if c.isStr {
+				strs[c.str] = struct{}{}
+			}
+			if c.isNum {
+				nums[c.num] = struct{}{}
+			}
+			if !c.isStr && !c.isNum || c.isTime {
+				others = append(others, c)
+			}
+		}
+	}
+
+	return func(event map[string]any) truth {
+		v := left(event)
+		if v == nil {
+			return truthUnknown
+		}
+
+		if s, ok := v.(string); ok {
+			if _, ok := strs[s]; ok {
+				return truthTrue
+			}
+			if n, err := strconv.ParseFloat(s, 64); err == nil && len(nums) > 0 {
+				if _, ok := nums[n]; ok {
+					return truthTrue
+				}
+			}
+		} else if n, ok := numberOf(v); ok {
+			if _, ok := nums[n]; ok {
+				return truthTrue
+			}
+		}
+
+		for _, c := range others {
+			if order, ok := c.compare(v); ok && order == 0 {
+				return truthTrue
+			}
+		}
+
+		result := truthFalse
+		for _, field := range fields {
+			other := field(event)
+			if other == nil {
+				result = truthUnknown
+				continue
+			}
+			if order, ok := compareValues(v, other); ok && order == 0 {
+				return truthTrue
+			}
+		}
+		if hasNull {
+			return truthUnknown
+		}
+		return result
+	}, nil
+}
+
+// value compiles a value into a getter.
+func (o *compileOptions) value(val *Value) (getter, error) {
+	switch {
+	case val == nil:
+		return nil, errors.New("empty value")
+	case val.Bitwise != nil:
+		return o.bitwise(val.Bitwise)
+	case val.Field != nil:
+		return fieldGetter(val.Field.Parts), nil
+	case val.Literal != nil:
+		c, err := o.constant(val.Literal)
+		if err != nil {
+			return nil, err
+		}
+		value := c.value
+		return func(map[string]any) any { return value }, nil
+	case val.Function != nil:
+		return o.function(val.Function)
+	case val.SubExpr != nil:
+		cond, err := o.expression(val.SubExpr)
+		if err != nil {
+			return nil, err
+		}
+		return func(event map[string]any) any {
+			switch cond(event) {
+			case truthTrue:
+				return true
+			case truthFalse:
+				return false
+			default:
+				return nil
+			}
+		}, nil
+	default:
+		return nil, errors.New("empty value")
+	}
+}
+
+// fieldGetter returns a getter for a field. A qualified field is looked up as a key of that name,
+// and else through nested maps.
+func fieldGetter(parts []string) getter {
+	if len(parts) == 1 {
+		name := parts[0]
+		return func(event map[string]any) any { return event[name] }
+	}
+
+	name := strings.Join(parts, ".")
+	return func(event map[string]any) any {
+		if v, ok := event[name]; ok {
+			return v
+		}
+
+		current := event
+		for i, part := range parts {
+			v := current[part]
+			if i == len(parts)-1 {
+				return v
+			}
+			next, ok := v.(map[string]any)
+			if !ok {
+				return nil
+			}
+			current = next
+		}
+		return nil
+	}
+}
+
+// function compiles a call of one of the supported functions.
+func (o *compileOptions) function(fn *FunctionCall) (getter, error) {
+	if fn.isCast() || fn.Cond != nil || fn.Star {
+		return nil, errors.Errorf("%s expressions cannot be compiled", strings.ToUpper(fn.Name))
+	}
+
+	args := make([]getter, len(fn.Args))
+	for i, arg := range fn.Args {
+		g, err := o.value(arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = g
+	}
+
+	name := strings.ToUpper(fn.Name)
+	if name == "COALESCE" {
+		return func(event map[string]any) any {
+			for _, arg := range args {
+				if v := arg(event); v != nil {
+					return v
+				}
+			}
+			return nil
+		}, nil
+	}
+
+	apply, ok := compiledFunctions[name]
+	if !ok {
+		return nil, errors.Errorf("function %q cannot be compiled", fn.Name)
+	}
+	if len(args) != 1 {
+		return nil, newMessage(MsgFunctionArgs, "function", name, "expected", "1 argument", "count", len(args))
+	}
+
+	arg := args[0]
+	return func(event map[string]any) any {
+		v := arg(event)
+		if v == nil {
+			return nil
+		}
+		return apply(v)
+	}, nil
+}
+
+// compiledFunctions holds the single-argument functions Compile supports. They return nil for
+// arguments of the wrong type.
+var compiledFunctions = map[string]func(any) any{
+	"LOWER": stringFunction(strings.ToLower),
+	"UPPER": stringFunction(strings.ToUpper),
+	"TRIM":  stringFunction(strings.TrimSpace),
+	"LENGTH": func(v any) any {
+		if s, ok := stringOf(v); ok {
+			return float64(utf8.RuneCountInString(s))
+		}
+		return nil
+	},
+	"ABS": func(v any) any {
+		if n, ok := numberOf(v); ok {
+			return math.Abs(n)
+		}
+		return nil
+	},
+}
+
+func stringFunction(fn func(string) string) func(any) any {
+	return func(v any) any {
+		if s, ok := stringOf(v); ok {
+			return fn(s)
+		}
+		return nil
+	}
+}
+
+// bitwise compiles a chain of bitwise operations on integers.
+func (o *compileOptions) bitwise(expr *BitwiseExpr) (getter, error) {
+	left, err := o.value(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range expr.Ops {
+		right, err := o.value(op.Right)
+		if err != nil {
+			return nil, err
+		}
+
+		var apply func(a, b int64) int64
+		switch op.Operator {
+		case "&":
+			apply = func(a, b int64) int64 { return a & b }
+		case "|":
+			apply = func(a, b int64) int64 { return a | b }
+		case "^":
+			apply = func(a, b int64) int64 { return a ^ b }
+		case "<<":
+			apply = func(a, b int64) int64 { return a << uint64(b) }
+		case ">>":
+			apply = func(a, b int64) int64 { return a >> uint64(b) }
+		default:
+			return nil, errors.Errorf("operator %s cannot be compiled", op.Operator)
+		}
+
+		prev := left
+		left = func(event map[string]any) any {
+			a, ok := numberOf(prev(event))
+			if !ok || a != math.Trunc(a) {
+				return nil
+			}
+			b, ok := numberOf(right(event))
+			if !ok || b != math.Trunc(b) || b < 0 {
+				return nil
+			}
+			return float64(apply(int64(a), int64(b)))
+		}
+	}
+	return left, nil
+}
+
+// constant returns a literal with its coercions precomputed.
+func (o *compileOptions) constant(lit *LiteralValue) (*constant, error) {
+	if lit.Variable != nil && !lit.bound {
+		value, err := o.variable(lit)
+		if err != nil {
+			return nil, err
+		}
+		return o.coerce(value), nil
+	}
+	return o.coerce(lit.Value()), nil
+}
+
+func (o *compileOptions) variable(lit *LiteralValue) (any, error) {
+	value, ok := o.variables[lit.variableName()]
+	if !ok {
+		return nil, newMessage(MsgMissingVariable, "variable", *lit.Variable)
+	}
+	return value, nil
+}
+
+// coerce precomputes the number and time a value compares as.
+func (o *compileOptions) coerce(value any) *constant {
+	c := &constant{value: value}
+	switch v := value.(type) {
+	case string:
+		c.str, c.isStr = v, true
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			c.num, c.isNum = n, true
+		}
+		if t, ok := parseTime(v, o.timeLayouts, time.UTC); ok {
+			c.time, c.isTime = t, true
+		}
+	case time.Time:
+		c.time, c.isTime = v, true
+	default:
+		c.num, c.isNum = numberOf(v)
+	}
+	return c
+}
+
+// compare orders an event value relative to the constant, returning false if they cannot be
+// compared.
+func (c *constant) compare(v any) (int, bool) {
+	switch x := v.(type) {
+	case string:
+		if c.isStr {
+			return strings.Compare(x, c.str), true
+		}
+		if c.isNum {
+			if n, err := strconv.ParseFloat(x, 64); err == nil {
+				return compareNumbers(n, c.num), true
+			}
+		}
+		return 0, false
+	case time.Time:
+		if c.isTime {
+			return x.Compare(c.time), true
+		}
+		return 0, false
+	case bool:
+		if b, ok := c.value.(bool); ok {
+			return compareBools(x, b), true
+		}
+		return 0, false
+	case []byte:
+		if b, ok := c.value.([]byte); ok {
+			return bytes.Compare(x, b), true
+		}
+		return 0, false
+	}
+
+	if n, ok := numberOf(v); ok && c.isNum {
+		return compareNumbers(n, c.num), true
+	}
+	return 0, false
+}
+
+// compareValues orders two event values, returning false if they cannot be compared.
+func compareValues(a, b any) (int, bool) {
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	}
+	if x, ok := a.(time.Time); ok {
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), true
+		}
+	}
+	if x, ok := a.(bool); ok {
+		if y, ok := b.(bool); ok {
+			return compareBools(x, y), true
+		}
+	}
+	if x, ok := a.([]byte); ok {
+		if y, ok := b.([]byte); ok {
+			return bytes.Compare(x, y), true
+		}
+	}
+
+	x, ok := numberOf(a)
+	if !ok {
+		return 0, false
+	}
+	y, ok := numberOf(b)
+	if !ok {
+		return 0, false
+	}
+	return compareNumbers(x, y), true
+}
+
+// numberOf returns the value of a number of any Go type, or of a json.Number.
+func numberOf(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func stringOf(v any) (string, bool) {
+	switch s := v.(type) {
+	case string:
+		return s, true
+	case []byte:
+		return string(s), true
+	default:
+		return "", false
+	}
+}
+
+func compareNumbers(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// literalOf returns the literal of a plain literal value, or nil for any other value.
+func literalOf(val *Value) *LiteralValue {
+	if val == nil || val.Bitwise != nil {
+		return nil
+	}
+	return val.Literal
+}
+
+func truthOf(b bool) truth {
+	if b {
+		return truthTrue
+	}
+	return truthFalse
+}
@@ -0,0 +1,134 @@
+package where_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	event := map[string]any{
+		"age":        int64(30),
+		"score":      json.Number("7.5"),
+		"level":      "error",
+		"name":       "Alice_Smith",
+		"active":     true,
+		"flags":      5,
+		"created_at": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		"zip":        "02134",
+		"deleted_at": nil,
+		"service":    map[string]any{"name": "api-gateway"},
+		"user.id":    "u1",
+	}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{filter: "age >= 18 AND age < 65", want: true},
+		{filter: "age = 30.0 AND score > 7", want: true},
+		{filter: "age != 30", want: false},
+		{filter: "level IN ('warn', 'error')", want: true},
+		{filter: "level NOT IN ('warn', 'error')", want: false},
+		{filter: "age IN (1, 30)", want: true},
+		{filter: "zip = 2134", want: true},
+		{filter: "name LIKE 'Alice%' AND name LIKE '%Smith' AND name LIKE '%e\\_S%'", want: true},
+		{filter: "name LIKE 'A_ice%'", want: true},
+		{filter: "name ILIKE 'alice_smith'", want: true},
+		{filter: "name NOT LIKE '%bob%'", want: true},
+		{filter: "active", want: true},
+		{filter: "active = false", want: false},
+		{filter: "flags & 4 = 4", want: true},
+		{filter: "flags & 2 = 2", want: false},
+		{filter: "created_at > '2024-01-01' AND created_at BETWEEN '2024-02-01' AND '2024-04-01'", want: true},
+		{filter: "deleted_at IS NULL AND level IS NOT NULL", want: true},
+		{filter: "service.name LIKE 'api-%' AND user.id = 'u1'", want: true},
+		{filter: "LOWER(level) = 'error' AND LENGTH(name) = 11 AND COALESCE(missing, 'x') = 'x'", want: true},
+		{filter: "UPPER(TRIM(level)) = 'ERROR' AND ABS(-3) = 3", want: true},
+		{filter: "age > score", want: true},
+		{filter: "NOT (level = 'error' OR age < 18)", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := where.Parse(tt.filter)
+			require.NoError(t, err)
+
+			match, err := filter.Compile()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, match(event))
+		})
+	}
+}
+
+func TestCompileNulls(t *testing.T) {
+	event := map[string]any{"status": nil, "kind": "a"}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{filter: "status = 'x'", want: false},
+		{filter: "NOT (status = 'x')", want: false},
+		{filter: "status != 'x'", want: false},
+		{filter: "missing = 'x' OR kind = 'a'", want: true},
+		{filter: "NOT (missing = 'x' AND kind = 'b')", want: true},
+		{filter: "status <=> NULL", want: true},
+		{filter: "kind <=> NULL", want: false},
+		{filter: "kind NOT IN ('b', NULL)", want: false},
+		{filter: "status NOT BETWEEN 1 AND 2", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := where.Parse(tt.filter)
+			require.NoError(t, err)
+
+			match, err := filter.Compile()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, match(event))
+		})
+	}
+}
+
+func TestCompileVariables(t *testing.T) {
+	filter, err := where.Parse("level IN (:levels) AND age > :min")
+	require.NoError(t, err)
+
+	_, err = filter.Compile()
+	require.EqualError(t, err, "missing value for variable :levels")
+
+	match, err := filter.Compile(where.WithCompileVariables(map[string]any{
+		"levels": []string{"warn", "error"},
+		"min":    18,
+	}))
+	require.NoError(t, err)
+	require.True(t, match(map[string]any{"level": "warn", "age": 20}))
+	require.False(t, match(map[string]any{"level": "info", "age": 20}))
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		filter string
+		err    string
+	}{
+		{filter: "body MATCHES 'fox'", err: "MATCHES conditions cannot be compiled"},
+		{filter: "DATE_TRUNC('day', ts) = '2024-01-01'", err: `function "DATE_TRUNC" cannot be compiled`},
+		{filter: "CAST(age AS TEXT) = '1'", err: "CAST expressions cannot be compiled"},
+		{filter: "name LIKE other", err: "LIKE patterns must be literals to be compiled"},
+		{filter: "LOWER(a, b) = 'x'", err: "function LOWER expects 1 argument, got 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := where.Parse(tt.filter)
+			require.NoError(t, err)
+
+			_, err = filter.Compile()
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
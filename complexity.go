@@ -0,0 +1,159 @@
+package where
+
+import (
+	"strings"
+)
+
+// Weights used to compute Complexity.Score.
+const (
+	predicateWeight       = 1
+	orBranchWeight        = 2
+	leadingWildcardWeight = 10
+	notInItemWeight       = 1
+	functionWeight        = 1
+	functionDepthWeight   = 3
+)
+
+type (
+	// Complexity summarizes how expensive a filter is likely to be for the database to evaluate.
+	Complexity struct {
+		// Predicates is the total number of predicates in the filter.
+		Predicates int
+
+		// ORBranches is the number of additional branches introduced by OR expressions.
+		ORBranches int
+
+		// LeadingWildcards counts LIKE/ILIKE patterns starting with a wildcard, which defeat indexes.
+		LeadingWildcards int
+
+		// NotInItems is the total number of values across all NOT IN lists.
+		NotInItems int
+
+		// Functions is the total number of function calls.
+		Functions int
+
+		// MaxFunctionDepth is the deepest level of function call nesting.
+		MaxFunctionDepth int
+
+		// Score is the weighted sum of all the above metrics.
+		Score int
+	}
+)
+
+// EstimateComplexity scores the filter based on predicate count, OR fan-out, leading wildcard
+// LIKE patterns, NOT IN list sizes, and function nesting. Higher scores indicate more expensive filters.
+func EstimateComplexity(filter *Filter) Complexity {
+	var c Complexity
+	if filter == nil {
+		return c
+	}
+
+	c.expression(filter.Expression)
+	c.Score = c.Predicates*predicateWeight +
+		c.ORBranches*orBranchWeight +
+		c.LeadingWildcards*leadingWildcardWeight +
+		c.NotInItems*notInItemWeight +
+		c.Functions*functionWeight +
+		c.MaxFunctionDepth*functionDepthWeight
+
+	return c
+}
+
+func (c *Complexity) expression(expr *Expression) {
+	if expr == nil || len(expr.Or) == 0 {
+		return
+	}
+
+	c.ORBranches += len(expr.Or) - 1
+	for _, term := range expr.Or {
+		if term == nil {
+			continue
+		}
+		for _, factor := range term.And {
+			c.factor(factor)
+		}
+	}
+}
+
+func (c *Complexity) factor(factor *Factor) {
+	if factor == nil {
+		return
+	}
+	if factor.SubExpr != nil {
+		c.expression(factor.SubExpr)
+		return
+	}
+	if factor.Predicate != nil {
+		c.predicate(factor.Predicate)
+	}
+	if factor.Exists != nil {
+		c.Predicates++
+	}
+}
+
+func (c *Complexity) predicate(pred *Predicate) {
+	c.Predicates++
+	c.value(pred.Left, 0)
+
+	op := pred.Operation
+	if op == nil {
+		return
+	}
+
+	switch {
+	case op.Compare != nil:
+		c.value(op.Compare.Right, 0)
+	case op.Like != nil:
+		c.value(op.Like.Pattern, 0)
+		if hasLeadingWildcard(op.Like.Pattern) {
+			c.LeadingWildcards++
+		}
+	case op.Match != nil:
+		c.value(op.Match.Query, 0)
+	case op.Between != nil:
+		c.value(op.Between.Lower, 0)
+		c.value(op.Between.Upper, 0)
+	case op.In != nil:
+		if op.In.Not {
+			c.NotInItems += len(op.In.Values)
+		}
+		for _, val := range op.In.Values {
+			c.value(val, 0)
+		}
+	}
+}
+
+func (c *Complexity) value(val *Value, depth int) {
+	if val == nil {
+		return
+	}
+
+	if val.Function != nil {
+		depth++
+		c.Functions++
+		c.MaxFunctionDepth = max(c.MaxFunctionDepth, depth)
+		for _, arg := range val.Function.arguments() {
+			c.value(arg, depth)
+		}
+	}
+
+	if val.SubExpr != nil {
+		c.expression(val.SubExpr)
+	}
+
+	if val.Bitwise != nil {
+		c.value(val.Bitwise.Left, depth)
+		for _, op := range val.Bitwise.Ops {
+			c.value(op.Right, depth)
+		}
+	}
+}
+
+func hasLeadingWildcard(pattern *Value) bool {
+	if pattern == nil || pattern.Literal == nil {
+		return false
+	}
+
+	s, ok := pattern.Literal.Value().(string)
+	return ok && (strings.HasPrefix(s, "%") || strings.HasPrefix(s, "_"))
+}
@@ -0,0 +1,65 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateComplexity(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  where.Complexity
+	}{
+		{
+			name:  "single predicate",
+			input: "age > 18",
+			want:  where.Complexity{Predicates: 1, Score: 1},
+		},
+		{
+			name:  "OR fan-out",
+			input: "a = 1 OR b = 2 OR (c = 3 OR d = 4)",
+			want:  where.Complexity{Predicates: 4, ORBranches: 3, Score: 10},
+		},
+		{
+			name:  "leading wildcard",
+			input: "name LIKE '%john' AND email ILIKE 'admin%'",
+			want:  where.Complexity{Predicates: 2, LeadingWildcards: 1, Score: 12},
+		},
+		{
+			name:  "NOT IN size",
+			input: "id NOT IN (1, 2, 3) AND status IN ('a', 'b')",
+			want:  where.Complexity{Predicates: 2, NotInItems: 3, Score: 5},
+		},
+		{
+			name:  "function nesting",
+			input: "LOWER(TRIM(name)) = UPPER('x')",
+			want:  where.Complexity{Predicates: 1, Functions: 3, MaxFunctionDepth: 2, Score: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, where.EstimateComplexity(filter))
+		})
+	}
+
+	t.Run("nil filter", func(t *testing.T) {
+		require.Equal(t, where.Complexity{}, where.EstimateComplexity(nil))
+	})
+}
+
+func TestWithMaxComplexity(t *testing.T) {
+	parser, err := where.NewParser(where.WithMaxComplexity(10))
+	require.NoError(t, err)
+
+	_, err = parser.Parse("age > 18 AND status = 'active'")
+	require.NoError(t, err)
+
+	_, err = parser.Parse("name LIKE '%john%' AND age > 18")
+	require.EqualError(t, err, "filter validation failed: filter complexity 12 exceeds maximum of 10")
+}
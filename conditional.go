@@ -0,0 +1,17 @@
+package where
+
+import "strings"
+
+// checkIf validates the form of IF(condition, a, b) expressions.
+func checkIf(fn *FunctionCall) error {
+	if fn.Cond == nil {
+		return nil
+	}
+	if !strings.EqualFold(fn.Name, "IF") {
+		return newMessage(MsgIfCondition, "function", fn.Name)
+	}
+	if len(fn.Args) != 2 {
+		return newMessage(MsgIfArgs)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package where_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIf(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       map[string]string
+		wantParams []any
+	}{
+		{
+			name:  "comparison",
+			input: "IF(age >= 18, 'adult', 'minor') = 'adult'",
+			want: map[string]string{
+				"postgres":   "CASE WHEN age >= $1 THEN $2 ELSE $3 END = $4",
+				"mysql":      "IF(age >= ?, ?, ?) = ?",
+				"clickhouse": "IF(age >= ?, ?, ?) = ?",
+			},
+			wantParams: []any{float64(18), "adult", "minor", "adult"},
+		},
+		{
+			name:  "compound condition",
+			input: "if(status = 'active' OR role IN ('admin', 'owner'), 1, 0) = 1",
+			want: map[string]string{
+				"postgres": "CASE WHEN (status = $1 OR role IN ($2, $3)) THEN $4 ELSE $5 END = $6",
+				"mysql":    "if((status = ? OR role IN (?, ?)), ?, ?) = ?",
+			},
+			wantParams: []any{"active", "admin", "owner", float64(1), float64(0), float64(1)},
+		},
+		{
+			name:  "boolean value",
+			input: "IF(verified, score, 0) > 10",
+			want: map[string]string{
+				"postgres": "CASE WHEN verified THEN score ELSE $1 END > $2",
+				"mysql":    "IF(verified, score, ?) > ?",
+			},
+			wantParams: []any{float64(0), float64(10)},
+		},
+		{
+			name:  "nested",
+			input: "IF(deleted_at IS NULL, IF(LOWER(name) LIKE 'a%', 'a', 'b'), 'c') = 'a'",
+			want: map[string]string{
+				"postgres": "CASE WHEN deleted_at IS NULL THEN CASE WHEN LOWER(name) LIKE $1 THEN $2 ELSE $3 END ELSE $4 END = $5",
+				"mysql":    "IF(deleted_at IS NULL, IF(LOWER(name) LIKE ?, ?, ?), ?) = ?",
+			},
+			wantParams: []any{"a%", "a", "b", "c", "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		filter, err := where.Parse(tt.input)
+		require.NoError(t, err)
+
+		for driver, want := range tt.want {
+			t.Run(tt.name+"/"+driver, func(t *testing.T) {
+				sql, params, err := filter.ToSQL(driver)
+				require.NoError(t, err)
+				require.Equal(t, want, sql)
+				require.Equal(t, tt.wantParams, params)
+			})
+		}
+	}
+}
+
+func TestIfErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{input: "COALESCE(a = 1, 2) = 1", wantErr: "failed to parse filter expression"},
+		{input: "IF(a = 1, 2) = 1", wantErr: "filter validation failed: IF requires a condition and two values"},
+		{input: "IF(a = 1, 2, 3, 4) = 1", wantErr: "filter validation failed: IF requires a condition and two values"},
+		{input: "IF(a =, 1, 2) = 1", wantErr: "failed to parse filter expression"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := where.Parse(tt.input)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNestedCallsParseQuickly(t *testing.T) {
+	parser, err := where.NewParser(where.WithMaxDepth(50))
+	require.NoError(t, err)
+
+	inputs := []string{
+		"a = " + strings.Repeat("F(", 40) + "1" + strings.Repeat(")", 40),
+		"a = " + strings.Repeat("F(", 40) + "1",
+		"a = " + strings.Repeat("IF(b = ", 40) + "1" + strings.Repeat(", 1, 2)", 40),
+		"a = " + strings.Repeat("IF(", 40) + "1",
+	}
+
+	// Reading every call's first argument as a possible condition took exponential time in the nesting.
+	start := time.Now()
+	for _, input := range inputs {
+		_, _ = parser.Parse(input)
+	}
+	require.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestIfValidation(t *testing.T) {
+	parser, err := where.NewParser(where.WithPortableFunctions(), where.WithFunctionArgValidation())
+	require.NoError(t, err)
+
+	_, err = parser.Parse("IF(age > 18 AND LOWER(name) = 'x', 1, 0) = 1")
+	require.NoError(t, err)
+
+	_, err = parser.Parse("IF(toYYYYMM(created_at) = 202401, 1, 0) = 1")
+	require.ErrorContains(t, err, `function "toYYYYMM" is not portable`)
+
+	validator := where.NewValidator().AllowFields("age").AllowFunctions("IF")
+	filter, err := where.Parse("IF(secret > 1, 1, 0) = 1")
+	require.NoError(t, err)
+	_, _, err = filter.ToSQL("postgres", where.WithValidator(validator))
+	require.ErrorContains(t, err, "secret")
+}
+
+func TestIfFormatAndClone(t *testing.T) {
+	filter, err := where.Parse("if(a > :min and b = 'x', lower(c), 'y') = 'z'")
+	require.NoError(t, err)
+
+	require.Equal(t, "if(a > :min AND b = 'x', lower(c), 'y') = 'z'", filter.String())
+	require.Equal(t, filter.String(), filter.Clone().String())
+	require.Equal(t, []string{"min"}, filter.Variables())
+
+	reparsed, err := where.Parse(filter.String())
+	require.NoError(t, err)
+	require.True(t, filter.Equal(reparsed))
+}
@@ -0,0 +1,89 @@
+package where
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// FromMap returns a filter matching rows where each field equals its value, e.g. for structured
+// query parameters. A nil value matches NULL, and a slice (other than a byte slice) matches any of
+// its elements like In; an empty slice matches no rows. Other values are bound as parameters without
+// conversion. Fields are ANDed in sorted order, so the SQL is the same for equal maps, and nil is
+// returned for an empty map. Fields may be qualified, e.g. "users.status".
+//
+// The result is an ordinary filter, so it is checked by the validator when combined with a parsed
+// filter using And. Map keys taken from a request must be validated like any other field.
+//
+// Example:
+//
+//	query := where.FromMap(map[string]any{"status": "open", "tag": []string{"a", "b"}, "deleted_at": nil})
+//	// deleted_at IS NULL AND status = 'open' AND tag IN ('a', 'b')
+//	filter, _ := where.Parse(r.URL.Query().Get("q"))
+//	sql, params, _ := filter.And(query).ToSQL("postgres", where.WithValidator(validator))
+func FromMap(values map[string]any) *Filter {
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	factors := make([]*Factor, len(fields))
+	for i, field := range fields {
+		factors[i] = &Factor{Predicate: mapPredicate(field, values[field])}
+	}
+	return (&Filter{}).withFactors(factors)
+}
+
+// mapPredicate returns the predicate FromMap uses for a field and its value.
+func mapPredicate(field string, value any) *Predicate {
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		if _, ok := value.([]byte); !ok {
+			return inFilter(field, []any{value}, false).Expression.Or[0].And[0].Predicate
+		}
+	}
+
+	left := &Value{Field: &FieldRef{Parts: strings.Split(field, ".")}}
+	if value == nil {
+		return &Predicate{Left: left, Operation: &Operation{IsNull: &IsNullOp{Is: "IS", Null: "NULL"}}}
+	}
+	return &Predicate{Left: left, Operation: &Operation{Compare: &CompareOp{
+		Operator: CompareOperator{Type: "="},
+		Right:    &Value{Literal: &LiteralValue{bound: true, param: value}},
+	}}}
+}
+
+// And returns a filter matching rows matched by f and every other filter, e.g. to combine a parsed
+// filter with one from FromMap. Unlike WithRequiredFilter, the combined filter is checked by the
+// validator as a whole. A filter with OR at its root is kept in parentheses, and nil filters are
+// skipped; nil is returned if every filter is nil. The returned filter shares AST nodes with the
+// filters and is not frozen.
+//
+// Example:
+//
+//	filter, _ := where.Parse("age > 18 OR verified")
+//	combined := filter.And(where.FromMap(map[string]any{"status": "open"}))
+//	// (age > 18 OR verified) AND status = 'open'
+func (f *Filter) And(others ...*Filter) *Filter {
+	var first *Filter
+	var factors []*Factor
+	for _, filter := range append([]*Filter{f}, others...) {
+		if filter == nil || filter.Expression == nil || len(filter.Expression.Or) == 0 {
+			continue
+		}
+		if first == nil {
+			first = filter
+		}
+
+		if len(filter.Expression.Or) == 1 {
+			factors = append(factors, filter.Expression.Or[0].And...)
+		} else {
+			factors = append(factors, &Factor{SubExpr: filter.Expression})
+		}
+	}
+
+	if first == nil {
+		return nil
+	}
+	return first.withFactors(factors)
+}
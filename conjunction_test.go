@@ -0,0 +1,95 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	_ "github.com/pseudomuto/where/drivers/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromMap(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     map[string]any
+		wantString string
+		wantSQL    string
+		wantParams []any
+	}{
+		{
+			name:       "equality",
+			values:     map[string]any{"status": "open"},
+			wantString: "status = 'open'",
+			wantSQL:    "status = $1",
+			wantParams: []any{"open"},
+		},
+		{
+			name:       "sorted fields",
+			values:     map[string]any{"tag": []string{"a", "b"}, "deleted_at": nil, "age": 30, "users.active": true},
+			wantString: "age = 30 AND deleted_at IS NULL AND tag IN ('a', 'b') AND users.active = TRUE",
+			wantSQL:    "(age = $1 AND deleted_at IS NULL AND tag IN ($2, $3) AND users.active = $4)",
+			wantParams: []any{30, "a", "b", true},
+		},
+		{
+			name:       "empty slice",
+			values:     map[string]any{"id": []int{}},
+			wantString: "id IN ()",
+			wantSQL:    "FALSE",
+			wantParams: []any{},
+		},
+		{
+			name:       "byte slice",
+			values:     map[string]any{"hash": []byte{0xCA, 0xFE}},
+			wantString: "hash = 0xCAFE",
+			wantSQL:    "hash = $1",
+			wantParams: []any{[]byte{0xCA, 0xFE}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := where.FromMap(tt.values)
+			require.Equal(t, tt.wantString, filter.String())
+
+			sql, params, err := filter.ToSQL("postgres")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantParams, params)
+		})
+	}
+
+	require.Nil(t, where.FromMap(nil))
+	require.Nil(t, where.FromMap(map[string]any{}))
+}
+
+func TestFromMapCompile(t *testing.T) {
+	match, err := where.FromMap(map[string]any{"status": "open", "tag": []string{"a", "b"}, "deleted_at": nil}).Compile()
+	require.NoError(t, err)
+
+	require.True(t, match(map[string]any{"status": "open", "tag": "b", "deleted_at": nil}))
+	require.False(t, match(map[string]any{"status": "open", "tag": "c", "deleted_at": nil}))
+	require.False(t, match(map[string]any{"status": "closed", "tag": "a", "deleted_at": nil}))
+}
+
+func TestFilterAnd(t *testing.T) {
+	parsed := mustParse(t, "age > 18 OR verified")
+	query := where.FromMap(map[string]any{"status": "open"})
+
+	combined := parsed.And(query, nil, mustParse(t, "a = 1 AND b = 2"))
+	require.Equal(t, "(age > 18 OR verified) AND status = 'open' AND a = 1 AND b = 2", combined.String())
+	require.Equal(t, "age > 18 OR verified", parsed.String())
+
+	sql, params, err := combined.ToSQL("postgres")
+	require.NoError(t, err)
+	require.Equal(t, "((age > $1 OR verified) AND status = $2 AND a = $3 AND b = $4)", sql)
+	require.Equal(t, []any{18.0, "open", 1.0, 2.0}, params)
+
+	validator := where.NewValidator().AllowFields("age", "verified")
+	_, _, err = parsed.And(query).ToSQL("postgres", where.WithValidator(validator))
+	require.EqualError(t, err, `field "status" is not allowed`)
+
+	var empty *where.Filter
+	require.Equal(t, "status = 'open'", empty.And(query).String())
+	require.Nil(t, empty.And(nil))
+	require.Same(t, parsed.Expression.Or[0], parsed.And().Expression.Or[0].And[0].SubExpr.Or[0])
+}
@@ -0,0 +1,174 @@
+package where
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+type (
+	// FieldConstraint restricts the literal values a filter may compare against a field.
+	// Zero values disable the corresponding check.
+	FieldConstraint struct {
+		// AllowedValues enumerates the only values the field may be compared with.
+		AllowedValues []any
+
+		// Min and Max bound numeric values (inclusive).
+		Min *float64
+		Max *float64
+
+		// MaxLength limits the length (in runes) of string values and LIKE patterns.
+		MaxLength int
+
+		// MaxWildcards limits the number of unescaped % and _ wildcards in LIKE patterns.
+		MaxWildcards int
+	}
+)
+
+// ConstrainField registers value constraints for the given field.
+// Field names are case-insensitive, and a field is constrained whether or not the filter quotes it.
+// Calling it again for the same field replaces the constraint.
+//
+// A constrained field may only be compared with literals, variables, and other fields, so that the
+// values compared with it can be checked; comparing it with a function call or an expression, e.g.
+// status = CONCAT('dele', 'ted'), is rejected. Constraints only apply where the field itself is
+// compared, not to a field used inside a function call or expression such as LOWER(status).
+func (v *Validator) ConstrainField(field string, constraint FieldConstraint) *Validator {
+	if v.constraints == nil {
+		v.constraints = make(map[string]FieldConstraint)
+	}
+	v.constraints[constraintKey(field)] = constraint
+	return v
+}
+
+// CheckValue returns an error if the value violates the constraints registered for the field.
+// Fields without constraints and NULL values always pass. Min and Max apply to values of any
+// numeric type.
+func (v *Validator) CheckValue(field string, value any) error {
+	c, ok := v.constraints[constraintKey(field)]
+	if !ok || value == nil {
+		return nil
+	}
+
+	if len(c.AllowedValues) > 0 && !containsValue(c.AllowedValues, value) {
+		return newMessage(MsgValueNotAllowed, "value", value, "field", strconv.Quote(field))
+	}
+
+	if str, ok := value.(string); ok {
+		if c.MaxLength > 0 && len([]rune(str)) > c.MaxLength {
+			return newMessage(MsgValueTooLong, "field", strconv.Quote(field), "max", c.MaxLength)
+		}
+	}
+
+	if num, ok := toFloat(value); ok {
+		if c.Min != nil && num < *c.Min {
+			return newMessage(MsgValueBelowMin, "value", value, "field", strconv.Quote(field), "min", *c.Min)
+		}
+		if c.Max != nil && num > *c.Max {
+			return newMessage(MsgValueAboveMax, "value", value, "field", strconv.Quote(field), "max", *c.Max)
+		}
+	}
+
+	return nil
+}
+
+// isConstrained returns true if constraints are registered for the field.
+func (v *Validator) isConstrained(field string) bool {
+	_, ok := v.constraints[constraintKey(field)]
+	return ok
+}
+
+// constraintKey returns the key of the field's constraints.
+func constraintKey(field string) string {
+	return strings.ToLower(fieldName(field))
+}
+
+// CheckPattern returns an error if the LIKE pattern violates the constraints registered for the field.
+// Only MaxLength and MaxWildcards apply to patterns.
+func (v *Validator) CheckPattern(field, pattern string) error {
+	c, ok := v.constraints[constraintKey(field)]
+	if !ok {
+		return nil
+	}
+
+	if c.MaxLength > 0 && len([]rune(pattern)) > c.MaxLength {
+		return newMessage(MsgPatternTooLong, "field", strconv.Quote(field), "max", c.MaxLength)
+	}
+
+	if c.MaxWildcards > 0 && countWildcards(pattern) > c.MaxWildcards {
+		return newMessage(MsgPatternWildcards, "field", strconv.Quote(field), "max", c.MaxWildcards)
+	}
+
+	return nil
+}
+
+func containsValue(values []any, value any) bool {
+	for _, allowed := range values {
+		if valuesEqual(allowed, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func valuesEqual(a, b any) bool {
+	af, aNum := toFloat(a)
+	bf, bNum := toFloat(b)
+	if aNum && bNum {
+		return af == bf
+	}
+
+	ab, aBytes := a.([]byte)
+	bb, bBytes := b.([]byte)
+	if aBytes || bBytes {
+		return aBytes && bBytes && bytes.Equal(ab, bb)
+	}
+	return a == b
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func countWildcards(pattern string) int {
+	count := 0
+	escaped := false
+	for _, ch := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+		case ch == '\\':
+			escaped = true
+		case ch == '%' || ch == '_':
+			count++
+		}
+	}
+	return count
+}
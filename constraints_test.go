@@ -0,0 +1,116 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorCheckValue(t *testing.T) {
+	minAge, maxAge := 0.0, 150.0
+	validator := where.NewValidator().
+		ConstrainField("status", where.FieldConstraint{AllowedValues: []any{"active", "archived"}}).
+		ConstrainField("age", where.FieldConstraint{Min: &minAge, Max: &maxAge}).
+		ConstrainField("name", where.FieldConstraint{MaxLength: 5}).
+		ConstrainField("level", where.FieldConstraint{AllowedValues: []any{1, 2, 3}})
+
+	require.NoError(t, validator.CheckValue("status", "active"))
+	require.NoError(t, validator.CheckValue("STATUS", "archived"))
+	require.EqualError(t, validator.CheckValue("status", "deleted"), `value deleted is not allowed for field "status"`)
+
+	require.NoError(t, validator.CheckValue("age", float64(42)))
+	require.EqualError(t, validator.CheckValue("age", float64(-1)), `value -1 for field "age" is below minimum of 0`)
+	require.EqualError(t, validator.CheckValue("age", float64(200)), `value 200 for field "age" exceeds maximum of 150`)
+
+	require.NoError(t, validator.CheckValue("name", "héllo"))
+	require.EqualError(t, validator.CheckValue("name", "abcdef"), `value for field "name" exceeds maximum length of 5`)
+
+	// Integer allowed values match parsed float64 literals
+	require.NoError(t, validator.CheckValue("level", float64(2)))
+	require.Error(t, validator.CheckValue("level", float64(4)))
+
+	// Min and Max apply to every numeric type
+	require.EqualError(t, validator.CheckValue("age", 1000), `value 1000 for field "age" exceeds maximum of 150`)
+	require.EqualError(t, validator.CheckValue("age", int64(-5)), `value -5 for field "age" is below minimum of 0`)
+	require.EqualError(t, validator.CheckValue("age", uint8(200)), `value 200 for field "age" exceeds maximum of 150`)
+	require.NoError(t, validator.CheckValue("age", int32(42)))
+
+	// Quotes around the field are ignored
+	require.EqualError(t, validator.CheckValue("`status`", "deleted"), "value deleted is not allowed for field \"`status`\"")
+	require.Error(t, validator.CheckValue(`"Status"`, "deleted"))
+
+	// NULL and unconstrained fields always pass
+	require.NoError(t, validator.CheckValue("status", nil))
+	require.NoError(t, validator.CheckValue("other", "anything"))
+}
+
+func TestValidatorCheckPattern(t *testing.T) {
+	validator := where.NewValidator().
+		ConstrainField("name", where.FieldConstraint{MaxLength: 10, MaxWildcards: 2})
+
+	require.NoError(t, validator.CheckPattern("name", "%john%"))
+	require.NoError(t, validator.CheckPattern("name", `%a\_b%`))
+	require.EqualError(t, validator.CheckPattern("name", "%j%o%"), `pattern for field "name" exceeds maximum of 2 wildcards`)
+	require.EqualError(t, validator.CheckPattern("name", "abcdefghijk"), `pattern for field "name" exceeds maximum length of 10`)
+	require.NoError(t, validator.CheckPattern("email", "%%%%%"))
+}
+
+func TestFieldConstraintsWithVariables(t *testing.T) {
+	maxAge := 100.0
+	validator := where.NewValidator().AllowAll().ConstrainField("age", where.FieldConstraint{Max: &maxAge})
+
+	filter, err := where.Parse("age > :x")
+	require.NoError(t, err)
+
+	_, _, err = filter.ToSQL("postgres", where.WithValidator(validator), where.WithVariables(map[string]any{"x": 1000}))
+	require.EqualError(t, err, `value 1000 for field "age" exceeds maximum of 100`)
+
+	_, _, err = filter.ToSQL("postgres", where.WithValidator(validator), where.WithVariables(map[string]any{"x": 50}))
+	require.NoError(t, err)
+}
+
+func TestFieldConstraintsWithToSQL(t *testing.T) {
+	maxAge := 150.0
+	validator := where.NewValidator().
+		AllowAll().
+		ConstrainField("status", where.FieldConstraint{AllowedValues: []any{"active", "archived"}}).
+		ConstrainField("age", where.FieldConstraint{Max: &maxAge}).
+		ConstrainField("name", where.FieldConstraint{MaxWildcards: 2})
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "allowed IN values", input: "status IN ('active', 'archived')"},
+		{name: "disallowed IN value", input: "status IN ('active', 'deleted')", wantErr: `value deleted is not allowed for field "status"`},
+		{name: "disallowed comparison", input: "status = 'deleted'", wantErr: `value deleted is not allowed for field "status"`},
+		{name: "reversed comparison", input: "'deleted' = status", wantErr: `value deleted is not allowed for field "status"`},
+		{name: "BETWEEN upper bound", input: "age BETWEEN 1 AND 500", wantErr: `value 500 for field "age" exceeds maximum of 150`},
+		{name: "LIKE wildcards", input: "name LIKE '%a%b%'", wantErr: `pattern for field "name" exceeds maximum of 2 wildcards`},
+		{name: "NULL comparison", input: "status = NULL"},
+		{name: "field to field", input: "status = other_status"},
+		{name: "quoted field", input: "`status` = 'deleted'", wantErr: "value deleted is not allowed for field \"`status`\""},
+		{name: "double quoted field", input: `"status" IN ('deleted')`, wantErr: `value deleted is not allowed for field "\"status\""`},
+		{name: "function value", input: "status = CONCAT('dele', 'ted')", wantErr: `value for field "status" must be a literal or field`},
+		{name: "reversed function value", input: "CONCAT('dele', 'ted') = status", wantErr: `value for field "status" must be a literal or field`},
+		{name: "expression value", input: "age > (200 | 0)", wantErr: `value for field "age" must be a literal or field`},
+		{name: "LIKE function pattern", input: "name LIKE CONCAT('%', 'a')", wantErr: `value for field "name" must be a literal or field`},
+		{name: "unconstrained function value", input: "other = CONCAT('a', 'b')"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			_, _, err = filter.ToSQL("postgres", where.WithValidator(validator))
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
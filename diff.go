@@ -0,0 +1,181 @@
+package where
+
+import "strings"
+
+type (
+	// FilterDiff describes how one filter differs from another, condition by condition. The
+	// conditions of a filter are its top-level AND operands; a filter with OR at its root is a single
+	// condition. Conditions are compared ignoring formatting, keyword and identifier case, and order,
+	// so only semantic edits are reported. It can be serialized with encoding/json for audit logs.
+	FilterDiff struct {
+		// Added holds the conditions only present in the new filter.
+		Added []string `json:"added,omitempty"`
+
+		// Removed holds the conditions only present in the old filter.
+		Removed []string `json:"removed,omitempty"`
+
+		// Changed holds the conditions on a field whose operator or values changed.
+		Changed []ChangedCondition `json:"changed,omitempty"`
+	}
+
+	// ChangedCondition is a condition on a field that is present in both filters with different
+	// operators or values.
+	ChangedCondition struct {
+		// Field is the field the condition constrains, in lowercase.
+		Field string `json:"field"`
+
+		// Before is the condition in the old filter.
+		Before string `json:"before"`
+
+		// After is the condition in the new filter.
+		After string `json:"after"`
+	}
+
+	// diffCondition is a top-level condition of a filter being compared.
+	diffCondition struct {
+		text    string
+		key     string
+		field   string
+		matched bool
+	}
+)
+
+// Diff compares two filters and returns the conditions that were added, removed, or changed to
+// turn a into b. Either filter may be nil. Conditions are formatted as by Filter.String, and a
+// condition that is removed and added on the same field is reported as changed.
+//
+// Example:
+//
+//	before, _ := where.Parse("status = 'active' AND age > 18")
+//	after, _ := where.Parse("AGE > 18 and status IN ('active', 'pending') and deleted_at IS NULL")
+//	diff := where.Diff(before, after)
+//	// diff.Added:   [deleted_at IS NULL]
+//	// diff.Changed: [{status status = 'active' status IN ('active', 'pending')}]
+func Diff(a, b *Filter) *FilterDiff {
+	before, after := diffConditions(a), diffConditions(b)
+
+	for _, cond := range before {
+		for _, other := range after {
+			if !other.matched && other.key == cond.key {
+				cond.matched, other.matched = true, true
+				break
+			}
+		}
+	}
+
+	diff := &FilterDiff{}
+	for _, cond := range before {
+		if cond.matched {
+			continue
+		}
+		if other := unmatchedField(after, cond.field); other != nil {
+			other.matched = true
+			diff.Changed = append(diff.Changed, ChangedCondition{Field: cond.field, Before: cond.text, After: other.text})
+			continue
+		}
+		diff.Removed = append(diff.Removed, cond.text)
+	}
+
+	for _, cond := range after {
+		if !cond.matched {
+			diff.Added = append(diff.Added, cond.text)
+		}
+	}
+	return diff
+}
+
+// Empty returns true if the filters have the same conditions.
+func (d *FilterDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String renders the diff with one condition per line, prefixed with + when added, - when
+// removed, and ~ when changed.
+func (d *FilterDiff) String() string {
+	var sb strings.Builder
+	for _, cond := range d.Removed {
+		sb.WriteString("- " + cond + "\n")
+	}
+	for _, cond := range d.Added {
+		sb.WriteString("+ " + cond + "\n")
+	}
+	for _, change := range d.Changed {
+		sb.WriteString("~ " + change.Before + " => " + change.After + "\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// unmatchedField returns the first unmatched condition on field, if any.
+func unmatchedField(conds []*diffCondition, field string) *diffCondition {
+	if field == "" {
+		return nil
+	}
+
+	for _, cond := range conds {
+		if !cond.matched && cond.field == field {
+			return cond
+		}
+	}
+	return nil
+}
+
+// diffConditions returns the top-level conditions of the filter.
+func diffConditions(f *Filter) []*diffCondition {
+	if f == nil || f.Expression == nil || len(f.Expression.Or) == 0 {
+		return nil
+	}
+
+	factors := []*Factor{{SubExpr: f.Expression}}
+	if len(f.Expression.Or) == 1 {
+		factors = f.Expression.Or[0].And
+	}
+
+	fm := &formatter{opts: &formatOptions{compact: true}}
+	conds := make([]*diffCondition, 0, len(factors))
+	for _, factor := range factors {
+		if factor == nil {
+			continue
+		}
+
+		cond := &diffCondition{text: fm.factor(factor, "")}
+		if len(factors) == 1 && factor.SubExpr == f.Expression {
+			cond.text = fm.expression(f.Expression, "")
+		}
+
+		canonical := factor.DeepCopy()
+		canonicalize(&Expression{Or: []*Term{{And: []*Factor{canonical}}}})
+		cond.key = fm.factor(canonical, "")
+		if canonical.Predicate != nil && canonical.Exists == nil {
+			cond.field = predicateField(canonical.Predicate)
+		}
+		conds = append(conds, cond)
+	}
+	return conds
+}
+
+// canonicalize rewrites the expression so that equivalent conditions format identically: unquoted
+// identifiers are lowercased, function names uppercased, string literals requoted, and boolean
+// predicates written out as comparisons with TRUE.
+func canonicalize(expr *Expression) {
+	walkValues(expr, func(val *Value) {
+		switch {
+		case val.Field != nil:
+			for i, part := range val.Field.Parts {
+				if !strings.HasPrefix(part, `"`) && !strings.HasPrefix(part, "`") {
+					val.Field.Parts[i] = strings.ToLower(part)
+				}
+			}
+		case val.Function != nil:
+			val.Function.Name = strings.ToUpper(val.Function.Name)
+		case val.Literal != nil && !val.Literal.bound && val.Literal.String != nil:
+			s, _ := val.Literal.Value().(string)
+			quoted := "'" + strings.ReplaceAll(s, "'", "''") + "'"
+			val.Literal.String = &quoted
+		case val.Literal != nil && !val.Literal.bound && val.Literal.Hex != nil:
+			hex := strings.ToUpper(*val.Literal.Hex)
+			val.Literal.Hex = &hex
+		case val.Literal != nil:
+			val.Literal.implicit = false
+		}
+	})
+}
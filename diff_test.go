@@ -0,0 +1,109 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   *where.FilterDiff
+	}{
+		{
+			name:   "formatting, case, and order",
+			before: "status = 'active' AND LOWER(email) LIKE '%@example.com' AND age > 18",
+			after:  "AGE  >  18 and lower(Email) like \"%@example.com\" and Status = 'active'",
+			want:   &where.FilterDiff{},
+		},
+		{
+			name:   "added and removed",
+			before: "status = 'active' AND age > 18",
+			after:  "age > 18 AND deleted_at IS NULL",
+			want: &where.FilterDiff{
+				Removed: []string{"status = 'active'"},
+				Added:   []string{"deleted_at IS NULL"},
+			},
+		},
+		{
+			name:   "changed",
+			before: "status = 'active' AND age > 18",
+			after:  "status IN ('active', 'pending') AND age >= 21",
+			want: &where.FilterDiff{
+				Changed: []where.ChangedCondition{
+					{Field: "status", Before: "status = 'active'", After: "status IN ('active', 'pending')"},
+					{Field: "age", Before: "age > 18", After: "age >= 21"},
+				},
+			},
+		},
+		{
+			name:   "string values are case-sensitive",
+			before: "status = 'active'",
+			after:  "status = 'Active'",
+			want: &where.FilterDiff{
+				Changed: []where.ChangedCondition{{Field: "status", Before: "status = 'active'", After: "status = 'Active'"}},
+			},
+		},
+		{
+			name:   "groups",
+			before: "tenant_id = 1 AND (status = 'a' OR status = 'b')",
+			after:  "tenant_id = 1 AND (status = 'a' OR status = 'c')",
+			want: &where.FilterDiff{
+				Removed: []string{"(status = 'a' OR status = 'b')"},
+				Added:   []string{"(status = 'a' OR status = 'c')"},
+			},
+		},
+		{
+			name:   "OR at the root",
+			before: "a = 1 OR b = 2",
+			after:  "b = 2 AND a = 1",
+			want: &where.FilterDiff{
+				Removed: []string{"a = 1 OR b = 2"},
+				Added:   []string{"b = 2", "a = 1"},
+			},
+		},
+		{
+			name:   "duplicates",
+			before: "a = 1 AND a = 1",
+			after:  "a = 1",
+			want: &where.FilterDiff{
+				Removed: []string{"a = 1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, err := where.Parse(tt.before)
+			require.NoError(t, err)
+			after, err := where.Parse(tt.after)
+			require.NoError(t, err)
+
+			diff := where.Diff(before, after)
+			require.Equal(t, tt.want, diff)
+			require.Equal(t, tt.want.Empty(), diff.Empty())
+		})
+	}
+}
+
+func TestDiffNil(t *testing.T) {
+	filter, err := where.Parse("age > 18 AND status = 'active'")
+	require.NoError(t, err)
+
+	require.Equal(t, &where.FilterDiff{Added: []string{"age > 18", "status = 'active'"}}, where.Diff(nil, filter))
+	require.Equal(t, &where.FilterDiff{Removed: []string{"age > 18", "status = 'active'"}}, where.Diff(filter, nil))
+	require.True(t, where.Diff(nil, nil).Empty())
+}
+
+func TestFilterDiffString(t *testing.T) {
+	before, err := where.Parse("status = 'active' AND age > 18 AND role = 'admin'")
+	require.NoError(t, err)
+	after, err := where.Parse("status = 'pending' AND age > 18 AND deleted_at IS NULL")
+	require.NoError(t, err)
+
+	require.Equal(t, "- role = 'admin'\n+ deleted_at IS NULL\n~ status = 'active' => status = 'pending'", where.Diff(before, after).String())
+}
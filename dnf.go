@@ -0,0 +1,183 @@
+package where
+
+import "fmt"
+
+// DefaultMaxDNFBranches is the maximum number of OR branches ToDNF produces unless
+// WithMaxDNFBranches is used.
+const DefaultMaxDNFBranches = 256
+
+type (
+	// dnfOptions holds configuration options for ToDNF.
+	dnfOptions struct {
+		maxBranches int
+	}
+
+	// DNFOption is a function type for configuring ToDNF.
+	DNFOption func(*dnfOptions)
+)
+
+// WithMaxDNFBranches returns a DNFOption that sets the maximum number of OR branches ToDNF may
+// produce. Converting to DNF can grow a filter exponentially, e.g. (a OR b) AND (c OR d) has four
+// branches. Zero or a negative value removes the limit.
+func WithMaxDNFBranches(max int) DNFOption {
+	return func(o *dnfOptions) {
+		o.maxBranches = max
+	}
+}
+
+// ToDNF returns an equivalent filter in disjunctive normal form: an OR of branches that are each an
+// AND of predicates, with no nested groups. Negated groups are expanded with De Morgan's laws, so
+// NOT only applies to single predicates and EXISTS conditions, e.g. NOT (a = 1 AND b = 2) becomes
+// NOT (a = 1) OR NOT (b = 2). The result shares no AST nodes with f.
+//
+// An error is returned if the result would exceed DefaultMaxDNFBranches branches, or the limit set
+// with WithMaxDNFBranches. Use Branches to get a filter per branch.
+//
+// Example:
+//
+//	filter, _ := where.Parse("tenant_id = 1 AND (region = 'eu' OR region = 'us')")
+//	dnf, _ := filter.ToDNF()
+//	// tenant_id = 1 AND region = 'eu' OR tenant_id = 1 AND region = 'us'
+func (f *Filter) ToDNF(opts ...DNFOption) (*Filter, error) {
+	options := &dnfOptions{maxBranches: DefaultMaxDNFBranches}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if f == nil || f.Expression == nil {
+		return f, nil
+	}
+
+	branches, err := options.expression(f.Expression, false)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := &Expression{Or: make([]*Term, len(branches))}
+	for i, branch := range branches {
+		term := &Term{And: make([]*Factor, len(branch))}
+		for j, factor := range branch {
+			term.And[j] = factor.DeepCopy()
+		}
+		expr.Or[i] = term
+	}
+	return &Filter{Pos: f.Pos, Expression: expr}, nil
+}
+
+// Branches returns a filter for each top-level OR branch of f, or f itself when its root is not an
+// OR. The returned filters share AST nodes with f. Combined with ToDNF, this splits a filter into
+// queries that can run independently, e.g. on different shards.
+func (f *Filter) Branches() []*Filter {
+	if f == nil || f.Expression == nil {
+		return nil
+	}
+	if len(f.Expression.Or) < 2 {
+		return []*Filter{f}
+	}
+
+	branches := make([]*Filter, 0, len(f.Expression.Or))
+	for _, term := range f.Expression.Or {
+		if term != nil {
+			branches = append(branches, &Filter{Pos: f.Pos, Expression: &Expression{Or: []*Term{term}}})
+		}
+	}
+	return branches
+}
+
+// expression returns the branches of the expression, or of its negation.
+func (o *dnfOptions) expression(expr *Expression, negate bool) ([][]*Factor, error) {
+	var parts [][][]*Factor
+	for _, term := range expr.Or {
+		if term == nil {
+			continue
+		}
+		branches, err := o.term(term, negate)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, branches)
+	}
+
+	// NOT (a OR b) is NOT a AND NOT b.
+	if negate {
+		return o.product(parts)
+	}
+	return o.union(parts)
+}
+
+// term returns the branches of the AND term, or of its negation.
+func (o *dnfOptions) term(term *Term, negate bool) ([][]*Factor, error) {
+	parts := make([][][]*Factor, 0, len(term.And))
+	for _, factor := range term.And {
+		if factor == nil {
+			continue
+		}
+		branches, err := o.factor(factor, negate)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, branches)
+	}
+
+	// NOT (a AND b) is NOT a OR NOT b.
+	if negate {
+		return o.union(parts)
+	}
+	return o.product(parts)
+}
+
+// factor returns the branches of the factor, or of its negation.
+func (o *dnfOptions) factor(factor *Factor, negate bool) ([][]*Factor, error) {
+	negate = negate != factor.Not
+	switch {
+	case factor.SubExpr != nil:
+		return o.expression(factor.SubExpr, negate)
+	case factor.Exists != nil:
+		return [][]*Factor{{{Not: negate, Exists: factor.Exists}}}, nil
+	case negate:
+		pred := &Expression{Or: []*Term{{And: []*Factor{{Predicate: factor.Predicate}}}}}
+		return [][]*Factor{{{Not: true, SubExpr: pred}}}, nil
+	default:
+		return [][]*Factor{{{Predicate: factor.Predicate}}}, nil
+	}
+}
+
+// union returns the branches of an OR of the parts.
+func (o *dnfOptions) union(parts [][][]*Factor) ([][]*Factor, error) {
+	var branches [][]*Factor
+	for _, part := range parts {
+		branches = append(branches, part...)
+		if err := o.check(len(branches)); err != nil {
+			return nil, err
+		}
+	}
+	return branches, nil
+}
+
+// product returns the branches of an AND of the parts, distributing AND over OR.
+func (o *dnfOptions) product(parts [][][]*Factor) ([][]*Factor, error) {
+	branches := [][]*Factor{nil}
+	for _, part := range parts {
+		if err := o.check(len(branches) * len(part)); err != nil {
+			return nil, err
+		}
+
+		next := make([][]*Factor, 0, len(branches)*len(part))
+		for _, branch := range branches {
+			for _, factors := range part {
+				combined := make([]*Factor, 0, len(branch)+len(factors))
+				combined = append(append(combined, branch...), factors...)
+				next = append(next, combined)
+			}
+		}
+		branches = next
+	}
+	return branches, nil
+}
+
+func (o *dnfOptions) check(branches int) error {
+	if o.maxBranches > 0 && branches > o.maxBranches {
+		return fmt.Errorf("DNF exceeds maximum of %d branches", o.maxBranches)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterToDNF(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "a = 1", want: "a = 1"},
+		{input: "a = 1 AND b = 2 OR c = 3", want: "a = 1 AND b = 2 OR c = 3"},
+		{input: "a = 1 AND (b = 2 OR c = 3)", want: "a = 1 AND b = 2 OR a = 1 AND c = 3"},
+		{
+			input: "(a = 1 OR b = 2) AND (c = 3 OR d = 4)",
+			want:  "a = 1 AND c = 3 OR a = 1 AND d = 4 OR b = 2 AND c = 3 OR b = 2 AND d = 4",
+		},
+		{input: "((a = 1 AND (b = 2)))", want: "a = 1 AND b = 2"},
+		{input: "NOT (a = 1 AND b = 2)", want: "NOT (a = 1) OR NOT (b = 2)"},
+		{input: "NOT (a = 1 OR b = 2)", want: "NOT (a = 1) AND NOT (b = 2)"},
+		{input: "NOT (NOT (a = 1) OR b IN (1, 2))", want: "a = 1 AND NOT (b IN (1, 2))"},
+		{input: "x = 1 AND NOT (a = 1 AND (b = 2 OR c = 3))", want: "x = 1 AND NOT (a = 1) OR x = 1 AND NOT (b = 2) AND NOT (c = 3)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			dnf, err := filter.ToDNF()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, dnf.String())
+		})
+	}
+}
+
+func TestFilterToDNFExists(t *testing.T) {
+	filter := where.NotExists("SELECT 1 FROM orders WHERE orders.user_id = users.id")
+
+	dnf, err := filter.ToDNF()
+	require.NoError(t, err)
+	require.Equal(t, "NOT EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id)", dnf.String())
+}
+
+func TestFilterToDNFLimit(t *testing.T) {
+	filter, err := where.Parse("(a = 1 OR a = 2) AND (b = 1 OR b = 2) AND (c = 1 OR c = 2)")
+	require.NoError(t, err)
+
+	_, err = filter.ToDNF(where.WithMaxDNFBranches(4))
+	require.EqualError(t, err, "DNF exceeds maximum of 4 branches")
+
+	dnf, err := filter.ToDNF(where.WithMaxDNFBranches(8))
+	require.NoError(t, err)
+	require.Len(t, dnf.Expression.Or, 8)
+
+	dnf, err = filter.ToDNF(where.WithMaxDNFBranches(0))
+	require.NoError(t, err)
+	require.Len(t, dnf.Expression.Or, 8)
+}
+
+func TestFilterToDNFDoesNotShareNodes(t *testing.T) {
+	filter, err := where.Parse("a = 1 AND (b = 2 OR c = 3)")
+	require.NoError(t, err)
+
+	dnf, err := filter.ToDNF()
+	require.NoError(t, err)
+
+	dnf.Expression.Or[0].And[0].Predicate.Left.Field.Parts[0] = "z"
+	require.Equal(t, "a = 1 AND (b = 2 OR c = 3)", filter.String())
+}
+
+func TestFilterBranches(t *testing.T) {
+	filter, err := where.Parse("a = 1 AND (b = 2 OR c = 3)")
+	require.NoError(t, err)
+
+	branches := filter.Branches()
+	require.Len(t, branches, 1)
+	require.Equal(t, filter.String(), branches[0].String())
+
+	dnf, err := filter.ToDNF()
+	require.NoError(t, err)
+
+	branches = dnf.Branches()
+	require.Len(t, branches, 2)
+	require.Equal(t, "a = 1 AND b = 2", branches[0].String())
+	require.Equal(t, "a = 1 AND c = 3", branches[1].String())
+
+	sql, params, err := branches[1].ToSQL("postgres")
+	require.NoError(t, err)
+	require.Equal(t, "(a = $1 AND c = $2)", sql)
+	require.Equal(t, []any{1.0, 3.0}, params)
+
+	require.Nil(t, (*where.Filter)(nil).Branches())
+}
@@ -2,14 +2,7 @@ package where
 
 import (
 	"strings"
-	"sync"
-
-	"github.com/pkg/errors"
-)
-
-var (
-	driversMu sync.RWMutex
-	drivers   = make(map[string]Driver)
+	"time"
 )
 
 type (
@@ -35,47 +28,125 @@ type (
 		// SupportsFeature returns true if the database supports the named feature.
 		SupportsFeature(feature string) bool
 	}
+
+	// ParamLimiter is implemented by drivers whose databases limit the number of bound parameters
+	// in a single statement. The SQL builder fails early when a filter exceeds the limit.
+	ParamLimiter interface {
+		// MaxParams returns the maximum number of parameters in a single statement.
+		MaxParams() int
+	}
+
+	// TypeMapper is implemented by drivers that translate type names in CAST expressions, e.g.
+	// INTEGER to Int32 in ClickHouse.
+	TypeMapper interface {
+		// MapType returns the database type for the upper-cased type name and its parameters,
+		// or false to use the type as written.
+		MapType(name string, params []string) (string, bool)
+	}
+
+	// ArrayBinder is implemented by drivers that can bind a list of values as a single array
+	// parameter. It is used by WithArrayBinding to render IN lists.
+	ArrayBinder interface {
+		// ArrayMembership returns SQL that tests whether expr is an element of the array bound to
+		// placeholder, or is not an element when not is true.
+		ArrayMembership(expr, placeholder string, not bool) string
+	}
+
+	// Lowercaser is implemented by drivers whose LOWER function does not lowercase every character,
+	// e.g. ClickHouse's only handles ASCII. It is used by WithCaseInsensitiveEquality.
+	Lowercaser interface {
+		// Lower returns SQL that lowercases the value of expr.
+		Lower(expr string) string
+	}
+
+	// TextSearcher is implemented by drivers with full-text search, which render the MATCHES
+	// operator. Drivers implementing it should also support the FULLTEXT feature.
+	TextSearcher interface {
+		// MatchText returns SQL that tests whether the text in expr matches the search query bound
+		// to query, e.g. to_tsvector(expr) @@ plainto_tsquery(query).
+		MatchText(expr, query string) string
+	}
+
+	// HintRenderer is implemented by drivers that support query hints or settings. It is used by
+	// RenderHints, which validates the hints first.
+	HintRenderer interface {
+		// RenderHints returns the SQL placing the hints in a statement, or an error for hints the
+		// database does not support.
+		RenderHints(hints []Hint) (QueryHints, error)
+	}
+
+	// FunctionRestricter is implemented by drivers that reject calls to some functions instead of
+	// passing them through unchanged, e.g. drivers that only render known functions, or that reject
+	// functions the database does not implement.
+	FunctionRestricter interface {
+		// SupportsFunction returns true if calls to the upper-cased function can be rendered, either
+		// as written or through a registered translation.
+		SupportsFunction(name string) bool
+	}
+
+	// LikeEscaper is implemented by drivers whose LIKE has no default escape character, e.g.
+	// standard SQL. Since patterns escape wildcards with a backslash, the SQL builder declares it
+	// with an ESCAPE clause.
+	LikeEscaper interface {
+		// LikeEscape returns the SQL string literal of the escape character, e.g. '\'.
+		LikeEscape() string
+	}
+
+	// TimeLiteralRenderer is implemented by drivers whose databases do not convert bound values to the
+	// type of date and time columns, e.g. Athena, where comparing a DATE column with a string fails or
+	// silently mismatches. Values compared with fields declared as FieldTypeTime or FieldTypeDate are
+	// rendered as typed literals instead of being bound.
+	TimeLiteralRenderer interface {
+		// TimeLiteral returns the SQL literal of t for a column of the given type, e.g.
+		// DATE '2024-01-15' for FieldTypeDate.
+		TimeLiteral(t time.Time, typ FieldType) string
+	}
+
+	// Versioner is implemented by drivers configured for a specific server version, e.g. MySQL 5.7.
+	// Function translations registered with RegisterVersionedFunctionTemplate are chosen by the
+	// version.
+	Versioner interface {
+		// Version returns the server version, or the zero Version if it is unknown.
+		Version() Version
+	}
+
+	// CaseFolder is implemented by drivers whose databases fold the case of unquoted identifiers,
+	// e.g. PostgreSQL folds CreatedAt to createdat. It is used with Validator.CaseSensitiveFields to
+	// keep the case of mixed-case fields.
+	CaseFolder interface {
+		// QuoteExact quotes an identifier so the database matches its case exactly.
+		QuoteExact(name string) string
+	}
+
+	// IdentifierQuote is the character a driver encloses identifiers in, such as " or `. Quotes inside
+	// an identifier are doubled, or escaped with a backslash if Backslash is set, as in GoogleSQL.
+	IdentifierQuote struct {
+		Char      string
+		Backslash bool
+	}
 )
 
 // RegisterDriver registers a database driver with the given name.
 // This function is typically called from driver package init() functions.
 func RegisterDriver(name string, driver Driver) {
-	driversMu.Lock()
-	defer driversMu.Unlock()
-
-	if driver == nil {
-		panic("where: RegisterDriver driver is nil")
-	}
-	if name == "" {
-		panic("where: RegisterDriver name is empty")
-	}
+	defaultRegistry.Register(name, driver)
+}
 
-	drivers[name] = driver
+// UnregisterDriver removes the driver registered with the given name, returning false if there is
+// none. Other names of the driver are kept.
+func UnregisterDriver(name string) bool {
+	return defaultRegistry.Unregister(name)
 }
 
 // GetDriver retrieves a registered driver by name.
 // Returns an error if the driver is not found.
 func GetDriver(name string) (Driver, error) {
-	driversMu.RLock()
-	defer driversMu.RUnlock()
-
-	driver, ok := drivers[name]
-	if !ok {
-		return nil, errors.Errorf("driver %q not registered", name)
-	}
-	return driver, nil
+	return defaultRegistry.Get(name)
 }
 
 // ListDrivers returns a list of all registered driver names.
 func ListDrivers() []string {
-	driversMu.RLock()
-	defer driversMu.RUnlock()
-
-	names := make([]string, 0, len(drivers))
-	for name := range drivers {
-		names = append(names, name)
-	}
-	return names
+	return defaultRegistry.List()
 }
 
 // IsReservedKeyword determines if a word is a reserved keyword for the given driver.
@@ -119,3 +190,39 @@ func NeedsQuoting(name string, driver Driver) bool {
 
 	return false
 }
+
+// Quote encloses the identifier in quotes, escaping the quotes inside it.
+func (q IdentifierQuote) Quote(name string) string {
+	if q.Backslash {
+		return q.Char + strings.NewReplacer(`\`, `\\`, q.Char, `\`+q.Char).Replace(name) + q.Char
+	}
+	return q.Char + strings.ReplaceAll(name, q.Char, q.Char+q.Char) + q.Char
+}
+
+// Requote handles names that are already quoted for QuoteIdentifier implementations. A name enclosed
+// in q's quotes is unescaped and quoted again, so that a quote inside it can't end the identifier, and
+// is returned with true. A name enclosed in the other identifier quote, " or `, is returned without
+// its quotes, for the driver to quote like an unquoted name. Other names are returned unchanged.
+func (q IdentifierQuote) Requote(name string) (string, bool) {
+	if inner, ok := enclosedIn(name, q.Char); ok {
+		if q.Backslash {
+			return q.Quote(strings.NewReplacer(`\\`, `\`, `\`+q.Char, q.Char).Replace(inner)), true
+		}
+		return q.Quote(strings.ReplaceAll(inner, q.Char+q.Char, q.Char)), true
+	}
+
+	for _, other := range []string{`"`, "`"} {
+		if inner, ok := enclosedIn(name, other); ok && other != q.Char {
+			return inner, false
+		}
+	}
+	return name, false
+}
+
+// enclosedIn returns the name without its quotes if it starts and ends with quote.
+func enclosedIn(name, quote string) (string, bool) {
+	if len(name) < 2*len(quote) || !strings.HasPrefix(name, quote) || !strings.HasSuffix(name, quote) {
+		return "", false
+	}
+	return name[len(quote) : len(name)-len(quote)], true
+}
@@ -116,6 +116,42 @@ func TestDriverRegistry(t *testing.T) {
 	})
 }
 
+func TestIdentifierQuote(t *testing.T) {
+	double := where.IdentifierQuote{Char: `"`}
+	backtick := where.IdentifierQuote{Char: "`"}
+	backslash := where.IdentifierQuote{Char: "`", Backslash: true}
+
+	tests := []struct {
+		name       string
+		quote      where.IdentifierQuote
+		input      string
+		wantQuote  string
+		wantName   string
+		wantQuoted bool
+	}{
+		{name: "plain name", quote: double, input: "user name", wantQuote: `"user name"`, wantName: "user name"},
+		{name: "quote doubled", quote: double, input: `a"b`, wantQuote: `"a""b"`, wantName: `a"b`},
+		{name: "quoted name requoted", quote: double, input: `"a""b"`, wantQuote: `"""a""""b"""`, wantName: `"a""b"`, wantQuoted: true},
+		{name: "lone quote escaped", quote: double, input: `"a"b"`, wantQuote: `"""a""b"""`, wantName: `"a""b"`, wantQuoted: true},
+		{name: "other quote removed", quote: double, input: "`a.b`", wantQuote: "\"`a.b`\"", wantName: "a.b"},
+		{name: "backtick doubled", quote: backtick, input: "`a``b`", wantQuote: "```a````b```", wantName: "`a``b`", wantQuoted: true},
+		{name: "backtick other quote removed", quote: backtick, input: `"a"`, wantQuote: "`\"a\"`", wantName: "a"},
+		{name: "backslash escaped", quote: backslash, input: "a`b\\", wantQuote: "`a\\`b\\\\`", wantName: "a`b\\"},
+		{name: "backslash requoted", quote: backslash, input: "`a\\`b`", wantQuote: "`\\`a\\\\\\`b\\``", wantName: "`a\\`b`", wantQuoted: true},
+		{name: "single quote character", quote: double, input: `"`, wantQuote: `""""`, wantName: `"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantQuote, tt.quote.Quote(tt.input))
+
+			name, quoted := tt.quote.Requote(tt.input)
+			require.Equal(t, tt.wantQuoted, quoted)
+			require.Equal(t, tt.wantName, name)
+		})
+	}
+}
+
 // Helper function
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -0,0 +1,140 @@
+// Package ansi provides a driver that renders strictly standard SQL, for tools that pass the output
+// to further translation layers rather than to a particular database.
+package ansi
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pseudomuto/where"
+)
+
+var (
+	supportedFeatures = []string{
+		"BOOLEAN",
+		"CTE",
+		"WINDOW",
+	}
+
+	supportedOperations = []string{
+		"=", "<>", "<", ">", "<=", ">=",
+		"LIKE", "NOT LIKE",
+		"IN", "NOT IN",
+		"IS NULL", "IS NOT NULL",
+		"BETWEEN", "NOT BETWEEN",
+	}
+
+	identifierQuote = where.IdentifierQuote{Char: `"`}
+)
+
+type (
+	// ANSIDriver implements the where.Driver interface for standard SQL. Identifiers are quoted with
+	// double quotes, parameters use ? placeholders, and filters using functions or operators without
+	// a standard equivalent are rejected rather than rendered with vendor extensions.
+	ANSIDriver struct{}
+)
+
+// NewANSIDriver creates a new standard SQL driver instance.
+//
+// Example:
+//
+//	import (
+//		"github.com/pseudomuto/where"
+//		_ "github.com/pseudomuto/where/drivers/ansi"
+//	)
+//
+//	filter, _ := where.Parse("name ILIKE 'j%' AND status != 'closed'")
+//	sql, params, _ := filter.ToSQL("ansi")
+//	// (LOWER(name) LIKE LOWER(?) ESCAPE '\' AND status <> ?)
+func NewANSIDriver() *ANSIDriver {
+	return &ANSIDriver{}
+}
+
+func (d *ANSIDriver) Name() string {
+	return "ansi"
+}
+
+func (d *ANSIDriver) QuoteIdentifier(name string) string {
+	if name == "" {
+		return name
+	}
+
+	name, quoted := identifierQuote.Requote(strings.TrimSpace(name))
+	if quoted {
+		return name
+	}
+
+	if strings.Contains(name, ".") {
+		parts := strings.Split(name, ".")
+		quoted := make([]string, len(parts))
+		for i, part := range parts {
+			quoted[i] = d.quoteSimpleIdentifier(part)
+		}
+		return strings.Join(quoted, ".")
+	}
+
+	return d.quoteSimpleIdentifier(name)
+}
+
+func (d *ANSIDriver) quoteSimpleIdentifier(name string) string {
+	if where.NeedsQuoting(name, d) {
+		return d.QuoteExact(name)
+	}
+	return name
+}
+
+// QuoteExact always quotes the identifier, since standard SQL folds unquoted identifiers to upper
+// case.
+func (d *ANSIDriver) QuoteExact(name string) string {
+	return identifierQuote.Quote(name)
+}
+
+func (d *ANSIDriver) Placeholder(int) string {
+	return "?"
+}
+
+func (d *ANSIDriver) Keywords() []string {
+	return keywords
+}
+
+// TranslateOperator renders != as <>, NULL-safe equality as IS NOT DISTINCT FROM, and ILIKE as LIKE,
+// which the SQL builder applies to lowercased values.
+func (d *ANSIDriver) TranslateOperator(op string) (string, bool) {
+	upperOp := strings.ToUpper(op)
+	if slices.Contains(supportedOperations, upperOp) {
+		return upperOp, true
+	}
+
+	switch upperOp {
+	case "!=":
+		return "<>", true
+	case "<=>":
+		return "IS NOT DISTINCT FROM", true
+	case "ILIKE", "NOT ILIKE":
+		return strings.Replace(upperOp, "ILIKE", "LIKE", 1), true
+	}
+
+	return "", false
+}
+
+func (d *ANSIDriver) SupportsFeature(feature string) bool {
+	return slices.Contains(supportedFeatures, strings.ToUpper(feature))
+}
+
+// LikeEscape declares the backslash escaping LIKE wildcards, since standard SQL has no default escape
+// character.
+func (d *ANSIDriver) LikeEscape() string {
+	return `'\'`
+}
+
+// SupportsFunction returns true for standard SQL functions and for the portable functions with a
+// standard translation.
+func (d *ANSIDriver) SupportsFunction(name string) bool {
+	return slices.Contains(functions, name)
+}
+
+func init() {
+	driver := NewANSIDriver()
+	registerFunctions(driver.Name())
+	where.RegisterDriver("ansi", driver)
+}
@@ -0,0 +1,141 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/pseudomuto/where/drivers/ansi"
+	"github.com/pseudomuto/where/drivertest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestANSISQL(t *testing.T) {
+	tests := []struct {
+		name           string
+		expression     string
+		expectedSQL    string
+		expectedParams []any
+	}{
+		{
+			name:           "comparisons",
+			expression:     "age >= 18 AND status != 'closed' AND kind <> 'x'",
+			expectedSQL:    "(age >= ? AND status <> ? AND kind <> ?)",
+			expectedParams: []any{float64(18), "closed", "x"},
+		},
+		{
+			name:           "quoted identifiers",
+			expression:     "user.value = 1 AND position > 2",
+			expectedSQL:    `("user"."value" = ? AND "position" > ?)`,
+			expectedParams: []any{float64(1), float64(2)},
+		},
+		{
+			name:           "LIKE declares its escape character",
+			expression:     `name LIKE 'a\_%' AND email NOT LIKE '%spam%'`,
+			expectedSQL:    `(name LIKE ? ESCAPE '\' AND email NOT LIKE ? ESCAPE '\')`,
+			expectedParams: []any{`a\_%`, "%spam%"},
+		},
+		{
+			name:           "ILIKE",
+			expression:     "name ILIKE 'j%' OR name NOT ILIKE '%x'",
+			expectedSQL:    `(LOWER(name) LIKE LOWER(?) ESCAPE '\' OR LOWER(name) NOT LIKE LOWER(?) ESCAPE '\')`,
+			expectedParams: []any{"j%", "%x"},
+		},
+		{
+			name:           "NULL-safe equality",
+			expression:     "a <=> 1",
+			expectedSQL:    "a IS NOT DISTINCT FROM ?",
+			expectedParams: []any{float64(1)},
+		},
+		{
+			name:           "standard functions",
+			expression:     "UPPER(TRIM(name)) = 'X' AND COALESCE(score, 0) > ABS(-1)",
+			expectedSQL:    "(UPPER(TRIM(name)) = ? AND COALESCE(score, ?) > ABS(?))",
+			expectedParams: []any{"X", float64(0), float64(-1)},
+		},
+		{
+			name:           "translated functions",
+			expression:     "LENGTH(name) > 3 AND YEAR(created_at) = 2024 AND CONCAT(a, b, c) = 'x' AND SUBSTRING(code, 1, 2) = 'AB' AND created_at < NOW()",
+			expectedSQL:    "(CHAR_LENGTH(name) > ? AND EXTRACT(YEAR FROM created_at) = ? AND (a || b || c) = ? AND SUBSTRING(code FROM ? FOR ?) = ? AND created_at < CURRENT_TIMESTAMP)",
+			expectedParams: []any{float64(3), float64(2024), "x", float64(1), float64(2), "AB"},
+		},
+		{
+			name:           "IF",
+			expression:     "IF(age > 18, 'adult', 'minor') = 'adult'",
+			expectedSQL:    "CASE WHEN age > ? THEN ? ELSE ? END = ?",
+			expectedParams: []any{float64(18), "adult", "minor", "adult"},
+		},
+		{
+			name:           "CAST types",
+			expression:     "CAST(score AS STRING) = '1' AND CAST(created_at AS DATETIME) > '2024-01-01'",
+			expectedSQL:    "(CAST(score AS VARCHAR) = ? AND CAST(created_at AS TIMESTAMP) > ?)",
+			expectedParams: []any{"1", "2024-01-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.expression)
+			require.NoError(t, err)
+
+			sql, params, err := filter.ToSQL("ansi")
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedSQL, sql)
+			require.Equal(t, tt.expectedParams, params)
+		})
+	}
+}
+
+func TestANSIRejectsNonPortableSQL(t *testing.T) {
+	tests := []struct {
+		expression string
+		err        string
+	}{
+		{expression: "DATE_TRUNC('month', created_at) = '2024-01-01'", err: `function "DATE_TRUNC" not supported by driver ansi`},
+		{expression: "REGEXP(email, '^admin') = true", err: `function "REGEXP" not supported by driver ansi`},
+		{expression: "ROUND(score) = 1", err: `function "ROUND" not supported by driver ansi`},
+		{expression: "LOWER(JSON_EXTRACT(data, '$.a')) = 'x'", err: `function "JSON_EXTRACT" not supported by driver ansi`},
+		{expression: "flags & 4 = 4", err: "operator & not supported by driver ansi"},
+		{expression: "body MATCHES 'fox'", err: "operator MATCHES not supported by driver ansi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			filter, err := where.Parse(tt.expression)
+			require.NoError(t, err)
+
+			_, _, err = filter.ToSQL("ansi")
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestANSICaseSensitiveFields(t *testing.T) {
+	filter, err := where.Parse("CreatedAt > '2024-01-01'")
+	require.NoError(t, err)
+
+	validator := where.NewValidator().AllowFields("CreatedAt").CaseSensitiveFields()
+	sql, _, err := filter.ToSQL("ansi", where.WithValidator(validator))
+	require.NoError(t, err)
+	require.Equal(t, `"CreatedAt" > ?`, sql)
+}
+
+func TestANSIQuoteIdentifier(t *testing.T) {
+	driver := ansi.NewANSIDriver()
+
+	require.Equal(t, `"my field"`, driver.QuoteIdentifier(`"my field"`))
+	require.Equal(t, `"a""b"`, driver.QuoteIdentifier(`"a""b"`))
+	require.Equal(t, `"a"" OR 1=1 OR ""b"`, driver.QuoteIdentifier(`"a" OR 1=1 OR "b"`))
+	require.Equal(t, `""""`, driver.QuoteIdentifier(`"`))
+
+	filter, err := where.Parse("`\"a\" OR 1=1 OR \"b\"` = 1")
+	require.NoError(t, err)
+
+	sql, _, err := filter.ToSQL("ansi")
+	require.NoError(t, err)
+	require.Equal(t, `"a"" OR 1=1 OR ""b" = ?`, sql)
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.Run(t, ansi.NewANSIDriver(), drivertest.WithFeatures("BOOLEAN", "CTE", "WINDOW"))
+}
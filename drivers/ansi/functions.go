@@ -0,0 +1,33 @@
+package ansi
+
+import (
+	"strings"
+
+	"github.com/pseudomuto/where"
+)
+
+// functions lists the functions rendered for standard SQL: those defined by the standard and the
+// portable functions translated by registerFunctions. Calls to other functions are rejected.
+var functions = []string{
+	"ABS", "CAST", "CEIL", "CEILING", "CHARACTER_LENGTH", "CHAR_LENGTH", "COALESCE", "EXP", "FLOOR",
+	"LN", "LOWER", "MOD", "NULLIF", "OCTET_LENGTH", "POWER", "SQRT", "SUBSTRING", "TRIM", "UPPER",
+
+	// Translated portable functions.
+	"CONCAT", "DAY", "IF", "LENGTH", "MONTH", "NOW", "YEAR",
+}
+
+// registerFunctions registers translations for portable functions written differently in standard
+// SQL.
+func registerFunctions(driver string) {
+	where.RegisterFunctionTemplate(driver, "LENGTH", 1, "CHAR_LENGTH({0})")
+	where.RegisterFunctionTemplate(driver, "SUBSTRING", 2, "SUBSTRING({0} FROM {1})")
+	where.RegisterFunctionTemplate(driver, "SUBSTRING", 3, "SUBSTRING({0} FROM {1} FOR {2})")
+	where.RegisterFunctionTemplate(driver, "NOW", 0, "CURRENT_TIMESTAMP")
+	where.RegisterFunctionTemplate(driver, "YEAR", 1, "EXTRACT(YEAR FROM {0})")
+	where.RegisterFunctionTemplate(driver, "MONTH", 1, "EXTRACT(MONTH FROM {0})")
+	where.RegisterFunctionTemplate(driver, "DAY", 1, "EXTRACT(DAY FROM {0})")
+	where.RegisterFunctionTemplate(driver, "IF", 3, "CASE WHEN {0} THEN {1} ELSE {2} END")
+	where.RegisterFunctionTranslator(driver, "CONCAT", where.AnyArgs, func(args []string) (string, error) {
+		return "(" + strings.Join(args, " || ") + ")", nil
+	})
+}
@@ -0,0 +1,28 @@
+package ansi
+
+import (
+	"strings"
+)
+
+// types maps portable type names to standard SQL types. Other names are used as written.
+var types = map[string]string{
+	"BOOL":     "BOOLEAN",
+	"DATETIME": "TIMESTAMP",
+	"DOUBLE":   "DOUBLE PRECISION",
+	"INT":      "INTEGER",
+	"STRING":   "VARCHAR",
+	"TEXT":     "VARCHAR",
+	"TINYINT":  "SMALLINT",
+}
+
+// MapType translates portable type names in CAST expressions to standard SQL types.
+func (d *ANSIDriver) MapType(name string, params []string) (string, bool) {
+	mapped, ok := types[name]
+	if !ok {
+		return "", false
+	}
+	if len(params) == 0 {
+		return mapped, true
+	}
+	return mapped + "(" + strings.Join(params, ", ") + ")", true
+}
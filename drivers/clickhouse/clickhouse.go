@@ -11,7 +11,9 @@ import (
 var (
 	supportedFeatures = []string{
 		"ARRAY",
+		"BOOLEAN",
 		"FINAL",
+		"FULLTEXT",
 		"GLOBAL",
 		"ILIKE",
 		"JSON",
@@ -28,14 +30,54 @@ var (
 		"IS NULL", "IS NOT NULL",
 		"BETWEEN", "NOT BETWEEN",
 	}
+
+	identifierQuote = where.IdentifierQuote{Char: "`"}
 )
 
 type (
 	// ClickHouseDriver implements the where.Driver interface for ClickHouse databases.
-	ClickHouseDriver struct{}
+	ClickHouseDriver struct {
+		keywords []string
+	}
+
+	// Option configures a ClickHouseDriver.
+	Option func(*ClickHouseDriver)
 )
 
-// NewClickHouseDriver creates a new ClickHouse driver instance.
+// WithOptionalKeywords returns an Option that also quotes identifiers matching common type and
+// function names (DATE, ID, TIMESTAMP, USER) which ClickHouse otherwise accepts unquoted.
+func WithOptionalKeywords() Option {
+	return WithKeywords(optionalKeywords...)
+}
+
+// WithKeywords returns an Option that quotes identifiers matching any of the given words
+// (case-insensitive) in addition to the reserved keywords.
+func WithKeywords(words ...string) Option {
+	return func(d *ClickHouseDriver) {
+		for _, word := range words {
+			word = strings.ToUpper(word)
+			if !slices.Contains(d.keywords, word) {
+				d.keywords = append(d.keywords, word)
+			}
+		}
+	}
+}
+
+// WithoutKeywords returns an Option that stops quoting identifiers matching the given reserved
+// keywords (case-insensitive). Only use it for words known to be safe in your queries.
+func WithoutKeywords(words ...string) Option {
+	return func(d *ClickHouseDriver) {
+		d.keywords = slices.DeleteFunc(d.keywords, func(keyword string) bool {
+			return slices.ContainsFunc(words, func(word string) bool {
+				return strings.EqualFold(word, keyword)
+			})
+		})
+	}
+}
+
+// NewClickHouseDriver creates a new ClickHouse driver instance. The driver registered as
+// "clickhouse" uses the default options; register a configured instance under another name to
+// change which identifiers are quoted.
 //
 // Example:
 //
@@ -46,8 +88,14 @@ type (
 //
 //	filter, params, _ := where.Build("age > 18", "clickhouse")
 //	// SELECT * FROM users WHERE age > ?
-func NewClickHouseDriver() *ClickHouseDriver {
-	return &ClickHouseDriver{}
+//
+//	where.RegisterDriver("clickhouse-strict", clickhouse.NewClickHouseDriver(clickhouse.WithOptionalKeywords()))
+func NewClickHouseDriver(opts ...Option) *ClickHouseDriver {
+	d := &ClickHouseDriver{keywords: slices.Clone(keywords)}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
 func (d *ClickHouseDriver) Name() string {
@@ -59,14 +107,10 @@ func (d *ClickHouseDriver) QuoteIdentifier(name string) string {
 		return name
 	}
 
-	name = strings.TrimSpace(name)
-
-	if strings.HasPrefix(name, "`") && strings.HasSuffix(name, "`") {
+	name, quoted := identifierQuote.Requote(strings.TrimSpace(name))
+	if quoted {
 		return name
 	}
-	if strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
-		name = name[1 : len(name)-1]
-	}
 
 	if strings.Contains(name, ".") {
 		parts := strings.Split(name, ".")
@@ -82,7 +126,7 @@ func (d *ClickHouseDriver) QuoteIdentifier(name string) string {
 
 func (d *ClickHouseDriver) quoteSimpleIdentifier(name string) string {
 	if where.NeedsQuoting(name, d) {
-		return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``"))
+		return identifierQuote.Quote(name)
 	}
 	return name
 }
@@ -92,7 +136,10 @@ func (d *ClickHouseDriver) Placeholder(position int) string {
 }
 
 func (d *ClickHouseDriver) Keywords() []string {
-	return keywords
+	if d.keywords == nil {
+		return keywords
+	}
+	return d.keywords
 }
 
 func (d *ClickHouseDriver) TranslateOperator(op string) (string, bool) {
@@ -108,8 +155,42 @@ func (d *ClickHouseDriver) SupportsFeature(feature string) bool {
 	return slices.Contains(supportedFeatures, strings.ToUpper(feature))
 }
 
+// ArrayMembership renders array membership as has(?, expr) or NOT has(?, expr).
+func (d *ClickHouseDriver) ArrayMembership(expr, placeholder string, not bool) string {
+	if not {
+		return fmt.Sprintf("NOT has(%s, %s)", placeholder, expr)
+	}
+	return fmt.Sprintf("has(%s, %s)", placeholder, expr)
+}
+
+// Lower renders lowercasing with lowerUTF8, since lower only handles ASCII.
+func (d *ClickHouseDriver) Lower(expr string) string {
+	return "lowerUTF8(" + expr + ")"
+}
+
+// MatchText renders full-text search as hasAll(tokens(lowerUTF8(expr)), tokens(lowerUTF8(query))),
+// which matches rows containing every word of the query, ignoring case. Unlike hasToken, it accepts
+// queries with several words.
+func (d *ClickHouseDriver) MatchText(expr, query string) string {
+	return fmt.Sprintf("hasAll(tokens(%s), tokens(%s))", d.Lower(expr), d.Lower(query))
+}
+
+// RenderHints renders settings as a SETTINGS clause ending the statement. ClickHouse has no
+// optimizer hints.
+func (d *ClickHouseDriver) RenderHints(hints []where.Hint) (where.QueryHints, error) {
+	settings := make([]string, 0, len(hints))
+	for _, hint := range hints {
+		if hint.Kind != where.HintSetting {
+			return where.QueryHints{}, fmt.Errorf("optimizer hint %q is not supported by ClickHouse", hint.Name)
+		}
+		settings = append(settings, hint.Name+" = "+hint.SQLValue())
+	}
+	return where.QueryHints{Suffix: "SETTINGS " + strings.Join(settings, ", ")}, nil
+}
+
 func init() {
 	driver := NewClickHouseDriver()
+	registerFunctions(driver.Name())
 	where.RegisterDriver("clickhouse", driver)
 	where.RegisterDriver("ch", driver)
 }
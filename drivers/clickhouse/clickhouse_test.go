@@ -4,8 +4,8 @@ import (
 	"testing"
 
 	"github.com/pseudomuto/where"
-	// Import the ClickHouse driver
-	_ "github.com/pseudomuto/where/drivers/clickhouse"
+	"github.com/pseudomuto/where/drivers/clickhouse"
+	"github.com/pseudomuto/where/drivertest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -26,7 +26,7 @@ func TestClickHouseFunctions(t *testing.T) {
 		{
 			name:           "toYYYYMMDD function",
 			expression:     "toYYYYMMDD(timestamp) >= 20240115",
-			expectedSQL:    "toYYYYMMDD(`timestamp`) >= ?",
+			expectedSQL:    "toYYYYMMDD(timestamp) >= ?",
 			expectedParams: []any{float64(20240115)},
 		},
 		{
@@ -66,7 +66,7 @@ func TestClickHouseFunctions(t *testing.T) {
 		{
 			name:           "toStartOfWeek function with timezone",
 			expression:     "toStartOfWeek(timestamp, 'UTC') >= '2024-01-01'",
-			expectedSQL:    "toStartOfWeek(`timestamp`, ?) >= ?",
+			expectedSQL:    "toStartOfWeek(timestamp, ?) >= ?",
 			expectedParams: []any{"UTC", "2024-01-01"},
 		},
 
@@ -92,7 +92,7 @@ func TestClickHouseFunctions(t *testing.T) {
 		{
 			name:           "leftPad function",
 			expression:     "leftPad(id, 10, '0') = '0000012345'",
-			expectedSQL:    "leftPad(`id`, ?, ?) = ?",
+			expectedSQL:    "leftPad(id, ?, ?) = ?",
 			expectedParams: []any{float64(10), "0", "0000012345"},
 		},
 
@@ -230,6 +230,12 @@ func TestClickHouseFunctions(t *testing.T) {
 			expectedSQL:    "(toYYYYMM(event_time) = ? AND has(tags, ?) = TRUE AND abs(revenue) > ? AND startsWith(domain(referrer), ?) = TRUE)",
 			expectedParams: []any{float64(202401), "conversion", float64(0), "google"},
 		},
+		{
+			name:           "within radius",
+			expression:     "WITHIN_RADIUS(location, 52.52, 13.405, 2500)",
+			expectedSQL:    "(greatCircleDistance(tupleElement(location, 1), tupleElement(location, 2), ?, ?) <= ?)",
+			expectedParams: []any{13.405, 52.52, float64(2500)},
+		},
 	}
 
 	for _, tt := range tests {
@@ -335,3 +341,48 @@ func TestClickHouseArrayOperations(t *testing.T) {
 		})
 	}
 }
+
+func TestClickHouseKeywordOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []clickhouse.Option
+		expectedSQL string
+	}{
+		{
+			name:        "default",
+			expectedSQL: "(id = ? AND date = ? AND user = ? AND `order` = ?)",
+		},
+		{
+			name:        "optional keywords",
+			opts:        []clickhouse.Option{clickhouse.WithOptionalKeywords()},
+			expectedSQL: "(`id` = ? AND `date` = ? AND `user` = ? AND `order` = ?)",
+		},
+		{
+			name:        "custom keywords",
+			opts:        []clickhouse.Option{clickhouse.WithKeywords("user"), clickhouse.WithoutKeywords("Order")},
+			expectedSQL: "(id = ? AND date = ? AND `user` = ? AND order = ?)",
+		},
+	}
+
+	filter, err := where.Parse("id = 1 AND date = '2024-01-01' AND user = 'x' AND order = 2")
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where.RegisterDriver("clickhouse-test", clickhouse.NewClickHouseDriver(tt.opts...))
+
+			sql, _, err := filter.ToSQL("clickhouse-test")
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedSQL, sql)
+		})
+	}
+
+	// Options only affect the configured instance.
+	sql, _, err := filter.ToSQL("clickhouse")
+	require.NoError(t, err)
+	require.Equal(t, tests[0].expectedSQL, sql)
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.Run(t, clickhouse.NewClickHouseDriver(), drivertest.WithFeatures("ARRAY", "BOOLEAN", "FINAL", "FULLTEXT", "GLOBAL", "ILIKE", "JSON", "PREWHERE", "SAMPLE", "TUPLE", "WITH"))
+}
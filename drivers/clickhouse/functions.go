@@ -0,0 +1,17 @@
+package clickhouse
+
+import (
+	"github.com/pseudomuto/where"
+)
+
+// withinRadiusTemplate renders WITHIN_RADIUS(location, lat, lng, meters) for a Point column, whose
+// first element is the longitude.
+const withinRadiusTemplate = "(greatCircleDistance(tupleElement({0}, 1), tupleElement({0}, 2), {2}, {1}) <= {3})"
+
+// registerFunctions registers translations for portable functions without a native ClickHouse
+// equivalent. Most standard SQL functions are case-insensitive aliases in ClickHouse.
+func registerFunctions(driver string) {
+	where.RegisterFunctionTemplate(driver, "LENGTH", 1, "lengthUTF8({0})")
+	where.RegisterFunctionTemplate(driver, "REGEXP", 2, "match({0}, {1})")
+	where.RegisterFunctionTemplate(driver, "WITHIN_RADIUS", 4, withinRadiusTemplate)
+}
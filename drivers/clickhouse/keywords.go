@@ -23,7 +23,10 @@ var keywords = []string{
 	"ARRAY", "CLUSTER", "DATABASE", "DICTIONARY", "ENGINE", "FINAL", "FORMAT", "GLOBAL",
 	"ILIKE", "MATERIALIZED", "PARTITION", "PREWHERE", "PRIMARY", "SAMPLE",
 	"SETTINGS", "SYSTEM", "TEMPORARY", "TTL", "WATCH",
+}
 
-	// Additional keywords expected by tests
+// optionalKeywords are type and function names that ClickHouse accepts as unquoted identifiers.
+// They are extremely common column names, so they are only quoted when WithOptionalKeywords is used.
+var optionalKeywords = []string{
 	"DATE", "ID", "TIMESTAMP", "USER",
 }
@@ -0,0 +1,84 @@
+package clickhouse
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/where"
+)
+
+// sampleBuckets is the number of hash buckets Sample assigns rows to, so rates are applied in steps
+// of 0.0001.
+const sampleBuckets = 10000
+
+// Sample returns a filter matching a deterministic fraction of rows by hashing the field, rendered
+// as modulo(cityHash64(field), 10000) < threshold. Every query sampling the same field at the same
+// rate keeps the same keys, so dashboards downsampled this way stay consistent with each other. The
+// rate must be between 0.0001 and 1. Combine it with user filters using where.WithRequiredFilter.
+//
+// Example:
+//
+//	sample, _ := clickhouse.Sample("user_id", 0.1)
+//	sql, params, _ := filter.ToSQL("clickhouse", where.WithRequiredFilter(sample))
+//	// (... AND modulo(cityHash64(user_id), ?) < ?) with params [... 10000 1000]
+func Sample(field string, rate float64) (*where.Filter, error) {
+	threshold, err := sampleThreshold(rate)
+	if err != nil {
+		return nil, err
+	}
+	if field == "" {
+		return nil, errors.New("sample field is empty")
+	}
+
+	hash := &where.FunctionCall{
+		Name: "cityHash64",
+		Args: []*where.Value{{Field: &where.FieldRef{Parts: strings.Split(field, ".")}}},
+	}
+	bucket := &where.FunctionCall{
+		Name: "modulo",
+		Args: []*where.Value{{Function: hash}, number(sampleBuckets)},
+	}
+	pred := &where.Predicate{
+		Left: &where.Value{Function: bucket},
+		Operation: &where.Operation{Compare: &where.CompareOp{
+			Operator: where.CompareOperator{Type: "<"},
+			Right:    number(threshold),
+		}},
+	}
+	return &where.Filter{Expression: &where.Expression{Or: []*where.Term{{And: []*where.Factor{{Predicate: pred}}}}}}, nil
+}
+
+// SampleClause returns a SAMPLE clause reading the given fraction of a table, e.g. "SAMPLE 0.1",
+// for tables declared with SAMPLE BY. Place it after the table name in the FROM clause. The rate
+// must be greater than 0 and at most 1.
+//
+// Example:
+//
+//	sample, _ := clickhouse.SampleClause(0.1)
+//	query := "SELECT count() FROM events " + sample + " WHERE " + sql
+func SampleClause(rate float64) (string, error) {
+	if math.IsNaN(rate) || rate <= 0 || rate > 1 {
+		return "", fmt.Errorf("sampling rate %v must be greater than 0 and at most 1", rate)
+	}
+	return "SAMPLE " + strconv.FormatFloat(rate, 'f', -1, 64), nil
+}
+
+// sampleThreshold returns the number of buckets sampled at the rate.
+func sampleThreshold(rate float64) (float64, error) {
+	if math.IsNaN(rate) || rate <= 0 || rate > 1 {
+		return 0, fmt.Errorf("sampling rate %v must be greater than 0 and at most 1", rate)
+	}
+
+	threshold := math.Round(rate * sampleBuckets)
+	if threshold < 1 {
+		return 0, fmt.Errorf("sampling rate %v is below the minimum of %v", rate, 1.0/sampleBuckets)
+	}
+	return threshold, nil
+}
+
+func number(n float64) *where.Value {
+	return &where.Value{Literal: &where.LiteralValue{Number: &n}}
+}
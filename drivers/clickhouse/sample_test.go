@@ -0,0 +1,48 @@
+package clickhouse_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/pseudomuto/where/drivers/clickhouse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSample(t *testing.T) {
+	filter, err := where.Parse("event_type = 'click'")
+	require.NoError(t, err)
+
+	sample, err := clickhouse.Sample("e.user_id", 0.25)
+	require.NoError(t, err)
+
+	sql, params, err := filter.ToSQL("clickhouse", where.WithRequiredFilter(sample))
+	require.NoError(t, err)
+	require.Equal(t, "(event_type = ? AND modulo(cityHash64(e.user_id), ?) < ?)", sql)
+	require.Equal(t, []any{"click", float64(10000), float64(2500)}, params)
+
+	require.Equal(t, "modulo(cityHash64(e.user_id), 10000) < 2500", sample.String())
+}
+
+func TestSampleErrors(t *testing.T) {
+	for _, rate := range []float64{0, -0.5, 1.5, math.NaN(), 0.00001} {
+		_, err := clickhouse.Sample("user_id", rate)
+		require.Error(t, err, "rate %v", rate)
+	}
+
+	_, err := clickhouse.Sample("", 0.5)
+	require.EqualError(t, err, "sample field is empty")
+}
+
+func TestSampleClause(t *testing.T) {
+	clause, err := clickhouse.SampleClause(0.1)
+	require.NoError(t, err)
+	require.Equal(t, "SAMPLE 0.1", clause)
+
+	clause, err = clickhouse.SampleClause(1)
+	require.NoError(t, err)
+	require.Equal(t, "SAMPLE 1", clause)
+
+	_, err = clickhouse.SampleClause(0)
+	require.EqualError(t, err, "sampling rate 0 must be greater than 0 and at most 1")
+}
@@ -0,0 +1,44 @@
+package clickhouse
+
+import (
+	"strings"
+)
+
+// types maps portable type names to ClickHouse types, which are case-sensitive. Other names are
+// used as written.
+var types = map[string]string{
+	"BIGINT":           "Int64",
+	"BOOL":             "Bool",
+	"BOOLEAN":          "Bool",
+	"CHAR":             "String",
+	"DATE":             "Date",
+	"DATETIME":         "DateTime",
+	"DECIMAL":          "Decimal",
+	"DOUBLE":           "Float64",
+	"DOUBLE PRECISION": "Float64",
+	"FLOAT":            "Float32",
+	"INT":              "Int32",
+	"INTEGER":          "Int32",
+	"NUMERIC":          "Decimal",
+	"REAL":             "Float32",
+	"SMALLINT":         "Int16",
+	"STRING":           "String",
+	"TEXT":             "String",
+	"TIMESTAMP":        "DateTime",
+	"TINYINT":          "Int8",
+	"UUID":             "UUID",
+	"VARCHAR":          "String",
+}
+
+// MapType translates portable type names in CAST expressions to ClickHouse types. Parameters are
+// kept only for Decimal, since string types in ClickHouse have no length.
+func (d *ClickHouseDriver) MapType(name string, params []string) (string, bool) {
+	mapped, ok := types[name]
+	if !ok {
+		return "", false
+	}
+	if mapped == "Decimal" && len(params) > 0 {
+		return mapped + "(" + strings.Join(params, ", ") + ")", true
+	}
+	return mapped, true
+}
@@ -0,0 +1,33 @@
+package mysql
+
+import (
+	"github.com/pseudomuto/where"
+)
+
+// dateTruncTemplate emulates DATE_TRUNC(unit, value), which MySQL lacks. Weeks start on Monday.
+const dateTruncTemplate = "CASE LOWER({0})" +
+	" WHEN 'second' THEN CAST(DATE_FORMAT({1}, '%Y-%m-%d %H:%i:%s') AS DATETIME)" +
+	" WHEN 'minute' THEN CAST(DATE_FORMAT({1}, '%Y-%m-%d %H:%i:00') AS DATETIME)" +
+	" WHEN 'hour' THEN CAST(DATE_FORMAT({1}, '%Y-%m-%d %H:00:00') AS DATETIME)" +
+	" WHEN 'day' THEN CAST(DATE({1}) AS DATETIME)" +
+	" WHEN 'week' THEN CAST(DATE({1}) - INTERVAL WEEKDAY({1}) DAY AS DATETIME)" +
+	" WHEN 'month' THEN CAST(DATE_FORMAT({1}, '%Y-%m-01') AS DATETIME)" +
+	" WHEN 'quarter' THEN CAST(MAKEDATE(YEAR({1}), 1) + INTERVAL QUARTER({1}) - 1 QUARTER AS DATETIME)" +
+	" WHEN 'year' THEN CAST(DATE_FORMAT({1}, '%Y-01-01') AS DATETIME)" +
+	" END"
+
+// withinRadiusTemplate renders WITHIN_RADIUS(location, lat, lng, meters) for a POINT column storing
+// longitude as X and latitude as Y.
+const withinRadiusTemplate = "(ST_Distance_Sphere({0}, POINT({2}, {1})) <= {3})"
+
+// registerFunctions registers translations for portable functions without a native MySQL equivalent.
+func registerFunctions(driver string) {
+	where.RegisterFunctionTemplate(driver, "LENGTH", 1, "CHAR_LENGTH({0})")
+	where.RegisterFunctionTemplate(driver, "REGEXP", 2, "({0} REGEXP {1})")
+	where.RegisterFunctionTemplate(driver, "DATE_TRUNC", 2, dateTruncTemplate)
+	where.RegisterFunctionTemplate(driver, "WITHIN_RADIUS", 4, withinRadiusTemplate)
+
+	// JSON_VALUE was added in 8.0.21, and is emulated before it.
+	where.RegisterFunctionTemplate(driver, "JSON_VALUE", 2, "JSON_UNQUOTE(JSON_EXTRACT({0}, {1}))")
+	where.RegisterVersionedFunctionTemplate(driver, "JSON_VALUE", 2, jsonValueVersion, "JSON_VALUE({0}, {1})")
+}
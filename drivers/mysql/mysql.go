@@ -8,8 +8,13 @@ import (
 	"github.com/pseudomuto/where"
 )
 
+// maxParams is the limit on placeholders in a single prepared statement.
+const maxParams = 65535
+
 var (
 	supportedFeatures = []string{
+		"BITWISE",
+		"BOOLEAN",
 		"CTE",
 		"FULLTEXT",
 		"JSON",
@@ -18,19 +23,46 @@ var (
 	}
 
 	supportedOperations = []string{
-		"=", "!=", "<>", "<", ">", "<=", ">=",
+		"=", "!=", "<>", "<", ">", "<=", ">=", "<=>",
 		"LIKE", "NOT LIKE",
 		"IN", "NOT IN",
 		"IS NULL", "IS NOT NULL",
 		"BETWEEN", "NOT BETWEEN",
+		"&", "|", "^", "<<", ">>",
 	}
+
+	identifierQuote = where.IdentifierQuote{Char: "`"}
+)
+
+var (
+	// cteVersion is the first version supporting common table expressions.
+	cteVersion = where.Version{Major: 8}
+
+	// setVarVersion is the first version supporting the SET_VAR optimizer hint.
+	setVarVersion = where.Version{Major: 8, Patch: 3}
+
+	// jsonValueVersion is the first version with JSON_VALUE.
+	jsonValueVersion = where.Version{Major: 8, Patch: 21}
 )
 
 type (
 	// MySQLDriver implements the where.Driver interface for MySQL and MariaDB databases.
-	MySQLDriver struct{}
+	MySQLDriver struct {
+		version where.Version
+	}
+
+	// Option configures a MySQLDriver.
+	Option func(*MySQLDriver)
 )
 
+// WithVersion returns an Option that targets the given server version, e.g. 5.7, so features and
+// functions the version lacks are emulated or rejected. By default the newest version is targeted.
+func WithVersion(version where.Version) Option {
+	return func(d *MySQLDriver) {
+		d.version = version
+	}
+}
+
 // NewMySQLDriver creates a new MySQL driver instance.
 //
 // Example:
@@ -42,8 +74,14 @@ type (
 //
 //	filter, params, _ := where.Build("age > 18", "mysql")
 //	// SELECT * FROM users WHERE age > ?
-func NewMySQLDriver() *MySQLDriver {
-	return &MySQLDriver{}
+//
+//	where.RegisterDriver("mysql57", mysql.NewMySQLDriver(mysql.WithVersion(where.Version{Major: 5, Minor: 7})))
+func NewMySQLDriver(opts ...Option) *MySQLDriver {
+	d := &MySQLDriver{}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
 func (d *MySQLDriver) Name() string {
@@ -55,14 +93,10 @@ func (d *MySQLDriver) QuoteIdentifier(name string) string {
 		return name
 	}
 
-	name = strings.TrimSpace(name)
-
-	if strings.HasPrefix(name, "`") && strings.HasSuffix(name, "`") {
+	name, quoted := identifierQuote.Requote(strings.TrimSpace(name))
+	if quoted {
 		return name
 	}
-	if strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
-		name = name[1 : len(name)-1]
-	}
 
 	if strings.Contains(name, ".") {
 		parts := strings.Split(name, ".")
@@ -78,7 +112,7 @@ func (d *MySQLDriver) QuoteIdentifier(name string) string {
 
 func (d *MySQLDriver) quoteSimpleIdentifier(name string) string {
 	if where.NeedsQuoting(name, d) {
-		return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``"))
+		return identifierQuote.Quote(name)
 	}
 	return name
 }
@@ -105,11 +139,50 @@ func (d *MySQLDriver) TranslateOperator(op string) (string, bool) {
 }
 
 func (d *MySQLDriver) SupportsFeature(feature string) bool {
-	return slices.Contains(supportedFeatures, strings.ToUpper(feature))
+	feature = strings.ToUpper(feature)
+	if feature == "CTE" && !d.version.AtLeast(cteVersion) {
+		return false
+	}
+	return slices.Contains(supportedFeatures, feature)
+}
+
+// Version returns the targeted server version, or the zero Version for the newest.
+func (d *MySQLDriver) Version() where.Version {
+	return d.version
+}
+
+// MatchText renders full-text search as MATCH (expr) AGAINST (query IN NATURAL LANGUAGE MODE),
+// which requires a FULLTEXT index on the column.
+func (d *MySQLDriver) MatchText(expr, query string) string {
+	return fmt.Sprintf("MATCH (%s) AGAINST (%s IN NATURAL LANGUAGE MODE)", expr, query)
+}
+
+// RenderHints renders hints as an optimizer hint comment following SELECT, with settings as
+// SET_VAR hints, e.g. /*+ MAX_EXECUTION_TIME(1000) SET_VAR(sort_buffer_size = 16777216) */.
+// Settings require MySQL 8.0.3.
+func (d *MySQLDriver) RenderHints(hints []where.Hint) (where.QueryHints, error) {
+	parts := make([]string, len(hints))
+	for i, hint := range hints {
+		if hint.Kind == where.HintSetting {
+			if !d.version.AtLeast(setVarVersion) {
+				return where.QueryHints{}, fmt.Errorf("setting %q requires MySQL %s", hint.Name, setVarVersion)
+			}
+			parts[i] = fmt.Sprintf("SET_VAR(%s = %s)", hint.Name, hint.SQLValue())
+		} else {
+			parts[i] = hint.Name
+		}
+	}
+	return where.QueryHints{Prefix: "/*+ " + strings.Join(parts, " ") + " */"}, nil
+}
+
+// MaxParams returns the maximum number of placeholders in a MySQL prepared statement.
+func (d *MySQLDriver) MaxParams() int {
+	return maxParams
 }
 
 func init() {
 	driver := NewMySQLDriver()
+	registerFunctions(driver.Name())
 	where.RegisterDriver("mysql", driver)
 	where.RegisterDriver("mariadb", driver)
 }
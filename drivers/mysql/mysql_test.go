@@ -1,11 +1,12 @@
 package mysql_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/pseudomuto/where"
-	// Import the MySQL driver
-	_ "github.com/pseudomuto/where/drivers/mysql"
+	"github.com/pseudomuto/where/drivers/mysql"
+	"github.com/pseudomuto/where/drivertest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -131,7 +132,12 @@ func TestMySQLFunctions(t *testing.T) {
 		},
 
 		// Conditional functions
-		// Note: IF is not supported as it requires complex conditional expression parsing
+		{
+			name:           "IF function",
+			expression:     "IF(score >= 50, 'pass', 'fail') = 'pass'",
+			expectedSQL:    "IF(score >= ?, ?, ?) = ?",
+			expectedParams: []any{float64(50), "pass", "fail", "pass"},
+		},
 		{
 			name:           "IFNULL function",
 			expression:     "IFNULL(nickname, username) != ''",
@@ -248,3 +254,77 @@ func TestMySQLILIKETranslation(t *testing.T) {
 		})
 	}
 }
+
+func TestMySQLDateTrunc(t *testing.T) {
+	filter, err := where.Parse("DATE_TRUNC('month', created_at) = '2024-01-01'")
+	require.NoError(t, err)
+
+	sql, params, err := filter.ToSQL("mysql")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(sql, "CASE LOWER(?) WHEN 'second' THEN "), sql)
+	require.Contains(t, sql, " WHEN 'month' THEN CAST(DATE_FORMAT(created_at, '%Y-%m-01') AS DATETIME)")
+	require.True(t, strings.HasSuffix(sql, " END = ?"), sql)
+	require.Equal(t, []any{"month", "2024-01-01"}, params)
+}
+
+func TestMySQLWithinRadius(t *testing.T) {
+	filter, err := where.Parse("WITHIN_RADIUS(location, 52.52, 13.405, 2500)")
+	require.NoError(t, err)
+
+	sql, params, err := filter.ToSQL("mysql")
+	require.NoError(t, err)
+	require.Equal(t, "(ST_Distance_Sphere(location, POINT(?, ?)) <= ?)", sql)
+	require.Equal(t, []any{13.405, 52.52, float64(2500)}, params)
+}
+
+func TestMySQLVersion(t *testing.T) {
+	where.RegisterDriver("mysql57", mysql.NewMySQLDriver(mysql.WithVersion(where.Version{Major: 5, Minor: 7})))
+
+	filter, err := where.Parse("JSON_VALUE(data, '$.id') = '42'")
+	require.NoError(t, err)
+
+	sql, params, err := filter.ToSQL("mysql57")
+	require.NoError(t, err)
+	require.Equal(t, "JSON_UNQUOTE(JSON_EXTRACT(data, ?)) = ?", sql)
+	require.Equal(t, []any{"$.id", "42"}, params)
+
+	sql, _, err = filter.ToSQL("mysql")
+	require.NoError(t, err)
+	require.Equal(t, "JSON_VALUE(data, ?) = ?", sql)
+
+	tests := []struct {
+		version  where.Version
+		cte      bool
+		setVar   bool
+		function string
+	}{
+		{where.Version{}, true, true, "JSON_VALUE"},
+		{where.Version{Major: 5, Minor: 7}, false, false, "JSON_UNQUOTE"},
+		{where.Version{Major: 8}, true, false, "JSON_UNQUOTE"},
+		{where.Version{Major: 8, Patch: 21}, true, true, "JSON_VALUE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version.String(), func(t *testing.T) {
+			driver := mysql.NewMySQLDriver(mysql.WithVersion(tt.version))
+			require.Equal(t, tt.version, driver.Version())
+			require.Equal(t, tt.cte, driver.SupportsFeature("cte"))
+
+			_, err := driver.RenderHints([]where.Hint{where.Setting("sort_buffer_size", 1024)})
+			if tt.setVar {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, `setting "sort_buffer_size" requires MySQL 8.0.3`)
+			}
+
+			where.RegisterDriver("mysql-version-test", driver)
+			sql, _, err := filter.ToSQL("mysql-version-test")
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(sql, tt.function+"("), sql)
+		})
+	}
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.Run(t, mysql.NewMySQLDriver(), drivertest.WithFeatures("BITWISE", "BOOLEAN", "CTE", "FULLTEXT", "JSON", "PARTITION", "SPATIAL"))
+}
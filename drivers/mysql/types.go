@@ -0,0 +1,37 @@
+package mysql
+
+import (
+	"strings"
+)
+
+// types maps portable type names to the limited set of types MySQL accepts in CAST expressions.
+// Other names are used as written.
+var types = map[string]string{
+	"BIGINT":    "SIGNED",
+	"BOOL":      "SIGNED",
+	"BOOLEAN":   "SIGNED",
+	"INT":       "SIGNED",
+	"INTEGER":   "SIGNED",
+	"NUMERIC":   "DECIMAL",
+	"REAL":      "DOUBLE",
+	"SMALLINT":  "SIGNED",
+	"STRING":    "CHAR",
+	"TEXT":      "CHAR",
+	"TIMESTAMP": "DATETIME",
+	"TINYINT":   "SIGNED",
+	"UUID":      "CHAR(36)",
+	"VARCHAR":   "CHAR",
+}
+
+// MapType translates portable type names in CAST expressions to MySQL cast targets.
+// Integer types become SIGNED, which does not accept a display width.
+func (d *MySQLDriver) MapType(name string, params []string) (string, bool) {
+	mapped, ok := types[name]
+	if !ok {
+		return "", false
+	}
+	if mapped == "SIGNED" || strings.Contains(mapped, "(") || len(params) == 0 {
+		return mapped, true
+	}
+	return mapped + "(" + strings.Join(params, ", ") + ")", true
+}
@@ -0,0 +1,20 @@
+package postgres
+
+import (
+	"github.com/pseudomuto/where"
+)
+
+// withinRadiusTemplate renders WITHIN_RADIUS(location, lat, lng, meters) with PostGIS, comparing
+// geographies so the distance is in meters and a geography index on the column can be used.
+const withinRadiusTemplate = "ST_DWithin(CAST({0} AS geography)," +
+	" CAST(ST_SetSRID(ST_MakePoint({2}, {1}), 4326) AS geography), {3})"
+
+// registerFunctions registers translations for portable functions without a native PostgreSQL equivalent.
+func registerFunctions(driver string) {
+	where.RegisterFunctionTemplate(driver, "YEAR", 1, "EXTRACT(YEAR FROM {0})")
+	where.RegisterFunctionTemplate(driver, "MONTH", 1, "EXTRACT(MONTH FROM {0})")
+	where.RegisterFunctionTemplate(driver, "DAY", 1, "EXTRACT(DAY FROM {0})")
+	where.RegisterFunctionTemplate(driver, "REGEXP", 2, "({0} ~ {1})")
+	where.RegisterFunctionTemplate(driver, "IF", 3, "CASE WHEN {0} THEN {1} ELSE {2} END")
+	where.RegisterFunctionTemplate(driver, "WITHIN_RADIUS", 4, withinRadiusTemplate)
+}
@@ -8,10 +8,16 @@ import (
 	"github.com/pseudomuto/where"
 )
 
+// maxParams is the protocol limit on bind parameters in a single statement.
+const maxParams = 65535
+
 var (
 	supportedFeatures = []string{
 		"ARRAY",
+		"BITWISE",
+		"BOOLEAN",
 		"CTE",
+		"FULLTEXT",
 		"ILIKE",
 		"JSON",
 		"JSONB",
@@ -25,14 +31,42 @@ var (
 		"IN", "NOT IN",
 		"IS NULL", "IS NOT NULL",
 		"BETWEEN", "NOT BETWEEN",
+		"&", "|", "<<", ">>",
 	}
+
+	identifierQuote = where.IdentifierQuote{Char: `"`}
 )
 
 type (
 	// PostgreSQLDriver implements the where.Driver interface for PostgreSQL databases.
-	PostgreSQLDriver struct{}
+	PostgreSQLDriver struct {
+		textSearchConfig string
+		version          where.Version
+	}
+
+	// Option configures a PostgreSQLDriver.
+	Option func(*PostgreSQLDriver)
 )
 
+// WithTextSearchConfig returns an Option that passes the named text search configuration (e.g.
+// "english") to to_tsvector and plainto_tsquery when rendering MATCHES. The calls are then
+// immutable, so a GIN index on to_tsvector('english', column) can be used. By default the server's
+// default_text_search_config is used.
+func WithTextSearchConfig(name string) Option {
+	return func(d *PostgreSQLDriver) {
+		d.textSearchConfig = name
+	}
+}
+
+// WithVersion returns an Option that targets the given server version, e.g. 12, so translations
+// registered with where.RegisterVersionedFunctionTemplate are chosen for it. By default the newest
+// version is targeted.
+func WithVersion(version where.Version) Option {
+	return func(d *PostgreSQLDriver) {
+		d.version = version
+	}
+}
+
 // NewPostgreSQLDriver creates a new PostgreSQL driver instance.
 //
 // Example:
@@ -44,8 +78,14 @@ type (
 //
 //	filter, params, _ := where.Build("age > 18", "postgres")
 //	// SELECT * FROM users WHERE age > $1
-func NewPostgreSQLDriver() *PostgreSQLDriver {
-	return &PostgreSQLDriver{}
+//
+//	where.RegisterDriver("postgres-en", postgres.NewPostgreSQLDriver(postgres.WithTextSearchConfig("english")))
+func NewPostgreSQLDriver(opts ...Option) *PostgreSQLDriver {
+	d := &PostgreSQLDriver{}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
 func (d *PostgreSQLDriver) Name() string {
@@ -57,14 +97,10 @@ func (d *PostgreSQLDriver) QuoteIdentifier(name string) string {
 		return name
 	}
 
-	name = strings.TrimSpace(name)
-
-	if strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
+	name, quoted := identifierQuote.Requote(strings.TrimSpace(name))
+	if quoted {
 		return name
 	}
-	if strings.HasPrefix(name, "`") && strings.HasSuffix(name, "`") {
-		name = name[1 : len(name)-1]
-	}
 
 	if strings.Contains(name, ".") {
 		parts := strings.Split(name, ".")
@@ -80,11 +116,21 @@ func (d *PostgreSQLDriver) QuoteIdentifier(name string) string {
 
 func (d *PostgreSQLDriver) quoteSimpleIdentifier(name string) string {
 	if where.NeedsQuoting(name, d) {
-		return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+		return identifierQuote.Quote(name)
 	}
 	return name
 }
 
+// Version returns the targeted server version, or the zero Version for the newest.
+func (d *PostgreSQLDriver) Version() where.Version {
+	return d.version
+}
+
+// QuoteExact always quotes the identifier, since PostgreSQL folds unquoted identifiers to lower case.
+func (d *PostgreSQLDriver) QuoteExact(name string) string {
+	return identifierQuote.Quote(name)
+}
+
 func (d *PostgreSQLDriver) Placeholder(position int) string {
 	return fmt.Sprintf("$%d", position)
 }
@@ -99,6 +145,15 @@ func (d *PostgreSQLDriver) TranslateOperator(op string) (string, bool) {
 		return upperOp, true
 	}
 
+	if upperOp == "<=>" {
+		return "IS NOT DISTINCT FROM", true
+	}
+
+	// ^ is exponentiation in PostgreSQL, which writes bitwise XOR as #.
+	if upperOp == "^" {
+		return "#", true
+	}
+
 	return "", false
 }
 
@@ -106,8 +161,33 @@ func (d *PostgreSQLDriver) SupportsFeature(feature string) bool {
 	return slices.Contains(supportedFeatures, strings.ToUpper(feature))
 }
 
+// MaxParams returns the maximum number of bind parameters in a PostgreSQL statement.
+func (d *PostgreSQLDriver) MaxParams() int {
+	return maxParams
+}
+
+// ArrayMembership renders array membership as expr = ANY($n) or expr <> ALL($n).
+func (d *PostgreSQLDriver) ArrayMembership(expr, placeholder string, not bool) string {
+	if not {
+		return fmt.Sprintf("%s <> ALL(%s)", expr, placeholder)
+	}
+	return fmt.Sprintf("%s = ANY(%s)", expr, placeholder)
+}
+
+// MatchText renders full-text search as to_tsvector(expr) @@ plainto_tsquery(query), which matches
+// rows containing every word of the query.
+func (d *PostgreSQLDriver) MatchText(expr, query string) string {
+	if d.textSearchConfig == "" {
+		return fmt.Sprintf("to_tsvector(%s) @@ plainto_tsquery(%s)", expr, query)
+	}
+
+	config := "'" + strings.ReplaceAll(d.textSearchConfig, "'", "''") + "'"
+	return fmt.Sprintf("to_tsvector(%s, %s) @@ plainto_tsquery(%s, %s)", config, expr, config, query)
+}
+
 func init() {
 	driver := NewPostgreSQLDriver()
+	registerFunctions(driver.Name())
 	where.RegisterDriver("postgres", driver)
 	where.RegisterDriver("postgresql", driver)
 	where.RegisterDriver("pg", driver)
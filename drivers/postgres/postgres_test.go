@@ -4,8 +4,8 @@ import (
 	"testing"
 
 	"github.com/pseudomuto/where"
-	// Import the PostgreSQL driver
-	_ "github.com/pseudomuto/where/drivers/postgres"
+	"github.com/pseudomuto/where/drivers/postgres"
+	"github.com/pseudomuto/where/drivertest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -40,6 +40,12 @@ func TestPostgreSQLDateTimeFunctions(t *testing.T) {
 			expectedSQL:    "EXTRACT($1, created_at) = $2",
 			expectedParams: []any{"year", float64(2024)},
 		},
+		{
+			name:           "IF function",
+			expression:     "IF(score >= 50, 'pass', 'fail') = 'pass'",
+			expectedSQL:    "CASE WHEN score >= $1 THEN $2 ELSE $3 END = $4",
+			expectedParams: []any{float64(50), "pass", "fail", "pass"},
+		},
 		{
 			name:           "TO_CHAR function",
 			expression:     "TO_CHAR(created_at, 'YYYY-MM-DD') = '2024-01-15'",
@@ -339,6 +345,12 @@ func TestPostgreSQLComplexExpressions(t *testing.T) {
 			expectedSQL:    "(DATE_TRUNC($1, created_at) = $2 AND JSONB_EXTRACT_PATH(metadata, $3, $4) = $5 AND ARRAY_LENGTH(tags, $6) > $7 AND REGEXP_MATCH(email, $8) IS NOT NULL)",
 			expectedParams: []any{"month", "2024-01-01", "user", "role", "admin", float64(1), float64(0), "^admin@"},
 		},
+		{
+			name:           "WITHIN_RADIUS function",
+			expression:     "WITHIN_RADIUS(location, 52.52, 13.405, 2500) AND NOT WITHIN_RADIUS(location, 52.5, 13.4, 100)",
+			expectedSQL:    "(ST_DWithin(CAST(location AS geography), CAST(ST_SetSRID(ST_MakePoint($1, $2), 4326) AS geography), $3) AND NOT (ST_DWithin(CAST(location AS geography), CAST(ST_SetSRID(ST_MakePoint($4, $5), 4326) AS geography), $6)))",
+			expectedParams: []any{13.405, 52.52, float64(2500), 13.4, 52.5, float64(100)},
+		},
 	}
 
 	runTests(t, tests)
@@ -390,3 +402,39 @@ func runTests(t *testing.T, tests []struct {
 		})
 	}
 }
+
+func TestPostgreSQLTextSearchConfig(t *testing.T) {
+	where.RegisterDriver("postgres-english", postgres.NewPostgreSQLDriver(postgres.WithTextSearchConfig("english")))
+
+	filter, err := where.Parse("body MATCHES 'quick fox'")
+	require.NoError(t, err)
+
+	sql, params, err := filter.ToSQL("postgres-english")
+	require.NoError(t, err)
+	require.Equal(t, "to_tsvector('english', body) @@ plainto_tsquery('english', $1)", sql)
+	require.Equal(t, []any{"quick fox"}, params)
+
+	driver := postgres.NewPostgreSQLDriver(postgres.WithTextSearchConfig("it's"))
+	require.Equal(t, "to_tsvector('it''s', body) @@ plainto_tsquery('it''s', $1)", driver.MatchText("body", "$1"))
+}
+
+func TestPostgreSQLVersion(t *testing.T) {
+	where.RegisterDriver("postgres11", postgres.NewPostgreSQLDriver(postgres.WithVersion(where.Version{Major: 11})))
+	where.RegisterFunctionTemplate("postgres", "JSON_HAS_PATH", 2, "({0} #> string_to_array({1}, '.') IS NOT NULL)")
+	where.RegisterVersionedFunctionTemplate("postgres", "JSON_HAS_PATH", 2, where.Version{Major: 12}, "jsonb_path_exists({0}, {1})")
+
+	filter, err := where.Parse("JSON_HAS_PATH(data, 'a.b')")
+	require.NoError(t, err)
+
+	sql, _, err := filter.ToSQL("postgres11")
+	require.NoError(t, err)
+	require.Equal(t, "(data #> string_to_array($1, '.') IS NOT NULL)", sql)
+
+	sql, _, err = filter.ToSQL("postgres")
+	require.NoError(t, err)
+	require.Equal(t, "jsonb_path_exists(data, $1)", sql)
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.Run(t, postgres.NewPostgreSQLDriver(), drivertest.WithFeatures("ARRAY", "BITWISE", "BOOLEAN", "CTE", "FULLTEXT", "ILIKE", "JSON", "JSONB", "RETURNING", "WINDOW"))
+}
@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"strings"
+)
+
+// types maps portable type names to PostgreSQL types. Other names are used as written.
+var types = map[string]string{
+	"DATETIME": "TIMESTAMP",
+	"DOUBLE":   "DOUBLE PRECISION",
+	"STRING":   "TEXT",
+	"TINYINT":  "SMALLINT",
+}
+
+// MapType translates portable type names in CAST expressions to PostgreSQL types.
+func (d *PostgreSQLDriver) MapType(name string, params []string) (string, bool) {
+	mapped, ok := types[name]
+	if !ok {
+		return "", false
+	}
+	if len(params) == 0 {
+		return mapped, true
+	}
+	return mapped + "(" + strings.Join(params, ", ") + ")", true
+}
@@ -0,0 +1,47 @@
+package redshift
+
+import (
+	"github.com/pseudomuto/where"
+)
+
+// withinRadiusTemplate renders WITHIN_RADIUS(location, lat, lng, meters) for a GEOMETRY column in
+// longitude and latitude, using the spherical distance in meters.
+const withinRadiusTemplate = "(ST_DistanceSphere({0}, ST_SetSRID(ST_Point({2}, {1}), 4326)) <= {3})"
+
+// unsupportedFunctions lists PostgreSQL functions that Redshift does not implement. Calls to them
+// are rejected rather than failing when the query runs.
+// Source: https://docs.aws.amazon.com/redshift/latest/dg/c_unsupported-postgresql-functions.html
+var unsupportedFunctions = []string{
+	// Array functions.
+	"ARRAY_LENGTH", "ARRAY_POSITION", "ARRAY_TO_STRING", "CARDINALITY", "STRING_TO_ARRAY", "UNNEST",
+
+	// JSONB and JSON construction functions. JSON_EXTRACT_PATH_TEXT works on VARCHAR columns.
+	"JSONB_ARRAY_LENGTH", "JSONB_EXTRACT_PATH", "JSONB_EXTRACT_PATH_TEXT", "JSONB_TYPEOF",
+	"JSON_BUILD_OBJECT", "JSON_EXTRACT_PATH", "TO_JSONB",
+
+	// Text search functions.
+	"PLAINTO_TSQUERY", "TO_TSQUERY", "TO_TSVECTOR", "TS_RANK", "WEBSEARCH_TO_TSQUERY",
+
+	// String functions.
+	"BIT_LENGTH", "CONVERT_FROM", "CONVERT_TO", "ENCODE", "FORMAT", "OVERLAY", "QUOTE_NULLABLE",
+	"REGEXP_MATCHES", "REGEXP_SPLIT_TO_ARRAY", "REGEXP_SPLIT_TO_TABLE",
+
+	// Date and time functions.
+	"CLOCK_TIMESTAMP", "JUSTIFY_DAYS", "JUSTIFY_HOURS", "JUSTIFY_INTERVAL", "PG_SLEEP",
+	"TRANSACTION_TIMESTAMP",
+
+	// Math and set-returning functions.
+	"DIV", "GENERATE_SERIES", "GENERATE_SUBSCRIPTS", "SETSEED", "WIDTH_BUCKET",
+}
+
+// registerFunctions registers translations for portable functions without a native Redshift
+// equivalent. NOW() only runs on the leader node, so GETDATE() is used instead.
+func registerFunctions(driver string) {
+	where.RegisterFunctionTemplate(driver, "NOW", 0, "GETDATE()")
+	where.RegisterFunctionTemplate(driver, "YEAR", 1, "EXTRACT(YEAR FROM {0})")
+	where.RegisterFunctionTemplate(driver, "MONTH", 1, "EXTRACT(MONTH FROM {0})")
+	where.RegisterFunctionTemplate(driver, "DAY", 1, "EXTRACT(DAY FROM {0})")
+	where.RegisterFunctionTemplate(driver, "REGEXP", 2, "({0} ~ {1})")
+	where.RegisterFunctionTemplate(driver, "IF", 3, "CASE WHEN {0} THEN {1} ELSE {2} END")
+	where.RegisterFunctionTemplate(driver, "WITHIN_RADIUS", 4, withinRadiusTemplate)
+}
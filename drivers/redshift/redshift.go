@@ -0,0 +1,137 @@
+// Package redshift provides a driver for Amazon Redshift.
+//
+// Redshift speaks the PostgreSQL protocol and much of its SQL, but the postgres driver produces
+// clauses that fail there: Redshift has no IS NOT DISTINCT FROM, no JSONB or array operations, and
+// no text search, and it reserves words such as TIMESTAMP and USER. This driver rejects those
+// constructs when the filter is built rather than when the query runs.
+package redshift
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pseudomuto/where"
+)
+
+// maxParams is the protocol limit on bind parameters in a single statement.
+const maxParams = 65535
+
+var (
+	supportedFeatures = []string{
+		"BITWISE",
+		"BOOLEAN",
+		"CTE",
+		"ILIKE",
+		"JSON",
+		"WINDOW",
+	}
+
+	supportedOperations = []string{
+		"=", "!=", "<>", "<", ">", "<=", ">=",
+		"LIKE", "NOT LIKE", "ILIKE", "NOT ILIKE",
+		"IN", "NOT IN",
+		"IS NULL", "IS NOT NULL",
+		"BETWEEN", "NOT BETWEEN",
+		"&", "|", "<<", ">>",
+	}
+
+	identifierQuote = where.IdentifierQuote{Char: `"`}
+)
+
+type (
+	// RedshiftDriver implements the where.Driver interface for Amazon Redshift.
+	RedshiftDriver struct{}
+)
+
+// NewRedshiftDriver creates a new Redshift driver instance.
+//
+// Example:
+//
+//	import (
+//		"github.com/pseudomuto/where"
+//		_ "github.com/pseudomuto/where/drivers/redshift"
+//	)
+//
+//	filter, params, _ := where.Build("timestamp > '2024-01-01' AND name ILIKE 'j%'", "redshift")
+//	// SELECT * FROM events WHERE ("timestamp" > $1 AND name ILIKE $2)
+func NewRedshiftDriver() *RedshiftDriver {
+	return &RedshiftDriver{}
+}
+
+func (d *RedshiftDriver) Name() string {
+	return "redshift"
+}
+
+func (d *RedshiftDriver) QuoteIdentifier(name string) string {
+	if name == "" {
+		return name
+	}
+
+	name, quoted := identifierQuote.Requote(strings.TrimSpace(name))
+	if quoted {
+		return name
+	}
+
+	if strings.Contains(name, ".") {
+		parts := strings.Split(name, ".")
+		quoted := make([]string, len(parts))
+		for i, part := range parts {
+			quoted[i] = d.quoteSimpleIdentifier(part)
+		}
+		return strings.Join(quoted, ".")
+	}
+
+	return d.quoteSimpleIdentifier(name)
+}
+
+func (d *RedshiftDriver) quoteSimpleIdentifier(name string) string {
+	if where.NeedsQuoting(name, d) {
+		return identifierQuote.Quote(name)
+	}
+	return name
+}
+
+func (d *RedshiftDriver) Placeholder(position int) string {
+	return fmt.Sprintf("$%d", position)
+}
+
+func (d *RedshiftDriver) Keywords() []string {
+	return keywords
+}
+
+// TranslateOperator renders bitwise XOR as #, as PostgreSQL does. NULL-safe equality is not
+// supported, since Redshift has no IS NOT DISTINCT FROM.
+func (d *RedshiftDriver) TranslateOperator(op string) (string, bool) {
+	upperOp := strings.ToUpper(op)
+	if slices.Contains(supportedOperations, upperOp) {
+		return upperOp, true
+	}
+
+	if upperOp == "^" {
+		return "#", true
+	}
+
+	return "", false
+}
+
+func (d *RedshiftDriver) SupportsFeature(feature string) bool {
+	return slices.Contains(supportedFeatures, strings.ToUpper(feature))
+}
+
+// SupportsFunction returns false for PostgreSQL functions Redshift does not implement, such as
+// JSONB, array, and text search functions. Other functions are rendered as written.
+func (d *RedshiftDriver) SupportsFunction(name string) bool {
+	return !slices.Contains(unsupportedFunctions, name)
+}
+
+// MaxParams returns the maximum number of bind parameters in a Redshift statement.
+func (d *RedshiftDriver) MaxParams() int {
+	return maxParams
+}
+
+func init() {
+	driver := NewRedshiftDriver()
+	registerFunctions(driver.Name())
+	where.RegisterDriver("redshift", driver)
+}
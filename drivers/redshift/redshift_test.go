@@ -0,0 +1,124 @@
+package redshift_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/pseudomuto/where/drivers/redshift"
+	"github.com/pseudomuto/where/drivertest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedshiftSQL(t *testing.T) {
+	tests := []struct {
+		name           string
+		expression     string
+		expectedSQL    string
+		expectedParams []any
+	}{
+		{
+			name:           "numbered placeholders",
+			expression:     "age >= 18 AND status != 'closed'",
+			expectedSQL:    "(age >= $1 AND status != $2)",
+			expectedParams: []any{float64(18), "closed"},
+		},
+		{
+			name:           "reserved words",
+			expression:     "timestamp > '2024-01-01' AND user = 'u1' AND tag = 'x' AND position = 1",
+			expectedSQL:    `("timestamp" > $1 AND "user" = $2 AND "tag" = $3 AND position = $4)`,
+			expectedParams: []any{"2024-01-01", "u1", "x", float64(1)},
+		},
+		{
+			name:           "native ILIKE",
+			expression:     "name ILIKE 'j%' AND email NOT ILIKE '%spam%'",
+			expectedSQL:    "(name ILIKE $1 AND email NOT ILIKE $2)",
+			expectedParams: []any{"j%", "%spam%"},
+		},
+		{
+			name:           "bitwise XOR",
+			expression:     "flags ^ 4 = 0",
+			expectedSQL:    "flags # $1 = $2",
+			expectedParams: []any{int64(4), float64(0)},
+		},
+		{
+			name:           "Redshift functions",
+			expression:     "JSON_EXTRACT_PATH_TEXT(data, 'a') = 'x' AND DATEDIFF('day', created_at, GETDATE()) < 7",
+			expectedSQL:    "(JSON_EXTRACT_PATH_TEXT(data, $1) = $2 AND DATEDIFF($3, created_at, GETDATE()) < $4)",
+			expectedParams: []any{"a", "x", "day", float64(7)},
+		},
+		{
+			name:           "translated functions",
+			expression:     "created_at < NOW() AND YEAR(created_at) = 2024 AND REGEXP(email, '^admin')",
+			expectedSQL:    "(created_at < GETDATE() AND EXTRACT(YEAR FROM created_at) = $1 AND (email ~ $2))",
+			expectedParams: []any{float64(2024), "^admin"},
+		},
+		{
+			name:           "WITHIN_RADIUS",
+			expression:     "WITHIN_RADIUS(location, 43.65, -79.38, 5000)",
+			expectedSQL:    "(ST_DistanceSphere(location, ST_SetSRID(ST_Point($1, $2), 4326)) <= $3)",
+			expectedParams: []any{-79.38, 43.65, float64(5000)},
+		},
+		{
+			name:           "CAST types",
+			expression:     "CAST(score AS STRING) = '1' AND CAST(created_at AS DATETIME) > '2024-01-01'",
+			expectedSQL:    "(CAST(score AS VARCHAR(MAX)) = $1 AND CAST(created_at AS TIMESTAMP) > $2)",
+			expectedParams: []any{"1", "2024-01-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.expression)
+			require.NoError(t, err)
+
+			sql, params, err := filter.ToSQL("redshift")
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedSQL, sql)
+			require.Equal(t, tt.expectedParams, params)
+		})
+	}
+}
+
+func TestRedshiftRejectsUnsupportedSQL(t *testing.T) {
+	tests := []struct {
+		expression string
+		err        string
+	}{
+		{expression: "JSONB_EXTRACT_PATH_TEXT(data, 'a') = 'x'", err: `function "JSONB_EXTRACT_PATH_TEXT" not supported by driver redshift`},
+		{expression: "ARRAY_LENGTH(tags, 1) > 0", err: `function "ARRAY_LENGTH" not supported by driver redshift`},
+		{expression: "REGEXP_MATCHES(name, 'x') IS NOT NULL", err: `function "REGEXP_MATCHES" not supported by driver redshift`},
+		{expression: "a <=> 1", err: "operator <=> not supported by driver redshift"},
+		{expression: "body MATCHES 'fox'", err: "operator MATCHES not supported by driver redshift"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			filter, err := where.Parse(tt.expression)
+			require.NoError(t, err)
+
+			_, _, err = filter.ToSQL("redshift")
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestRedshiftQuoteIdentifier(t *testing.T) {
+	driver := redshift.NewRedshiftDriver()
+
+	require.Equal(t, `"my field"`, driver.QuoteIdentifier(`"my field"`))
+	require.Equal(t, `"a"" OR 1=1 OR ""b"`, driver.QuoteIdentifier(`"a" OR 1=1 OR "b"`))
+	require.Equal(t, `""""`, driver.QuoteIdentifier(`"`))
+
+	filter, err := where.Parse("`\"a\" OR 1=1 OR \"b\"` = 1")
+	require.NoError(t, err)
+
+	sql, _, err := filter.ToSQL("redshift")
+	require.NoError(t, err)
+	require.Equal(t, `"a"" OR 1=1 OR ""b" = $1`, sql)
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.Run(t, redshift.NewRedshiftDriver(),
+		drivertest.WithFeatures("BITWISE", "BOOLEAN", "CTE", "ILIKE", "JSON", "WINDOW"))
+}
@@ -0,0 +1,30 @@
+package redshift
+
+import (
+	"strings"
+)
+
+// types maps portable type names to Redshift types. Other names are used as written. Strings are
+// cast to VARCHAR(MAX), since Redshift's VARCHAR defaults to 256 bytes.
+var types = map[string]string{
+	"DATETIME": "TIMESTAMP",
+	"DOUBLE":   "DOUBLE PRECISION",
+	"JSON":     "SUPER",
+	"JSONB":    "SUPER",
+	"STRING":   "VARCHAR(MAX)",
+	"TEXT":     "VARCHAR(MAX)",
+	"TINYINT":  "SMALLINT",
+	"UUID":     "CHAR(36)",
+}
+
+// MapType translates portable type names in CAST expressions to Redshift types.
+func (d *RedshiftDriver) MapType(name string, params []string) (string, bool) {
+	mapped, ok := types[name]
+	if !ok {
+		return "", false
+	}
+	if strings.Contains(mapped, "(") || len(params) == 0 {
+		return mapped, true
+	}
+	return mapped + "(" + strings.Join(params, ", ") + ")", true
+}
@@ -0,0 +1,32 @@
+package spanner
+
+import (
+	"github.com/pseudomuto/where"
+)
+
+// dateTruncTemplate renders DATE_TRUNC(unit, value) with TIMESTAMP_TRUNC, whose unit must be written
+// as a keyword rather than bound. Spanner truncates in America/Los_Angeles by default, so UTC is
+// given explicitly. Weeks start on Monday.
+const dateTruncTemplate = "CASE LOWER({0})" +
+	" WHEN 'second' THEN TIMESTAMP_TRUNC({1}, SECOND, 'UTC')" +
+	" WHEN 'minute' THEN TIMESTAMP_TRUNC({1}, MINUTE, 'UTC')" +
+	" WHEN 'hour' THEN TIMESTAMP_TRUNC({1}, HOUR, 'UTC')" +
+	" WHEN 'day' THEN TIMESTAMP_TRUNC({1}, DAY, 'UTC')" +
+	" WHEN 'week' THEN TIMESTAMP_TRUNC({1}, ISOWEEK, 'UTC')" +
+	" WHEN 'month' THEN TIMESTAMP_TRUNC({1}, MONTH, 'UTC')" +
+	" WHEN 'quarter' THEN TIMESTAMP_TRUNC({1}, QUARTER, 'UTC')" +
+	" WHEN 'year' THEN TIMESTAMP_TRUNC({1}, YEAR, 'UTC')" +
+	" END"
+
+// registerFunctions registers translations for portable functions written differently in Spanner.
+// YEAR, MONTH, and DAY extract from TIMESTAMP values in UTC rather than Spanner's default time zone.
+func registerFunctions(driver string) {
+	where.RegisterFunctionTemplate(driver, "NOW", 0, "CURRENT_TIMESTAMP()")
+	where.RegisterFunctionTemplate(driver, "YEAR", 1, "EXTRACT(YEAR FROM {0} AT TIME ZONE 'UTC')")
+	where.RegisterFunctionTemplate(driver, "MONTH", 1, "EXTRACT(MONTH FROM {0} AT TIME ZONE 'UTC')")
+	where.RegisterFunctionTemplate(driver, "DAY", 1, "EXTRACT(DAY FROM {0} AT TIME ZONE 'UTC')")
+	where.RegisterFunctionTemplate(driver, "SUBSTRING", 2, "SUBSTR({0}, {1})")
+	where.RegisterFunctionTemplate(driver, "SUBSTRING", 3, "SUBSTR({0}, {1}, {2})")
+	where.RegisterFunctionTemplate(driver, "REGEXP", 2, "REGEXP_CONTAINS({0}, {1})")
+	where.RegisterFunctionTemplate(driver, "DATE_TRUNC", 2, dateTruncTemplate)
+}
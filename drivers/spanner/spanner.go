@@ -0,0 +1,154 @@
+// Package spanner provides a driver for Cloud Spanner databases using the GoogleSQL dialect.
+//
+// Spanner differs from the other databases in ways an alias cannot cover: parameters are named
+// (@p1, @p2, ...), identifiers are quoted with backticks, there is no ILIKE, and many portable
+// functions are spelled differently, e.g. REGEXP as REGEXP_CONTAINS and DATE_TRUNC as
+// TIMESTAMP_TRUNC. Native functions such as STARTS_WITH and ENDS_WITH are rendered as written.
+// Databases using Spanner's PostgreSQL dialect should use the postgres driver instead.
+package spanner
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pseudomuto/where"
+)
+
+// maxParams is the limit on query parameters in a single Spanner statement.
+const maxParams = 950
+
+var (
+	supportedFeatures = []string{
+		"ARRAY",
+		"BITWISE",
+		"BOOLEAN",
+		"CTE",
+		"JSON",
+		"WINDOW",
+	}
+
+	supportedOperations = []string{
+		"=", "!=", "<>", "<", ">", "<=", ">=",
+		"LIKE", "NOT LIKE",
+		"IN", "NOT IN",
+		"IS NULL", "IS NOT NULL",
+		"BETWEEN", "NOT BETWEEN",
+		"&", "|", "^", "<<", ">>",
+	}
+
+	// GoogleSQL escapes backslashes and backticks in quoted identifiers with a backslash.
+	identifierQuote = where.IdentifierQuote{Char: "`", Backslash: true}
+)
+
+type (
+	// SpannerDriver implements the where.Driver interface for Cloud Spanner databases.
+	SpannerDriver struct{}
+)
+
+// NewSpannerDriver creates a new Cloud Spanner driver instance.
+//
+// Example:
+//
+//	import (
+//		"github.com/pseudomuto/where"
+//		_ "github.com/pseudomuto/where/drivers/spanner"
+//	)
+//
+//	filter, params, _ := where.Build("age > 18 AND name ILIKE 'j%'", "spanner")
+//	// SELECT * FROM users WHERE (age > @p1 AND LOWER(name) LIKE LOWER(@p2))
+func NewSpannerDriver() *SpannerDriver {
+	return &SpannerDriver{}
+}
+
+func (d *SpannerDriver) Name() string {
+	return "spanner"
+}
+
+func (d *SpannerDriver) QuoteIdentifier(name string) string {
+	if name == "" {
+		return name
+	}
+
+	name, quoted := identifierQuote.Requote(strings.TrimSpace(name))
+	if quoted {
+		return name
+	}
+
+	if strings.Contains(name, ".") {
+		parts := strings.Split(name, ".")
+		quoted := make([]string, len(parts))
+		for i, part := range parts {
+			quoted[i] = d.quoteSimpleIdentifier(part)
+		}
+		return strings.Join(quoted, ".")
+	}
+
+	return d.quoteSimpleIdentifier(name)
+}
+
+// quoteSimpleIdentifier quotes with backticks, escaping backticks and backslashes, which GoogleSQL
+// treats as escape characters inside quoted identifiers.
+func (d *SpannerDriver) quoteSimpleIdentifier(name string) string {
+	if where.NeedsQuoting(name, d) {
+		return identifierQuote.Quote(name)
+	}
+	return name
+}
+
+// Placeholder returns a named parameter, e.g. @p1, which the Spanner client binds from
+// spanner.Statement.Params with the keys p1, p2, and so on.
+func (d *SpannerDriver) Placeholder(position int) string {
+	return fmt.Sprintf("@p%d", position)
+}
+
+func (d *SpannerDriver) Keywords() []string {
+	return keywords
+}
+
+// TranslateOperator renders NULL-safe equality as IS NOT DISTINCT FROM, and ILIKE as LIKE, which the
+// SQL builder applies to lowercased values.
+func (d *SpannerDriver) TranslateOperator(op string) (string, bool) {
+	upperOp := strings.ToUpper(op)
+	if slices.Contains(supportedOperations, upperOp) {
+		return upperOp, true
+	}
+
+	switch upperOp {
+	case "<=>":
+		return "IS NOT DISTINCT FROM", true
+	case "ILIKE", "NOT ILIKE":
+		return strings.Replace(upperOp, "ILIKE", "LIKE", 1), true
+	}
+
+	return "", false
+}
+
+func (d *SpannerDriver) SupportsFeature(feature string) bool {
+	return slices.Contains(supportedFeatures, strings.ToUpper(feature))
+}
+
+// SupportsFunction returns false for WITHIN_RADIUS, since Spanner has no geography functions, and
+// true for other functions, which are passed through.
+func (d *SpannerDriver) SupportsFunction(name string) bool {
+	return name != "WITHIN_RADIUS"
+}
+
+// MaxParams returns the maximum number of parameters in a Spanner statement.
+func (d *SpannerDriver) MaxParams() int {
+	return maxParams
+}
+
+// ArrayMembership renders array membership as expr IN UNNEST(@pn) or expr NOT IN UNNEST(@pn).
+func (d *SpannerDriver) ArrayMembership(expr, placeholder string, not bool) string {
+	if not {
+		return fmt.Sprintf("%s NOT IN UNNEST(%s)", expr, placeholder)
+	}
+	return fmt.Sprintf("%s IN UNNEST(%s)", expr, placeholder)
+}
+
+func init() {
+	driver := NewSpannerDriver()
+	registerFunctions(driver.Name())
+	where.RegisterDriver("spanner", driver)
+}
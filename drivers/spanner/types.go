@@ -0,0 +1,35 @@
+package spanner
+
+// types maps portable type names to GoogleSQL types. Other names are used as written. CAST does not
+// accept lengths or precisions, so type parameters are dropped.
+var types = map[string]string{
+	"BIGINT":    "INT64",
+	"BINARY":    "BYTES",
+	"BLOB":      "BYTES",
+	"BOOLEAN":   "BOOL",
+	"CHAR":      "STRING",
+	"DATETIME":  "TIMESTAMP",
+	"DECIMAL":   "NUMERIC",
+	"DOUBLE":    "FLOAT64",
+	"FLOAT":     "FLOAT64",
+	"INT":       "INT64",
+	"INTEGER":   "INT64",
+	"REAL":      "FLOAT32",
+	"SMALLINT":  "INT64",
+	"TEXT":      "STRING",
+	"TINYINT":   "INT64",
+	"UUID":      "STRING",
+	"VARBINARY": "BYTES",
+	"VARCHAR":   "STRING",
+}
+
+// MapType translates portable type names in CAST expressions to GoogleSQL types.
+func (d *SpannerDriver) MapType(name string, params []string) (string, bool) {
+	if mapped, ok := types[name]; ok {
+		return mapped, true
+	}
+	if len(params) > 0 {
+		return name, true
+	}
+	return "", false
+}
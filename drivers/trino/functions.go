@@ -0,0 +1,17 @@
+package trino
+
+import (
+	"github.com/pseudomuto/where"
+)
+
+// withinRadiusTemplate renders WITHIN_RADIUS(location, lat, lng, meters) for a point geometry in
+// longitude and latitude, measuring the spherical distance in meters.
+const withinRadiusTemplate = "(ST_Distance(to_spherical_geography({0})," +
+	" to_spherical_geography(ST_Point({2}, {1}))) <= {3})"
+
+// registerFunctions registers translations for portable functions without a native Trino
+// equivalent. Function names are registered for the driver, so Presto and Athena share them.
+func registerFunctions(driver string) {
+	where.RegisterFunctionTemplate(driver, "REGEXP", 2, "regexp_like({0}, {1})")
+	where.RegisterFunctionTemplate(driver, "WITHIN_RADIUS", 4, withinRadiusTemplate)
+}
@@ -0,0 +1,146 @@
+// Package trino provides a driver for Trino and the engines derived from it, Presto and Amazon
+// Athena.
+//
+// Athena does not convert string parameters to the type of DATE and TIMESTAMP columns, so
+// created_at > ? bound to '2024-01-15' fails or compares the wrong types. Fields declared with
+// where.FieldTypeDate or where.FieldTypeTime are compared with DATE '2024-01-15' and
+// TIMESTAMP '2024-01-15 00:00:00.000' literals instead, rendered from the parsed time rather than
+// from the user's input.
+package trino
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pseudomuto/where"
+)
+
+var (
+	supportedFeatures = []string{
+		"BOOLEAN",
+		"CTE",
+		"JSON",
+		"WINDOW",
+	}
+
+	supportedOperations = []string{
+		"=", "!=", "<>", "<", ">", "<=", ">=",
+		"LIKE", "NOT LIKE",
+		"IN", "NOT IN",
+		"IS NULL", "IS NOT NULL",
+		"BETWEEN", "NOT BETWEEN",
+	}
+
+	identifierQuote = where.IdentifierQuote{Char: `"`}
+)
+
+type (
+	// TrinoDriver implements the where.Driver interface for Trino, Presto, and Athena.
+	TrinoDriver struct{}
+)
+
+// NewTrinoDriver creates a new Trino driver instance.
+//
+// Example:
+//
+//	import (
+//		"github.com/pseudomuto/where"
+//		_ "github.com/pseudomuto/where/drivers/trino"
+//	)
+//
+//	filter, _ := where.Parse("dt >= '2024-01-15' AND name ILIKE 'j%'")
+//	sql, params, _ := filter.ToSQL("athena", where.WithFieldTypes(map[string]where.FieldType{
+//		"dt": where.FieldTypeDate,
+//	}))
+//	// (dt >= DATE '2024-01-15' AND LOWER(name) LIKE LOWER(?) ESCAPE '\')
+func NewTrinoDriver() *TrinoDriver {
+	return &TrinoDriver{}
+}
+
+func (d *TrinoDriver) Name() string {
+	return "trino"
+}
+
+func (d *TrinoDriver) QuoteIdentifier(name string) string {
+	if name == "" {
+		return name
+	}
+
+	name, quoted := identifierQuote.Requote(strings.TrimSpace(name))
+	if quoted {
+		return name
+	}
+
+	if strings.Contains(name, ".") {
+		parts := strings.Split(name, ".")
+		quoted := make([]string, len(parts))
+		for i, part := range parts {
+			quoted[i] = d.quoteSimpleIdentifier(part)
+		}
+		return strings.Join(quoted, ".")
+	}
+
+	return d.quoteSimpleIdentifier(name)
+}
+
+func (d *TrinoDriver) quoteSimpleIdentifier(name string) string {
+	if where.NeedsQuoting(name, d) {
+		return identifierQuote.Quote(name)
+	}
+	return name
+}
+
+func (d *TrinoDriver) Placeholder(int) string {
+	return "?"
+}
+
+func (d *TrinoDriver) Keywords() []string {
+	return keywords
+}
+
+// TranslateOperator renders NULL-safe equality as IS NOT DISTINCT FROM, and ILIKE as LIKE, which the
+// SQL builder applies to lowercased values. Bitwise operations are functions in Trino, e.g.
+// bitwise_and, so the operators are not supported.
+func (d *TrinoDriver) TranslateOperator(op string) (string, bool) {
+	upperOp := strings.ToUpper(op)
+	if slices.Contains(supportedOperations, upperOp) {
+		return upperOp, true
+	}
+
+	switch upperOp {
+	case "<=>":
+		return "IS NOT DISTINCT FROM", true
+	case "ILIKE", "NOT ILIKE":
+		return strings.Replace(upperOp, "ILIKE", "LIKE", 1), true
+	}
+
+	return "", false
+}
+
+func (d *TrinoDriver) SupportsFeature(feature string) bool {
+	return slices.Contains(supportedFeatures, strings.ToUpper(feature))
+}
+
+// LikeEscape declares the backslash escaping LIKE wildcards, since Trino's LIKE has no default escape
+// character.
+func (d *TrinoDriver) LikeEscape() string {
+	return `'\'`
+}
+
+// TimeLiteral renders a DATE literal for FieldTypeDate, and a TIMESTAMP literal in UTC with
+// millisecond precision, which Athena stores, for FieldTypeTime.
+func (d *TrinoDriver) TimeLiteral(t time.Time, typ where.FieldType) string {
+	if typ == where.FieldTypeDate {
+		return "DATE '" + t.Format(time.DateOnly) + "'"
+	}
+	return "TIMESTAMP '" + t.UTC().Format("2006-01-02 15:04:05.000") + "'"
+}
+
+func init() {
+	driver := NewTrinoDriver()
+	registerFunctions(driver.Name())
+	where.RegisterDriver("trino", driver)
+	where.RegisterDriver("presto", driver)
+	where.RegisterDriver("athena", driver)
+}
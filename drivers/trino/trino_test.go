@@ -0,0 +1,148 @@
+package trino_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pseudomuto/where"
+	"github.com/pseudomuto/where/drivers/trino"
+	"github.com/pseudomuto/where/drivertest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrinoSQL(t *testing.T) {
+	tests := []struct {
+		name           string
+		expression     string
+		expectedSQL    string
+		expectedParams []any
+	}{
+		{
+			name:           "comparisons",
+			expression:     "age >= 18 AND status != 'closed'",
+			expectedSQL:    "(age >= ? AND status != ?)",
+			expectedParams: []any{float64(18), "closed"},
+		},
+		{
+			name:           "quoted identifiers",
+			expression:     "t.values = 1 AND skip = 2",
+			expectedSQL:    `(t."values" = ? AND "skip" = ?)`,
+			expectedParams: []any{float64(1), float64(2)},
+		},
+		{
+			name:           "LIKE declares its escape character",
+			expression:     `name LIKE 'a\_%' AND email NOT ILIKE '%spam%'`,
+			expectedSQL:    `(name LIKE ? ESCAPE '\' AND LOWER(email) NOT LIKE LOWER(?) ESCAPE '\')`,
+			expectedParams: []any{`a\_%`, "%spam%"},
+		},
+		{
+			name:           "NULL-safe equality",
+			expression:     "a <=> 1",
+			expectedSQL:    "a IS NOT DISTINCT FROM ?",
+			expectedParams: []any{float64(1)},
+		},
+		{
+			name:           "functions",
+			expression:     "REGEXP(email, '^admin') AND DATE_TRUNC('day', ts) = '2024-01-01' AND YEAR(ts) = 2024",
+			expectedSQL:    "(regexp_like(email, ?) AND DATE_TRUNC(?, ts) = ? AND YEAR(ts) = ?)",
+			expectedParams: []any{"^admin", "day", "2024-01-01", float64(2024)},
+		},
+		{
+			name:           "CAST types",
+			expression:     "CAST(score AS STRING) = '1' AND CAST(n AS INT) > 2",
+			expectedSQL:    "(CAST(score AS VARCHAR) = ? AND CAST(n AS INTEGER) > ?)",
+			expectedParams: []any{"1", float64(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.expression)
+			require.NoError(t, err)
+
+			sql, params, err := filter.ToSQL("trino")
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedSQL, sql)
+			require.Equal(t, tt.expectedParams, params)
+		})
+	}
+}
+
+func TestTrinoTimeLiterals(t *testing.T) {
+	types := where.WithFieldTypes(map[string]where.FieldType{
+		"dt": where.FieldTypeDate,
+		"ts": where.FieldTypeTime,
+	})
+
+	tests := []struct {
+		name           string
+		expression     string
+		options        []where.BuildOption
+		expectedSQL    string
+		expectedParams []any
+	}{
+		{
+			name:           "date",
+			expression:     "dt >= '2024-01-15' AND dt IN ('2024-02-01', '2024-03-01T10:00:00')",
+			expectedSQL:    "(dt >= DATE '2024-01-15' AND dt IN (DATE '2024-02-01', DATE '2024-03-01'))",
+			expectedParams: []any{},
+		},
+		{
+			name:           "timestamp",
+			expression:     "ts BETWEEN '2024-01-15' AND '2024-01-15T10:30:00.25Z' AND name = '2024-01-15'",
+			expectedSQL:    "(ts BETWEEN TIMESTAMP '2024-01-15 00:00:00.000' AND TIMESTAMP '2024-01-15 10:30:00.250' AND name = ?)",
+			expectedParams: []any{"2024-01-15"},
+		},
+		{
+			name:           "timestamps are written in UTC",
+			expression:     "ts < '2024-01-15 09:00:00'",
+			options:        []where.BuildOption{where.WithTimeLocation(time.FixedZone("EST", -5*3600))},
+			expectedSQL:    "ts < TIMESTAMP '2024-01-15 14:00:00.000'",
+			expectedParams: []any{},
+		},
+		{
+			name:           "untyped fields are bound",
+			expression:     "other > '2024-01-15'",
+			expectedSQL:    "other > ?",
+			expectedParams: []any{"2024-01-15"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.expression)
+			require.NoError(t, err)
+
+			sql, params, err := filter.ToSQL("athena", append(tt.options, types)...)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedSQL, sql)
+			require.Equal(t, tt.expectedParams, params)
+		})
+	}
+
+	filter, err := where.Parse("dt = 'tomorrow'")
+	require.NoError(t, err)
+	_, _, err = filter.ToSQL("athena", types)
+	require.EqualError(t, err, `invalid time "tomorrow" for field "dt"`)
+}
+
+func TestTrinoQuoteIdentifier(t *testing.T) {
+	driver := trino.NewTrinoDriver()
+
+	require.Equal(t, `"my field"`, driver.QuoteIdentifier(`"my field"`))
+	require.Equal(t, `"a"" OR 1=1 OR ""b"`, driver.QuoteIdentifier(`"a" OR 1=1 OR "b"`))
+	require.Equal(t, `""""`, driver.QuoteIdentifier(`"`))
+
+	filter, err := where.Parse("`\"a\" OR 1=1 OR \"b\"` = 1")
+	require.NoError(t, err)
+
+	sql, _, err := filter.ToSQL("trino")
+	require.NoError(t, err)
+	require.Equal(t, `"a"" OR 1=1 OR ""b" = ?`, sql)
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.Run(t, trino.NewTrinoDriver(), drivertest.WithFeatures("BOOLEAN", "CTE", "JSON", "WINDOW"))
+}
@@ -0,0 +1,29 @@
+package trino
+
+import (
+	"strings"
+)
+
+// types maps portable type names to Trino types. Other names are used as written.
+var types = map[string]string{
+	"BINARY":   "VARBINARY",
+	"BLOB":     "VARBINARY",
+	"BOOL":     "BOOLEAN",
+	"DATETIME": "TIMESTAMP",
+	"INT":      "INTEGER",
+	"NUMERIC":  "DECIMAL",
+	"STRING":   "VARCHAR",
+	"TEXT":     "VARCHAR",
+}
+
+// MapType translates portable type names in CAST expressions to Trino types.
+func (d *TrinoDriver) MapType(name string, params []string) (string, bool) {
+	mapped, ok := types[name]
+	if !ok {
+		return "", false
+	}
+	if mapped == "VARBINARY" || mapped == "BOOLEAN" || len(params) == 0 {
+		return mapped, true
+	}
+	return mapped + "(" + strings.Join(params, ", ") + ")", true
+}
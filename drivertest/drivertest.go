@@ -0,0 +1,245 @@
+// Package drivertest provides a conformance suite for where.Driver implementations.
+//
+// Third-party drivers can verify that they behave like the built-in drivers by running the suite
+// from their own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		drivertest.Run(t, vertica.NewDriver(), drivertest.WithFeatures("WINDOW"))
+//	}
+package drivertest
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// standardOperators must be supported by every driver.
+	standardOperators = []string{
+		"=", "!=", "<>", "<", ">", "<=", ">=",
+		"LIKE", "NOT LIKE", "ILIKE", "NOT ILIKE",
+		"IN", "NOT IN",
+		"IS NULL", "IS NOT NULL",
+		"BETWEEN", "NOT BETWEEN",
+	}
+
+	// filters are built with the driver to check that generated SQL binds every value.
+	filters = []string{
+		"age >= 18 AND status = 'active'",
+		"name LIKE 'a%' OR name NOT LIKE '%b'",
+		"email ILIKE '%@example.com' AND email NOT ILIKE 'admin%'",
+		"id IN (1, 2, 3) AND id NOT IN (4)",
+		"score BETWEEN 1 AND 10 AND score NOT BETWEEN 4 AND 5",
+		"deleted_at IS NULL AND parent_id IS NOT NULL",
+		"NOT (a = 1 OR b <> 'x') AND LOWER(c) = 'y'",
+		"t.col = 'x' AND flag = true",
+	}
+
+	// fullTextFilters are also built with drivers that support the FULLTEXT feature.
+	fullTextFilters = []string{
+		"body MATCHES 'quick brown fox' AND title NOT MATCHES 'draft'",
+	}
+
+	// bitwiseOperators must be supported by drivers with the BITWISE feature.
+	bitwiseOperators = []string{"&", "|", "^", "<<", ">>"}
+
+	// bitwiseFilters are also built with drivers that support the BITWISE feature.
+	bitwiseFilters = []string{
+		"flags & 4 = 4 AND perm | 1 > 0 AND mask << 1 >> 1 ^ (2 & 3) != 0",
+	}
+
+	numberedPlaceholder = regexp.MustCompile(`\d+`)
+)
+
+type (
+	// Option configures the conformance suite.
+	Option func(*config)
+
+	config struct {
+		features []string
+		skip     map[string]bool
+	}
+)
+
+// WithFeatures declares features the driver must report as supported through SupportsFeature.
+func WithFeatures(features ...string) Option {
+	return func(c *config) {
+		c.features = append(c.features, features...)
+	}
+}
+
+// WithSkip skips the named subtests (e.g. "Keywords") for drivers that intentionally deviate.
+func WithSkip(names ...string) Option {
+	return func(c *config) {
+		for _, name := range names {
+			c.skip[name] = true
+		}
+	}
+}
+
+// Run runs the conformance suite against the driver. The driver is registered with where under the
+// name "drivertest/<driver name>" so filters can be built with it.
+func Run(t *testing.T, driver where.Driver, opts ...Option) {
+	t.Helper()
+
+	cfg := &config{skip: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	require.NotNil(t, driver, "driver must not be nil")
+	require.NotEmpty(t, driver.Name(), "driver must have a name")
+
+	name := "drivertest/" + driver.Name()
+	where.RegisterDriver(name, driver)
+
+	tests := []struct {
+		name string
+		test func(t *testing.T, driver where.Driver, cfg *config)
+	}{
+		{"Quoting", testQuoting},
+		{"Keywords", testKeywords},
+		{"Placeholders", testPlaceholders},
+		{"Operators", testOperators},
+		{"ILIKE", testILIKE},
+		{"Features", testFeatures},
+		{"Build", func(t *testing.T, driver where.Driver, _ *config) { testBuild(t, driver, name) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if cfg.skip[tt.name] {
+				t.Skip("skipped by drivertest.WithSkip")
+			}
+			tt.test(t, driver, cfg)
+		})
+	}
+}
+
+func testQuoting(t *testing.T, driver where.Driver, _ *config) {
+	require.Empty(t, driver.QuoteIdentifier(""), "empty identifiers are returned unchanged")
+	require.Equal(t, "age", driver.QuoteIdentifier("age"), "plain identifiers are not quoted")
+	require.Equal(t, "users.age", driver.QuoteIdentifier("users.age"), "each part of a qualified name is quoted separately")
+
+	quoted := driver.QuoteIdentifier("my field")
+	require.NotEqual(t, "my field", quoted, "identifiers with spaces must be quoted")
+	require.Equal(t, quoted, driver.QuoteIdentifier(quoted), "quoted identifiers are returned unchanged")
+
+	open := quoted[:1]
+	injected := driver.QuoteIdentifier("a" + open + " OR 1=1 --")
+	inner := strings.TrimSuffix(strings.TrimPrefix(injected, open), open)
+	require.NotContains(t, strings.ReplaceAll(inner, open+open, ""), open,
+		"quote characters inside identifiers must be escaped: %s", injected)
+
+	injected = driver.QuoteIdentifier(open + "a" + open + " OR 1=1 OR " + open + "b" + open)
+	inner = strings.TrimSuffix(strings.TrimPrefix(injected, open), open)
+	require.NotContains(t, strings.ReplaceAll(inner, open+open, ""), open,
+		"quoted identifiers must be escaped rather than returned as is: %s", injected)
+	require.NotPanics(t, func() { driver.QuoteIdentifier(open) }, "a lone quote character must be quoted")
+}
+
+func testKeywords(t *testing.T, driver where.Driver, _ *config) {
+	keywords := driver.Keywords()
+	require.NotEmpty(t, keywords, "drivers must report their reserved keywords")
+
+	for _, keyword := range keywords {
+		require.NotEqual(t, keyword, driver.QuoteIdentifier(keyword), "keyword %s must be quoted", keyword)
+		require.NotEqual(t, strings.ToLower(keyword), driver.QuoteIdentifier(strings.ToLower(keyword)),
+			"keyword %s must be quoted regardless of case", keyword)
+	}
+}
+
+func testPlaceholders(t *testing.T, driver where.Driver, _ *config) {
+	first, second := driver.Placeholder(1), driver.Placeholder(2)
+	require.NotEmpty(t, first)
+
+	if first == second {
+		// Positional placeholders such as ? are the same for every parameter.
+		require.Equal(t, first, driver.Placeholder(100))
+		return
+	}
+
+	// Numbered placeholders must identify the parameter position.
+	require.Equal(t, []string{"1"}, numberedPlaceholder.FindAllString(first, -1))
+	require.Equal(t, []string{"12"}, numberedPlaceholder.FindAllString(driver.Placeholder(12), -1))
+}
+
+func testOperators(t *testing.T, driver where.Driver, _ *config) {
+	for _, op := range standardOperators {
+		translated, ok := driver.TranslateOperator(op)
+		require.True(t, ok, "operator %s must be supported", op)
+		require.NotEmpty(t, translated, "operator %s must translate to SQL", op)
+
+		lower, ok := driver.TranslateOperator(strings.ToLower(op))
+		require.True(t, ok, "operators must be case-insensitive: %s", strings.ToLower(op))
+		require.Equal(t, translated, lower)
+	}
+
+	_, ok := driver.TranslateOperator("~~~")
+	require.False(t, ok, "unknown operators must not be supported")
+
+	if driver.SupportsFeature("BITWISE") {
+		for _, op := range bitwiseOperators {
+			translated, ok := driver.TranslateOperator(op)
+			require.True(t, ok, "drivers with the BITWISE feature must support %s", op)
+			require.NotEmpty(t, translated, "operator %s must translate to SQL", op)
+		}
+	}
+}
+
+func testILIKE(t *testing.T, driver where.Driver, _ *config) {
+	translated, ok := driver.TranslateOperator("ILIKE")
+	require.True(t, ok)
+
+	if driver.SupportsFeature("ILIKE") {
+		require.Equal(t, "ILIKE", translated, "drivers with native ILIKE must not translate it")
+		return
+	}
+	require.NotEqual(t, "ILIKE", translated, "drivers without native ILIKE must translate it")
+}
+
+func testFeatures(t *testing.T, driver where.Driver, cfg *config) {
+	for _, feature := range cfg.features {
+		require.True(t, driver.SupportsFeature(feature), "feature %s must be supported", feature)
+		require.True(t, driver.SupportsFeature(strings.ToLower(feature)), "features must be case-insensitive")
+	}
+	require.False(t, driver.SupportsFeature("NO_SUCH_FEATURE"))
+
+	if driver.SupportsFeature("FULLTEXT") {
+		_, ok := where.DriverAs[where.TextSearcher](driver)
+		require.True(t, ok, "drivers with the FULLTEXT feature must implement where.TextSearcher")
+	}
+}
+
+func testBuild(t *testing.T, driver where.Driver, driverName string) {
+	inputs := filters
+	if driver.SupportsFeature("FULLTEXT") {
+		inputs = append(slices.Clone(inputs), fullTextFilters...)
+	}
+	if driver.SupportsFeature("BITWISE") {
+		inputs = append(slices.Clone(inputs), bitwiseFilters...)
+	}
+
+	for _, input := range inputs {
+		filter, err := where.Parse(input)
+		require.NoError(t, err)
+
+		sql, params, err := filter.ToSQL(driverName)
+		require.NoError(t, err, input)
+		require.NotEmpty(t, sql)
+
+		typedSQL, typed, err := filter.ToSQLTyped(driverName)
+		require.NoError(t, err, input)
+		require.Equal(t, sql, typedSQL)
+		require.Len(t, typed, len(params))
+
+		for _, param := range typed {
+			require.Contains(t, sql, param.Name, "placeholder %s must appear in %s", param.Name, sql)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package where
+
+import "fmt"
+
+// ExtractEqualityValues returns the values the filter restricts a field to with = and IN, so sharded
+// systems can route a query to the shards holding those values. Only conditions that apply to every
+// row are considered: top-level AND conditions, including those in parenthesized AND groups, that
+// are not negated or inside an OR. Values are returned as Go values (see LiteralValue.Value) in the
+// order they first appear, without duplicates; several conditions on the field are intersected.
+//
+// ok is false if the filter does not restrict the field to a set of literal values, in which case a
+// query must be sent to every shard. An empty slice with ok true means no value can match.
+//
+// Example:
+//
+//	filter, _ := where.Parse("tenant_id IN (1, 2) AND status = 'active'")
+//	values, ok := where.ExtractEqualityValues(filter, "tenant_id")
+//	// values: [1 2], ok: true
+func ExtractEqualityValues(filter *Filter, field string) (values []any, ok bool) {
+	if filter == nil || filter.Expression == nil || len(filter.Expression.Or) != 1 {
+		return nil, false
+	}
+
+	var allowed map[string]bool
+	for _, factor := range conjuncts(filter.Expression.Or[0].And) {
+		vals, found := factorEqualityValues(factor, field)
+		if !found {
+			continue
+		}
+
+		if allowed == nil {
+			allowed = make(map[string]bool, len(vals))
+			values = make([]any, 0, len(vals))
+			for _, val := range vals {
+				if key := equalityKey(val); !allowed[key] {
+					allowed[key] = true
+					values = append(values, val)
+				}
+			}
+			continue
+		}
+
+		// Keep the values allowed by every condition, in the order they first appeared.
+		next := make(map[string]bool, len(vals))
+		for _, val := range vals {
+			next[equalityKey(val)] = true
+		}
+
+		kept := values[:0]
+		for _, val := range values {
+			if next[equalityKey(val)] {
+				kept = append(kept, val)
+			}
+		}
+		values = kept
+	}
+
+	if allowed == nil {
+		return nil, false
+	}
+	return values, true
+}
+
+// equalityKey identifies a value by its type and formatted value.
+func equalityKey(val any) string {
+	return fmt.Sprintf("%T:%v", val, val)
+}
+
+// factorEqualityValues returns the literal values an = or IN factor allows for the field.
+func factorEqualityValues(factor *Factor, field string) ([]any, bool) {
+	if factor.Not || factor.Predicate == nil || factor.Predicate.Operation == nil {
+		return nil, false
+	}
+
+	pred := factor.Predicate
+	op := pred.Operation
+	var operands []*Value
+	switch {
+	case op.Compare != nil && op.Compare.Operator.String() == "=":
+		switch {
+		case isField(pred.Left, field):
+			operands = []*Value{op.Compare.Right}
+		case isField(op.Compare.Right, field):
+			operands = []*Value{pred.Left}
+		}
+	case op.In != nil && !op.In.Not && isField(pred.Left, field):
+		operands = op.In.Values
+	}
+	if len(operands) == 0 {
+		return nil, false
+	}
+
+	values := make([]any, 0, len(operands))
+	for _, operand := range operands {
+		if operand == nil || operand.Literal == nil {
+			return nil, false
+		}
+		if typ := operand.Literal.Type(); typ == "null" || typ == "variable" {
+			return nil, false
+		}
+		values = append(values, operand.Literal.Value())
+	}
+	return values, true
+}
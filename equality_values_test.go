@@ -0,0 +1,51 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractEqualityValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []any
+		ok    bool
+	}{
+		{name: "equality", input: "tenant_id = 7 AND status = 'active'", want: []any{7.0}, ok: true},
+		{name: "reversed equality", input: "'acme' = TENANT_ID", want: []any{"acme"}, ok: true},
+		{name: "IN", input: "tenant_id IN (1, 2, 1) AND age > 18", want: []any{1.0, 2.0}, ok: true},
+		{name: "intersection", input: "tenant_id IN (1, 2, 3) AND (age > 18 AND tenant_id IN (3, 2))", want: []any{2.0, 3.0}, ok: true},
+		{name: "empty intersection", input: "tenant_id = 1 AND tenant_id = 2", want: []any{}, ok: true},
+		{name: "unconstrained", input: "status = 'active'"},
+		{name: "range", input: "tenant_id > 5"},
+		{name: "negated", input: "tenant_id NOT IN (1, 2) AND NOT (tenant_id = 3)"},
+		{name: "OR", input: "tenant_id = 1 OR tenant_id = 2"},
+		{name: "inside OR group", input: "status = 'a' AND (tenant_id = 1 OR age > 3)"},
+		{name: "non-literal", input: "tenant_id = LOWER(name)"},
+		{name: "variable", input: "tenant_id = :tenant"},
+		{name: "NULL", input: "tenant_id IN (1, NULL)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			values, ok := where.ExtractEqualityValues(filter, "tenant_id")
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, values)
+		})
+	}
+}
+
+func TestExtractEqualityValuesBound(t *testing.T) {
+	values, ok := where.ExtractEqualityValues(where.In("tenant_id", "a", "b"), "tenant_id")
+	require.True(t, ok)
+	require.Equal(t, []any{"a", "b"}, values)
+
+	_, ok = where.ExtractEqualityValues(nil, "tenant_id")
+	require.False(t, ok)
+}
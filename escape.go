@@ -0,0 +1,120 @@
+package where
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// EscapeBackslash treats backslash as an escape character inside string literals (C-style).
+	// Supported escapes are \', \", \\, \n, \r, \t, \b, \0, \xHH, \uXXXX, and \UXXXXXXXX.
+	// Unknown escapes such as the LIKE wildcard escapes \% and \_ are kept verbatim.
+	// Doubled quotes ('' inside '...') are also accepted. This is the default.
+	EscapeBackslash EscapeMode = iota
+
+	// EscapeStandard follows the SQL standard: backslash is an ordinary character and the only
+	// escape is a doubled quote ('' inside '...' or "" inside "...").
+	EscapeStandard
+)
+
+type (
+	// EscapeMode determines how escape sequences in string literals are interpreted.
+	EscapeMode int
+)
+
+// stringPatterns returns the lexer patterns for single and double quoted strings in this mode.
+func (m EscapeMode) stringPatterns() (single, double string) {
+	if m == EscapeStandard {
+		return `'([^']|'')*'`, `"([^"]|"")*"`
+	}
+	return `'([^'\\]|\\.|'')*'`, `"([^"\\]|\\.|"")*"`
+}
+
+// quote returns s as a single quoted string literal token, escaped for this mode.
+func (m EscapeMode) quote(s string) string {
+	if m != EscapeStandard {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// unquote strips the surrounding quotes from a string literal token and decodes its escape sequences.
+func (m EscapeMode) unquote(token string) string {
+	if len(token) < 2 {
+		return token
+	}
+
+	quote := token[0]
+	if (quote != '\'' && quote != '"') || token[len(token)-1] != quote {
+		return token
+	}
+
+	s := token[1 : len(token)-1]
+	s = strings.ReplaceAll(s, string([]byte{quote, quote}), string(quote))
+	if m == EscapeStandard || !strings.Contains(s, `\`) {
+		return s
+	}
+
+	return unescapeBackslashes(s)
+}
+
+func unescapeBackslashes(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch ch := s[i]; ch {
+		case '\'', '"', '\\':
+			sb.WriteByte(ch)
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'b':
+			sb.WriteByte('\b')
+		case '0':
+			sb.WriteByte(0)
+		case 'x', 'u', 'U':
+			size := 2
+			if ch == 'u' {
+				size = 4
+			} else if ch == 'U' {
+				size = 8
+			}
+			if r, ok := decodeHex(s[i+1:], size); ok {
+				sb.WriteRune(r)
+				i += size
+				continue
+			}
+			sb.WriteByte('\\')
+			sb.WriteByte(ch)
+		default:
+			// Keep unknown escapes (e.g. LIKE's \% and \_) intact.
+			sb.WriteByte('\\')
+			sb.WriteByte(ch)
+		}
+	}
+
+	return sb.String()
+}
+
+func decodeHex(s string, size int) (rune, bool) {
+	if len(s) < size {
+		return 0, false
+	}
+
+	n, err := strconv.ParseUint(s[:size], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(n)) {
+		return 0, false
+	}
+	return rune(n), true
+}
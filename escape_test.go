@@ -0,0 +1,76 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  any
+	}{
+		{name: "escaped single quote", input: `name = 'don\'t'`, want: "don't"},
+		{name: "doubled single quote", input: `name = 'don''t'`, want: "don't"},
+		{name: "escaped double quote", input: `name = "say \"hi\""`, want: `say "hi"`},
+		{name: "doubled double quote", input: `name = "say ""hi"""`, want: `say "hi"`},
+		{name: "backslash", input: `path = 'C:\\temp'`, want: `C:\temp`},
+		{name: "control characters", input: `s = 'a\nb\tc\rd'`, want: "a\nb\tc\rd"},
+		{name: "unicode escape", input: `s = 'caf\u00e9'`, want: "café"},
+		{name: "long unicode escape", input: `s = '\U0001F600'`, want: "😀"},
+		{name: "hex escape", input: `s = '\x41'`, want: "A"},
+		{name: "invalid unicode escape kept", input: `s = '\uZZZZ'`, want: `\uZZZZ`},
+		{name: "LIKE escapes kept", input: `s LIKE '100\%'`, want: `100\%`},
+		{name: "raw unicode", input: `s = 'こんにちは'`, want: "こんにちは"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			_, params, err := filter.ToSQL("postgres")
+			require.NoError(t, err)
+			require.Equal(t, []any{tt.want}, params)
+		})
+	}
+}
+
+func TestWithEscapeMode(t *testing.T) {
+	parser, err := where.NewParser(where.WithEscapeMode(where.EscapeStandard))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		input string
+		want  any
+	}{
+		{name: "doubled quote", input: `name = 'don''t'`, want: "don't"},
+		{name: "backslash is literal", input: `path = 'C:\'`, want: `C:\`},
+		{name: "no C-style escapes", input: `s = 'a\nb'`, want: `a\nb`},
+		{name: "doubled double quote", input: `name = "say ""hi"""`, want: `say "hi"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := parser.Parse(tt.input)
+			require.NoError(t, err)
+
+			_, params, err := filter.ToSQL("mysql")
+			require.NoError(t, err)
+			require.Equal(t, []any{tt.want}, params)
+		})
+	}
+
+	t.Run("applies to nested literals", func(t *testing.T) {
+		filter, err := parser.Parse(`LOWER(CONCAT(a, 'x\y')) IN ('it''s')`)
+		require.NoError(t, err)
+
+		_, params, err := filter.ToSQL("mysql")
+		require.NoError(t, err)
+		require.Equal(t, []any{`x\y`, "it's"}, params)
+	})
+}
@@ -243,7 +243,7 @@ func ExampleParse_reservedKeywords() {
 	// mysql: (`user` = ? AND `order` > ? AND `select` NOT IN (?, ?))
 	// Params: [admin 100 draft deleted]
 	//
-	// clickhouse: (`user` = ? AND `order` > ? AND `select` NOT IN (?, ?))
+	// clickhouse: (user = ? AND `order` > ? AND `select` NOT IN (?, ?))
 	// Params: [admin 100 draft deleted]
 }
 
@@ -280,11 +280,11 @@ func ExampleParse_advancedClickHouse() {
 
 	// Output:
 	// Advanced ClickHouse Query:
-	// SQL: (`timestamp` >= ? AND user_id IN (?, ?, ?, ?, ?) AND event_type = ? AND properties.channel IN (?, ?, ?) AND revenue > ? AND NOT ((test_group = ? AND variant IS NULL)))
+	// SQL: (timestamp >= ? AND user_id IN (?, ?, ?, ?, ?) AND event_type = ? AND properties.channel IN (?, ?, ?) AND revenue > ? AND NOT ((test_group = ? AND variant IS NULL)))
 	// Parameter count: 12
 	// Sample params: [2024-01-15 1001 1002]...
 	//
-	// Aggregation filter: (`date` >= ? AND country IN (?, ?) AND active = TRUE)
+	// Aggregation filter: (date >= ? AND country IN (?, ?) AND active = TRUE)
 	// Parameters: [2024-01-01 US CA]
 }
 
@@ -328,7 +328,7 @@ func ExampleParse_crossDatabaseFunctions() {
 
 	// Output:
 	// Date functions across databases:
-	// postgres: (created_at >= $1 AND YEAR(created_at) = $2 AND MONTH(created_at) IN ($3, $4, $5))
+	// postgres: (created_at >= $1 AND EXTRACT(YEAR FROM created_at) = $2 AND EXTRACT(MONTH FROM created_at) IN ($3, $4, $5))
 	//   Params: [2024-01-01 2024 1 2 3]
 	// mysql: (created_at >= ? AND YEAR(created_at) = ? AND MONTH(created_at) IN (?, ?, ?))
 	//   Params: [2024-01-01 2024 1 2 3]
@@ -337,8 +337,8 @@ func ExampleParse_crossDatabaseFunctions() {
 	//
 	// String functions across databases:
 	// postgres: (LOWER(email) LIKE $1 AND LENGTH(password) >= $2 A...
-	// mysql: (LOWER(email) LIKE ? AND LENGTH(password) >= ? AND...
-	// clickhouse: (LOWER(email) LIKE ? AND LENGTH(password) >= ? AND...
+	// mysql: (LOWER(email) LIKE ? AND CHAR_LENGTH(password) >= ...
+	// clickhouse: (LOWER(email) LIKE ? AND lengthUTF8(password) >= ?...
 }
 
 // ExampleParse_databaseSpecificFunctions demonstrates database-specific function usage.
@@ -434,7 +434,7 @@ func ExampleParse_clickHouseStandardFunctions() {
 
 	// Output:
 	// ClickHouse Date Functions:
-	// SQL: (toYYYYMM(event_time) = ? AND toYear(created_at) = ? AND toStartOfMonth(`timestamp`) >= ?)
+	// SQL: (toYYYYMM(event_time) = ? AND toYear(created_at) = ? AND toStartOfMonth(timestamp) >= ?)
 	// Parameters: [202401 2024 2024-01-01]
 	//
 	// Array Operations:
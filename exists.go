@@ -0,0 +1,88 @@
+package where
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// ExistsOp represents an EXISTS (subquery) condition. It cannot be written in filter
+	// expressions and is only created with Exists or NotExists, so the subquery SQL is always
+	// provided by the application rather than user input.
+	ExistsOp struct {
+		// Query is the subquery SQL using ? placeholders for Args. Use ?? for a literal question mark.
+		Query string
+
+		// Args are the values bound to the placeholders in Query.
+		Args []any
+	}
+)
+
+// Exists returns a filter that matches rows for which the subquery returns at least one row.
+// The subquery may reference the outer query (correlated subqueries) and uses ? placeholders,
+// which are renumbered for the target driver and merged with the filter's parameters.
+// Combine it with user filters using WithRequiredFilter.
+//
+// Example:
+//
+//	member := where.Exists("SELECT 1 FROM members m WHERE m.user_id = users.id AND m.org_id = ?", orgID)
+//	sql, params, err := filter.ToSQL("postgres", where.WithRequiredFilter(member))
+func Exists(query string, args ...any) *Filter {
+	return existsFilter(query, args, false)
+}
+
+// NotExists returns a filter that matches rows for which the subquery returns no rows. See Exists.
+func NotExists(query string, args ...any) *Filter {
+	return existsFilter(query, args, true)
+}
+
+func existsFilter(query string, args []any, not bool) *Filter {
+	factor := &Factor{Not: not, Exists: &ExistsOp{Query: query, Args: args}}
+	return &Filter{Expression: &Expression{Or: []*Term{{And: []*Factor{factor}}}}}
+}
+
+// buildExists renders the subquery, replacing its ? placeholders with driver placeholders.
+func (b *SQLBuilder) buildExists(exists *ExistsOp, not bool) (string, error) {
+	query := strings.TrimSpace(exists.Query)
+	if query == "" {
+		return "", errors.New("empty EXISTS subquery")
+	}
+
+	var sb strings.Builder
+	var quote rune
+	used := 0
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		ch := runes[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '?' && i+1 < len(runes) && runes[i+1] == '?':
+			i++
+		case ch == '?':
+			if used >= len(exists.Args) {
+				return "", fmt.Errorf("EXISTS subquery has more placeholders than the %d arguments", len(exists.Args))
+			}
+			sb.WriteString(b.addParam(exists.Args[used]))
+			used++
+			continue
+		}
+		sb.WriteRune(ch)
+	}
+
+	if used != len(exists.Args) {
+		return "", fmt.Errorf("EXISTS subquery has %d placeholders but %d arguments", used, len(exists.Args))
+	}
+
+	b.trust(sb.String())
+	if not {
+		return "NOT EXISTS (" + sb.String() + ")", nil
+	}
+	return "EXISTS (" + sb.String() + ")", nil
+}
@@ -0,0 +1,94 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExists(t *testing.T) {
+	user, err := where.Parse("age > 18 AND status = 'active'")
+	require.NoError(t, err)
+
+	member := where.Exists("SELECT 1 FROM members m WHERE m.user_id = users.id AND m.org_id = ?", 42)
+	banned := where.NotExists("SELECT 1 FROM bans b WHERE b.user_id = users.id AND b.reason != '?' AND b.flags ?? 'x'")
+
+	tests := []struct {
+		driver   string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			driver: "postgres",
+			wantSQL: "((age > $1 AND status = $2) AND " +
+				"EXISTS (SELECT 1 FROM members m WHERE m.user_id = users.id AND m.org_id = $3) AND " +
+				"NOT EXISTS (SELECT 1 FROM bans b WHERE b.user_id = users.id AND b.reason != '?' AND b.flags ? 'x'))",
+			wantArgs: []any{float64(18), "active", 42},
+		},
+		{
+			driver: "mysql",
+			wantSQL: "((age > ? AND status = ?) AND " +
+				"EXISTS (SELECT 1 FROM members m WHERE m.user_id = users.id AND m.org_id = ?) AND " +
+				"NOT EXISTS (SELECT 1 FROM bans b WHERE b.user_id = users.id AND b.reason != '?' AND b.flags ? 'x'))",
+			wantArgs: []any{float64(18), "active", 42},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			validator := where.NewValidator().AllowFields("age", "status")
+			sql, args, err := user.ToSQL(tt.driver,
+				where.WithValidator(validator),
+				where.WithRequiredFilter(member, banned),
+			)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestExistsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  *where.Filter
+		wantErr string
+	}{
+		{
+			name:    "empty",
+			filter:  where.Exists(" "),
+			wantErr: "empty EXISTS subquery",
+		},
+		{
+			name:    "missing arguments",
+			filter:  where.Exists("SELECT 1 FROM t WHERE a = ? AND b = ?", 1),
+			wantErr: "EXISTS subquery has more placeholders than the 1 arguments",
+		},
+		{
+			name:    "extra arguments",
+			filter:  where.Exists("SELECT 1 FROM t WHERE a = ?", 1, 2),
+			wantErr: "EXISTS subquery has 1 placeholders but 2 arguments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.filter.ToSQL("postgres")
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestExistsCannotBeParsed(t *testing.T) {
+	_, err := where.Parse("EXISTS (SELECT 1 FROM users)")
+	require.Error(t, err)
+}
+
+func TestExistsFormatAndExplain(t *testing.T) {
+	filter := where.NotExists("SELECT 1 FROM t WHERE t.id = users.id")
+
+	require.Equal(t, "NOT EXISTS (SELECT 1 FROM t WHERE t.id = users.id)", filter.String())
+	require.Equal(t, "NOT\n  EXISTS SELECT 1 FROM t WHERE t.id = users.id", filter.Explain().String())
+	require.Equal(t, 1, where.EstimateComplexity(filter).Predicates)
+}
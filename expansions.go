@@ -0,0 +1,100 @@
+package where
+
+import "strings"
+
+// WithFieldExpansions returns a BuildOption that expands user-facing field names into several
+// columns. A predicate on an expanded field is built once per column and the results are ORed, so
+// that with {"name": {"first_name", "last_name"}} the filter name LIKE 'J%' becomes
+// (first_name LIKE ? OR last_name LIKE ?). Negated predicates such as != and NOT LIKE must hold for
+// every column and are ANDed instead.
+//
+// Every reference to an expanded field in a predicate is replaced, including on the right side and
+// inside function calls, so LOWER(name) = 'j' becomes (LOWER(first_name) = ? OR LOWER(last_name) = ?).
+// Names are case-insensitive and are checked by the validator like any other field, along with their
+// value constraints; the columns are trusted and are not validated.
+func WithFieldExpansions(expansions map[string][]string) BuildOption {
+	return func(b *SQLBuilder) {
+		if b.expansions == nil {
+			b.expansions = make(map[string][]string, len(expansions))
+		}
+		for name, columns := range expansions {
+			b.expansions[strings.ToLower(name)] = columns
+		}
+	}
+}
+
+// expansion returns the first expanded field the predicate refers to, on either side or inside a
+// function call, and the columns it expands to.
+func (b *SQLBuilder) expansion(pred *Predicate) (*FieldRef, []string, bool) {
+	if len(b.expansions) == 0 {
+		return nil, nil, false
+	}
+
+	var field *FieldRef
+	var columns []string
+	walkPredicateValues(pred, func(val *Value) {
+		if field != nil || val.Field == nil || val.Field.column {
+			return
+		}
+		if expanded := b.expansions[strings.ToLower(val.Field.String())]; len(expanded) > 0 {
+			field, columns = val.Field, expanded
+		}
+	})
+	return field, columns, field != nil
+}
+
+// buildExpanded builds the predicate once for each column of an expanded field, replacing every
+// reference to the field. Other expanded fields in the predicate are expanded in turn.
+func (b *SQLBuilder) buildExpanded(pred *Predicate, field *FieldRef, columns []string) (string, error) {
+	if err := b.checkField(field); err != nil {
+		return "", err
+	}
+	if err := b.checkConstraints(pred); err != nil {
+		return "", rejected(err, RejectionMeta{Rule: RuleConstraint, Field: predicateField(pred)})
+	}
+
+	name := strings.ToLower(field.String())
+	parts := make([]string, len(columns))
+	for i, column := range columns {
+		expanded := pred.DeepCopy()
+		walkPredicateValues(expanded, func(val *Value) {
+			if val.Field != nil && !val.Field.column && strings.ToLower(val.Field.String()) == name {
+				val.Field = &FieldRef{Pos: val.Field.Pos, Parts: strings.Split(column, "."), column: true}
+			}
+		})
+
+		part, err := b.buildPredicate(expanded)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = part
+	}
+
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+	if isNegated(pred.Operation) {
+		return "(" + strings.Join(parts, " AND ") + ")", nil
+	}
+	return "(" + strings.Join(parts, " OR ") + ")", nil
+}
+
+// isNegated returns true for operations that exclude a value, such as != and NOT IN.
+func isNegated(op *Operation) bool {
+	switch {
+	case op.Compare != nil:
+		sqlOp := op.Compare.Operator.String()
+		return sqlOp == "!=" || sqlOp == "<>"
+	case op.Like != nil:
+		return op.Like.Not
+	case op.Match != nil:
+		return op.Match.Not
+	case op.Between != nil:
+		return op.Between.Not
+	case op.In != nil:
+		return op.In.Not
+	case op.IsNull != nil:
+		return op.IsNull.Not
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldExpansions(t *testing.T) {
+	expansions := where.WithFieldExpansions(map[string][]string{
+		"name":  {"first_name", "last_name"},
+		"Phone": {"contacts.mobile", "contacts.home", "contacts.work"},
+		"email": {"email_address"},
+	})
+
+	tests := []struct {
+		name     string
+		filter   string
+		driver   string
+		options  []where.BuildOption
+		wantSQL  string
+		wantArgs []any
+		wantErr  string
+	}{
+		{
+			name:     "LIKE is ORed",
+			filter:   "name LIKE 'J%'",
+			driver:   "mysql",
+			wantSQL:  "(first_name LIKE ? OR last_name LIKE ?)",
+			wantArgs: []any{"J%", "J%"},
+		},
+		{
+			name:     "qualified columns and case-insensitive names",
+			filter:   "PHONE = '555' AND age > 18",
+			driver:   "postgres",
+			wantSQL:  "((contacts.mobile = $1 OR contacts.home = $2 OR contacts.work = $3) AND age > $4)",
+			wantArgs: []any{"555", "555", "555", float64(18)},
+		},
+		{
+			name:     "negated predicates are ANDed",
+			filter:   "name NOT ILIKE '%bot%' AND name != 'x' AND name IS NOT NULL",
+			driver:   "postgres",
+			wantSQL:  "((first_name NOT ILIKE $1 AND last_name NOT ILIKE $2) AND (first_name != $3 AND last_name != $4) AND (first_name IS NOT NULL AND last_name IS NOT NULL))",
+			wantArgs: []any{"%bot%", "%bot%", "x", "x"},
+		},
+		{
+			name:     "IN and BETWEEN",
+			filter:   "name IN ('a', 'b') OR name NOT BETWEEN 'a' AND 'c'",
+			driver:   "clickhouse",
+			wantSQL:  "((first_name IN (?, ?) OR last_name IN (?, ?)) OR (first_name NOT BETWEEN ? AND ? AND last_name NOT BETWEEN ? AND ?))",
+			wantArgs: []any{"a", "b", "a", "b", "a", "c", "a", "c"},
+		},
+		{
+			name:     "single column",
+			filter:   "email = 'a@b.c'",
+			driver:   "postgres",
+			wantSQL:  "email_address = $1",
+			wantArgs: []any{"a@b.c"},
+		},
+		{
+			name:     "right side",
+			filter:   "nickname = name",
+			driver:   "mysql",
+			wantSQL:  "(nickname = first_name OR nickname = last_name)",
+			wantArgs: []any{},
+		},
+		{
+			name:     "both sides",
+			filter:   "name = name",
+			driver:   "mysql",
+			wantSQL:  "(first_name = first_name OR last_name = last_name)",
+			wantArgs: []any{},
+		},
+		{
+			name:     "function arguments",
+			filter:   "LOWER(name) = 'x'",
+			driver:   "mysql",
+			wantSQL:  "(LOWER(first_name) = ? OR LOWER(last_name) = ?)",
+			wantArgs: []any{"x", "x"},
+		},
+		{
+			name:     "two expanded fields",
+			filter:   "name != email",
+			driver:   "mysql",
+			wantSQL:  "(first_name != email_address AND last_name != email_address)",
+			wantArgs: []any{},
+		},
+		{
+			name:     "deduplicated parameters",
+			filter:   "name = 'x'",
+			driver:   "postgres",
+			options:  []where.BuildOption{where.WithParamDeduplication()},
+			wantSQL:  "(first_name = $1 OR last_name = $1)",
+			wantArgs: []any{"x"},
+		},
+		{
+			name:     "validator checks the user-facing name",
+			filter:   "name = 'x'",
+			driver:   "postgres",
+			options:  []where.BuildOption{where.WithValidator(where.NewValidator().AllowFields("name"))},
+			wantSQL:  "(first_name = $1 OR last_name = $2)",
+			wantArgs: []any{"x", "x"},
+		},
+		{
+			name:    "validator rejects the user-facing name",
+			filter:  "name = 'x'",
+			driver:  "postgres",
+			options: []where.BuildOption{where.WithValidator(where.NewValidator().AllowFields("first_name", "last_name"))},
+			wantErr: `field "name" is not allowed`,
+		},
+		{
+			name:    "validator checks the other fields",
+			filter:  "name = secret",
+			driver:  "postgres",
+			options: []where.BuildOption{where.WithValidator(where.NewValidator().AllowFields("name"))},
+			wantErr: `field "secret" is not allowed`,
+		},
+		{
+			name:   "constraints apply to the user-facing name",
+			filter: "name = 'x'",
+			driver: "postgres",
+			options: []where.BuildOption{where.WithValidator(where.NewValidator().
+				AllowFields("name").
+				ConstrainField("name", where.FieldConstraint{AllowedValues: []any{"y"}}))},
+			wantErr: `value x is not allowed for field "name"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.filter)
+			require.NoError(t, err)
+
+			sql, args, err := filter.ToSQL(tt.driver, append([]where.BuildOption{expansions}, tt.options...)...)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package where
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Node types used in ExplainNode.Type.
+const (
+	ExplainOr        = "or"
+	ExplainAnd       = "and"
+	ExplainNot       = "not"
+	ExplainPredicate = "predicate"
+	ExplainExists    = "exists"
+	ExplainField     = "field"
+	ExplainFunction  = "function"
+	ExplainLiteral   = "literal"
+	ExplainBitwise   = "bitwise"
+)
+
+type (
+	// ExplainNode is a node in the structured plan of a parsed filter returned by Filter.Explain.
+	// The tree can be rendered as indented text with String or serialized with encoding/json.
+	ExplainNode struct {
+		// Type is one of the Explain* node type constants.
+		Type string `json:"type"`
+
+		// Operator is the SQL operator for predicate nodes (e.g. "=", "NOT IN", "IS NULL") and
+		// bitwise nodes (e.g. "&").
+		Operator string `json:"operator,omitempty"`
+
+		// Name is the field name for field nodes, the function name for function nodes, and the
+		// subquery for exists nodes.
+		Name string `json:"name,omitempty"`
+
+		// Value is the Go value of a literal node.
+		Value any `json:"value,omitempty"`
+
+		// ValueType is the literal type for literal nodes: "string", "binary", "number", "boolean", or "null".
+		ValueType string `json:"valueType,omitempty"`
+
+		// CastType is the target type for CAST function nodes.
+		CastType string `json:"castType,omitempty"`
+
+		// Children are the operands of this node.
+		Children []*ExplainNode `json:"children,omitempty"`
+	}
+)
+
+// Explain returns a structured tree describing the parsed filter's operators, fields, literal
+// types, and nesting. It is intended for visualizing filters without reading the generated SQL.
+func (f *Filter) Explain() *ExplainNode {
+	if f == nil {
+		return nil
+	}
+	return explainExpression(f.Expression)
+}
+
+// String renders the node and its children as an indented tree.
+func (n *ExplainNode) String() string {
+	var sb strings.Builder
+	n.write(&sb, 0)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (n *ExplainNode) write(sb *strings.Builder, depth int) {
+	if n == nil {
+		return
+	}
+
+	sb.WriteString(strings.Repeat("  ", depth))
+	sb.WriteString(strings.ToUpper(n.Type))
+
+	switch n.Type {
+	case ExplainPredicate, ExplainBitwise:
+		sb.WriteString(" " + n.Operator)
+	case ExplainField, ExplainFunction, ExplainExists:
+		sb.WriteString(" " + n.Name)
+		if n.CastType != "" {
+			sb.WriteString(" AS " + n.CastType)
+		}
+	case ExplainLiteral:
+		sb.WriteString(" " + formatExplainValue(n.Value, n.ValueType) + " (" + n.ValueType + ")")
+	}
+	sb.WriteString("\n")
+
+	for _, child := range n.Children {
+		child.write(sb, depth+1)
+	}
+}
+
+func formatExplainValue(value any, valueType string) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []byte:
+		return fmt.Sprintf("0x%X", v)
+	case nil:
+		return strings.ToUpper(valueType)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func explainExpression(expr *Expression) *ExplainNode {
+	if expr == nil || len(expr.Or) == 0 {
+		return nil
+	}
+	if len(expr.Or) == 1 {
+		return explainTerm(expr.Or[0])
+	}
+
+	node := &ExplainNode{Type: ExplainOr}
+	for _, term := range expr.Or {
+		node.Children = append(node.Children, explainTerm(term))
+	}
+	return node
+}
+
+func explainTerm(term *Term) *ExplainNode {
+	if term == nil || len(term.And) == 0 {
+		return nil
+	}
+	if len(term.And) == 1 {
+		return explainFactor(term.And[0])
+	}
+
+	node := &ExplainNode{Type: ExplainAnd}
+	for _, factor := range term.And {
+		node.Children = append(node.Children, explainFactor(factor))
+	}
+	return node
+}
+
+func explainFactor(factor *Factor) *ExplainNode {
+	if factor == nil {
+		return nil
+	}
+
+	var node *ExplainNode
+	if factor.Exists != nil {
+		node = &ExplainNode{Type: ExplainExists, Name: factor.Exists.Query}
+	} else if factor.SubExpr != nil {
+		node = explainExpression(factor.SubExpr)
+	} else {
+		node = explainPredicate(factor.Predicate)
+	}
+
+	if factor.Not {
+		return &ExplainNode{Type: ExplainNot, Children: []*ExplainNode{node}}
+	}
+	return node
+}
+
+func explainPredicate(pred *Predicate) *ExplainNode {
+	if pred == nil || pred.Operation == nil {
+		return nil
+	}
+
+	op := pred.Operation
+	node := &ExplainNode{
+		Type:     ExplainPredicate,
+		Operator: op.sqlOperator(),
+		Children: []*ExplainNode{explainValue(pred.Left)},
+	}
+
+	for _, operand := range op.operands() {
+		node.Children = append(node.Children, explainValue(operand))
+	}
+	return node
+}
+
+func explainValue(val *Value) *ExplainNode {
+	if val == nil {
+		return nil
+	}
+
+	switch {
+	case val.Function != nil:
+		node := &ExplainNode{Type: ExplainFunction, Name: val.Function.Name}
+		if val.Function.isCast() {
+			node.CastType = val.Function.CastAs.String()
+		}
+		for _, arg := range val.Function.arguments() {
+			node.Children = append(node.Children, explainValue(arg))
+		}
+		return node
+	case val.Field != nil:
+		return &ExplainNode{Type: ExplainField, Name: val.Field.String()}
+	case val.Literal != nil:
+		return &ExplainNode{Type: ExplainLiteral, Value: val.Literal.Value(), ValueType: val.Literal.Type()}
+	case val.SubExpr != nil:
+		return explainExpression(val.SubExpr)
+	case val.Bitwise != nil:
+		// Operations are evaluated left to right, so each one applies to the result of the last.
+		node := explainValue(val.Bitwise.Left)
+		for _, op := range val.Bitwise.Ops {
+			node = &ExplainNode{Type: ExplainBitwise, Operator: op.Operator, Children: []*ExplainNode{node, explainValue(op.Right)}}
+		}
+		return node
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package where_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterExplain(t *testing.T) {
+	filter, err := where.Parse("(age >= 18 OR verified = true) AND NOT (status IN ('banned', 'deleted')) AND LOWER(email) LIKE '%@example.com' AND deleted_at IS NULL")
+	require.NoError(t, err)
+
+	want := `AND
+  OR
+    PREDICATE >=
+      FIELD age
+      LITERAL 18 (number)
+    PREDICATE =
+      FIELD verified
+      LITERAL true (boolean)
+  NOT
+    PREDICATE IN
+      FIELD status
+      LITERAL "banned" (string)
+      LITERAL "deleted" (string)
+  PREDICATE LIKE
+    FUNCTION LOWER
+      FIELD email
+    LITERAL "%@example.com" (string)
+  PREDICATE IS NULL
+    FIELD deleted_at`
+
+	require.Equal(t, want, filter.Explain().String())
+}
+
+func TestFilterExplainJSON(t *testing.T) {
+	filter, err := where.Parse("users.age NOT BETWEEN 18 AND 65 OR parent_id = NULL")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(filter.Explain())
+	require.NoError(t, err)
+
+	want := `{
+		"type": "or",
+		"children": [
+			{
+				"type": "predicate",
+				"operator": "NOT BETWEEN",
+				"children": [
+					{"type": "field", "name": "users.age"},
+					{"type": "literal", "value": 18, "valueType": "number"},
+					{"type": "literal", "value": 65, "valueType": "number"}
+				]
+			},
+			{
+				"type": "predicate",
+				"operator": "=",
+				"children": [
+					{"type": "field", "name": "parent_id"},
+					{"type": "literal", "valueType": "null"}
+				]
+			}
+		]
+	}`
+	require.JSONEq(t, want, string(data))
+}
+
+func TestFilterExplainNil(t *testing.T) {
+	var filter *where.Filter
+	require.Nil(t, filter.Explain())
+}
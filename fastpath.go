@@ -0,0 +1,156 @@
+package where
+
+import "github.com/alecthomas/participle/v2/lexer"
+
+// inListScanner builds the AST for filters of the form `field [NOT] IN (literal, ...)` directly
+// from the token stream. Generated filters often consist of a single IN list with thousands of
+// values, for which the backtracking grammar is slow and allocation heavy.
+type inListScanner struct {
+	lex     lexer.Lexer
+	symbols map[lexer.TokenType]string
+	tok     lexer.Token
+}
+
+// parseINList returns the filter for a simple IN list input. ok is false when the input has any
+// other shape, including lexical errors, in which case it must be parsed by the grammar.
+func (p *Parser) parseINList(input string) (*Filter, bool) {
+	lex, err := p.lexer.LexString("", input)
+	if err != nil {
+		return nil, false
+	}
+
+	s := &inListScanner{lex: lex, symbols: p.symbols}
+	if !s.next() {
+		return nil, false
+	}
+
+	filter := &Filter{Pos: s.tok.Pos}
+	pred, ok := s.predicate()
+	if !ok || !s.tok.EOF() {
+		return nil, false
+	}
+
+	filter.Expression = &Expression{Or: []*Term{{And: []*Factor{{Predicate: pred}}}}}
+	return filter, true
+}
+
+// next advances to the next non-whitespace token, returning false on a lexical error.
+func (s *inListScanner) next() bool {
+	for {
+		tok, err := s.lex.Next()
+		if err != nil {
+			return false
+		}
+		if s.symbols[tok.Type] != "Whitespace" {
+			s.tok = tok
+			return true
+		}
+	}
+}
+
+// accept advances past the current token if it has the given type.
+func (s *inListScanner) accept(typ string) bool {
+	if s.symbols[s.tok.Type] != typ {
+		return false
+	}
+	return s.next()
+}
+
+func (s *inListScanner) predicate() (*Predicate, bool) {
+	pos := s.tok.Pos
+	field, ok := s.field()
+	if !ok {
+		return nil, false
+	}
+
+	in := &InOp{}
+	if s.symbols[s.tok.Type] == "Not" {
+		in.Not = true
+		if !s.next() {
+			return nil, false
+		}
+	}
+
+	in.In = s.tok.Value
+	if !s.accept("In") || !s.accept("LParen") {
+		return nil, false
+	}
+
+	if !s.accept("RParen") {
+		for {
+			lit, ok := s.literal()
+			if !ok {
+				return nil, false
+			}
+			in.Values = append(in.Values, &Value{Literal: lit})
+
+			if s.accept("RParen") {
+				break
+			}
+			if !s.accept("Comma") {
+				return nil, false
+			}
+		}
+	}
+
+	return &Predicate{Pos: pos, Left: &Value{Field: field}, Operation: &Operation{In: in}}, true
+}
+
+func (s *inListScanner) field() (*FieldRef, bool) {
+	field := &FieldRef{Pos: s.tok.Pos}
+	for {
+		switch s.symbols[s.tok.Type] {
+		case "Ident", "QuotedIdent", "BacktickIdent":
+			field.Parts = append(field.Parts, s.tok.Value)
+		default:
+			return nil, false
+		}
+
+		if !s.next() {
+			return nil, false
+		}
+		if s.symbols[s.tok.Type] != "Dot" {
+			return field, true
+		}
+		if !s.next() {
+			return nil, false
+		}
+	}
+}
+
+func (s *inListScanner) literal() (*LiteralValue, bool) {
+	var sign string
+	if typ := s.symbols[s.tok.Type]; typ == "Minus" || typ == "Plus" {
+		sign = s.tok.Value
+		if !s.next() || s.symbols[s.tok.Type] != "Number" {
+			return nil, false
+		}
+	}
+
+	value := s.tok.Value
+	lit := &LiteralValue{}
+
+	switch s.symbols[s.tok.Type] {
+	case "String", "DoubleQuotedString":
+		lit.String = &value
+	case "Hex":
+		lit.Hex = &value
+	case "Number":
+		numeral, err := normalizeNumeral(value)
+		if err != nil {
+			return nil, false
+		}
+		numeral = sign + numeral
+		lit.Numeral = &numeral
+	case "True":
+		lit.Boolean = &BooleanLit{True: true}
+	case "False":
+		lit.Boolean = &BooleanLit{False: true}
+	case "Null":
+		lit.Null = true
+	default:
+		return nil, false
+	}
+
+	return lit, s.next()
+}
@@ -0,0 +1,80 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseINListFastPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []where.ParserOption
+	}{
+		{name: "numbers", input: "id IN (1, -2, 3.5, 1e3)"},
+		{name: "strings", input: `status in ('a', "b", 'it\'s')`},
+		{name: "standard escapes", input: "name IN ('it''s')", opts: []where.ParserOption{where.WithEscapeMode(where.EscapeStandard)}},
+		{name: "mixed literals", input: "v NOT IN (0xCAFE, TRUE, false, NULL, 'x')"},
+		{name: "qualified field", input: "users.`user id` IN (1)"},
+		{name: "quoted field", input: `"Order".id NOT IN (1, 2)`},
+		{name: "empty list", input: "id IN ()", opts: []where.ParserOption{where.WithEmptyIN()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := where.NewParser(tt.opts...)
+			require.NoError(t, err)
+
+			filter, err := parser.Parse(tt.input)
+			require.NoError(t, err)
+
+			// Another condition forces the full grammar without moving the IN list.
+			full, err := parser.Parse(tt.input + " OR x = 1")
+			require.NoError(t, err)
+			require.Equal(t, full.Expression.Or[:1], filter.Expression.Or)
+			require.Equal(t, 1, filter.Pos.Line)
+			require.Equal(t, 1, filter.Pos.Column)
+		})
+	}
+}
+
+func TestParseINListFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantSQL string
+		wantErr string
+	}{
+		{name: "followed by more conditions", input: "id IN (1, 2) AND x = 1", wantSQL: "(id IN ($1, $2) AND x = $3)"},
+		{name: "function on the left", input: "LOWER(name) IN ('a')", wantSQL: "LOWER(name) IN ($1)"},
+		{name: "field in the list", input: "id IN (1, other_id)", wantSQL: "id IN ($1, other_id)"},
+		{name: "unterminated list", input: "id IN (1, 2", wantErr: "failed to parse filter expression"},
+		{name: "trailing tokens", input: "id IN (1) )", wantErr: "failed to parse filter expression"},
+		{name: "empty list", input: "id IN ()", wantErr: "IN expression requires at least one value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			sql, _, err := filter.ToSQL("postgres")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+		})
+	}
+}
+
+func TestParseINListLimits(t *testing.T) {
+	parser, err := where.NewParser(where.WithMaxINItems(2))
+	require.NoError(t, err)
+
+	_, err = parser.Parse("id IN (1, 2, 3)")
+	require.ErrorContains(t, err, "IN expression exceeds maximum of 2 items")
+}
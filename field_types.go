@@ -0,0 +1,156 @@
+package where
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// FieldTypeUUID binds UUID string literals in canonical lowercase hyphenated form.
+	FieldTypeUUID FieldType = "uuid"
+
+	// FieldTypeUUIDBinary binds UUID literals as 16 byte slices, e.g. for MySQL BINARY(16) columns.
+	FieldTypeUUIDBinary FieldType = "uuid_binary"
+
+	// FieldTypeBinary binds string and hex (0x...) literals as byte slices.
+	FieldTypeBinary FieldType = "binary"
+
+	// FieldTypeTime binds string literals as time.Time values using the configured time layouts.
+	FieldTypeTime FieldType = "time"
+
+	// FieldTypeDate binds string literals as time.Time values at midnight UTC on the literal's date,
+	// for DATE columns.
+	FieldTypeDate FieldType = "date"
+)
+
+type (
+	// FieldType describes how literals compared against a field should be bound as parameters.
+	FieldType string
+)
+
+// WithFieldTypes returns a BuildOption that declares column types for fields. Literals compared
+// against a typed field are converted to the matching Go type before being bound, and literals that
+// cannot be converted fail SQL generation. Field names are case-insensitive.
+func WithFieldTypes(types map[string]FieldType) BuildOption {
+	return func(b *SQLBuilder) {
+		if b.fieldTypes == nil {
+			b.fieldTypes = make(map[string]FieldType, len(types))
+		}
+		for field, typ := range types {
+			b.fieldTypes[strings.ToLower(field)] = typ
+		}
+	}
+}
+
+// predicateFieldType returns the declared type of the field a predicate compares literals against.
+func (b *SQLBuilder) predicateFieldType(pred *Predicate) (string, FieldType) {
+	if len(b.fieldTypes) == 0 {
+		return "", ""
+	}
+
+	field := pred.Left.Field
+	if field == nil && pred.Operation.Compare != nil && pred.Operation.Compare.Right != nil {
+		field = pred.Operation.Compare.Right.Field
+	}
+	if field == nil {
+		return "", ""
+	}
+
+	name := field.String()
+	return name, b.fieldTypes[strings.ToLower(name)]
+}
+
+// typedParam converts a literal value to the Go type required by the current field type.
+func (b *SQLBuilder) typedParam(value any) (any, error) {
+	switch b.fieldType {
+	case FieldTypeUUID:
+		s, ok := value.(string)
+		if !ok {
+			return nil, newMessage(MsgInvalidUUID, "value", value, "field", strconv.Quote(b.field))
+		}
+		id, ok := parseUUID(s)
+		if !ok {
+			return nil, newMessage(MsgInvalidUUID, "value", strconv.Quote(s), "field", strconv.Quote(b.field))
+		}
+		return formatUUID(id), nil
+	case FieldTypeUUIDBinary:
+		switch v := value.(type) {
+		case string:
+			if id, ok := parseUUID(v); ok {
+				return id[:], nil
+			}
+		case []byte:
+			if len(v) == 16 {
+				return v, nil
+			}
+		}
+		return nil, newMessage(MsgInvalidUUID, "value", value, "field", strconv.Quote(b.field))
+	case FieldTypeBinary:
+		if s, ok := value.(string); ok {
+			return []byte(s), nil
+		}
+		return value, nil
+	case FieldTypeTime, FieldTypeDate:
+		s, ok := value.(string)
+		if !ok {
+			return nil, newMessage(MsgInvalidTime, "value", value, "field", strconv.Quote(b.field))
+		}
+		layouts := b.timeLayouts
+		if len(layouts) == 0 {
+			layouts = DefaultTimeLayouts
+		}
+		t, ok := b.parseTimeParam(s, layouts)
+		if !ok {
+			return nil, newMessage(MsgInvalidTime, "value", strconv.Quote(s), "field", strconv.Quote(b.field))
+		}
+		if b.fieldType == FieldTypeDate {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+		return t, nil
+	default:
+		return value, nil
+	}
+}
+
+// parseUUID parses a UUID in hyphenated (8-4-4-4-12) or plain 32 hex digit form.
+func parseUUID(s string) ([16]byte, bool) {
+	var id [16]byte
+
+	switch len(s) {
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return id, false
+		}
+		s = strings.ReplaceAll(s, "-", "")
+	case 32:
+	default:
+		return id, false
+	}
+
+	if _, err := hex.Decode(id[:], []byte(s)); err != nil {
+		return id, false
+	}
+	return id, true
+}
+
+func formatUUID(id [16]byte) string {
+	s := hex.EncodeToString(id[:])
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// decodeHexLiteral decodes a 0x prefixed hex literal into bytes, padding odd lengths with a leading zero.
+// Digits may be separated by underscores.
+func decodeHexLiteral(token string) []byte {
+	digits := strings.ReplaceAll(token[2:], "_", "")
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+
+	data, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil
+	}
+	return data
+}
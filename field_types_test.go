@@ -0,0 +1,124 @@
+package where_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFieldTypes(t *testing.T) {
+	uuidBytes := []byte{
+		0x55, 0x0e, 0x84, 0x00, 0xe2, 0x9b, 0x41, 0xd4,
+		0xa7, 0x16, 0x44, 0x66, 0x55, 0x44, 0x00, 0x00,
+	}
+
+	types := map[string]where.FieldType{
+		"id":         where.FieldTypeUUID,
+		"user_id":    where.FieldTypeUUIDBinary,
+		"hash":       where.FieldTypeBinary,
+		"created_at": where.FieldTypeTime,
+		"birthday":   where.FieldTypeDate,
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		wantArgs []any
+		wantErr  string
+	}{
+		{
+			name:     "UUID normalized",
+			input:    "id = '550E8400-E29B-41D4-A716-446655440000'",
+			wantArgs: []any{"550e8400-e29b-41d4-a716-446655440000"},
+		},
+		{
+			name:     "UUID without hyphens",
+			input:    "ID IN ('550e8400e29b41d4a716446655440000')",
+			wantArgs: []any{"550e8400-e29b-41d4-a716-446655440000"},
+		},
+		{
+			name:    "invalid UUID",
+			input:   "id = 'not-a-uuid'",
+			wantErr: `invalid UUID "not-a-uuid" for field "id"`,
+		},
+		{
+			name:     "binary UUID from string",
+			input:    "user_id = '550e8400-e29b-41d4-a716-446655440000'",
+			wantArgs: []any{uuidBytes},
+		},
+		{
+			name:     "binary UUID from hex literal",
+			input:    "user_id = 0x550E8400E29B41D4A716446655440000",
+			wantArgs: []any{uuidBytes},
+		},
+		{
+			name:    "binary UUID wrong size",
+			input:   "user_id = 0xFF",
+			wantErr: `invalid UUID [255] for field "user_id"`,
+		},
+		{
+			name:     "binary field",
+			input:    "hash IN (0xDEADBEEF, 'abc', 0xF)",
+			wantArgs: []any{[]byte{0xde, 0xad, 0xbe, 0xef}, []byte("abc"), []byte{0x0f}},
+		},
+		{
+			name:     "time field",
+			input:    "created_at > '2024-01-15' AND name = '2024-01-15'",
+			wantArgs: []any{time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), "2024-01-15"},
+		},
+		{
+			name:     "date field",
+			input:    "birthday BETWEEN '2024-01-15' AND '2024-02-01T23:30:00'",
+			wantArgs: []any{time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:    "invalid date",
+			input:   "birthday = 'soon'",
+			wantErr: `invalid time "soon" for field "birthday"`,
+		},
+		{
+			name:    "invalid time",
+			input:   "created_at > 'yesterday'",
+			wantErr: `invalid time "yesterday" for field "created_at"`,
+		},
+		{
+			name:     "reversed comparison",
+			input:    "'550E8400-E29B-41D4-A716-446655440000' = id",
+			wantArgs: []any{"550e8400-e29b-41d4-a716-446655440000"},
+		},
+		{
+			name:     "function arguments are not typed",
+			input:    "id = LOWER('X')",
+			wantArgs: []any{"X"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			_, args, err := filter.ToSQL("mysql", where.WithFieldTypes(types))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestHexLiterals(t *testing.T) {
+	filter, err := where.Parse("data = 0xCAFE")
+	require.NoError(t, err)
+
+	sql, args, err := filter.ToSQL("postgres")
+	require.NoError(t, err)
+	require.Equal(t, "data = $1", sql)
+	require.Equal(t, []any{[]byte{0xca, 0xfe}}, args)
+	require.Equal(t, "data = 0xCAFE", filter.String())
+	require.Equal(t, "PREDICATE =\n  FIELD data\n  LITERAL 0xCAFE (binary)", filter.Explain().String())
+}
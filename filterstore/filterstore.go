@@ -0,0 +1,259 @@
+// Package filterstore saves named filters, such as the saved searches of a user, and loads them back
+// as parsed filters.
+//
+// Filters are persisted as a versioned JSON document of the filter's AST, so stored filters do not
+// need to be parsed again and keep working as the filter syntax evolves. MemoryStore keeps filters in
+// memory and SQLStore keeps them in a database table:
+//
+//	store := filterstore.NewMemoryStore()
+//	err := store.Save(ctx, &filterstore.SavedFilter{Owner: "alice", Name: "adults", Filter: filter})
+//
+//	saved, err := store.Get(ctx, "alice", "adults")
+//	sql, params, err := saved.Filter.ToSQL("postgres")
+package filterstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/where"
+)
+
+// SchemaVersion is the version of the JSON documents written by Encode.
+const SchemaVersion = 1
+
+// ErrNotFound is returned when a saved filter does not exist.
+var ErrNotFound = errors.New("filter not found")
+
+type (
+	// SavedFilter is a named filter belonging to an owner, such as a user or tenant.
+	SavedFilter struct {
+		Owner     string
+		Name      string
+		Filter    *where.Filter
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+
+	// Store persists saved filters, identified by owner and name.
+	Store interface {
+		// Save creates or replaces the filter with the same owner and name. CreatedAt and UpdatedAt
+		// are set by the store.
+		Save(ctx context.Context, filter *SavedFilter) error
+
+		// Get returns the named filter, or ErrNotFound.
+		Get(ctx context.Context, owner, name string) (*SavedFilter, error)
+
+		// List returns the owner's filters ordered by name.
+		List(ctx context.Context, owner string) ([]*SavedFilter, error)
+
+		// Delete removes the named filter, or returns ErrNotFound.
+		Delete(ctx context.Context, owner, name string) error
+	}
+
+	// document is the JSON representation of a stored filter.
+	document struct {
+		Version int           `json:"version"`
+		Filter  *where.Filter `json:"filter"`
+	}
+)
+
+// Encode returns the JSON document for a filter. Values bound by constructors such as where.In and
+// strings parsed with where.EscapeStandard are stored as the equivalent literals: numbers are bound
+// as float64, times as RFC 3339 strings, and booleans and nil are written inline as TRUE, FALSE, and
+// NULL. Bound values must be strings, numbers, booleans, byte slices, times, or nil. EXISTS
+// subqueries cannot be stored.
+func Encode(filter *where.Filter) ([]byte, error) {
+	if filter == nil || filter.Expression == nil {
+		return nil, errors.New("empty filter")
+	}
+
+	filter = filter.Clone()
+	if err := normalizeExpression(filter.Expression); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(document{Version: SchemaVersion, Filter: filter})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode filter")
+	}
+	return data, nil
+}
+
+// Decode returns the filter stored in a JSON document written by Encode.
+func Decode(data []byte) (*where.Filter, error) {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(err, "failed to decode filter")
+	}
+
+	if doc.Version < 1 || doc.Version > SchemaVersion {
+		return nil, fmt.Errorf("unsupported filter schema version %d", doc.Version)
+	}
+	if doc.Filter == nil || doc.Filter.Expression == nil {
+		return nil, errors.New("failed to decode filter: missing expression")
+	}
+	return doc.Filter, nil
+}
+
+// validate checks the fields required to save a filter.
+func validate(filter *SavedFilter) error {
+	if filter == nil || filter.Name == "" {
+		return errors.New("saved filter requires a name")
+	}
+	if filter.Filter == nil {
+		return fmt.Errorf("saved filter %q has no filter", filter.Name)
+	}
+	return nil
+}
+
+func normalizeExpression(expr *where.Expression) error {
+	if expr == nil {
+		return nil
+	}
+
+	for _, term := range expr.Or {
+		if term == nil {
+			continue
+		}
+		for _, factor := range term.And {
+			if factor == nil {
+				continue
+			}
+			if factor.Exists != nil {
+				return errors.New("EXISTS subqueries cannot be stored")
+			}
+			if err := normalizeExpression(factor.SubExpr); err != nil {
+				return err
+			}
+			if err := normalizePredicate(factor.Predicate); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func normalizePredicate(pred *where.Predicate) error {
+	if pred == nil {
+		return nil
+	}
+
+	values := []*where.Value{pred.Left}
+	if op := pred.Operation; op != nil {
+		switch {
+		case op.Compare != nil:
+			values = append(values, op.Compare.Right)
+		case op.Like != nil:
+			values = append(values, op.Like.Pattern)
+		case op.Match != nil:
+			values = append(values, op.Match.Query)
+		case op.Between != nil:
+			values = append(values, op.Between.Lower, op.Between.Upper)
+		case op.In != nil:
+			values = append(values, op.In.Values...)
+		}
+	}
+
+	for _, val := range values {
+		if err := normalizeValue(val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func normalizeValue(val *where.Value) error {
+	if val == nil {
+		return nil
+	}
+
+	if val.Function != nil {
+		if err := normalizeExpression(val.Function.Cond); err != nil {
+			return err
+		}
+		for _, arg := range val.Function.Args {
+			if err := normalizeValue(arg); err != nil {
+				return err
+			}
+		}
+	}
+	if val.Literal != nil {
+		if err := normalizeLiteral(val.Literal); err != nil {
+			return err
+		}
+	}
+	if val.Bitwise != nil {
+		if err := normalizeValue(val.Bitwise.Left); err != nil {
+			return err
+		}
+		for _, op := range val.Bitwise.Ops {
+			if err := normalizeValue(op.Right); err != nil {
+				return err
+			}
+		}
+	}
+	return normalizeExpression(val.SubExpr)
+}
+
+// normalizeLiteral rewrites string literals with backslash escapes, the parser's default, and
+// replaces bound values with the equivalent literal.
+func normalizeLiteral(lit *where.LiteralValue) error {
+	switch {
+	case lit.String != nil:
+		s, _ := lit.Value().(string)
+		*lit = where.LiteralValue{String: quote(s)}
+	case lit.Hex != nil, lit.Numeral != nil, lit.Number != nil, lit.Boolean != nil, lit.Null, lit.Variable != nil:
+	default:
+		bound, err := boundLiteral(lit.Value())
+		if err != nil {
+			return err
+		}
+		*lit = bound
+	}
+	return nil
+}
+
+// boundLiteral returns the literal for a value bound by a constructor such as where.In.
+func boundLiteral(value any) (where.LiteralValue, error) {
+	switch v := value.(type) {
+	case nil:
+		return where.LiteralValue{Null: true}, nil
+	case string:
+		return where.LiteralValue{String: quote(v)}, nil
+	case time.Time:
+		return where.LiteralValue{String: quote(v.Format(time.RFC3339Nano))}, nil
+	case bool:
+		return where.LiteralValue{Boolean: &where.BooleanLit{True: v, False: !v}}, nil
+	case []byte:
+		hex := fmt.Sprintf("0x%X", v)
+		return where.LiteralValue{Hex: &hex}, nil
+	case float32:
+		return number(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case float64:
+		return number(strconv.FormatFloat(v, 'g', -1, 64))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return number(fmt.Sprint(v))
+	default:
+		return where.LiteralValue{}, fmt.Errorf("cannot store value of type %T", value)
+	}
+}
+
+func number(numeral string) (where.LiteralValue, error) {
+	n, err := strconv.ParseFloat(numeral, 64)
+	if err != nil {
+		return where.LiteralValue{}, errors.Wrapf(err, "invalid number %s", numeral)
+	}
+	return where.LiteralValue{Numeral: &numeral, Number: &n}, nil
+}
+
+// quote returns s as a single-quoted literal with backslash escapes.
+func quote(s string) *string {
+	quoted := "'" + strings.NewReplacer(`\`, `\\`, `'`, `''`).Replace(s) + "'"
+	return &quoted
+}
@@ -0,0 +1,139 @@
+package filterstore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pseudomuto/where"
+	_ "github.com/pseudomuto/where/drivers/postgres"
+	"github.com/pseudomuto/where/filterstore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter func(t *testing.T) *where.Filter
+	}{
+		{name: "comparisons", filter: parse("age >= 18 AND status = 'active'")},
+		{name: "grouping", filter: parse("NOT (status = 'active' OR age < 18) AND deleted_at IS NULL")},
+		{name: "operators", filter: parse("name NOT ILIKE 'j%' AND age BETWEEN 18 AND 65 AND id IN (1, 2, 3)")},
+		{name: "functions", filter: parse("LOWER(email) LIKE '%@example.com' AND CAST(age AS TEXT) = '30'")},
+		{name: "escapes", filter: parse(`name = 'O''Brien' AND path = 'C:\\temp\n'`)},
+		{name: "literals", filter: parse("data = 0xCAFE AND active = TRUE AND score > 1.5e3 AND deleted_at IS NULL")},
+		{name: "variables", filter: parse("created_at > :since")},
+		{name: "standard escapes", filter: func(t *testing.T) *where.Filter {
+			t.Helper()
+			parser, err := where.NewParser(where.WithEscapeMode(where.EscapeStandard))
+			require.NoError(t, err)
+			filter, err := parser.Parse(`path = 'C:\temp' AND name = 'it''s'`)
+			require.NoError(t, err)
+			return filter
+		}},
+		{name: "bound values", filter: func(*testing.T) *where.Filter {
+			return and(
+				where.In("status", "active", "pending"),
+				where.In("id", 1, int64(2), uint8(3), 4.5),
+				where.In("data", []byte{0xca, 0xfe}),
+				where.In("created_at", time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)),
+			)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter(t)
+			data, err := filterstore.Encode(filter)
+			require.NoError(t, err)
+
+			decoded, err := filterstore.Decode(data)
+			require.NoError(t, err)
+
+			opts := where.WithVariables(map[string]any{"since": "2024-01-01"})
+			wantSQL, wantParams, err := filter.ToSQL("postgres", opts)
+			require.NoError(t, err)
+			gotSQL, gotParams, err := decoded.ToSQL("postgres", opts)
+			require.NoError(t, err)
+
+			require.Equal(t, wantSQL, gotSQL)
+			require.Equal(t, normalize(wantParams), gotParams)
+		})
+	}
+}
+
+func TestEncodeBoundLiterals(t *testing.T) {
+	data, err := filterstore.Encode(where.In("flag", true, false, nil))
+	require.NoError(t, err)
+
+	decoded, err := filterstore.Decode(data)
+	require.NoError(t, err)
+	require.Equal(t, "flag IN (TRUE, FALSE, NULL)", decoded.String())
+}
+
+func TestEncodeErrors(t *testing.T) {
+	_, err := filterstore.Encode(nil)
+	require.EqualError(t, err, "empty filter")
+
+	_, err = filterstore.Encode(where.In("id", struct{}{}))
+	require.EqualError(t, err, "cannot store value of type struct {}")
+
+	_, err = filterstore.Encode(where.Exists("SELECT 1 FROM orders WHERE orders.user_id = users.id"))
+	require.EqualError(t, err, "EXISTS subqueries cannot be stored")
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{data: `{"version":2,"filter":{"Expression":{}}}`, err: "unsupported filter schema version 2"},
+		{data: `{"filter":{"Expression":{}}}`, err: "unsupported filter schema version 0"},
+		{data: `{"version":1}`, err: "failed to decode filter: missing expression"},
+		{data: `not json`, err: "failed to decode filter: invalid character 'o' in literal null (expecting 'u')"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			_, err := filterstore.Decode([]byte(tt.data))
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func parse(input string) func(*testing.T) *where.Filter {
+	return func(t *testing.T) *where.Filter {
+		t.Helper()
+		filter, err := where.Parse(input)
+		require.NoError(t, err)
+		return filter
+	}
+}
+
+// and combines the filters with AND.
+func and(filters ...*where.Filter) *where.Filter {
+	term := &where.Term{}
+	for _, filter := range filters {
+		term.And = append(term.And, filter.Expression.Or[0].And...)
+	}
+	return &where.Filter{Expression: &where.Expression{Or: []*where.Term{term}}}
+}
+
+// normalize converts bound values to the types the equivalent literals are bound as.
+func normalize(params []any) []any {
+	out := make([]any, len(params))
+	for i, param := range params {
+		switch v := param.(type) {
+		case int:
+			out[i] = float64(v)
+		case int64:
+			out[i] = float64(v)
+		case uint8:
+			out[i] = float64(v)
+		case time.Time:
+			out[i] = v.Format(time.RFC3339Nano)
+		default:
+			out[i] = param
+		}
+	}
+	return out
+}
@@ -0,0 +1,124 @@
+package filterstore
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store that keeps filters in memory. It is safe for concurrent use and is
+// intended for tests and single-process applications.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	filters map[memoryKey]memoryEntry
+}
+
+type (
+	memoryKey struct {
+		owner string
+		name  string
+	}
+
+	// memoryEntry holds the encoded filter, so stored filters are not affected by later changes to
+	// the saved or returned filters.
+	memoryEntry struct {
+		data      []byte
+		createdAt time.Time
+		updatedAt time.Time
+	}
+)
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{filters: make(map[memoryKey]memoryEntry)}
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(_ context.Context, filter *SavedFilter) error {
+	if err := validate(filter); err != nil {
+		return err
+	}
+
+	data, err := Encode(filter.Filter)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memoryKey{owner: filter.Owner, name: filter.Name}
+	now := time.Now().UTC()
+	entry := memoryEntry{data: data, createdAt: now, updatedAt: now}
+	if existing, ok := s.filters[key]; ok {
+		entry.createdAt = existing.createdAt
+	}
+	s.filters[key] = entry
+
+	filter.CreatedAt, filter.UpdatedAt = entry.createdAt, entry.updatedAt
+	return nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, owner, name string) (*SavedFilter, error) {
+	s.mu.RLock()
+	entry, ok := s.filters[memoryKey{owner: owner, name: name}]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return entry.saved(owner, name)
+}
+
+// List implements Store.
+func (s *MemoryStore) List(_ context.Context, owner string) ([]*SavedFilter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var filters []*SavedFilter
+	for key, entry := range s.filters {
+		if key.owner != owner {
+			continue
+		}
+
+		saved, err := entry.saved(key.owner, key.name)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, saved)
+	}
+
+	slices.SortFunc(filters, func(a, b *SavedFilter) int { return strings.Compare(a.Name, b.Name) })
+	return filters, nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(_ context.Context, owner, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memoryKey{owner: owner, name: name}
+	if _, ok := s.filters[key]; !ok {
+		return ErrNotFound
+	}
+	delete(s.filters, key)
+	return nil
+}
+
+func (e memoryEntry) saved(owner, name string) (*SavedFilter, error) {
+	filter, err := Decode(e.data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SavedFilter{
+		Owner:     owner,
+		Name:      name,
+		Filter:    filter,
+		CreatedAt: e.createdAt,
+		UpdatedAt: e.updatedAt,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package filterstore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/pseudomuto/where/filterstore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := filterstore.NewMemoryStore()
+
+	adults := &filterstore.SavedFilter{Owner: "alice", Name: "adults", Filter: parse("age >= 18")(t)}
+	require.NoError(t, store.Save(ctx, adults))
+	require.False(t, adults.CreatedAt.IsZero())
+	require.Equal(t, adults.CreatedAt, adults.UpdatedAt)
+
+	require.NoError(t, store.Save(ctx, &filterstore.SavedFilter{Owner: "alice", Name: "active", Filter: parse("status = 'active'")(t)}))
+	require.NoError(t, store.Save(ctx, &filterstore.SavedFilter{Owner: "bob", Name: "mine", Filter: parse("owner_id = 2")(t)}))
+
+	// Changes to a saved filter do not affect the store.
+	adults.Filter.Expression = nil
+
+	saved, err := store.Get(ctx, "alice", "adults")
+	require.NoError(t, err)
+	require.Equal(t, "age >= 18", saved.Filter.String())
+	require.Equal(t, adults.CreatedAt, saved.CreatedAt)
+
+	updated := &filterstore.SavedFilter{Owner: "alice", Name: "adults", Filter: parse("age >= 21")(t)}
+	require.NoError(t, store.Save(ctx, updated))
+	require.Equal(t, adults.CreatedAt, updated.CreatedAt)
+	require.False(t, updated.UpdatedAt.Before(updated.CreatedAt))
+
+	list, err := store.List(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, "active", list[0].Name)
+	require.Equal(t, "adults", list[1].Name)
+	require.Equal(t, "age >= 21", list[1].Filter.String())
+
+	require.NoError(t, store.Delete(ctx, "alice", "adults"))
+	require.ErrorIs(t, store.Delete(ctx, "alice", "adults"), filterstore.ErrNotFound)
+
+	_, err = store.Get(ctx, "alice", "adults")
+	require.ErrorIs(t, err, filterstore.ErrNotFound)
+
+	_, err = store.Get(ctx, "alice", "mine")
+	require.ErrorIs(t, err, filterstore.ErrNotFound)
+
+	list, err = store.List(ctx, "carol")
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
+
+func TestMemoryStoreSaveErrors(t *testing.T) {
+	ctx := context.Background()
+	store := filterstore.NewMemoryStore()
+
+	require.EqualError(t, store.Save(ctx, &filterstore.SavedFilter{Owner: "alice"}), "saved filter requires a name")
+	require.EqualError(t, store.Save(ctx, &filterstore.SavedFilter{Owner: "alice", Name: "empty"}), `saved filter "empty" has no filter`)
+	require.EqualError(t, store.Save(ctx, &filterstore.SavedFilter{Name: "ids", Filter: where.In("id", struct{}{})}), "cannot store value of type struct {}")
+}
@@ -0,0 +1,170 @@
+package filterstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/where"
+)
+
+// SQLStore is a Store that keeps filters in a database table with the columns owner, name, filter,
+// created_at, and updated_at, where filter holds the JSON document written by Encode. The table can
+// be created with CreateTable. Save runs in a transaction, so the database must support them; MySQL
+// connections need parseTime=true to scan the timestamps.
+type SQLStore struct {
+	db     *sql.DB
+	driver where.Driver
+	table  string
+}
+
+// NewSQLStore returns a SQLStore for the table, using the where driver with the given name (e.g.
+// "postgres" or "mysql") for placeholders and identifier quoting.
+func NewSQLStore(db *sql.DB, driverName, table string) (*SQLStore, error) {
+	driver, err := where.GetDriver(driverName)
+	if err != nil {
+		return nil, err
+	}
+	if table == "" {
+		return nil, errors.New("filter store requires a table name")
+	}
+
+	return &SQLStore{db: db, driver: driver, table: driver.QuoteIdentifier(table)}, nil
+}
+
+// CreateTable creates the store's table if it does not exist.
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	owner VARCHAR(255) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	filter TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (owner, name)
+)`, s.table)
+
+	if _, err := s.db.ExecContext(ctx, query); err != nil {
+		return errors.Wrap(err, "failed to create filter table")
+	}
+	return nil
+}
+
+// Save implements Store.
+func (s *SQLStore) Save(ctx context.Context, filter *SavedFilter) error {
+	if err := validate(filter); err != nil {
+		return err
+	}
+
+	data, err := Encode(filter.Filter)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to save filter")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	createdAt := now
+
+	query := fmt.Sprintf("SELECT created_at FROM %s WHERE owner = %s AND name = %s",
+		s.table, s.driver.Placeholder(1), s.driver.Placeholder(2))
+	err = tx.QueryRowContext(ctx, query, filter.Owner, filter.Name).Scan(&createdAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		query = fmt.Sprintf("INSERT INTO %s (filter, created_at, updated_at, owner, name) VALUES (%s, %s, %s, %s, %s)",
+			s.table, s.driver.Placeholder(1), s.driver.Placeholder(2), s.driver.Placeholder(3), s.driver.Placeholder(4), s.driver.Placeholder(5))
+	case err != nil:
+		return errors.Wrap(err, "failed to save filter")
+	default:
+		query = fmt.Sprintf("UPDATE %s SET filter = %s, created_at = %s, updated_at = %s WHERE owner = %s AND name = %s",
+			s.table, s.driver.Placeholder(1), s.driver.Placeholder(2), s.driver.Placeholder(3), s.driver.Placeholder(4), s.driver.Placeholder(5))
+	}
+
+	createdAt = createdAt.UTC()
+	if _, err := tx.ExecContext(ctx, query, string(data), createdAt, now, filter.Owner, filter.Name); err != nil {
+		return errors.Wrap(err, "failed to save filter")
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to save filter")
+	}
+
+	filter.CreatedAt, filter.UpdatedAt = createdAt, now
+	return nil
+}
+
+// Get implements Store.
+func (s *SQLStore) Get(ctx context.Context, owner, name string) (*SavedFilter, error) {
+	query := fmt.Sprintf("SELECT filter, created_at, updated_at FROM %s WHERE owner = %s AND name = %s",
+		s.table, s.driver.Placeholder(1), s.driver.Placeholder(2))
+
+	saved := &SavedFilter{Owner: owner, Name: name}
+	var data string
+	err := s.db.QueryRowContext(ctx, query, owner, name).Scan(&data, &saved.CreatedAt, &saved.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get filter")
+	}
+
+	if saved.Filter, err = Decode([]byte(data)); err != nil {
+		return nil, err
+	}
+	saved.CreatedAt, saved.UpdatedAt = saved.CreatedAt.UTC(), saved.UpdatedAt.UTC()
+	return saved, nil
+}
+
+// List implements Store.
+func (s *SQLStore) List(ctx context.Context, owner string) ([]*SavedFilter, error) {
+	query := fmt.Sprintf("SELECT name, filter, created_at, updated_at FROM %s WHERE owner = %s ORDER BY name",
+		s.table, s.driver.Placeholder(1))
+
+	rows, err := s.db.QueryContext(ctx, query, owner)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list filters")
+	}
+	defer func() { _ = rows.Close() }()
+
+	var filters []*SavedFilter
+	for rows.Next() {
+		saved := &SavedFilter{Owner: owner}
+		var data string
+		if err := rows.Scan(&saved.Name, &data, &saved.CreatedAt, &saved.UpdatedAt); err != nil {
+			return nil, errors.Wrap(err, "failed to list filters")
+		}
+		if saved.Filter, err = Decode([]byte(data)); err != nil {
+			return nil, err
+		}
+		saved.CreatedAt, saved.UpdatedAt = saved.CreatedAt.UTC(), saved.UpdatedAt.UTC()
+		filters = append(filters, saved)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to list filters")
+	}
+	return filters, nil
+}
+
+// Delete implements Store.
+func (s *SQLStore) Delete(ctx context.Context, owner, name string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE owner = %s AND name = %s",
+		s.table, s.driver.Placeholder(1), s.driver.Placeholder(2))
+
+	res, err := s.db.ExecContext(ctx, query, owner, name)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete filter")
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to delete filter")
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package where
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// formatOptions holds configuration options for the formatter.
+	formatOptions struct {
+		indent            string
+		compact           bool
+		lowercaseKeywords bool
+	}
+
+	// FormatOption is a function type for configuring formatting options.
+	FormatOption func(*formatOptions)
+
+	// formatter prints a parsed filter back into filter expression syntax.
+	formatter struct {
+		opts *formatOptions
+	}
+)
+
+// WithIndent returns a FormatOption that sets the string used for each level of indentation.
+// The default is two spaces.
+func WithIndent(indent string) FormatOption {
+	return func(o *formatOptions) {
+		o.indent = indent
+	}
+}
+
+// WithCompact returns a FormatOption that prints the entire filter on a single line.
+func WithCompact() FormatOption {
+	return func(o *formatOptions) {
+		o.compact = true
+	}
+}
+
+// WithLowercaseKeywords returns a FormatOption that prints keywords (AND, OR, IN, etc.) in lowercase.
+func WithLowercaseKeywords() FormatOption {
+	return func(o *formatOptions) {
+		o.lowercaseKeywords = true
+	}
+}
+
+// Format parses the filter expression and reprints it with consistent keyword casing, spacing,
+// and indentation. Expressions without nested groups are kept on a single line, while nested
+// groups are broken across lines with one operand per line.
+func Format(input string, opts ...FormatOption) (string, error) {
+	filter, err := Parse(input)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to format filter")
+	}
+	return filter.Format(opts...), nil
+}
+
+// Format prints the filter in canonical filter expression syntax. See the package level Format function.
+func (f *Filter) Format(opts ...FormatOption) string {
+	options := &formatOptions{indent: "  "}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if f == nil || f.Expression == nil {
+		return ""
+	}
+
+	fm := &formatter{opts: options}
+	return fm.expression(f.Expression, "")
+}
+
+// String returns the filter formatted on a single line.
+func (f *Filter) String() string {
+	return f.Format(WithCompact())
+}
+
+func (fm *formatter) keyword(kw string) string {
+	if fm.opts.lowercaseKeywords {
+		return strings.ToLower(kw)
+	}
+	return kw
+}
+
+func (fm *formatter) expression(expr *Expression, indent string) string {
+	if expr == nil {
+		return ""
+	}
+
+	if fm.opts.compact || isFlat(expr) {
+		parts := make([]string, len(expr.Or))
+		for i, term := range expr.Or {
+			parts[i] = fm.term(term, indent, false)
+		}
+		return strings.Join(parts, " "+fm.keyword("OR")+" ")
+	}
+
+	var sb strings.Builder
+	nested := len(expr.Or) > 1
+	for i, term := range expr.Or {
+		if i > 0 {
+			sb.WriteString("\n" + indent + fm.keyword("OR") + " ")
+		}
+		sb.WriteString(fm.term(term, indent, nested))
+	}
+	return sb.String()
+}
+
+func (fm *formatter) term(term *Term, indent string, nested bool) string {
+	if term == nil {
+		return ""
+	}
+
+	if fm.opts.compact || isTermFlat(term) {
+		parts := make([]string, len(term.And))
+		for i, factor := range term.And {
+			parts[i] = fm.factor(factor, indent)
+		}
+		return strings.Join(parts, " "+fm.keyword("AND")+" ")
+	}
+
+	// AND-ed operands within an OR branch are indented to make precedence obvious.
+	lineIndent := indent
+	if nested {
+		lineIndent += fm.opts.indent
+	}
+
+	var sb strings.Builder
+	for i, factor := range term.And {
+		if i > 0 {
+			sb.WriteString("\n" + lineIndent + fm.keyword("AND") + " ")
+		}
+		sb.WriteString(fm.factor(factor, lineIndent))
+	}
+	return sb.String()
+}
+
+func (fm *formatter) factor(factor *Factor, indent string) string {
+	if factor == nil {
+		return ""
+	}
+
+	var prefix string
+	if factor.Not {
+		prefix = fm.keyword("NOT") + " "
+	}
+
+	if factor.Exists != nil {
+		return prefix + fm.keyword("EXISTS") + " (" + factor.Exists.Query + ")"
+	}
+
+	if factor.Macro != nil {
+		return prefix + *factor.Macro
+	}
+
+	if factor.SubExpr == nil {
+		return prefix + fm.predicate(factor.Predicate)
+	}
+
+	if fm.opts.compact || isFlat(factor.SubExpr) {
+		return prefix + "(" + fm.expression(factor.SubExpr, indent) + ")"
+	}
+
+	inner := indent + fm.opts.indent
+	return prefix + "(\n" + inner + fm.expression(factor.SubExpr, inner) + "\n" + indent + ")"
+}
+
+func (fm *formatter) predicate(pred *Predicate) string {
+	if pred == nil || pred.Operation == nil {
+		return ""
+	}
+
+	left := fm.value(pred.Left)
+	if isBooleanPredicate(pred) {
+		return left
+	}
+
+	op := pred.Operation
+	operator := fm.keyword(op.sqlOperator())
+
+	switch {
+	case op.Compare != nil:
+		if isNotEqual(operator) {
+			operator = "!="
+		}
+		return left + " " + operator + " " + fm.value(op.Compare.Right)
+	case op.Between != nil:
+		return left + " " + operator + " " + fm.value(op.Between.Lower) + " " +
+			fm.keyword("AND") + " " + fm.value(op.Between.Upper)
+	case op.In != nil:
+		return left + " " + operator + " (" + fm.values(op.In.Values) + ")"
+	case op.Like != nil:
+		return left + " " + operator + " " + fm.value(op.Like.Pattern)
+	case op.Match != nil:
+		return left + " " + operator + " " + fm.value(op.Match.Query)
+	default:
+		return left + " " + operator
+	}
+}
+
+func (fm *formatter) values(vals []*Value) string {
+	parts := make([]string, len(vals))
+	for i, val := range vals {
+		parts[i] = fm.value(val)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (fm *formatter) value(val *Value) string {
+	switch {
+	case val == nil:
+		return ""
+	case val.Function != nil && val.Function.isCast():
+		return val.Function.Name + "(" + fm.values(val.Function.Args) + " " + fm.keyword("AS") + " " +
+			val.Function.CastAs.String() + ")"
+	case val.Function != nil && val.Function.Star:
+		return val.Function.Name + "(*)"
+	case val.Function != nil && val.Function.Cond != nil:
+		return val.Function.Name + "(" + fm.expression(val.Function.Cond, "") + ", " + fm.values(val.Function.Args) + ")"
+	case val.Function != nil:
+		return val.Function.Name + "(" + fm.values(val.Function.Args) + ")"
+	case val.Field != nil:
+		return val.Field.String()
+	case val.Literal != nil:
+		return fm.literal(val.Literal)
+	case val.SubExpr != nil:
+		return "(" + fm.expression(val.SubExpr, "") + ")"
+	case val.Bitwise != nil:
+		s := fm.bitwiseOperand(val.Bitwise.Left)
+		for _, op := range val.Bitwise.Ops {
+			s += " " + op.Operator + " " + fm.bitwiseOperand(op.Right)
+		}
+		return s
+	default:
+		return ""
+	}
+}
+
+// bitwiseOperand formats an operand of a bitwise expression, parenthesizing nested expressions.
+func (fm *formatter) bitwiseOperand(val *Value) string {
+	if val != nil && val.Bitwise != nil {
+		return "(" + fm.value(val) + ")"
+	}
+	return fm.value(val)
+}
+
+func (fm *formatter) literal(lit *LiteralValue) string {
+	switch {
+	case lit.bound:
+		return fm.boundLiteral(lit.param)
+	case lit.String != nil:
+		return *lit.String
+	case lit.Hex != nil:
+		return *lit.Hex
+	case lit.Number != nil:
+		// Integers are printed in full rather than in scientific notation or rounded.
+		if numeral, ok := lit.numeral(); ok && isInteger(numeral) {
+			return numeral
+		}
+		return strconv.FormatFloat(*lit.Number, 'g', -1, 64)
+	case lit.Boolean != nil:
+		if lit.Boolean.Value() {
+			return fm.keyword("TRUE")
+		}
+		return fm.keyword("FALSE")
+	case lit.Variable != nil:
+		return *lit.Variable
+	default:
+		return fm.keyword("NULL")
+	}
+}
+
+// isFlat returns true if the expression contains no parenthesized groups with AND/OR connectives.
+func isFlat(expr *Expression) bool {
+	for _, term := range expr.Or {
+		if !isTermFlat(term) {
+			return false
+		}
+	}
+	return true
+}
+
+func isTermFlat(term *Term) bool {
+	for _, factor := range term.And {
+		if factor == nil || factor.SubExpr == nil {
+			continue
+		}
+		sub := factor.SubExpr
+		if len(sub.Or) > 1 || (len(sub.Or) == 1 && len(sub.Or[0].And) > 1) || !isFlat(sub) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []where.FormatOption
+		want  string
+	}{
+		{
+			name:  "normalizes spacing and casing",
+			input: "age>=18   and status='active'",
+			want:  "age >= 18 AND status = 'active'",
+		},
+		{
+			name:  "normalizes operators",
+			input: "a <> 1 and b not like 'x%' and c is not null and d not in (1,2) and e not between 1 and 2",
+			want:  "a != 1 AND b NOT LIKE 'x%' AND c IS NOT NULL AND d NOT IN (1, 2) AND e NOT BETWEEN 1 AND 2",
+		},
+		{
+			name:  "flat groups stay inline",
+			input: "(a = 1 or b = 2)",
+			want:  "(a = 1 OR b = 2)",
+		},
+		{
+			name:  "literals and functions",
+			input: `lower(name) = "John" and active = true and parent is null and n = 1.50 and x = null`,
+			want:  `lower(name) = "John" AND active = TRUE AND parent IS NULL AND n = 1.5 AND x = NULL`,
+		},
+		{
+			name:  "nested groups break lines",
+			input: "age >= 18 and (status = 'active' or status = 'pending')",
+			want:  "age >= 18\nAND (status = 'active' OR status = 'pending')",
+		},
+		{
+			name:  "deeply nested groups are indented",
+			input: "a = 1 and not (b = 1 or (c = 1 and d = 1))",
+			want:  "a = 1\nAND NOT (\n  b = 1\n  OR (c = 1 AND d = 1)\n)",
+		},
+		{
+			name:  "AND within OR branches is indented",
+			input: "a = 1 and (b = 1 or c = 1) or d = 1",
+			want:  "a = 1\n  AND (b = 1 OR c = 1)\nOR d = 1",
+		},
+		{
+			name:  "compact",
+			input: "a = 1 and not (b = 1 or (c = 1 and d = 1))",
+			opts:  []where.FormatOption{where.WithCompact()},
+			want:  "a = 1 AND NOT (b = 1 OR (c = 1 AND d = 1))",
+		},
+		{
+			name:  "lowercase keywords and custom indent",
+			input: "A = 1 AND NOT (B = 1 OR (C = 1 AND D IS NULL))",
+			opts:  []where.FormatOption{where.WithLowercaseKeywords(), where.WithIndent("\t")},
+			want:  "A = 1\nand not (\n\tB = 1\n\tor (C = 1 and D is null)\n)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := where.Format(tt.input, tt.opts...)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+
+			// Formatting is idempotent
+			again, err := where.Format(got, tt.opts...)
+			require.NoError(t, err)
+			require.Equal(t, got, again)
+		})
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := where.Format("age >")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to format filter")
+	})
+}
+
+func TestFilterString(t *testing.T) {
+	filter, err := where.Parse("a = 1 and (b = 2 or c = 3)")
+	require.NoError(t, err)
+	require.Equal(t, "a = 1 AND (b = 2 OR c = 3)", filter.String())
+}
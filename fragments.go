@@ -0,0 +1,33 @@
+package where
+
+import "strings"
+
+// WithFragments returns a BuildOption that registers named SQL fragments. Fields in the filter that
+// match a fragment name are replaced with the parenthesized fragment, which lets APIs expose computed
+// metrics (e.g. "revenue" for SUM(price * qty)) that are not real columns. Fragment names are
+// case-insensitive and are checked by the validator like any other field; the fragment SQL itself is
+// trusted and inserted verbatim.
+func WithFragments(fragments map[string]string) BuildOption {
+	return func(b *SQLBuilder) {
+		if b.fragments == nil {
+			b.fragments = make(map[string]string, len(fragments))
+		}
+		for name, sql := range fragments {
+			b.fragments[strings.ToLower(name)] = sql
+		}
+	}
+}
+
+// fragment returns the expanded SQL for a field that refers to a registered fragment.
+func (b *SQLBuilder) fragment(field *FieldRef) (string, bool) {
+	if len(b.fragments) == 0 {
+		return "", false
+	}
+
+	sql, ok := b.fragments[strings.ToLower(field.String())]
+	if !ok {
+		return "", false
+	}
+	b.trust(sql)
+	return "(" + sql + ")", true
+}
@@ -0,0 +1,85 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFragments(t *testing.T) {
+	fragments := where.WithFragments(map[string]string{
+		"revenue":   "SUM(price * qty)",
+		"Full_Name": "CONCAT(first_name, ' ', last_name)",
+	})
+
+	tests := []struct {
+		name     string
+		filter   string
+		driver   string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "fragment compared with literal",
+			filter:   "revenue > 1000",
+			driver:   "postgres",
+			wantSQL:  "(SUM(price * qty)) > $1",
+			wantArgs: []any{float64(1000)},
+		},
+		{
+			name:     "case-insensitive names",
+			filter:   "full_name LIKE 'J%' AND REVENUE BETWEEN 10 AND 20",
+			driver:   "mysql",
+			wantSQL:  "((CONCAT(first_name, ' ', last_name)) LIKE ? AND (SUM(price * qty)) BETWEEN ? AND ?)",
+			wantArgs: []any{"J%", float64(10), float64(20)},
+		},
+		{
+			name:     "fragment as function argument",
+			filter:   "ROUND(revenue) = 5",
+			driver:   "postgres",
+			wantSQL:  "ROUND((SUM(price * qty))) = $1",
+			wantArgs: []any{float64(5)},
+		},
+		{
+			name:     "other fields are quoted as usual",
+			filter:   "order > 1",
+			driver:   "postgres",
+			wantSQL:  `"order" > $1`,
+			wantArgs: []any{float64(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.filter)
+			require.NoError(t, err)
+
+			sql, args, err := filter.ToSQL(tt.driver, fragments)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestFragmentsValidation(t *testing.T) {
+	validator := where.NewValidator().AllowFields("revenue")
+	fragments := where.WithFragments(map[string]string{
+		"revenue": "SUM(price * qty)",
+		"margin":  "SUM(price - cost)",
+	})
+
+	filter, err := where.Parse("revenue > 10")
+	require.NoError(t, err)
+
+	sql, _, err := filter.ToSQL("postgres", fragments, where.WithValidator(validator))
+	require.NoError(t, err)
+	require.Equal(t, "(SUM(price * qty)) > $1", sql)
+
+	filter, err = where.Parse("margin > 10")
+	require.NoError(t, err)
+
+	_, _, err = filter.ToSQL("postgres", fragments, where.WithValidator(validator))
+	require.EqualError(t, err, `field "margin" is not allowed`)
+}
@@ -0,0 +1,44 @@
+package where
+
+import "github.com/pkg/errors"
+
+// WithFrozenFilters returns a ParserOption that freezes every filter the parser returns, e.g. for
+// filters kept in a cache and shared between requests. See Filter.Freeze.
+func WithFrozenFilters() ParserOption {
+	return func(o *parserOptions) {
+		o.freeze = true
+	}
+}
+
+// Freeze marks the filter as immutable and returns it. The AST fields remain exported, so Freeze
+// cannot prevent changes, but building SQL or compiling a frozen filter whose String has changed
+// since it was frozen returns an error rather than a query nobody wrote. Rewrites must start from a
+// copy returned by DeepCopy, which is not frozen. Freeze must be called before the filter is
+// shared, and checking a frozen filter formats it, which adds to the cost of each build.
+//
+// Example:
+//
+//	filter, _ := where.Parse("tenant_id = 7")
+//	filter.Freeze()
+//	filter.Expression.Or[0].And[0].Not = true
+//	_, _, err := filter.ToSQL("postgres") // frozen filter was modified
+func (f *Filter) Freeze() *Filter {
+	if f != nil && f.frozen == nil {
+		s := f.String()
+		f.frozen = &s
+	}
+	return f
+}
+
+// Frozen returns true if the filter was frozen with Freeze or by a parser using WithFrozenFilters.
+func (f *Filter) Frozen() bool {
+	return f != nil && f.frozen != nil
+}
+
+// checkFrozen returns an error if the filter is frozen and was modified since.
+func (f *Filter) checkFrozen() error {
+	if f.frozen != nil && f.String() != *f.frozen {
+		return errors.New("frozen filter was modified; modify a copy returned by DeepCopy instead")
+	}
+	return nil
+}
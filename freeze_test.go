@@ -0,0 +1,49 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	_ "github.com/pseudomuto/where/drivers/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeze(t *testing.T) {
+	filter := mustParse(t, "tenant_id = 7 AND status IN ('open', 'closed')")
+	require.False(t, filter.Frozen())
+	require.Same(t, filter, filter.Freeze())
+	require.True(t, filter.Frozen())
+
+	sql, params, err := filter.ToSQL("postgres")
+	require.NoError(t, err)
+	require.Equal(t, "(tenant_id = $1 AND status IN ($2, $3))", sql)
+	require.Equal(t, []any{7.0, "open", "closed"}, params)
+
+	copied := filter.DeepCopy()
+	require.False(t, copied.Frozen())
+	copied.Expression.Or[0].And[0].Not = true
+	_, _, err = copied.ToSQL("postgres")
+	require.NoError(t, err)
+
+	filter.Expression.Or[0].And[1].Predicate.Operation.In.Values = nil
+	_, _, err = filter.ToSQL("postgres")
+	require.EqualError(t, err, "frozen filter was modified; modify a copy returned by DeepCopy instead")
+
+	_, err = filter.Compile()
+	require.EqualError(t, err, "frozen filter was modified; modify a copy returned by DeepCopy instead")
+}
+
+func TestWithFrozenFilters(t *testing.T) {
+	parser, err := where.NewParser(where.WithFrozenFilters())
+	require.NoError(t, err)
+
+	for _, input := range []string{"age > 18", "id IN (1, 2, 3)"} {
+		filter, err := parser.Parse(input)
+		require.NoError(t, err)
+		require.True(t, filter.Frozen(), input)
+	}
+
+	var filter *where.Filter
+	require.Nil(t, filter.Freeze())
+	require.False(t, filter.Frozen())
+}
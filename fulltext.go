@@ -0,0 +1,22 @@
+package where
+
+// buildMatch renders a MATCHES operation with the driver's full-text search, e.g.
+// description MATCHES 'quick brown fox' becomes to_tsvector(description) @@ plainto_tsquery($1) on
+// PostgreSQL. Drivers without the FULLTEXT feature reject it.
+func (b *SQLBuilder) buildMatch(leftVal string, match *MatchOp) (string, error) {
+	searcher, ok := DriverAs[TextSearcher](b.driver)
+	if !ok || !b.driver.SupportsFeature("FULLTEXT") {
+		return "", newMessage(MsgOperatorNotSupported, "operator", "MATCHES", "driver", b.driver.Name())
+	}
+
+	query, err := b.buildValue(match.Query)
+	if err != nil {
+		return "", err
+	}
+
+	sql := searcher.MatchText(leftVal, query)
+	if match.Not {
+		return "NOT " + sql, nil
+	}
+	return sql, nil
+}
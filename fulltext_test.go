@@ -0,0 +1,93 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       map[string]string
+		wantParams []any
+	}{
+		{
+			name:  "search",
+			input: "description MATCHES 'quick brown fox'",
+			want: map[string]string{
+				"postgres":   "to_tsvector(description) @@ plainto_tsquery($1)",
+				"mysql":      "MATCH (description) AGAINST (? IN NATURAL LANGUAGE MODE)",
+				"clickhouse": "hasAll(tokens(lowerUTF8(description)), tokens(lowerUTF8(?)))",
+			},
+			wantParams: []any{"quick brown fox"},
+		},
+		{
+			name:  "combined with structured filters",
+			input: "status = 'published' AND body NOT matches 'spam' AND price < 10",
+			want: map[string]string{
+				"postgres": "(status = $1 AND NOT to_tsvector(body) @@ plainto_tsquery($2) AND price < $3)",
+				"mysql":    "(status = ? AND NOT MATCH (body) AGAINST (? IN NATURAL LANGUAGE MODE) AND price < ?)",
+			},
+			wantParams: []any{"published", "spam", float64(10)},
+		},
+		{
+			name:  "field named matches",
+			input: "matches > 3 AND t.matches MATCHES 'final'",
+			want: map[string]string{
+				"postgres": "(matches > $1 AND to_tsvector(t.matches) @@ plainto_tsquery($2))",
+			},
+			wantParams: []any{float64(3), "final"},
+		},
+	}
+
+	for _, tt := range tests {
+		filter, err := where.Parse(tt.input)
+		require.NoError(t, err)
+
+		for driver, want := range tt.want {
+			t.Run(tt.name+"/"+driver, func(t *testing.T) {
+				sql, params, err := filter.ToSQL(driver)
+				require.NoError(t, err)
+				require.Equal(t, want, sql)
+				require.Equal(t, tt.wantParams, params)
+			})
+		}
+	}
+}
+
+func TestMatchesVariables(t *testing.T) {
+	filter, err := where.Parse("body MATCHES :q")
+	require.NoError(t, err)
+	require.Equal(t, []string{"q"}, filter.Variables())
+
+	sql, params, err := filter.ToSQL("postgres", where.WithVariables(map[string]any{"q": "brown fox"}))
+	require.NoError(t, err)
+	require.Equal(t, "to_tsvector(body) @@ plainto_tsquery($1)", sql)
+	require.Equal(t, []any{"brown fox"}, params)
+}
+
+func TestMatchesNotSupported(t *testing.T) {
+	where.RegisterDriver("no-fulltext", &MockDriver{name: "no-fulltext"})
+
+	filter, err := where.Parse("body MATCHES 'fox'")
+	require.NoError(t, err)
+
+	_, _, err = filter.ToSQL("no-fulltext")
+	require.EqualError(t, err, "operator MATCHES not supported by driver no-fulltext")
+}
+
+func TestMatchesAnalysis(t *testing.T) {
+	filter, err := where.Parse("body matches 'fox' AND NOT (title MATCHES 'draft')")
+	require.NoError(t, err)
+
+	require.Equal(t, "body MATCHES 'fox' AND NOT (title MATCHES 'draft')", filter.String())
+	require.Equal(t, filter.String(), filter.Clone().String())
+	require.Equal(t, "body MATCHES 'fox' AND title NOT MATCHES 'draft'", filter.ToNNF().String())
+
+	reparsed, err := where.Parse(filter.String())
+	require.NoError(t, err)
+	require.True(t, filter.Equal(reparsed))
+}
@@ -1,6 +1,7 @@
 package where
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -13,6 +14,7 @@ const (
 	FunctionTypeString     FunctionType = "string"
 	FunctionTypeMath       FunctionType = "math"
 	FunctionTypeConversion FunctionType = "conversion"
+	FunctionTypeGeo        FunctionType = "geo"
 )
 
 type (
@@ -30,7 +32,8 @@ type (
 )
 
 // StandardFunctions contains metadata for commonly supported SQL functions.
-// This is optional documentation - all functions are supported by default regardless of this list.
+// All functions are supported by default regardless of this list; the metadata is only enforced
+// by parsers created with WithFunctionArgValidation.
 var StandardFunctions = map[string]FunctionDef{
 	"LOWER": {
 		Name:        "LOWER",
@@ -256,6 +259,41 @@ var StandardFunctions = map[string]FunctionDef{
 		MaxArgs:     2,
 		Description: "Raises to power",
 	},
+	"COUNT": {
+		Name:        "COUNT",
+		Type:        FunctionTypeAggregate,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Counts non-null values",
+	},
+	"SUM": {
+		Name:        "SUM",
+		Type:        FunctionTypeAggregate,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Sums values",
+	},
+	"AVG": {
+		Name:        "AVG",
+		Type:        FunctionTypeAggregate,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Averages values",
+	},
+	"MIN": {
+		Name:        "MIN",
+		Type:        FunctionTypeAggregate,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Returns the smallest value",
+	},
+	"MAX": {
+		Name:        "MAX",
+		Type:        FunctionTypeAggregate,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Returns the largest value",
+	},
 }
 
 // GetFunctionDef retrieves the function definition for the given function name.
@@ -269,15 +307,58 @@ func GetFunctionDef(name string) (FunctionDef, bool) {
 // ValidateFunctionArgs validates that the given argument count is valid for the function.
 // Returns true if the argument count is within the allowed range for functions in StandardFunctions.
 // Note: This is optional validation - all functions are supported by default regardless of arity.
+// Use WithFunctionArgValidation to enforce it at parse time.
 func ValidateFunctionArgs(name string, argCount int) bool {
 	def, ok := GetFunctionDef(name)
+	return ok && def.acceptsArgs(argCount)
+}
+
+// checkFunctionArgs validates the call against the function's metadata in StandardFunctions or
+// PortableFunctions. Unknown functions are not checked.
+func checkFunctionArgs(fn *FunctionCall) error {
+	def, ok := GetFunctionDef(fn.Name)
 	if !ok {
-		return false
+		def, ok = PortableFunctions[strings.ToUpper(fn.Name)]
+	}
+	if !ok || fn.isCast() {
+		return nil
+	}
+
+	args := fn.arguments()
+	if !def.acceptsArgs(fn.argCount()) {
+		return newMessage(MsgFunctionArgs, "function", fn.Name, "expected", def.arity(), "count", fn.argCount())
 	}
 
-	if def.MaxArgs == -1 {
-		return argCount >= def.MinArgs
+	if def.Type != FunctionTypeMath {
+		return nil
 	}
 
-	return argCount >= def.MinArgs && argCount <= def.MaxArgs
+	for i, arg := range args {
+		if arg == nil || arg.Literal == nil || arg.Literal.Null || arg.Literal.Variable != nil {
+			continue
+		}
+		if typ := arg.Literal.Type(); typ != "number" {
+			return newMessage(MsgFunctionArgType, "function", fn.Name, "position", i+1, "type", typ)
+		}
+	}
+	return nil
+}
+
+// arity describes the number of arguments the function accepts.
+func (d FunctionDef) arity() string {
+	plural := func(n int) string {
+		if n == 1 {
+			return "1 argument"
+		}
+		return fmt.Sprintf("%d arguments", n)
+	}
+
+	switch {
+	case d.MaxArgs == -1:
+		return "at least " + plural(d.MinArgs)
+	case d.MinArgs == d.MaxArgs:
+		return plural(d.MinArgs)
+	default:
+		return fmt.Sprintf("%d to %d arguments", d.MinArgs, d.MaxArgs)
+	}
 }
@@ -300,3 +300,61 @@ func TestFunctionTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestWithFunctionArgValidation(t *testing.T) {
+	parser, err := where.NewParser(where.WithFunctionArgValidation())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "valid", input: "LOWER(name) = 'x' AND ROUND(price, 2) > 10 AND created_at < NOW()"},
+		{name: "variadic", input: "COALESCE(a, b, c, 'd') = 'd'"},
+		{name: "unknown functions are not checked", input: "my_udf(a, b, c) = 1"},
+		{name: "math with field and NULL", input: "ABS(balance) > 0 AND ROUND(price, NULL) > 0"},
+		{name: "portable functions", input: "DATE_TRUNC('day', created_at) = '2024-01-01'"},
+		{
+			name:    "too many arguments",
+			input:   "LOWER(a, b) = 'x'",
+			wantErr: "filter validation failed: function LOWER expects 1 argument, got 2",
+		},
+		{
+			name:    "no arguments expected",
+			input:   "created_at < now(1)",
+			wantErr: "filter validation failed: function now expects 0 arguments, got 1",
+		},
+		{
+			name:    "range",
+			input:   "SUBSTRING(name) = 'x'",
+			wantErr: "filter validation failed: function SUBSTRING expects 2 to 3 arguments, got 1",
+		},
+		{
+			name:    "at least",
+			input:   "CONCAT(a) = 'x'",
+			wantErr: "filter validation failed: function CONCAT expects at least 2 arguments, got 1",
+		},
+		{
+			name:    "nested",
+			input:   "LOWER(TRIM(a, b)) = 'x'",
+			wantErr: "filter validation failed: function TRIM expects 1 argument, got 2",
+		},
+		{
+			name:    "math argument type",
+			input:   "ROUND(price, 'two') > 10",
+			wantErr: "filter validation failed: function ROUND expects a number for argument 2, got string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.Parse(tt.input)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package where_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func FuzzParse(f *testing.F) {
+	seeds := []string{
+		"age > 18",
+		"age >= 18 AND status = 'active'",
+		"name ILIKE '%john%' OR email NOT LIKE '%spam%'",
+		"id NOT IN (1, 2, 3) AND deleted_at IS NOT NULL",
+		"NOT (a BETWEEN 1 AND 2 OR b = true)",
+		"LOWER(TRIM(name)) = 'x' AND `order`.\"select\" = null",
+		`s = 'don\'t' AND t = "quoted"`,
+		"a = 1e10 AND b = -5.5",
+		"((((a = 1))))",
+		"a = 'unterminated",
+		strings.Repeat("(", 100) + "a = 1",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		filter, err := where.Parse(input)
+		if err != nil {
+			return
+		}
+
+		for _, driver := range []string{"postgres", "mysql", "clickhouse"} {
+			_, _, _ = filter.ToSQL(driver)
+		}
+		_ = filter.Explain().String()
+		_ = filter.Lint()
+
+		// Formatting must produce a filter that parses back to the same canonical form.
+		formatted := filter.Format()
+		reparsed, err := where.Parse(formatted)
+		require.NoError(t, err, "formatted filter %q failed to parse", formatted)
+		require.Equal(t, formatted, reparsed.Format())
+	})
+}
+
+func TestParseHardening(t *testing.T) {
+	t.Run("deeply nested groups", func(t *testing.T) {
+		input := strings.Repeat("(", 5000) + "a = 1" + strings.Repeat(")", 5000)
+		_, err := where.Parse(input)
+		require.EqualError(t, err, "filter validation failed: expression depth exceeds maximum of 10")
+	})
+
+	t.Run("deeply nested values", func(t *testing.T) {
+		input := "a = " + strings.Repeat("(", 50) + "1" + strings.Repeat(")", 50)
+		_, err := where.Parse(input)
+		require.EqualError(t, err, "filter validation failed: expression depth exceeds maximum of 10")
+	})
+
+	t.Run("nested values count towards depth", func(t *testing.T) {
+		parser, err := where.NewParser(where.WithMaxDepth(1))
+		require.NoError(t, err)
+
+		_, err = parser.Parse("a = (b = (c = (d = 1)))")
+		require.EqualError(t, err, "filter validation failed: expression depth exceeds maximum of 1")
+	})
+
+	t.Run("parentheses in strings are ignored", func(t *testing.T) {
+		input := "a = '" + strings.Repeat("(", 100) + "'"
+		_, err := where.Parse(input)
+		require.NoError(t, err)
+	})
+
+	t.Run("backslash ends strings in standard mode", func(t *testing.T) {
+		parser, err := where.NewParser(where.WithEscapeMode(where.EscapeStandard))
+		require.NoError(t, err)
+
+		input := `a = 'x\' AND b = '` + strings.Repeat("(", 100) + "'"
+		_, err = parser.Parse(input)
+		require.NoError(t, err)
+
+		input = `a = 'x\' OR ` + strings.Repeat("(", 50) + "b = 1" + strings.Repeat(")", 50) + ` OR c = '\'`
+		_, err = parser.Parse(input)
+		require.EqualError(t, err, "filter validation failed: expression depth exceeds maximum of 10")
+	})
+
+	t.Run("unbalanced and invalid groups fail quickly", func(t *testing.T) {
+		parser, err := where.NewParser(where.WithMaxDepth(30))
+		require.NoError(t, err)
+
+		inputs := []string{
+			strings.Repeat("(", 34) + "a = 1",
+			strings.Repeat("(", 34) + "a = " + strings.Repeat(")", 34),
+			strings.Repeat("(", 34) + "a = 1 +" + strings.Repeat(")", 34),
+			"NOT " + strings.Repeat("(NOT ", 34) + "a = 1",
+		}
+
+		// Each of these took longer than a minute when every group could be read two ways.
+		start := time.Now()
+		for _, input := range inputs {
+			_, err := parser.Parse(input)
+			require.Error(t, err, input)
+		}
+		require.Less(t, time.Since(start), 2*time.Second)
+	})
+
+	t.Run("input length", func(t *testing.T) {
+		parser, err := where.NewParser(where.WithMaxInputLength(10))
+		require.NoError(t, err)
+
+		_, err = parser.Parse("name = 'something long'")
+		require.EqualError(t, err, "filter validation failed: filter expression exceeds maximum length of 10 bytes")
+	})
+
+	t.Run("huge numbers", func(t *testing.T) {
+		_, err := where.Parse("a = 1e999")
+		require.Error(t, err)
+	})
+
+	t.Run("quote-only identifiers", func(t *testing.T) {
+		for _, input := range []string{"` \"` = 1", "`\"\"` = 1", "a.`  ` = 1", "LOWER(`\"`) = 'x'"} {
+			_, err := where.Parse(input)
+			require.ErrorContains(t, err, "is empty", input)
+		}
+	})
+
+	t.Run("unterminated strings", func(t *testing.T) {
+		_, err := where.Parse("a = 'abc")
+		require.Error(t, err)
+	})
+}
@@ -1,14 +1,24 @@
 package where
 
 import (
+	"strings"
+
 	"github.com/alecthomas/participle/v2/lexer"
 )
 
 type (
 	// Filter represents the root AST node for a parsed filter expression.
+	//
+	// Building SQL, formatting, linting, and the other Filter methods only read the AST, so a parsed
+	// Filter may be shared and used by multiple goroutines at once. Code that modifies the AST must do
+	// so on a copy returned by Clone while the filter is shared. Freeze makes building SQL from a
+	// filter that was modified anyway fail.
 	Filter struct {
 		Pos        lexer.Position
 		Expression *Expression `parser:"@@"`
+
+		// frozen holds the filter's String when it was frozen, see Freeze.
+		frozen *string
 	}
 
 	// Expression represents logical expressions with proper precedence (OR has lower precedence than AND).
@@ -22,16 +32,28 @@ type (
 	}
 
 	// Factor represents a single factor in a logical expression, which can be negated.
+	// Exists is never set by the parser; see the Exists function. Macro holds an @name reference
+	// while parsing, which the parser replaces with the macro's expression in SubExpr (see WithMacros).
+	//
+	// The grammar reads a parenthesized group as a predicate whose value is the group, since a group
+	// may also start a comparison such as (a + b) = c, and the parser then moves the group to SubExpr.
+	// Trying both readings at every level would make parsing take exponential time in the nesting.
 	Factor struct {
-		Not       bool        `parser:"@Not?"`
-		SubExpr   *Expression `parser:"( LParen @@ RParen )"`
-		Predicate *Predicate  `parser:"| @@"`
+		Not       bool `parser:"@Not?"`
+		SubExpr   *Expression
+		Macro     *string    `parser:"( @Macro"`
+		Predicate *Predicate `parser:"| @@ )"`
+		Exists    *ExistsOp
 	}
 
 	// Predicate represents the core predicate AST node containing a left value and an operation.
+	// A field or function call without an operation, such as is_active, is a boolean predicate that
+	// the parser completes as is_active = TRUE. Pos is the position of the predicate in the filter's
+	// text, which is zero if the predicate was not parsed.
 	Predicate struct {
+		Pos       lexer.Position
 		Left      *Value     `parser:"@@"`
-		Operation *Operation `parser:"@@"`
+		Operation *Operation `parser:"@@?"`
 	}
 
 	// Operation represents different types of operations with clean separation of each operation type.
@@ -39,11 +61,12 @@ type (
 		Between *BetweenOp `parser:"@@"`
 		In      *InOp      `parser:"| @@"`
 		Like    *LikeOp    `parser:"| @@"`
+		Match   *MatchOp   `parser:"| @@"`
 		Compare *CompareOp `parser:"| @@"`
 		IsNull  *IsNullOp  `parser:"| @@"`
 	}
 
-	// CompareOp represents comparison operations (=, !=, <, >, <=, >=).
+	// CompareOp represents comparison operations (=, !=, <, >, <=, >=, <=>).
 	CompareOp struct {
 		Operator CompareOperator `parser:"@@"`
 		Right    *Value          `parser:"@@"`
@@ -51,7 +74,7 @@ type (
 
 	// CompareOperator represents the type of comparison operator.
 	CompareOperator struct {
-		Type string `parser:"@( Equal | NotEqual | LessOrEqual | GreaterOrEqual | Less | Greater )"`
+		Type string `parser:"@( NullSafeEqual | Equal | NotEqual | LessOrEqual | GreaterOrEqual | Less | Greater )"`
 	}
 
 	// LikeOp represents LIKE and ILIKE operations with optional NOT.
@@ -66,6 +89,14 @@ type (
 		Operator string `parser:"@( Like | ILike )"`
 	}
 
+	// MatchOp represents full-text MATCHES operations with optional NOT. MATCHES is not a reserved
+	// word, so fields named matches are still allowed.
+	MatchOp struct {
+		Not     bool   `parser:"@Not?"`
+		Matches string `parser:"@'MATCHES'"`
+		Query   *Value `parser:"@@"`
+	}
+
 	// BetweenOp represents BETWEEN operations with optional NOT.
 	BetweenOp struct {
 		Not     bool   `parser:"@Not?"`
@@ -79,7 +110,7 @@ type (
 	InOp struct {
 		Not    bool     `parser:"@Not?"`
 		In     string   `parser:"@In"`
-		Values []*Value `parser:"LParen @@ ( Comma @@ )* RParen"`
+		Values []*Value `parser:"LParen ( @@ ( Comma @@ )* )? RParen"`
 	}
 
 	// IsNullOp represents IS NULL operations with optional NOT.
@@ -91,29 +122,83 @@ type (
 
 	// Value represents different types of values that can appear in expressions.
 	Value struct {
-		Function *FunctionCall `parser:"@@"`
+		Function *FunctionCall `parser:"( @@"`
 		Field    *FieldRef     `parser:"| @@"`
 		Literal  *LiteralValue `parser:"| @@"`
-		SubExpr  *Expression   `parser:"| LParen @@ RParen"`
+		SubExpr  *Expression   `parser:"| LParen @@ RParen )"`
+		Bitwise  *BitwiseExpr  `parser:"@@?"`
+	}
+
+	// BitwiseExpr represents bitwise operations evaluated left to right, such as flags & 4 or
+	// perm | 1. The parser reads the first operand into the enclosing Value and then moves it to
+	// Left, so a Value with a BitwiseExpr has no other fields set.
+	BitwiseExpr struct {
+		Left *Value
+		Ops  []*BitwiseOp `parser:"@@+"`
+	}
+
+	// BitwiseOp represents a bitwise operator (&, |, ^, <<, >>) and its right operand.
+	BitwiseOp struct {
+		Operator string `parser:"@Bitwise"`
+		Right    *Value `parser:"@@"`
 	}
 
 	// FunctionCall represents a function call with a name and arguments.
+	// CastAs is set for CAST(value AS type) expressions, Cond for IF(condition, a, b) expressions,
+	// where Args holds a and b, and Star for COUNT(*), which has no Args. A name followed by "(" is always a function, even if it is a keyword
+	// such as LIKE or IN; only AND, OR, and NOT cannot be function names. Pos is the position of the
+	// function's name in the filter's text.
+	//
+	// The grammar reads a condition only after IF, and the parser then sets Name from the text, so that
+	// other calls are read once rather than first as a condition and then as arguments.
 	FunctionCall struct {
-		Name string   `parser:"@Ident"`
-		Args []*Value `parser:"LParen ( @@ ( Comma @@ )* )? RParen"`
+		Pos    lexer.Position
+		Cond   *Expression `parser:"( 'IF' LParen @@ Comma"`
+		Name   string      `parser:"| (?! 'IF' LParen ) @( Ident | Between | In | Like | ILike | Is | Null | True | False ) (?= LParen) LParen )"`
+		Star   bool        `parser:"( @Star"`
+		Args   []*Value    `parser:"| @@ ( Comma @@ )* )?"`
+		CastAs *SQLType    `parser:"( 'AS' @@ )? RParen"`
+	}
+
+	// SQLType represents a type name in a CAST expression, e.g. INTEGER, VARCHAR(255), or DOUBLE PRECISION.
+	SQLType struct {
+		Name   []string `parser:"@Ident+"`
+		Params []string `parser:"( LParen @Number ( Comma @Number )* RParen )?"`
 	}
 
 	// FieldRef represents a field reference with support for qualified names (table.column).
+	// Pos is the position of the field in the filter's text.
 	FieldRef struct {
+		Pos   lexer.Position
 		Parts []string `parser:"@( QuotedIdent | BacktickIdent | Ident ) ( Dot @( QuotedIdent | BacktickIdent | Ident ) )*"`
+
+		// column is true for a column a field was expanded to with WithFieldExpansions, which is
+		// trusted rather than validated.
+		column bool
 	}
 
-	// LiteralValue represents literal values (strings, numbers, booleans, null).
+	// LiteralValue represents literal values (strings, binary, numbers, booleans, null) and :name
+	// variables, whose values are supplied with WithVariables.
 	LiteralValue struct {
-		String  *string     `parser:"@( String | DoubleQuotedString )"`
-		Number  *float64    `parser:"| @Number"`
-		Boolean *BooleanLit `parser:"| @@"`
-		Null    bool        `parser:"| @Null"`
+		String   *string     `parser:"@( String | DoubleQuotedString )"`
+		Hex      *string     `parser:"| @Hex"`
+		Numeral  *string     `parser:"| @( ( Minus | Plus )? Number )"`
+		Boolean  *BooleanLit `parser:"| @@"`
+		Null     bool        `parser:"| @Null"`
+		Variable *string     `parser:"| @Variable"`
+
+		// Number is the value of a number literal. The parser sets it from Numeral, which holds the
+		// number as written in plain decimal form so that large integers keep their precision.
+		Number *float64
+
+		escapes EscapeMode
+
+		// bound and param hold a Go value supplied through the In and NotIn constructors.
+		bound bool
+		param any
+
+		// implicit is true for the TRUE completing a boolean predicate such as is_active.
+		implicit bool
 	}
 
 	// BooleanLit represents boolean literal values (true/false).
@@ -128,14 +213,17 @@ func (b *BooleanLit) Value() bool {
 	return b.True // True if True token found, False if False token found
 }
 
-// Value returns the Go value represented by the LiteralValue, with strings having quotes stripped.
+// Value returns the Go value represented by the LiteralValue, with strings having quotes stripped
+// and escape sequences decoded.
 func (l *LiteralValue) Value() any {
+	if l.bound {
+		return l.param
+	}
 	if l.String != nil {
-		s := *l.String
-		if len(s) >= 2 {
-			s = s[1 : len(s)-1]
-		}
-		return s
+		return l.escapes.unquote(*l.String)
+	}
+	if l.Hex != nil {
+		return decodeHexLiteral(*l.Hex)
 	}
 	if l.Number != nil {
 		return *l.Number
@@ -151,6 +239,117 @@ func (l *LiteralValue) IsNull() bool {
 	return l.Null
 }
 
+// Type returns the name of the literal's type: "string", "binary", "number", "boolean", "variable",
+// or "null".
+func (l *LiteralValue) Type() string {
+	if l.bound {
+		return boundType(l.param)
+	}
+
+	switch {
+	case l.String != nil:
+		return "string"
+	case l.Hex != nil:
+		return "binary"
+	case l.Number != nil:
+		return "number"
+	case l.Boolean != nil:
+		return "boolean"
+	case l.Variable != nil:
+		return "variable"
+	default:
+		return "null"
+	}
+}
+
+// sqlOperator returns the SQL operator for the operation (e.g. "=", "NOT IN", "IS NOT NULL").
+func (op *Operation) sqlOperator() string {
+	switch {
+	case op.Compare != nil:
+		return op.Compare.Operator.String()
+	case op.Like != nil:
+		return negate(strings.ToUpper(op.Like.Type.Operator), op.Like.Not)
+	case op.Match != nil:
+		return negate("MATCHES", op.Match.Not)
+	case op.Between != nil:
+		return negate("BETWEEN", op.Between.Not)
+	case op.In != nil:
+		return negate("IN", op.In.Not)
+	case op.IsNull != nil:
+		if op.IsNull.Not {
+			return "IS NOT NULL"
+		}
+		return "IS NULL"
+	default:
+		return ""
+	}
+}
+
+// operands returns the right-hand side values of the operation in source order.
+func (op *Operation) operands() []*Value {
+	switch {
+	case op.Compare != nil:
+		return []*Value{op.Compare.Right}
+	case op.Like != nil:
+		return []*Value{op.Like.Pattern}
+	case op.Match != nil:
+		return []*Value{op.Match.Query}
+	case op.Between != nil:
+		return []*Value{op.Between.Lower, op.Between.Upper}
+	case op.In != nil:
+		return op.In.Values
+	default:
+		return nil
+	}
+}
+
+func negate(operator string, not bool) string {
+	if not {
+		return "NOT " + operator
+	}
+	return operator
+}
+
+// String returns the dotted field name as written in the filter expression.
+func (f *FieldRef) String() string {
+	return strings.Join(f.Parts, ".")
+}
+
+// String returns the type as written, e.g. "DECIMAL(10, 2)".
+func (t *SQLType) String() string {
+	return formatType(strings.Join(t.Name, " "), t.Params)
+}
+
+// isCast returns true if the function call is a CAST(value AS type) expression.
+func (fn *FunctionCall) isCast() bool {
+	return fn.CastAs != nil
+}
+
+// argCount returns the number of arguments of the function call, counting the * of COUNT(*) and the
+// condition of an IF expression.
+func (fn *FunctionCall) argCount() int {
+	if fn.Star {
+		return len(fn.arguments()) + 1
+	}
+	return len(fn.arguments())
+}
+
+// arguments returns the arguments of the function call, including the condition of an
+// IF(condition, a, b) expression as a grouped first argument.
+func (fn *FunctionCall) arguments() []*Value {
+	if fn.Cond == nil {
+		return fn.Args
+	}
+	return append([]*Value{{SubExpr: fn.Cond}}, fn.Args...)
+}
+
+func formatType(name string, params []string) string {
+	if len(params) == 0 {
+		return name
+	}
+	return name + "(" + strings.Join(params, ", ") + ")"
+}
+
 // String returns the SQL operator string representation of the CompareOperator.
 func (op *CompareOperator) String() string {
 	switch op.Type {
@@ -166,6 +365,8 @@ func (op *CompareOperator) String() string {
 		return "<="
 	case "GreaterOrEqual":
 		return ">="
+	case "NullSafeEqual":
+		return "<=>"
 	default:
 		return op.Type
 	}
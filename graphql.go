@@ -0,0 +1,278 @@
+package where
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// graphQLOperators maps the GraphQL filter operators to the operators of structured JSON filters.
+// Operator names are matched case-insensitively, ignoring underscores, so gt, _gt, notIn, and
+// _not_in are all accepted.
+var graphQLOperators = map[string]string{
+	"eq":         "=",
+	"ne":         "!=",
+	"neq":        "!=",
+	"gt":         ">",
+	"gte":        ">=",
+	"lt":         "<",
+	"lte":        "<=",
+	"in":         "in",
+	"nin":        "not in",
+	"notin":      "not in",
+	"like":       "like",
+	"nlike":      "not like",
+	"notlike":    "not like",
+	"ilike":      "ilike",
+	"nilike":     "not ilike",
+	"notilike":   "not ilike",
+	"between":    "between",
+	"notbetween": "not between",
+	"matches":    "matches",
+	"isnull":     "is null",
+}
+
+// ParseGraphQL converts a GraphQL filter input, as decoded by a GraphQL server into a map, into the
+// same AST that Parse returns for the equivalent expression, so GraphQL and string filters share
+// one validation and SQL generation pipeline. The filter is validated according to the parser's
+// configured options.
+//
+// Each key of the input is a field mapped to its operators, e.g. {"age": {"gt": 18}}, or a scalar
+// value for equality, e.g. {"status": "active"}, with null matching NULL. Nested objects are fields
+// of a relation, so {"user": {"name": {"eq": "x"}}} filters on user.name. The keys and, or, and not
+// combine filters, and several keys in one object are ANDed in key order. The operators are eq, ne (neq), gt, gte,
+// lt, lte, in, nin (notIn), like, nlike (notLike), ilike, nilike (notILike), between, notBetween,
+// matches, and isNull, which takes a boolean. Names are case-insensitive and may use Hasura's
+// underscore style, e.g. _and and _is_null.
+//
+// Example:
+//
+//	filter, err := parser.ParseGraphQL(map[string]any{
+//		"age":    map[string]any{"gt": 18},
+//		"status": map[string]any{"in": []any{"active"}},
+//	})
+//	// same as parser.Parse("age > 18 AND status IN ('active')")
+func (p *Parser) ParseGraphQL(input map[string]any) (*Filter, error) {
+	filter, err := p.parseGraphQL(input)
+	if err != nil && p.opts.onReject != nil {
+		raw, _ := json.Marshal(input)
+		meta, _ := rejectionMeta(err)
+		p.opts.onReject(string(raw), err, meta)
+	}
+	return filter, err
+}
+
+func (p *Parser) parseGraphQL(input map[string]any) (*Filter, error) {
+	if len(input) == 0 {
+		return nil, newMessage(MsgEmptyFilter)
+	}
+
+	node, err := graphQLNode(input, "", "")
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+
+	jb := &jsonBuilder{escapes: p.opts.escapes}
+	expr, err := jb.expression(node, "")
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+
+	filter := &Filter{Expression: expr}
+	if err := p.resolve(filter); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// ParseGraphQL is a convenience function that creates a default parser and converts the GraphQL
+// filter input. See Parser.ParseGraphQL.
+func ParseGraphQL(input map[string]any) (*Filter, error) {
+	parser, err := NewParser()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create parser")
+	}
+	return parser.ParseGraphQL(input)
+}
+
+// graphQLNode converts a GraphQL filter object to a structured filter node. Fields are prefixed
+// with prefix, and path locates the object in errors.
+func graphQLNode(input map[string]any, prefix, path string) (*jsonNode, error) {
+	keys := make([]string, 0, len(input))
+	for key := range input {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var nodes []*jsonNode
+	for _, key := range keys {
+		keyPath := graphQLPath(path, key)
+		value := input[key]
+
+		var (
+			node *jsonNode
+			err  error
+		)
+		switch graphQLName(key) {
+		case "and", "or":
+			node, err = graphQLGroup(graphQLName(key), value, prefix, keyPath)
+		case "not":
+			inner, ok := value.(map[string]any)
+			if !ok || len(inner) == 0 {
+				return nil, errors.Errorf("%s: not requires a filter object", keyPath)
+			}
+			node = &jsonNode{}
+			node.Not, err = graphQLNode(inner, prefix, keyPath)
+		default:
+			var conditions []*jsonNode
+			conditions, err = graphQLField(prefix+key, value, keyPath)
+			nodes = append(nodes, conditions...)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return &jsonNode{And: nodes}, nil
+}
+
+// graphQLGroup converts the filters of an and or or key, given as a list or a single object.
+func graphQLGroup(kind string, value any, prefix, path string) (*jsonNode, error) {
+	var items []any
+	switch v := value.(type) {
+	case []any:
+		items = v
+	case []map[string]any:
+		for _, item := range v {
+			items = append(items, item)
+		}
+	case map[string]any:
+		items = []any{v}
+	}
+	if len(items) == 0 {
+		return nil, errors.Errorf("%s: %s requires a list of filter objects", path, kind)
+	}
+
+	nodes := make([]*jsonNode, len(items))
+	for i, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok || len(obj) == 0 {
+			return nil, errors.Errorf("%s[%d]: %s requires a list of filter objects", path, i, kind)
+		}
+
+		node, err := graphQLNode(obj, prefix, graphQLIndexPath(path, i))
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = node
+	}
+
+	if kind == "and" {
+		return &jsonNode{And: nodes}, nil
+	}
+	return &jsonNode{Or: nodes}, nil
+}
+
+// graphQLField converts the operators of a field to conditions. A scalar is compared for equality,
+// null matches NULL, and an object of fields rather than operators filters a relation. A relation's
+// fields must be objects too, so that an unknown operator with a scalar value is an error.
+func graphQLField(field string, value any, path string) ([]*jsonNode, error) {
+	if value == nil {
+		return []*jsonNode{{Field: field, Op: "is null", path: path}}, nil
+	}
+
+	ops, ok := value.(map[string]any)
+	if !ok {
+		node, err := graphQLCondition(field, "=", value, path)
+		return []*jsonNode{node}, err
+	}
+	if len(ops) == 0 {
+		return nil, errors.Errorf("%s: no operators", path)
+	}
+
+	keys := make([]string, 0, len(ops))
+	operators := 0
+	for key := range ops {
+		keys = append(keys, key)
+		if _, ok := graphQLOperators[graphQLName(key)]; ok {
+			operators++
+		}
+	}
+	sort.Strings(keys)
+
+	switch operators {
+	case 0:
+		for _, key := range keys {
+			if _, ok := ops[key].(map[string]any); !ok {
+				return nil, errors.Errorf("%s: unknown operator %s", graphQLPath(path, key), key)
+			}
+		}
+		node, err := graphQLNode(ops, field+".", path)
+		return []*jsonNode{node}, err
+	case len(ops):
+	default:
+		return nil, errors.Errorf("%s: cannot mix operators and fields", path)
+	}
+
+	nodes := make([]*jsonNode, len(keys))
+	for i, key := range keys {
+		node, err := graphQLCondition(field, graphQLOperators[graphQLName(key)], ops[key], graphQLPath(path, key))
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = node
+	}
+	return nodes, nil
+}
+
+// graphQLCondition returns the condition node comparing the field using the operator. Equality with
+// null is converted to IS NULL, since = NULL never matches.
+func graphQLCondition(field, op string, value any, path string) (*jsonNode, error) {
+	if value == nil && (op == "=" || op == "!=") {
+		if op == "!=" {
+			return &jsonNode{Field: field, Op: "is not null", path: path}, nil
+		}
+		return &jsonNode{Field: field, Op: "is null", path: path}, nil
+	}
+	if op == "is null" {
+		isNull, ok := value.(bool)
+		if !ok {
+			return nil, errors.Errorf("%s: isNull requires a boolean", path)
+		}
+		if !isNull {
+			op = "is not null"
+		}
+		return &jsonNode{Field: field, Op: op, path: path}, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Errorf("%s: invalid value: %v", path, err)
+	}
+	return &jsonNode{Field: field, Op: op, Value: raw, path: path}, nil
+}
+
+// graphQLName normalizes an operator or logical key, e.g. _not_in and notIn both become notin.
+func graphQLName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
+
+func graphQLPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func graphQLIndexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
@@ -0,0 +1,173 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGraphQL(t *testing.T) {
+	tests := []struct {
+		name  string
+		input map[string]any
+		want  string
+	}{
+		{
+			name: "operators",
+			input: map[string]any{
+				"age":    map[string]any{"gt": 18, "lte": 65},
+				"status": map[string]any{"in": []string{"active", "pending"}},
+			},
+			want: "age > 18 AND age <= 65 AND status IN ('active', 'pending')",
+		},
+		{
+			name:  "scalar equality",
+			input: map[string]any{"status": "active", "deleted_at": nil},
+			want:  "deleted_at IS NULL AND status = 'active'",
+		},
+		{
+			name: "or",
+			input: map[string]any{
+				"OR": []any{
+					map[string]any{"name": map[string]any{"ilike": "j%"}},
+					map[string]any{"email": map[string]any{"notLike": "%@example.com"}},
+				},
+			},
+			want: "name ILIKE 'j%' OR email NOT LIKE '%@example.com'",
+		},
+		{
+			name: "hasura style",
+			input: map[string]any{
+				"_and": []map[string]any{
+					{"score": map[string]any{"_gte": 1.5}},
+					{"_or": []any{
+						map[string]any{"tags": map[string]any{"_nin": []any{"spam"}}},
+						map[string]any{"reviewed_at": map[string]any{"_is_null": false}},
+					}},
+				},
+				"_not": map[string]any{"archived": map[string]any{"_eq": true}},
+			},
+			want: "(score >= 1.5 AND (tags NOT IN ('spam') OR reviewed_at IS NOT NULL)) AND NOT archived = TRUE",
+		},
+		{
+			name: "relations",
+			input: map[string]any{
+				"user": map[string]any{
+					"name":    map[string]any{"eq": "x"},
+					"profile": map[string]any{"age": map[string]any{"between": []int{18, 30}}},
+				},
+			},
+			want: "user.name = 'x' AND user.profile.age BETWEEN 18 AND 30",
+		},
+		{
+			name:  "null equality",
+			input: map[string]any{"deleted_at": map[string]any{"eq": nil}, "email": map[string]any{"_neq": nil}},
+			want:  "deleted_at IS NULL AND email IS NOT NULL",
+		},
+		{
+			name:  "full-text search",
+			input: map[string]any{"body": map[string]any{"matches": "quick fox", "isNull": false}},
+			want:  "body IS NOT NULL AND body MATCHES 'quick fox'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.ParseGraphQL(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, filter.String())
+
+			want, err := where.Parse(tt.want)
+			require.NoError(t, err)
+			require.True(t, want.Equal(filter))
+		})
+	}
+}
+
+func TestParseGraphQLPipeline(t *testing.T) {
+	filter, err := where.ParseGraphQL(map[string]any{
+		"age":    map[string]any{"gt": 18},
+		"status": map[string]any{"in": []any{"active"}},
+	})
+	require.NoError(t, err)
+
+	validator := where.NewValidator().AllowFields("age", "status")
+	sql, params, err := filter.ToSQL("postgres", where.WithValidator(validator))
+	require.NoError(t, err)
+	require.Equal(t, "(age > $1 AND status IN ($2))", sql)
+	require.Equal(t, []any{float64(18), "active"}, params)
+
+	_, _, err = filter.ToSQL("postgres", where.WithValidator(where.NewValidator().AllowFields("age")))
+	require.EqualError(t, err, `field "status" is not allowed`)
+}
+
+func TestParseGraphQLErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input map[string]any
+		err   string
+	}{
+		{
+			name: "empty",
+			err:  "empty filter expression",
+		},
+		{
+			name:  "operators and fields",
+			input: map[string]any{"age": map[string]any{"gt": 1, "over": 2}},
+			err:   "failed to parse filter expression: age: cannot mix operators and fields",
+		},
+		{
+			name:  "invalid field",
+			input: map[string]any{"age; DROP TABLE users": 1},
+			err:   `failed to parse filter expression: age; DROP TABLE users: invalid field "age; DROP TABLE users"`,
+		},
+		{
+			name:  "empty operators",
+			input: map[string]any{"and": []any{map[string]any{"age": map[string]any{}}}},
+			err:   "failed to parse filter expression: and[0].age: no operators",
+		},
+		{
+			name:  "empty group",
+			input: map[string]any{"or": []any{}},
+			err:   "failed to parse filter expression: or: or requires a list of filter objects",
+		},
+		{
+			name:  "not a filter object",
+			input: map[string]any{"not": []any{1}},
+			err:   "failed to parse filter expression: not: not requires a filter object",
+		},
+		{
+			name:  "isNull value",
+			input: map[string]any{"age": map[string]any{"isNull": "yes"}},
+			err:   "failed to parse filter expression: age.isNull: isNull requires a boolean",
+		},
+		{
+			name:  "unknown operator",
+			input: map[string]any{"name": map[string]any{"contains": "x%"}},
+			err:   "failed to parse filter expression: name.contains: unknown operator contains",
+		},
+		{
+			name:  "unknown operator in a relation",
+			input: map[string]any{"user": map[string]any{"name": map[string]any{"startsWith": nil}}},
+			err:   "failed to parse filter expression: user.name.startsWith: unknown operator startsWith",
+		},
+		{
+			name:  "in value",
+			input: map[string]any{"age": map[string]any{"in": 5}},
+			err:   "failed to parse filter expression: age.in: operator in requires an array value",
+		},
+		{
+			name:  "object value",
+			input: map[string]any{"age": map[string]any{"eq": map[string]any{"gt": 1}}},
+			err:   "failed to parse filter expression: age.eq: operator = requires a string, number, boolean, or null value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := where.ParseGraphQL(tt.input)
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
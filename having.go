@@ -0,0 +1,157 @@
+package where
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// Where targets a WHERE clause. It is the default target.
+	Where ClauseTarget = "where"
+
+	// Having targets a HAVING clause. Fields must be grouped or appear inside aggregate functions.
+	Having ClauseTarget = "having"
+)
+
+type (
+	// ClauseTarget is the SQL clause the generated condition is intended for.
+	ClauseTarget string
+)
+
+// WithClauseTarget returns a BuildOption that sets the clause the filter is generated for. When the
+// target is Having, every field must either be listed with WithGroupBy, be a fragment registered with
+// WithFragments, or appear inside an aggregate function (see FunctionTypeAggregate).
+func WithClauseTarget(target ClauseTarget) BuildOption {
+	return func(b *SQLBuilder) {
+		b.target = target
+	}
+}
+
+// WithGroupBy returns a BuildOption that declares the grouped fields which may be referenced directly
+// in a HAVING filter. Field names are case-insensitive.
+func WithGroupBy(fields ...string) BuildOption {
+	return func(b *SQLBuilder) {
+		if b.groupBy == nil {
+			b.groupBy = make(map[string]bool, len(fields))
+		}
+		for _, field := range fields {
+			b.groupBy[strings.ToLower(field)] = true
+		}
+	}
+}
+
+// ToHavingSQL converts the filter to a HAVING condition for the specified database driver. It is
+// equivalent to ToSQL with WithClauseTarget(Having).
+//
+// Example:
+//
+//	filter, _ := where.Parse("SUM(amount) > 1000 AND region = 'EU'")
+//	sql, params, _ := filter.ToHavingSQL("postgres", where.WithGroupBy("region"))
+//	// (SUM(amount) > $1 AND region = $2)
+func (f *Filter) ToHavingSQL(driverName string, options ...BuildOption) (string, []any, error) {
+	return f.ToSQL(driverName, append(options, WithClauseTarget(Having))...)
+}
+
+// IsAggregateFunction returns true if the function is registered in StandardFunctions as an
+// aggregate function.
+func IsAggregateFunction(name string) bool {
+	def, ok := StandardFunctions[strings.ToUpper(name)]
+	return ok && def.Type == FunctionTypeAggregate
+}
+
+// checkStar verifies that * is only used as the argument of COUNT.
+func checkStar(fn *FunctionCall) error {
+	if fn.Star && !strings.EqualFold(fn.Name, "COUNT") {
+		return newMessage(MsgStarArgument, "function", fn.Name)
+	}
+	return nil
+}
+
+// checkNestedAggregates returns an error if an aggregate function appears in the arguments of the
+// aggregate function fn, such as SUM(SUM(x)), which databases reject.
+func checkNestedAggregates(fn *FunctionCall) error {
+	var err error
+	for _, arg := range fn.arguments() {
+		walkValue(arg, func(val *Value) {
+			if err == nil && val.Function != nil && IsAggregateFunction(val.Function.Name) {
+				err = newMessage(MsgNestedAggregate, "function", val.Function.Name, "outer", fn.Name)
+			}
+		})
+	}
+	return err
+}
+
+// checkHaving verifies that every field in the expression is grouped or aggregated.
+func (b *SQLBuilder) checkHaving(expr *Expression) error {
+	if expr == nil {
+		return nil
+	}
+
+	for _, term := range expr.Or {
+		if term == nil {
+			continue
+		}
+		for _, factor := range term.And {
+			if factor == nil {
+				continue
+			}
+			if err := b.checkHaving(factor.SubExpr); err != nil {
+				return err
+			}
+			if factor.Predicate == nil {
+				continue
+			}
+			if err := b.checkHavingValue(factor.Predicate.Left); err != nil {
+				return err
+			}
+			if factor.Predicate.Operation == nil {
+				continue
+			}
+			for _, val := range factor.Predicate.Operation.operands() {
+				if err := b.checkHavingValue(val); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (b *SQLBuilder) checkHavingValue(val *Value) error {
+	switch {
+	case val == nil:
+		return nil
+	case val.Function != nil:
+		// Any field may appear inside an aggregate, but not another aggregate.
+		if IsAggregateFunction(val.Function.Name) {
+			return checkNestedAggregates(val.Function)
+		}
+		for _, arg := range val.Function.arguments() {
+			if err := b.checkHavingValue(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	case val.Field != nil:
+		name := val.Field.String()
+		if b.groupBy[strings.ToLower(name)] {
+			return nil
+		}
+		if _, ok := b.fragment(val.Field); ok {
+			return nil
+		}
+		return newMessage(MsgNotGrouped, "field", strconv.Quote(name))
+	case val.Bitwise != nil:
+		if err := b.checkHavingValue(val.Bitwise.Left); err != nil {
+			return err
+		}
+		for _, op := range val.Bitwise.Ops {
+			if err := b.checkHavingValue(op.Right); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return b.checkHaving(val.SubExpr)
+	}
+}
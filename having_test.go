@@ -0,0 +1,138 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToHavingSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   string
+		options  []where.BuildOption
+		wantSQL  string
+		wantArgs []any
+		wantErr  string
+	}{
+		{
+			name:     "aggregates",
+			filter:   "SUM(amount) > 1000 AND COUNT(id) >= 5",
+			wantSQL:  "(SUM(amount) > $1 AND COUNT(id) >= $2)",
+			wantArgs: []any{float64(1000), float64(5)},
+		},
+		{
+			name:     "COUNT(*)",
+			filter:   "count(*) > 1 AND SUM(amount) > 10",
+			wantSQL:  "(count(*) > $1 AND SUM(amount) > $2)",
+			wantArgs: []any{float64(1), float64(10)},
+		},
+		{
+			name:     "grouped field",
+			filter:   "AVG(score) BETWEEN 1 AND 5 OR Region = 'EU'",
+			options:  []where.BuildOption{where.WithGroupBy("region")},
+			wantSQL:  "(AVG(score) BETWEEN $1 AND $2 OR Region = $3)",
+			wantArgs: []any{float64(1), float64(5), "EU"},
+		},
+		{
+			name:     "aggregate nested in scalar function",
+			filter:   "ROUND(AVG(price), 2) > 9.99",
+			wantSQL:  "ROUND(AVG(price), $1) > $2",
+			wantArgs: []any{float64(2), 9.99},
+		},
+		{
+			name:     "fragment",
+			filter:   "revenue > 100",
+			options:  []where.BuildOption{where.WithFragments(map[string]string{"revenue": "SUM(price * qty)"})},
+			wantSQL:  "(SUM(price * qty)) > $1",
+			wantArgs: []any{float64(100)},
+		},
+		{
+			name:    "ungrouped field",
+			filter:  "SUM(amount) > 1000 AND status = 'paid'",
+			wantErr: `field "status" must be grouped or used in an aggregate function`,
+		},
+		{
+			name:    "ungrouped field in scalar function",
+			filter:  "MAX(price) > 5 AND LOWER(name) = 'x'",
+			wantErr: `field "name" must be grouped or used in an aggregate function`,
+		},
+		{
+			name:    "ungrouped field in sub-expression",
+			filter:  "NOT (MIN(price) > 5 OR total < 10)",
+			wantErr: `field "total" must be grouped or used in an aggregate function`,
+		},
+		{
+			name:    "nested aggregate",
+			filter:  "SUM(SUM(x)) > 1",
+			wantErr: "aggregate function SUM cannot be nested inside SUM",
+		},
+		{
+			name:    "aggregate nested in an aggregate's expression",
+			filter:  "MAX(ABS(COUNT(*))) > 1",
+			wantErr: "aggregate function COUNT cannot be nested inside MAX",
+		},
+		{
+			name:    "ungrouped field on the right",
+			filter:  "MAX(price) > cost",
+			wantErr: `field "cost" must be grouped or used in an aggregate function`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.filter)
+			require.NoError(t, err)
+
+			sql, args, err := filter.ToHavingSQL("postgres", tt.options...)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestWithClauseTarget(t *testing.T) {
+	filter, err := where.Parse("status = 'paid'")
+	require.NoError(t, err)
+
+	_, _, err = filter.ToSQL("mysql", where.WithClauseTarget(where.Where))
+	require.NoError(t, err)
+
+	_, _, err = filter.ToSQL("mysql", where.WithClauseTarget(where.Having))
+	require.EqualError(t, err, `field "status" must be grouped or used in an aggregate function`)
+}
+
+func TestCountStar(t *testing.T) {
+	filter, err := where.Parse("COUNT( * ) > 1")
+	require.NoError(t, err)
+	require.Equal(t, "COUNT(*) > 1", filter.String())
+	require.Equal(t, filter.String(), filter.Clone().String())
+
+	parser, err := where.NewParser(where.WithFunctionArgValidation())
+	require.NoError(t, err)
+	_, err = parser.Parse("COUNT(*) > 1")
+	require.NoError(t, err)
+
+	_, err = where.Parse("SUM(*) > 1")
+	require.EqualError(t, err, "filter validation failed: * is only valid as the argument of COUNT, not SUM")
+
+	_, err = where.Parse("COUNT(*, x) > 1")
+	require.ErrorContains(t, err, "failed to parse filter expression")
+
+	_, err = where.Parse("a * 2 > 1")
+	require.ErrorContains(t, err, "failed to parse filter expression")
+}
+
+func TestIsAggregateFunction(t *testing.T) {
+	require.True(t, where.IsAggregateFunction("sum"))
+	require.True(t, where.IsAggregateFunction("COUNT"))
+	require.False(t, where.IsAggregateFunction("LOWER"))
+	require.False(t, where.IsAggregateFunction("unknown"))
+}
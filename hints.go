@@ -0,0 +1,132 @@
+package where
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// HintSetting is a named setting with a value, e.g. max_execution_time = 10.
+	HintSetting HintKind = iota
+	// HintOptimizer is an optimizer hint, e.g. MAX_EXECUTION_TIME(1000) or NO_INDEX_MERGE(t).
+	HintOptimizer
+)
+
+var settingName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type (
+	// HintKind identifies the kind of a Hint.
+	HintKind int
+
+	// Hint is a driver-specific query hint or setting, created with Setting or OptimizerHint and
+	// rendered with RenderHints. Hints come from application code, never from filter expressions.
+	Hint struct {
+		Kind HintKind
+		// Name is the setting name, or the text of an optimizer hint.
+		Name string
+		// Value is the value of a setting: a bool, integer, float, or string.
+		Value any
+	}
+
+	// QueryHints holds the SQL rendered from hints for a statement. Either part may be empty.
+	QueryHints struct {
+		// Prefix is placed right after the SELECT keyword, e.g. /*+ MAX_EXECUTION_TIME(1000) */ in MySQL.
+		Prefix string
+		// Suffix is placed at the end of the statement, e.g. SETTINGS max_execution_time = 10 in ClickHouse.
+		Suffix string
+	}
+)
+
+// Setting returns a hint setting name to value for a single query, such as a ClickHouse setting or
+// a MySQL SET_VAR hint. The value must be a bool, integer, float, or string.
+func Setting(name string, value any) Hint {
+	return Hint{Kind: HintSetting, Name: name, Value: value}
+}
+
+// OptimizerHint returns an optimizer hint written in the database's syntax, e.g.
+// MAX_EXECUTION_TIME(1000) for MySQL.
+func OptimizerHint(text string) Hint {
+	return Hint{Kind: HintOptimizer, Name: text}
+}
+
+// RenderHints validates the hints and returns the SQL placing them in a statement for the driver.
+// Drivers render hints by implementing HintRenderer, and fail for hints their database does not
+// support.
+//
+// Example:
+//
+//	hints, _ := where.RenderHints("clickhouse", where.Setting("max_execution_time", 10))
+//	query := "SELECT count() FROM events WHERE " + sql + " " + hints.Suffix
+//	// ... SETTINGS max_execution_time = 10
+func RenderHints(driverName string, hints ...Hint) (QueryHints, error) {
+	driver, err := GetDriver(driverName)
+	if err != nil {
+		return QueryHints{}, err
+	}
+	if len(hints) == 0 {
+		return QueryHints{}, nil
+	}
+
+	for _, hint := range hints {
+		if err := hint.validate(); err != nil {
+			return QueryHints{}, err
+		}
+	}
+
+	renderer, ok := DriverAs[HintRenderer](driver)
+	if !ok {
+		return QueryHints{}, errors.Errorf("query hints are not supported by driver %s", driver.Name())
+	}
+	return renderer.RenderHints(hints)
+}
+
+// SQLValue returns the value of a setting as a SQL literal. Booleans are rendered as 1 and 0, and
+// strings are quoted with backslash escapes.
+func (h Hint) SQLValue() string {
+	switch v := h.Value.(type) {
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case string:
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// validate returns an error if the hint cannot be rendered safely.
+func (h Hint) validate() error {
+	switch h.Kind {
+	case HintSetting:
+		if !settingName.MatchString(h.Name) {
+			return errors.Errorf("invalid setting name %q", h.Name)
+		}
+		switch h.Value.(type) {
+		case bool, string, float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return nil
+		default:
+			return errors.Errorf("setting %s has unsupported value type %T", h.Name, h.Value)
+		}
+	case HintOptimizer:
+		if strings.TrimSpace(h.Name) == "" {
+			return errors.New("optimizer hint is empty")
+		}
+		// Hints are written inside a comment, which */ would end.
+		if strings.Contains(h.Name, "*/") {
+			return errors.Errorf("invalid optimizer hint %q", h.Name)
+		}
+		return nil
+	default:
+		return errors.Errorf("unknown hint kind %d", h.Kind)
+	}
+}
@@ -0,0 +1,125 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHints(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		hints  []where.Hint
+		want   where.QueryHints
+	}{
+		{
+			name:   "no hints",
+			driver: "postgres",
+		},
+		{
+			name:   "clickhouse settings",
+			driver: "clickhouse",
+			hints: []where.Hint{
+				where.Setting("max_execution_time", 10),
+				where.Setting("use_query_cache", true),
+				where.Setting("load_balancing", "nearest_hostname"),
+			},
+			want: where.QueryHints{
+				Suffix: "SETTINGS max_execution_time = 10, use_query_cache = 1, load_balancing = 'nearest_hostname'",
+			},
+		},
+		{
+			name:   "mysql optimizer hints",
+			driver: "mysql",
+			hints: []where.Hint{
+				where.OptimizerHint("MAX_EXECUTION_TIME(1000)"),
+				where.Setting("sort_buffer_size", uint64(16777216)),
+				where.OptimizerHint("NO_INDEX_MERGE(t)"),
+			},
+			want: where.QueryHints{
+				Prefix: "/*+ MAX_EXECUTION_TIME(1000) SET_VAR(sort_buffer_size = 16777216) NO_INDEX_MERGE(t) */",
+			},
+		},
+		{
+			name:   "quoted string",
+			driver: "mysql",
+			hints:  []where.Hint{where.Setting("optimizer_switch", `it's \on`)},
+			want:   where.QueryHints{Prefix: `/*+ SET_VAR(optimizer_switch = 'it\'s \\on') */`},
+		},
+		{
+			name:   "float",
+			driver: "clickhouse",
+			hints:  []where.Hint{where.Setting("max_memory_usage_ratio", 0.5)},
+			want:   where.QueryHints{Suffix: "SETTINGS max_memory_usage_ratio = 0.5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := where.RenderHints(tt.driver, tt.hints...)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRenderHintsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		hint   where.Hint
+		err    string
+	}{
+		{
+			name:   "unknown driver",
+			driver: "oracle",
+			hint:   where.Setting("a", 1),
+			err:    `driver "oracle" not registered`,
+		},
+		{
+			name:   "injected setting name",
+			driver: "clickhouse",
+			hint:   where.Setting("a = 1; DROP TABLE t; --", 1),
+			err:    `invalid setting name "a = 1; DROP TABLE t; --"`,
+		},
+		{
+			name:   "unsupported value",
+			driver: "clickhouse",
+			hint:   where.Setting("max_threads", []int{1}),
+			err:    "setting max_threads has unsupported value type []int",
+		},
+		{
+			name:   "comment end",
+			driver: "mysql",
+			hint:   where.OptimizerHint("BKA(t) */ DROP TABLE t; /*"),
+			err:    `invalid optimizer hint "BKA(t) */ DROP TABLE t; /*"`,
+		},
+		{
+			name:   "empty optimizer hint",
+			driver: "mysql",
+			hint:   where.OptimizerHint(" "),
+			err:    "optimizer hint is empty",
+		},
+		{
+			name:   "optimizer hint in clickhouse",
+			driver: "clickhouse",
+			hint:   where.OptimizerHint("BKA(t)"),
+			err:    `optimizer hint "BKA(t)" is not supported by ClickHouse`,
+		},
+		{
+			name:   "driver without hints",
+			driver: "postgres",
+			hint:   where.Setting("statement_timeout", 1000),
+			err:    "query hints are not supported by driver postgres",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := where.RenderHints(tt.driver, tt.hint)
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package where
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// SimpleIdentifier matches identifiers that could be written without quotes: ASCII letters, digits,
+// and underscores, not starting with a digit. Use it with IdentifierPattern to reject quoted
+// identifiers containing spaces, punctuation, control characters, or unicode homoglyphs.
+var SimpleIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// MaxIdentifierLength rejects field names with any identifier longer than max bytes, after removing
+// quotes. Each part of a qualified name such as users.email is checked separately. PostgreSQL, for
+// example, truncates identifiers longer than 63 bytes.
+func (v *Validator) MaxIdentifierLength(max int) *Validator {
+	v.maxIdentifierLength = max
+	return v
+}
+
+// IdentifierPattern rejects field names with any identifier that does not match re, after removing
+// quotes. Each part of a qualified name such as users.email is checked separately. The policy
+// applies in addition to the field allowlist, including when AllowAll is set.
+//
+// Example:
+//
+//	validator := where.NewValidator().AllowAll().IdentifierPattern(where.SimpleIdentifier)
+//	// `user name; drop` = 1 is rejected
+func (v *Validator) IdentifierPattern(re *regexp.Regexp) *Validator {
+	v.identifierPattern = re
+	return v
+}
+
+// CheckIdentifier returns an error if the unquoted identifier violates the validator's identifier
+// length or character policy.
+func (v *Validator) CheckIdentifier(name string) error {
+	if v.maxIdentifierLength > 0 && len(name) > v.maxIdentifierLength {
+		return newMessage(MsgIdentifierTooLong, "identifier", strconv.Quote(name), "max", v.maxIdentifierLength)
+	}
+	if v.identifierPattern != nil && !v.identifierPattern.MatchString(name) {
+		return newMessage(MsgIdentifierCharacters, "identifier", strconv.Quote(name))
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package where_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentifierPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    string
+		validator *where.Validator
+		wantSQL   string
+		wantErr   string
+	}{
+		{
+			name:      "simple identifiers pass",
+			filter:    "users.email = 'x' AND `status` = 'a'",
+			validator: where.NewValidator().AllowAll().IdentifierPattern(where.SimpleIdentifier),
+			wantSQL:   "(users.email = $1 AND status = $2)",
+		},
+		{
+			name:      "punctuation in backtick identifier",
+			filter:    "`user name; drop` = 1",
+			validator: where.NewValidator().AllowAll().IdentifierPattern(where.SimpleIdentifier),
+			wantErr:   `identifier "user name; drop" contains disallowed characters`,
+		},
+		{
+			name:      "unicode homoglyph",
+			filter:    "`emаil` = 'x'", // Cyrillic "а"
+			validator: where.NewValidator().AllowAll().IdentifierPattern(where.SimpleIdentifier),
+			wantErr:   `identifier "emаil" contains disallowed characters`,
+		},
+		{
+			name:      "control character",
+			filter:    "`a\tb` = 1",
+			validator: where.NewValidator().AllowAll().IdentifierPattern(where.SimpleIdentifier),
+			wantErr:   `identifier "a\tb" contains disallowed characters`,
+		},
+		{
+			name:      "qualified name parts are checked separately",
+			filter:    "users.`bad-name` = 1",
+			validator: where.NewValidator().AllowAll().IdentifierPattern(where.SimpleIdentifier),
+			wantErr:   `identifier "bad-name" contains disallowed characters`,
+		},
+		{
+			name:      "custom pattern",
+			filter:    "`first name` = 'x'",
+			validator: where.NewValidator().AllowAll().IdentifierPattern(regexp.MustCompile(`^[a-z ]+$`)),
+			wantSQL:   `"first name" = $1`,
+		},
+		{
+			name:      "within maximum length",
+			filter:    "users.email = 'x'",
+			validator: where.NewValidator().AllowAll().MaxIdentifierLength(5),
+			wantSQL:   "users.email = $1",
+		},
+		{
+			name:      "exceeds maximum length",
+			filter:    strings.Repeat("a", 64) + " = 1",
+			validator: where.NewValidator().AllowAll().MaxIdentifierLength(63),
+			wantErr:   `identifier "` + strings.Repeat("a", 64) + `" exceeds maximum length of 63`,
+		},
+		{
+			name:      "allowlist is still applied",
+			filter:    "secret = 1",
+			validator: where.NewValidator().AllowFields("email").IdentifierPattern(where.SimpleIdentifier),
+			wantErr:   `field "secret" is not allowed`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := mustParse(t, tt.filter)
+
+			sql, _, err := filter.ToSQL("postgres", where.WithValidator(tt.validator))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+		})
+	}
+}
+
+func TestIdentifierPolicyRejection(t *testing.T) {
+	var meta where.RejectionMeta
+	validator := where.NewValidator().
+		AllowAll().
+		IdentifierPattern(where.SimpleIdentifier).
+		OnRejection(func(_ string, _ error, m where.RejectionMeta) { meta = m })
+
+	_, _, err := mustParse(t, "`a;b` = 1").ToSQL("postgres", where.WithValidator(validator))
+	require.Error(t, err)
+	require.Equal(t, where.RejectionMeta{Rule: where.RuleField, Field: "`a;b`"}, meta)
+}
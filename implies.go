@@ -0,0 +1,258 @@
+package where
+
+// implication compares canonicalized conditions by their formatted text.
+type implication struct {
+	fm *formatter
+}
+
+// Equal reports whether the filters are equivalent, i.e. each implies the other (see Implies). It
+// ignores formatting, keyword and identifier case, and the order of AND and OR operands, so it can be
+// used to detect duplicate saved filters. Nil filters are only equal to each other.
+func (f *Filter) Equal(other *Filter) bool {
+	if f == nil || f.Expression == nil || other == nil || other.Expression == nil {
+		return (f == nil || f.Expression == nil) && (other == nil || other.Expression == nil)
+	}
+	return f.Implies(other) && other.Implies(f)
+}
+
+// Implies reports whether every row matched by the filter is also matched by other, i.e. the filter
+// is at least as restrictive. This can verify that a user's filter stays within a mandated baseline.
+//
+// Implication is only detected for simple cases, and Implies returns false when it cannot be shown:
+// each condition of other must appear in the filter, or follow from the filter's conditions on the
+// same field: a tighter numeric range (age > 21 implies age >= 18), a subset of IN values
+// (status = 'a' implies status IN ('a', 'b')), or any comparison implying IS NOT NULL. OR groups in
+// other are implied when one of their branches is, and OR groups in the filter, including OR at its
+// root, imply a condition when each of their branches does. A nil filter matches every row and
+// implies only nil filters.
+//
+// Example:
+//
+//	baseline, _ := where.Parse("tenant_id = 7 AND deleted_at IS NULL")
+//	filter, _ := where.Parse("deleted_at IS NULL AND tenant_id = 7 AND age > 21")
+//	filter.Implies(baseline) // true
+func (f *Filter) Implies(other *Filter) bool {
+	if other == nil || other.Expression == nil {
+		return true
+	}
+	if f == nil || f.Expression == nil {
+		return false
+	}
+
+	expr, target := f.Expression.DeepCopy(), other.Expression.DeepCopy()
+	canonicalize(expr)
+	canonicalize(target)
+
+	im := implication{fm: &formatter{opts: &formatOptions{compact: true}}}
+	for _, term := range expr.Or {
+		if term == nil || !im.impliesExpression(conjuncts(term.And), target) {
+			return false
+		}
+	}
+	return true
+}
+
+// conjuncts returns the factors ANDed together, flattening parenthesized AND groups.
+func conjuncts(factors []*Factor) []*Factor {
+	var flat []*Factor
+	for _, factor := range factors {
+		switch {
+		case factor == nil:
+		case !factor.Not && factor.SubExpr != nil && len(factor.SubExpr.Or) == 1 && factor.SubExpr.Or[0] != nil:
+			flat = append(flat, conjuncts(factor.SubExpr.Or[0].And)...)
+		default:
+			flat = append(flat, factor)
+		}
+	}
+	return flat
+}
+
+// impliesExpression reports whether the ANDed factors imply one of the expression's OR branches.
+func (im implication) impliesExpression(factors []*Factor, expr *Expression) bool {
+	for _, term := range expr.Or {
+		if term == nil {
+			continue
+		}
+
+		implied := true
+		for _, target := range conjuncts(term.And) {
+			if !im.impliesFactor(factors, target) {
+				implied = false
+				break
+			}
+		}
+		if implied {
+			return true
+		}
+	}
+	return false
+}
+
+// impliesFactor reports whether the ANDed factors imply the target factor.
+func (im implication) impliesFactor(factors []*Factor, target *Factor) bool {
+	key := im.fm.factor(target, "")
+	for _, factor := range factors {
+		if im.fm.factor(factor, "") == key || im.groupImplies(factor, target) {
+			return true
+		}
+	}
+
+	switch {
+	case target.Not || target.Exists != nil:
+		return false
+	case target.SubExpr != nil:
+		return im.impliesExpression(factors, target.SubExpr)
+	case target.Predicate == nil || target.Predicate.Left == nil || target.Predicate.Left.Field == nil:
+		return false
+	}
+
+	field := target.Predicate.Left.Field.String()
+	op := target.Predicate.Operation
+	switch {
+	case op.IsNull != nil && op.IsNull.Not:
+		return impliesNotNull(factors, field)
+	case op.In != nil && !op.In.Not:
+		return im.impliesIn(factors, field, op.In.Values)
+	case op.Compare != nil && op.Compare.Operator.String() == "=":
+		return im.impliesIn(factors, field, []*Value{op.Compare.Right})
+	}
+
+	if _, lower, upper, ok := factorRange(target); ok {
+		return impliesRange(factors, field, lower, upper)
+	}
+	return false
+}
+
+// groupImplies reports whether the factor is an OR group whose every branch implies the target.
+func (im implication) groupImplies(factor, target *Factor) bool {
+	if factor.Not || factor.SubExpr == nil || len(factor.SubExpr.Or) < 2 {
+		return false
+	}
+
+	for _, term := range factor.SubExpr.Or {
+		if term == nil || !im.impliesFactor(conjuncts(term.And), target) {
+			return false
+		}
+	}
+	return true
+}
+
+// impliesNotNull reports whether a factor compares the field in a way that rejects NULL.
+func impliesNotNull(factors []*Factor, field string) bool {
+	for _, factor := range factors {
+		if factor.Not || factor.Predicate == nil || factor.Predicate.Operation == nil {
+			continue
+		}
+
+		pred := factor.Predicate
+		if pred.Left == nil || pred.Left.Field == nil || pred.Left.Field.String() != field {
+			continue
+		}
+
+		op := pred.Operation
+		switch {
+		case op.IsNull != nil:
+			if op.IsNull.Not {
+				return true
+			}
+		case op.Compare != nil:
+			if op.Compare.Operator.String() != "<=>" {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// impliesIn reports whether a factor restricts the field to a subset of the values.
+func (im implication) impliesIn(factors []*Factor, field string, values []*Value) bool {
+	allowed := make(map[string]bool, len(values))
+	for _, val := range values {
+		allowed[im.fm.value(val)] = true
+	}
+
+	for _, factor := range factors {
+		if factor.Not || factor.Predicate == nil || factor.Predicate.Operation == nil {
+			continue
+		}
+
+		pred := factor.Predicate
+		if pred.Left == nil || pred.Left.Field == nil || pred.Left.Field.String() != field {
+			continue
+		}
+
+		var vals []*Value
+		switch op := pred.Operation; {
+		case op.In != nil && !op.In.Not:
+			vals = op.In.Values
+		case op.Compare != nil && op.Compare.Operator.String() == "=":
+			vals = []*Value{op.Compare.Right}
+		default:
+			continue
+		}
+
+		subset := len(vals) > 0
+		for _, val := range vals {
+			subset = subset && allowed[im.fm.value(val)]
+		}
+		if subset {
+			return true
+		}
+	}
+	return false
+}
+
+// impliesRange reports whether the numeric bounds the factors place on the field are at least as
+// tight as the given bounds.
+func impliesRange(factors []*Factor, field string, lower, upper *bound) bool {
+	r := &fieldRange{}
+	for _, factor := range factors {
+		ref, lo, hi, ok := factorRange(factor)
+		if !ok {
+			ref, lo, hi, ok = equalityRange(factor)
+		}
+		if !ok || ref.String() != field {
+			continue
+		}
+		r.lower = tighterBound(r.lower, lo, 1)
+		r.upper = tighterBound(r.upper, hi, -1)
+	}
+
+	return withinBound(r.lower, lower, 1) && withinBound(r.upper, upper, -1)
+}
+
+// equalityRange returns the range of a numeric equality factor such as age = 21.
+func equalityRange(factor *Factor) (*FieldRef, *bound, *bound, bool) {
+	if factor.Not || factor.Predicate == nil || factor.Predicate.Operation == nil {
+		return nil, nil, nil, false
+	}
+
+	pred := factor.Predicate
+	op := pred.Operation
+	if pred.Left == nil || pred.Left.Field == nil || op.Compare == nil || op.Compare.Operator.String() != "=" {
+		return nil, nil, nil, false
+	}
+
+	b, ok := numericBound(op.Compare.Right, true)
+	if !ok {
+		return nil, nil, nil, false
+	}
+	return pred.Left.Field, b, b, true
+}
+
+// withinBound reports whether the actual bound is at least as tight as the required one. direction
+// is 1 for lower bounds and -1 for upper bounds.
+func withinBound(actual, required *bound, direction float64) bool {
+	switch {
+	case required == nil:
+		return true
+	case actual == nil:
+		return false
+	case actual.value*direction != required.value*direction:
+		return actual.value*direction > required.value*direction
+	default:
+		return required.inclusive || !actual.inclusive
+	}
+}
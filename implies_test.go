@@ -0,0 +1,91 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterImplies(t *testing.T) {
+	tests := []struct {
+		filter string
+		other  string
+		want   bool
+	}{
+		{filter: "a = 1 AND b = 2", other: "b = 2", want: true},
+		{filter: "B = 2 and A = 1", other: "a = 1 AND b = 2", want: true},
+		{filter: "a = 1", other: "a = 1 AND b = 2", want: false},
+		{filter: "age > 21", other: "age >= 18", want: true},
+		{filter: "age >= 18", other: "age > 18", want: false},
+		{filter: "age > 18", other: "age >= 18", want: true},
+		{filter: "age BETWEEN 20 AND 30", other: "age > 18 AND age <= 30", want: true},
+		{filter: "age >= 18 AND age < 65", other: "age BETWEEN 18 AND 65", want: true},
+		{filter: "age > 18", other: "age BETWEEN 18 AND 65", want: false},
+		{filter: "age = 30", other: "age BETWEEN 18 AND 65", want: true},
+		{filter: "status = 'a'", other: "status IN ('a', 'b')", want: true},
+		{filter: "status IN ('b', 'a')", other: "status IN ('a', 'b', 'c')", want: true},
+		{filter: "status IN ('a', 'd')", other: "status IN ('a', 'b')", want: false},
+		{filter: "status IN ('a')", other: "status = 'a'", want: true},
+		{filter: "status = 'A'", other: "status = 'a'", want: false},
+		{filter: "age > 18", other: "age IS NOT NULL", want: true},
+		{filter: "name LIKE 'a%'", other: "name IS NOT NULL", want: true},
+		{filter: "name IS NULL", other: "name IS NOT NULL", want: false},
+		{filter: "a = 1", other: "a = 1 OR b = 2", want: true},
+		{filter: "a = 1 OR b = 2", other: "b = 2 OR a = 1", want: true},
+		{filter: "a = 1 OR b = 2", other: "a = 1", want: false},
+		{filter: "(a = 1 AND b = 2) OR (a = 1 AND c = 3)", other: "a = 1", want: true},
+		{filter: "tenant_id = 7 AND (x = 1 OR y = 2)", other: "(y = 2 OR x = 1) AND tenant_id = 7", want: true},
+		{filter: "tenant_id = 7 AND (a = 1 AND b = 2)", other: "b = 2", want: true},
+		{filter: "NOT (a = 1)", other: "NOT (A = 1)", want: true},
+		{filter: "NOT (a = 1)", other: "a != 1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter+" => "+tt.other, func(t *testing.T) {
+			filter, err := where.Parse(tt.filter)
+			require.NoError(t, err)
+			other, err := where.Parse(tt.other)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.want, filter.Implies(other))
+		})
+	}
+}
+
+func TestFilterEqual(t *testing.T) {
+	tests := []struct {
+		filter string
+		other  string
+		want   bool
+	}{
+		{filter: "a = 1 AND b = 2", other: "b = 2 and A = 1", want: true},
+		{filter: "LOWER(email) = 'x'", other: "lower(Email) = 'x'", want: true},
+		{filter: "a = 1 OR (b = 2 AND c = 3)", other: "(c = 3 AND b = 2) OR a = 1", want: true},
+		{filter: "age > 18 AND age > 21", other: "age > 21", want: true},
+		{filter: "a = 1", other: "a = 1 AND b = 2", want: false},
+		{filter: "a = 1", other: "a = 2", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter+" == "+tt.other, func(t *testing.T) {
+			filter, err := where.Parse(tt.filter)
+			require.NoError(t, err)
+			other, err := where.Parse(tt.other)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.want, filter.Equal(other))
+			require.Equal(t, tt.want, other.Equal(filter))
+		})
+	}
+}
+
+func TestFilterImpliesNil(t *testing.T) {
+	filter, err := where.Parse("a = 1")
+	require.NoError(t, err)
+
+	require.True(t, filter.Implies(nil))
+	require.False(t, (*where.Filter)(nil).Implies(filter))
+	require.False(t, filter.Equal(nil))
+	require.True(t, (*where.Filter)(nil).Equal(nil))
+}
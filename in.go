@@ -0,0 +1,113 @@
+package where
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// In returns a filter matching rows where the field is one of the values. The values are bound as
+// parameters without conversion, and a single slice argument is expanded, so In("id", ids) and
+// In("id", ids...) are equivalent. An empty list matches no rows (rendered as FALSE). Like other
+// filters, the result can be passed to WithRequiredFilter.
+//
+// Example:
+//
+//	filter := where.In("user_id", []int64{1, 2, 3})
+//	sql, params, _ := filter.ToSQL("postgres")
+//	// user_id IN ($1, $2, $3) with params [1 2 3]
+func In(field string, values ...any) *Filter {
+	return inFilter(field, values, false)
+}
+
+// NotIn returns a filter matching rows where the field is none of the values. An empty list matches
+// every row (rendered as TRUE). See In.
+func NotIn(field string, values ...any) *Filter {
+	return inFilter(field, values, true)
+}
+
+func inFilter(field string, values []any, not bool) *Filter {
+	values = expandValues(values)
+
+	in := &InOp{Not: not, In: "IN", Values: make([]*Value, len(values))}
+	for i, value := range values {
+		in.Values[i] = &Value{Literal: &LiteralValue{bound: true, param: value}}
+	}
+
+	pred := &Predicate{
+		Left:      &Value{Field: &FieldRef{Parts: strings.Split(field, ".")}},
+		Operation: &Operation{In: in},
+	}
+	return &Filter{Expression: &Expression{Or: []*Term{{And: []*Factor{{Predicate: pred}}}}}}
+}
+
+// expandValues expands a single slice argument (other than a byte slice) into its elements.
+func expandValues(values []any) []any {
+	if len(values) != 1 {
+		return values
+	}
+	if _, ok := values[0].([]byte); ok {
+		return values
+	}
+
+	v := reflect.ValueOf(values[0])
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return values
+	}
+
+	expanded := make([]any, v.Len())
+	for i := range expanded {
+		expanded[i] = v.Index(i).Interface()
+	}
+	return expanded
+}
+
+// boundType returns the literal type name for a bound Go value.
+func boundType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string, time.Time:
+		return "string"
+	case []byte:
+		return "binary"
+	case bool:
+		return "boolean"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return "number"
+	default:
+		return "value"
+	}
+}
+
+// boundLiteral formats a bound Go value as a filter literal.
+func (fm *formatter) boundLiteral(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return fm.keyword("NULL")
+	case bool:
+		if v {
+			return fm.keyword("TRUE")
+		}
+		return fm.keyword("FALSE")
+	case []byte:
+		return fmt.Sprintf("0x%X", v)
+	case time.Time:
+		return quoteString(v.Format(time.RFC3339Nano))
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return quoteString(v)
+	default:
+		if boundType(v) == "number" {
+			return fmt.Sprint(v)
+		}
+		return quoteString(fmt.Sprint(v))
+	}
+}
+
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
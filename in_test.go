@@ -0,0 +1,121 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEmptyIN(t *testing.T) {
+	parser, err := where.NewParser(where.WithEmptyIN())
+	require.NoError(t, err)
+
+	tests := []struct {
+		filter   string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{"id IN ()", "FALSE", []any{}},
+		{"id NOT IN ()", "TRUE", []any{}},
+		{"status = 'a' AND id IN ()", "(status = $1 AND FALSE)", []any{"a"}},
+		{"id IN () OR id NOT IN ( )", "(FALSE OR TRUE)", []any{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := parser.Parse(tt.filter)
+			require.NoError(t, err)
+
+			sql, args, err := filter.ToSQL("postgres", where.WithArrayBinding(), where.WithStableShape())
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+
+	_, err = where.Parse("id IN ()")
+	require.ErrorContains(t, err, "IN expression requires at least one value")
+}
+
+func TestIn(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   *where.Filter
+		driver   string
+		wantSQL  string
+		wantArgs []any
+		wantStr  string
+	}{
+		{
+			name:     "variadic values",
+			filter:   where.In("status", "active", "pending"),
+			driver:   "postgres",
+			wantSQL:  "status IN ($1, $2)",
+			wantArgs: []any{"active", "pending"},
+			wantStr:  "status IN ('active', 'pending')",
+		},
+		{
+			name:     "slice values keep their types",
+			filter:   where.In("users.id", []int64{9007199254740993, 2}),
+			driver:   "mysql",
+			wantSQL:  "users.id IN (?, ?)",
+			wantArgs: []any{int64(9007199254740993), int64(2)},
+			wantStr:  "users.id IN (9007199254740993, 2)",
+		},
+		{
+			name:     "not in",
+			filter:   where.NotIn("name", []string{"o'brien"}),
+			driver:   "postgres",
+			wantSQL:  "name NOT IN ($1)",
+			wantArgs: []any{"o'brien"},
+			wantStr:  "name NOT IN ('o''brien')",
+		},
+		{
+			name:     "byte slice is a single value",
+			filter:   where.In("hash", []byte{0xAB}),
+			driver:   "postgres",
+			wantSQL:  "hash IN ($1)",
+			wantArgs: []any{[]byte{0xAB}},
+			wantStr:  "hash IN (0xAB)",
+		},
+		{
+			name:     "empty",
+			filter:   where.In("id", []int{}),
+			driver:   "postgres",
+			wantSQL:  "FALSE",
+			wantArgs: []any{},
+			wantStr:  "id IN ()",
+		},
+		{
+			name:     "empty not in",
+			filter:   where.NotIn("id"),
+			driver:   "mysql",
+			wantSQL:  "TRUE",
+			wantArgs: []any{},
+			wantStr:  "id NOT IN ()",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.filter.ToSQL(tt.driver)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+			require.Equal(t, tt.wantStr, tt.filter.String())
+		})
+	}
+}
+
+func TestInRequiredFilter(t *testing.T) {
+	filter := mustParse(t, "age > 18")
+
+	sql, args, err := filter.ToSQL("postgres",
+		where.WithRequiredFilter(where.In("org_id", 1, 2)),
+		where.WithValidator(where.NewValidator().AllowFields("age")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "(age > $1 AND org_id IN ($2, $3))", sql)
+	require.Equal(t, []any{float64(18), 1, 2}, args)
+}
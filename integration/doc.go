@@ -0,0 +1,6 @@
+// Package integration runs SQL generated by where against real PostgreSQL, MySQL, and ClickHouse
+// databases started with dockertest. The tests are behind the integration build tag and require
+// Docker:
+//
+//	cd integration && go test -tags integration ./...
+package integration
@@ -0,0 +1,304 @@
+package where
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// SchemaOption configures ValidatorFromDB.
+	SchemaOption func(*schemaConfig)
+
+	// schemaConfig holds the settings used to introspect a table.
+	schemaConfig struct {
+		driver string
+	}
+)
+
+// columnQueries list the columns of a table for each database, ordered by position. The schema and
+// table are bound as parameters.
+var columnQueries = map[string]string{
+	"postgres": "SELECT column_name FROM information_schema.columns " +
+		"WHERE table_schema = $1 AND table_name = $2 ORDER BY ordinal_position",
+	"mysql": "SELECT column_name FROM information_schema.columns " +
+		"WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position",
+	"clickhouse": "SELECT name FROM system.columns WHERE database = ? AND table = ? ORDER BY position",
+}
+
+// WithSchemaDriver sets the database ValidatorFromDB queries, one of "postgres", "mysql", or
+// "clickhouse". It is only needed when the database cannot be detected from the database/sql
+// driver, e.g. when the driver is wrapped for tracing.
+func WithSchemaDriver(name string) SchemaOption {
+	return func(c *schemaConfig) {
+		c.driver = strings.ToLower(name)
+	}
+}
+
+// ValidatorFromDB returns a validator allowing the columns of a table, as reported by the database,
+// so the allowlist cannot drift from the schema. Columns are allowed both on their own and qualified
+// by the table name. PostgreSQL and MySQL are queried through information_schema and ClickHouse
+// through system.columns; for MySQL and ClickHouse the schema is the database name.
+//
+// Example:
+//
+//	validator, err := where.ValidatorFromDB(ctx, db, "public", "users")
+//	if err != nil {
+//		return err
+//	}
+//	validator.DenyFields("password_hash").AllowFunctions("LOWER")
+func ValidatorFromDB(ctx context.Context, db *sql.DB, schema, table string, opts ...SchemaOption) (*Validator, error) {
+	cfg := &schemaConfig{driver: detectSchemaDriver(db)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	query, ok := columnQueries[cfg.driver]
+	if !ok {
+		return nil, fmt.Errorf("cannot introspect database for driver %T; use WithSchemaDriver", db.Driver())
+	}
+
+	rows, err := db.QueryContext(ctx, query, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list columns of %s.%s: %w", schema, table, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("failed to list columns of %s.%s: %w", schema, table, err)
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list columns of %s.%s: %w", schema, table, err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s.%s not found", schema, table)
+	}
+
+	return NewValidator().allowColumns(table, columns), nil
+}
+
+// ValidatorFromModel returns a validator allowing the columns of a struct model such as those
+// generated by sqlc or declared for gorm. Column names are taken from the gorm column setting, then
+// the db tag, and otherwise the snake_cased field name, matching gorm's default naming. Fields
+// tagged gorm:"-", db:"-", or where:"-" are not allowed, and embedded structs are included. If the
+// model has a TableName method, columns are also allowed qualified by the table name.
+//
+// Example:
+//
+//	type User struct {
+//		ID       int64
+//		Email    string `db:"email"`
+//		Password string `where:"-"`
+//	}
+//
+//	validator, err := where.ValidatorFromModel(User{}) // allows id and email
+func ValidatorFromModel(model any) (*Validator, error) {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("model must be a struct, got %T", model)
+	}
+
+	table := ""
+	if named, ok := model.(interface{ TableName() string }); ok {
+		table = named.TableName()
+	}
+
+	return NewValidator().allowColumns(table, modelColumns(t, nil)), nil
+}
+
+// ValidatorFromStruct returns a validator allowing the fields of an API resource type under the
+// names given by a struct tag such as json, so the type defines which fields are filterable. Each
+// field is written in the SQL as its column, which is derived as in ValidatorFromModel. Fields
+// without the tag use the Go field name, as encoding/json does, and fields tagged "-" or where:"-"
+// are not allowed. Embedded structs without a tag name are included.
+//
+// Example:
+//
+//	type Order struct {
+//		ID        int64     `json:"id"`
+//		CreatedAt time.Time `json:"createdAt"`
+//		Total     float64   `json:"total" db:"total_cents"`
+//	}
+//
+//	validator, err := where.ValidatorFromStruct(Order{}, "json")
+//	// createdAt > '2024-01-01' AND total < 100 becomes created_at > ? AND total_cents < ?
+func ValidatorFromStruct(model any, tag string) (*Validator, error) {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("model must be a struct, got %T", model)
+	}
+	if tag == "" {
+		return nil, errors.New("struct tag is empty")
+	}
+
+	v := NewValidator()
+	structFields(t, tag, v)
+	return v, nil
+}
+
+// structFields maps the names of the struct type's fields in the tag to their columns.
+func structFields(t reflect.Type, tag string, v *Validator) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+
+		column, skip := modelColumn(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				structFields(ft, tag, v)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		v.MapField(name, column)
+	}
+}
+
+// allowColumns allows each column, and each column qualified by table if table is not empty.
+func (v *Validator) allowColumns(table string, columns []string) *Validator {
+	v.AllowFields(columns...)
+	if table != "" {
+		for _, column := range columns {
+			v.AllowFields(table + "." + column)
+		}
+	}
+	return v
+}
+
+// detectSchemaDriver guesses the database from the package of the database/sql driver.
+func detectSchemaDriver(db *sql.DB) string {
+	t := reflect.TypeOf(db.Driver())
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	pkg := strings.ToLower(t.PkgPath())
+	switch {
+	case strings.Contains(pkg, "clickhouse"):
+		return "clickhouse"
+	case strings.Contains(pkg, "mysql"):
+		return "mysql"
+	case strings.Contains(pkg, "lib/pq"), strings.Contains(pkg, "pgx"):
+		return "postgres"
+	}
+	return ""
+}
+
+// modelColumns appends the column names of the struct type's fields to columns.
+func modelColumns(t reflect.Type, columns []string) []string {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		column, skip := modelColumn(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && !hasColumnTag(field) {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				columns = modelColumns(ft, columns)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// modelColumn returns the column of a struct field: the gorm column setting, then the db tag, and
+// otherwise the snake_cased field name. Fields tagged gorm:"-", db:"-", or where:"-" are skipped.
+func modelColumn(field reflect.StructField) (column string, skip bool) {
+	if field.Tag.Get("where") == "-" || field.Tag.Get("db") == "-" {
+		return "", true
+	}
+
+	column, skip = gormColumn(field.Tag.Get("gorm"))
+	if skip {
+		return "", true
+	}
+	if column == "" {
+		column, _, _ = strings.Cut(field.Tag.Get("db"), ",")
+	}
+	if column == "" {
+		column = snakeCase(field.Name)
+	}
+	return column, false
+}
+
+// hasColumnTag returns true if the field's gorm tag sets its column.
+func hasColumnTag(field reflect.StructField) bool {
+	column, _ := gormColumn(field.Tag.Get("gorm"))
+	return column != ""
+}
+
+// gormColumn returns the column setting of a gorm struct tag and whether the field is ignored.
+func gormColumn(tag string) (column string, skip bool) {
+	if tag == "-" || tag == "-:all" {
+		return "", true
+	}
+	for _, setting := range strings.Split(tag, ";") {
+		key, value, _ := strings.Cut(setting, ":")
+		if strings.EqualFold(strings.TrimSpace(key), "column") {
+			return strings.TrimSpace(value), false
+		}
+	}
+	return "", false
+}
+
+// snakeCase converts a Go field name to snake case, keeping initialisms together: UserID becomes
+// user_id and HTTPStatus becomes http_status.
+func snakeCase(name string) string {
+	runes := []rune(name)
+
+	var sb strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
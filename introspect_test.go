@@ -0,0 +1,174 @@
+package where_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// schemaDB is a database/sql driver that answers every query with the given columns and records
+	// the last query and its arguments.
+	schemaDB struct {
+		columns []string
+		query   string
+		args    []driver.NamedValue
+	}
+
+	schemaConn struct{ db *schemaDB }
+
+	schemaRows struct {
+		columns []string
+		next    int
+	}
+)
+
+func (d *schemaDB) Open(string) (driver.Conn, error) { return &schemaConn{db: d}, nil }
+
+func (c *schemaConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *schemaConn) Close() error                        { return nil }
+func (c *schemaConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+func (c *schemaConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.query, c.db.args = query, args
+	return &schemaRows{columns: c.db.columns}, nil
+}
+
+func (r *schemaRows) Columns() []string { return []string{"column_name"} }
+func (r *schemaRows) Close() error      { return nil }
+
+func (r *schemaRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.columns) {
+		return io.EOF
+	}
+	dest[0] = r.columns[r.next]
+	r.next++
+	return nil
+}
+
+func TestValidatorFromDB(t *testing.T) {
+	fake := &schemaDB{columns: []string{"id", "email", "created_at"}}
+	db := sql.OpenDB(connector{fake})
+	defer db.Close()
+
+	validator, err := where.ValidatorFromDB(context.Background(), db, "public", "users", where.WithSchemaDriver("postgres"))
+	require.NoError(t, err)
+	require.Contains(t, fake.query, "information_schema.columns")
+	require.Contains(t, fake.query, "$1")
+	require.Equal(t, "public", fake.args[0].Value)
+	require.Equal(t, "users", fake.args[1].Value)
+
+	for _, field := range []string{"id", "EMAIL", "users.created_at"} {
+		require.True(t, validator.IsFieldAllowed(field), field)
+	}
+	require.False(t, validator.IsFieldAllowed("password"))
+
+	_, err = where.ValidatorFromDB(context.Background(), db, "default", "events", where.WithSchemaDriver("clickhouse"))
+	require.NoError(t, err)
+	require.Contains(t, fake.query, "system.columns")
+}
+
+func TestValidatorFromDBErrors(t *testing.T) {
+	db := sql.OpenDB(connector{&schemaDB{}})
+	defer db.Close()
+
+	_, err := where.ValidatorFromDB(context.Background(), db, "public", "users")
+	require.ErrorContains(t, err, "cannot introspect database")
+
+	_, err = where.ValidatorFromDB(context.Background(), db, "public", "missing", where.WithSchemaDriver("mysql"))
+	require.EqualError(t, err, "table public.missing not found")
+}
+
+type connector struct{ db *schemaDB }
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return c.db.Open("") }
+func (c connector) Driver() driver.Driver                        { return c.db }
+
+type (
+	timestamps struct {
+		CreatedAt string
+		UpdatedAt string
+	}
+
+	account struct {
+		timestamps
+		ID           int64
+		UserID       int64
+		HTTPStatus   int
+		Email        string `db:"email_address,omitempty"`
+		Name         string `gorm:"column:full_name;size:64"`
+		PasswordHash string `where:"-"`
+		Token        string `gorm:"-"`
+		Secret       string `db:"-"`
+		internal     string
+	}
+)
+
+func (account) TableName() string { return "accounts" }
+
+func TestValidatorFromModel(t *testing.T) {
+	validator, err := where.ValidatorFromModel(&account{internal: "x"})
+	require.NoError(t, err)
+
+	allowed := []string{
+		"created_at", "updated_at", "id", "user_id", "http_status", "email_address", "full_name",
+		"accounts.id", "accounts.full_name",
+	}
+	for _, field := range allowed {
+		require.True(t, validator.IsFieldAllowed(field), field)
+	}
+
+	for _, field := range []string{"email", "name", "password_hash", "token", "secret", "internal"} {
+		require.False(t, validator.IsFieldAllowed(field), field)
+	}
+
+	_, err = where.ValidatorFromModel("users")
+	require.EqualError(t, err, "model must be a struct, got string")
+}
+
+type (
+	audit struct {
+		CreatedAt string `json:"createdAt"`
+	}
+
+	order struct {
+		audit
+		ID       int64   `json:"id"`
+		Total    float64 `json:"total,omitempty" db:"total_cents"`
+		Customer string  `json:"customer" gorm:"column:customer_name"`
+		Status   string
+		Notes    string `json:"-"`
+		Secret   string `json:"secret" where:"-"`
+		internal string
+	}
+)
+
+func TestValidatorFromStruct(t *testing.T) {
+	validator, err := where.ValidatorFromStruct(&order{internal: "x"}, "json")
+	require.NoError(t, err)
+
+	for _, field := range []string{"createdAt", "id", "total", "customer", "Status"} {
+		require.True(t, validator.IsFieldAllowed(field), field)
+	}
+	for _, field := range []string{"created_at", "total_cents", "customer_name", "notes", "secret", "internal"} {
+		require.False(t, validator.IsFieldAllowed(field), field)
+	}
+
+	filter, err := where.Parse("createdAt > '2024-01-01' AND total < 100 AND customer = 'acme' AND status = 'paid'")
+	require.NoError(t, err)
+
+	sql, _, err := filter.ToSQL("postgres", where.WithValidator(validator))
+	require.NoError(t, err)
+	require.Equal(t, "(created_at > $1 AND total_cents < $2 AND customer_name = $3 AND status = $4)", sql)
+
+	_, err = where.ValidatorFromStruct("orders", "json")
+	require.EqualError(t, err, "model must be a struct, got string")
+
+	_, err = where.ValidatorFromStruct(order{}, "")
+	require.EqualError(t, err, "struct tag is empty")
+}
@@ -0,0 +1,363 @@
+package where
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// kqlReserved holds the characters that end an unquoted KQL field or value.
+const kqlReserved = `():<>"`
+
+type (
+	// kqlParser is a recursive descent parser for KQL-style expressions, producing structured filter
+	// nodes. It shares the cursor helpers of rsqlParser.
+	kqlParser struct {
+		rsqlParser
+	}
+
+	// kqlValue is a value of a KQL condition. pattern holds the LIKE pattern of an unquoted value
+	// with * wildcards.
+	kqlValue struct {
+		text    string
+		quoted  bool
+		pattern string
+	}
+
+	// kqlCondition parses the values of a field:value condition.
+	kqlCondition struct {
+		kp    *kqlParser
+		field string
+		path  string
+	}
+)
+
+// ParseKQL parses a Kibana Query Language (KQL) style expression, as typed into log search UIs, such
+// as status:active and age>18, into the same AST that Parse returns for the equivalent expression.
+// The filter is validated according to the parser's configured options.
+//
+// A condition is field:value for equality, or field>value, field>=value, field<value, or
+// field<=value. Conditions are combined with and, or, and not, which are case-insensitive, where
+// and binds tighter and parentheses group. An unquoted value containing * matches it as a wildcard
+// using LIKE, and field:* matches any non-null value. field:(a or b) matches any of the values and
+// field:(a and b) all of them. Unquoted numbers, true, and false are bound as such; quote values
+// with " to compare strings and to match * literally. \ escapes a character in unquoted values.
+//
+// Example:
+//
+//	filter, err := parser.ParseKQL("status:active and age>18 and name:*john*")
+//	// same as parser.Parse("status = 'active' AND age > 18 AND name LIKE '%john%'")
+func (p *Parser) ParseKQL(input string) (*Filter, error) {
+	filter, err := p.parseKQL(input)
+	if err != nil && p.opts.onReject != nil {
+		meta, _ := rejectionMeta(err)
+		p.opts.onReject(input, err, meta)
+	}
+	return filter, err
+}
+
+func (p *Parser) parseKQL(input string) (*Filter, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, newMessage(MsgEmptyFilter)
+	}
+
+	if err := p.precheck(input); err != nil {
+		return nil, errors.Wrapf(err, "filter validation failed")
+	}
+
+	kp := &kqlParser{rsqlParser{input: input}}
+	node, err := kp.or()
+	if err == nil && kp.skipSpace() < len(input) {
+		err = kp.errorf("unexpected %q", input[kp.pos:kp.pos+1])
+	}
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+
+	jb := &jsonBuilder{escapes: p.opts.escapes}
+	expr, err := jb.expression(node, "")
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+
+	filter := &Filter{Expression: expr}
+	if err := p.resolve(filter); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// ParseKQL is a convenience function that creates a default parser and parses the KQL-style
+// expression. See Parser.ParseKQL.
+func ParseKQL(input string) (*Filter, error) {
+	parser, err := NewParser()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create parser")
+	}
+	return parser.ParseKQL(input)
+}
+
+// or parses conditions and groups joined with or.
+func (kp *kqlParser) or() (*jsonNode, error) {
+	return kp.group("or", kp.and)
+}
+
+// and parses conditions and groups joined with and.
+func (kp *kqlParser) and() (*jsonNode, error) {
+	return kp.group("and", kp.not)
+}
+
+// not parses a negated or plain condition or group.
+func (kp *kqlParser) not() (*jsonNode, error) {
+	return kp.negation(kp.not, kp.primary)
+}
+
+// primary parses a parenthesized group or a condition.
+func (kp *kqlParser) primary() (*jsonNode, error) {
+	if kp.consume("(") {
+		return kp.closing(kp.or)
+	}
+
+	start := kp.skipSpace()
+	field := kp.word()
+	if field == "" {
+		return nil, kp.errorf("expected field")
+	}
+
+	kp.skipSpace()
+	op := ""
+	for _, symbol := range []string{":", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(kp.input[kp.pos:], symbol) {
+			kp.pos += len(symbol)
+			op = symbol
+			break
+		}
+	}
+	if op == "" {
+		return nil, kp.errorf("expected : or a comparison after %s", field)
+	}
+
+	c := &kqlCondition{kp: kp, field: field, path: fmt.Sprintf("%s%s at position %d", field, op, start+1)}
+	if op != ":" {
+		val, err := kp.value()
+		if err != nil {
+			return nil, err
+		}
+		if val.pattern != "" {
+			return nil, errors.Errorf("%s: wildcards require :", c.path)
+		}
+		return c.node(op, val), nil
+	}
+	return c.values()
+}
+
+// group parses items joined by the keyword, combining several into a group.
+func (kp *kqlParser) group(keyword string, item func() (*jsonNode, error)) (*jsonNode, error) {
+	var nodes []*jsonNode
+	for {
+		node, err := item()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+
+		if !kp.keyword(keyword) {
+			break
+		}
+	}
+
+	switch {
+	case len(nodes) == 1:
+		return nodes[0], nil
+	case keyword == "and":
+		return &jsonNode{And: nodes}, nil
+	default:
+		return &jsonNode{Or: nodes}, nil
+	}
+}
+
+// negation parses not followed by a negated item, or a plain item.
+func (kp *kqlParser) negation(negated, item func() (*jsonNode, error)) (*jsonNode, error) {
+	if !kp.keyword("not") {
+		return item()
+	}
+
+	node, err := negated()
+	if err != nil {
+		return nil, err
+	}
+	return &jsonNode{Not: node}, nil
+}
+
+// closing parses an item followed by ), after a ( was consumed.
+func (kp *kqlParser) closing(item func() (*jsonNode, error)) (*jsonNode, error) {
+	node, err := item()
+	if err != nil {
+		return nil, err
+	}
+	if !kp.consume(")") {
+		return nil, kp.errorf("expected )")
+	}
+	return node, nil
+}
+
+// value parses a quoted or unquoted value. Quoted values may escape characters with \, and unquoted
+// values may escape reserved characters, whitespace, and * with \.
+func (kp *kqlParser) value() (kqlValue, error) {
+	start := kp.skipSpace()
+	if start == len(kp.input) {
+		return kqlValue{}, kp.errorf("expected value")
+	}
+
+	if kp.input[start] == '"' {
+		var sb strings.Builder
+		for kp.pos++; kp.pos < len(kp.input); kp.pos++ {
+			switch c := kp.input[kp.pos]; {
+			case c == '"':
+				kp.pos++
+				return kqlValue{text: sb.String(), quoted: true}, nil
+			case c == '\\' && kp.pos+1 < len(kp.input):
+				kp.pos++
+				sb.WriteByte(kp.input[kp.pos])
+			default:
+				sb.WriteByte(c)
+			}
+		}
+		kp.pos = start
+		return kqlValue{}, kp.errorf("unterminated string")
+	}
+
+	var text, pattern strings.Builder
+	wildcard := false
+	for ; kp.pos < len(kp.input); kp.pos++ {
+		c := kp.input[kp.pos]
+		if strings.IndexByte(kqlReserved, c) >= 0 || unicode.IsSpace(rune(c)) {
+			break
+		}
+		switch {
+		case c == '\\' && kp.pos+1 < len(kp.input):
+			kp.pos++
+			c = kp.input[kp.pos]
+			pattern.WriteString(likeEscaper.Replace(string(c)))
+		case c == '*':
+			wildcard = true
+			pattern.WriteByte('%')
+			continue
+		default:
+			pattern.WriteString(likeEscaper.Replace(string(c)))
+		}
+		text.WriteByte(c)
+	}
+	if kp.pos == start {
+		return kqlValue{}, kp.errorf("expected value")
+	}
+
+	val := kqlValue{text: text.String()}
+	if wildcard {
+		val.pattern = pattern.String()
+	}
+	return val, nil
+}
+
+// word reads an unquoted field name.
+func (kp *kqlParser) word() string {
+	start := kp.pos
+	for kp.pos < len(kp.input) && strings.IndexByte(kqlReserved, kp.input[kp.pos]) < 0 &&
+		!unicode.IsSpace(rune(kp.input[kp.pos])) {
+		kp.pos++
+	}
+	return kp.input[start:kp.pos]
+}
+
+// keyword consumes the case-insensitive keyword and, or, or not, which must be followed by
+// whitespace or, for not, a parenthesis.
+func (kp *kqlParser) keyword(word string) bool {
+	start := kp.pos
+	end := kp.skipSpace() + len(word)
+	if end < len(kp.input) && strings.EqualFold(kp.input[kp.pos:end], word) &&
+		(unicode.IsSpace(rune(kp.input[end])) || (word == "not" && kp.input[end] == '(')) {
+		kp.pos = end
+		return true
+	}
+	kp.pos = start
+	return false
+}
+
+// values parses a single value or a parenthesized group of values joined with and, or, and not.
+// A group of values joined only with or becomes an IN condition.
+func (c *kqlCondition) values() (*jsonNode, error) {
+	if !c.kp.consume("(") {
+		return c.item()
+	}
+
+	node, err := c.kp.closing(c.or)
+	if err != nil {
+		return nil, err
+	}
+	return kqlIn(node), nil
+}
+
+func (c *kqlCondition) or() (*jsonNode, error) {
+	return c.kp.group("or", c.and)
+}
+
+func (c *kqlCondition) and() (*jsonNode, error) {
+	return c.kp.group("and", c.not)
+}
+
+func (c *kqlCondition) not() (*jsonNode, error) {
+	return c.kp.negation(c.not, c.primary)
+}
+
+// primary parses a nested group of values or a single value.
+func (c *kqlCondition) primary() (*jsonNode, error) {
+	if c.kp.consume("(") {
+		return c.kp.closing(c.or)
+	}
+	return c.item()
+}
+
+// item parses a value, matching * with IS NOT NULL and wildcards with LIKE.
+func (c *kqlCondition) item() (*jsonNode, error) {
+	val, err := c.kp.value()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case val.pattern == "%":
+		return &jsonNode{Field: c.field, Op: "is not null", path: c.path}, nil
+	case val.pattern != "":
+		raw, _ := json.Marshal(val.pattern)
+		return &jsonNode{Field: c.field, Op: "like", Value: raw, path: c.path}, nil
+	default:
+		return c.node("=", val), nil
+	}
+}
+
+// node returns the node comparing the field to the value. KQL has no null literal, so an unquoted
+// null is a string.
+func (c *kqlCondition) node(op string, val kqlValue) *jsonNode {
+	arg := rsqlArg{text: val.text, quoted: val.quoted || strings.EqualFold(val.text, "null")}
+	return &jsonNode{Field: c.field, Op: op, Value: arg.value(), path: c.path}
+}
+
+// kqlIn turns an or group of equality conditions into an IN condition.
+func kqlIn(node *jsonNode) *jsonNode {
+	if node.Or == nil {
+		return node
+	}
+
+	values := make([]json.RawMessage, len(node.Or))
+	for i, child := range node.Or {
+		if child.Op != "=" {
+			return node
+		}
+		values[i] = child.Value
+	}
+	raw, _ := json.Marshal(values)
+	return &jsonNode{Field: node.Or[0].Field, Op: "in", Value: raw, path: node.Or[0].path}
+}
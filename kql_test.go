@@ -0,0 +1,150 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKQL(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "and",
+			input: "status:active AND age>18",
+			want:  "status = 'active' AND age > 18",
+		},
+		{
+			name:  "ranges",
+			input: "a < 1 and b<=2.5 and c >-3 and d>=4",
+			want:  "a < 1 AND b <= 2.5 AND c > -3 AND d >= 4",
+		},
+		{
+			name:  "or binds looser than and",
+			input: "a:1 or b:2 and c:3",
+			want:  "a = 1 OR b = 2 AND c = 3",
+		},
+		{
+			name:  "not and groups",
+			input: "not status:closed and (user.role: admin or NOT(age<18))",
+			want:  "NOT status = 'closed' AND (user.role = 'admin' OR NOT age < 18)",
+		},
+		{
+			name:  "wildcards",
+			input: `name:*john* and email:*@example_test.com and code:a\*b`,
+			want:  `name LIKE '%john%' AND email LIKE '%@example\\_test.com' AND code = 'a*b'`,
+		},
+		{
+			name:  "exists",
+			input: "deleted_at:* or not email:*",
+			want:  "deleted_at IS NOT NULL OR NOT email IS NOT NULL",
+		},
+		{
+			name:  "value groups",
+			input: "status:(active or pending) and tags:(a and not b)",
+			want:  "status IN ('active', 'pending') AND (tags = 'a' AND NOT tags = 'b')",
+		},
+		{
+			name:  "mixed value group",
+			input: "name:(jo* or jane)",
+			want:  "name LIKE 'jo%' OR name = 'jane'",
+		},
+		{
+			name:  "quoted values",
+			input: `message:"disk full *" and code:"42" and flag:true and note:null and path:C\:\\tmp`,
+			want:  `message = 'disk full *' AND code = '42' AND flag = TRUE AND note = 'null' AND path = 'C:\\tmp'`,
+		},
+		{
+			name:  "keywords inside values",
+			input: "brand:android or nothing:x",
+			want:  "brand = 'android' OR nothing = 'x'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.ParseKQL(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, filter.String())
+
+			want, err := where.Parse(tt.want)
+			require.NoError(t, err)
+			requireEqualAST(t, want.Expression, filter.Expression)
+		})
+	}
+}
+
+func TestParseKQLWildcardParams(t *testing.T) {
+	filter, err := where.ParseKQL(`name:*100%_off*`)
+	require.NoError(t, err)
+
+	sql, params, err := filter.ToSQL("mysql")
+	require.NoError(t, err)
+	require.Equal(t, "name LIKE ?", sql)
+	require.Equal(t, []any{`%100\%\_off%`}, params)
+}
+
+func TestParseKQLErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "empty",
+			input: "  ",
+			err:   "empty filter expression",
+		},
+		{
+			name:  "free text",
+			input: "error",
+			err:   "failed to parse filter expression: expected : or a comparison after error at position 6",
+		},
+		{
+			name:  "missing value",
+			input: "a:1 and b:",
+			err:   "failed to parse filter expression: expected value at position 11",
+		},
+		{
+			name:  "missing operator keyword",
+			input: "a:1 b:2",
+			err:   `failed to parse filter expression: unexpected "b" at position 5`,
+		},
+		{
+			name:  "unterminated string",
+			input: `a:"x`,
+			err:   "failed to parse filter expression: unterminated string at position 3",
+		},
+		{
+			name:  "unbalanced parentheses",
+			input: "(a:1 and b:2",
+			err:   "failed to parse filter expression: expected ) at position 13",
+		},
+		{
+			name:  "wildcard in range",
+			input: "a>1*",
+			err:   "failed to parse filter expression: a> at position 1: wildcards require :",
+		},
+		{
+			name:  "invalid field",
+			input: "b:1 and a-b:1",
+			err:   `failed to parse filter expression: a-b: at position 9: invalid field "a-b"`,
+		},
+		{
+			name:  "validated like parsed filters",
+			input: "((((((((((((((((a:1))))))))))))))))",
+			err:   "filter validation failed: expression depth exceeds maximum of 10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := where.ParseKQL(tt.input)
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
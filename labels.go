@@ -0,0 +1,219 @@
+package where
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// labelNamePattern matches Prometheus label names.
+	labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*`)
+
+	// metricNamePattern matches Prometheus metric names, which may also contain colons.
+	metricNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*`)
+)
+
+type (
+	// labelParser parses Prometheus series selectors, producing the AST directly. It shares the
+	// cursor helpers of rsqlParser.
+	labelParser struct {
+		rsqlParser
+		escapes EscapeMode
+	}
+)
+
+// ParseLabelMatchers parses a Prometheus-style series selector, such as {env="prod", status=~"5.."},
+// into the same AST that Parse returns for the equivalent expression, so observability UIs using
+// label matcher syntax can query labels stored as columns. The filter is validated according to the
+// parser's configured options.
+//
+// The matchers are ANDed. = and != compare a label with a value, and =~ and !~ match it against a
+// regular expression, which is anchored at both ends as in Prometheus and built with the REGEXP
+// function, so WithFunctions must allow REGEXP if it is set. A metric name before the braces
+// matches the __name__ label. Values are quoted with ", ', or `, using Go escape sequences in the
+// first two.
+//
+// Example:
+//
+//	filter, err := parser.ParseLabelMatchers(`{env="prod", status=~"5.."}`)
+//	// same as parser.Parse("env = 'prod' AND REGEXP(status, '^(?:5..)$')")
+func (p *Parser) ParseLabelMatchers(input string) (*Filter, error) {
+	filter, err := p.parseLabelMatchers(input)
+	if err != nil && p.opts.onReject != nil {
+		meta, _ := rejectionMeta(err)
+		p.opts.onReject(input, err, meta)
+	}
+	return filter, err
+}
+
+func (p *Parser) parseLabelMatchers(input string) (*Filter, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, newMessage(MsgEmptyFilter)
+	}
+
+	if err := p.precheck(input); err != nil {
+		return nil, errors.Wrapf(err, "filter validation failed")
+	}
+
+	lp := &labelParser{rsqlParser: rsqlParser{input: input}, escapes: p.opts.escapes}
+	term, err := lp.selector()
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+
+	filter := &Filter{Expression: &Expression{Or: []*Term{term}}}
+	if err := p.resolve(filter); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// ParseLabelMatchers is a convenience function that creates a default parser and parses the
+// Prometheus-style series selector. See Parser.ParseLabelMatchers.
+func ParseLabelMatchers(input string) (*Filter, error) {
+	parser, err := NewParser()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create parser")
+	}
+	return parser.ParseLabelMatchers(input)
+}
+
+// selector parses an optional metric name followed by an optional list of matchers in braces.
+func (lp *labelParser) selector() (*Term, error) {
+	term := &Term{}
+
+	lp.skipSpace()
+	if name := metricNamePattern.FindString(lp.input[lp.pos:]); name != "" {
+		lp.pos += len(name)
+		term.And = append(term.And, lp.compare("__name__", "=", name))
+	}
+
+	if lp.consume("{") {
+		for !lp.consume("}") {
+			factor, err := lp.matcher()
+			if err != nil {
+				return nil, err
+			}
+			term.And = append(term.And, factor)
+
+			if !lp.consume(",") {
+				if !lp.consume("}") {
+					return nil, lp.errorf("expected , or }")
+				}
+				break
+			}
+		}
+	}
+
+	if lp.skipSpace() < len(lp.input) {
+		return nil, lp.errorf("unexpected %q", lp.input[lp.pos:lp.pos+1])
+	}
+	if len(term.And) == 0 {
+		return nil, errors.New("selector has no matchers")
+	}
+	return term, nil
+}
+
+// matcher parses label op "value".
+func (lp *labelParser) matcher() (*Factor, error) {
+	lp.skipSpace()
+	name := labelNamePattern.FindString(lp.input[lp.pos:])
+	if name == "" {
+		return nil, lp.errorf("expected label name")
+	}
+	lp.pos += len(name)
+
+	lp.skipSpace()
+	op := ""
+	for _, symbol := range []string{"=~", "!~", "!=", "="} {
+		if strings.HasPrefix(lp.input[lp.pos:], symbol) {
+			lp.pos += len(symbol)
+			op = symbol
+			break
+		}
+	}
+	if op == "" {
+		return nil, lp.errorf("expected =, !=, =~, or !~")
+	}
+
+	start := lp.skipSpace()
+	value, err := lp.value()
+	if err != nil {
+		return nil, err
+	}
+
+	if op == "=" || op == "!=" {
+		return lp.compare(name, op, value), nil
+	}
+
+	pattern := "^(?:" + value + ")$"
+	if _, err := regexp.Compile(pattern); err != nil {
+		lp.pos = start
+		return nil, lp.errorf("invalid regular expression %q", value)
+	}
+	quoted := lp.escapes.quote(pattern)
+	return &Factor{
+		Not: op == "!~",
+		Predicate: &Predicate{Left: &Value{Function: &FunctionCall{
+			Name: "REGEXP",
+			Args: []*Value{{Field: &FieldRef{Parts: []string{name}}}, {Literal: &LiteralValue{String: &quoted}}},
+		}}},
+	}, nil
+}
+
+// value parses a quoted value. Double and single quoted values use Go escape sequences, and
+// backquoted values are raw.
+func (lp *labelParser) value() (string, error) {
+	start := lp.pos
+	if start == len(lp.input) || !strings.ContainsRune("\"'`", rune(lp.input[start])) {
+		return "", lp.errorf("expected quoted value")
+	}
+
+	quote := lp.input[start]
+	end := start + 1
+	for end < len(lp.input) && lp.input[end] != quote {
+		if lp.input[end] == '\\' && quote != '`' {
+			end++
+		}
+		end++
+	}
+	if end >= len(lp.input) {
+		return "", lp.errorf("unterminated string")
+	}
+
+	var sb strings.Builder
+	for s := lp.input[start+1 : end]; s != ""; {
+		if quote == '`' {
+			sb.WriteString(s)
+			break
+		}
+		r, multibyte, tail, err := strconv.UnquoteChar(s, quote)
+		if err != nil {
+			return "", lp.errorf("invalid escape sequence")
+		}
+		if multibyte || r < 0x80 {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte(byte(r))
+		}
+		s = tail
+	}
+
+	lp.pos = end + 1
+	return sb.String(), nil
+}
+
+// compare returns the factor comparing the label with the value.
+func (lp *labelParser) compare(name, op, value string) *Factor {
+	quoted := lp.escapes.quote(value)
+	return &Factor{Predicate: &Predicate{
+		Left: &Value{Field: &FieldRef{Parts: []string{name}}},
+		Operation: &Operation{Compare: &CompareOp{
+			Operator: CompareOperator{Type: op},
+			Right:    &Value{Literal: &LiteralValue{String: &quoted}},
+		}},
+	}}
+}
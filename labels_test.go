@@ -0,0 +1,134 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	_ "github.com/pseudomuto/where/drivers/clickhouse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLabelMatchers(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "equality",
+			input: `{env="prod"}`,
+			want:  "env = 'prod'",
+		},
+		{
+			name:  "all operators",
+			input: `{env = "prod", job!="api", status=~"5..", path !~ "/health|/ready"}`,
+			want:  `env = 'prod' AND job != 'api' AND REGEXP(status, '^(?:5..)$') AND NOT REGEXP(path, '^(?:/health|/ready)$')`,
+		},
+		{
+			name:  "metric name",
+			input: `http_requests_total{method="GET",}`,
+			want:  "__name__ = 'http_requests_total' AND method = 'GET'",
+		},
+		{
+			name:  "metric name only",
+			input: "node:cpu_seconds:rate5m",
+			want:  "__name__ = 'node:cpu_seconds:rate5m'",
+		},
+		{
+			name:  "quoting",
+			input: `{a='it\'s', b="say \"hi\" \u00e9", c=~` + "`\\d+`" + `}`,
+			want:  `a = 'it''s' AND b = 'say "hi" é' AND REGEXP(c, '^(?:\\d+)$')`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.ParseLabelMatchers(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, filter.String())
+
+			want, err := where.Parse(tt.want)
+			require.NoError(t, err)
+			requireEqualAST(t, want.Expression, filter.Expression)
+		})
+	}
+}
+
+func TestParseLabelMatchersSQL(t *testing.T) {
+	filter, err := where.ParseLabelMatchers(`{env="prod", status=~"5.."}`)
+	require.NoError(t, err)
+
+	sql, params, err := filter.ToSQL("clickhouse")
+	require.NoError(t, err)
+	require.Equal(t, "(env = ? AND match(status, ?))", sql)
+	require.Equal(t, []any{"prod", "^(?:5..)$"}, params)
+}
+
+func TestParseLabelMatchersErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "empty",
+			input: "  ",
+			err:   "empty filter expression",
+		},
+		{
+			name:  "no matchers",
+			input: "{}",
+			err:   "failed to parse filter expression: selector has no matchers",
+		},
+		{
+			name:  "missing label",
+			input: `{="x"}`,
+			err:   "failed to parse filter expression: expected label name at position 2",
+		},
+		{
+			name:  "unknown operator",
+			input: `{a>"x"}`,
+			err:   "failed to parse filter expression: expected =, !=, =~, or !~ at position 3",
+		},
+		{
+			name:  "unquoted value",
+			input: `{a=x}`,
+			err:   "failed to parse filter expression: expected quoted value at position 4",
+		},
+		{
+			name:  "unterminated string",
+			input: `{a="x}`,
+			err:   "failed to parse filter expression: unterminated string at position 4",
+		},
+		{
+			name:  "missing brace",
+			input: `{a="x" b="y"}`,
+			err:   "failed to parse filter expression: expected , or } at position 8",
+		},
+		{
+			name:  "invalid regex",
+			input: `{a=~"("}`,
+			err:   `failed to parse filter expression: invalid regular expression "(" at position 5`,
+		},
+		{
+			name:  "trailing input",
+			input: `{a="x"} or {b="y"}`,
+			err:   `failed to parse filter expression: unexpected "o" at position 9`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := where.ParseLabelMatchers(tt.input)
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestParseLabelMatchersFunctionAllowlist(t *testing.T) {
+	parser, err := where.NewParser(where.WithFunctions("LOWER"))
+	require.NoError(t, err)
+
+	_, err = parser.ParseLabelMatchers(`{a=~"x"}`)
+	require.ErrorContains(t, err, `function "REGEXP" is not allowed`)
+}
@@ -7,6 +7,12 @@ import (
 // NewLexer creates a new lexer for parsing SQL filter expressions.
 // The lexer supports case-insensitive keywords, quoted identifiers, and various operators.
 func NewLexer() (*lexer.StatefulDefinition, error) {
+	return newLexer(EscapeBackslash)
+}
+
+func newLexer(escapes EscapeMode) (*lexer.StatefulDefinition, error) {
+	singleQuoted, doubleQuoted := escapes.stringPatterns()
+
 	return lexer.NewSimple([]lexer.SimpleRule{
 		{Name: "Whitespace", Pattern: `\s+`},
 
@@ -22,6 +28,8 @@ func NewLexer() (*lexer.StatefulDefinition, error) {
 		{Name: "True", Pattern: `(?i)\bTRUE\b`},
 		{Name: "False", Pattern: `(?i)\bFALSE\b`},
 
+		{Name: "Bitwise", Pattern: `<<|>>|[&|^]`},
+		{Name: "NullSafeEqual", Pattern: `<=>`},
 		{Name: "NotEqual", Pattern: `!=|<>`},
 		{Name: "LessOrEqual", Pattern: `<=`},
 		{Name: "GreaterOrEqual", Pattern: `>=`},
@@ -29,19 +37,25 @@ func NewLexer() (*lexer.StatefulDefinition, error) {
 		{Name: "Less", Pattern: `<`},
 		{Name: "Greater", Pattern: `>`},
 
-		{Name: "String", Pattern: `'([^'\\]|\\.)*'`},
+		{Name: "String", Pattern: singleQuoted},
 
 		{Name: "BacktickIdent", Pattern: "`[^`]+`"},
 		{Name: "QuotedIdent", Pattern: `"[a-zA-Z_][a-zA-Z0-9_]*"`},
-		{Name: "DoubleQuotedString", Pattern: `"([^"\\]|\\.)*"`},
+		{Name: "DoubleQuotedString", Pattern: doubleQuoted},
 
-		{Name: "Number", Pattern: `[-+]?\d+(\.\d+)?([eE][-+]?\d+)?`},
+		{Name: "Hex", Pattern: `0[xX][0-9a-fA-F]+(_[0-9a-fA-F]+)*\b`},
+		{Name: "Number", Pattern: `0[oO][0-7]+(_[0-7]+)*\b|\d+(_\d+)*(\.\d+(_\d+)*)?([eE][-+]?\d+)?`},
 
 		{Name: "Ident", Pattern: `[a-zA-Z_][a-zA-Z0-9_]*`},
+		{Name: "Variable", Pattern: `:[a-zA-Z_][a-zA-Z0-9_]*`},
+		{Name: "Macro", Pattern: `@[a-zA-Z_][a-zA-Z0-9_]*`},
 
 		{Name: "Dot", Pattern: `\.`},
 		{Name: "LParen", Pattern: `\(`},
 		{Name: "RParen", Pattern: `\)`},
 		{Name: "Comma", Pattern: `,`},
+		{Name: "Minus", Pattern: `-`},
+		{Name: "Plus", Pattern: `\+`},
+		{Name: "Star", Pattern: `\*`},
 	})
 }
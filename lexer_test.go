@@ -3,6 +3,7 @@ package where_test
 import (
 	"testing"
 
+	"github.com/alecthomas/participle/v2/lexer"
 	"github.com/pseudomuto/where"
 	"github.com/stretchr/testify/require"
 )
@@ -55,6 +56,33 @@ func TestNewLexer(t *testing.T) {
 		}
 	})
 
+	t.Run("signs are separate tokens", func(t *testing.T) {
+		def, err := where.NewLexer()
+		require.NoError(t, err)
+		symbols := lexer.SymbolsByRune(def)
+
+		types := func(input string) []string {
+			lex, err := def.LexString("", input)
+			require.NoError(t, err)
+
+			tokens, err := lexer.ConsumeAll(lex)
+			require.NoError(t, err)
+
+			var names []string
+			for _, tok := range tokens {
+				if name := symbols[tok.Type]; name != "Whitespace" && name != "EOF" {
+					names = append(names, name)
+				}
+			}
+			return names
+		}
+
+		require.Equal(t, []string{"Ident", "Minus", "Number"}, types("a -1"))
+		require.Equal(t, types("a -1"), types("a - 1"))
+		require.Equal(t, []string{"Number"}, types("1.5e-10"))
+		require.Equal(t, []string{"Plus", "Number"}, types("+0o17"))
+	})
+
 	t.Run("lexer handles case insensitive keywords", func(t *testing.T) {
 		// Test that the lexer properly handles case insensitive keywords
 		// by verifying they can be parsed correctly
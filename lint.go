@@ -0,0 +1,174 @@
+package where
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Lint rule names reported in LintWarning.Rule.
+const (
+	LintConstantComparison = "constant-comparison"
+	LintSelfComparison     = "self-comparison"
+	LintDuplicateCondition = "duplicate-condition"
+	LintMatchAllPattern    = "match-all-pattern"
+	LintEmptyRange         = "empty-range"
+	LintNullComparison     = "null-comparison"
+)
+
+type (
+	// LintWarning describes a suspicious construct found in a filter expression.
+	LintWarning struct {
+		// Rule is the name of the rule that produced the warning.
+		Rule string
+
+		// Message is a human readable description of the problem.
+		Message string
+
+		// Expression is the offending part of the filter, formatted on a single line.
+		Expression string
+	}
+
+	// linter collects warnings while walking a filter.
+	linter struct {
+		fm       *formatter
+		warnings []LintWarning
+	}
+)
+
+// String returns the warning formatted as "rule: message (expression)".
+func (w LintWarning) String() string {
+	return fmt.Sprintf("%s: %s (%s)", w.Rule, w.Message, w.Expression)
+}
+
+// Lint parses the filter expression and reports suspicious constructs such as tautologies
+// (1 = 1), comparisons of a field with itself, comparisons with NULL (parent_id = NULL), duplicate
+// conditions, LIKE patterns that match everything, and empty BETWEEN ranges. An error is only
+// returned if the input fails to parse.
+func Lint(input string) ([]LintWarning, error) {
+	filter, err := Parse(input)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to lint filter")
+	}
+	return filter.Lint(), nil
+}
+
+// Lint reports suspicious constructs in the parsed filter. See the package level Lint function.
+func (f *Filter) Lint() []LintWarning {
+	if f == nil || f.Expression == nil {
+		return nil
+	}
+
+	l := &linter{fm: &formatter{opts: &formatOptions{compact: true}}}
+	l.expression(f.Expression)
+	return l.warnings
+}
+
+func (l *linter) warn(rule, message, expression string) {
+	l.warnings = append(l.warnings, LintWarning{Rule: rule, Message: message, Expression: expression})
+}
+
+func (l *linter) expression(expr *Expression) {
+	if expr == nil {
+		return
+	}
+
+	seen := make(map[string]bool, len(expr.Or))
+	for _, term := range expr.Or {
+		text := l.fm.term(term, "", false)
+		if seen[text] {
+			l.warn(LintDuplicateCondition, "condition is repeated in OR expression", text)
+		}
+		seen[text] = true
+
+		l.term(term)
+	}
+}
+
+func (l *linter) term(term *Term) {
+	if term == nil {
+		return
+	}
+
+	seen := make(map[string]bool, len(term.And))
+	for _, factor := range term.And {
+		text := l.fm.factor(factor, "")
+		if seen[text] {
+			l.warn(LintDuplicateCondition, "condition is repeated in AND expression", text)
+		}
+		seen[text] = true
+
+		if factor == nil {
+			continue
+		}
+		if factor.SubExpr != nil {
+			l.expression(factor.SubExpr)
+			continue
+		}
+		l.predicate(factor.Predicate)
+	}
+}
+
+func (l *linter) predicate(pred *Predicate) {
+	if pred == nil || pred.Operation == nil {
+		return
+	}
+
+	text := l.fm.predicate(pred)
+	op := pred.Operation
+
+	switch {
+	case op.Compare != nil:
+		right := op.Compare.Right
+		switch {
+		case op.Compare.Operator.String() != "<=>" && (isNullLiteral(pred.Left) || isNullLiteral(right)):
+			l.warn(LintNullComparison, "comparison with NULL is never true; use IS NULL or IS NOT NULL", text)
+		case isLiteral(pred.Left) && isLiteral(right):
+			l.warn(LintConstantComparison, "comparison between literals is always true or always false", text)
+		case pred.Left.Field != nil && right != nil && right.Field != nil &&
+			pred.Left.Field.String() == right.Field.String():
+			l.warn(LintSelfComparison, "field is compared with itself", text)
+		}
+	case op.Like != nil:
+		if s, ok := literalString(op.Like.Pattern); ok && isMatchAll(s) {
+			l.warn(LintMatchAllPattern, "pattern matches every non-NULL value", text)
+		}
+	case op.Between != nil:
+		lower, lok := literalNumber(op.Between.Lower)
+		upper, uok := literalNumber(op.Between.Upper)
+		if lok && uok && lower > upper {
+			l.warn(LintEmptyRange, "lower bound is greater than upper bound", text)
+		}
+	}
+}
+
+func isLiteral(val *Value) bool {
+	return val != nil && val.Literal != nil && !val.Literal.Null
+}
+
+func literalString(val *Value) (string, bool) {
+	if val == nil || val.Literal == nil {
+		return "", false
+	}
+	s, ok := val.Literal.Value().(string)
+	return s, ok
+}
+
+func literalNumber(val *Value) (float64, bool) {
+	if val == nil || val.Literal == nil || val.Literal.Number == nil {
+		return 0, false
+	}
+	return *val.Literal.Number, true
+}
+
+func isMatchAll(pattern string) bool {
+	if pattern == "" {
+		return false
+	}
+	for _, ch := range pattern {
+		if ch != '%' {
+			return false
+		}
+	}
+	return true
+}
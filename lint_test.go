@@ -0,0 +1,111 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []where.LintWarning
+	}{
+		{
+			name:  "clean filter",
+			input: "age > 18 AND status IN ('active', 'pending')",
+		},
+		{
+			name:  "tautology",
+			input: "age > 18 OR 1 = 1",
+			want: []where.LintWarning{{
+				Rule:       where.LintConstantComparison,
+				Message:    "comparison between literals is always true or always false",
+				Expression: "1 = 1",
+			}},
+		},
+		{
+			name:  "self comparison",
+			input: "status = status",
+			want: []where.LintWarning{{
+				Rule:       where.LintSelfComparison,
+				Message:    "field is compared with itself",
+				Expression: "status = status",
+			}},
+		},
+		{
+			name:  "duplicate AND condition",
+			input: "age > 18 AND name = 'x' AND age > 18",
+			want: []where.LintWarning{{
+				Rule:       where.LintDuplicateCondition,
+				Message:    "condition is repeated in AND expression",
+				Expression: "age > 18",
+			}},
+		},
+		{
+			name:  "duplicate OR condition in group",
+			input: "a = 1 AND (b = 1 OR b=1)",
+			want: []where.LintWarning{{
+				Rule:       where.LintDuplicateCondition,
+				Message:    "condition is repeated in OR expression",
+				Expression: "b = 1",
+			}},
+		},
+		{
+			name:  "match all pattern",
+			input: "name LIKE '%%'",
+			want: []where.LintWarning{{
+				Rule:       where.LintMatchAllPattern,
+				Message:    "pattern matches every non-NULL value",
+				Expression: "name LIKE '%%'",
+			}},
+		},
+		{
+			name:  "empty range",
+			input: "age BETWEEN 65 AND 18",
+			want: []where.LintWarning{{
+				Rule:       where.LintEmptyRange,
+				Message:    "lower bound is greater than upper bound",
+				Expression: "age BETWEEN 65 AND 18",
+			}},
+		},
+		{
+			name:  "NULL comparison",
+			input: "parent_id = NULL OR NULL != deleted_at OR manager_id <=> NULL",
+			want: []where.LintWarning{
+				{
+					Rule:       where.LintNullComparison,
+					Message:    "comparison with NULL is never true; use IS NULL or IS NOT NULL",
+					Expression: "parent_id = NULL",
+				},
+				{
+					Rule:       where.LintNullComparison,
+					Message:    "comparison with NULL is never true; use IS NULL or IS NOT NULL",
+					Expression: "NULL != deleted_at",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := where.Lint(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, warnings)
+		})
+	}
+
+	t.Run("parse error", func(t *testing.T) {
+		_, err := where.Lint("age >")
+		require.Error(t, err)
+	})
+
+	t.Run("String", func(t *testing.T) {
+		warnings, err := where.Lint("1 = 1")
+		require.NoError(t, err)
+		require.Len(t, warnings, 1)
+		require.Equal(t, "constant-comparison: comparison between literals is always true or always false (1 = 1)", warnings[0].String())
+	})
+}
@@ -0,0 +1,179 @@
+package where
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WithMacros returns a ParserOption that registers named macros, which filters reference as @name.
+// Each macro is a filter expression that is expanded in place, in parentheses, when a filter is
+// parsed, so the resulting Filter contains no macro references. Macros may reference other macros.
+// Names are case-insensitive and may be given with or without the @. NewParser fails if a macro
+// cannot be parsed, references an unknown macro, or references itself directly or indirectly.
+//
+// Example:
+//
+//	parser, _ := where.NewParser(where.WithMacros(map[string]string{
+//		"active":   "status = 'active' AND deleted_at IS NULL",
+//		"customer": "@active AND role = 'customer'",
+//	}))
+//	filter, _ := parser.Parse("@customer AND age > 18")
+//	// (status = 'active' AND deleted_at IS NULL AND role = 'customer') AND age > 18
+func WithMacros(macros map[string]string) ParserOption {
+	return func(o *parserOptions) {
+		if o.macros == nil {
+			o.macros = make(map[string]string, len(macros))
+		}
+		for name, body := range macros {
+			o.macros[macroName(name)] = body
+		}
+	}
+}
+
+// macroName normalizes a macro name or reference such as @Active to active.
+func macroName(name string) string {
+	return strings.ToLower(strings.TrimPrefix(name, "@"))
+}
+
+// compileMacros parses the registered macros and expands the macros they reference.
+func (p *Parser) compileMacros() error {
+	parsed := make(map[string]*Expression, len(p.opts.macros))
+	for name, body := range p.opts.macros {
+		filter, err := p.parseString(body)
+		if err != nil {
+			return fmt.Errorf("failed to parse macro @%s: %w", name, err)
+		}
+		clearPositions(filter.Expression)
+		parsed[name] = filter.Expression
+	}
+
+	names := make([]string, 0, len(parsed))
+	for name := range parsed {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	p.macros = make(map[string]*Expression, len(parsed))
+	for _, name := range names {
+		if err := p.compileMacro(name, parsed, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compileMacro expands the macros referenced by the named macro, with stack holding the macros
+// being expanded to detect cycles.
+func (p *Parser) compileMacro(name string, parsed map[string]*Expression, stack []string) error {
+	if _, ok := p.macros[name]; ok {
+		return nil
+	}
+	if slices.Contains(stack, name) {
+		return fmt.Errorf("macro @%s references itself: @%s -> @%s", name, strings.Join(stack, " -> @"), name)
+	}
+
+	stack = append(stack, name)
+	expr := parsed[name]
+	err := walkFactors(expr, func(factor *Factor) error {
+		if factor.Macro == nil {
+			return nil
+		}
+
+		ref := macroName(*factor.Macro)
+		if _, ok := parsed[ref]; !ok {
+			return fmt.Errorf("macro @%s references unknown macro @%s", name, ref)
+		}
+		if err := p.compileMacro(ref, parsed, stack); err != nil {
+			return err
+		}
+		factor.SubExpr, factor.Macro = p.macros[ref].DeepCopy(), nil
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	p.macros[name] = expr
+	return nil
+}
+
+// expandMacros replaces the macro references in the expression with copies of the macros.
+func (p *Parser) expandMacros(expr *Expression) error {
+	return walkFactors(expr, func(factor *Factor) error {
+		if factor.Macro == nil {
+			return nil
+		}
+
+		macro, ok := p.macros[macroName(*factor.Macro)]
+		if !ok {
+			return newMessage(MsgUnknownMacro, "macro", *factor.Macro)
+		}
+		factor.SubExpr, factor.Macro = macro.DeepCopy(), nil
+		return nil
+	})
+}
+
+// walkFactors calls fn for every factor in the expression, including factors within parenthesized
+// values and function arguments. Factors are visited before the expressions nested in them.
+func walkFactors(expr *Expression, fn func(*Factor) error) error {
+	if expr == nil {
+		return nil
+	}
+
+	for _, term := range expr.Or {
+		if term == nil {
+			continue
+		}
+		for _, factor := range term.And {
+			if factor == nil {
+				continue
+			}
+			if err := fn(factor); err != nil {
+				return err
+			}
+			if err := walkFactors(factor.SubExpr, fn); err != nil {
+				return err
+			}
+			if factor.Predicate == nil {
+				continue
+			}
+
+			values := []*Value{factor.Predicate.Left}
+			if factor.Predicate.Operation != nil {
+				values = append(values, factor.Predicate.Operation.operands()...)
+			}
+			for _, val := range values {
+				if err := walkValueFactors(val, fn); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func walkValueFactors(val *Value, fn func(*Factor) error) error {
+	if val == nil {
+		return nil
+	}
+
+	if val.Function != nil {
+		for _, arg := range val.Function.arguments() {
+			if err := walkValueFactors(arg, fn); err != nil {
+				return err
+			}
+		}
+	}
+	if val.Bitwise != nil {
+		if err := walkValueFactors(val.Bitwise.Left, fn); err != nil {
+			return err
+		}
+		for _, op := range val.Bitwise.Ops {
+			if err := walkValueFactors(op.Right, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return walkFactors(val.SubExpr, fn)
+}
@@ -0,0 +1,119 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacros(t *testing.T) {
+	parser, err := where.NewParser(where.WithMacros(map[string]string{
+		"active":    "status = 'active' AND deleted_at IS NULL",
+		"@Customer": "@active AND role = 'customer'",
+		"adult":     "age >= 18",
+	}))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		input      string
+		wantFormat string
+		wantSQL    string
+		wantErr    string
+	}{
+		{
+			name:       "single macro",
+			input:      "@active",
+			wantFormat: "(status = 'active' AND deleted_at IS NULL)",
+			wantSQL:    "(status = $1 AND deleted_at IS NULL)",
+		},
+		{
+			name:       "nested macros and case-insensitive names",
+			input:      "@CUSTOMER OR @adult",
+			wantFormat: "((status = 'active' AND deleted_at IS NULL) AND role = 'customer') OR (age >= 18)",
+			wantSQL:    "(((status = $1 AND deleted_at IS NULL) AND role = $2) OR age >= $3)",
+		},
+		{
+			name:       "negated macro",
+			input:      "NOT @adult AND name = 'x'",
+			wantFormat: "NOT (age >= 18) AND name = 'x'",
+			wantSQL:    "(NOT (age >= $1) AND name = $2)",
+		},
+		{
+			name:    "unknown macro",
+			input:   "@missing AND age > 1",
+			wantErr: "unknown macro @missing",
+		},
+		{
+			name:    "macro as a value",
+			input:   "status = @active",
+			wantErr: "failed to parse filter expression",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := parser.Parse(tt.input)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantFormat, filter.String())
+
+			sql, _, err := filter.ToSQL("postgres")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+		})
+	}
+}
+
+func TestMacrosAreCopied(t *testing.T) {
+	parser, err := where.NewParser(where.WithMacros(map[string]string{"adult": "age >= 18"}))
+	require.NoError(t, err)
+
+	first, err := parser.Parse("@adult")
+	require.NoError(t, err)
+	*first.Expression.Or[0].And[0].SubExpr.Or[0].And[0].Predicate.Left.Field = where.FieldRef{Parts: []string{"x"}}
+
+	second, err := parser.Parse("@adult")
+	require.NoError(t, err)
+	require.Equal(t, "(age >= 18)", second.String())
+}
+
+func TestMacroErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		macros  map[string]string
+		wantErr string
+	}{
+		{
+			name:    "direct cycle",
+			macros:  map[string]string{"a": "x = 1 OR @a"},
+			wantErr: "macro @a references itself: @a -> @a",
+		},
+		{
+			name:    "indirect cycle",
+			macros:  map[string]string{"a": "@b", "b": "@c AND y = 1", "c": "NOT @a"},
+			wantErr: "macro @a references itself: @a -> @b -> @c -> @a",
+		},
+		{
+			name:    "unknown reference",
+			macros:  map[string]string{"a": "@b"},
+			wantErr: "macro @a references unknown macro @b",
+		},
+		{
+			name:    "invalid body",
+			macros:  map[string]string{"a": "x = "},
+			wantErr: "failed to parse macro @a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := where.NewParser(where.WithMacros(tt.macros))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package where
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/pkg/errors"
+)
+
+// MessageKey identifies a user-facing error message in a Catalog.
+type MessageKey string
+
+// Keys of the parse and validation errors that are meant to be shown to end users. Messages holds the
+// English template for each key, and the placeholders that key's template may use.
+const (
+	MsgEmptyFilter          MessageKey = "empty_filter"
+	MsgSyntax               MessageKey = "syntax"
+	MsgParseTimeout         MessageKey = "parse_timeout"
+	MsgInputLength          MessageKey = "input_length"
+	MsgDepth                MessageKey = "depth"
+	MsgComplexity           MessageKey = "complexity"
+	MsgINEmpty              MessageKey = "in_empty"
+	MsgINItems              MessageKey = "in_items"
+	MsgORBranches           MessageKey = "or_branches"
+	MsgNegations            MessageKey = "negations"
+	MsgFieldNotAllowed      MessageKey = "field_not_allowed"
+	MsgFunctionNotAllowed   MessageKey = "function_not_allowed"
+	MsgFunctionNotSupported MessageKey = "function_not_supported"
+	MsgFunctionArgs         MessageKey = "function_args"
+	MsgFunctionArgType      MessageKey = "function_arg_type"
+	MsgOperatorNotSupported MessageKey = "operator_not_supported"
+	MsgCastAs               MessageKey = "cast_as"
+	MsgCastArgs             MessageKey = "cast_args"
+	MsgIfCondition          MessageKey = "if_condition"
+	MsgIfArgs               MessageKey = "if_args"
+	MsgValueNotAllowed      MessageKey = "value_not_allowed"
+	MsgValueTooLong         MessageKey = "value_too_long"
+	MsgValueBelowMin        MessageKey = "value_below_min"
+	MsgValueAboveMax        MessageKey = "value_above_max"
+	MsgValueNotLiteral      MessageKey = "value_not_literal"
+	MsgPatternTooLong       MessageKey = "pattern_too_long"
+	MsgPatternWildcards     MessageKey = "pattern_wildcards"
+	MsgIdentifierTooLong    MessageKey = "identifier_too_long"
+	MsgIdentifierCharacters MessageKey = "identifier_characters"
+	MsgIdentifierEmpty      MessageKey = "identifier_empty"
+	MsgFieldRequired        MessageKey = "field_required"
+	MsgTimeRangeRequired    MessageKey = "time_range_required"
+	MsgTimeRangeTooWide     MessageKey = "time_range_too_wide"
+	MsgNotGrouped           MessageKey = "not_grouped"
+	MsgNestedAggregate      MessageKey = "nested_aggregate"
+	MsgStarArgument         MessageKey = "star_argument"
+	MsgInvalidUUID          MessageKey = "invalid_uuid"
+	MsgInvalidTime          MessageKey = "invalid_time"
+	MsgMissingVariable      MessageKey = "missing_variable"
+	MsgUnknownMacro         MessageKey = "unknown_macro"
+	MsgNullComparison       MessageKey = "null_comparison"
+	MsgDidYouMean           MessageKey = "did_you_mean"
+)
+
+type (
+	// Catalog maps message keys to templates. Templates refer to the details of an error with
+	// placeholders such as {field}, which are replaced with the values from MessageError.Args.
+	// Placeholders may be reordered or omitted.
+	Catalog map[MessageKey]string
+
+	// MessageError is a user-facing error that can be translated with Localize. Its Error method
+	// renders the English template from Messages, followed by the underlying error, if any.
+	MessageError struct {
+		// Key identifies the message template.
+		Key MessageKey
+
+		// Args holds the values for the template's placeholders, already formatted. Field and
+		// function names are quoted where the English message quotes them.
+		Args map[string]string
+
+		// Err is the underlying error, e.g. the parser's description of a syntax error.
+		Err error
+	}
+)
+
+// Messages holds the English templates used for error messages. It can be copied as a starting
+// point for translations.
+var Messages = Catalog{
+	MsgEmptyFilter:          "empty filter expression",
+	MsgSyntax:               "failed to parse filter expression",
+	MsgParseTimeout:         "filter expression could not be parsed within {timeout}",
+	MsgInputLength:          "filter expression exceeds maximum length of {max} bytes",
+	MsgDepth:                "expression depth exceeds maximum of {max}",
+	MsgComplexity:           "filter complexity {score} exceeds maximum of {max}",
+	MsgINEmpty:              "IN expression requires at least one value",
+	MsgINItems:              "IN expression exceeds maximum of {max} items",
+	MsgORBranches:           "OR expression exceeds maximum of {max} branches",
+	MsgNegations:            "filter exceeds maximum of {max} negations",
+	MsgFieldNotAllowed:      "field {field} is not allowed",
+	MsgFunctionNotAllowed:   "function {function} is not allowed",
+	MsgFunctionNotSupported: "function {function} not supported by driver {driver}",
+	MsgFunctionArgs:         "function {function} expects {expected}, got {count}",
+	MsgFunctionArgType:      "function {function} expects a number for argument {position}, got {type}",
+	MsgOperatorNotSupported: "operator {operator} not supported by driver {driver}",
+	MsgCastAs:               "AS is only valid in CAST expressions, not {function}",
+	MsgCastArgs:             "CAST requires exactly one value",
+	MsgIfCondition:          "a condition is only valid as the first argument of IF, not {function}",
+	MsgIfArgs:               "IF requires a condition and two values",
+	MsgValueNotAllowed:      "value {value} is not allowed for field {field}",
+	MsgValueTooLong:         "value for field {field} exceeds maximum length of {max}",
+	MsgValueBelowMin:        "value {value} for field {field} is below minimum of {min}",
+	MsgValueAboveMax:        "value {value} for field {field} exceeds maximum of {max}",
+	MsgValueNotLiteral:      "value for field {field} must be a literal or field",
+	MsgPatternTooLong:       "pattern for field {field} exceeds maximum length of {max}",
+	MsgPatternWildcards:     "pattern for field {field} exceeds maximum of {max} wildcards",
+	MsgIdentifierTooLong:    "identifier {identifier} exceeds maximum length of {max}",
+	MsgIdentifierCharacters: "identifier {identifier} contains disallowed characters",
+	MsgIdentifierEmpty:      "identifier {identifier} is empty",
+	MsgFieldRequired:        "filter must constrain field {field}",
+	MsgTimeRangeRequired:    "filter must bound field {field} with a time range",
+	MsgTimeRangeTooWide:     "time range for field {field} exceeds maximum of {max}",
+	MsgNotGrouped:           "field {field} must be grouped or used in an aggregate function",
+	MsgNestedAggregate:      "aggregate function {function} cannot be nested inside {outer}",
+	MsgStarArgument:         "* is only valid as the argument of COUNT, not {function}",
+	MsgInvalidUUID:          "invalid UUID {value} for field {field}",
+	MsgInvalidTime:          "invalid time {value} for field {field}",
+	MsgMissingVariable:      "missing value for variable {variable}",
+	MsgUnknownMacro:         "unknown macro {macro}",
+	MsgNullComparison:       "comparison with NULL using {operator} is never true; use IS NULL or IS NOT NULL",
+	MsgDidYouMean:           "did you mean {suggestion}?",
+}
+
+var (
+	catalogsMu sync.RWMutex
+	catalogs   = make(map[string]Catalog)
+)
+
+// RegisterCatalog registers the translated templates for a language tag such as "de" or "pt-BR".
+// Keys missing from the catalog fall back to English.
+func RegisterCatalog(lang string, catalog Catalog) {
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+
+	catalogs[strings.ToLower(lang)] = catalog
+}
+
+// Localize returns the message of the first MessageError in err's chain, translated with the catalog
+// registered for lang. A regional tag such as "pt-BR" falls back to the catalog for "pt". Errors
+// without a translation are returned in English, and errors that are not user-facing are returned
+// unchanged.
+//
+// Example:
+//
+//	where.RegisterCatalog("de", where.Catalog{
+//		where.MsgFieldNotAllowed: "Feld {field} ist nicht erlaubt",
+//	})
+//
+//	_, _, err := filter.ToSQL("postgres", where.WithValidator(validator))
+//	fmt.Println(where.Localize(err, "de-AT")) // Feld "secret" ist nicht erlaubt
+func Localize(err error, lang string) string {
+	if err == nil {
+		return ""
+	}
+
+	var me *MessageError
+	if !errors.As(err, &me) {
+		return err.Error()
+	}
+
+	if _, ok := lookupTemplate(lang, me.Key); ok {
+		return me.message(func(key MessageKey) string {
+			if tmpl, ok := lookupTemplate(lang, key); ok {
+				return tmpl
+			}
+			return Messages[key]
+		})
+	}
+	return me.Error()
+}
+
+// lookupTemplate returns the template for key in the catalog for lang or its base language.
+func lookupTemplate(lang string, key MessageKey) (string, bool) {
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	lang = strings.ToLower(lang)
+	for {
+		if tmpl, ok := catalogs[lang][key]; ok {
+			return tmpl, true
+		}
+
+		i := strings.LastIndexAny(lang, "-_")
+		if i < 0 {
+			return "", false
+		}
+		lang = lang[:i]
+	}
+}
+
+// newMessage returns a MessageError for key with placeholder names and values given as pairs.
+func newMessage(key MessageKey, pairs ...any) *MessageError {
+	args := make(map[string]string, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		args[fmt.Sprint(pairs[i])] = fmt.Sprint(pairs[i+1])
+	}
+	return &MessageError{Key: key, Args: args}
+}
+
+// wrapping sets the underlying error and returns the message.
+func (e *MessageError) wrapping(err error) *MessageError {
+	e.Err = err
+	return e
+}
+
+func (e *MessageError) Error() string {
+	msg := e.message(func(key MessageKey) string { return Messages[key] })
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *MessageError) Unwrap() error { return e.Err }
+
+// message renders the message with the templates returned by lookup. A suggestion for a misspelled
+// name, e.g. of a field that is not allowed, is appended using the MsgDidYouMean template.
+func (e *MessageError) message(lookup func(MessageKey) string) string {
+	msg := e.render(lookup(e.Key))
+	if _, ok := e.Args["suggestion"]; ok && e.Key != MsgDidYouMean {
+		msg += "; " + e.render(lookup(MsgDidYouMean))
+	}
+	return msg
+}
+
+func (e *MessageError) render(tmpl string) string {
+	if len(e.Args) == 0 {
+		return tmpl
+	}
+
+	replacements := make([]string, 0, len(e.Args)*2)
+	for name, value := range e.Args {
+		replacements = append(replacements, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(replacements...).Replace(tmpl)
+}
+
+// syntaxError describes a parse failure, with the position of the error when the parser reports one.
+func syntaxError(err error) *MessageError {
+	var perr participle.Error
+	if errors.As(err, &perr) {
+		pos := perr.Position()
+		return newMessage(MsgSyntax, "line", pos.Line, "column", pos.Column).wrapping(err)
+	}
+	return newMessage(MsgSyntax).wrapping(err)
+}
@@ -0,0 +1,128 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalize(t *testing.T) {
+	where.RegisterCatalog("xx", where.Catalog{
+		where.MsgFieldNotAllowed: "campo {field} no permitido",
+		where.MsgSyntax:          "error de sintaxis en la línea {line}, columna {column}",
+		where.MsgValueAboveMax:   "{field}: máximo {max}, recibido {value}",
+		where.MsgEmptyFilter:     "filtro vacío",
+	})
+	where.RegisterCatalog("xx-YY", where.Catalog{
+		where.MsgFieldNotAllowed: "campo regional {field}",
+	})
+
+	maxAge := 150.0
+	validator := where.NewValidator().
+		AllowFields("age").
+		ConstrainField("age", where.FieldConstraint{Max: &maxAge})
+
+	tests := []struct {
+		name    string
+		lang    string
+		err     func() error
+		want    string
+		english string
+	}{
+		{
+			name: "validation error",
+			lang: "xx",
+			err: func() error {
+				_, _, err := mustParse(t, "secret = 1").ToSQL("postgres", where.WithValidator(validator))
+				return err
+			},
+			want:    `campo "secret" no permitido`,
+			english: `field "secret" is not allowed`,
+		},
+		{
+			name: "regional catalog",
+			lang: "xx-YY",
+			err: func() error {
+				_, _, err := mustParse(t, "secret = 1").ToSQL("postgres", where.WithValidator(validator))
+				return err
+			},
+			want: `campo regional "secret"`,
+		},
+		{
+			name: "regional tag falls back to the base language",
+			lang: "XX_ZZ",
+			err: func() error {
+				_, _, err := mustParse(t, "age > 200").ToSQL("postgres", where.WithValidator(validator))
+				return err
+			},
+			want:    `"age": máximo 150, recibido 200`,
+			english: `value 200 for field "age" exceeds maximum of 150`,
+		},
+		{
+			name: "syntax error",
+			lang: "xx",
+			err: func() error {
+				_, err := where.Parse("age > > 1")
+				return err
+			},
+			want: "error de sintaxis en la línea 1, columna 7",
+		},
+		{
+			name: "wrapped error",
+			lang: "xx",
+			err: func() error {
+				_, err := where.Parse("")
+				return errors.Wrap(err, "request failed")
+			},
+			want:    "filtro vacío",
+			english: "request failed: empty filter expression",
+		},
+		{
+			name: "missing translation",
+			lang: "xx",
+			err: func() error {
+				_, _, err := mustParse(t, "x <=> 1").ToSQL("clickhouse")
+				return err
+			},
+			want:    "operator <=> not supported by driver clickhouse",
+			english: "operator <=> not supported by driver clickhouse",
+		},
+		{
+			name: "unknown language",
+			lang: "fr",
+			err: func() error {
+				_, _, err := mustParse(t, "secret = 1").ToSQL("postgres", where.WithValidator(validator))
+				return err
+			},
+			want: `field "secret" is not allowed`,
+		},
+		{
+			name: "not a user-facing error",
+			lang: "xx",
+			err:  func() error { return errors.New("boom") },
+			want: "boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.err()
+			require.Error(t, err)
+			require.Equal(t, tt.want, where.Localize(err, tt.lang))
+			if tt.english != "" {
+				require.EqualError(t, err, tt.english)
+			}
+
+			var me *where.MessageError
+			if errors.As(err, &me) {
+				require.Contains(t, where.Messages, me.Key)
+			}
+		})
+	}
+}
+
+func TestLocalizeNil(t *testing.T) {
+	require.Empty(t, where.Localize(nil, "en"))
+}
@@ -0,0 +1,91 @@
+package where
+
+type (
+	// DriverMiddleware wraps a driver, e.g. to audit or override the identifiers it quotes. See
+	// WrapDriver.
+	DriverMiddleware func(Driver) Driver
+
+	// DriverWrapper is embedded by middleware to delegate every Driver method to the wrapped driver,
+	// so the middleware only implements the methods it intercepts. Optional interfaces such as
+	// ParamLimiter are looked up through Unwrap, so they keep working when the driver is wrapped.
+	//
+	// Example:
+	//
+	//	type auditDriver struct{ where.DriverWrapper }
+	//
+	//	func (d auditDriver) QuoteIdentifier(name string) string {
+	//		log.Printf("quoting %s", name)
+	//		return d.Driver.QuoteIdentifier(name)
+	//	}
+	DriverWrapper struct {
+		Driver
+	}
+
+	// FunctionInterceptor is implemented by middleware that intercepts function calls, e.g. to count
+	// them or to render some functions differently. It is called after the validator accepts the
+	// call.
+	FunctionInterceptor interface {
+		// TranslateFunction renders a function call. next renders a call the way the wrapped driver
+		// would, and may be called with a different call, e.g. one with another name.
+		TranslateFunction(fn *FunctionCall, next func(*FunctionCall) (string, error)) (string, error)
+	}
+
+	// unwrapper is implemented by drivers wrapping another driver.
+	unwrapper interface {
+		Unwrap() Driver
+	}
+)
+
+// Unwrap returns the wrapped driver.
+func (w DriverWrapper) Unwrap() Driver {
+	return w.Driver
+}
+
+// WrapDriver replaces the driver registered with the given name by the driver returned by the
+// middleware, which receives the registered driver. Other names of the driver are not wrapped.
+// Translations registered for the driver still apply as long as the middleware keeps its Name.
+// An error is returned if no driver is registered with the name.
+//
+// Example:
+//
+//	err := where.WrapDriver("postgres", func(d where.Driver) where.Driver {
+//		return auditDriver{where.DriverWrapper{Driver: d}}
+//	})
+func WrapDriver(name string, middleware DriverMiddleware) error {
+	return defaultRegistry.Wrap(name, middleware)
+}
+
+// DriverAs returns the first driver in the chain of wrapped drivers, starting with driver itself,
+// that implements T. It is used to find optional interfaces, e.g. DriverAs[where.ParamLimiter].
+func DriverAs[T any](driver Driver) (T, bool) {
+	for driver != nil {
+		if t, ok := driver.(T); ok {
+			return t, true
+		}
+
+		w, ok := driver.(unwrapper)
+		if !ok {
+			break
+		}
+		driver = w.Unwrap()
+	}
+
+	var zero T
+	return zero, false
+}
+
+// translateFunction renders a function call through the function interceptors of the driver and
+// the drivers it wraps, outermost first.
+func (b *SQLBuilder) translateFunction(driver Driver, fn *FunctionCall) (string, error) {
+	next := b.renderFunctionCall
+	if w, ok := driver.(unwrapper); ok {
+		if inner := w.Unwrap(); inner != nil {
+			next = func(fn *FunctionCall) (string, error) { return b.translateFunction(inner, fn) }
+		}
+	}
+
+	if interceptor, ok := driver.(FunctionInterceptor); ok {
+		return interceptor.TranslateFunction(fn, next)
+	}
+	return next(fn)
+}
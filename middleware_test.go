@@ -0,0 +1,125 @@
+package where_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pseudomuto/where"
+	_ "github.com/pseudomuto/where/drivers/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+type auditDriver struct {
+	where.DriverWrapper
+	quoted    []string
+	functions []string
+}
+
+func (d *auditDriver) QuoteIdentifier(name string) string {
+	d.quoted = append(d.quoted, name)
+	return d.Driver.QuoteIdentifier(name)
+}
+
+func (d *auditDriver) TranslateFunction(fn *where.FunctionCall, next func(*where.FunctionCall) (string, error)) (string, error) {
+	d.functions = append(d.functions, fn.Name)
+	if strings.EqualFold(fn.Name, "TODAY") {
+		return "CURRENT_DATE", nil
+	}
+	return next(fn)
+}
+
+type renameDriver struct {
+	where.DriverWrapper
+}
+
+func (d renameDriver) TranslateFunction(fn *where.FunctionCall, next func(*where.FunctionCall) (string, error)) (string, error) {
+	if strings.EqualFold(fn.Name, "LEN") {
+		return next(&where.FunctionCall{Name: "LENGTH", Args: fn.Args})
+	}
+	return next(fn)
+}
+
+func TestWrapDriver(t *testing.T) {
+	pg, err := where.GetDriver("postgres")
+	require.NoError(t, err)
+
+	where.RegisterDriver("wrapped_pg", pg)
+	var audit *auditDriver
+	require.NoError(t, where.WrapDriver("wrapped_pg", func(d where.Driver) where.Driver {
+		audit = &auditDriver{DriverWrapper: where.DriverWrapper{Driver: d}}
+		return audit
+	}))
+
+	filter, err := where.Parse("user = 'bob' AND created_at >= TODAY() AND YEAR(created_at) = 2024 AND id IN (1, 2)")
+	require.NoError(t, err)
+
+	sql, params, err := filter.ToSQL("wrapped_pg", where.WithArrayBinding())
+	require.NoError(t, err)
+	require.Equal(t, `("user" = $1 AND created_at >= CURRENT_DATE AND EXTRACT(YEAR FROM created_at) = $2 AND id = ANY($3))`, sql)
+	require.Len(t, params, 3)
+	require.Equal(t, []string{"user", "created_at", "created_at", "id"}, audit.quoted)
+	require.Equal(t, []string{"TODAY", "YEAR"}, audit.functions)
+
+	// Other names of the driver are not wrapped.
+	_, _, err = filter.ToSQL("postgres")
+	require.NoError(t, err)
+	require.Len(t, audit.quoted, 4)
+}
+
+func TestWrapDriverNested(t *testing.T) {
+	pg, err := where.GetDriver("postgres")
+	require.NoError(t, err)
+
+	where.RegisterDriver("nested_pg", pg)
+	var audit *auditDriver
+	require.NoError(t, where.WrapDriver("nested_pg", func(d where.Driver) where.Driver {
+		return renameDriver{where.DriverWrapper{Driver: d}}
+	}))
+	require.NoError(t, where.WrapDriver("nested_pg", func(d where.Driver) where.Driver {
+		audit = &auditDriver{DriverWrapper: where.DriverWrapper{Driver: d}}
+		return audit
+	}))
+
+	filter, err := where.Parse("LEN(name) > 3 AND TODAY() > created_at")
+	require.NoError(t, err)
+
+	sql, _, err := filter.ToSQL("nested_pg")
+	require.NoError(t, err)
+	require.Equal(t, "(LENGTH(name) > $1 AND CURRENT_DATE > created_at)", sql)
+	require.Equal(t, []string{"LEN", "TODAY"}, audit.functions)
+}
+
+func TestWrapDriverErrors(t *testing.T) {
+	err := where.WrapDriver("nonexistent", func(d where.Driver) where.Driver { return d })
+	require.EqualError(t, err, `driver "nonexistent" not registered`)
+
+	where.RegisterDriver("nil_wrapped", &MockDriver{name: "nil_wrapped"})
+	err = where.WrapDriver("nil_wrapped", func(where.Driver) where.Driver { return nil })
+	require.EqualError(t, err, `middleware for driver "nil_wrapped" returned nil`)
+
+	driver, err := where.GetDriver("nil_wrapped")
+	require.NoError(t, err)
+	require.Equal(t, "nil_wrapped", driver.Name())
+
+	require.Panics(t, func() { _ = where.WrapDriver("nil_wrapped", nil) })
+}
+
+func TestDriverAs(t *testing.T) {
+	pg, err := where.GetDriver("postgres")
+	require.NoError(t, err)
+
+	wrapped := &auditDriver{DriverWrapper: where.DriverWrapper{Driver: pg}}
+	_, ok := wrapped.Driver.(where.FunctionInterceptor)
+	require.False(t, ok)
+
+	searcher, ok := where.DriverAs[where.TextSearcher](wrapped)
+	require.True(t, ok)
+	require.Equal(t, pg, searcher)
+
+	interceptor, ok := where.DriverAs[where.FunctionInterceptor](wrapped)
+	require.True(t, ok)
+	require.Equal(t, wrapped, interceptor)
+
+	_, ok = where.DriverAs[where.TextSearcher](&MockDriver{name: "mock"})
+	require.False(t, ok)
+}
@@ -0,0 +1,137 @@
+package where
+
+// negatedOperators maps comparison operators to their negation.
+var negatedOperators = map[string]string{
+	"=":  "!=",
+	"!=": "=",
+	"<>": "=",
+	"<":  ">=",
+	">=": "<",
+	">":  "<=",
+	"<=": ">",
+}
+
+// ToNNF returns an equivalent filter in negation normal form, where NOT is pushed down to single
+// predicates using De Morgan's laws and then folded into the predicate's operator, e.g.
+// NOT (a = 1 AND b IN (1, 2)) becomes a != 1 OR b NOT IN (1, 2). This helps query engines that
+// cannot use indexes under NOT, and targets without a general NOT. Negations follow SQL's
+// three-valued logic, so the result matches the same rows even when fields are NULL.
+//
+// NOT remains only where the operator has no negated form: on <=> comparisons, as NOT (a <=> b),
+// and on EXISTS conditions, as NOT EXISTS. The result shares no AST nodes with f.
+func (f *Filter) ToNNF() *Filter {
+	if f == nil || f.Expression == nil {
+		return f
+	}
+	expr := nnfExpression(f.Expression, false)
+	for len(expr.Or) == 1 && len(expr.Or[0].And) == 1 && !expr.Or[0].And[0].Not && expr.Or[0].And[0].SubExpr != nil {
+		expr = expr.Or[0].And[0].SubExpr
+	}
+	return &Filter{Pos: f.Pos, Expression: expr}
+}
+
+// nnfExpression returns the expression, or its negation, in negation normal form.
+func nnfExpression(expr *Expression, negate bool) *Expression {
+	if !negate {
+		result := &Expression{Or: make([]*Term, 0, len(expr.Or))}
+		for _, term := range expr.Or {
+			if term != nil {
+				result.Or = append(result.Or, nnfTerm(term, false).Or...)
+			}
+		}
+		return result
+	}
+
+	// NOT (a OR b) is NOT a AND NOT b.
+	term := &Term{And: make([]*Factor, 0, len(expr.Or))}
+	for _, t := range expr.Or {
+		if t != nil {
+			term.And = append(term.And, group(nnfTerm(t, true)))
+		}
+	}
+	return &Expression{Or: []*Term{term}}
+}
+
+// nnfTerm returns the AND term, or its negation, in negation normal form.
+func nnfTerm(term *Term, negate bool) *Expression {
+	if !negate {
+		result := &Term{And: make([]*Factor, 0, len(term.And))}
+		for _, factor := range term.And {
+			if factor != nil {
+				result.And = append(result.And, nnfFactor(factor, false))
+			}
+		}
+		return &Expression{Or: []*Term{result}}
+	}
+
+	// NOT (a AND b) is NOT a OR NOT b.
+	result := &Expression{Or: make([]*Term, 0, len(term.And))}
+	for _, factor := range term.And {
+		if factor != nil {
+			result.Or = append(result.Or, &Term{And: []*Factor{nnfFactor(factor, true)}})
+		}
+	}
+	return result
+}
+
+// nnfFactor returns the factor, or its negation, in negation normal form.
+func nnfFactor(factor *Factor, negate bool) *Factor {
+	negate = negate != factor.Not
+	switch {
+	case factor.SubExpr != nil:
+		return group(nnfExpression(factor.SubExpr, negate))
+	case factor.Exists != nil:
+		exists := factor.DeepCopy()
+		exists.Not = negate
+		return exists
+	case factor.Predicate == nil:
+		return factor.DeepCopy()
+	case !negate:
+		return &Factor{Predicate: factor.Predicate.DeepCopy()}
+	}
+
+	if pred := negatePredicate(factor.Predicate); pred != nil {
+		return &Factor{Predicate: pred}
+	}
+	return &Factor{Not: true, SubExpr: &Expression{Or: []*Term{{And: []*Factor{{Predicate: factor.Predicate.DeepCopy()}}}}}}
+}
+
+// group returns the expression as a factor, without parentheses if it is a single factor.
+func group(expr *Expression) *Factor {
+	if len(expr.Or) == 1 && len(expr.Or[0].And) == 1 {
+		return expr.Or[0].And[0]
+	}
+	return &Factor{SubExpr: expr}
+}
+
+// negatePredicate returns a copy of the predicate with its operator negated, or nil if the operator
+// has no negated form.
+func negatePredicate(pred *Predicate) *Predicate {
+	if pred.Operation == nil {
+		return nil
+	}
+
+	negated := pred.DeepCopy()
+	op := negated.Operation
+	switch {
+	case op.Compare != nil:
+		operator, ok := negatedOperators[op.Compare.Operator.String()]
+		if !ok {
+			return nil
+		}
+		op.Compare.Operator = CompareOperator{Type: operator}
+	case op.Like != nil:
+		op.Like.Not = !op.Like.Not
+	case op.Match != nil:
+		op.Match.Not = !op.Match.Not
+	case op.In != nil:
+		op.In.Not = !op.In.Not
+	case op.Between != nil:
+		op.Between.Not = !op.Between.Not
+	case op.IsNull != nil:
+		op.IsNull.Not = !op.IsNull.Not
+	default:
+		return nil
+	}
+	return negated
+}
@@ -0,0 +1,57 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterToNNF(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "a = 1 AND b > 2", want: "a = 1 AND b > 2"},
+		{input: "NOT (a = 1 AND b = 2)", want: "a != 1 OR b != 2"},
+		{input: "NOT (a = 1 OR b = 2)", want: "a != 1 AND b != 2"},
+		{input: "NOT (a <> 1)", want: "a = 1"},
+		{input: "NOT (a < 1 OR a >= 5 OR a > 2 OR a <= 3)", want: "a >= 1 AND a < 5 AND a <= 2 AND a > 3"},
+		{input: "NOT (name LIKE 'a%' AND name NOT ILIKE 'b%')", want: "name NOT LIKE 'a%' OR name ILIKE 'b%'"},
+		{input: "NOT (a IN (1, 2) OR b NOT IN (3))", want: "a NOT IN (1, 2) AND b IN (3)"},
+		{input: "NOT (a BETWEEN 1 AND 5)", want: "a NOT BETWEEN 1 AND 5"},
+		{input: "NOT (a IS NULL OR b IS NOT NULL)", want: "a IS NOT NULL AND b IS NULL"},
+		{input: "NOT (NOT (a = 1))", want: "a = 1"},
+		{input: "NOT (a <=> NULL)", want: "NOT (a <=> NULL)"},
+		{input: "x = 1 AND NOT (a = 1 AND (b = 2 OR c = 3))", want: "x = 1 AND (a != 1 OR (b != 2 AND c != 3))"},
+		{input: "NOT ((a = 1 OR b = 2) AND c = 3)", want: "(a != 1 AND b != 2) OR c != 3"},
+		{input: "(a = 1 OR b = 2) AND c = 3", want: "(a = 1 OR b = 2) AND c = 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, filter.ToNNF().String())
+		})
+	}
+}
+
+func TestFilterToNNFExists(t *testing.T) {
+	filter := where.NotExists("SELECT 1 FROM orders WHERE orders.user_id = users.id")
+	require.Equal(t, "NOT EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id)", filter.ToNNF().String())
+}
+
+func TestFilterToNNFSQL(t *testing.T) {
+	filter, err := where.Parse("NOT (a = 1 AND b IN (1, 2))")
+	require.NoError(t, err)
+
+	nnf := filter.ToNNF()
+	sql, params, err := nnf.ToSQL("postgres")
+	require.NoError(t, err)
+	require.Equal(t, "(a != $1 OR b NOT IN ($2, $3))", sql)
+	require.Equal(t, []any{1.0, 1.0, 2.0}, params)
+
+	// The original filter is unchanged.
+	require.Equal(t, "NOT (a = 1 AND b IN (1, 2))", filter.String())
+}
@@ -0,0 +1,97 @@
+package where
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// UnicodeNone leaves the code points of a string unchanged.
+	UnicodeNone UnicodeForm = iota
+	// UnicodeNFC composes characters, e.g. "e" followed by a combining acute accent becomes "é".
+	UnicodeNFC
+	// UnicodeNFKC composes characters and replaces compatibility characters with their plain
+	// equivalents, e.g. the ligature "ﬁ" becomes "fi" and the full-width "Ａ" becomes "A".
+	UnicodeNFKC
+)
+
+type (
+	// UnicodeForm is a Unicode normalization form applied to bound strings.
+	UnicodeForm int
+
+	// StringNormalization describes how string parameters are normalized before being bound.
+	StringNormalization struct {
+		// Form is the Unicode normalization form strings are converted to.
+		Form UnicodeForm
+		// TrimSpace removes leading and trailing white space.
+		TrimSpace bool
+	}
+)
+
+// Normalize returns s normalized as described by n. Applications can use it to normalize stored
+// content the same way filter values are normalized.
+func (n StringNormalization) Normalize(s string) string {
+	if n.TrimSpace {
+		s = strings.TrimSpace(s)
+	}
+
+	switch n.Form {
+	case UnicodeNFC:
+		return norm.NFC.String(s)
+	case UnicodeNFKC:
+		return norm.NFKC.String(s)
+	default:
+		return s
+	}
+}
+
+// WithStringNormalization returns a BuildOption that normalizes every bound string parameter,
+// whether it comes from a string literal, a variable, or a bound value, before it is passed to the
+// database. Values that look the same but are encoded differently, such as a precomposed "é" and
+// an "e" followed by a combining accent, then compare equal to content normalized the same way.
+// Fields configured with WithFieldNormalization use their own normalization instead.
+//
+// Example:
+//
+//	filter, _ := where.Parse("name = 'Zoë'")
+//	sql, params, _ := filter.ToSQL("postgres", where.WithStringNormalization(where.StringNormalization{
+//		Form: where.UnicodeNFC,
+//	}))
+//	// name = $1, with "Zoë" bound in its composed form
+func WithStringNormalization(n StringNormalization) BuildOption {
+	return func(b *SQLBuilder) {
+		b.normDefault = &n
+	}
+}
+
+// WithFieldNormalization returns a BuildOption that normalizes string parameters compared against
+// the given fields. Use a zero StringNormalization to leave a field's values unchanged when
+// WithStringNormalization is also set. Field names are case-insensitive.
+func WithFieldNormalization(fields map[string]StringNormalization) BuildOption {
+	return func(b *SQLBuilder) {
+		if b.normFields == nil {
+			b.normFields = make(map[string]StringNormalization, len(fields))
+		}
+		for field, n := range fields {
+			b.normFields[strings.ToLower(field)] = n
+		}
+	}
+}
+
+// predicateNormalization returns the normalization applied to strings bound in a predicate, or nil
+// if they are bound unchanged.
+func (b *SQLBuilder) predicateNormalization(pred *Predicate) *StringNormalization {
+	if n, ok := b.normFields[strings.ToLower(predicateField(pred))]; ok {
+		return &n
+	}
+	return b.normDefault
+}
+
+// normalizeParam normalizes param if it is a string and the current predicate normalizes strings.
+func (b *SQLBuilder) normalizeParam(param any) any {
+	if str, ok := param.(string); ok && b.normalize != nil {
+		return b.normalize.Normalize(str)
+	}
+	return param
+}
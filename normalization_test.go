@@ -0,0 +1,101 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringNormalization(t *testing.T) {
+	nfc := where.StringNormalization{Form: where.UnicodeNFC}
+	nfkc := where.StringNormalization{Form: where.UnicodeNFKC, TrimSpace: true}
+
+	tests := []struct {
+		name    string
+		input   string
+		options []where.BuildOption
+		want    string
+		params  []any
+	}{
+		{
+			name:    "NFC",
+			input:   "name = 'Zoe\u0308' AND city LIKE 'Montre\u0301al%'",
+			options: []where.BuildOption{where.WithStringNormalization(nfc)},
+			want:    "(name = $1 AND city LIKE $2)",
+			params:  []any{"Zo\u00eb", "Montr\u00e9al%"},
+		},
+		{
+			name:    "NFKC with trimming",
+			input:   "title = '  \ufb01le \uff21 '",
+			options: []where.BuildOption{where.WithStringNormalization(nfkc)},
+			want:    "title = $1",
+			params:  []any{"file A"},
+		},
+		{
+			name:  "per field",
+			input: "name = ' Zoe\u0308 ' AND code = ' \uff21 ' AND note = ' x '",
+			options: []where.BuildOption{
+				where.WithStringNormalization(nfc),
+				where.WithFieldNormalization(map[string]where.StringNormalization{"CODE": nfkc, "note": {}}),
+			},
+			want:   "(name = $1 AND code = $2 AND note = $3)",
+			params: []any{" Zo\u00eb ", "A", " x "},
+		},
+		{
+			name:  "variables and IN",
+			input: "name = :name AND tag IN (:tags) AND age = :age",
+			options: []where.BuildOption{
+				where.WithStringNormalization(nfc),
+				where.WithVariables(map[string]any{"name": "Zoe\u0308", "tags": []string{"cafe\u0301", "x"}, "age": 30}),
+			},
+			want:   "(name = $1 AND tag IN ($2, $3) AND age = $4)",
+			params: []any{"Zo\u00eb", "caf\u00e9", "x", 30},
+		},
+		{
+			name:  "function arguments",
+			input: "LOWER(name) = 'zoe\u0308'",
+			options: []where.BuildOption{
+				where.WithFieldNormalization(map[string]where.StringNormalization{"name": nfc}),
+			},
+			want:   "LOWER(name) = $1",
+			params: []any{"zo\u00eb"},
+		},
+		{
+			name:  "typed fields",
+			input: "id = ' A0EEBC99-9C0B-4EF8-BB6D-6BB9BD380A11 '",
+			options: []where.BuildOption{
+				where.WithStringNormalization(where.StringNormalization{TrimSpace: true}),
+				where.WithFieldTypes(map[string]where.FieldType{"id": where.FieldTypeUUID}),
+			},
+			want:   "id = $1",
+			params: []any{"a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+		},
+		{
+			name:   "disabled by default",
+			input:  "name = ' Zoe\u0308 '",
+			want:   "name = $1",
+			params: []any{" Zoe\u0308 "},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			sql, params, err := filter.ToSQL("postgres", tt.options...)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, sql)
+			require.Equal(t, tt.params, params)
+		})
+	}
+}
+
+func TestStringNormalizationNormalize(t *testing.T) {
+	require.Equal(t, "Zo\u00eb", where.StringNormalization{Form: where.UnicodeNFC}.Normalize("Zoe\u0308"))
+	require.Equal(t, "\ufb01", where.StringNormalization{Form: where.UnicodeNFC}.Normalize("\ufb01"))
+	require.Equal(t, "fi", where.StringNormalization{Form: where.UnicodeNFKC}.Normalize("\ufb01"))
+	require.Equal(t, " a ", where.StringNormalization{}.Normalize(" a "))
+	require.Equal(t, "a", where.StringNormalization{TrimSpace: true}.Normalize(" a\t"))
+}
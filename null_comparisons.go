@@ -0,0 +1,73 @@
+package where
+
+// NullComparisonMode determines how comparisons with a NULL literal, such as parent_id = NULL, are
+// built. Such comparisons are never true in SQL, so they are almost always meant as IS NULL.
+type NullComparisonMode int
+
+const (
+	// NullComparisonAllow builds comparisons with NULL as written. This is the default.
+	NullComparisonAllow NullComparisonMode = iota
+
+	// NullComparisonRewrite builds = NULL as IS NULL and != NULL or <> NULL as IS NOT NULL. Other
+	// comparisons with NULL are built as written.
+	NullComparisonRewrite
+
+	// NullComparisonReject fails to build filters that compare a value with NULL using any operator
+	// other than <=>.
+	NullComparisonReject
+)
+
+// WithNullComparisons returns a BuildOption that sets how comparisons with a NULL literal are built.
+// The NULL may be on either side of the comparison; NULL-safe <=> comparisons are unaffected.
+//
+// Example:
+//
+//	filter, _ := where.Parse("parent_id = NULL AND deleted_at != NULL")
+//	sql, _, _ := filter.ToSQL("postgres", where.WithNullComparisons(where.NullComparisonRewrite))
+//	// (parent_id IS NULL AND deleted_at IS NOT NULL)
+func WithNullComparisons(mode NullComparisonMode) BuildOption {
+	return func(b *SQLBuilder) {
+		b.nullCompares = mode
+	}
+}
+
+// nullComparison returns the predicate to build for a comparison with NULL according to the
+// builder's mode, or an error if the comparison is rejected.
+func (b *SQLBuilder) nullComparison(pred *Predicate) (*Predicate, error) {
+	comp := pred.Operation.Compare
+	if b.nullCompares == NullComparisonAllow || comp == nil {
+		return pred, nil
+	}
+
+	operator := comp.Operator.String()
+	if operator == "<=>" {
+		return pred, nil
+	}
+
+	var other *Value
+	switch {
+	case isNullLiteral(comp.Right):
+		other = pred.Left
+	case isNullLiteral(pred.Left):
+		other = comp.Right
+	default:
+		return pred, nil
+	}
+
+	if b.nullCompares == NullComparisonReject {
+		return nil, newMessage(MsgNullComparison, "operator", operator)
+	}
+	if operator != "=" && !isNotEqual(operator) {
+		return pred, nil
+	}
+
+	return &Predicate{
+		Left:      other,
+		Operation: &Operation{IsNull: &IsNullOp{Is: "IS", Not: operator != "=", Null: "NULL"}},
+	}, nil
+}
+
+// isNullLiteral returns true if the value is a NULL literal.
+func isNullLiteral(val *Value) bool {
+	return val != nil && val.Literal != nil && val.Literal.Type() == "null"
+}
@@ -0,0 +1,95 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithNullComparisons(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		mode   where.NullComparisonMode
+		want   string
+		params []any
+		err    string
+	}{
+		{
+			name:  "allow",
+			input: "parent_id = NULL AND deleted_at != NULL",
+			mode:  where.NullComparisonAllow,
+			want:  "(parent_id = NULL AND deleted_at != NULL)",
+		},
+		{
+			name:  "rewrite",
+			input: "parent_id = NULL AND deleted_at != NULL AND NULL <> manager_id",
+			mode:  where.NullComparisonRewrite,
+			want:  "(parent_id IS NULL AND deleted_at IS NOT NULL AND manager_id IS NOT NULL)",
+		},
+		{
+			name:   "rewrite keeps other operators",
+			input:  "age > NULL AND manager_id <=> NULL AND name = 'x'",
+			mode:   where.NullComparisonRewrite,
+			want:   "(age > NULL AND manager_id IS NOT DISTINCT FROM NULL AND name = $1)",
+			params: []any{"x"},
+		},
+		{
+			name:  "reject",
+			input: "name = 'x' AND parent_id = NULL",
+			mode:  where.NullComparisonReject,
+			err:   "comparison with NULL using = is never true; use IS NULL or IS NOT NULL",
+		},
+		{
+			name:  "reject other operators",
+			input: "age > NULL",
+			mode:  where.NullComparisonReject,
+			err:   "comparison with NULL using > is never true; use IS NULL or IS NOT NULL",
+		},
+		{
+			name:  "reject allows NULL-safe comparison and IS NULL",
+			input: "manager_id <=> NULL AND parent_id IS NULL",
+			mode:  where.NullComparisonReject,
+			want:  "(manager_id IS NOT DISTINCT FROM NULL AND parent_id IS NULL)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			sql, params, err := filter.ToSQL("postgres", where.WithNullComparisons(tt.mode))
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+
+				var msg *where.MessageError
+				require.ErrorAs(t, err, &msg)
+				require.Equal(t, where.MsgNullComparison, msg.Key)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, sql)
+			if tt.params == nil {
+				require.Empty(t, params)
+			} else {
+				require.Equal(t, tt.params, params)
+			}
+		})
+	}
+}
+
+func TestWithNullComparisonsBoundNull(t *testing.T) {
+	filter, err := where.Parse("parent_id = NULL")
+	require.NoError(t, err)
+
+	sql, params, err := filter.ToSQL("postgres",
+		where.WithNullComparisons(where.NullComparisonRewrite),
+		where.WithBindConstants(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "parent_id IS NULL", sql)
+	require.Empty(t, params)
+}
@@ -0,0 +1,173 @@
+package where
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+const (
+	// NumberFloat64 binds every number literal as a float64. This is the default. Integers beyond
+	// 2^53 are rounded to the nearest float64.
+	NumberFloat64 NumberType = iota
+
+	// NumberInt64 binds integer literals, including integers in scientific notation such as 1e6, as
+	// int64, or as uint64 when they exceed the int64 range. Other numbers are bound as float64, and
+	// integers beyond the uint64 range fail SQL generation.
+	NumberInt64
+
+	// NumberString binds number literals as their decimal text, e.g. "12.50" or "1e6", preserving
+	// the exact value for DECIMAL and large integer columns.
+	NumberString
+)
+
+type (
+	// NumberType selects the Go type number literals are bound as.
+	NumberType int
+)
+
+// WithHexNumbers returns a ParserOption that parses hex literals such as 0xFF as numbers, as
+// ClickHouse does, instead of binary strings.
+func WithHexNumbers() ParserOption {
+	return func(o *parserOptions) {
+		o.hexNumbers = true
+	}
+}
+
+// WithNumberType returns a BuildOption that selects the Go type number literals are bound as.
+// Use NumberInt64 to compare large integers, such as IDs or timestamps stored as numbers,
+// without the rounding of float64.
+func WithNumberType(typ NumberType) BuildOption {
+	return func(b *SQLBuilder) {
+		b.numberType = typ
+	}
+}
+
+// numberParam returns the value bound for a number literal. Integer operands of bitwise operators
+// are bound as integers with NumberFloat64 too.
+func (b *SQLBuilder) numberParam(lit *LiteralValue) (any, error) {
+	if b.numberType == NumberFloat64 && !b.integers {
+		return *lit.Number, nil
+	}
+
+	numeral, ok := lit.numeral()
+	if !ok {
+		numeral = strconv.FormatFloat(*lit.Number, 'f', -1, 64)
+	}
+
+	switch b.numberType {
+	case NumberInt64, NumberFloat64:
+		return integerParam(numeral, *lit.Number)
+	case NumberString:
+		return numeral, nil
+	default:
+		return *lit.Number, nil
+	}
+}
+
+// numeral returns the Numeral of a number literal, unless Number has since been changed.
+func (l *LiteralValue) numeral() (string, bool) {
+	if l.Numeral == nil || l.Number == nil {
+		return "", false
+	}
+
+	n, err := strconv.ParseFloat(*l.Numeral, 64)
+	return *l.Numeral, err == nil && n == *l.Number
+}
+
+// isInteger returns true if the numeral is written as an integer, without a fraction or exponent.
+func isInteger(numeral string) bool {
+	return !strings.ContainsAny(numeral, ".eE")
+}
+
+// integerParam returns the numeral as an int64 or uint64 if it is an integer, and value otherwise.
+func integerParam(numeral string, value float64) (any, error) {
+	if n, err := strconv.ParseInt(numeral, 10, 64); err == nil {
+		return n, nil
+	}
+	if n, err := strconv.ParseUint(numeral, 10, 64); err == nil {
+		return n, nil
+	}
+
+	f, _, err := big.ParseFloat(numeral, 10, 256, big.ToNearestEven)
+	if err != nil || !f.IsInt() {
+		return value, nil
+	}
+	if n, acc := f.Int64(); acc == big.Exact {
+		return n, nil
+	}
+	if n, acc := f.Uint64(); acc == big.Exact {
+		return n, nil
+	}
+	return nil, fmt.Errorf("integer %s is out of range for int64 and uint64", numeral)
+}
+
+// normalizeNumeral rewrites a Number token in plain decimal form: digit separators are removed and
+// octal integers are converted to decimal.
+func normalizeNumeral(token string) (string, error) {
+	token = strings.ReplaceAll(token, "_", "")
+	if !isOctal(token) {
+		return token, nil
+	}
+
+	n, ok := new(big.Int).SetString(token, 0)
+	if !ok {
+		return "", fmt.Errorf("invalid octal literal %s", token)
+	}
+	return n.String(), nil
+}
+
+// normalizeNumber is a token mapper applying normalizeNumeral to Number tokens.
+func normalizeNumber(tok lexer.Token) (lexer.Token, error) {
+	numeral, err := normalizeNumeral(tok.Value)
+	if err != nil {
+		return tok, err
+	}
+	tok.Value = numeral
+	return tok, nil
+}
+
+func isOctal(token string) bool {
+	return len(token) > 1 && token[0] == '0' && (token[1] == 'o' || token[1] == 'O')
+}
+
+// resolveLiterals applies the parser options to the literals of a parsed filter and sets the value
+// of number literals from their numerals. The sign of a number is a separate token, so a unary plus
+// is dropped from the numeral here.
+func (p *Parser) resolveLiterals(expr *Expression) error {
+	var err error
+	walkValues(expr, func(val *Value) {
+		lit := val.Literal
+		if err != nil || lit == nil {
+			return
+		}
+
+		lit.escapes = p.opts.escapes
+
+		if lit.Hex != nil && p.opts.hexNumbers {
+			n, ok := new(big.Int).SetString(strings.ReplaceAll((*lit.Hex)[2:], "_", ""), 16)
+			if !ok {
+				err = fmt.Errorf("invalid hex literal %s", *lit.Hex)
+				return
+			}
+			numeral := n.String()
+			lit.Hex, lit.Numeral = nil, &numeral
+		}
+
+		if lit.Numeral != nil {
+			numeral := strings.TrimPrefix(*lit.Numeral, "+")
+			lit.Numeral = &numeral
+
+			num, perr := strconv.ParseFloat(*lit.Numeral, 64)
+			if perr != nil {
+				err = fmt.Errorf("invalid number %s", *lit.Numeral)
+				return
+			}
+			lit.Number = &num
+		}
+	})
+	return err
+}
@@ -0,0 +1,223 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumericLiterals(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		opts       []where.ParserOption
+		wantParams []any
+		wantErr    string
+	}{
+		{name: "underscores", input: "n = 1_000_000", wantParams: []any{float64(1000000)}},
+		{name: "underscores in decimals", input: "n = 1_000.000_5", wantParams: []any{1000.0005}},
+		{name: "octal", input: "mode = 0o755", wantParams: []any{float64(493)}},
+		{name: "negative octal", input: "n = -0O1_7", wantParams: []any{float64(-15)}},
+		{name: "octal in IN list", input: "mode IN (0o644, 0o755)", wantParams: []any{float64(420), float64(493)}},
+		{name: "hex is binary by default", input: "data = 0xFF", wantParams: []any{[]byte{0xFF}}},
+		{name: "hex with underscores", input: "data = 0xCA_FE", wantParams: []any{[]byte{0xCA, 0xFE}}},
+		{
+			name:       "hex numbers",
+			input:      "flags = 0xFF OR flags IN (0x1_00, 10)",
+			opts:       []where.ParserOption{where.WithHexNumbers()},
+			wantParams: []any{float64(255), float64(256), float64(10)},
+		},
+		{
+			name:       "hex numbers in IN list",
+			input:      "flags IN (0xff, 0x10)",
+			opts:       []where.ParserOption{where.WithHexNumbers()},
+			wantParams: []any{float64(255), float64(16)},
+		},
+		{name: "invalid octal digit", input: "n = 0o758", wantErr: "failed to parse filter expression"},
+		{name: "trailing underscore", input: "n = 1_", wantErr: "failed to parse filter expression"},
+		{name: "double underscore", input: "n = 1__0", wantErr: "failed to parse filter expression"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := where.NewParser(tt.opts...)
+			require.NoError(t, err)
+
+			filter, err := parser.Parse(tt.input)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			_, params, err := filter.ToSQL("postgres")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantParams, params)
+		})
+	}
+}
+
+func TestNumericLiteralsFormat(t *testing.T) {
+	parser, err := where.NewParser(where.WithHexNumbers())
+	require.NoError(t, err)
+
+	filter, err := parser.Parse("a = 1_000 AND b = 0o10 AND c = 0x10")
+	require.NoError(t, err)
+	require.Equal(t, "a = 1000 AND b = 8 AND c = 16", filter.String())
+}
+
+func TestNumberType(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		numberType where.NumberType
+		wantParams []any
+		wantErr    string
+	}{
+		{
+			name:       "float64 by default",
+			input:      "ts = 20240115120000 AND id = 9007199254740993 AND price = 12.50",
+			numberType: where.NumberFloat64,
+			wantParams: []any{float64(20240115120000), float64(9007199254740992), 12.5},
+		},
+		{
+			name:       "int64",
+			input:      "ts = 20240115120000 AND id = 9007199254740993 AND price = 12.50 AND n = 1e6 AND m = -0o17",
+			numberType: where.NumberInt64,
+			wantParams: []any{int64(20240115120000), int64(9007199254740993), 12.5, int64(1000000), int64(-15)},
+		},
+		{
+			name:       "uint64 beyond the int64 range",
+			input:      "id IN (18446744073709551615, 1_000)",
+			numberType: where.NumberInt64,
+			wantParams: []any{uint64(18446744073709551615), int64(1000)},
+		},
+		{
+			name:       "integer beyond the uint64 range",
+			input:      "id = 18446744073709551616",
+			numberType: where.NumberInt64,
+			wantErr:    "integer 18446744073709551616 is out of range for int64 and uint64",
+		},
+		{
+			name:       "string",
+			input:      "price = 12.50 AND id = +99999999999999999999 AND n = 1e6",
+			numberType: where.NumberString,
+			wantParams: []any{"12.50", "99999999999999999999", "1e6"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			_, params, err := filter.ToSQL("clickhouse", where.WithNumberType(tt.numberType))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantParams, params)
+		})
+	}
+}
+
+func TestNumberTypeHexNumbers(t *testing.T) {
+	parser, err := where.NewParser(where.WithHexNumbers())
+	require.NoError(t, err)
+
+	filter, err := parser.Parse("id = 0xFFFFFFFFFFFFFFFF")
+	require.NoError(t, err)
+
+	_, params, err := filter.ToSQL("clickhouse", where.WithNumberType(where.NumberInt64))
+	require.NoError(t, err)
+	require.Equal(t, []any{uint64(0xFFFFFFFFFFFFFFFF)}, params)
+}
+
+func TestNumberTypeModifiedNumber(t *testing.T) {
+	filter, err := where.Parse("id = 9007199254740993")
+	require.NoError(t, err)
+
+	// Changing Number takes precedence over the numeral as written.
+	*filter.Expression.Or[0].And[0].Predicate.Operation.Compare.Right.Literal.Number = 5
+
+	_, params, err := filter.ToSQL("clickhouse", where.WithNumberType(where.NumberInt64))
+	require.NoError(t, err)
+	require.Equal(t, []any{int64(5)}, params)
+	require.Equal(t, "id = 5", filter.String())
+}
+
+func TestFormatLargeIntegers(t *testing.T) {
+	filter, err := where.Parse("ts = 20240115120000 AND id = 9007199254740993 AND n = 1e21 AND x = 1.50")
+	require.NoError(t, err)
+	require.Equal(t, "ts = 20240115120000 AND id = 9007199254740993 AND n = 1e+21 AND x = 1.5", filter.String())
+}
+
+func TestUnaryMinus(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantSQL    string
+		wantParams []any
+		wantFormat string
+		wantErr    string
+	}{
+		{
+			name:       "negative bounds",
+			input:      "x BETWEEN -5 AND -1",
+			wantSQL:    "x BETWEEN $1 AND $2",
+			wantParams: []any{float64(-5), float64(-1)},
+			wantFormat: "x BETWEEN -5 AND -1",
+		},
+		{
+			name:       "without spaces",
+			input:      "x=-1",
+			wantSQL:    "x = $1",
+			wantParams: []any{float64(-1)},
+			wantFormat: "x = -1",
+		},
+		{
+			name:       "space after the sign",
+			input:      "x > - 1.5",
+			wantSQL:    "x > $1",
+			wantParams: []any{-1.5},
+			wantFormat: "x > -1.5",
+		},
+		{
+			name:       "unary plus",
+			input:      "x IN (+1, -0o10, -1e-3)",
+			wantSQL:    "x IN ($1, $2, $3)",
+			wantParams: []any{float64(1), float64(-8), -0.001},
+			wantFormat: "x IN (1, -8, -0.001)",
+		},
+		{
+			name:       "negative literal on the left",
+			input:      "-5 < ROUND(x, -2)",
+			wantSQL:    "$1 < ROUND(x, $2)",
+			wantParams: []any{float64(-5), float64(-2)},
+			wantFormat: "-5 < ROUND(x, -2)",
+		},
+		{name: "binary minus", input: "a - 1 = 0", wantErr: "failed to parse filter expression"},
+		{name: "missing operator", input: "a -1", wantErr: "failed to parse filter expression"},
+		{name: "double sign", input: "a = --1", wantErr: "failed to parse filter expression"},
+		{name: "negated field", input: "a = -b", wantErr: "failed to parse filter expression"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantFormat, filter.String())
+
+			sql, params, err := filter.ToSQL("postgres")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantParams, params)
+		})
+	}
+}
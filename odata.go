@@ -0,0 +1,393 @@
+package where
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/pkg/errors"
+)
+
+var (
+	odataLexer = lexer.MustSimple([]lexer.SimpleRule{
+		{Name: "Whitespace", Pattern: `\s+`},
+		{Name: "String", Pattern: `'([^']|'')*'`},
+		{Name: "Guid", Pattern: `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`},
+		{Name: "DateTime", Pattern: `\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:\d{2})?)?`},
+		{Name: "Number", Pattern: `-?\d+(\.\d+)?([eE][-+]?\d+)?[mMdDfFlL]?\b`},
+		{Name: "Ident", Pattern: `[a-zA-Z_][a-zA-Z0-9_]*`},
+		{Name: "Slash", Pattern: `/`},
+		{Name: "LParen", Pattern: `\(`},
+		{Name: "RParen", Pattern: `\)`},
+		{Name: "Comma", Pattern: `,`},
+	})
+
+	odataSymbols = lexer.SymbolsByRune(odataLexer)
+
+	// odataComparisons maps OData comparison operators to SQL operators.
+	odataComparisons = map[string]string{"eq": "=", "ne": "!=", "gt": ">", "ge": ">=", "lt": "<", "le": "<="}
+
+	// odataFunctions maps OData functions returning values to SQL functions.
+	odataFunctions = map[string]string{
+		"tolower": "LOWER",
+		"toupper": "UPPER",
+		"length":  "LENGTH",
+		"trim":    "TRIM",
+		"round":   "ROUND",
+		"floor":   "FLOOR",
+		"ceiling": "CEIL",
+	}
+
+	// odataPatterns maps OData string functions returning booleans to the LIKE pattern matching
+	// them, with %s replaced by the escaped argument.
+	odataPatterns = map[string]string{"startswith": "%s%%", "endswith": "%%%s", "contains": "%%%s%%"}
+
+	// likeEscaper escapes the LIKE wildcards in a string matched literally.
+	likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+)
+
+type (
+	// odataParser is a recursive descent parser for OData $filter expressions, producing the AST
+	// directly.
+	odataParser struct {
+		tokens  []lexer.Token
+		pos     int
+		escapes EscapeMode
+	}
+)
+
+// ParseOData parses an OData $filter expression, such as age gt 18 and startswith(name,'Jo'), into
+// the same AST that Parse returns for the equivalent expression. The filter is validated according
+// to the parser's configured options.
+//
+// The comparison operators are eq, ne, gt, ge, lt, and le, along with in for lists such as
+// status in ('a','b'), combined with and, or, not, and parentheses. startswith, endswith, and
+// contains become LIKE conditions, and tolower, toupper, length, trim, round, floor, and ceiling
+// become SQL functions. Property paths such as address/city are written as address.city. Strings,
+// numbers, true, false, null, dates, timestamps, and GUIDs are supported; dates, timestamps, and
+// GUIDs are bound as strings. eq null and ne null become IS NULL and IS NOT NULL.
+//
+// Example:
+//
+//	filter, err := parser.ParseOData("age gt 18 and startswith(name,'Jo')")
+//	// same as parser.Parse("age > 18 AND name LIKE 'Jo%'")
+func (p *Parser) ParseOData(input string) (*Filter, error) {
+	filter, err := p.parseOData(input)
+	if err != nil && p.opts.onReject != nil {
+		meta, _ := rejectionMeta(err)
+		p.opts.onReject(input, err, meta)
+	}
+	return filter, err
+}
+
+func (p *Parser) parseOData(input string) (*Filter, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, newMessage(MsgEmptyFilter)
+	}
+
+	if err := p.precheck(input); err != nil {
+		return nil, errors.Wrapf(err, "filter validation failed")
+	}
+
+	lex, err := odataLexer.LexString("", input)
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+	tokens, err := lexer.ConsumeAll(lex)
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+
+	op := &odataParser{escapes: p.opts.escapes}
+	for _, tok := range tokens {
+		if odataSymbols[tok.Type] != "Whitespace" {
+			op.tokens = append(op.tokens, tok)
+		}
+	}
+
+	expr, err := op.expression()
+	if err == nil && !op.peek().EOF() {
+		err = op.errorf("unexpected %q", op.peek().Value)
+	}
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+
+	filter := &Filter{Expression: expr}
+	if err := p.resolve(filter); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// ParseOData is a convenience function that creates a default parser and parses the OData $filter
+// expression. See Parser.ParseOData.
+func ParseOData(input string) (*Filter, error) {
+	parser, err := NewParser()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create parser")
+	}
+	return parser.ParseOData(input)
+}
+
+// expression parses terms joined with or.
+func (op *odataParser) expression() (*Expression, error) {
+	expr := &Expression{}
+	for {
+		term, err := op.term()
+		if err != nil {
+			return nil, err
+		}
+		expr.Or = append(expr.Or, term)
+
+		if !op.keyword("or") {
+			return expr, nil
+		}
+	}
+}
+
+// term parses factors joined with and.
+func (op *odataParser) term() (*Term, error) {
+	term := &Term{}
+	for {
+		factor, err := op.factor()
+		if err != nil {
+			return nil, err
+		}
+		term.And = append(term.And, factor)
+
+		if !op.keyword("and") {
+			return term, nil
+		}
+	}
+}
+
+// factor parses a negation, a parenthesized expression, or a condition.
+func (op *odataParser) factor() (*Factor, error) {
+	if op.keyword("not") {
+		inner, err := op.factor()
+		if err != nil {
+			return nil, err
+		}
+		if inner.Not {
+			return &Factor{Not: true, SubExpr: &Expression{Or: []*Term{{And: []*Factor{inner}}}}}, nil
+		}
+		inner.Not = true
+		return inner, nil
+	}
+
+	if op.symbol("LParen") {
+		expr, err := op.expression()
+		if err != nil {
+			return nil, err
+		}
+		if !op.symbol("RParen") {
+			return nil, op.errorf("expected )")
+		}
+		return &Factor{SubExpr: expr}, nil
+	}
+
+	pred, err := op.predicate()
+	if err != nil {
+		return nil, err
+	}
+	return &Factor{Predicate: pred}, nil
+}
+
+// predicate parses a string function such as startswith, a comparison, an in list, or a boolean
+// property on its own.
+func (op *odataParser) predicate() (*Predicate, error) {
+	if name := strings.ToLower(op.peek().Value); odataPatterns[name] != "" && op.isCall() {
+		return op.pattern(name)
+	}
+
+	left, err := op.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	if sqlOp, ok := odataComparisons[strings.ToLower(op.peek().Value)]; ok && odataSymbols[op.peek().Type] == "Ident" {
+		op.pos++
+		right, err := op.operand()
+		if err != nil {
+			return nil, err
+		}
+
+		// OData's eq null and ne null test for null, which = NULL and != NULL never do in SQL.
+		if sqlOp == "=" || sqlOp == "!=" {
+			if left.Literal != nil && left.Literal.Null {
+				left, right = right, left
+			}
+			if right.Literal != nil && right.Literal.Null {
+				return &Predicate{Left: left, Operation: &Operation{IsNull: &IsNullOp{Is: "IS", Not: sqlOp == "!=", Null: "NULL"}}}, nil
+			}
+		}
+		return &Predicate{Left: left, Operation: &Operation{Compare: &CompareOp{
+			Operator: CompareOperator{Type: sqlOp},
+			Right:    right,
+		}}}, nil
+	}
+
+	if op.keyword("in") {
+		values, err := op.list()
+		if err != nil {
+			return nil, err
+		}
+		return &Predicate{Left: left, Operation: &Operation{In: &InOp{In: "IN", Values: values}}}, nil
+	}
+
+	return &Predicate{Left: left}, nil
+}
+
+// pattern parses startswith, endswith, or contains as a LIKE condition. The string argument is
+// matched literally, so its wildcards are escaped.
+func (op *odataParser) pattern(name string) (*Predicate, error) {
+	args, err := op.arguments(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != 2 || args[1].Literal == nil || args[1].Literal.String == nil {
+		return nil, errors.Errorf("%s requires a value and a string literal", name)
+	}
+
+	search := op.escapes.unquote(*args[1].Literal.String)
+	pattern := op.escapes.quote(fmt.Sprintf(odataPatterns[name], likeEscaper.Replace(search)))
+	return &Predicate{Left: args[0], Operation: &Operation{Like: &LikeOp{
+		Type:    LikeType{Operator: "LIKE"},
+		Pattern: &Value{Literal: &LiteralValue{String: &pattern}},
+	}}}, nil
+}
+
+// operand parses a literal, a function call, or a property path.
+func (op *odataParser) operand() (*Value, error) {
+	tok := op.peek()
+	switch odataSymbols[tok.Type] {
+	case "String":
+		op.pos++
+		quoted := op.escapes.quote(odataUnquote(tok.Value))
+		return &Value{Literal: &LiteralValue{String: &quoted}}, nil
+	case "DateTime", "Guid":
+		op.pos++
+		quoted := op.escapes.quote(tok.Value)
+		return &Value{Literal: &LiteralValue{String: &quoted}}, nil
+	case "Number":
+		op.pos++
+		numeral := strings.TrimRight(tok.Value, "mMdDfFlL")
+		return &Value{Literal: &LiteralValue{Numeral: &numeral}}, nil
+	case "Ident":
+	default:
+		if tok.EOF() {
+			return nil, op.errorf("unexpected end of filter")
+		}
+		return nil, op.errorf("unexpected %q", tok.Value)
+	}
+
+	switch strings.ToLower(tok.Value) {
+	case "true", "false":
+		op.pos++
+		value := strings.EqualFold(tok.Value, "true")
+		return &Value{Literal: &LiteralValue{Boolean: &BooleanLit{True: value, False: !value}}}, nil
+	case "null":
+		op.pos++
+		return &Value{Literal: &LiteralValue{Null: true}}, nil
+	}
+
+	if op.isCall() {
+		name := strings.ToLower(tok.Value)
+		sqlName, ok := odataFunctions[name]
+		if !ok {
+			return nil, op.errorf("unsupported function %s", tok.Value)
+		}
+		args, err := op.arguments(name)
+		if err != nil {
+			return nil, err
+		}
+		return &Value{Function: &FunctionCall{Name: sqlName, Args: args}}, nil
+	}
+
+	parts := []string{tok.Value}
+	for op.pos++; op.symbol("Slash"); op.pos++ {
+		if odataSymbols[op.peek().Type] != "Ident" {
+			return nil, op.errorf("expected property name")
+		}
+		parts = append(parts, op.peek().Value)
+	}
+	return &Value{Field: &FieldRef{Parts: parts}}, nil
+}
+
+// arguments parses the parenthesized arguments of a function call.
+func (op *odataParser) arguments(name string) ([]*Value, error) {
+	op.pos++
+	values, err := op.list()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid arguments to %s", name)
+	}
+	return values, nil
+}
+
+// list parses a parenthesized, comma separated list of operands.
+func (op *odataParser) list() ([]*Value, error) {
+	if !op.symbol("LParen") {
+		return nil, op.errorf("expected (")
+	}
+
+	var values []*Value
+	for {
+		val, err := op.operand()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, val)
+
+		if op.symbol("RParen") {
+			return values, nil
+		}
+		if !op.symbol("Comma") {
+			return nil, op.errorf("expected , or )")
+		}
+	}
+}
+
+// isCall returns true if the next token is an identifier followed by (.
+func (op *odataParser) isCall() bool {
+	return odataSymbols[op.peek().Type] == "Ident" && op.pos+1 < len(op.tokens) &&
+		odataSymbols[op.tokens[op.pos+1].Type] == "LParen"
+}
+
+// keyword consumes the next token if it is the keyword, ignoring case.
+func (op *odataParser) keyword(word string) bool {
+	if tok := op.peek(); odataSymbols[tok.Type] == "Ident" && strings.EqualFold(tok.Value, word) {
+		op.pos++
+		return true
+	}
+	return false
+}
+
+// symbol consumes the next token if it has the type.
+func (op *odataParser) symbol(name string) bool {
+	if odataSymbols[op.peek().Type] == name {
+		op.pos++
+		return true
+	}
+	return false
+}
+
+func (op *odataParser) peek() lexer.Token {
+	if op.pos < len(op.tokens) {
+		return op.tokens[op.pos]
+	}
+	return lexer.EOFToken(lexer.Position{})
+}
+
+func (op *odataParser) errorf(format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if tok := op.peek(); !tok.EOF() {
+		msg += fmt.Sprintf(" at position %d", tok.Pos.Offset+1)
+	}
+	return errors.New(msg)
+}
+
+// odataUnquote strips the quotes from an OData string literal, in which quotes are doubled.
+func odataUnquote(token string) string {
+	return strings.ReplaceAll(token[1:len(token)-1], "''", "'")
+}
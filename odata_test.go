@@ -0,0 +1,153 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOData(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "comparisons",
+			input: "age gt 18 and age le 65 and status ne 'closed' or score ge -1.5",
+			want:  "age > 18 AND age <= 65 AND status != 'closed' OR score >= -1.5",
+		},
+		{
+			name:  "string functions",
+			input: "startswith(name,'Jo') and endswith(email, '@example.com') and contains(tolower(title), '50%_off')",
+			want:  `name LIKE 'Jo%' AND email LIKE '%@example.com' AND LOWER(title) LIKE '%50\\%\\_off%'`,
+		},
+		{
+			name:  "grouping and negation",
+			input: "not (a eq 1 or b eq 2) and not startswith(c,'x') and not not d eq null",
+			want:  "NOT (a = 1 OR b = 2) AND NOT c LIKE 'x%' AND NOT (NOT d IS NULL)",
+		},
+		{
+			name:  "null",
+			input: "deleted_at eq null and null ne email and manager/id ne null",
+			want:  "deleted_at IS NULL AND email IS NOT NULL AND manager.id IS NOT NULL",
+		},
+		{
+			name:  "in",
+			input: "status in ('active', 'pending') and id in (1,2)",
+			want:  "status IN ('active', 'pending') AND id IN (1, 2)",
+		},
+		{
+			name:  "literals",
+			input: "name eq 'O''Brien' and active eq true and price lt 9.99M and created ge 2024-01-01T10:00:00Z and day eq 2024-02-29",
+			want:  "name = 'O''Brien' AND active = TRUE AND price < 9.99 AND created >= '2024-01-01T10:00:00Z' AND day = '2024-02-29'",
+		},
+		{
+			name:  "guid",
+			input: "id eq 01234567-89ab-cdef-0123-456789abcdef",
+			want:  "id = '01234567-89ab-cdef-0123-456789abcdef'",
+		},
+		{
+			name:  "paths and functions",
+			input: "toupper(address/city) eq 'PARIS' and length(trim(name)) gt 3 and ceiling(total) le floor(round(limit))",
+			want:  "UPPER(address.city) = 'PARIS' AND LENGTH(TRIM(name)) > 3 AND CEIL(total) <= FLOOR(ROUND(limit))",
+		},
+		{
+			name:  "boolean property",
+			input: "is_active AND NOT deleted",
+			want:  "is_active AND NOT deleted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.ParseOData(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, filter.String())
+
+			want, err := where.Parse(tt.want)
+			require.NoError(t, err)
+			requireEqualAST(t, want.Expression, filter.Expression)
+		})
+	}
+}
+
+func TestParseODataParams(t *testing.T) {
+	parser, err := where.NewParser(where.WithEscapeMode(where.EscapeStandard))
+	require.NoError(t, err)
+
+	filter, err := parser.ParseOData(`contains(path,'C:\tmp') and name eq 'it''s'`)
+	require.NoError(t, err)
+
+	sql, params, err := filter.ToSQL("postgres")
+	require.NoError(t, err)
+	require.Equal(t, "(path LIKE $1 AND name = $2)", sql)
+	require.Equal(t, []any{`%C:\\tmp%`, "it's"}, params)
+}
+
+func TestParseODataErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "empty",
+			input: " ",
+			err:   "empty filter expression",
+		},
+		{
+			name:  "missing operand",
+			input: "a eq",
+			err:   "failed to parse filter expression: unexpected end of filter",
+		},
+		{
+			name:  "unsupported function",
+			input: "a eq 1 and substringof('x', b)",
+			err:   "failed to parse filter expression: unsupported function substringof at position 12",
+		},
+		{
+			name:  "trailing input",
+			input: "a eq 1)",
+			err:   `failed to parse filter expression: unexpected ")" at position 7`,
+		},
+		{
+			name:  "unbalanced parentheses",
+			input: "(a eq 1",
+			err:   "failed to parse filter expression: expected )",
+		},
+		{
+			name:  "string function arguments",
+			input: "startswith(name)",
+			err:   "failed to parse filter expression: startswith requires a value and a string literal",
+		},
+		{
+			name:  "in list",
+			input: "a in 1",
+			err:   "failed to parse filter expression: expected ( at position 6",
+		},
+		{
+			name:  "invalid token",
+			input: "a eq 'x",
+			err:   `failed to parse filter expression: 1:6: lexer: invalid input text "'x"`,
+		},
+		{
+			name:  "empty in list",
+			input: "a in ()",
+			err:   `failed to parse filter expression: unexpected ")" at position 7`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := where.ParseOData(tt.input)
+			require.EqualError(t, err, tt.err)
+		})
+	}
+
+	parser, err := where.NewParser(where.WithFunctions("LOWER"))
+	require.NoError(t, err)
+	_, err = parser.ParseOData("toupper(name) eq 'X'")
+	require.EqualError(t, err, `filter validation failed: function "UPPER" is not allowed`)
+}
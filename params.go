@@ -0,0 +1,195 @@
+package where
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type (
+	// bytesKey makes byte slice parameters usable as map keys.
+	bytesKey string
+)
+
+// WithParamDeduplication returns a BuildOption that binds repeated literal values to a single
+// parameter. It only applies to drivers with numbered placeholders (e.g. $1 in PostgreSQL);
+// positional placeholders (?) must be bound once per occurrence and are left unchanged.
+func WithParamDeduplication() BuildOption {
+	return func(b *SQLBuilder) {
+		b.dedupParams = make(map[any]int)
+	}
+}
+
+// WithBindConstants returns a BuildOption that binds TRUE, FALSE, and NULL literals as parameters
+// instead of inlining them, so the SQL text only depends on the structure of the filter and every
+// compared value appears in the parameter list. IS NULL and IS NOT NULL are unaffected.
+func WithBindConstants() BuildOption {
+	return func(b *SQLBuilder) {
+		b.bindConsts = true
+	}
+}
+
+// WithMaxParams returns a BuildOption that limits the number of parameters the generated SQL may
+// bind. It overrides the limit declared by drivers implementing ParamLimiter, which is useful for
+// databases with lower limits (e.g. 2100 for SQL Server). Zero uses the driver's limit.
+func WithMaxParams(limit int) BuildOption {
+	return func(b *SQLBuilder) {
+		b.maxParams = limit
+	}
+}
+
+// WithINChunkSize returns a BuildOption that splits IN lists with more than size values into
+// groups, e.g. (col IN (...) OR col IN (...)). NOT IN lists are joined with AND instead.
+// Only IN lists compared against a field are chunked.
+func WithINChunkSize(size int) BuildOption {
+	return func(b *SQLBuilder) {
+		b.inChunkSize = size
+	}
+}
+
+// WithArrayBinding returns a BuildOption that binds IN lists of string and number literals as a
+// single array parameter, e.g. col = ANY($1) in PostgreSQL, for drivers implementing ArrayBinder.
+// Lists containing fields, functions, booleans, or NULL are rendered as regular IN lists.
+func WithArrayBinding() BuildOption {
+	return func(b *SQLBuilder) {
+		b.arrayBinding = true
+	}
+}
+
+// addParam binds the value and returns its placeholder, reusing an earlier parameter with the
+// same value when deduplication is enabled.
+func (b *SQLBuilder) addParam(param any) string {
+	if b.dedupParams == nil || b.placeholder(1) == b.placeholder(2) {
+		return b.placeholder(b.appendParam(param))
+	}
+
+	key := param
+	if data, ok := param.([]byte); ok {
+		key = bytesKey(data)
+	}
+	if t := reflect.TypeOf(key); t != nil && !t.Comparable() {
+		return b.placeholder(b.appendParam(param))
+	}
+
+	if position, ok := b.dedupParams[key]; ok {
+		return b.placeholder(position)
+	}
+
+	position := b.appendParam(param)
+	b.dedupParams[key] = position
+	return b.placeholder(position)
+}
+
+// placeholder returns the placeholder for the parameter at position.
+func (b *SQLBuilder) placeholder(position int) string {
+	if b.positional {
+		return "?"
+	}
+	return b.driver.Placeholder(b.paramBase + position)
+}
+
+// appendParam binds a new parameter and returns its position.
+func (b *SQLBuilder) appendParam(param any) int {
+	b.params = append(b.params, param)
+	position := len(b.params)
+
+	if b.typed {
+		b.typedParams = append(b.typedParams, Param{
+			Name:  b.placeholder(position),
+			Value: param,
+			Field: b.paramField,
+			Kind:  paramKind(param),
+		})
+	}
+	return position
+}
+
+// checkParamLimit returns an error if more parameters were bound than the driver allows.
+func (b *SQLBuilder) checkParamLimit() error {
+	limit := b.maxParams
+	if limiter, ok := DriverAs[ParamLimiter](b.driver); ok && limit == 0 {
+		limit = limiter.MaxParams()
+	}
+
+	if count := b.paramBase + len(b.params); limit > 0 && count > limit {
+		return fmt.Errorf("filter requires %d parameters, exceeding the maximum of %d for driver %s",
+			count, limit, b.driver.Name())
+	}
+	return nil
+}
+
+// buildArrayIn renders the IN list as membership of a single array parameter. It reports false
+// if the driver cannot bind arrays or the list contains values that cannot be bound together.
+func (b *SQLBuilder) buildArrayIn(leftVal string, in *InOp) (string, bool, error) {
+	binder, ok := DriverAs[ArrayBinder](b.driver)
+	if !ok {
+		b.warnf(WarnArrayBindingSkipped, "IN list was not bound as an array because driver %s cannot bind arrays", b.driver.Name())
+		return "", false, nil
+	}
+
+	if !isBindableList(in.Values) {
+		b.warnf(WarnArrayBindingSkipped, "IN list was not bound as an array because it contains NULL or boolean values")
+		return "", false, nil
+	}
+
+	values := make([]any, len(in.Values))
+	for i, item := range in.Values {
+		value, err := b.literalParam(item.Literal)
+		if err != nil {
+			return "", false, err
+		}
+		values[i] = value
+	}
+
+	placeholder := b.addParam(arrayParam(values))
+	return binder.ArrayMembership(leftVal, placeholder, in.Not), true, nil
+}
+
+// isBindableList returns true if every value is a string, number, or hex literal.
+func isBindableList(values []*Value) bool {
+	for _, item := range values {
+		if item == nil || item.Literal == nil || item.Literal.Null || item.Literal.Boolean != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayParam returns the values as a typed slice when they are all strings or all numbers, so
+// database drivers can infer the array element type.
+func arrayParam(values []any) any {
+	strs := make([]string, 0, len(values))
+	nums := make([]float64, 0, len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			strs = append(strs, v)
+		case float64:
+			nums = append(nums, v)
+		}
+	}
+
+	switch len(values) {
+	case len(strs):
+		return strs
+	case len(nums):
+		return nums
+	default:
+		return values
+	}
+}
+
+// chunkIn splits the IN list items into groups of at most size values.
+func chunkIn(leftVal, sqlOp string, items []string, size int) string {
+	connective := " OR "
+	if sqlOp == "NOT IN" {
+		connective = " AND "
+	}
+
+	var parts []string
+	for start := 0; start < len(items); start += size {
+		end := min(start+size, len(items))
+		parts = append(parts, fmt.Sprintf("%s %s (%s)", leftVal, sqlOp, strings.Join(items[start:end], ", ")))
+	}
+	return "(" + strings.Join(parts, connective) + ")"
+}
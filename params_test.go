@@ -0,0 +1,257 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithParamDeduplication(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		input    string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "repeated values share a parameter",
+			driver:   "postgres",
+			input:    "(status IN ('a', 'b') AND age > 18) OR (status IN ('a', 'b') AND score > 18)",
+			wantSQL:  "((status IN ($1, $2) AND age > $3) OR (status IN ($1, $2) AND score > $3))",
+			wantArgs: []any{"a", "b", float64(18)},
+		},
+		{
+			name:     "values of different types are distinct",
+			driver:   "postgres",
+			input:    "a = '1' OR b = 1 OR c = 0x01 OR d = 0x01",
+			wantSQL:  "(a = $1 OR b = $2 OR c = $3 OR d = $3)",
+			wantArgs: []any{"1", float64(1), []byte{0x01}},
+		},
+		{
+			name:     "positional placeholders are unchanged",
+			driver:   "mysql",
+			input:    "a = 'x' OR b = 'x'",
+			wantSQL:  "(a = ? OR b = ?)",
+			wantArgs: []any{"x", "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			sql, args, err := filter.ToSQL(tt.driver, where.WithParamDeduplication())
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestParamLimits(t *testing.T) {
+	filter, err := where.Parse("a IN (1, 2, 3)")
+	require.NoError(t, err)
+
+	_, _, err = filter.ToSQL("postgres", where.WithMaxParams(2))
+	require.EqualError(t, err, "filter requires 3 parameters, exceeding the maximum of 2 for driver postgres")
+
+	_, args, err := filter.ToSQL("postgres", where.WithMaxParams(3))
+	require.NoError(t, err)
+	require.Len(t, args, 3)
+
+	// Array binding uses a single parameter for the whole list.
+	_, args, err = filter.ToSQL("postgres", where.WithMaxParams(2), where.WithArrayBinding())
+	require.NoError(t, err)
+	require.Len(t, args, 1)
+}
+
+func TestDriverParamLimits(t *testing.T) {
+	for _, name := range []string{"postgres", "mysql"} {
+		driver, err := where.GetDriver(name)
+		require.NoError(t, err)
+
+		limiter, ok := driver.(where.ParamLimiter)
+		require.True(t, ok)
+		require.Equal(t, 65535, limiter.MaxParams())
+	}
+
+	driver, err := where.GetDriver("clickhouse")
+	require.NoError(t, err)
+	_, ok := driver.(where.ParamLimiter)
+	require.False(t, ok)
+}
+
+func TestWithINChunkSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantSQL string
+	}{
+		{
+			name:    "IN list chunked with OR",
+			input:   "id IN (1, 2, 3, 4, 5)",
+			wantSQL: "(id IN ($1, $2) OR id IN ($3, $4) OR id IN ($5))",
+		},
+		{
+			name:    "NOT IN list chunked with AND",
+			input:   "id NOT IN (1, 2, 3)",
+			wantSQL: "(id NOT IN ($1, $2) AND id NOT IN ($3))",
+		},
+		{
+			name:    "small list unchanged",
+			input:   "id IN (1, 2)",
+			wantSQL: "id IN ($1, $2)",
+		},
+		{
+			name:    "function left side unchanged",
+			input:   "LOWER(name) IN ('a', 'b', 'c')",
+			wantSQL: "LOWER(name) IN ($1, $2, $3)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			sql, _, err := filter.ToSQL("postgres", where.WithINChunkSize(2))
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+		})
+	}
+}
+
+func TestWithArrayBinding(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		input    string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "postgres strings",
+			driver:   "postgres",
+			input:    "status IN ('a', 'b') AND age > 18",
+			wantSQL:  "(status = ANY($1) AND age > $2)",
+			wantArgs: []any{[]string{"a", "b"}, float64(18)},
+		},
+		{
+			name:     "postgres NOT IN numbers",
+			driver:   "postgres",
+			input:    "id NOT IN (1, 2)",
+			wantSQL:  "id <> ALL($1)",
+			wantArgs: []any{[]float64{1, 2}},
+		},
+		{
+			name:     "mixed types",
+			driver:   "postgres",
+			input:    "id IN (1, 'a')",
+			wantSQL:  "id = ANY($1)",
+			wantArgs: []any{[]any{float64(1), "a"}},
+		},
+		{
+			name:     "non-literal values",
+			driver:   "postgres",
+			input:    "id IN (1, other_id)",
+			wantSQL:  "id IN ($1, other_id)",
+			wantArgs: []any{float64(1)},
+		},
+		{
+			name:     "clickhouse",
+			driver:   "clickhouse",
+			input:    "status NOT IN ('a', 'b')",
+			wantSQL:  "NOT has(?, status)",
+			wantArgs: []any{[]string{"a", "b"}},
+		},
+		{
+			name:     "unsupported driver",
+			driver:   "mysql",
+			input:    "status IN ('a', 'b')",
+			wantSQL:  "status IN (?, ?)",
+			wantArgs: []any{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			sql, args, err := filter.ToSQL(tt.driver, where.WithArrayBinding(), where.WithParamDeduplication())
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+// limitedDriver is a MockDriver that declares a parameter limit.
+type limitedDriver struct {
+	MockDriver
+}
+
+func (d *limitedDriver) MaxParams() int { return 2 }
+
+func TestDriverDeclaredParamLimit(t *testing.T) {
+	where.RegisterDriver("limited", &limitedDriver{MockDriver{name: "limited"}})
+
+	filter, err := where.Parse("a = 'x' OR b = 'x' OR c = 'y'")
+	require.NoError(t, err)
+
+	_, _, err = filter.ToSQL("limited")
+	require.EqualError(t, err, "filter requires 3 parameters, exceeding the maximum of 2 for driver limited")
+
+	_, _, err = filter.ToSQL("limited", where.WithMaxParams(3))
+	require.NoError(t, err)
+}
+
+func TestWithBindConstants(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		input    string
+		opts     []where.BuildOption
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "booleans and NULL",
+			driver:   "postgres",
+			input:    "active = true AND deleted = FALSE AND parent_id = NULL AND name = 'x'",
+			wantSQL:  "(active = $1 AND deleted = $2 AND parent_id = $3 AND name = $4)",
+			wantArgs: []any{true, false, nil, "x"},
+		},
+		{
+			name:     "IS NULL unchanged",
+			driver:   "mysql",
+			input:    "deleted_at IS NULL AND active = true",
+			wantSQL:  "(deleted_at IS NULL AND active = ?)",
+			wantArgs: []any{true},
+		},
+		{
+			name:     "deduplicated",
+			driver:   "postgres",
+			input:    "a = true OR b = true OR c = NULL OR d = NULL",
+			opts:     []where.BuildOption{where.WithParamDeduplication()},
+			wantSQL:  "(a = $1 OR b = $1 OR c = $2 OR d = $2)",
+			wantArgs: []any{true, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			opts := append([]where.BuildOption{where.WithBindConstants()}, tt.opts...)
+			sql, args, err := filter.ToSQL(tt.driver, opts...)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
@@ -2,24 +2,49 @@ package where
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
 	"github.com/pkg/errors"
 )
 
+// parenDepthAllowance is the number of parentheses levels allowed beyond the maximum expression
+// depth, accounting for function calls and IN lists which also use parentheses.
+const parenDepthAllowance = 4
+
 type (
 	// Parser represents a configured filter expression parser with validation options.
 	Parser struct {
-		parser *participle.Parser[Filter]
-		opts   *parserOptions
+		parser  *participle.Parser[Filter]
+		lexer   *lexer.StatefulDefinition
+		symbols map[lexer.TokenType]string
+		macros  map[string]*Expression
+		opts    *parserOptions
 	}
 
 	// parserOptions holds configuration options for the parser.
 	parserOptions struct {
-		maxDepth     int
-		maxINItems   int
-		allowedFuncs map[string]bool
+		maxDepth       int
+		maxINItems     int
+		maxComplexity  int
+		maxORBranches  int
+		maxNegations   int
+		maxInputLength int
+		parseTimeout   time.Duration
+		escapes        EscapeMode
+		allowedFuncs   map[string]bool
+		portableFuncs  bool
+		validateArgs   bool
+		freeze         bool
+		allowEmptyIN   bool
+		hexNumbers     bool
+		macros         map[string]string
+		onReject       RejectionHandler
 	}
 
 	// ParserOption is a function type for configuring parser options.
@@ -40,6 +65,58 @@ func WithMaxINItems(max int) ParserOption {
 	}
 }
 
+// WithEmptyIN returns a ParserOption that accepts empty IN lists such as "id IN ()". An empty IN
+// matches no rows and renders as FALSE, and an empty NOT IN matches every row and renders as TRUE.
+func WithEmptyIN() ParserOption {
+	return func(o *parserOptions) {
+		o.allowEmptyIN = true
+	}
+}
+
+// WithMaxInputLength returns a ParserOption that sets the maximum length (in bytes) of filter expressions.
+// The default is 1 MiB. A value of zero disables the check.
+func WithMaxInputLength(max int) ParserOption {
+	return func(o *parserOptions) {
+		o.maxInputLength = max
+	}
+}
+
+// WithEscapeMode returns a ParserOption that selects how escape sequences in string literals are
+// interpreted. The default is EscapeBackslash; use EscapeStandard for SQL-standard strings where
+// backslash has no special meaning.
+func WithEscapeMode(mode EscapeMode) ParserOption {
+	return func(o *parserOptions) {
+		o.escapes = mode
+	}
+}
+
+// WithMaxComplexity returns a ParserOption that rejects filters whose EstimateComplexity score exceeds max.
+// A value of zero (the default) disables the check.
+func WithMaxComplexity(max int) ParserOption {
+	return func(o *parserOptions) {
+		o.maxComplexity = max
+	}
+}
+
+// WithMaxORBranches returns a ParserOption that sets the maximum number of conditions an OR may join,
+// including ORs in parentheses and in function arguments. Wide ORs are shallow, so WithMaxDepth
+// does not limit them. A value of zero (the default) disables the check.
+func WithMaxORBranches(max int) ParserOption {
+	return func(o *parserOptions) {
+		o.maxORBranches = max
+	}
+}
+
+// WithMaxNegations returns a ParserOption that sets the maximum number of NOT operators applied to
+// conditions or groups in a filter, e.g. NOT (a = 1 OR NOT (b = 2)) has two. Negated groups grow
+// when rewritten with ToNNF or ToDNF. NOT IN, NOT LIKE, NOT BETWEEN, and IS NOT NULL are not
+// counted. A value of zero (the default) disables the check.
+func WithMaxNegations(max int) ParserOption {
+	return func(o *parserOptions) {
+		o.maxNegations = max
+	}
+}
+
 // WithFunctions returns a ParserOption that restricts which functions are allowed in expressions.
 // This provides parse-time validation - note that all functions are supported at the driver level.
 // Use the Validator for runtime validation instead for more comprehensive security.
@@ -54,18 +131,28 @@ func WithFunctions(names ...string) ParserOption {
 	}
 }
 
+// WithFunctionArgValidation returns a ParserOption that checks calls to functions listed in
+// StandardFunctions or PortableFunctions against their argument counts, and rejects non-numeric
+// literal arguments to math functions. Functions without metadata are not checked.
+func WithFunctionArgValidation() ParserOption {
+	return func(o *parserOptions) {
+		o.validateArgs = true
+	}
+}
+
 // NewParser creates a new parser with the specified options.
 func NewParser(opts ...ParserOption) (*Parser, error) {
 	options := &parserOptions{
-		maxDepth:   10,
-		maxINItems: 1000,
+		maxDepth:       10,
+		maxINItems:     1000,
+		maxInputLength: 1 << 20,
 	}
 
 	for _, opt := range opts {
 		opt(options)
 	}
 
-	lex, err := NewLexer()
+	lex, err := newLexer(options.escapes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create lexer: %w", err)
 	}
@@ -74,49 +161,136 @@ func NewParser(opts ...ParserOption) (*Parser, error) {
 		participle.Lexer(lex),
 		participle.Elide("Whitespace"),
 		participle.UseLookahead(5),
+		participle.CaseInsensitive("Ident"),
+		participle.Map(normalizeNumber, "Number"),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build parser: %w", err)
 	}
 
-	return &Parser{
-		parser: parser,
-		opts:   options,
-	}, nil
+	p := &Parser{
+		parser:  parser,
+		lexer:   lex,
+		symbols: lexer.SymbolsByRune(lex),
+		opts:    options,
+	}
+	if err := p.compileMacros(); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
 // Parse parses a filter expression string and returns the parsed Filter AST.
 // The input is validated according to the parser's configured options.
 func (p *Parser) Parse(input string) (*Filter, error) {
-	if input == "" {
-		return nil, errors.New("empty filter expression")
+	filter, err := p.parse(input)
+	if err != nil && p.opts.onReject != nil {
+		meta, _ := rejectionMeta(err)
+		p.opts.onReject(input, err, meta)
 	}
+	return filter, err
+}
 
-	filter, err := p.parser.ParseString("", input)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to parse filter expression")
+func (p *Parser) parse(input string) (*Filter, error) {
+	if input == "" {
+		return nil, newMessage(MsgEmptyFilter)
 	}
 
-	if err := p.validate(filter); err != nil {
+	if err := p.precheck(input); err != nil {
 		return nil, errors.Wrapf(err, "filter validation failed")
 	}
 
+	filter, ok := p.parseINList(input)
+	if !ok {
+		var err error
+		filter, err = p.parseGrammar(input)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expandMacros(filter.Expression); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.resolve(filter); err != nil {
+		return nil, err
+	}
+
+	if p.opts.freeze {
+		filter.Freeze()
+	}
 	return filter, nil
 }
 
+// resolve applies the post-parse fixups to a filter's AST and validates it.
+func (p *Parser) resolve(filter *Filter) error {
+	resolveBitwise(filter.Expression)
+
+	if err := p.resolveLiterals(filter.Expression); err != nil {
+		return errors.Wrapf(err, "failed to parse filter expression")
+	}
+
+	if err := resolveBooleans(filter.Expression); err != nil {
+		return errors.Wrapf(err, "failed to parse filter expression")
+	}
+
+	if err := p.validate(filter); err != nil {
+		return errors.Wrapf(err, "filter validation failed")
+	}
+
+	return nil
+}
+
+// precheck rejects pathological inputs before they reach the parser. Deeply nested parentheses
+// cause the backtracking parser to take exponential time, so the raw nesting of parentheses is
+// limited to the maximum expression depth plus an allowance for function calls and IN lists.
+func (p *Parser) precheck(input string) error {
+	if p.opts.maxInputLength > 0 && len(input) > p.opts.maxInputLength {
+		return rejected(newMessage(MsgInputLength, "max", p.opts.maxInputLength),
+			RejectionMeta{Rule: RuleInputLength})
+	}
+
+	if parenDepth(input, p.opts.escapes) > p.opts.maxDepth+parenDepthAllowance {
+		return rejected(newMessage(MsgDepth, "max", p.opts.maxDepth), RejectionMeta{Rule: RuleDepth})
+	}
+
+	return nil
+}
+
 func (p *Parser) validate(filter *Filter) error {
-	return p.validateExpression(filter.Expression, 0)
+	if err := p.validateExpression(filter.Expression, 0); err != nil {
+		return err
+	}
+
+	if p.opts.maxNegations > 0 {
+		if n := countNegations(filter.Expression); n > p.opts.maxNegations {
+			return rejected(newMessage(MsgNegations, "max", p.opts.maxNegations), RejectionMeta{Rule: RuleNegations})
+		}
+	}
+
+	if p.opts.maxComplexity > 0 {
+		if score := EstimateComplexity(filter).Score; score > p.opts.maxComplexity {
+			return rejected(newMessage(MsgComplexity, "score", score, "max", p.opts.maxComplexity),
+				RejectionMeta{Rule: RuleComplexity})
+		}
+	}
+
+	return nil
 }
 
 func (p *Parser) validateExpression(expr *Expression, depth int) error {
 	if depth > p.opts.maxDepth {
-		return fmt.Errorf("expression depth exceeds maximum of %d", p.opts.maxDepth)
+		return rejected(newMessage(MsgDepth, "max", p.opts.maxDepth), RejectionMeta{Rule: RuleDepth})
 	}
 
 	if expr == nil || len(expr.Or) == 0 {
 		return nil
 	}
 
+	if p.opts.maxORBranches > 0 && len(expr.Or) > p.opts.maxORBranches {
+		return rejected(newMessage(MsgORBranches, "max", p.opts.maxORBranches), RejectionMeta{Rule: RuleORBranches})
+	}
+
 	for _, term := range expr.Or {
 		if err := p.validateTerm(term, depth); err != nil {
 			return err
@@ -150,19 +324,19 @@ func (p *Parser) validateFactor(factor *Factor, depth int) error {
 	}
 
 	if factor.Predicate != nil {
-		return p.validatePredicate(factor.Predicate)
+		return p.validatePredicate(factor.Predicate, depth)
 	}
 
 	return errors.New("empty factor content")
 }
 
-func (p *Parser) validatePredicate(pred *Predicate) error {
+func (p *Parser) validatePredicate(pred *Predicate, depth int) error {
 	if pred == nil {
 		return errors.New("empty predicate")
 	}
 
 	// Validate the left side (field/function/literal)
-	if err := p.validateValue(pred.Left); err != nil {
+	if err := p.validateValue(pred.Left, depth); err != nil {
 		return err
 	}
 
@@ -170,39 +344,44 @@ func (p *Parser) validatePredicate(pred *Predicate) error {
 		return errors.New("predicate missing operation")
 	}
 
-	return p.validateOperation(pred.Operation)
+	return p.validateOperation(pred.Operation, depth)
 }
 
-func (p *Parser) validateOperation(op *Operation) error {
+func (p *Parser) validateOperation(op *Operation, depth int) error {
 	if op == nil {
 		return errors.New("empty operation")
 	}
 
 	if op.Compare != nil {
-		return p.validateValue(op.Compare.Right)
+		return p.validateValue(op.Compare.Right, depth)
 	}
 
 	if op.Like != nil {
-		return p.validateValue(op.Like.Pattern)
+		return p.validateValue(op.Like.Pattern, depth)
+	}
+
+	if op.Match != nil {
+		return p.validateValue(op.Match.Query, depth)
 	}
 
 	if op.Between != nil {
-		if err := p.validateValue(op.Between.Lower); err != nil {
+		if err := p.validateValue(op.Between.Lower, depth); err != nil {
 			return err
 		}
-		return p.validateValue(op.Between.Upper)
+		return p.validateValue(op.Between.Upper, depth)
 	}
 
 	if op.In != nil {
-		if len(op.In.Values) == 0 {
-			return errors.New("IN expression requires at least one value")
+		if len(op.In.Values) == 0 && !p.opts.allowEmptyIN {
+			return newMessage(MsgINEmpty)
 		}
 		if len(op.In.Values) > p.opts.maxINItems {
-			return fmt.Errorf("IN expression exceeds maximum of %d items", p.opts.maxINItems)
+			return rejected(newMessage(MsgINItems, "max", p.opts.maxINItems),
+				RejectionMeta{Rule: RuleINItems})
 		}
 
 		for _, value := range op.In.Values {
-			if err := p.validateValue(value); err != nil {
+			if err := p.validateValue(value, depth); err != nil {
 				return err
 			}
 		}
@@ -217,32 +396,106 @@ func (p *Parser) validateOperation(op *Operation) error {
 	return errors.New("operation has no valid type")
 }
 
-func (p *Parser) validateValue(val *Value) error {
+func (p *Parser) validateValue(val *Value, depth int) error {
 	if val == nil {
 		return nil
 	}
 
+	if val.Field != nil {
+		if err := checkFieldParts(val.Field); err != nil {
+			return err
+		}
+	}
+
 	if val.Function != nil {
+		if err := checkCast(val.Function); err != nil {
+			return err
+		}
+		if err := checkIf(val.Function); err != nil {
+			return err
+		}
+		if err := checkStar(val.Function); err != nil {
+			return err
+		}
+
 		if p.opts.allowedFuncs != nil {
 			if !p.opts.allowedFuncs[strings.ToUpper(val.Function.Name)] {
-				return fmt.Errorf("function %q is not allowed", val.Function.Name)
+				msg := newMessage(MsgFunctionNotAllowed, "function", strconv.Quote(val.Function.Name))
+				return rejected(withSuggestion(msg, val.Function.Name, slices.Collect(maps.Keys(p.opts.allowedFuncs))),
+					RejectionMeta{Rule: RuleFunction, Function: val.Function.Name})
+			}
+		}
+
+		if p.opts.portableFuncs {
+			if err := checkPortable(val.Function); err != nil {
+				return rejected(err, RejectionMeta{Rule: RuleFunction, Function: val.Function.Name})
+			}
+		}
+
+		if p.opts.validateArgs {
+			if err := checkFunctionArgs(val.Function); err != nil {
+				return rejected(err, RejectionMeta{Rule: RuleFunction, Function: val.Function.Name})
+			}
+		}
+
+		for _, arg := range val.Function.arguments() {
+			if err := p.validateValue(arg, depth); err != nil {
+				return err
 			}
 		}
+	}
 
-		for _, arg := range val.Function.Args {
-			if err := p.validateValue(arg); err != nil {
+	if val.Bitwise != nil {
+		if err := p.validateValue(val.Bitwise.Left, depth); err != nil {
+			return err
+		}
+		for _, op := range val.Bitwise.Ops {
+			if err := p.validateValue(op.Right, depth); err != nil {
 				return err
 			}
 		}
 	}
 
 	if val.SubExpr != nil {
-		return p.validateExpression(val.SubExpr, 0)
+		return p.validateExpression(val.SubExpr, depth+1)
 	}
 
 	return nil
 }
 
+// checkFieldParts returns an error if a part of the field has no name once its quotes and spaces are
+// removed, e.g. a backtick quoted double quote, which drivers cannot quote as a column name.
+func checkFieldParts(field *FieldRef) error {
+	for _, part := range field.Parts {
+		if strings.Trim(unquoteIdentifier(part), " \t\r\n`\"") == "" {
+			return newMessage(MsgIdentifierEmpty, "identifier", strconv.Quote(part))
+		}
+	}
+	return nil
+}
+
+// parseString parses the input with the grammar, names the IF calls read with a condition, see
+// FunctionCall, and moves the parenthesized groups read as predicates to their factors, see Factor.
+func (p *Parser) parseString(input string, opts ...participle.ParseOption) (*Filter, error) {
+	filter, err := p.parser.ParseString("", input, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	walkValues(filter.Expression, func(val *Value) {
+		if fn := val.Function; fn != nil && fn.Cond != nil && fn.Name == "" {
+			fn.Name = input[fn.Pos.Offset : fn.Pos.Offset+len("IF")]
+		}
+	})
+	_ = walkFactors(filter.Expression, func(factor *Factor) error {
+		if pred := factor.Predicate; pred != nil && pred.Operation == nil && pred.Left.SubExpr != nil && pred.Left.Bitwise == nil {
+			factor.SubExpr, factor.Predicate = pred.Left.SubExpr, nil
+		}
+		return nil
+	})
+	return filter, nil
+}
+
 // Parse is a convenience function that creates a default parser and parses the input.
 // For more control over parsing options, create a parser with NewParser.
 func Parse(input string) (*Filter, error) {
@@ -252,3 +505,48 @@ func Parse(input string) (*Filter, error) {
 	}
 	return parser.Parse(input)
 }
+
+// parenDepth returns the maximum nesting of parentheses in the input, ignoring quoted strings and identifiers.
+// Backslash escapes a character in strings only in the EscapeBackslash mode, and never in backtick
+// quoted identifiers.
+func parenDepth(input string, escapes EscapeMode) int {
+	depth, maxDepth := 0, 0
+	var quote byte
+
+	for i := 0; i < len(input); i++ {
+		ch := input[i]
+		if quote != 0 {
+			switch {
+			case ch == '\\' && quote != '`' && escapes == EscapeBackslash:
+				i++
+			case ch == quote:
+				quote = 0
+			}
+			continue
+		}
+
+		switch ch {
+		case '\'', '"', '`':
+			quote = ch
+		case '(':
+			depth++
+			maxDepth = max(maxDepth, depth)
+		case ')':
+			depth--
+		}
+	}
+
+	return maxDepth
+}
+
+// countNegations returns the number of negated conditions and groups in the expression.
+func countNegations(expr *Expression) int {
+	n := 0
+	_ = walkFactors(expr, func(factor *Factor) error {
+		if factor.Not {
+			n++
+		}
+		return nil
+	})
+	return n
+}
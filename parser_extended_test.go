@@ -343,3 +343,60 @@ func TestParseFunctionGlobal(t *testing.T) {
 		require.Nil(t, filter)
 	})
 }
+
+func TestWithMaxORBranches(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "at the limit", input: "a = 1 OR b = 2 OR c = 3"},
+		{name: "separate groups", input: "(a = 1 OR b = 2 OR c = 3) AND (d = 4 OR e = 5 OR f = 6)"},
+		{name: "wide root", input: "a = 1 OR b = 2 OR c = 3 OR d = 4", wantErr: "OR expression exceeds maximum of 3 branches"},
+		{name: "wide group", input: "x = 0 AND (a = 1 OR b = 2 OR c = 3 OR d = 4)", wantErr: "OR expression exceeds maximum of 3 branches"},
+		{name: "wide function argument", input: "IF(a = 1 OR b = 2 OR c = 3 OR d = 4, 1, 0) = 1", wantErr: "OR expression exceeds maximum of 3 branches"},
+	}
+
+	parser, err := where.NewParser(where.WithMaxORBranches(3))
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := parser.Parse(tt.input)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, "filter validation failed: "+tt.wantErr)
+				require.Nil(t, filter)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWithMaxNegations(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "at the limit", input: "NOT a = 1 AND NOT (b = 2 OR c = 3)"},
+		{name: "negated operators are not counted", input: "NOT a NOT IN (1) AND b NOT LIKE 'x' AND c IS NOT NULL AND NOT d NOT BETWEEN 1 AND 2"},
+		{name: "nested", input: "NOT (a = 1 OR NOT (b = 2 AND NOT c = 3))", wantErr: "filter exceeds maximum of 2 negations"},
+		{name: "in function arguments", input: "NOT a = 1 AND IF(NOT b = 2 AND NOT c = 3, 1, 0) = 1", wantErr: "filter exceeds maximum of 2 negations"},
+	}
+
+	parser, err := where.NewParser(where.WithMaxNegations(2))
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := parser.Parse(tt.input)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, "filter validation failed: "+tt.wantErr)
+				require.Nil(t, filter)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
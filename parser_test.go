@@ -21,6 +21,7 @@ func TestParseSimpleComparison(t *testing.T) {
 		{"greater than", "price > 99.99"},
 		{"less or equal", "quantity <= 10"},
 		{"greater or equal", "score >= 80"},
+		{"null-safe equal", "manager_id <=> NULL"},
 		{"case insensitive keywords", "AGE = 18 AND STATUS != 'active'"},
 	}
 
@@ -158,6 +159,73 @@ func TestParseFunctions(t *testing.T) {
 	}
 }
 
+func TestParseKeywordFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		postgres string
+		mysql    string
+	}{
+		{
+			name:     "IF",
+			input:    "IF(active, 1, 0) = 1",
+			postgres: "CASE WHEN active THEN $1 ELSE $2 END = $3",
+			mysql:    "IF(active, ?, ?) = ?",
+		},
+		{
+			name:     "CAST",
+			input:    "CAST(age AS INTEGER) > 18",
+			postgres: "CAST(age AS INTEGER) > $1",
+			mysql:    "CAST(age AS SIGNED) > ?",
+		},
+		{
+			name:     "LEFT",
+			input:    "LEFT(name, 3) = 'abc'",
+			postgres: "LEFT(name, $1) = $2",
+			mysql:    "LEFT(name, ?) = ?",
+		},
+		{
+			name:     "RIGHT",
+			input:    "right(name, 3) = 'xyz'",
+			postgres: "right(name, $1) = $2",
+			mysql:    "right(name, ?) = ?",
+		},
+		{
+			name:     "VALUES",
+			input:    "VALUES(total) > 0",
+			postgres: "VALUES(total) > $1",
+			mysql:    "VALUES(total) > ?",
+		},
+		{
+			name:     "lexer keyword",
+			input:    "like(name, 'a%') = TRUE AND IN(id, 1) = 1",
+			postgres: "(like(name, $1) = TRUE AND IN(id, $2) = $3)",
+			mysql:    "(like(name, ?) = TRUE AND IN(id, ?) = ?)",
+		},
+		{
+			name:     "keyword operators",
+			input:    "id IN(1, 2) AND name LIKE 'a%' AND NOT(left IS NULL)",
+			postgres: `(id IN ($1, $2) AND name LIKE $3 AND NOT ("left" IS NULL))`,
+			mysql:    "(id IN (?, ?) AND name LIKE ? AND NOT (`left` IS NULL))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			sql, _, err := filter.ToSQL("postgres")
+			require.NoError(t, err)
+			require.Equal(t, tt.postgres, sql)
+
+			sql, _, err = filter.ToSQL("mysql")
+			require.NoError(t, err)
+			require.Equal(t, tt.mysql, sql)
+		})
+	}
+}
+
 func TestParseFieldReferences(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -253,7 +321,7 @@ func TestParseErrors(t *testing.T) {
 		{"empty input", "", "empty filter expression"},
 		{"invalid operator", "age >> 18", ""},
 		{"unclosed parenthesis", "(age > 18", ""},
-		{"empty in list", "id IN ()", "unexpected token \")\""},
+		{"empty in list", "id IN ()", "IN expression requires at least one value"},
 		{"invalid syntax", "age > > 18", ""},
 	}
 
@@ -0,0 +1,189 @@
+package where
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PortableFunctions contains the functions translated by the PostgreSQL, MySQL, ClickHouse, Redshift,
+// and Trino drivers. Filters restricted to these functions (see WithPortableFunctions) produce
+// equivalent SQL on those databases. The ansi and spanner drivers reject the functions they have no
+// translation for, such as REGEXP and WITHIN_RADIUS, rather than passing them through.
+var PortableFunctions = map[string]FunctionDef{
+	"LOWER": {
+		Name:        "LOWER",
+		Type:        FunctionTypeString,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Converts string to lowercase",
+	},
+	"UPPER": {
+		Name:        "UPPER",
+		Type:        FunctionTypeString,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Converts string to uppercase",
+	},
+	"LENGTH": {
+		Name:        "LENGTH",
+		Type:        FunctionTypeString,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Returns length of string in characters",
+	},
+	"TRIM": {
+		Name:        "TRIM",
+		Type:        FunctionTypeString,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Removes leading and trailing whitespace",
+	},
+	"CONCAT": {
+		Name:        "CONCAT",
+		Type:        FunctionTypeString,
+		MinArgs:     2,
+		MaxArgs:     -1,
+		Description: "Concatenates strings",
+	},
+	"SUBSTRING": {
+		Name:        "SUBSTRING",
+		Type:        FunctionTypeString,
+		MinArgs:     2,
+		MaxArgs:     3,
+		Description: "Extracts substring from string (1-based)",
+	},
+	"REGEXP": {
+		Name:        "REGEXP",
+		Type:        FunctionTypeString,
+		MinArgs:     2,
+		MaxArgs:     2,
+		Description: "Returns true if string matches regular expression",
+	},
+	"COALESCE": {
+		Name:        "COALESCE",
+		Type:        FunctionTypeScalar,
+		MinArgs:     2,
+		MaxArgs:     -1,
+		Description: "Returns first non-null value",
+	},
+	"NOW": {
+		Name:        "NOW",
+		Type:        FunctionTypeDate,
+		MinArgs:     0,
+		MaxArgs:     0,
+		Description: "Returns current timestamp",
+	},
+	"DATE_TRUNC": {
+		Name:        "DATE_TRUNC",
+		Type:        FunctionTypeDate,
+		MinArgs:     2,
+		MaxArgs:     2,
+		Description: "Truncates timestamp to unit (second, minute, hour, day, week, month, quarter, year)",
+	},
+	"YEAR": {
+		Name:        "YEAR",
+		Type:        FunctionTypeDate,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Extracts year from date",
+	},
+	"MONTH": {
+		Name:        "MONTH",
+		Type:        FunctionTypeDate,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Extracts month from date",
+	},
+	"DAY": {
+		Name:        "DAY",
+		Type:        FunctionTypeDate,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Extracts day of month from date",
+	},
+	"CAST": {
+		Name:        "CAST",
+		Type:        FunctionTypeConversion,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Converts value to type, written as CAST(value AS type)",
+	},
+	"IF": {
+		Name:        "IF",
+		Type:        FunctionTypeScalar,
+		MinArgs:     3,
+		MaxArgs:     3,
+		Description: "Returns the second argument if the condition is true, otherwise the third",
+	},
+	"ABS": {
+		Name:        "ABS",
+		Type:        FunctionTypeMath,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Absolute value",
+	},
+	"ROUND": {
+		Name:        "ROUND",
+		Type:        FunctionTypeMath,
+		MinArgs:     1,
+		MaxArgs:     2,
+		Description: "Rounds a number",
+	},
+	"FLOOR": {
+		Name:        "FLOOR",
+		Type:        FunctionTypeMath,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Rounds down to integer",
+	},
+	"CEIL": {
+		Name:        "CEIL",
+		Type:        FunctionTypeMath,
+		MinArgs:     1,
+		MaxArgs:     1,
+		Description: "Rounds up to integer",
+	},
+	"WITHIN_RADIUS": {
+		Name:        "WITHIN_RADIUS",
+		Type:        FunctionTypeGeo,
+		MinArgs:     4,
+		MaxArgs:     4,
+		Description: "Returns true if a point column is within a distance in meters of a latitude and longitude",
+	},
+}
+
+// IsPortableFunction returns true if the function is in PortableFunctions and accepts argCount arguments.
+// Function names are case-insensitive.
+func IsPortableFunction(name string, argCount int) bool {
+	def, ok := PortableFunctions[strings.ToUpper(name)]
+	return ok && def.acceptsArgs(argCount)
+}
+
+// WithPortableFunctions returns a ParserOption that rejects functions not listed in PortableFunctions,
+// or called with an unsupported number of arguments, so filters build on every bundled driver that
+// translates them all (see PortableFunctions).
+func WithPortableFunctions() ParserOption {
+	return func(o *parserOptions) {
+		o.portableFuncs = true
+	}
+}
+
+// checkPortable returns an error if the function call is not portable.
+func checkPortable(fn *FunctionCall) error {
+	if _, ok := PortableFunctions[strings.ToUpper(fn.Name)]; !ok {
+		return fmt.Errorf("function %q is not portable", fn.Name)
+	}
+	argCount := fn.argCount()
+	if !IsPortableFunction(fn.Name, argCount) || (strings.EqualFold(fn.Name, "CAST") && !fn.isCast()) {
+		return fmt.Errorf("function %q is not portable with %d arguments", fn.Name, argCount)
+	}
+	return nil
+}
+
+// acceptsArgs returns true if the function accepts argCount arguments.
+func (d FunctionDef) acceptsArgs(argCount int) bool {
+	if d.MaxArgs == -1 {
+		return argCount >= d.MinArgs
+	}
+	return argCount >= d.MinArgs && argCount <= d.MaxArgs
+}
@@ -0,0 +1,154 @@
+package where_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pseudomuto/where"
+	_ "github.com/pseudomuto/where/drivers/ansi"
+	_ "github.com/pseudomuto/where/drivers/redshift"
+	_ "github.com/pseudomuto/where/drivers/spanner"
+	_ "github.com/pseudomuto/where/drivers/trino"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPortableFunctions(t *testing.T) {
+	parser, err := where.NewParser(where.WithPortableFunctions())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "portable", input: "LOWER(email) = 'x' AND DATE_TRUNC('day', created_at) > NOW()"},
+		{name: "case-insensitive", input: "coalesce(a, b) = 1"},
+		{name: "nested", input: "LENGTH(TRIM(name)) > 0"},
+		{name: "cast", input: "CAST(age AS INTEGER) > 18"},
+		{
+			name:    "cast function form",
+			input:   "CAST(age, 'INTEGER') > 18",
+			wantErr: `filter validation failed: function "CAST" is not portable with 2 arguments`,
+		},
+		{
+			name:    "not portable",
+			input:   "toYYYYMM(created_at) = 202401",
+			wantErr: `filter validation failed: function "toYYYYMM" is not portable`,
+		},
+		{
+			name:    "nested not portable",
+			input:   "LOWER(JSON_EXTRACT(data, '$.a')) = 'x'",
+			wantErr: `filter validation failed: function "JSON_EXTRACT" is not portable`,
+		},
+		{
+			name:    "wrong arity",
+			input:   "LOWER(a, b) = 'x'",
+			wantErr: `filter validation failed: function "LOWER" is not portable with 2 arguments`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.Parse(tt.input)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPortableFunctionTranslations(t *testing.T) {
+	tests := []struct {
+		input string
+		want  map[string]string
+	}{
+		{
+			input: "YEAR(created_at) = 2024",
+			want: map[string]string{
+				"postgres":   "EXTRACT(YEAR FROM created_at) = $1",
+				"mysql":      "YEAR(created_at) = ?",
+				"clickhouse": "YEAR(created_at) = ?",
+			},
+		},
+		{
+			input: "LENGTH(name) > 3",
+			want: map[string]string{
+				"postgres":   "LENGTH(name) > $1",
+				"mysql":      "CHAR_LENGTH(name) > ?",
+				"clickhouse": "lengthUTF8(name) > ?",
+			},
+		},
+		{
+			input: "REGEXP(email, '^admin') = true",
+			want: map[string]string{
+				"postgres":   "(email ~ $1) = TRUE",
+				"mysql":      "(email REGEXP ?) = TRUE",
+				"clickhouse": "match(email, ?) = TRUE",
+			},
+		},
+		{
+			input: "WITHIN_RADIUS(location, 52.52, 13.405, 5000) AND price < 100",
+			want: map[string]string{
+				"postgres":   "(ST_DWithin(CAST(location AS geography), CAST(ST_SetSRID(ST_MakePoint($1, $2), 4326) AS geography), $3) AND price < $4)",
+				"mysql":      "((ST_Distance_Sphere(location, POINT(?, ?)) <= ?) AND price < ?)",
+				"clickhouse": "((greatCircleDistance(tupleElement(location, 1), tupleElement(location, 2), ?, ?) <= ?) AND price < ?)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		filter, err := where.Parse(tt.input)
+		require.NoError(t, err)
+
+		for driver, want := range tt.want {
+			t.Run(driver+"/"+tt.input, func(t *testing.T) {
+				sql, _, err := filter.ToSQL(driver)
+				require.NoError(t, err)
+				require.Equal(t, want, sql)
+			})
+		}
+	}
+}
+
+func TestPortableFunctionsBuildOnAllDrivers(t *testing.T) {
+	// Drivers without a translation for a portable function must reject it rather than pass it through.
+	unsupported := map[string][]string{
+		"ansi":    {"DATE_TRUNC", "REGEXP", "ROUND", "WITHIN_RADIUS"},
+		"spanner": {"WITHIN_RADIUS"},
+	}
+
+	for name, def := range where.PortableFunctions {
+		args := make([]string, def.MinArgs)
+		for i := range args {
+			args[i] = "col"
+		}
+		switch name {
+		case "DATE_TRUNC":
+			args[0] = "'day'"
+		case "CAST":
+			args[0] = "col AS INTEGER"
+		}
+
+		filter, err := where.Parse(name + "(" + strings.Join(args, ", ") + ") IS NOT NULL")
+		require.NoError(t, err)
+
+		for _, driver := range []string{"ansi", "clickhouse", "mysql", "postgres", "redshift", "spanner", "trino"} {
+			_, _, err := filter.ToSQL(driver)
+			if slices.Contains(unsupported[driver], name) {
+				require.EqualError(t, err, `function "`+name+`" not supported by driver `+driver)
+				continue
+			}
+			require.NoError(t, err, "%s on %s", name, driver)
+		}
+	}
+}
+
+func TestIsPortableFunction(t *testing.T) {
+	require.True(t, where.IsPortableFunction("date_trunc", 2))
+	require.True(t, where.IsPortableFunction("CONCAT", 5))
+	require.False(t, where.IsPortableFunction("CONCAT", 1))
+	require.False(t, where.IsPortableFunction("IFNULL", 2))
+}
@@ -0,0 +1,69 @@
+package where
+
+import (
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/pkg/errors"
+)
+
+// PositionError attributes an error building SQL to the node of the filter that caused it, e.g. a
+// field the validator does not allow, so the problem can be pointed out in the user's text. Its
+// message is that of the underlying error, which it wraps.
+type PositionError struct {
+	// Pos is the position of the offending predicate, function, or field in the filter's text.
+	Pos lexer.Position
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *PositionError) Error() string { return e.Err.Error() }
+func (e *PositionError) Unwrap() error { return e.Err }
+
+// ErrorPosition returns the position in the filter's text of the node that caused err, which is
+// known when building SQL from a parsed filter fails because of a predicate, function, or field.
+//
+// Example:
+//
+//	filter, _ := where.Parse("age > 18 AND\n  password = 'x'")
+//	_, _, err := filter.ToSQL("postgres", where.WithValidator(validator))
+//	if pos, ok := where.ErrorPosition(err); ok {
+//		// pos.Line == 2, pos.Column == 3
+//	}
+func ErrorPosition(err error) (lexer.Position, bool) {
+	var perr *PositionError
+	if errors.As(err, &perr) {
+		return perr.Pos, true
+	}
+	return lexer.Position{}, false
+}
+
+// atPosition attributes err to the node at pos, unless the node was not parsed or err is already
+// attributed to a node nested in it.
+func atPosition(err error, pos lexer.Position) error {
+	if err == nil || pos.Line == 0 {
+		return err
+	}
+	if _, ok := ErrorPosition(err); ok {
+		return err
+	}
+	return &PositionError{Pos: pos, Err: err}
+}
+
+// clearPositions removes the positions from the expression's nodes, e.g. for macros whose positions
+// refer to the macro's text rather than the filter's.
+func clearPositions(expr *Expression) {
+	_ = walkFactors(expr, func(factor *Factor) error {
+		if factor.Predicate != nil {
+			factor.Predicate.Pos = lexer.Position{}
+			walkPredicateValues(factor.Predicate, func(val *Value) {
+				if val.Function != nil {
+					val.Function.Pos = lexer.Position{}
+				}
+				if val.Field != nil {
+					val.Field.Pos = lexer.Position{}
+				}
+			})
+		}
+		return nil
+	})
+}
@@ -0,0 +1,153 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/pseudomuto/where"
+	_ "github.com/pseudomuto/where/drivers/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorPosition(t *testing.T) {
+	validator := where.NewValidator().AllowFields("age", "name").AllowFunctions("LOWER").
+		ConstrainField("name", where.FieldConstraint{MaxLength: 3})
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+		wantPos lexer.Position
+	}{
+		{
+			name:    "field",
+			input:   "age > 18 AND\n  password = 'x'",
+			wantErr: `field "password" is not allowed`,
+			wantPos: lexer.Position{Offset: 15, Line: 2, Column: 3},
+		},
+		{
+			name:    "function",
+			input:   "age > 18 OR UPPER(name) = 'X'",
+			wantErr: `function "UPPER" is not allowed`,
+			wantPos: lexer.Position{Offset: 12, Line: 1, Column: 13},
+		},
+		{
+			name:    "field in function",
+			input:   "(age > 18 AND LOWER(email) = 'x')",
+			wantErr: `field "email" is not allowed`,
+			wantPos: lexer.Position{Offset: 20, Line: 1, Column: 21},
+		},
+		{
+			name:    "predicate",
+			input:   "age > 18 AND name = 'long'",
+			wantErr: `value for field "name" exceeds maximum length of 3`,
+			wantPos: lexer.Position{Offset: 13, Line: 1, Column: 14},
+		},
+		{
+			name:    "IN list",
+			input:   "secret IN (1, 2, 3)",
+			wantErr: `field "secret" is not allowed`,
+			wantPos: lexer.Position{Offset: 0, Line: 1, Column: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := mustParse(t, tt.input)
+
+			_, _, err := filter.ToSQL("postgres", where.WithValidator(validator))
+			require.EqualError(t, err, tt.wantErr)
+
+			pos, ok := where.ErrorPosition(err)
+			require.True(t, ok)
+			require.Equal(t, tt.wantPos, pos)
+		})
+	}
+}
+
+func TestErrorPositionUnknown(t *testing.T) {
+	validator := where.NewValidator().AllowFields("age")
+
+	_, _, err := where.NotIn("secret", 1, 2).ToSQL("postgres", where.WithValidator(validator))
+	require.EqualError(t, err, `field "secret" is not allowed`)
+	_, ok := where.ErrorPosition(err)
+	require.False(t, ok)
+
+	parser, err := where.NewParser(where.WithMacros(map[string]string{"hidden": "secret = 1"}))
+	require.NoError(t, err)
+	filter, err := parser.Parse("age > 1 AND @hidden")
+	require.NoError(t, err)
+
+	_, _, err = filter.ToSQL("postgres", where.WithValidator(validator))
+	require.EqualError(t, err, `field "secret" is not allowed`)
+	_, ok = where.ErrorPosition(err)
+	require.False(t, ok)
+}
+
+// requireEqualAST asserts that the expressions are the same apart from the positions of their nodes,
+// e.g. to compare a filter parsed from another syntax with the equivalent filter expression.
+func requireEqualAST(t *testing.T, want, got *where.Expression) {
+	t.Helper()
+	want, got = want.DeepCopy(), got.DeepCopy()
+	clearExpressionPositions(want)
+	clearExpressionPositions(got)
+	require.Equal(t, want, got)
+}
+
+func clearExpressionPositions(expr *where.Expression) {
+	if expr == nil {
+		return
+	}
+	for _, term := range expr.Or {
+		for _, factor := range term.And {
+			clearExpressionPositions(factor.SubExpr)
+			if pred := factor.Predicate; pred != nil {
+				pred.Pos = lexer.Position{}
+				clearValuePositions(pred.Left)
+				if op := pred.Operation; op != nil {
+					if op.Between != nil {
+						clearValuePositions(op.Between.Lower)
+						clearValuePositions(op.Between.Upper)
+					}
+					if op.In != nil {
+						for _, val := range op.In.Values {
+							clearValuePositions(val)
+						}
+					}
+					if op.Like != nil {
+						clearValuePositions(op.Like.Pattern)
+					}
+					if op.Match != nil {
+						clearValuePositions(op.Match.Query)
+					}
+					if op.Compare != nil {
+						clearValuePositions(op.Compare.Right)
+					}
+				}
+			}
+		}
+	}
+}
+
+func clearValuePositions(val *where.Value) {
+	if val == nil {
+		return
+	}
+	if val.Field != nil {
+		val.Field.Pos = lexer.Position{}
+	}
+	if fn := val.Function; fn != nil {
+		fn.Pos = lexer.Position{}
+		clearExpressionPositions(fn.Cond)
+		for _, arg := range fn.Args {
+			clearValuePositions(arg)
+		}
+	}
+	clearExpressionPositions(val.SubExpr)
+	if val.Bitwise != nil {
+		clearValuePositions(val.Bitwise.Left)
+		for _, op := range val.Bitwise.Ops {
+			clearValuePositions(op.Right)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package where
+
+import "fmt"
+
+// ToPrewhereSQL splits the filter into a PREWHERE condition and a WHERE condition for drivers that
+// support the PREWHERE feature (ClickHouse). Top-level AND conditions made up only of comparisons
+// between a plain column and literals (=, !=, <, >, IN, BETWEEN, IS NULL, ...) are moved to PREWHERE
+// so ClickHouse can skip reading other columns for rows that do not match; everything else, including
+// filters added with WithRequiredFilter, stays in WHERE. See Filter.Split for how conditions are
+// partitioned. Either condition may be empty. Parameters are returned in
+// PREWHERE then WHERE order, matching their position in a SELECT statement.
+//
+// Example:
+//
+//	filter, _ := where.Parse("event_date = '2024-01-01' AND position(url, 'x') > 0")
+//	prewhere, rest, params, _ := filter.ToPrewhereSQL("clickhouse")
+//	// prewhere: event_date = ?
+//	// rest:     position(url, ?) > ?
+func (f *Filter) ToPrewhereSQL(driverName string, options ...BuildOption) (string, string, []any, error) {
+	builder, err := newSQLBuilder(f, driverName, options)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	if !builder.driver.SupportsFeature("PREWHERE") {
+		return "", "", nil, fmt.Errorf("PREWHERE is not supported by driver %s", builder.driver.Name())
+	}
+
+	prewhere, rest := f.Split(builder.prewhereEligible)
+
+	var prewhereSQL, whereSQL string
+	if prewhere != nil {
+		if prewhereSQL, err = builder.buildExpression(prewhere.Expression); err != nil {
+			return "", "", nil, builder.notifyRejection(f, err)
+		}
+	}
+	if rest != nil {
+		if whereSQL, err = builder.buildExpression(rest.Expression); err != nil {
+			return "", "", nil, builder.notifyRejection(f, err)
+		}
+	}
+
+	whereSQL, err = builder.applyRequired(whereSQL)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	if err := builder.checkParamLimit(); err != nil {
+		return "", "", nil, err
+	}
+
+	if err := builder.auditSQL(prewhereSQL, whereSQL); err != nil {
+		return "", "", nil, err
+	}
+
+	return prewhereSQL, whereSQL, builder.params, nil
+}
+
+// prewhereEligible reports whether the predicate is a cheap comparison of a column with literals.
+// Pattern and full-text matches are not cheap, so LIKE and MATCHES stay in WHERE.
+func (b *SQLBuilder) prewhereEligible(pred *Predicate) bool {
+	if pred.Left == nil || pred.Left.Field == nil || pred.Operation == nil {
+		return false
+	}
+	if pred.Operation.Like != nil || pred.Operation.Match != nil {
+		return false
+	}
+	if _, ok := b.fragment(pred.Left.Field); ok {
+		return false
+	}
+
+	for _, operand := range pred.Operation.operands() {
+		if operand == nil || operand.Literal == nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToPrewhereSQL(t *testing.T) {
+	tests := []struct {
+		name         string
+		filter       string
+		options      []where.BuildOption
+		wantPrewhere string
+		wantWhere    string
+		wantArgs     []any
+	}{
+		{
+			name:         "split",
+			filter:       "event_date = '2024-01-01' AND position(url, 'checkout') > 0 AND user_id IN (1, 2)",
+			wantPrewhere: "(event_date = ? AND user_id IN (?, ?))",
+			wantWhere:    "position(url, ?) > ?",
+			wantArgs:     []any{"2024-01-01", float64(1), float64(2), "checkout", float64(0)},
+		},
+		{
+			name:         "all eligible",
+			filter:       "score BETWEEN 1 AND 5 AND deleted_at IS NULL",
+			wantPrewhere: "(score BETWEEN ? AND ? AND deleted_at IS NULL)",
+			wantWhere:    "",
+			wantArgs:     []any{float64(1), float64(5)},
+		},
+		{
+			name:         "like, functions, and column comparisons stay in WHERE",
+			filter:       "id = 5 AND name LIKE 'a%' AND NOT (status = 'x') AND LOWER(city) = 'y' AND a > b",
+			wantPrewhere: "(id = ? AND NOT (status = ?))",
+			wantWhere:    "(name LIKE ? AND LOWER(city) = ? AND a > b)",
+			wantArgs:     []any{float64(5), "x", "a%", "y"},
+		},
+		{
+			name:         "full-text matches stay in WHERE",
+			filter:       "NOT a = 1 AND b MATCHES 'x'",
+			wantPrewhere: "NOT (a = ?)",
+			wantWhere:    "hasAll(tokens(lowerUTF8(b)), tokens(lowerUTF8(?)))",
+			wantArgs:     []any{float64(1), "x"},
+		},
+		{
+			name:         "OR at the root",
+			filter:       "id = 1 OR id = 2",
+			wantPrewhere: "(id = ? OR id = ?)",
+			wantWhere:    "",
+			wantArgs:     []any{float64(1), float64(2)},
+		},
+		{
+			name:         "OR branches are not separated",
+			filter:       "id = 1 AND (status = 'a' OR LOWER(name) = 'b')",
+			wantPrewhere: "id = ?",
+			wantWhere:    "(status = ? OR LOWER(name) = ?)",
+			wantArgs:     []any{float64(1), "a", "b"},
+		},
+		{
+			name:         "required filters stay in WHERE",
+			filter:       "id = 1",
+			options:      []where.BuildOption{where.WithRequiredFilter(mustParse(t, "tenant_id = 7"))},
+			wantPrewhere: "id = ?",
+			wantWhere:    "(tenant_id = ?)",
+			wantArgs:     []any{float64(1), float64(7)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := mustParse(t, tt.filter)
+
+			prewhere, whereSQL, args, err := filter.ToPrewhereSQL("clickhouse", tt.options...)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPrewhere, prewhere)
+			require.Equal(t, tt.wantWhere, whereSQL)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestToPrewhereSQLErrors(t *testing.T) {
+	filter := mustParse(t, "id = 1")
+
+	_, _, _, err := filter.ToPrewhereSQL("postgres")
+	require.EqualError(t, err, "PREWHERE is not supported by driver postgres")
+
+	validator := where.NewValidator().AllowFields("name")
+	_, _, _, err = filter.ToPrewhereSQL("clickhouse", where.WithValidator(validator))
+	require.EqualError(t, err, `field "id" is not allowed`)
+}
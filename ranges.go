@@ -0,0 +1,195 @@
+package where
+
+type (
+	// bound is one side of a numeric range placed on a field.
+	bound struct {
+		value     float64
+		inclusive bool
+		literal   *Value
+	}
+
+	// fieldRange collects the bounds placed on a field by the predicates of an AND term.
+	fieldRange struct {
+		field *FieldRef
+		lower *bound
+		upper *bound
+		count int
+	}
+)
+
+// WithRangeMerge returns a BuildOption that merges numeric range predicates on the same field within
+// an AND group before generating SQL. Redundant bounds are dropped ("age > 18 AND age > 21" becomes
+// "age > 21") and inclusive bounds on both sides become BETWEEN ("x >= 1 AND x <= 5" becomes
+// "x BETWEEN 1 AND 5"). Since every merged predicate compares the same field, the result is NULL for
+// NULL fields exactly as before. Empty ranges are left unchanged. The parsed filter is not modified.
+func WithRangeMerge() BuildOption {
+	return func(b *SQLBuilder) {
+		b.mergeRanges = true
+	}
+}
+
+// mergeRangeFactors returns the factors with the range predicates on each field merged into at most
+// two predicates, placed where the first range predicate on the field appeared.
+func mergeRangeFactors(factors []*Factor) []*Factor {
+	var order []string
+	ranges := make(map[string]*fieldRange)
+	for _, factor := range factors {
+		field, lower, upper, ok := factorRange(factor)
+		if !ok {
+			continue
+		}
+
+		name := field.String()
+		r, exists := ranges[name]
+		if !exists {
+			r = &fieldRange{field: field}
+			ranges[name] = r
+			order = append(order, name)
+		}
+		r.lower = tighterBound(r.lower, lower, 1)
+		r.upper = tighterBound(r.upper, upper, -1)
+		r.count++
+	}
+
+	merged := make(map[string]bool)
+	for _, name := range order {
+		if r := ranges[name]; r.count > 1 && !r.empty() {
+			merged[name] = true
+		}
+	}
+	if len(merged) == 0 {
+		return factors
+	}
+
+	result := make([]*Factor, 0, len(factors))
+	for _, factor := range factors {
+		field, _, _, ok := factorRange(factor)
+		if !ok || !merged[field.String()] {
+			result = append(result, factor)
+			continue
+		}
+
+		name := field.String()
+		if r := ranges[name]; r != nil {
+			result = append(result, r.factors()...)
+			ranges[name] = nil
+		}
+	}
+	return result
+}
+
+// factorRange returns the bounds placed on a field by a numeric comparison or BETWEEN factor.
+func factorRange(factor *Factor) (*FieldRef, *bound, *bound, bool) {
+	if factor == nil || factor.Not || factor.Predicate == nil || factor.Predicate.Operation == nil {
+		return nil, nil, nil, false
+	}
+
+	pred := factor.Predicate
+	if pred.Left == nil || pred.Left.Field == nil {
+		return nil, nil, nil, false
+	}
+
+	op := pred.Operation
+	switch {
+	case op.Compare != nil:
+		b, ok := numericBound(op.Compare.Right, false)
+		if !ok {
+			return nil, nil, nil, false
+		}
+		switch op.Compare.Operator.Type {
+		case ">=":
+			b.inclusive = true
+			fallthrough
+		case ">":
+			return pred.Left.Field, b, nil, true
+		case "<=":
+			b.inclusive = true
+			fallthrough
+		case "<":
+			return pred.Left.Field, nil, b, true
+		}
+	case op.Between != nil && !op.Between.Not:
+		lower, ok := numericBound(op.Between.Lower, true)
+		if !ok {
+			return nil, nil, nil, false
+		}
+		upper, ok := numericBound(op.Between.Upper, true)
+		if !ok {
+			return nil, nil, nil, false
+		}
+		return pred.Left.Field, lower, upper, true
+	}
+
+	return nil, nil, nil, false
+}
+
+func numericBound(val *Value, inclusive bool) (*bound, bool) {
+	if val == nil || val.Literal == nil || val.Literal.Number == nil {
+		return nil, false
+	}
+	return &bound{value: *val.Literal.Number, inclusive: inclusive, literal: val}, true
+}
+
+// tighterBound returns the more restrictive of two bounds. direction is 1 for lower bounds, where
+// larger values are tighter, and -1 for upper bounds.
+func tighterBound(current, next *bound, direction float64) *bound {
+	switch {
+	case next == nil:
+		return current
+	case current == nil:
+		return next
+	case next.value*direction > current.value*direction:
+		return next
+	case next.value == current.value && !next.inclusive:
+		return next
+	default:
+		return current
+	}
+}
+
+// empty reports whether no value satisfies the range.
+func (r *fieldRange) empty() bool {
+	if r.lower == nil || r.upper == nil {
+		return false
+	}
+	if r.lower.value == r.upper.value {
+		return !r.lower.inclusive || !r.upper.inclusive
+	}
+	return r.lower.value > r.upper.value
+}
+
+// factors returns the predicates expressing the range.
+func (r *fieldRange) factors() []*Factor {
+	left := &Value{Field: r.field}
+	if r.lower != nil && r.upper != nil && r.lower.inclusive && r.upper.inclusive {
+		return []*Factor{{Predicate: &Predicate{
+			Left: left,
+			Operation: &Operation{Between: &BetweenOp{
+				Between: "BETWEEN",
+				Lower:   r.lower.literal,
+				And:     "AND",
+				Upper:   r.upper.literal,
+			}},
+		}}}
+	}
+
+	var factors []*Factor
+	if r.lower != nil {
+		factors = append(factors, compareFactor(left, ">", ">=", r.lower))
+	}
+	if r.upper != nil {
+		factors = append(factors, compareFactor(left, "<", "<=", r.upper))
+	}
+	return factors
+}
+
+func compareFactor(left *Value, exclusive, inclusive string, b *bound) *Factor {
+	operator := exclusive
+	if b.inclusive {
+		operator = inclusive
+	}
+	return &Factor{Predicate: &Predicate{
+		Left:      left,
+		Operation: &Operation{Compare: &CompareOp{Operator: CompareOperator{Type: operator}, Right: b.literal}},
+	}}
+}
@@ -0,0 +1,145 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRangeMerge(t *testing.T) {
+	tests := []struct {
+		filter string
+		merged string
+	}{
+		{"age > 18 AND age > 21", "age > 21"},
+		{"age >= 21 AND age > 21", "age > 21"},
+		{"age < 65 AND age <= 65 AND age < 70", "age < 65"},
+		{"x >= 1 AND x <= 5", "x BETWEEN 1 AND 5"},
+		{"x > 1 AND x <= 5", "x > 1 AND x <= 5"},
+		{"x BETWEEN 1 AND 10 AND x > 3", "x > 3 AND x <= 10"},
+		{"x BETWEEN 1 AND 10 AND x BETWEEN 5 AND 20", "x BETWEEN 5 AND 10"},
+		{"x > 1 AND y = 2 AND x < 3 AND y > 0", "x > 1 AND x < 3 AND y = 2 AND y > 0"},
+		{"x >= 5 AND x <= 5", "x BETWEEN 5 AND 5"},
+		{"NOT (x > 1 AND x > 2) OR x < 0", "NOT (x > 2) OR x < 0"},
+
+		// Left unchanged.
+		{"x > 5 AND x < 1", "x > 5 AND x < 1"},
+		{"x > 5 AND x < 5", "x > 5 AND x < 5"},
+		{"x > 1 OR x > 2", "x > 1 OR x > 2"},
+		{"x > 1 AND NOT (x > 2)", "x > 1 AND NOT (x > 2)"},
+		{"x > 1 AND x NOT BETWEEN 2 AND 3", "x > 1 AND x NOT BETWEEN 2 AND 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter := mustParse(t, tt.filter)
+			merged := mustParse(t, tt.merged)
+
+			sql, args, err := filter.ToSQL("postgres", where.WithRangeMerge())
+			require.NoError(t, err)
+
+			wantSQL, wantArgs, err := merged.ToSQL("postgres")
+			require.NoError(t, err)
+			require.Equal(t, wantSQL, sql)
+			require.Equal(t, wantArgs, args)
+
+			for _, x := range []*float64{nil, num(-1), num(0), num(1), num(2), num(3), num(4), num(5), num(6), num(10), num(20), num(21), num(22), num(65), num(70)} {
+				row := map[string]*float64{"x": x, "y": x, "age": x}
+				require.Equal(t, eval(filter.Expression, row), eval(merged.Expression, row), "x = %v", deref(x))
+			}
+		})
+	}
+}
+
+func TestWithRangeMergeRequired(t *testing.T) {
+	filter := mustParse(t, "price > 10")
+	required := mustParse(t, "price >= 0 AND price <= 100")
+
+	sql, args, err := filter.ToSQL("mysql", where.WithRangeMerge(), where.WithRequiredFilter(required))
+	require.NoError(t, err)
+	require.Equal(t, "(price > ? AND price BETWEEN ? AND ?)", sql)
+	require.Equal(t, []any{float64(10), float64(0), float64(100)}, args)
+}
+
+func num(v float64) *float64 { return &v }
+
+func deref(v *float64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// eval evaluates numeric comparisons with SQL three-valued logic. A nil result is NULL.
+func eval(expr *where.Expression, row map[string]*float64) *bool {
+	result := boolPtr(false)
+	for _, term := range expr.Or {
+		and := boolPtr(true)
+		for _, factor := range term.And {
+			var v *bool
+			if factor.SubExpr != nil {
+				v = eval(factor.SubExpr, row)
+			} else {
+				v = evalPredicate(factor.Predicate, row)
+			}
+			if factor.Not && v != nil {
+				v = boolPtr(!*v)
+			}
+			and = and3(and, v)
+		}
+		result = or3(result, and)
+	}
+	return result
+}
+
+func evalPredicate(pred *where.Predicate, row map[string]*float64) *bool {
+	x := row[pred.Left.Field.String()]
+	if x == nil {
+		return nil
+	}
+
+	op := pred.Operation
+	if op.Between != nil {
+		in := *x >= *op.Between.Lower.Literal.Number && *x <= *op.Between.Upper.Literal.Number
+		return boolPtr(in != op.Between.Not)
+	}
+
+	right := *op.Compare.Right.Literal.Number
+	switch op.Compare.Operator.Type {
+	case ">":
+		return boolPtr(*x > right)
+	case ">=":
+		return boolPtr(*x >= right)
+	case "<":
+		return boolPtr(*x < right)
+	case "<=":
+		return boolPtr(*x <= right)
+	default:
+		return boolPtr(*x == right)
+	}
+}
+
+func and3(a, b *bool) *bool {
+	switch {
+	case a != nil && !*a, b != nil && !*b:
+		return boolPtr(false)
+	case a == nil || b == nil:
+		return nil
+	default:
+		return boolPtr(true)
+	}
+}
+
+func or3(a, b *bool) *bool {
+	switch {
+	case a != nil && *a, b != nil && *b:
+		return boolPtr(true)
+	case a == nil || b == nil:
+		return nil
+	default:
+		return boolPtr(false)
+	}
+}
+
+func boolPtr(v bool) *bool { return &v }
@@ -0,0 +1,119 @@
+package where
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// defaultRegistry holds the drivers registered with RegisterDriver, which driver packages call from
+// init.
+var defaultRegistry = NewRegistry()
+
+// Registry is a set of drivers by name. The package-level functions such as RegisterDriver and
+// GetDriver use a global registry; a separate Registry, passed to the SQL builder with WithRegistry,
+// lets tests and multi-tenant services use isolated driver sets instead of changing the global one.
+// A Registry is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	drivers map[string]Driver
+}
+
+// NewRegistry returns an empty registry.
+//
+// Example:
+//
+//	registry := where.NewRegistry()
+//	registry.Register("postgres", postgres.NewPostgreSQLDriver())
+//	sql, params, err := filter.ToSQL("postgres", where.WithRegistry(registry))
+func NewRegistry() *Registry {
+	return &Registry{drivers: make(map[string]Driver)}
+}
+
+// Register registers a driver with the given name, replacing any driver already registered with it.
+func (r *Registry) Register(name string, driver Driver) {
+	if driver == nil {
+		panic("where: Register driver is nil")
+	}
+	if name == "" {
+		panic("where: Register name is empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.drivers[name] = driver
+}
+
+// Unregister removes the driver registered with the given name. Other names of the driver are kept.
+// It returns false if no driver is registered with the name.
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.drivers[name]
+	delete(r.drivers, name)
+	return ok
+}
+
+// Get retrieves a registered driver by name.
+// Returns an error if the driver is not found.
+func (r *Registry) Get(name string) (Driver, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	driver, ok := r.drivers[name]
+	if !ok {
+		return nil, errors.Errorf("driver %q not registered", name)
+	}
+	return driver, nil
+}
+
+// List returns the sorted names of the registered drivers.
+func (r *Registry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.drivers))
+	for name := range r.drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Wrap replaces the driver registered with the given name by the driver returned by the middleware.
+// See WrapDriver.
+func (r *Registry) Wrap(name string, middleware DriverMiddleware) error {
+	if middleware == nil {
+		panic("where: Wrap middleware is nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	driver, ok := r.drivers[name]
+	if !ok {
+		return errors.Errorf("driver %q not registered", name)
+	}
+
+	wrapped := middleware(driver)
+	if wrapped == nil {
+		return errors.Errorf("middleware for driver %q returned nil", name)
+	}
+
+	r.drivers[name] = wrapped
+	return nil
+}
+
+// WithRegistry returns a BuildOption that looks up the driver in the given registry instead of the
+// global one. A nil registry uses the global one.
+func WithRegistry(r *Registry) BuildOption {
+	return func(b *SQLBuilder) {
+		if r == nil {
+			r = defaultRegistry
+		}
+		b.registry = r
+	}
+}
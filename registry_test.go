@@ -0,0 +1,90 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	pg, err := where.GetDriver("postgres")
+	require.NoError(t, err)
+
+	registry := where.NewRegistry()
+	require.Empty(t, registry.List())
+
+	registry.Register("tenant", pg)
+	registry.Register("mock", &MockDriver{name: "mock"})
+	require.Equal(t, []string{"mock", "tenant"}, registry.List())
+
+	driver, err := registry.Get("tenant")
+	require.NoError(t, err)
+	require.Equal(t, pg, driver)
+
+	// Drivers registered in a separate registry are not global, and global drivers are not in it.
+	_, err = where.GetDriver("tenant")
+	require.EqualError(t, err, `driver "tenant" not registered`)
+	_, err = registry.Get("postgres")
+	require.EqualError(t, err, `driver "postgres" not registered`)
+
+	require.True(t, registry.Unregister("mock"))
+	require.False(t, registry.Unregister("mock"))
+	require.Equal(t, []string{"tenant"}, registry.List())
+
+	require.Panics(t, func() { registry.Register("", pg) })
+	require.Panics(t, func() { registry.Register("nil", nil) })
+}
+
+func TestRegistryWrap(t *testing.T) {
+	registry := where.NewRegistry()
+	registry.Register("mock", &MockDriver{name: "mock"})
+
+	var audit *auditDriver
+	require.NoError(t, registry.Wrap("mock", func(d where.Driver) where.Driver {
+		audit = &auditDriver{DriverWrapper: where.DriverWrapper{Driver: d}}
+		return audit
+	}))
+	require.EqualError(t, registry.Wrap("postgres", func(d where.Driver) where.Driver { return d }),
+		`driver "postgres" not registered`)
+
+	filter, err := where.Parse("name = 'bob'")
+	require.NoError(t, err)
+
+	sql, _, err := filter.ToSQL("mock", where.WithRegistry(registry))
+	require.NoError(t, err)
+	require.Equal(t, "[name] = ?", sql)
+	require.Equal(t, []string{"name"}, audit.quoted)
+
+	// The global mock driver is not wrapped.
+	where.RegisterDriver("mock", &MockDriver{name: "mock"})
+	_, _, err = filter.ToSQL("mock")
+	require.NoError(t, err)
+	require.Len(t, audit.quoted, 1)
+}
+
+func TestWithRegistry(t *testing.T) {
+	filter, err := where.Parse("age > 18")
+	require.NoError(t, err)
+
+	registry := where.NewRegistry()
+	_, _, err = filter.ToSQL("postgres", where.WithRegistry(registry))
+	require.EqualError(t, err, `failed to get driver "postgres": driver "postgres" not registered`)
+
+	sql, params, err := filter.ToSQL("postgres", where.WithRegistry(nil))
+	require.NoError(t, err)
+	require.Equal(t, "age > $1", sql)
+	require.Equal(t, []any{float64(18)}, params)
+}
+
+func TestUnregisterDriver(t *testing.T) {
+	where.RegisterDriver("unregister-test", &MockDriver{name: "unregister-test"})
+	require.Contains(t, where.ListDrivers(), "unregister-test")
+
+	require.True(t, where.UnregisterDriver("unregister-test"))
+	require.NotContains(t, where.ListDrivers(), "unregister-test")
+	require.False(t, where.UnregisterDriver("unregister-test"))
+
+	_, err := where.GetDriver("unregister-test")
+	require.EqualError(t, err, `driver "unregister-test" not registered`)
+}
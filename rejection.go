@@ -0,0 +1,91 @@
+package where
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Rules reported in RejectionMeta.Rule.
+const (
+	RuleSyntax       = "syntax"
+	RuleInputLength  = "input_length"
+	RuleParseTimeout = "parse_timeout"
+	RuleDepth        = "depth"
+	RuleINItems      = "in_items"
+	RuleComplexity   = "complexity"
+	RuleORBranches   = "or_branches"
+	RuleNegations    = "negations"
+	RuleFunction     = "function"
+	RuleField        = "field"
+	RuleConstraint   = "constraint"
+	RuleRequirement  = "requirement"
+)
+
+type (
+	// RejectionHandler is called with the input and error whenever a filter is rejected.
+	RejectionHandler func(input string, err error, meta RejectionMeta)
+
+	// RejectionMeta describes why a filter was rejected.
+	RejectionMeta struct {
+		// Rule is one of the Rule* constants. Errors that are not attributed to a specific rule,
+		// including malformed input, are reported as RuleSyntax.
+		Rule string
+
+		// Field is the field that caused the rejection, if any.
+		Field string
+
+		// Function is the function that caused the rejection, if any.
+		Function string
+	}
+
+	// rejectionError attaches the rule that rejected a filter to the underlying error.
+	rejectionError struct {
+		err  error
+		meta RejectionMeta
+	}
+)
+
+// WithRejectionHandler returns a ParserOption that calls handler whenever parsing or parse-time
+// validation fails, e.g. to centrally log probing attempts. Use Validator.OnRejection to be notified
+// of filters rejected by a validator during SQL generation.
+func WithRejectionHandler(handler RejectionHandler) ParserOption {
+	return func(o *parserOptions) {
+		o.onReject = handler
+	}
+}
+
+// OnRejection sets a handler that is called whenever the validator rejects a filter during SQL
+// generation. The input passed to the handler is the formatted filter.
+func (v *Validator) OnRejection(handler RejectionHandler) *Validator {
+	v.onReject = handler
+	return v
+}
+
+func (e *rejectionError) Error() string { return e.err.Error() }
+func (e *rejectionError) Unwrap() error { return e.err }
+
+// rejected attributes err to the rule described by meta.
+func rejected(err error, meta RejectionMeta) error {
+	return &rejectionError{err: err, meta: meta}
+}
+
+// rejectionMeta returns the rule attributed to err.
+func rejectionMeta(err error) (RejectionMeta, bool) {
+	var re *rejectionError
+	if errors.As(err, &re) {
+		return re.meta, true
+	}
+	return RejectionMeta{Rule: RuleSyntax}, false
+}
+
+// notifyRejection reports err to the validator's rejection handler when a validator rule rejected the
+// filter, and returns err.
+func (b *SQLBuilder) notifyRejection(f *Filter, err error) error {
+	if err == nil || b.validator == nil || b.validator.onReject == nil {
+		return err
+	}
+
+	if meta, ok := rejectionMeta(err); ok {
+		b.validator.onReject(f.String(), err, meta)
+	}
+	return err
+}
@@ -0,0 +1,156 @@
+package where_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+type rejection struct {
+	input string
+	err   string
+	meta  where.RejectionMeta
+}
+
+func TestWithRejectionHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []where.ParserOption
+		input    string
+		wantMeta where.RejectionMeta
+	}{
+		{
+			name:     "syntax",
+			input:    "age >",
+			wantMeta: where.RejectionMeta{Rule: where.RuleSyntax},
+		},
+		{
+			name:     "input length",
+			options:  []where.ParserOption{where.WithMaxInputLength(5)},
+			input:    "age > 18",
+			wantMeta: where.RejectionMeta{Rule: where.RuleInputLength},
+		},
+		{
+			name:     "depth",
+			options:  []where.ParserOption{where.WithMaxDepth(1)},
+			input:    "((a = 1))",
+			wantMeta: where.RejectionMeta{Rule: where.RuleDepth},
+		},
+		{
+			name:     "IN items",
+			options:  []where.ParserOption{where.WithMaxINItems(2)},
+			input:    "id IN (1, 2, 3)",
+			wantMeta: where.RejectionMeta{Rule: where.RuleINItems},
+		},
+		{
+			name:     "OR branches",
+			options:  []where.ParserOption{where.WithMaxORBranches(2)},
+			input:    "a = 1 OR b = 2 OR c = 3",
+			wantMeta: where.RejectionMeta{Rule: where.RuleORBranches},
+		},
+		{
+			name:     "negations",
+			options:  []where.ParserOption{where.WithMaxNegations(1)},
+			input:    "NOT (a = 1 AND NOT b = 2)",
+			wantMeta: where.RejectionMeta{Rule: where.RuleNegations},
+		},
+		{
+			name:     "complexity",
+			options:  []where.ParserOption{where.WithMaxComplexity(1)},
+			input:    "a = 1 OR b = 2 OR c = 3",
+			wantMeta: where.RejectionMeta{Rule: where.RuleComplexity},
+		},
+		{
+			name:     "function",
+			options:  []where.ParserOption{where.WithFunctions("LOWER")},
+			input:    "UPPER(name) = 'X'",
+			wantMeta: where.RejectionMeta{Rule: where.RuleFunction, Function: "UPPER"},
+		},
+		{
+			name:     "function arguments",
+			options:  []where.ParserOption{where.WithFunctionArgValidation()},
+			input:    "LOWER(a, b) = 'x'",
+			wantMeta: where.RejectionMeta{Rule: where.RuleFunction, Function: "LOWER"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []rejection
+			options := append(tt.options, where.WithRejectionHandler(func(input string, err error, meta where.RejectionMeta) {
+				got = append(got, rejection{input: input, err: err.Error(), meta: meta})
+			}))
+
+			parser, err := where.NewParser(options...)
+			require.NoError(t, err)
+
+			_, err = parser.Parse(tt.input)
+			require.Error(t, err)
+			require.Equal(t, []rejection{{input: tt.input, err: err.Error(), meta: tt.wantMeta}}, got)
+		})
+	}
+}
+
+func TestWithRejectionHandlerNotCalledOnSuccess(t *testing.T) {
+	called := false
+	parser, err := where.NewParser(where.WithRejectionHandler(func(string, error, where.RejectionMeta) {
+		called = true
+	}))
+	require.NoError(t, err)
+
+	_, err = parser.Parse("age > 18")
+	require.NoError(t, err)
+	require.False(t, called)
+}
+
+func TestValidatorOnRejection(t *testing.T) {
+	var got []rejection
+	validator := where.NewValidator().
+		AllowFields("age", "status", "created_at").
+		AllowFunctions("LOWER").
+		ConstrainField("status", where.FieldConstraint{AllowedValues: []any{"active"}}).
+		RequireTimeRange("created_at", 24*time.Hour).
+		OnRejection(func(input string, err error, meta where.RejectionMeta) {
+			got = append(got, rejection{input: input, err: err.Error(), meta: meta})
+		})
+
+	timeRange := " AND created_at BETWEEN '2024-01-01' AND '2024-01-02'"
+	tests := []struct {
+		filter   string
+		wantMeta where.RejectionMeta
+	}{
+		{"password = 'x'" + timeRange, where.RejectionMeta{Rule: where.RuleField, Field: "password"}},
+		{"UPPER(status) = 'X'" + timeRange, where.RejectionMeta{Rule: where.RuleFunction, Function: "UPPER"}},
+		{"status = 'deleted'" + timeRange, where.RejectionMeta{Rule: where.RuleConstraint, Field: "status"}},
+		{"age > 18", where.RejectionMeta{Rule: where.RuleRequirement}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			got = nil
+			filter := mustParse(t, tt.filter)
+
+			_, _, err := filter.ToSQL("postgres", where.WithValidator(validator))
+			require.Error(t, err)
+			require.Equal(t, []rejection{{input: filter.String(), err: err.Error(), meta: tt.wantMeta}}, got)
+		})
+	}
+
+	got = nil
+	_, _, err := mustParse(t, "LOWER(status) = 'active'"+timeRange).ToSQL("postgres", where.WithValidator(validator))
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestValidatorOnRejectionIgnoresBuildErrors(t *testing.T) {
+	called := false
+	validator := where.NewValidator().AllowAll().OnRejection(func(string, error, where.RejectionMeta) {
+		called = true
+	})
+
+	_, _, err := mustParse(t, "a <=> 1").ToSQL("clickhouse", where.WithValidator(validator))
+	require.ErrorContains(t, err, "not supported")
+	require.False(t, called)
+}
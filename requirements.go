@@ -0,0 +1,277 @@
+package where
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// requirement is a server-side rule that a filter must satisfy before SQL is generated.
+	requirement func(filter *Filter) error
+
+	// timeBounds holds the lower and upper time bounds placed on a field by an expression, and
+	// whether each bound includes its value.
+	timeBounds struct {
+		lower          *time.Time
+		upper          *time.Time
+		lowerInclusive bool
+		upperInclusive bool
+	}
+)
+
+// RequireFields requires the filter to constrain each of the given fields for every row it matches.
+// A field is constrained when an = or IN predicate comparing it with literals or variables appears in
+// every OR branch and is not negated, so that tenant_id = tenant_id or tenant_id > 0 does not count.
+// Filters that do not satisfy the requirement fail SQL generation.
+func (v *Validator) RequireFields(fields ...string) *Validator {
+	for _, field := range fields {
+		v.requirements = append(v.requirements, func(filter *Filter) error {
+			if !exprConstrains(filter.Expression, field) {
+				return newMessage(MsgFieldRequired, "field", strconv.Quote(field))
+			}
+			return nil
+		})
+	}
+	return v
+}
+
+// RequireTimeRange requires the filter to bound the field with date/time literals on both sides,
+// with the bounded range no wider than maxWidth. Bounds may be supplied using BETWEEN or a pair of
+// comparisons combined with AND, e.g. "created_at >= '2024-01-01' AND created_at < '2024-02-01'".
+func (v *Validator) RequireTimeRange(field string, maxWidth time.Duration) *Validator {
+	v.requirements = append(v.requirements, func(filter *Filter) error {
+		bounds := exprTimeBounds(filter.Expression, field)
+		if bounds.lower == nil || bounds.upper == nil {
+			return newMessage(MsgTimeRangeRequired, "field", strconv.Quote(field))
+		}
+		if bounds.upper.Sub(*bounds.lower) > maxWidth {
+			return newMessage(MsgTimeRangeTooWide, "field", strconv.Quote(field), "max", maxWidth)
+		}
+		return nil
+	})
+	return v
+}
+
+// CheckRequirements returns an error if the filter does not satisfy the validator's required predicates.
+func (v *Validator) CheckRequirements(filter *Filter) error {
+	if filter == nil || filter.Expression == nil {
+		return nil
+	}
+
+	for _, req := range v.requirements {
+		if err := req(filter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exprConstrains(expr *Expression, field string) bool {
+	if expr == nil || len(expr.Or) == 0 {
+		return false
+	}
+
+	for _, term := range expr.Or {
+		if !termConstrains(term, field) {
+			return false
+		}
+	}
+	return true
+}
+
+func termConstrains(term *Term, field string) bool {
+	for _, factor := range term.And {
+		if factorConstrains(factor, field) {
+			return true
+		}
+	}
+	return false
+}
+
+func factorConstrains(factor *Factor, field string) bool {
+	if factor == nil || factor.Not {
+		return false
+	}
+	if factor.SubExpr != nil {
+		return exprConstrains(factor.SubExpr, field)
+	}
+	return predicateConstrains(factor.Predicate, field)
+}
+
+func predicateConstrains(pred *Predicate, field string) bool {
+	if pred == nil || pred.Operation == nil {
+		return false
+	}
+
+	op := pred.Operation
+	switch {
+	case op.Compare != nil && op.Compare.Operator.String() == "=":
+		return (isField(pred.Left, field) && isLiteral(op.Compare.Right)) ||
+			(isField(op.Compare.Right, field) && isLiteral(pred.Left))
+	case op.In != nil && !op.In.Not:
+		if !isField(pred.Left, field) {
+			return false
+		}
+		for _, val := range op.In.Values {
+			if !isLiteral(val) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func isField(val *Value, field string) bool {
+	return val != nil && val.Field != nil && strings.EqualFold(val.Field.String(), field)
+}
+
+func exprTimeBounds(expr *Expression, field string) timeBounds {
+	if expr == nil || len(expr.Or) == 0 {
+		return timeBounds{}
+	}
+
+	result := termTimeBounds(expr.Or[0], field)
+	for _, term := range expr.Or[1:] {
+		result = result.union(termTimeBounds(term, field))
+	}
+	return result
+}
+
+func termTimeBounds(term *Term, field string) timeBounds {
+	var result timeBounds
+	for _, factor := range term.And {
+		result = result.intersect(factorTimeBounds(factor, field))
+	}
+	return result
+}
+
+func factorTimeBounds(factor *Factor, field string) timeBounds {
+	if factor == nil || factor.Not {
+		return timeBounds{}
+	}
+	if factor.SubExpr != nil {
+		return exprTimeBounds(factor.SubExpr, field)
+	}
+	return predicateTimeBounds(factor.Predicate, field)
+}
+
+func predicateTimeBounds(pred *Predicate, field string) timeBounds {
+	if pred == nil || pred.Operation == nil {
+		return timeBounds{}
+	}
+
+	op := pred.Operation
+	if op.Between != nil && !op.Between.Not && isField(pred.Left, field) {
+		return timeBounds{
+			lower:          timeLiteral(op.Between.Lower),
+			upper:          timeLiteral(op.Between.Upper),
+			lowerInclusive: true,
+			upperInclusive: true,
+		}
+	}
+	if op.Compare == nil {
+		return timeBounds{}
+	}
+
+	operator := op.Compare.Operator.String()
+	var value *time.Time
+	switch {
+	case isField(pred.Left, field):
+		value = timeLiteral(op.Compare.Right)
+	case isField(op.Compare.Right, field):
+		value = timeLiteral(pred.Left)
+		operator = flipOperator(operator)
+	default:
+		return timeBounds{}
+	}
+
+	switch operator {
+	case "=", "<=>":
+		return timeBounds{lower: value, upper: value, lowerInclusive: true, upperInclusive: true}
+	case ">", ">=":
+		return timeBounds{lower: value, lowerInclusive: operator == ">="}
+	case "<", "<=":
+		return timeBounds{upper: value, upperInclusive: operator == "<="}
+	default:
+		return timeBounds{}
+	}
+}
+
+// flipOperator returns the comparison operator that is equivalent when the operands are swapped.
+func flipOperator(operator string) string {
+	switch operator {
+	case "<":
+		return ">"
+	case ">":
+		return "<"
+	case "<=":
+		return ">="
+	case ">=":
+		return "<="
+	default:
+		return operator
+	}
+}
+
+func isNotEqual(operator string) bool {
+	return operator == "!=" || operator == "<>"
+}
+
+func timeLiteral(val *Value) *time.Time {
+	if val == nil || val.Literal == nil {
+		return nil
+	}
+
+	if t, ok := val.Literal.Value().(time.Time); ok {
+		return &t
+	}
+
+	s, ok := val.Literal.Value().(string)
+	if !ok {
+		return nil
+	}
+
+	t, ok := parseTime(s, DefaultTimeLayouts, time.UTC)
+	if !ok {
+		return nil
+	}
+	return &t
+}
+
+// intersect combines bounds from AND-ed predicates, keeping the tightest bound on each side. Of two
+// bounds at the same time, the exclusive one is tighter.
+func (b timeBounds) intersect(other timeBounds) timeBounds {
+	if other.lower != nil && (b.lower == nil || other.lower.After(*b.lower) ||
+		(other.lower.Equal(*b.lower) && !other.lowerInclusive)) {
+		b.lower, b.lowerInclusive = other.lower, other.lowerInclusive
+	}
+	if other.upper != nil && (b.upper == nil || other.upper.Before(*b.upper) ||
+		(other.upper.Equal(*b.upper) && !other.upperInclusive)) {
+		b.upper, b.upperInclusive = other.upper, other.upperInclusive
+	}
+	return b
+}
+
+// union combines bounds from OR-ed branches. A side is only bounded if every branch bounds it.
+func (b timeBounds) union(other timeBounds) timeBounds {
+	switch {
+	case b.lower == nil || other.lower == nil:
+		b.lower, b.lowerInclusive = nil, false
+	case other.lower.Before(*b.lower):
+		b.lower, b.lowerInclusive = other.lower, other.lowerInclusive
+	case other.lower.Equal(*b.lower):
+		b.lowerInclusive = b.lowerInclusive || other.lowerInclusive
+	}
+	switch {
+	case b.upper == nil || other.upper == nil:
+		b.upper, b.upperInclusive = nil, false
+	case other.upper.After(*b.upper):
+		b.upper, b.upperInclusive = other.upper, other.upperInclusive
+	case other.upper.Equal(*b.upper):
+		b.upperInclusive = b.upperInclusive || other.upperInclusive
+	}
+	return b
+}
@@ -0,0 +1,115 @@
+package where_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireFields(t *testing.T) {
+	validator := where.NewValidator().AllowAll().RequireFields("tenant_id")
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "equality", input: "tenant_id = 1 AND age > 18"},
+		{name: "IN list", input: "tenant_id IN (1, 2)"},
+		{name: "reversed comparison", input: "1 = tenant_id"},
+		{name: "case insensitive", input: "TENANT_ID = 1"},
+		{name: "every OR branch", input: "(tenant_id = 1 AND a = 1) OR (tenant_id = 2 AND b = 2)"},
+		{name: "nested group", input: "a = 1 AND (tenant_id = 1 OR tenant_id = 2)"},
+		{name: "missing", input: "age > 18", wantErr: true},
+		{name: "only one OR branch", input: "tenant_id = 1 OR age > 18", wantErr: true},
+		{name: "negated", input: "NOT (tenant_id = 1)", wantErr: true},
+		{name: "not equal", input: "tenant_id != 1", wantErr: true},
+		{name: "NOT IN", input: "tenant_id NOT IN (1)", wantErr: true},
+		{name: "IS NULL", input: "tenant_id IS NULL", wantErr: true},
+		{name: "variable", input: "tenant_id = :tenant"},
+		{name: "compared with itself", input: "tenant_id = tenant_id", wantErr: true},
+		{name: "compared with another field", input: "tenant_id = owner_id", wantErr: true},
+		{name: "range", input: "tenant_id > 0", wantErr: true},
+		{name: "BETWEEN", input: "tenant_id BETWEEN 0 AND 1000000", wantErr: true},
+		{name: "LIKE", input: "tenant_id LIKE '%'", wantErr: true},
+		{name: "compared with a function", input: "tenant_id = ABS(tenant_id)", wantErr: true},
+		{name: "IN list with a field", input: "tenant_id IN (1, tenant_id)", wantErr: true},
+		{name: "compared with NULL", input: "tenant_id = NULL", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			_, _, err = filter.ToSQL("postgres", where.WithValidator(validator), where.WithVariables(map[string]any{"tenant": 1}))
+			if tt.wantErr {
+				require.EqualError(t, err, `filter must constrain field "tenant_id"`)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRequireTimeRange(t *testing.T) {
+	validator := where.NewValidator().AllowAll().RequireTimeRange("created_at", 90*24*time.Hour)
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "BETWEEN", input: "created_at BETWEEN '2024-01-01' AND '2024-03-01'"},
+		{name: "comparisons", input: "created_at >= '2024-01-01T00:00:00Z' AND created_at < '2024-02-01T00:00:00Z'"},
+		{name: "reversed comparison", input: "'2024-01-01' <= created_at AND created_at < '2024-02-01'"},
+		{name: "tightest bounds win", input: "created_at > '2020-01-01' AND created_at > '2024-01-01' AND created_at < '2024-02-01'"},
+		{name: "OR branches", input: "created_at BETWEEN '2024-01-01' AND '2024-01-02' OR created_at BETWEEN '2024-02-01' AND '2024-02-02'"},
+		{
+			name:    "too wide",
+			input:   "created_at BETWEEN '2024-01-01' AND '2024-12-31'",
+			wantErr: `time range for field "created_at" exceeds maximum of 2160h0m0s`,
+		},
+		{
+			name:    "OR branches too wide",
+			input:   "created_at BETWEEN '2024-01-01' AND '2024-01-02' OR created_at BETWEEN '2024-11-01' AND '2024-11-02'",
+			wantErr: `time range for field "created_at" exceeds maximum of 2160h0m0s`,
+		},
+		{
+			name:    "open ended",
+			input:   "created_at > '2024-01-01'",
+			wantErr: `filter must bound field "created_at" with a time range`,
+		},
+		{
+			name:    "unbounded OR branch",
+			input:   "created_at BETWEEN '2024-01-01' AND '2024-01-02' OR status = 'active'",
+			wantErr: `filter must bound field "created_at" with a time range`,
+		},
+		{
+			name:    "negated",
+			input:   "NOT (created_at BETWEEN '2024-01-01' AND '2024-01-02')",
+			wantErr: `filter must bound field "created_at" with a time range`,
+		},
+		{
+			name:    "non-time literals",
+			input:   "created_at BETWEEN 'yesterday' AND 'today'",
+			wantErr: `filter must bound field "created_at" with a time range`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			_, _, err = filter.ToSQL("postgres", where.WithValidator(validator))
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package where
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// rsqlReserved holds the characters that end an unquoted RSQL argument.
+const rsqlReserved = `"'();,=!<>~`
+
+var (
+	// rsqlOperators maps RSQL/FIQL comparison operators to the operators of structured JSON filters.
+	rsqlOperators = map[string]string{
+		"==":       "=",
+		"!=":       "!=",
+		"<":        "<",
+		"=lt=":     "<",
+		"<=":       "<=",
+		"=le=":     "<=",
+		">":        ">",
+		"=gt=":     ">",
+		">=":       ">=",
+		"=ge=":     ">=",
+		"=in=":     "in",
+		"=out=":    "not in",
+		"=like=":   "like",
+		"=ilike=":  "ilike",
+		"=isnull=": "is null",
+	}
+
+	// rsqlNumber matches unquoted arguments that are bound as numbers, using JSON's number syntax.
+	rsqlNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+	// rsqlWildcards escapes LIKE wildcards in an == argument and turns its * wildcards into %.
+	rsqlWildcards = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `*`, `%`)
+)
+
+type (
+	// rsqlParser is a recursive descent parser for RSQL/FIQL expressions, producing structured
+	// filter nodes.
+	rsqlParser struct {
+		input string
+		pos   int
+	}
+
+	// rsqlArg is an argument of an RSQL comparison.
+	rsqlArg struct {
+		text   string
+		quoted bool
+	}
+)
+
+// ParseRSQL parses an RSQL/FIQL expression, such as age=gt=18;status==active, into the same AST that
+// Parse returns for the equivalent expression. The filter is validated according to the parser's
+// configured options.
+//
+// Comparisons are joined with ; or "and", and with , or "or", where AND binds tighter and
+// parentheses group. The operators are ==, !=, =lt= (<), =le= (<=), =gt= (>), =ge= (>=), =in=,
+// =out=, =like=, =ilike=, and =isnull=, which takes true or false. =in= and =out= take a list such
+// as (a,b). An == or != argument containing * matches it as a wildcard using LIKE. Unquoted
+// arguments that are numbers, true, false, or null are bound as such; quote them with ' or " to
+// compare strings.
+//
+// Example:
+//
+//	filter, err := parser.ParseRSQL("age=gt=18;status=in=(active,pending)")
+//	// same as parser.Parse("age > 18 AND status IN ('active', 'pending')")
+func (p *Parser) ParseRSQL(input string) (*Filter, error) {
+	filter, err := p.parseRSQL(input)
+	if err != nil && p.opts.onReject != nil {
+		meta, _ := rejectionMeta(err)
+		p.opts.onReject(input, err, meta)
+	}
+	return filter, err
+}
+
+func (p *Parser) parseRSQL(input string) (*Filter, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, newMessage(MsgEmptyFilter)
+	}
+
+	if err := p.precheck(input); err != nil {
+		return nil, errors.Wrapf(err, "filter validation failed")
+	}
+
+	rp := &rsqlParser{input: input}
+	node, err := rp.or()
+	if err == nil && rp.skipSpace() < len(input) {
+		err = rp.errorf("unexpected %q", input[rp.pos:rp.pos+1])
+	}
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+
+	jb := &jsonBuilder{escapes: p.opts.escapes}
+	expr, err := jb.expression(node, "")
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+
+	filter := &Filter{Expression: expr}
+	if err := p.resolve(filter); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// ParseRSQL is a convenience function that creates a default parser and parses the RSQL/FIQL
+// expression. See Parser.ParseRSQL.
+func ParseRSQL(input string) (*Filter, error) {
+	parser, err := NewParser()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create parser")
+	}
+	return parser.ParseRSQL(input)
+}
+
+// or parses comparisons and groups joined with , or "or".
+func (rp *rsqlParser) or() (*jsonNode, error) {
+	return rp.list(",", "or", rp.and, func(nodes []*jsonNode) *jsonNode { return &jsonNode{Or: nodes} })
+}
+
+// and parses comparisons and groups joined with ; or "and".
+func (rp *rsqlParser) and() (*jsonNode, error) {
+	return rp.list(";", "and", rp.constraint, func(nodes []*jsonNode) *jsonNode { return &jsonNode{And: nodes} })
+}
+
+// list parses items joined by the separator or keyword, combining several into a group.
+func (rp *rsqlParser) list(sep, keyword string, item func() (*jsonNode, error), group func([]*jsonNode) *jsonNode) (*jsonNode, error) {
+	var nodes []*jsonNode
+	for {
+		node, err := item()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+
+		if !rp.consume(sep) && !rp.keyword(keyword) {
+			break
+		}
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return group(nodes), nil
+}
+
+// constraint parses a parenthesized group or a comparison.
+func (rp *rsqlParser) constraint() (*jsonNode, error) {
+	if rp.consume("(") {
+		node, err := rp.or()
+		if err != nil {
+			return nil, err
+		}
+		if !rp.consume(")") {
+			return nil, rp.errorf("expected )")
+		}
+		return node, nil
+	}
+	return rp.comparison()
+}
+
+// comparison parses selector operator argument(s).
+func (rp *rsqlParser) comparison() (*jsonNode, error) {
+	rp.skipSpace()
+	start := rp.pos
+	for rp.pos < len(rp.input) && !strings.ContainsRune(rsqlReserved, rune(rp.input[rp.pos])) &&
+		!unicode.IsSpace(rune(rp.input[rp.pos])) {
+		rp.pos++
+	}
+	field := rp.input[start:rp.pos]
+	if field == "" {
+		return nil, rp.errorf("expected selector")
+	}
+
+	opStart := rp.skipSpace()
+	symbol := rp.operator()
+	op, ok := rsqlOperators[strings.ToLower(symbol)]
+	if !ok {
+		rp.pos = opStart
+		if symbol != "" {
+			return nil, rp.errorf("unknown operator %q", symbol)
+		}
+		return nil, rp.errorf("expected comparison operator")
+	}
+
+	args, err := rp.arguments()
+	if err != nil {
+		return nil, err
+	}
+	return rsqlCondition(field, symbol, op, args, start)
+}
+
+// operator reads a comparison operator, either a symbol such as == or a FIQL name such as =gt=.
+func (rp *rsqlParser) operator() string {
+	for _, symbol := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(rp.input[rp.pos:], symbol) {
+			rp.pos += len(symbol)
+			return symbol
+		}
+	}
+
+	rest := rp.input[rp.pos:]
+	if !strings.HasPrefix(rest, "=") {
+		return ""
+	}
+	end := strings.IndexByte(rest[1:], '=')
+	if end < 0 {
+		return ""
+	}
+	symbol := rest[:end+2]
+	rp.pos += len(symbol)
+	return symbol
+}
+
+// arguments parses a single argument or a parenthesized list of arguments.
+func (rp *rsqlParser) arguments() ([]rsqlArg, error) {
+	if !rp.consume("(") {
+		arg, err := rp.argument()
+		if err != nil {
+			return nil, err
+		}
+		return []rsqlArg{arg}, nil
+	}
+
+	var args []rsqlArg
+	for {
+		arg, err := rp.argument()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		if rp.consume(")") {
+			return args, nil
+		}
+		if !rp.consume(",") {
+			return nil, rp.errorf("expected , or )")
+		}
+	}
+}
+
+// argument parses a quoted or unquoted argument. Quoted arguments may escape characters with \.
+func (rp *rsqlParser) argument() (rsqlArg, error) {
+	start := rp.skipSpace()
+	if start == len(rp.input) {
+		return rsqlArg{}, rp.errorf("expected argument")
+	}
+
+	quote := rp.input[start]
+	if quote != '\'' && quote != '"' {
+		for rp.pos < len(rp.input) && !strings.ContainsRune(rsqlReserved, rune(rp.input[rp.pos])) &&
+			!unicode.IsSpace(rune(rp.input[rp.pos])) {
+			rp.pos++
+		}
+		if rp.pos == start {
+			return rsqlArg{}, rp.errorf("expected argument")
+		}
+		return rsqlArg{text: rp.input[start:rp.pos]}, nil
+	}
+
+	var sb strings.Builder
+	for rp.pos++; rp.pos < len(rp.input); rp.pos++ {
+		switch c := rp.input[rp.pos]; {
+		case c == quote:
+			rp.pos++
+			return rsqlArg{text: sb.String(), quoted: true}, nil
+		case c == '\\' && rp.pos+1 < len(rp.input):
+			rp.pos++
+			sb.WriteByte(rp.input[rp.pos])
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	rp.pos = start
+	return rsqlArg{}, rp.errorf("unterminated string")
+}
+
+// consume skips whitespace and the token if it is next, returning whether it was.
+func (rp *rsqlParser) consume(token string) bool {
+	rp.skipSpace()
+	if strings.HasPrefix(rp.input[rp.pos:], token) {
+		rp.pos += len(token)
+		return true
+	}
+	return false
+}
+
+// keyword consumes the logical operator and or or, which must be surrounded by whitespace.
+func (rp *rsqlParser) keyword(word string) bool {
+	if rp.pos == 0 || !unicode.IsSpace(rune(rp.input[rp.pos-1])) {
+		return false
+	}
+
+	end := rp.skipSpace() + len(word)
+	if end >= len(rp.input) || !strings.EqualFold(rp.input[rp.pos:end], word) ||
+		!unicode.IsSpace(rune(rp.input[end])) {
+		return false
+	}
+	rp.pos = end
+	return true
+}
+
+// skipSpace advances past whitespace and returns the new position.
+func (rp *rsqlParser) skipSpace() int {
+	for rp.pos < len(rp.input) && unicode.IsSpace(rune(rp.input[rp.pos])) {
+		rp.pos++
+	}
+	return rp.pos
+}
+
+func (rp *rsqlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), rp.pos+1)
+}
+
+// rsqlCondition returns the condition node for a comparison.
+func rsqlCondition(field, symbol, op string, args []rsqlArg, pos int) (*jsonNode, error) {
+	path := fmt.Sprintf("%s%s at position %d", field, symbol, pos+1)
+
+	multiple := op == "in" || op == "not in"
+	if !multiple && len(args) != 1 {
+		return nil, errors.Errorf("%s: operator %s requires a single argument", path, symbol)
+	}
+
+	if op == "is null" {
+		switch strings.ToLower(args[0].text) {
+		case "true":
+		case "false":
+			op = "is not null"
+		default:
+			return nil, errors.Errorf("%s: operator %s requires true or false", path, symbol)
+		}
+		return &jsonNode{Field: field, Op: op, path: path}, nil
+	}
+
+	if (op == "=" || op == "!=") && strings.Contains(args[0].text, "*") {
+		args[0] = rsqlArg{text: rsqlWildcards.Replace(args[0].text), quoted: true}
+		op = map[string]string{"=": "like", "!=": "not like"}[op]
+	}
+
+	if !multiple {
+		return &jsonNode{Field: field, Op: op, Value: args[0].value(), path: path}, nil
+	}
+
+	values := make([]json.RawMessage, len(args))
+	for i, arg := range args {
+		values[i] = arg.value()
+	}
+	raw, _ := json.Marshal(values)
+	return &jsonNode{Field: field, Op: op, Value: raw, path: path}, nil
+}
+
+// value returns the argument as a JSON value. Unquoted numbers, booleans, and null keep their types.
+func (a rsqlArg) value() json.RawMessage {
+	if !a.quoted {
+		switch lower := strings.ToLower(a.text); {
+		case lower == "true" || lower == "false" || lower == "null":
+			return json.RawMessage(lower)
+		case rsqlNumber.MatchString(a.text):
+			return json.RawMessage(a.text)
+		}
+	}
+
+	raw, _ := json.Marshal(a.text)
+	return raw
+}
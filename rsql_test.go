@@ -0,0 +1,150 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRSQL(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "and",
+			input: "age=gt=18;status==active",
+			want:  "age > 18 AND status = 'active'",
+		},
+		{
+			name:  "symbols",
+			input: "a<1;b<=2.5;c>-3;d>=4;e!=x",
+			want:  "a < 1 AND b <= 2.5 AND c > -3 AND d >= 4 AND e != 'x'",
+		},
+		{
+			name:  "or binds looser than and",
+			input: "a==1,b==2;c==3",
+			want:  "a = 1 OR b = 2 AND c = 3",
+		},
+		{
+			name:  "groups and keywords",
+			input: "age=ge=18 and (status=in=(active,'pending') or users.role=out=(guest))",
+			want:  "age >= 18 AND (status IN ('active', 'pending') OR users.role NOT IN ('guest'))",
+		},
+		{
+			name:  "quoted strings",
+			input: `name=="O'Brien \"Jr\"";code=='42';flag==true;note==null`,
+			want:  `name = 'O''Brien "Jr"' AND code = '42' AND flag = TRUE AND note = NULL`,
+		},
+		{
+			name:  "wildcards",
+			input: `name==Jo*;email!='*@example_test.com'`,
+			want:  `name LIKE 'Jo%' AND email NOT LIKE '%@example\\_test.com'`,
+		},
+		{
+			name:  "extensions",
+			input: "name=ilike='j%';deleted_at=isnull=true;email=isnull=FALSE;title=like=x%",
+			want:  "name ILIKE 'j%' AND deleted_at IS NULL AND email IS NOT NULL AND title LIKE 'x%'",
+		},
+		{
+			name:  "case-insensitive operators",
+			input: "age=GT=1 OR age=LT=0",
+			want:  "age > 1 OR age < 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.ParseRSQL(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, filter.String())
+
+			want, err := where.Parse(tt.want)
+			require.NoError(t, err)
+			requireEqualAST(t, want.Expression, filter.Expression)
+		})
+	}
+}
+
+func TestParseRSQLWildcardParams(t *testing.T) {
+	filter, err := where.ParseRSQL(`name==*100%_off*`)
+	require.NoError(t, err)
+
+	sql, params, err := filter.ToSQL("mysql")
+	require.NoError(t, err)
+	require.Equal(t, "name LIKE ?", sql)
+	require.Equal(t, []any{`%100\%\_off%`}, params)
+}
+
+func TestParseRSQLErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "empty",
+			input: "  ",
+			err:   "empty filter expression",
+		},
+		{
+			name:  "unknown operator",
+			input: "a=foo=1",
+			err:   `failed to parse filter expression: unknown operator "=foo=" at position 2`,
+		},
+		{
+			name:  "missing operator",
+			input: "a b==1",
+			err:   "failed to parse filter expression: expected comparison operator at position 3",
+		},
+		{
+			name:  "missing argument",
+			input: "a==1;b==",
+			err:   "failed to parse filter expression: expected argument at position 9",
+		},
+		{
+			name:  "unterminated string",
+			input: "a=='x",
+			err:   "failed to parse filter expression: unterminated string at position 4",
+		},
+		{
+			name:  "unbalanced parentheses",
+			input: "(a==1;b==2",
+			err:   "failed to parse filter expression: expected ) at position 11",
+		},
+		{
+			name:  "trailing input",
+			input: "a==1)",
+			err:   `failed to parse filter expression: unexpected ")" at position 5`,
+		},
+		{
+			name:  "list for single argument",
+			input: "a==(1,2)",
+			err:   "failed to parse filter expression: a== at position 1: operator == requires a single argument",
+		},
+		{
+			name:  "isnull argument",
+			input: "a=isnull=yes",
+			err:   "failed to parse filter expression: a=isnull= at position 1: operator =isnull= requires true or false",
+		},
+		{
+			name:  "invalid selector",
+			input: "b==1;a-b==1",
+			err:   `failed to parse filter expression: a-b== at position 6: invalid field "a-b"`,
+		},
+		{
+			name:  "validated like parsed filters",
+			input: "((((((((((((((((a==1))))))))))))))))",
+			err:   "filter validation failed: expression depth exceeds maximum of 10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := where.ParseRSQL(tt.input)
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
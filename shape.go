@@ -0,0 +1,71 @@
+package where
+
+import (
+	"slices"
+)
+
+// defaultINBuckets are the IN list sizes used by WithStableShape when no buckets are given.
+var defaultINBuckets = []int{1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024}
+
+// WithStableShape returns a BuildOption that generates the same SQL text for filters that only
+// differ in the number of IN list values, so prepared statement caches keyed by SQL text hit.
+// IN lists are bound as a single array parameter for drivers implementing ArrayBinder. Otherwise
+// lists of literals, including NULL, are padded to the next bucket size by repeating their last
+// value, which does not change the result. Lists longer than the largest bucket are padded to a
+// multiple of it.
+// TRUE, FALSE, and NULL literals are bound as parameters, as with WithBindConstants.
+// Buckets default to powers of two up to 1024. Combining it with WithParamDeduplication makes the
+// placeholder numbering depend on the values, so avoid it when a stable shape is required.
+func WithStableShape(buckets ...int) BuildOption {
+	return func(b *SQLBuilder) {
+		b.arrayBinding = true
+		b.bindConsts = true
+		b.inBuckets = defaultINBuckets
+		if len(buckets) > 0 {
+			b.inBuckets = slices.Sorted(slices.Values(buckets))
+		}
+	}
+}
+
+// padIn pads the built IN list items to the configured bucket size.
+func (b *SQLBuilder) padIn(in *InOp, items []string) ([]string, error) {
+	if len(b.inBuckets) == 0 || !isLiteralList(in.Values) {
+		return items, nil
+	}
+
+	size := bucketSize(len(items), b.inBuckets)
+	for len(items) < size {
+		item, err := b.buildLiteralValue(in.Values[len(in.Values)-1].Literal)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// isLiteralList returns true if every value is a literal or variable.
+func isLiteralList(values []*Value) bool {
+	for _, item := range values {
+		if item == nil || item.Literal == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// bucketSize returns the smallest bucket that fits n values, or the next multiple of the largest
+// bucket if none do. Buckets must be sorted in ascending order.
+func bucketSize(n int, buckets []int) int {
+	for _, size := range buckets {
+		if size >= n {
+			return size
+		}
+	}
+
+	largest := buckets[len(buckets)-1]
+	if largest <= 0 {
+		return n
+	}
+	return (n + largest - 1) / largest * largest
+}
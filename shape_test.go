@@ -0,0 +1,117 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStableShape(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		input    string
+		opts     []where.BuildOption
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "padded to power of two",
+			driver:   "mysql",
+			input:    "id IN (1, 2, 3)",
+			wantSQL:  "id IN (?, ?, ?, ?)",
+			wantArgs: []any{float64(1), float64(2), float64(3), float64(3)},
+		},
+		{
+			name:     "exact bucket unchanged",
+			driver:   "mysql",
+			input:    "id NOT IN ('a', 'b')",
+			wantSQL:  "id NOT IN (?, ?)",
+			wantArgs: []any{"a", "b"},
+		},
+		{
+			name:     "custom buckets",
+			driver:   "mysql",
+			input:    "id IN (1, 2)",
+			opts:     []where.BuildOption{where.WithStableShape(10, 5)},
+			wantSQL:  "id IN (?, ?, ?, ?, ?)",
+			wantArgs: []any{float64(1), float64(2), float64(2), float64(2), float64(2)},
+		},
+		{
+			name:     "beyond largest bucket",
+			driver:   "mysql",
+			input:    "id IN (1, 2, 3, 4, 5)",
+			opts:     []where.BuildOption{where.WithStableShape(2)},
+			wantSQL:  "id IN (?, ?, ?, ?, ?, ?)",
+			wantArgs: []any{float64(1), float64(2), float64(3), float64(4), float64(5), float64(5)},
+		},
+		{
+			name:     "NULL and booleans padded",
+			driver:   "mysql",
+			input:    "id IN (1, NULL, 3)",
+			wantSQL:  "id IN (?, ?, ?, ?)",
+			wantArgs: []any{float64(1), nil, float64(3), float64(3)},
+		},
+		{
+			name:     "padded with NULL",
+			driver:   "postgres",
+			input:    "flag NOT IN (true, NULL)",
+			opts:     []where.BuildOption{where.WithStableShape(4)},
+			wantSQL:  "flag NOT IN ($1, $2, $3, $4)",
+			wantArgs: []any{true, nil, nil, nil},
+		},
+		{
+			name:     "non-literal values unchanged",
+			driver:   "mysql",
+			input:    "id IN (1, 2, other_id)",
+			wantSQL:  "id IN (?, ?, other_id)",
+			wantArgs: []any{float64(1), float64(2)},
+		},
+		{
+			name:     "constants bound",
+			driver:   "mysql",
+			input:    "active = true",
+			wantSQL:  "active = ?",
+			wantArgs: []any{true},
+		},
+		{
+			name:     "array binding when supported",
+			driver:   "postgres",
+			input:    "id IN (1, 2, 3)",
+			wantSQL:  "id = ANY($1)",
+			wantArgs: []any{[]float64{1, 2, 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			opts := tt.opts
+			if opts == nil {
+				opts = []where.BuildOption{where.WithStableShape()}
+			}
+
+			sql, args, err := filter.ToSQL(tt.driver, opts...)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestStableShapeIsStable(t *testing.T) {
+	var shapes []string
+	for _, input := range []string{"id IN (1, 2, 3)", "id IN (4, 5, 6, 7)", "id IN (9, 9, 9)"} {
+		filter, err := where.Parse(input)
+		require.NoError(t, err)
+
+		sql, _, err := filter.ToSQL("mysql", where.WithStableShape())
+		require.NoError(t, err)
+		shapes = append(shapes, sql)
+	}
+
+	require.Equal(t, []string{"id IN (?, ?, ?, ?)", "id IN (?, ?, ?, ?)", "id IN (?, ?, ?, ?)"}, shapes)
+}
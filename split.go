@@ -0,0 +1,84 @@
+package where
+
+// Split partitions the filter into the conditions whose predicates all satisfy match and the
+// remaining conditions, such that matched AND rest is equivalent to the original filter. This allows
+// part of a filter to be pushed down to a database while the rest is evaluated elsewhere.
+//
+// Only the top-level AND conditions are partitioned. A parenthesized or negated condition is matched
+// as a whole only when every predicate inside it matches, so OR branches are never separated; a
+// filter with OR at its root is returned entirely as matched or as rest. EXISTS conditions are never
+// matched. Either result is nil when it has no conditions. The returned filters share AST nodes with f.
+//
+// Example:
+//
+//	filter, _ := where.Parse("tenant_id = 1 AND (status = 'a' OR LOWER(name) = 'b')")
+//	db, app := filter.Split(func(pred *where.Predicate) bool {
+//		return pred.Left.Field != nil
+//	})
+//	// db:  tenant_id = 1
+//	// app: (status = 'a' OR LOWER(name) = 'b')
+func (f *Filter) Split(match func(pred *Predicate) bool) (matched, rest *Filter) {
+	if f == nil || f.Expression == nil {
+		return nil, nil
+	}
+
+	if len(f.Expression.Or) != 1 {
+		if exprMatches(f.Expression, match) {
+			return f, nil
+		}
+		return nil, f
+	}
+
+	var matchedFactors, restFactors []*Factor
+	for _, factor := range f.Expression.Or[0].And {
+		if factorMatches(factor, match) {
+			matchedFactors = append(matchedFactors, factor)
+		} else {
+			restFactors = append(restFactors, factor)
+		}
+	}
+
+	return f.withFactors(matchedFactors), f.withFactors(restFactors)
+}
+
+// withFactors returns a filter with the AND of the factors, or nil when there are none.
+func (f *Filter) withFactors(factors []*Factor) *Filter {
+	if len(factors) == 0 {
+		return nil
+	}
+	return &Filter{
+		Pos:        f.Pos,
+		Expression: &Expression{Or: []*Term{{And: factors}}},
+	}
+}
+
+func exprMatches(expr *Expression, match func(pred *Predicate) bool) bool {
+	if expr == nil || len(expr.Or) == 0 {
+		return false
+	}
+
+	for _, term := range expr.Or {
+		if term == nil || len(term.And) == 0 {
+			return false
+		}
+		for _, factor := range term.And {
+			if !factorMatches(factor, match) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func factorMatches(factor *Factor, match func(pred *Predicate) bool) bool {
+	switch {
+	case factor == nil || factor.Exists != nil:
+		return false
+	case factor.SubExpr != nil:
+		return exprMatches(factor.SubExpr, match)
+	case factor.Predicate != nil:
+		return match(factor.Predicate)
+	default:
+		return false
+	}
+}
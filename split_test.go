@@ -0,0 +1,102 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	// Pushes down predicates that compare a plain field.
+	pushdown := func(pred *where.Predicate) bool {
+		return pred.Left.Field != nil
+	}
+
+	tests := []struct {
+		name        string
+		filter      string
+		wantMatched string
+		wantRest    string
+	}{
+		{
+			name:        "partitions AND conditions",
+			filter:      "tenant_id = 1 AND LOWER(name) = 'x' AND age > 18",
+			wantMatched: "tenant_id = 1 AND age > 18",
+			wantRest:    "LOWER(name) = 'x'",
+		},
+		{
+			name:        "keeps OR groups together",
+			filter:      "tenant_id = 1 AND (status = 'a' OR LOWER(name) = 'b') AND NOT (age < 18 OR age > 65)",
+			wantMatched: "tenant_id = 1 AND NOT (age < 18 OR age > 65)",
+			wantRest:    "(status = 'a' OR LOWER(name) = 'b')",
+		},
+		{
+			name:        "OR root that matches",
+			filter:      "a = 1 OR b = 2",
+			wantMatched: "a = 1 OR b = 2",
+		},
+		{
+			name:     "OR root that does not match",
+			filter:   "a = 1 OR LENGTH(b) = 2",
+			wantRest: "a = 1 OR LENGTH(b) = 2",
+		},
+		{
+			name:        "everything matches",
+			filter:      "a = 1 AND b IN (1, 2)",
+			wantMatched: "a = 1 AND b IN (1, 2)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.filter)
+			require.NoError(t, err)
+
+			matched, rest := filter.Split(pushdown)
+			requireFilter(t, tt.wantMatched, matched)
+			requireFilter(t, tt.wantRest, rest)
+		})
+	}
+}
+
+func TestSplitBuildsSQL(t *testing.T) {
+	filter, err := where.Parse("tenant_id = 1 AND LOWER(name) = 'x'")
+	require.NoError(t, err)
+
+	matched, rest := filter.Split(func(pred *where.Predicate) bool {
+		return pred.Left.Field != nil
+	})
+
+	sql, args, err := matched.ToSQL("postgres")
+	require.NoError(t, err)
+	require.Equal(t, "tenant_id = $1", sql)
+	require.Equal(t, []any{float64(1)}, args)
+
+	sql, args, err = rest.ToSQL("postgres")
+	require.NoError(t, err)
+	require.Equal(t, "LOWER(name) = $1", sql)
+	require.Equal(t, []any{"x"}, args)
+}
+
+func TestSplitExists(t *testing.T) {
+	matched, rest := where.Exists("SELECT 1").Split(func(*where.Predicate) bool { return true })
+	require.Nil(t, matched)
+	require.NotNil(t, rest)
+
+	var filter *where.Filter
+	matched, rest = filter.Split(func(*where.Predicate) bool { return true })
+	require.Nil(t, matched)
+	require.Nil(t, rest)
+}
+
+func requireFilter(t *testing.T, want string, filter *where.Filter) {
+	t.Helper()
+
+	if want == "" {
+		require.Nil(t, filter)
+		return
+	}
+	require.NotNil(t, filter)
+	require.Equal(t, want, filter.String())
+}
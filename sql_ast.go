@@ -0,0 +1,397 @@
+package where
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// SQLNode is a node of the WHERE clause returned by Filter.ToAST: a conjunction, disjunction,
+	// negation, comparison, boolean column, or raw SQL condition.
+	SQLNode interface {
+		// SQL renders the node as it appears in the SQL returned by ToSQL, with ? placeholders for
+		// its parameters, which are returned in order.
+		SQL() (string, []any)
+	}
+
+	// SQLAnd is true when all of its conditions are true.
+	SQLAnd struct {
+		Conditions []SQLNode
+	}
+
+	// SQLOr is true when any of its conditions is true.
+	SQLOr struct {
+		Conditions []SQLNode
+	}
+
+	// SQLNot negates its condition.
+	SQLNot struct {
+		Condition SQLNode
+	}
+
+	// SQLComparison compares a column with parameters. Operator is the operator as rendered for the
+	// driver, e.g. "=", "<>", "NOT LIKE", "IN", "BETWEEN", "IS NULL", or "IS NOT DISTINCT FROM".
+	// IN and NOT IN have an operand for each value, BETWEEN and NOT BETWEEN the lower and upper
+	// bounds, IS NULL and IS NOT NULL none, and other operators one.
+	SQLComparison struct {
+		Column   *SQLColumn
+		Operator string
+		Operands []*SQLParam
+	}
+
+	// SQLColumn references a column. Used as a condition on its own, it is a boolean column.
+	SQLColumn struct {
+		// Parts holds the unquoted names of the column, e.g. ["users", "age"], after the
+		// validator's field mappings are applied.
+		Parts []string
+
+		// Quoted is the column as rendered for the driver, e.g. "users"."order".
+		Quoted string
+	}
+
+	// SQLParam is a bound parameter.
+	SQLParam struct {
+		Value any
+	}
+
+	// SQLRaw is a condition that has no structured representation, e.g. one calling functions,
+	// comparing two columns, or using database-specific syntax. Its SQL uses ? placeholders for
+	// Params.
+	SQLRaw struct {
+		Text   string
+		Params []any
+	}
+)
+
+// ToAST builds the filter for the specified database driver like ToSQL, but returns the WHERE
+// clause as a tree of nodes rather than a string, so query builders such as goqu can compose it
+// without re-parsing the SQL. Conditions comparing a column with literals are returned as
+// SQLComparison nodes, built from the filter's operations and the driver's operator translations,
+// and any others as SQLRaw nodes holding their SQL. Every node renders to exactly the SQL ToSQL returns,
+// but with ? placeholders for every driver, so parameters are never deduplicated.
+//
+// Example:
+//
+//	filter, _ := where.Parse("age >= 18 AND (status IN ('a', 'b') OR LOWER(name) = 'x')")
+//	node, _ := filter.ToAST("postgres")
+//	// &SQLAnd{Conditions: []SQLNode{
+//	//	&SQLComparison{Column: &SQLColumn{Parts: []string{"age"}, Quoted: "age"}, Operator: ">=", Operands: [18]},
+//	//	&SQLOr{Conditions: []SQLNode{
+//	//		&SQLComparison{Column: status, Operator: "IN", Operands: [a b]},
+//	//		&SQLRaw{Text: "LOWER(name) = ?", Params: [x]},
+//	//	}},
+//	// }}
+func (f *Filter) ToAST(driverName string, options ...BuildOption) (SQLNode, error) {
+	options = append(options, func(b *SQLBuilder) { b.positional = true })
+
+	builder, err := newSQLBuilder(f, driverName, options)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := builder.astExpression(f.Expression)
+	if err != nil {
+		return nil, builder.notifyRejection(f, err)
+	}
+
+	node, err = builder.astRequired(node)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := builder.checkParamLimit(); err != nil {
+		return nil, err
+	}
+
+	sql, _ := node.SQL()
+	if err := builder.auditSQL(sql); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// astRequired ANDs the required filters onto the user filter, like applyRequired.
+func (b *SQLBuilder) astRequired(node SQLNode) (SQLNode, error) {
+	if len(b.required) == 0 {
+		return node, nil
+	}
+
+	validator := b.validator
+	b.validator = nil
+	defer func() { b.validator = validator }()
+
+	and := &SQLAnd{Conditions: []SQLNode{node}}
+	for _, req := range b.required {
+		if req == nil || req.Expression == nil {
+			return nil, errors.New("empty required filter")
+		}
+
+		part, err := b.astExpression(req.Expression)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to build required filter")
+		}
+		and.Conditions = append(and.Conditions, part)
+	}
+	return and, nil
+}
+
+func (b *SQLBuilder) astExpression(expr *Expression) (SQLNode, error) {
+	if expr == nil || len(expr.Or) == 0 {
+		return nil, errors.New("empty expression")
+	}
+
+	if len(expr.Or) == 1 {
+		return b.astTerm(expr.Or[0])
+	}
+
+	or := &SQLOr{Conditions: make([]SQLNode, len(expr.Or))}
+	for i, term := range expr.Or {
+		node, err := b.astTerm(term)
+		if err != nil {
+			return nil, err
+		}
+		or.Conditions[i] = node
+	}
+	return or, nil
+}
+
+func (b *SQLBuilder) astTerm(term *Term) (SQLNode, error) {
+	if term == nil || len(term.And) == 0 {
+		return nil, errors.New("empty term")
+	}
+
+	if b.mergeRanges {
+		term = &Term{And: mergeRangeFactors(term.And)}
+	}
+
+	if len(term.And) == 1 {
+		return b.astFactor(term.And[0])
+	}
+
+	and := &SQLAnd{Conditions: make([]SQLNode, len(term.And))}
+	for i, factor := range term.And {
+		node, err := b.astFactor(factor)
+		if err != nil {
+			return nil, err
+		}
+		and.Conditions[i] = node
+	}
+	return and, nil
+}
+
+func (b *SQLBuilder) astFactor(factor *Factor) (SQLNode, error) {
+	if factor == nil {
+		return nil, errors.New("empty factor")
+	}
+
+	var (
+		node SQLNode
+		err  error
+	)
+	switch {
+	case factor.Exists != nil:
+		start := len(b.params)
+		sql, err := b.buildExists(factor.Exists, factor.Not)
+		if err != nil {
+			return nil, err
+		}
+		return &SQLRaw{Text: sql, Params: b.paramsSince(start)}, nil
+	case factor.SubExpr != nil:
+		node, err = b.astExpression(factor.SubExpr)
+	case factor.Predicate != nil:
+		node, err = b.astPredicate(factor.Predicate)
+	default:
+		return nil, errors.New("empty factor content")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if factor.Not {
+		return &SQLNot{Condition: node}, nil
+	}
+	return node, nil
+}
+
+// astPredicate builds the predicate and returns it as a comparison or boolean column when it
+// compares a column with literals, or as raw SQL otherwise.
+func (b *SQLBuilder) astPredicate(pred *Predicate) (SQLNode, error) {
+	start := len(b.params)
+	sql, err := b.buildPredicate(pred)
+	if err != nil {
+		return nil, err
+	}
+	params := b.paramsSince(start)
+	raw := &SQLRaw{Text: sql, Params: params}
+
+	node, ok, err := b.astComparison(pred, params)
+	if err != nil || !ok {
+		return raw, err
+	}
+
+	// Rewrites made while building, such as case folding, casts, ESCAPE clauses, or array binding,
+	// change the SQL of the comparison, which is then kept as raw SQL.
+	if rendered, _ := node.SQL(); rendered != sql {
+		return raw, nil
+	}
+	return node, nil
+}
+
+// astComparison returns the comparison or boolean column the predicate builds, taking the operator
+// from the operation as translated for the driver and the operands from the parameters it bound. It
+// returns false when the left side is not a column or the right side is not made of literals.
+func (b *SQLBuilder) astComparison(pred *Predicate, params []any) (SQLNode, bool, error) {
+	pred, err := b.nullComparison(pred)
+	if err != nil || pred.Left == nil || pred.Left.Field == nil {
+		return nil, false, err
+	}
+	if _, ok := b.fragment(pred.Left.Field); ok {
+		return nil, false, nil
+	}
+	if _, _, ok := b.expansion(pred); ok {
+		return nil, false, nil
+	}
+
+	column := b.astColumn(pred.Left.Field)
+	if isBooleanPredicate(pred) && b.driver.SupportsFeature("BOOLEAN") {
+		return column, true, nil
+	}
+
+	var (
+		operator string
+		operands []*Value
+	)
+	op := pred.Operation
+	switch {
+	case op.Compare != nil:
+		if operator, err = b.compareOperator(op.Compare); err != nil {
+			return nil, false, err
+		}
+		operands = []*Value{op.Compare.Right}
+	case op.Like != nil:
+		written, translated, err := b.likeOperator(op.Like)
+		if err != nil {
+			return nil, false, err
+		}
+		// ILIKE rewritten as LIKE compares lowercased values rather than the column.
+		if strings.Contains(written, "ILIKE") && !strings.Contains(translated, "ILIKE") {
+			return nil, false, nil
+		}
+		operator, operands = translated, []*Value{op.Like.Pattern}
+	case op.Between != nil:
+		operator, operands = "BETWEEN", []*Value{op.Between.Lower, op.Between.Upper}
+		if op.Between.Not {
+			operator = "NOT BETWEEN"
+		}
+	case op.In != nil:
+		if len(op.In.Values) == 0 {
+			return nil, false, nil
+		}
+		operator, operands = "IN", op.In.Values
+		if op.In.Not {
+			operator = "NOT IN"
+		}
+	case op.IsNull != nil:
+		operator = "IS NULL"
+		if op.IsNull.Not {
+			operator = "IS NOT NULL"
+		}
+	default:
+		return nil, false, nil
+	}
+
+	for _, operand := range operands {
+		if operand == nil || operand.Literal == nil {
+			return nil, false, nil
+		}
+	}
+
+	node := &SQLComparison{Column: column, Operator: operator, Operands: make([]*SQLParam, len(params))}
+	for i, param := range params {
+		node.Operands[i] = &SQLParam{Value: param}
+	}
+	return node, true, nil
+}
+
+// astColumn returns the column a field refers to, after the validator's field mappings.
+func (b *SQLBuilder) astColumn(field *FieldRef) *SQLColumn {
+	names := b.columnNames(field)
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = b.quoteIdentifier(name)
+	}
+	return &SQLColumn{Parts: names, Quoted: strings.Join(quoted, ".")}
+}
+
+// paramsSince returns a copy of the parameters bound since start.
+func (b *SQLBuilder) paramsSince(start int) []any {
+	return append([]any{}, b.params[start:]...)
+}
+
+// SQL renders the conditions joined with AND.
+func (n *SQLAnd) SQL() (string, []any) {
+	return joinNodes(n.Conditions, " AND ")
+}
+
+// SQL renders the conditions joined with OR.
+func (n *SQLOr) SQL() (string, []any) {
+	return joinNodes(n.Conditions, " OR ")
+}
+
+// SQL renders the negated condition.
+func (n *SQLNot) SQL() (string, []any) {
+	sql, params := n.Condition.SQL()
+	return "NOT (" + sql + ")", params
+}
+
+// SQL renders the comparison.
+func (n *SQLComparison) SQL() (string, []any) {
+	params := make([]any, len(n.Operands))
+	for i, operand := range n.Operands {
+		params[i] = operand.Value
+	}
+
+	sql := n.Column.Quoted + " " + n.Operator
+	switch {
+	case strings.HasPrefix(n.Operator, "IS ") && strings.HasSuffix(n.Operator, "NULL"):
+	case strings.HasSuffix(n.Operator, "BETWEEN"):
+		sql += " ? AND ?"
+	case strings.HasSuffix(n.Operator, "IN"):
+		sql += " (" + strings.TrimSuffix(strings.Repeat("?, ", len(params)), ", ") + ")"
+	default:
+		sql += " ?"
+	}
+	return sql, params
+}
+
+// SQL renders the quoted column.
+func (n *SQLColumn) SQL() (string, []any) {
+	return n.Quoted, nil
+}
+
+// SQL renders the placeholder of the parameter.
+func (n *SQLParam) SQL() (string, []any) {
+	return "?", []any{n.Value}
+}
+
+// SQL returns the raw SQL and its parameters.
+func (n *SQLRaw) SQL() (string, []any) {
+	return n.Text, n.Params
+}
+
+func joinNodes(nodes []SQLNode, sep string) (string, []any) {
+	parts := make([]string, len(nodes))
+	params := make([]any, 0)
+	for i, node := range nodes {
+		sql, nodeParams := node.SQL()
+		parts[i] = sql
+		params = append(params, nodeParams...)
+	}
+
+	if len(parts) == 1 {
+		return parts[0], params
+	}
+	return "(" + strings.Join(parts, sep) + ")", params
+}
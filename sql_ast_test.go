@@ -0,0 +1,189 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	_ "github.com/pseudomuto/where/drivers/ansi"
+	_ "github.com/pseudomuto/where/drivers/mysql"
+	_ "github.com/pseudomuto/where/drivers/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToAST(t *testing.T) {
+	column := func(parts ...string) *where.SQLColumn {
+		return &where.SQLColumn{Parts: parts, Quoted: parts[len(parts)-1]}
+	}
+	compare := func(col *where.SQLColumn, op string, values ...any) *where.SQLComparison {
+		operands := make([]*where.SQLParam, len(values))
+		for i, v := range values {
+			operands[i] = &where.SQLParam{Value: v}
+		}
+		return &where.SQLComparison{Column: col, Operator: op, Operands: operands}
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  where.SQLNode
+	}{
+		{
+			name:  "comparison",
+			input: "age >= 18",
+			want:  compare(column("age"), ">=", float64(18)),
+		},
+		{
+			name:  "conjunctions and negation",
+			input: "age >= 18 AND NOT (status = 'x' OR status IS NULL)",
+			want: &where.SQLAnd{Conditions: []where.SQLNode{
+				compare(column("age"), ">=", float64(18)),
+				&where.SQLNot{Condition: &where.SQLOr{Conditions: []where.SQLNode{
+					compare(column("status"), "=", "x"),
+					compare(column("status"), "IS NULL"),
+				}}},
+			}},
+		},
+		{
+			name:  "list and range operators",
+			input: "status NOT IN ('a', 'b') AND age BETWEEN 1 AND 9 AND name ILIKE 'j%'",
+			want: &where.SQLAnd{Conditions: []where.SQLNode{
+				compare(column("status"), "NOT IN", "a", "b"),
+				compare(column("age"), "BETWEEN", float64(1), float64(9)),
+				compare(column("name"), "ILIKE", "j%"),
+			}},
+		},
+		{
+			name:  "qualified and quoted columns",
+			input: `users.order = 1`,
+			want:  compare(&where.SQLColumn{Parts: []string{"users", "order"}, Quoted: `users."order"`}, "=", float64(1)),
+		},
+		{
+			name:  "boolean column",
+			input: "active",
+			want:  column("active"),
+		},
+		{
+			name:  "functions are raw SQL",
+			input: "LOWER(name) = 'x' OR a = b",
+			want: &where.SQLOr{Conditions: []where.SQLNode{
+				&where.SQLRaw{Text: "LOWER(name) = ?", Params: []any{"x"}},
+				&where.SQLRaw{Text: "a = b", Params: []any{}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			node, err := filter.ToAST("postgres")
+			require.NoError(t, err)
+			require.Equal(t, tt.want, node)
+		})
+	}
+}
+
+func TestToASTTranslatesOperators(t *testing.T) {
+	column := &where.SQLColumn{Parts: []string{"a"}, Quoted: "a"}
+
+	tests := []struct {
+		name    string
+		input   string
+		driver  string
+		options []where.BuildOption
+		want    where.SQLNode
+	}{
+		{
+			name:   "not equal as standard SQL",
+			input:  "a != 1",
+			driver: "ansi",
+			want:   &where.SQLComparison{Column: column, Operator: "<>", Operands: []*where.SQLParam{{Value: float64(1)}}},
+		},
+		{
+			name:   "null-safe equality",
+			input:  "a <=> 1",
+			driver: "postgres",
+			want:   &where.SQLComparison{Column: column, Operator: "IS NOT DISTINCT FROM", Operands: []*where.SQLParam{{Value: float64(1)}}},
+		},
+		{
+			name:    "configured not equal operator",
+			input:   "a <> 1",
+			driver:  "postgres",
+			options: []where.BuildOption{where.WithNotEqualOperator("!=")},
+			want:    &where.SQLComparison{Column: column, Operator: "!=", Operands: []*where.SQLParam{{Value: float64(1)}}},
+		},
+		{
+			name:    "rewritten equality with NULL",
+			input:   "a != NULL",
+			driver:  "postgres",
+			options: []where.BuildOption{where.WithNullComparisons(where.NullComparisonRewrite)},
+			want:    &where.SQLComparison{Column: column, Operator: "IS NOT NULL", Operands: []*where.SQLParam{}},
+		},
+		{
+			name:   "equality with NULL",
+			input:  "a = NULL",
+			driver: "postgres",
+			want:   &where.SQLRaw{Text: "a = NULL", Params: []any{}},
+		},
+		{
+			name:   "ILIKE rewritten with LOWER",
+			input:  "a ILIKE 'j%'",
+			driver: "mysql",
+			want:   &where.SQLRaw{Text: "LOWER(a) LIKE LOWER(?)", Params: []any{"j%"}},
+		},
+		{
+			name:   "LIKE with an ESCAPE clause",
+			input:  "a LIKE 'j%'",
+			driver: "ansi",
+			want:   &where.SQLRaw{Text: `a LIKE ? ESCAPE '\'`, Params: []any{"j%"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			node, err := filter.ToAST(tt.driver, tt.options...)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, node)
+		})
+	}
+}
+
+func TestToASTRendersBuiltSQL(t *testing.T) {
+	inputs := []string{
+		"(status IN ('a', 'b') AND age > 18) OR (status IN ('a', 'b') AND score > 18)",
+		"NOT (a = 1) AND b != 'x' AND c IS NOT NULL AND d NOT BETWEEN 1 AND 2",
+		"UPPER(TRIM(name)) LIKE 'A%' AND created_at < NOW()",
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			filter, err := where.Parse(input)
+			require.NoError(t, err)
+
+			node, err := filter.ToAST("postgres", where.WithRequiredFilter(where.Exists("SELECT 1 FROM t WHERE t.id = ?", 7)))
+			require.NoError(t, err)
+
+			wantSQL, wantParams, err := filter.ToSQL("mysql", where.WithRequiredFilter(where.Exists("SELECT 1 FROM t WHERE t.id = ?", 7)))
+			require.NoError(t, err)
+
+			sql, params := node.SQL()
+			require.Equal(t, wantSQL, sql)
+			require.Equal(t, wantParams, params)
+		})
+	}
+}
+
+func TestToASTValidation(t *testing.T) {
+	filter, err := where.Parse("secret = 1")
+	require.NoError(t, err)
+
+	_, err = filter.ToAST("postgres", where.WithValidator(where.NewValidator().AllowFields("age")))
+	require.Error(t, err)
+
+	_, err = filter.ToAST("unknown")
+	require.Error(t, err)
+}
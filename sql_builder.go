@@ -2,7 +2,9 @@ package where
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -10,9 +12,68 @@ import (
 type (
 	// SQLBuilder builds SQL queries from parsed filter expressions.
 	SQLBuilder struct {
-		driver    Driver
-		params    []any
-		validator *Validator
+		driver       Driver
+		registry     *Registry
+		params       []any
+		validator    *Validator
+		required     []*Filter
+		timeLayouts  []string
+		timeLocation *time.Location
+		fieldTypes   map[string]FieldType
+		fragments    map[string]string
+		expansions   map[string][]string
+		variables    map[string]any
+		target       ClauseTarget
+		groupBy      map[string]bool
+		dedupParams  map[any]int
+		maxParams    int
+		inChunkSize  int
+		arrayBinding bool
+		inBuckets    []int
+		bindConsts   bool
+		mergeRanges  bool
+		numberType   NumberType
+		nullCompares NullComparisonMode
+		notEqual     string
+		foldCase     bool
+		foldFields   map[string]bool
+		normDefault  *StringNormalization
+		normFields   map[string]StringNormalization
+		audit        bool
+		trusted      []string
+		identifiers  []string
+
+		// warn is true when building for ToSQLWithWarnings, which returns the warnings recorded.
+		warn     bool
+		warnings []BuildWarning
+
+		// field and fieldType describe the typed field of the predicate being built.
+		field     string
+		fieldType FieldType
+
+		// fold is true while building a predicate compared ignoring case.
+		fold bool
+
+		// integers is true while building bitwise operands, whose integer literals are bound as
+		// integers.
+		integers bool
+
+		// normalize is the normalization applied to strings bound in the predicate being built.
+		normalize *StringNormalization
+
+		// typed records Param metadata for ToSQLTyped, with paramField naming the field of the
+		// predicate being built.
+		typed       bool
+		typedParams []Param
+		paramField  string
+
+		// positional is true when building for ToAST, which uses ? placeholders regardless of the
+		// driver.
+		positional bool
+
+		// paramBase is the number of parameters bound before this filter's, when UnionSQL builds
+		// several filters into one statement.
+		paramBase int
 	}
 
 	// BuildOption is a function type for configuring SQL building options.
@@ -26,33 +87,135 @@ func WithValidator(v *Validator) BuildOption {
 	}
 }
 
+// WithRequiredFilter returns a BuildOption that combines a server-enforced filter with the user filter.
+// The generated SQL is the AND of the user filter and every required filter, each kept in its own
+// parenthesized group so user expressions can neither negate nor short-circuit the required conditions.
+// Required filters are trusted and are not checked by the validator.
+func WithRequiredFilter(filters ...*Filter) BuildOption {
+	return func(b *SQLBuilder) {
+		b.required = append(b.required, filters...)
+	}
+}
+
+// WithNotEqualOperator returns a BuildOption that renders every inequality with the given operator,
+// "<>" or "!=", regardless of how it was written in the filter. Dialects and SQL linters that only
+// accept the standard operator need "<>". By default the operator is rendered as written.
+func WithNotEqualOperator(operator string) BuildOption {
+	return func(b *SQLBuilder) {
+		b.notEqual = operator
+	}
+}
+
 // ToSQL converts the filter to SQL with parameterized values for the specified database driver.
 // Returns the SQL string, parameter values, and any error encountered during conversion.
+// The filter is not modified, so ToSQL may be called concurrently on the same filter.
 func (f *Filter) ToSQL(driverName string, options ...BuildOption) (string, []any, error) {
-	driver, err := GetDriver(driverName)
+	builder, sql, err := f.build(driverName, options)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, builder.params, nil
+}
+
+func (f *Filter) build(driverName string, options []BuildOption) (*SQLBuilder, string, error) {
+	builder, err := newSQLBuilder(f, driverName, options)
 	if err != nil {
-		return "", nil, errors.Wrapf(err, "failed to get driver %q", driverName)
+		return nil, "", err
 	}
 
+	sql, err := builder.buildExpression(f.Expression)
+	if err != nil {
+		return nil, "", builder.notifyRejection(f, err)
+	}
+
+	sql, err = builder.applyRequired(sql)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := builder.checkParamLimit(); err != nil {
+		return nil, "", err
+	}
+
+	if err := builder.auditSQL(sql); err != nil {
+		return nil, "", err
+	}
+
+	return builder, sql, nil
+}
+
+// newSQLBuilder creates a builder for the filter and runs the checks that apply to the whole filter
+// before any SQL is generated.
+func newSQLBuilder(f *Filter, driverName string, options []BuildOption) (*SQLBuilder, error) {
 	builder := &SQLBuilder{
-		driver: driver,
-		params: make([]any, 0),
+		registry: defaultRegistry,
+		params:   make([]any, 0),
 	}
 
 	for _, opt := range options {
 		opt(builder)
 	}
 
+	driver, err := builder.registry.Get(driverName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get driver %q", driverName)
+	}
+	builder.driver = driver
+
 	if f == nil || f.Expression == nil {
-		return "", nil, errors.New("empty filter")
+		return nil, errors.New("empty filter")
 	}
 
-	sql, err := builder.buildExpression(f.Expression)
-	if err != nil {
-		return "", nil, err
+	if err := f.checkFrozen(); err != nil {
+		return nil, err
 	}
 
-	return sql, builder.params, nil
+	if builder.notEqual != "" && !isNotEqual(builder.notEqual) {
+		return nil, fmt.Errorf("invalid not-equal operator %q; use \"<>\" or \"!=\"", builder.notEqual)
+	}
+
+	if builder.validator != nil {
+		if err := builder.validator.CheckRequirements(f); err != nil {
+			return nil, builder.notifyRejection(f, rejected(err, RejectionMeta{Rule: RuleRequirement}))
+		}
+	}
+
+	if builder.target == Having {
+		if err := builder.checkHaving(f.Expression); err != nil {
+			return nil, err
+		}
+	}
+
+	return builder, nil
+}
+
+// applyRequired ANDs the required filters onto the already built user SQL, which may be empty.
+func (b *SQLBuilder) applyRequired(sql string) (string, error) {
+	if len(b.required) == 0 {
+		return sql, nil
+	}
+
+	validator := b.validator
+	b.validator = nil
+	defer func() { b.validator = validator }()
+
+	var parts []string
+	if sql != "" {
+		parts = append(parts, sql)
+	}
+	for _, req := range b.required {
+		if req == nil || req.Expression == nil {
+			return "", errors.New("empty required filter")
+		}
+
+		part, err := b.buildExpression(req.Expression)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to build required filter")
+		}
+		parts = append(parts, part)
+	}
+
+	return "(" + strings.Join(parts, " AND ") + ")", nil
 }
 
 func (b *SQLBuilder) buildExpression(expr *Expression) (string, error) {
@@ -81,6 +244,10 @@ func (b *SQLBuilder) buildTerm(term *Term) (string, error) {
 		return "", errors.New("empty term")
 	}
 
+	if b.mergeRanges {
+		term = &Term{And: mergeRangeFactors(term.And)}
+	}
+
 	if len(term.And) == 1 {
 		return b.buildFactor(term.And[0])
 	}
@@ -102,6 +269,10 @@ func (b *SQLBuilder) buildFactor(factor *Factor) (string, error) {
 		return "", errors.New("empty factor")
 	}
 
+	if factor.Exists != nil {
+		return b.buildExists(factor.Exists, factor.Not)
+	}
+
 	var result string
 	var err error
 
@@ -109,6 +280,7 @@ func (b *SQLBuilder) buildFactor(factor *Factor) (string, error) {
 		result, err = b.buildExpression(factor.SubExpr)
 	} else if factor.Predicate != nil {
 		result, err = b.buildPredicate(factor.Predicate)
+		err = atPosition(err, factor.Predicate.Pos)
 	} else {
 		return "", errors.New("empty factor content")
 	}
@@ -129,19 +301,145 @@ func (b *SQLBuilder) buildPredicate(pred *Predicate) (string, error) {
 		return "", errors.New("empty predicate")
 	}
 
+	if pred.Operation == nil {
+		return "", errors.New("predicate missing operation")
+	}
+
+	pred, err := b.nullComparison(pred)
+	if err != nil {
+		return "", err
+	}
+
+	if field, columns, ok := b.expansion(pred); ok {
+		return b.buildExpanded(pred, field, columns)
+	}
+
+	if err := b.checkConstraints(pred); err != nil {
+		return "", rejected(err, RejectionMeta{Rule: RuleConstraint, Field: predicateField(pred)})
+	}
+
+	b.field, b.fieldType = b.predicateFieldType(pred)
+	defer func() { b.field, b.fieldType = "", "" }()
+
+	if b.typed || b.warn {
+		paramField := b.paramField
+		b.paramField = predicateField(pred)
+		defer func() { b.paramField = paramField }()
+	}
+
+	if b.foldsCase(pred) {
+		b.fold = true
+		defer func() { b.fold = false }()
+	}
+
+	if b.normDefault != nil || len(b.normFields) > 0 {
+		b.normalize = b.predicateNormalization(pred)
+		defer func() { b.normalize = nil }()
+	}
+
 	leftVal, err := b.buildValue(pred.Left)
 	if err != nil {
 		return "", err
 	}
+	if b.fold {
+		leftVal = b.lower(leftVal)
+	}
 
-	if pred.Operation == nil {
-		return "", errors.New("predicate missing operation")
+	// Boolean predicates are built as the bare column where the database allows it.
+	if isBooleanPredicate(pred) && b.driver.SupportsFeature("BOOLEAN") {
+		return leftVal, nil
+	}
+
+	return b.buildOperation(pred.Left, leftVal, pred.Operation)
+}
+
+// checkConstraints applies the validator's per-field value constraints to the literals
+// compared against a field in the predicate.
+func (b *SQLBuilder) checkConstraints(pred *Predicate) error {
+	if b.validator == nil || len(b.validator.constraints) == 0 {
+		return nil
+	}
+
+	op := pred.Operation
+	// Support reversed comparisons such as 'active' = status.
+	if op.Compare != nil && op.Compare.Right != nil && op.Compare.Right.Field != nil {
+		if err := b.checkLiteral(op.Compare.Right.Field.String(), pred.Left); err != nil {
+			return err
+		}
+	}
+
+	field := pred.Left.Field
+	if field == nil || !b.validator.isConstrained(field.String()) {
+		return nil
 	}
 
-	return b.buildOperation(leftVal, pred.Operation)
+	name := field.String()
+	switch {
+	case op.Compare != nil:
+		return b.checkLiteral(name, op.Compare.Right)
+	case op.Like != nil:
+		if op.Like.Pattern == nil {
+			return nil
+		}
+		if op.Like.Pattern.Literal == nil && op.Like.Pattern.Field == nil {
+			return newMessage(MsgValueNotLiteral, "field", strconv.Quote(name))
+		}
+		if op.Like.Pattern.Literal == nil {
+			return nil
+		}
+		if pattern, ok := b.literalValue(op.Like.Pattern.Literal).(string); ok {
+			return b.validator.CheckPattern(name, pattern)
+		}
+	case op.Match != nil:
+		return b.checkLiteral(name, op.Match.Query)
+	case op.Between != nil:
+		if err := b.checkLiteral(name, op.Between.Lower); err != nil {
+			return err
+		}
+		return b.checkLiteral(name, op.Between.Upper)
+	case op.In != nil:
+		for _, val := range op.In.Values {
+			if err := b.checkLiteral(name, val); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkLiteral applies the field's constraints to the value compared with it. A constrained field may
+// be compared with another field, but not with a function call or expression, whose value cannot be
+// checked.
+func (b *SQLBuilder) checkLiteral(field string, val *Value) error {
+	if val == nil || val.Field != nil || !b.validator.isConstrained(field) {
+		return nil
+	}
+	if val.Literal == nil {
+		return newMessage(MsgValueNotLiteral, "field", strconv.Quote(field))
+	}
+
+	if val.Literal.Variable == nil {
+		return b.validator.CheckValue(field, val.Literal.Value())
+	}
+	for _, value := range expandValues([]any{b.literalValue(val.Literal)}) {
+		if err := b.validator.CheckValue(field, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// literalValue returns the literal's value, or the value supplied for a variable. Missing variables
+// are reported when the value is bound.
+func (b *SQLBuilder) literalValue(lit *LiteralValue) any {
+	if lit.Variable != nil {
+		return b.variables[lit.variableName()]
+	}
+	return lit.Value()
 }
 
-func (b *SQLBuilder) buildOperation(leftVal string, op *Operation) (string, error) {
+func (b *SQLBuilder) buildOperation(left *Value, leftVal string, op *Operation) (string, error) {
 	if op == nil {
 		return "", errors.New("empty operation")
 	}
@@ -152,11 +450,14 @@ func (b *SQLBuilder) buildOperation(leftVal string, op *Operation) (string, erro
 	if op.Like != nil {
 		return b.buildLike(leftVal, op.Like)
 	}
+	if op.Match != nil {
+		return b.buildMatch(leftVal, op.Match)
+	}
 	if op.Between != nil {
 		return b.buildBetween(leftVal, op.Between)
 	}
 	if op.In != nil {
-		return b.buildIn(leftVal, op.In)
+		return b.buildIn(left, leftVal, op.In)
 	}
 	if op.IsNull != nil {
 		return b.buildIsNull(leftVal, op.IsNull)
@@ -170,16 +471,67 @@ func (b *SQLBuilder) buildCompare(leftVal string, comp *CompareOp) (string, erro
 	if err != nil {
 		return "", err
 	}
-	sqlOp := comp.Operator.String()
+	if b.fold {
+		rightVal = b.lower(rightVal)
+	}
+
+	sqlOp, err := b.compareOperator(comp)
+	if err != nil {
+		return "", err
+	}
 	return fmt.Sprintf("%s %s %s", leftVal, sqlOp, rightVal), nil
 }
 
+// compareOperator returns the comparison operator as rendered for the driver.
+func (b *SQLBuilder) compareOperator(comp *CompareOp) (string, error) {
+	sqlOp := comp.Operator.String()
+	if b.notEqual != "" && isNotEqual(sqlOp) {
+		sqlOp = b.notEqual
+	} else if sqlOp == "!=" {
+		// Drivers may render != as the standard <>.
+		if translated, supported := b.driver.TranslateOperator(sqlOp); supported {
+			sqlOp = translated
+		}
+	}
+
+	// NULL-safe equality has no common syntax, so drivers translate it.
+	if sqlOp == "<=>" {
+		translated, supported := b.driver.TranslateOperator(sqlOp)
+		if !supported {
+			return "", newMessage(MsgOperatorNotSupported, "operator", sqlOp, "driver", b.driver.Name())
+		}
+		sqlOp = translated
+	}
+
+	return sqlOp, nil
+}
+
 func (b *SQLBuilder) buildLike(leftVal string, like *LikeOp) (string, error) {
 	pattern, err := b.buildValue(like.Pattern)
 	if err != nil {
 		return "", err
 	}
 
+	operator, translated, err := b.likeOperator(like)
+	if err != nil {
+		return "", err
+	}
+
+	// Drivers without ILIKE translate it to LIKE, so both sides are lowercased.
+	if strings.Contains(operator, "ILIKE") && !strings.Contains(translated, "ILIKE") {
+		b.warnf(WarnILIKERewrite, "ILIKE is not supported by driver %s and was rewritten as LIKE with LOWER()", b.driver.Name())
+		leftVal = fmt.Sprintf("LOWER(%s)", leftVal)
+		pattern = fmt.Sprintf("LOWER(%s)", pattern)
+	}
+
+	if escaper, ok := DriverAs[LikeEscaper](b.driver); ok {
+		return fmt.Sprintf("%s %s %s ESCAPE %s", leftVal, translated, pattern, escaper.LikeEscape()), nil
+	}
+	return fmt.Sprintf("%s %s %s", leftVal, translated, pattern), nil
+}
+
+// likeOperator returns the LIKE operator as written, e.g. NOT ILIKE, and as rendered for the driver.
+func (b *SQLBuilder) likeOperator(like *LikeOp) (string, string, error) {
 	operator := strings.ToUpper(like.Type.Operator)
 	if like.Not {
 		operator = "NOT " + operator
@@ -187,15 +539,9 @@ func (b *SQLBuilder) buildLike(leftVal string, like *LikeOp) (string, error) {
 
 	translated, supported := b.driver.TranslateOperator(operator)
 	if !supported {
-		return "", fmt.Errorf("operator %s not supported by driver %s", operator, b.driver.Name())
+		return "", "", newMessage(MsgOperatorNotSupported, "operator", operator, "driver", b.driver.Name())
 	}
-
-	if b.driver.Name() == "mysql" && strings.Contains(strings.ToUpper(like.Type.Operator), "ILIKE") {
-		leftVal = fmt.Sprintf("LOWER(%s)", leftVal)
-		pattern = fmt.Sprintf("LOWER(%s)", pattern)
-	}
-
-	return fmt.Sprintf("%s %s %s", leftVal, translated, pattern), nil
+	return operator, translated, nil
 }
 
 func (b *SQLBuilder) buildBetween(leftVal string, between *BetweenOp) (string, error) {
@@ -217,18 +563,44 @@ func (b *SQLBuilder) buildBetween(leftVal string, between *BetweenOp) (string, e
 	return fmt.Sprintf("%s %s %s AND %s", leftVal, sqlOp, lower, upper), nil
 }
 
-func (b *SQLBuilder) buildIn(leftVal string, in *InOp) (string, error) {
+func (b *SQLBuilder) buildIn(left *Value, leftVal string, in *InOp) (string, error) {
+	in, err := b.resolveVariables(in)
+	if err != nil {
+		return "", err
+	}
+
+	// An empty list matches no rows, even when the left side is NULL.
 	if len(in.Values) == 0 {
-		return "", errors.New("IN expression requires at least one value")
+		if in.Not {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	}
+
+	// Array parameters cannot be lowercased in SQL, so case-insensitive lists are not bound as arrays.
+	if b.arrayBinding && b.fold {
+		b.warnf(WarnArrayBindingSkipped, "IN list was not bound as an array because it is compared ignoring case")
+	} else if b.arrayBinding {
+		sql, ok, err := b.buildArrayIn(leftVal, in)
+		if ok || err != nil {
+			return sql, err
+		}
 	}
 
 	items := make([]string, len(in.Values))
-	var err error
 	for i, item := range in.Values {
 		items[i], err = b.buildValue(item)
 		if err != nil {
 			return "", err
 		}
+		if b.fold {
+			items[i] = b.lower(items[i])
+		}
+	}
+
+	items, err = b.padIn(in, items)
+	if err != nil {
+		return "", err
 	}
 
 	sqlOp := "IN"
@@ -236,6 +608,14 @@ func (b *SQLBuilder) buildIn(leftVal string, in *InOp) (string, error) {
 		sqlOp = "NOT IN"
 	}
 
+	// Chunks repeat the left side, so only fields (which never bind parameters) are chunked.
+	if b.inChunkSize > 0 && len(items) > b.inChunkSize {
+		if left != nil && left.Field != nil {
+			return chunkIn(leftVal, sqlOp, items, b.inChunkSize), nil
+		}
+		b.warnf(WarnINChunkingSkipped, "IN list of %d values was not chunked because its left side is not a field", len(items))
+	}
+
 	return fmt.Sprintf("%s %s (%s)", leftVal, sqlOp, strings.Join(items, ", ")), nil
 }
 
@@ -269,40 +649,72 @@ func (b *SQLBuilder) buildValue(val *Value) (string, error) {
 		return b.buildExpression(val.SubExpr)
 	}
 
+	if val.Bitwise != nil {
+		return b.buildBitwise(val.Bitwise)
+	}
+
 	return "", errors.New("unrecognized value type")
 }
 
 func (b *SQLBuilder) buildFunctionCall(fn *FunctionCall) (string, error) {
 	if b.validator != nil && !b.validator.IsFunctionAllowed(fn.Name) {
-		return "", fmt.Errorf("function %q is not allowed", fn.Name)
+		return "", atPosition(rejected(b.validator.functionNotAllowed(fn.Name),
+			RejectionMeta{Rule: RuleFunction, Function: fn.Name}), fn.Pos)
 	}
 
-	// Build template directly without driver involvement
-	var template string
-	if len(fn.Args) == 0 {
-		template = fn.Name + "()"
-	} else {
-		placeholders := make([]string, len(fn.Args))
-		for i := range len(fn.Args) {
-			placeholders[i] = "%s"
+	sql, err := b.translateFunction(b.driver, fn)
+	return sql, atPosition(err, fn.Pos)
+}
+
+// renderFunctionCall renders a function call accepted by the validator the way the driver does.
+func (b *SQLBuilder) renderFunctionCall(fn *FunctionCall) (string, error) {
+	if restricter, ok := DriverAs[FunctionRestricter](b.driver); ok && !restricter.SupportsFunction(strings.ToUpper(fn.Name)) {
+		return "", newMessage(MsgFunctionNotSupported, "function", strconv.Quote(fn.Name), "driver", b.driver.Name())
+	}
+
+	// Field types only apply to literals compared directly against the field.
+	fieldType := b.fieldType
+	b.fieldType = ""
+	defer func() { b.fieldType = fieldType }()
+
+	if fn.isCast() {
+		return b.buildCast(fn)
+	}
+	if fn.Star {
+		return fn.Name + "(*)", nil
+	}
+	if fn.Cond != nil {
+		if err := checkIf(fn); err != nil {
+			return "", err
 		}
-		template = fn.Name + "(" + strings.Join(placeholders, ", ") + ")"
+		// Drivers translate IF with the condition as the first argument.
+		fn = &FunctionCall{Name: fn.Name, Args: fn.arguments()}
 	}
 
-	if len(fn.Args) == 0 {
-		return template, nil
+	if translate, ok := lookupTranslation(b.driver, fn); ok {
+		return translate(b, fn)
+	}
+
+	// Functions without a registered translation are passed through unchanged
+	args, err := b.buildArgs(fn.Args)
+	if err != nil {
+		return "", err
 	}
 
-	args := make([]any, len(fn.Args))
-	for i, arg := range fn.Args {
-		argStr, err := b.buildValue(arg)
+	return fn.Name + "(" + strings.Join(args, ", ") + ")", nil
+}
+
+// buildArgs builds the function arguments in order.
+func (b *SQLBuilder) buildArgs(args []*Value) ([]string, error) {
+	built := make([]string, len(args))
+	for i, arg := range args {
+		var err error
+		built[i], err = b.buildValue(arg)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		args[i] = argStr
 	}
-
-	return fmt.Sprintf(template, args...), nil
+	return built, nil
 }
 
 func (b *SQLBuilder) buildFieldRef(field *FieldRef) (string, error) {
@@ -310,52 +722,153 @@ func (b *SQLBuilder) buildFieldRef(field *FieldRef) (string, error) {
 		return "", errors.New("empty field")
 	}
 
-	if b.validator != nil && !b.validator.IsFieldAllowed(strings.Join(field.Parts, ".")) {
-		return "", fmt.Errorf("field %q is not allowed", strings.Join(field.Parts, "."))
+	if err := b.checkField(field); err != nil {
+		return "", atPosition(err, field.Pos)
 	}
 
-	parts := make([]string, len(field.Parts))
-	for i, part := range field.Parts {
-		part = strings.TrimSpace(part)
+	if sql, ok := b.fragment(field); ok {
+		return sql, nil
+	}
+
+	names := b.columnNames(field)
+	parts := make([]string, len(names))
+	for i, part := range names {
+		parts[i] = b.quoteIdentifier(part)
+	}
 
-		if strings.HasPrefix(part, "`") && strings.HasSuffix(part, "`") {
-			part = part[1 : len(part)-1]
-		} else if strings.HasPrefix(part, `"`) && strings.HasSuffix(part, `"`) {
-			part = part[1 : len(part)-1]
+	return strings.Join(parts, "."), nil
+}
+
+// columnNames returns the unquoted names of the column a field refers to, using the validator's
+// field mappings and the case of its allowed names.
+func (b *SQLBuilder) columnNames(field *FieldRef) []string {
+	names := field.Parts
+	if b.validator != nil && !field.column {
+		if name, ok := b.validator.allowedField(field.String()); ok {
+			if column, mapped := b.validator.columns[name]; mapped {
+				names = strings.Split(column, ".")
+			} else if allowed := strings.Split(name, "."); len(allowed) == len(names) {
+				names = allowed
+			}
 		}
+	}
 
-		parts[i] = b.driver.QuoteIdentifier(part)
+	unquoted := make([]string, len(names))
+	for i, part := range names {
+		unquoted[i] = unquoteIdentifier(part)
 	}
+	return unquoted
+}
 
-	return strings.Join(parts, "."), nil
+// checkField returns an error if the validator does not allow the field or its identifiers.
+func (b *SQLBuilder) checkField(field *FieldRef) error {
+	if b.validator == nil || field.column {
+		return nil
+	}
+
+	if !b.validator.IsFieldAllowed(field.String()) {
+		return rejected(b.validator.fieldNotAllowed(field.String()),
+			RejectionMeta{Rule: RuleField, Field: field.String()})
+	}
+	for _, part := range field.Parts {
+		if err := b.validator.CheckIdentifier(unquoteIdentifier(part)); err != nil {
+			return rejected(err, RejectionMeta{Rule: RuleField, Field: field.String()})
+		}
+	}
+	return nil
+}
+
+// quoteIdentifier quotes a field name part, keeping its case when the validator's field names are
+// case-sensitive and the database would otherwise fold it.
+func (b *SQLBuilder) quoteIdentifier(name string) string {
+	if b.validator != nil && b.validator.caseSensitive && name != strings.ToLower(name) {
+		if folder, ok := DriverAs[CaseFolder](b.driver); ok {
+			return b.quoted(folder.QuoteExact(name))
+		}
+	}
+
+	quoted := b.driver.QuoteIdentifier(name)
+	if b.warn && quoted != name && IsReservedKeyword(name, b.driver) {
+		b.warnf(WarnQuotedKeyword, "identifier %s was quoted because it is a reserved keyword for driver %s", name, b.driver.Name())
+	}
+	return b.quoted(quoted)
+}
+
+// unquoteIdentifier removes the backticks or double quotes around a field name part.
+func unquoteIdentifier(part string) string {
+	part = strings.TrimSpace(part)
+
+	if strings.HasPrefix(part, "`") && strings.HasSuffix(part, "`") {
+		return part[1 : len(part)-1]
+	}
+	if strings.HasPrefix(part, `"`) && strings.HasSuffix(part, `"`) {
+		return part[1 : len(part)-1]
+	}
+	return part
 }
 
 func (b *SQLBuilder) buildLiteralValue(lit *LiteralValue) (string, error) {
 	if lit.Null {
+		if b.bindConsts {
+			return b.addParam(nil), nil
+		}
 		return "NULL", nil
 	}
 
 	if lit.Boolean != nil {
+		if b.bindConsts {
+			return b.addParam(lit.Boolean.Value()), nil
+		}
 		if lit.Boolean.Value() {
 			return "TRUE", nil
 		}
 		return "FALSE", nil
 	}
 
-	if lit.Number != nil {
-		b.params = append(b.params, *lit.Number)
-		return b.driver.Placeholder(len(b.params)), nil
+	param, err := b.literalParam(lit)
+	if err != nil {
+		return "", err
 	}
 
-	if lit.String != nil {
-		str := *lit.String
-		if len(str) >= 2 && ((str[0] == '\'' && str[len(str)-1] == '\'') ||
-			(str[0] == '"' && str[len(str)-1] == '"')) {
-			str = str[1 : len(str)-1]
+	// Times compared with typed fields are written as typed literals for drivers that need them.
+	if t, ok := param.(time.Time); ok && (b.fieldType == FieldTypeTime || b.fieldType == FieldTypeDate) {
+		if renderer, ok := DriverAs[TimeLiteralRenderer](b.driver); ok {
+			return renderer.TimeLiteral(t, b.fieldType), nil
+		}
+	}
+
+	return b.addParam(param), nil
+}
+
+// literalParam returns the value bound for a string, number, or hex literal.
+func (b *SQLBuilder) literalParam(lit *LiteralValue) (any, error) {
+	var param any
+	switch {
+	case lit.bound:
+		param = b.normalizeParam(lit.param)
+	case lit.Variable != nil:
+		var err error
+		if param, err = b.variable(lit); err != nil {
+			return nil, err
+		}
+		param = b.normalizeParam(param)
+	case lit.Number != nil:
+		var err error
+		if param, err = b.numberParam(lit); err != nil {
+			return nil, err
+		}
+	case lit.Hex != nil:
+		param = lit.Value()
+	case lit.String != nil:
+		str, _ := b.normalizeParam(lit.Value()).(string)
+		if t, ok := b.timeParam(str); ok && b.fieldType == "" {
+			param = t
+		} else {
+			param = str
 		}
-		b.params = append(b.params, str)
-		return b.driver.Placeholder(len(b.params)), nil
+	default:
+		return nil, errors.New("unrecognized literal type")
 	}
 
-	return "", errors.New("unrecognized literal type")
+	return b.typedParam(param)
 }
@@ -202,7 +202,7 @@ func TestSQLGenerationClickHouse(t *testing.T) {
 		{
 			name:     "array syntax",
 			input:    "id IN (1, 2, 3)",
-			wantSQL:  "`id` IN (?, ?, ?)",
+			wantSQL:  "id IN (?, ?, ?)",
 			wantArgs: []any{float64(1), float64(2), float64(3)},
 		},
 	}
@@ -341,6 +341,64 @@ func TestBooleanAndNullLiterals(t *testing.T) {
 	}
 }
 
+func TestNullSafeEquality(t *testing.T) {
+	tests := []struct {
+		driver   string
+		wantSQL  string
+		wantArgs []any
+		wantErr  string
+	}{
+		{driver: "postgres", wantSQL: "(manager_id IS NOT DISTINCT FROM $1 AND parent_id IS NOT DISTINCT FROM NULL)", wantArgs: []any{float64(5)}},
+		{driver: "mysql", wantSQL: "(manager_id <=> ? AND parent_id <=> NULL)", wantArgs: []any{float64(5)}},
+		{driver: "clickhouse", wantErr: "operator <=> not supported by driver clickhouse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			filter, err := where.Parse("manager_id <=> 5 AND parent_id <=> NULL")
+			require.NoError(t, err)
+			require.Equal(t, "manager_id <=> 5 AND parent_id <=> NULL", filter.String())
+
+			sql, args, err := filter.ToSQL(tt.driver)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestWithNotEqualOperator(t *testing.T) {
+	tests := []struct {
+		operator string
+		wantSQL  string
+		wantErr  string
+	}{
+		{operator: "", wantSQL: "(a != $1 AND b <> $2 AND c = $3)"},
+		{operator: "<>", wantSQL: "(a <> $1 AND b <> $2 AND c = $3)"},
+		{operator: "!=", wantSQL: "(a != $1 AND b != $2 AND c = $3)"},
+		{operator: "=/=", wantErr: `invalid not-equal operator "=/="; use "<>" or "!="`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operator, func(t *testing.T) {
+			filter, err := where.Parse("a != 1 AND b <> 2 AND c = 3")
+			require.NoError(t, err)
+
+			sql, _, err := filter.ToSQL("postgres", where.WithNotEqualOperator(tt.operator))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+		})
+	}
+}
+
 func TestInvalidDriver(t *testing.T) {
 	filter, err := where.Parse("age > 18")
 	require.NoError(t, err)
@@ -388,3 +446,83 @@ func TestQuotedIdentifiers(t *testing.T) {
 		})
 	}
 }
+
+func TestWithRequiredFilter(t *testing.T) {
+	tenant, err := where.Parse("tenant_id = 42")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		input    string
+		required []*where.Filter
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "simple predicate",
+			input:    "age > 18",
+			required: []*where.Filter{tenant},
+			wantSQL:  "(age > $1 AND tenant_id = $2)",
+			wantArgs: []any{float64(18), float64(42)},
+		},
+		{
+			name:     "OR cannot bypass required filter",
+			input:    "age > 18 OR 1 = 1",
+			required: []*where.Filter{tenant},
+			wantSQL:  "((age > $1 OR $2 = $3) AND tenant_id = $4)",
+			wantArgs: []any{float64(18), float64(1), float64(1), float64(42)},
+		},
+		{
+			name:     "NOT cannot negate required filter",
+			input:    "NOT (status = 'deleted')",
+			required: []*where.Filter{tenant},
+			wantSQL:  "(NOT (status = $1) AND tenant_id = $2)",
+			wantArgs: []any{"deleted", float64(42)},
+		},
+		{
+			name:     "multiple required filters",
+			input:    "age > 18",
+			required: []*where.Filter{tenant, mustParse(t, "deleted_at IS NULL")},
+			wantSQL:  "(age > $1 AND tenant_id = $2 AND deleted_at IS NULL)",
+			wantArgs: []any{float64(18), float64(42)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			sql, args, err := filter.ToSQL("postgres", where.WithRequiredFilter(tt.required...))
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+
+	t.Run("required filter bypasses validator", func(t *testing.T) {
+		filter, err := where.Parse("age > 18")
+		require.NoError(t, err)
+
+		validator := where.NewValidator().AllowFields("age")
+		sql, _, err := filter.ToSQL("mysql", where.WithValidator(validator), where.WithRequiredFilter(tenant))
+		require.NoError(t, err)
+		require.Equal(t, "(age > ? AND tenant_id = ?)", sql)
+	})
+
+	t.Run("nil required filter", func(t *testing.T) {
+		filter, err := where.Parse("age > 18")
+		require.NoError(t, err)
+
+		_, _, err = filter.ToSQL("postgres", where.WithRequiredFilter(nil))
+		require.EqualError(t, err, "empty required filter")
+	})
+}
+
+func mustParse(t *testing.T, input string) *where.Filter {
+	t.Helper()
+
+	filter, err := where.Parse(input)
+	require.NoError(t, err)
+	return filter
+}
@@ -0,0 +1,119 @@
+package where
+
+import (
+	"strings"
+)
+
+type (
+	// Stats describes the size and shape of a filter, e.g. to log it or to enforce policies the
+	// parser options do not cover.
+	Stats struct {
+		// Predicates is the total number of predicates, including EXISTS conditions.
+		Predicates int
+
+		// MaxDepth is the deepest nesting of parenthesized groups, as limited by WithMaxDepth. A
+		// filter without groups has depth 0.
+		MaxDepth int
+
+		// INLists is the number of IN and NOT IN lists.
+		INLists int
+
+		// INItems is the total number of values across all IN and NOT IN lists.
+		INItems int
+
+		// Functions counts the calls of each function, by upper-cased name.
+		Functions map[string]int
+
+		// Literals is the total number of literal values, including variables and the values of IN
+		// lists, but not the TRUE completing a boolean predicate such as is_active.
+		Literals int
+	}
+)
+
+// Stats returns the size and shape of the filter.
+//
+// Example:
+//
+//	filter, _ := where.Parse("LOWER(name) = 'bob' AND (id IN (1, 2, 3) OR age > 18)")
+//	stats := filter.Stats()
+//	// {Predicates: 3, MaxDepth: 1, INLists: 1, INItems: 3, Functions: map[LOWER:1], Literals: 5}
+func (f *Filter) Stats() Stats {
+	s := Stats{Functions: make(map[string]int)}
+	if f != nil {
+		s.expression(f.Expression, 0)
+	}
+	return s
+}
+
+func (s *Stats) expression(expr *Expression, depth int) {
+	if expr == nil {
+		return
+	}
+
+	s.MaxDepth = max(s.MaxDepth, depth)
+	for _, term := range expr.Or {
+		if term == nil {
+			continue
+		}
+		for _, factor := range term.And {
+			s.factor(factor, depth)
+		}
+	}
+}
+
+func (s *Stats) factor(factor *Factor, depth int) {
+	switch {
+	case factor == nil:
+	case factor.SubExpr != nil:
+		s.expression(factor.SubExpr, depth+1)
+	case factor.Predicate != nil:
+		s.predicate(factor.Predicate, depth)
+	case factor.Exists != nil:
+		s.Predicates++
+	}
+}
+
+func (s *Stats) predicate(pred *Predicate, depth int) {
+	s.Predicates++
+	s.value(pred.Left, depth)
+
+	op := pred.Operation
+	if op == nil {
+		return
+	}
+	if op.In != nil {
+		s.INLists++
+		s.INItems += len(op.In.Values)
+	}
+	for _, val := range op.operands() {
+		s.value(val, depth)
+	}
+}
+
+func (s *Stats) value(val *Value, depth int) {
+	if val == nil {
+		return
+	}
+
+	if val.Literal != nil && !val.Literal.implicit {
+		s.Literals++
+	}
+
+	if val.Function != nil {
+		s.Functions[strings.ToUpper(val.Function.Name)]++
+		for _, arg := range val.Function.arguments() {
+			s.value(arg, depth)
+		}
+	}
+
+	if val.SubExpr != nil {
+		s.expression(val.SubExpr, depth+1)
+	}
+
+	if val.Bitwise != nil {
+		s.value(val.Bitwise.Left, depth)
+		for _, op := range val.Bitwise.Ops {
+			s.value(op.Right, depth)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  where.Stats
+	}{
+		{
+			name:  "simple",
+			input: "age > 18",
+			want:  where.Stats{Predicates: 1, Functions: map[string]int{}, Literals: 1},
+		},
+		{
+			name:  "example",
+			input: "LOWER(name) = 'bob' AND (id IN (1, 2, 3) OR age > 18)",
+			want: where.Stats{
+				Predicates: 3, MaxDepth: 1, INLists: 1, INItems: 3,
+				Functions: map[string]int{"LOWER": 1}, Literals: 5,
+			},
+		},
+		{
+			name:  "nested groups",
+			input: "a = 1 OR (b = 2 AND NOT (c = 3 OR (d IS NULL)))",
+			want:  where.Stats{Predicates: 4, MaxDepth: 3, Functions: map[string]int{}, Literals: 3},
+		},
+		{
+			name:  "functions by name",
+			input: "lower(a) = LOWER(b) AND COALESCE(UPPER(c), 'x') = 'X'",
+			want: where.Stats{
+				Predicates: 2,
+				Functions:  map[string]int{"LOWER": 2, "COALESCE": 1, "UPPER": 1},
+				Literals:   2,
+			},
+		},
+		{
+			name:  "IN lists",
+			input: "a IN (1, 2) AND b NOT IN ('x', 'y', 'z') AND c IN (:ids)",
+			want:  where.Stats{Predicates: 3, INLists: 3, INItems: 6, Functions: map[string]int{}, Literals: 6},
+		},
+		{
+			name:  "boolean field",
+			input: "is_active AND score BETWEEN 1 AND 10",
+			want:  where.Stats{Predicates: 2, Functions: map[string]int{}, Literals: 2},
+		},
+		{
+			name:  "bitwise",
+			input: "flags & 4 = 4",
+			want:  where.Stats{Predicates: 1, Functions: map[string]int{}, Literals: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, filter.Stats())
+		})
+	}
+}
+
+func TestStatsExists(t *testing.T) {
+	filter := where.NotExists("SELECT 1 FROM tags WHERE tags.post_id = posts.id")
+	require.Equal(t, where.Stats{Predicates: 1, Functions: map[string]int{}}, filter.Stats())
+
+	var empty *where.Filter
+	require.Equal(t, where.Stats{Functions: map[string]int{}}, empty.Stats())
+}
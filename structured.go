@@ -0,0 +1,340 @@
+package where
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var jsonFieldPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*$`)
+
+type (
+	// jsonNode is a node of a structured JSON filter: an "and", "or", or "not" group, or a condition
+	// with "field", "op", and "value".
+	jsonNode struct {
+		And   []*jsonNode     `json:"and"`
+		Or    []*jsonNode     `json:"or"`
+		Not   *jsonNode       `json:"not"`
+		Field string          `json:"field"`
+		Op    string          `json:"op"`
+		Value json.RawMessage `json:"value"`
+
+		// path locates the node in errors when it was converted from another input, e.g. GraphQL.
+		path string
+	}
+
+	// jsonBuilder converts jsonNodes to the AST the parser produces for the same filter.
+	jsonBuilder struct {
+		escapes EscapeMode
+	}
+)
+
+// ParseJSON parses a structured JSON filter, as emitted by frontends that would rather not build
+// expression strings, into the same AST that Parse returns for the equivalent expression. The
+// filter is validated according to the parser's configured options.
+//
+// Each node is a group, {"and": [...]}, {"or": [...]}, or {"not": {...}}, or a condition such as
+// {"field": "age", "op": ">", "value": 18}. The operators are =, !=, <>, <, <=, >, >=, like,
+// ilike, matches, in, between, is null, and is not null, where like, ilike, matches, in, and
+// between can be prefixed with "not". The values of in and between are arrays, and is null takes
+// no value. Values are JSON strings, numbers, booleans, or null.
+//
+// Example:
+//
+//	filter, err := parser.ParseJSON([]byte(`{"and": [
+//		{"field": "age", "op": ">", "value": 18},
+//		{"field": "status", "op": "in", "value": ["active", "pending"]}
+//	]}`))
+//	// same as parser.Parse("age > 18 AND status IN ('active', 'pending')")
+func (p *Parser) ParseJSON(data []byte) (*Filter, error) {
+	filter, err := p.parseJSON(data)
+	if err != nil && p.opts.onReject != nil {
+		meta, _ := rejectionMeta(err)
+		p.opts.onReject(string(data), err, meta)
+	}
+	return filter, err
+}
+
+func (p *Parser) parseJSON(data []byte) (*Filter, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, newMessage(MsgEmptyFilter)
+	}
+	if p.opts.maxInputLength > 0 && len(data) > p.opts.maxInputLength {
+		err := rejected(newMessage(MsgInputLength, "max", p.opts.maxInputLength), RejectionMeta{Rule: RuleInputLength})
+		return nil, errors.Wrapf(err, "filter validation failed")
+	}
+
+	var node jsonNode
+	if err := decodeJSONNode(data, &node); err != nil {
+		return nil, syntaxError(err)
+	}
+
+	jb := &jsonBuilder{escapes: p.opts.escapes}
+	expr, err := jb.expression(&node, "")
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+
+	filter := &Filter{Expression: expr}
+	if err := p.resolve(filter); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// ParseJSON is a convenience function that creates a default parser and parses the structured JSON
+// filter. See Parser.ParseJSON.
+func ParseJSON(data []byte) (*Filter, error) {
+	parser, err := NewParser()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create parser")
+	}
+	return parser.ParseJSON(data)
+}
+
+// decodeJSONNode decodes a single JSON document, rejecting unknown keys and trailing data.
+func decodeJSONNode(data []byte, node *jsonNode) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(node); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after JSON filter")
+	}
+	return nil
+}
+
+// expression returns the expression for a node. An "or" node becomes the terms of the expression.
+func (jb *jsonBuilder) expression(node *jsonNode, path string) (*Expression, error) {
+	if err := node.check(path); err != nil {
+		return nil, err
+	}
+	if node.Or == nil {
+		term, err := jb.term(node, path)
+		if err != nil {
+			return nil, err
+		}
+		return &Expression{Or: []*Term{term}}, nil
+	}
+
+	expr := &Expression{Or: make([]*Term, len(node.Or))}
+	for i, child := range node.Or {
+		term, err := jb.term(child, childPath(path, "or", i))
+		if err != nil {
+			return nil, err
+		}
+		expr.Or[i] = term
+	}
+	return expr, nil
+}
+
+// term returns the term for a node. An "and" node becomes the factors of the term.
+func (jb *jsonBuilder) term(node *jsonNode, path string) (*Term, error) {
+	if err := node.check(path); err != nil {
+		return nil, err
+	}
+	if node.And == nil {
+		factor, err := jb.factor(node, path)
+		if err != nil {
+			return nil, err
+		}
+		return &Term{And: []*Factor{factor}}, nil
+	}
+
+	term := &Term{And: make([]*Factor, len(node.And))}
+	for i, child := range node.And {
+		factor, err := jb.factor(child, childPath(path, "and", i))
+		if err != nil {
+			return nil, err
+		}
+		term.And[i] = factor
+	}
+	return term, nil
+}
+
+// factor returns the factor for a node. Groups are parenthesized and conditions are predicates.
+func (jb *jsonBuilder) factor(node *jsonNode, path string) (*Factor, error) {
+	if err := node.check(path); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case node.Not != nil:
+		inner, err := jb.factor(node.Not, childPath(path, "not", -1))
+		if err != nil {
+			return nil, err
+		}
+		if inner.Not {
+			return &Factor{Not: true, SubExpr: &Expression{Or: []*Term{{And: []*Factor{inner}}}}}, nil
+		}
+		inner.Not = true
+		return inner, nil
+	case node.And != nil || node.Or != nil:
+		expr, err := jb.expression(node, path)
+		if err != nil {
+			return nil, err
+		}
+		return &Factor{SubExpr: expr}, nil
+	default:
+		pred, err := jb.predicate(node, path)
+		if err != nil {
+			return nil, err
+		}
+		return &Factor{Predicate: pred}, nil
+	}
+}
+
+// predicate returns the predicate for a condition node.
+func (jb *jsonBuilder) predicate(node *jsonNode, path string) (*Predicate, error) {
+	if node.path != "" {
+		path = node.path
+	}
+	if !jsonFieldPattern.MatchString(node.Field) {
+		return nil, jsonError(path, "invalid field %q", node.Field)
+	}
+
+	op := strings.Join(strings.Fields(strings.ToLower(node.Op)), " ")
+	not := strings.HasPrefix(op, "not ")
+	base := strings.TrimPrefix(op, "not ")
+
+	var (
+		operation = &Operation{}
+		err       error
+	)
+	switch {
+	case op == "is null" || op == "is not null":
+		if node.Value != nil && string(node.Value) != "null" {
+			return nil, jsonError(path, "operator %s takes no value", op)
+		}
+		operation.IsNull = &IsNullOp{Is: "IS", Not: op == "is not null", Null: "NULL"}
+	case op == "=" || op == "!=" || op == "<>" || op == "<" || op == "<=" || op == ">" || op == ">=":
+		compare := &CompareOp{Operator: CompareOperator{Type: op}}
+		compare.Right, err = jb.value(node.Value, path, op)
+		operation.Compare = compare
+	case base == "like" || base == "ilike":
+		like := &LikeOp{Not: not, Type: LikeType{Operator: strings.ToUpper(base)}}
+		like.Pattern, err = jb.value(node.Value, path, op)
+		operation.Like = like
+	case base == "matches":
+		match := &MatchOp{Not: not, Matches: "MATCHES"}
+		match.Query, err = jb.value(node.Value, path, op)
+		operation.Match = match
+	case base == "in":
+		in := &InOp{Not: not, In: "IN"}
+		in.Values, err = jb.values(node.Value, path, op, -1)
+		operation.In = in
+	case base == "between":
+		var bounds []*Value
+		if bounds, err = jb.values(node.Value, path, op, 2); err == nil {
+			operation.Between = &BetweenOp{Not: not, Between: "BETWEEN", Lower: bounds[0], And: "AND", Upper: bounds[1]}
+		}
+	default:
+		return nil, jsonError(path, "unknown operator %q", node.Op)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Predicate{
+		Left:      &Value{Field: &FieldRef{Parts: strings.Split(node.Field, ".")}},
+		Operation: operation,
+	}, nil
+}
+
+// values returns the values of a JSON array, which must have n elements if n is not negative.
+func (jb *jsonBuilder) values(raw json.RawMessage, path, op string, n int) ([]*Value, error) {
+	var items []json.RawMessage
+	if raw == nil || json.Unmarshal(raw, &items) != nil || items == nil {
+		return nil, jsonError(path, "operator %s requires an array value", op)
+	}
+	if n >= 0 && len(items) != n {
+		return nil, jsonError(path, "operator %s requires %d values", op, n)
+	}
+
+	vals := make([]*Value, len(items))
+	for i, item := range items {
+		val, err := jb.value(item, path, op)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = val
+	}
+	return vals, nil
+}
+
+// value returns the literal for a JSON scalar. Numbers keep their numerals, as the parser does.
+func (jb *jsonBuilder) value(raw json.RawMessage, path, op string) (*Value, error) {
+	if raw == nil {
+		return nil, jsonError(path, "operator %s requires a value", op)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, jsonError(path, "invalid value: %v", err)
+	}
+
+	lit := &LiteralValue{}
+	switch v := v.(type) {
+	case nil:
+		lit.Null = true
+	case bool:
+		lit.Boolean = &BooleanLit{True: v, False: !v}
+	case json.Number:
+		numeral := v.String()
+		lit.Numeral = &numeral
+	case string:
+		quoted := jb.escapes.quote(v)
+		lit.String = &quoted
+	default:
+		return nil, jsonError(path, "operator %s requires a string, number, boolean, or null value", op)
+	}
+	return &Value{Literal: lit}, nil
+}
+
+// check returns an error unless the node is exactly one of a group or a condition.
+func (n *jsonNode) check(path string) error {
+	if n == nil {
+		return jsonError(path, "filter is null")
+	}
+
+	kinds := 0
+	for _, set := range []bool{n.And != nil, n.Or != nil, n.Not != nil, n.Field != "" || n.Op != "" || n.Value != nil} {
+		if set {
+			kinds++
+		}
+	}
+	switch {
+	case kinds != 1:
+		return jsonError(path, `filter must have exactly one of "and", "or", "not", or "field"`)
+	case n.And != nil && len(n.And) == 0, n.Or != nil && len(n.Or) == 0:
+		return jsonError(path, "group is empty")
+	}
+	return nil
+}
+
+// childPath returns the path of a child node for error messages, e.g. and[1].not.
+func childPath(path, key string, i int) string {
+	if path != "" {
+		key = path + "." + key
+	}
+	if i < 0 {
+		return key
+	}
+	return fmt.Sprintf("%s[%d]", key, i)
+}
+
+// jsonError returns an error located at the path of a node.
+func jsonError(path, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if path == "" {
+		return errors.New(msg)
+	}
+	return errors.New(path + ": " + msg)
+}
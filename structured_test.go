@@ -0,0 +1,204 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "condition",
+			input: `{"field": "age", "op": ">", "value": 18}`,
+			want:  "age > 18",
+		},
+		{
+			name: "and",
+			input: `{"and": [
+				{"field": "age", "op": ">=", "value": 18.5},
+				{"field": "status", "op": "in", "value": ["active", "pending"]},
+				{"field": "users.verified", "op": "=", "value": true}
+			]}`,
+			want: "age >= 18.5 AND status IN ('active', 'pending') AND users.verified = TRUE",
+		},
+		{
+			name: "or of and",
+			input: `{"or": [
+				{"and": [{"field": "a", "op": "=", "value": 1}, {"field": "b", "op": "!=", "value": 2}]},
+				{"field": "c", "op": "is null"}
+			]}`,
+			want: "a = 1 AND b != 2 OR c IS NULL",
+		},
+		{
+			name: "nested groups",
+			input: `{"and": [
+				{"field": "a", "op": "<", "value": -5},
+				{"or": [{"field": "b", "op": "like", "value": "x%"}, {"field": "c", "op": "NOT  ILIKE", "value": "y%"}]}
+			]}`,
+			want: "a < -5 AND (b LIKE 'x%' OR c NOT ILIKE 'y%')",
+		},
+		{
+			name:  "not",
+			input: `{"not": {"or": [{"field": "a", "op": "between", "value": [1, 10]}, {"field": "b", "op": "is not null", "value": null}]}}`,
+			want:  "NOT (a BETWEEN 1 AND 10 OR b IS NOT NULL)",
+		},
+		{
+			name:  "double negation",
+			input: `{"not": {"not": {"field": "a", "op": "not in", "value": [1]}}}`,
+			want:  "NOT (NOT a NOT IN (1))",
+		},
+		{
+			name:  "matches",
+			input: `{"field": "body", "op": "matches", "value": "quick fox"}`,
+			want:  "body MATCHES 'quick fox'",
+		},
+		{
+			name:  "escaped strings",
+			input: `{"field": "name", "op": "=", "value": "O'Brien \\ Co"}`,
+			want:  `name = 'O''Brien \\ Co'`,
+		},
+		{
+			name:  "null",
+			input: `{"field": "deleted_at", "op": "=", "value": null}`,
+			want:  "deleted_at = NULL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.ParseJSON([]byte(tt.input))
+			require.NoError(t, err)
+
+			want, err := where.Parse(tt.want)
+			require.NoError(t, err)
+			requireEqualAST(t, want.Expression, filter.Expression)
+			require.Equal(t, tt.want, filter.String())
+		})
+	}
+}
+
+func TestParseJSONStringValues(t *testing.T) {
+	parser, err := where.NewParser(where.WithEscapeMode(where.EscapeStandard))
+	require.NoError(t, err)
+
+	for _, p := range []*where.Parser{parser, nil} {
+		var filter *where.Filter
+		input := []byte(`{"field": "path", "op": "=", "value": "C:\\temp\\n 'x'"}`)
+		if p == nil {
+			filter, err = where.ParseJSON(input)
+		} else {
+			filter, err = p.ParseJSON(input)
+		}
+		require.NoError(t, err)
+
+		_, params, err := filter.ToSQL("postgres")
+		require.NoError(t, err)
+		require.Equal(t, []any{`C:\temp\n 'x'`}, params)
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "empty",
+			input: " ",
+			err:   "empty filter expression",
+		},
+		{
+			name:  "invalid JSON",
+			input: `{"field": "a"`,
+			err:   "failed to parse filter expression: unexpected EOF",
+		},
+		{
+			name:  "unknown key",
+			input: `{"field": "a", "op": "=", "value": 1, "type": "number"}`,
+			err:   `failed to parse filter expression: json: unknown field "type"`,
+		},
+		{
+			name:  "trailing data",
+			input: `{"field": "a", "op": "=", "value": 1} {}`,
+			err:   "failed to parse filter expression: unexpected data after JSON filter",
+		},
+		{
+			name:  "mixed node",
+			input: `{"and": [{"field": "a", "op": "=", "value": 1, "not": {"field": "b", "op": "is null"}}]}`,
+			err:   `failed to parse filter expression: and[0]: filter must have exactly one of "and", "or", "not", or "field"`,
+		},
+		{
+			name:  "empty group",
+			input: `{"or": [{"field": "a", "op": "=", "value": 1}, {"and": []}]}`,
+			err:   "failed to parse filter expression: or[1]: group is empty",
+		},
+		{
+			name:  "injected field",
+			input: `{"not": {"field": "a = 1 OR b", "op": "=", "value": 1}}`,
+			err:   `failed to parse filter expression: not: invalid field "a = 1 OR b"`,
+		},
+		{
+			name:  "unknown operator",
+			input: `{"field": "a", "op": "==", "value": 1}`,
+			err:   `failed to parse filter expression: unknown operator "=="`,
+		},
+		{
+			name:  "missing value",
+			input: `{"field": "a", "op": "<"}`,
+			err:   "failed to parse filter expression: operator < requires a value",
+		},
+		{
+			name:  "object value",
+			input: `{"field": "a", "op": "=", "value": {"field": "b"}}`,
+			err:   "failed to parse filter expression: operator = requires a string, number, boolean, or null value",
+		},
+		{
+			name:  "scalar IN",
+			input: `{"field": "a", "op": "in", "value": 1}`,
+			err:   "failed to parse filter expression: operator in requires an array value",
+		},
+		{
+			name:  "BETWEEN bounds",
+			input: `{"field": "a", "op": "not between", "value": [1]}`,
+			err:   "failed to parse filter expression: operator not between requires 2 values",
+		},
+		{
+			name:  "IS NULL value",
+			input: `{"field": "a", "op": "is null", "value": 1}`,
+			err:   "failed to parse filter expression: operator is null takes no value",
+		},
+		{
+			name:  "validated like parsed filters",
+			input: `{"field": "a", "op": "in", "value": []}`,
+			err:   "filter validation failed: IN expression requires at least one value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := where.ParseJSON([]byte(tt.input))
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestParseJSONOptions(t *testing.T) {
+	var rejected string
+	parser, err := where.NewParser(
+		where.WithMaxINItems(2),
+		where.WithRejectionHandler(func(input string, _ error, _ where.RejectionMeta) { rejected = input }),
+	)
+	require.NoError(t, err)
+
+	input := `{"field": "a", "op": "in", "value": [1, 2, 3]}`
+	_, err = parser.ParseJSON([]byte(input))
+	require.ErrorContains(t, err, "IN expression exceeds maximum of 2 items")
+	require.Equal(t, input, rejected)
+}
@@ -0,0 +1,107 @@
+package where
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxSuggestionDistance is the largest edit distance between a rejected name and an allowed one for
+// which the allowed name is suggested. Shorter names allow fewer edits, see suggest.
+const maxSuggestionDistance = 2
+
+// fieldNotAllowed returns the error for a field the validator rejected, suggesting the closest
+// allowed field, if any.
+func (v *Validator) fieldNotAllowed(field string) *MessageError {
+	msg := newMessage(MsgFieldNotAllowed, "field", strconv.Quote(field))
+	field = fieldName(field)
+	if v.deniedFields[strings.ToLower(field)] {
+		return msg
+	}
+
+	var candidates []string
+	for _, names := range v.allowedFields {
+		for _, name := range names {
+			if v.IsFieldAllowed(name) {
+				candidates = append(candidates, name)
+			}
+		}
+	}
+	return withSuggestion(msg, field, candidates)
+}
+
+// functionNotAllowed returns the error for a function the validator rejected, suggesting the closest
+// allowed function, if any.
+func (v *Validator) functionNotAllowed(function string) *MessageError {
+	msg := newMessage(MsgFunctionNotAllowed, "function", strconv.Quote(function))
+	if v.deniedFunctions[strings.ToUpper(function)] {
+		return msg
+	}
+
+	var candidates []string
+	for name := range v.allowedFunctions {
+		if v.IsFunctionAllowed(name) {
+			candidates = append(candidates, name)
+		}
+	}
+	return withSuggestion(msg, function, candidates)
+}
+
+// withSuggestion adds the candidate closest to name to the message, if one is close enough.
+func withSuggestion(msg *MessageError, name string, candidates []string) *MessageError {
+	if suggestion, ok := suggest(name, candidates); ok {
+		msg.Args["suggestion"] = strconv.Quote(suggestion)
+	}
+	return msg
+}
+
+// suggest returns the candidate with the smallest case-insensitive edit distance to name, preferring
+// the first in sorted order. Candidates are only suggested if they differ from name by at most a
+// third of its length, and by at least one edit for names shorter than three characters.
+func suggest(name string, candidates []string) (string, bool) {
+	limit := max(1, min(maxSuggestionDistance, len([]rune(name))/3))
+	lower := strings.ToLower(name)
+
+	slices.Sort(candidates)
+	best, bestDistance := "", limit+1
+	for _, candidate := range candidates {
+		if candidate == name {
+			continue
+		}
+		if d := editDistance(lower, strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance returns the number of single character insertions, deletions, substitutions, and
+// transpositions of adjacent characters needed to turn a into b, so that the common typo "emial"
+// is one edit away from "email".
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// prev2, prev, and curr are the last three rows of the distance matrix.
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
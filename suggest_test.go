@@ -0,0 +1,95 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	_ "github.com/pseudomuto/where/drivers/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestions(t *testing.T) {
+	validator := where.NewValidator().
+		AllowFields("email", "name", "status", "created_at", "id", "ip").
+		AllowFunctions("LOWER", "COALESCE").
+		DenyFields("password")
+
+	tests := []struct {
+		name    string
+		filter  string
+		wantErr string
+	}{
+		{
+			name:    "transposed letters",
+			filter:  "emial = 'a@b.c'",
+			wantErr: `field "emial" is not allowed; did you mean "email"?`,
+		},
+		{
+			name:    "missing letter",
+			filter:  "created_a > 1",
+			wantErr: `field "created_a" is not allowed; did you mean "created_at"?`,
+		},
+		{
+			name:    "case is ignored",
+			filter:  "STATSU = 'open'",
+			wantErr: `field "STATSU" is not allowed; did you mean "status"?`,
+		},
+		{
+			name:    "function",
+			filter:  "lowr(name) = 'x'",
+			wantErr: `function "lowr" is not allowed; did you mean "LOWER"?`,
+		},
+		{
+			name:    "ties prefer the first in order",
+			filter:  "iq = 1",
+			wantErr: `field "iq" is not allowed; did you mean "id"?`,
+		},
+		{
+			name:    "no close match",
+			filter:  "secret = 1",
+			wantErr: `field "secret" is not allowed`,
+		},
+		{
+			name:    "too many edits for a short name",
+			filter:  "nm = 'x'",
+			wantErr: `field "nm" is not allowed`,
+		},
+		{
+			name:    "denied fields are not corrected",
+			filter:  "password = 'x'",
+			wantErr: `field "password" is not allowed`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := mustParse(t, tt.filter).ToSQL("postgres", where.WithValidator(validator))
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSuggestionsAtParseTime(t *testing.T) {
+	parser, err := where.NewParser(where.WithFunctions("UPPER", "LOWER"))
+	require.NoError(t, err)
+
+	_, err = parser.Parse("UPER(name) = 'X'")
+	require.EqualError(t, err, `filter validation failed: function "UPER" is not allowed; did you mean "UPPER"?`)
+}
+
+func TestLocalizeSuggestion(t *testing.T) {
+	where.RegisterCatalog("xs", where.Catalog{
+		where.MsgFieldNotAllowed: "campo {field} no permitido",
+		where.MsgDidYouMean:      "¿quiso decir {suggestion}?",
+	})
+	where.RegisterCatalog("xt", where.Catalog{
+		where.MsgFieldNotAllowed: "campo {field} no permitido",
+	})
+
+	validator := where.NewValidator().AllowFields("email")
+	_, _, err := mustParse(t, "emial = 'x'").ToSQL("postgres", where.WithValidator(validator))
+	require.Error(t, err)
+
+	require.Equal(t, `campo "emial" no permitido; ¿quiso decir "email"?`, where.Localize(err, "xs"))
+	require.Equal(t, `campo "emial" no permitido; did you mean "email"?`, where.Localize(err, "xt"))
+}
@@ -0,0 +1,59 @@
+package where
+
+import (
+	"time"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/pkg/errors"
+)
+
+// ErrParseTimeout is wrapped by the error returned when parsing takes longer than the timeout set
+// with WithParseTimeout.
+var ErrParseTimeout = errors.New("parse timeout")
+
+// WithParseTimeout returns a ParserOption that stops parsing a filter after the given wall clock
+// time and returns an error wrapping ErrParseTimeout, as a bound on the time spent on any one input.
+// The parser checks the deadline at each step of the grammar, which makes parsing several times
+// slower, so inputs are still best limited with WithMaxInputLength and WithMaxDepth. A value of zero (the
+// default) disables the timeout.
+func WithParseTimeout(d time.Duration) ParserOption {
+	return func(o *parserOptions) {
+		o.parseTimeout = d
+	}
+}
+
+// deadlineWriter is the trace output of a parse with a timeout. The grammar writes to it at each
+// step, and it stops the parse by panicking with ErrParseTimeout once the deadline has passed.
+type deadlineWriter struct {
+	deadline time.Time
+}
+
+func (w deadlineWriter) Write(p []byte) (int, error) {
+	if time.Now().After(w.deadline) {
+		panic(ErrParseTimeout)
+	}
+	return len(p), nil
+}
+
+// parseGrammar parses the input with the grammar, stopping after the parse timeout, if any.
+func (p *Parser) parseGrammar(input string) (filter *Filter, err error) {
+	var opts []participle.ParseOption
+	if p.opts.parseTimeout > 0 {
+		opts = append(opts, participle.Trace(deadlineWriter{deadline: time.Now().Add(p.opts.parseTimeout)}))
+		defer func() {
+			if r := recover(); r != nil {
+				if r != ErrParseTimeout {
+					panic(r)
+				}
+				filter, err = nil, rejected(newMessage(MsgParseTimeout, "timeout", p.opts.parseTimeout).wrapping(ErrParseTimeout),
+					RejectionMeta{Rule: RuleParseTimeout})
+			}
+		}()
+	}
+
+	filter, err = p.parseString(input, opts...)
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+	return filter, nil
+}
@@ -0,0 +1,51 @@
+package where_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithParseTimeout(t *testing.T) {
+	slow := strings.Repeat("(a = 1 OR b LIKE 'x%') AND ", 300) + "c = 1"
+
+	var rejected []where.RejectionMeta
+	parser, err := where.NewParser(
+		where.WithParseTimeout(time.Microsecond),
+		where.WithRejectionHandler(func(_ string, _ error, meta where.RejectionMeta) {
+			rejected = append(rejected, meta)
+		}),
+	)
+	require.NoError(t, err)
+
+	// The parse stops at the deadline rather than running on in the background.
+	goroutines := runtime.NumGoroutine()
+	filter, err := parser.Parse(slow)
+	require.Equal(t, goroutines, runtime.NumGoroutine())
+	require.Nil(t, filter)
+	require.True(t, errors.Is(err, where.ErrParseTimeout))
+	require.EqualError(t, err, "filter expression could not be parsed within 1µs: parse timeout")
+	require.Equal(t, []where.RejectionMeta{{Rule: where.RuleParseTimeout}}, rejected)
+
+	// Simple IN lists are not parsed by the grammar, so they are not subject to the timeout.
+	_, err = parser.Parse("id IN (1, 2, 3)")
+	require.NoError(t, err)
+}
+
+func TestWithParseTimeoutNotReached(t *testing.T) {
+	parser, err := where.NewParser(where.WithParseTimeout(time.Minute))
+	require.NoError(t, err)
+
+	filter, err := parser.Parse("age > 18 AND status = 'active'")
+	require.NoError(t, err)
+	require.Equal(t, "age > 18 AND status = 'active'", filter.String())
+
+	_, err = parser.Parse("age > > 18")
+	require.EqualError(t, err, `failed to parse filter expression: 1:7: unexpected token ">" (expected <number>)`)
+	require.False(t, errors.Is(err, where.ErrParseTimeout))
+}
@@ -0,0 +1,56 @@
+package where
+
+import "time"
+
+// TimeRange is the range of times a filter allows for a field. A nil Min or Max means the filter
+// does not bound that side.
+type TimeRange struct {
+	// Min is the earliest time allowed, if bounded.
+	Min *time.Time
+
+	// MinInclusive is true if Min itself is allowed (>= or BETWEEN rather than >).
+	MinInclusive bool
+
+	// Max is the latest time allowed, if bounded.
+	Max *time.Time
+
+	// MaxInclusive is true if Max itself is allowed (<= or BETWEEN rather than <).
+	MaxInclusive bool
+}
+
+// ExtractTimeRange returns the bounds the filter places on a timestamp field, so callers can pick
+// the partitions or tables to query and enforce retention limits. Bounds come from comparisons with
+// date/time literals (in DefaultTimeLayouts, read as UTC when they have no zone) or bound time.Time
+// values, and from BETWEEN. Bounds ANDed together are intersected, and OR branches are combined so
+// that a side is only bounded if every branch bounds it. Negated conditions do not bound the field.
+// The field name is case-insensitive.
+//
+// Example:
+//
+//	filter, _ := where.Parse("created_at >= '2024-01-01' AND created_at < '2024-02-01' AND status = 'a'")
+//	r := where.ExtractTimeRange(filter, "created_at")
+//	// r.Min: 2024-01-01 00:00:00 UTC (inclusive), r.Max: 2024-02-01 00:00:00 UTC (exclusive)
+func ExtractTimeRange(filter *Filter, field string) TimeRange {
+	if filter == nil {
+		return TimeRange{}
+	}
+
+	bounds := exprTimeBounds(filter.Expression, field)
+	return TimeRange{
+		Min:          bounds.lower,
+		MinInclusive: bounds.lower != nil && bounds.lowerInclusive,
+		Max:          bounds.upper,
+		MaxInclusive: bounds.upper != nil && bounds.upperInclusive,
+	}
+}
+
+// Contains reports whether t is within the range.
+func (r TimeRange) Contains(t time.Time) bool {
+	if r.Min != nil && (t.Before(*r.Min) || (!r.MinInclusive && t.Equal(*r.Min))) {
+		return false
+	}
+	if r.Max != nil && (t.After(*r.Max) || (!r.MaxInclusive && t.Equal(*r.Max))) {
+		return false
+	}
+	return true
+}
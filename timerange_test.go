@@ -0,0 +1,85 @@
+package where_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractTimeRange(t *testing.T) {
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		input string
+		want  where.TimeRange
+	}{
+		{
+			name:  "unbounded",
+			input: "status = 'active'",
+		},
+		{
+			name:  "comparisons",
+			input: "created_at >= '2024-01-01' AND created_at < '2024-02-01' AND status = 'active'",
+			want:  where.TimeRange{Min: &jan, MinInclusive: true, Max: &feb},
+		},
+		{
+			name:  "BETWEEN",
+			input: "CREATED_AT BETWEEN '2024-01-01' AND '2024-02-01T00:00:00Z'",
+			want:  where.TimeRange{Min: &jan, MinInclusive: true, Max: &feb, MaxInclusive: true},
+		},
+		{
+			name:  "reversed comparison",
+			input: "'2024-01-01' < created_at",
+			want:  where.TimeRange{Min: &jan},
+		},
+		{
+			name:  "tightest bounds",
+			input: "created_at > '2023-06-01' AND created_at >= '2024-01-01' AND created_at <= '2024-03-01' AND created_at < '2024-03-01'",
+			want:  where.TimeRange{Min: &jan, MinInclusive: true, Max: &mar},
+		},
+		{
+			name:  "equality",
+			input: "created_at = '2024-02-01'",
+			want:  where.TimeRange{Min: &feb, MinInclusive: true, Max: &feb, MaxInclusive: true},
+		},
+		{
+			name:  "OR branches",
+			input: "(created_at >= '2024-01-01' AND created_at < '2024-02-01') OR created_at BETWEEN '2024-02-01' AND '2024-03-01'",
+			want:  where.TimeRange{Min: &jan, MinInclusive: true, Max: &mar, MaxInclusive: true},
+		},
+		{
+			name:  "OR branch without bound",
+			input: "created_at >= '2024-01-01' OR status = 'active'",
+		},
+		{
+			name:  "negated",
+			input: "NOT (created_at >= '2024-01-01') AND created_at < '2024-03-01'",
+			want:  where.TimeRange{Max: &mar},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, where.ExtractTimeRange(filter, "created_at"))
+		})
+	}
+}
+
+func TestTimeRangeContains(t *testing.T) {
+	filter, err := where.Parse("created_at >= '2024-01-01' AND created_at < '2024-02-01'")
+	require.NoError(t, err)
+
+	r := where.ExtractTimeRange(filter, "created_at")
+	require.True(t, r.Contains(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	require.True(t, r.Contains(time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)))
+	require.False(t, r.Contains(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)))
+	require.False(t, r.Contains(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)))
+	require.True(t, where.TimeRange{}.Contains(time.Now()))
+}
@@ -0,0 +1,67 @@
+package where
+
+import (
+	"time"
+)
+
+// DefaultTimeLayouts lists the layouts used to recognize date/time string literals when no
+// layouts are configured explicitly.
+var DefaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// WithTimeParsing returns a BuildOption that binds string literals matching one of the given
+// layouts as time.Time parameters instead of strings. DefaultTimeLayouts is used when no layouts
+// are given. Timestamps without a zone are interpreted as UTC unless WithTimeLocation is used.
+func WithTimeParsing(layouts ...string) BuildOption {
+	return func(b *SQLBuilder) {
+		if len(layouts) == 0 {
+			layouts = DefaultTimeLayouts
+		}
+		b.timeLayouts = layouts
+	}
+}
+
+// WithTimeLocation returns a BuildOption that sets the location used for time literals that do not
+// specify a zone. Times are converted to loc before being bound. It has no effect without WithTimeParsing.
+func WithTimeLocation(loc *time.Location) BuildOption {
+	return func(b *SQLBuilder) {
+		b.timeLocation = loc
+	}
+}
+
+// timeParam returns the time.Time parameter for a string literal when time parsing is enabled.
+func (b *SQLBuilder) timeParam(s string) (time.Time, bool) {
+	if len(b.timeLayouts) == 0 {
+		return time.Time{}, false
+	}
+	return b.parseTimeParam(s, b.timeLayouts)
+}
+
+// parseTimeParam parses s with the given layouts in the builder's configured location.
+func (b *SQLBuilder) parseTimeParam(s string, layouts []string) (time.Time, bool) {
+	loc := b.timeLocation
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	t, ok := parseTime(s, layouts, loc)
+	if !ok {
+		return time.Time{}, false
+	}
+	return t.In(loc), true
+}
+
+// parseTime parses s using the first matching layout. Times without a zone are interpreted in loc.
+func parseTime(s string, layouts []string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
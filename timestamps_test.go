@@ -0,0 +1,58 @@
+package where_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTimeParsing(t *testing.T) {
+	filter, err := where.Parse("created_at BETWEEN '2024-01-15' AND '2024-01-31T12:30:00+02:00' AND name = 'John'")
+	require.NoError(t, err)
+
+	t.Run("disabled by default", func(t *testing.T) {
+		_, params, err := filter.ToSQL("postgres")
+		require.NoError(t, err)
+		require.Equal(t, []any{"2024-01-15", "2024-01-31T12:30:00+02:00", "John"}, params)
+	})
+
+	t.Run("default layouts", func(t *testing.T) {
+		sql, params, err := filter.ToSQL("postgres", where.WithTimeParsing())
+		require.NoError(t, err)
+		require.Equal(t, "(created_at BETWEEN $1 AND $2 AND name = $3)", sql)
+		require.Len(t, params, 3)
+
+		require.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), params[0])
+		require.True(t, time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC).Equal(params[1].(time.Time)))
+		require.Equal(t, "John", params[2])
+	})
+
+	t.Run("custom layouts", func(t *testing.T) {
+		filter, err := where.Parse("day = '15/01/2024' AND other = '2024-01-15'")
+		require.NoError(t, err)
+
+		_, params, err := filter.ToSQL("mysql", where.WithTimeParsing("02/01/2006"))
+		require.NoError(t, err)
+		require.Equal(t, []any{time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), "2024-01-15"}, params)
+	})
+
+	t.Run("location", func(t *testing.T) {
+		loc := time.FixedZone("EST", -5*60*60)
+		filter, err := where.Parse("a = '2024-01-15 08:00:00' AND b = '2024-01-15T08:00:00Z'")
+		require.NoError(t, err)
+
+		_, params, err := filter.ToSQL("clickhouse", where.WithTimeParsing(), where.WithTimeLocation(loc))
+		require.NoError(t, err)
+		require.Len(t, params, 2)
+
+		a := params[0].(time.Time)
+		require.Equal(t, time.Date(2024, 1, 15, 8, 0, 0, 0, loc), a)
+		require.Equal(t, loc, a.Location())
+
+		b := params[1].(time.Time)
+		require.True(t, time.Date(2024, 1, 15, 3, 0, 0, 0, loc).Equal(b))
+		require.Equal(t, loc, b.Location())
+	})
+}
@@ -0,0 +1,193 @@
+package where
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// AnyArgs registers a function translation for calls with any number of arguments.
+const AnyArgs = -1
+
+var (
+	translationsMu sync.RWMutex
+	translations   = make(map[translationKey][]versionedTranslation)
+
+	templateArgPattern = regexp.MustCompile(`\{(\d+|\*)\}`)
+)
+
+type (
+	// FunctionTranslator renders a function call from the SQL of its arguments. Arguments are built
+	// in call order, so translators for drivers with positional placeholders (?) must not reorder
+	// or repeat arguments that bind parameters; templates handle this automatically.
+	FunctionTranslator func(args []string) (string, error)
+
+	translationKey struct {
+		driver   string
+		function string
+		argCount int
+	}
+
+	// translation builds a function call for a specific driver.
+	translation func(b *SQLBuilder, fn *FunctionCall) (string, error)
+
+	// versionedTranslation is a translation for servers of at least minVersion.
+	versionedTranslation struct {
+		minVersion  Version
+		translation translation
+	}
+
+	// templatePart is a literal piece of a template followed by an optional argument reference.
+	templatePart struct {
+		text string
+		arg  int
+		all  bool
+		ref  bool
+	}
+)
+
+// RegisterFunctionTemplate registers a SQL template used to render calls to the named function
+// with argCount arguments (or AnyArgs) on the given driver. Templates reference arguments by
+// zero-based index, e.g. "EXTRACT(YEAR FROM {0})", and {*} expands to all arguments separated
+// by commas. Arguments are rendered where they are referenced, so templates may reorder or
+// repeat them on any driver. Function names are case-insensitive, and the driver may be given
+// by any of its registered names. Registering the same function again replaces the translation.
+//
+// Example:
+//
+//	where.RegisterFunctionTemplate("postgres", "YEAR", 1, "EXTRACT(YEAR FROM {0})")
+func RegisterFunctionTemplate(driver, function string, argCount int, template string) {
+	registerTranslation(driver, function, argCount, Version{}, templateTranslation(argCount, template))
+}
+
+// RegisterVersionedFunctionTemplate registers a template like RegisterFunctionTemplate that is only
+// used for servers of at least minVersion, so a function can be rendered differently by server
+// version. Calls are rendered with the translation for the newest version the driver's Versioner
+// reaches, falling back to one registered with RegisterFunctionTemplate, and drivers without a
+// version use the newest translation.
+//
+// Example:
+//
+//	where.RegisterFunctionTemplate("mysql", "JSON_VALUE", 2, "JSON_UNQUOTE(JSON_EXTRACT({0}, {1}))")
+//	where.RegisterVersionedFunctionTemplate("mysql", "JSON_VALUE", 2, where.Version{Major: 8, Patch: 21}, "JSON_VALUE({0}, {1})")
+func RegisterVersionedFunctionTemplate(driver, function string, argCount int, minVersion Version, template string) {
+	registerTranslation(driver, function, argCount, minVersion, templateTranslation(argCount, template))
+}
+
+// templateTranslation returns the translation rendering the template.
+func templateTranslation(argCount int, template string) translation {
+	parts := parseTemplate(template)
+	for _, part := range parts {
+		if part.ref && !part.all && argCount != AnyArgs && part.arg >= argCount {
+			panic(fmt.Sprintf("where: function template %q references argument %d of %d", template, part.arg, argCount))
+		}
+	}
+
+	return func(b *SQLBuilder, fn *FunctionCall) (string, error) {
+		var sb strings.Builder
+		for _, part := range parts {
+			sb.WriteString(part.text)
+			if !part.ref {
+				continue
+			}
+
+			args := fn.Args
+			if !part.all {
+				if part.arg >= len(args) {
+					return "", fmt.Errorf("function %s requires at least %d arguments", fn.Name, part.arg+1)
+				}
+				args = args[part.arg : part.arg+1]
+			}
+
+			built, err := b.buildArgs(args)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(strings.Join(built, ", "))
+		}
+		return sb.String(), nil
+	}
+}
+
+// RegisterFunctionTranslator registers a callback used to render calls to the named function with
+// argCount arguments (or AnyArgs) on the given driver. See RegisterFunctionTemplate.
+func RegisterFunctionTranslator(driver, function string, argCount int, translator FunctionTranslator) {
+	if translator == nil {
+		panic("where: RegisterFunctionTranslator translator is nil")
+	}
+
+	registerTranslation(driver, function, argCount, Version{}, func(b *SQLBuilder, fn *FunctionCall) (string, error) {
+		args, err := b.buildArgs(fn.Args)
+		if err != nil {
+			return "", err
+		}
+		return translator(args)
+	})
+}
+
+func registerTranslation(driver, function string, argCount int, minVersion Version, t translation) {
+	if function == "" {
+		panic("where: function name is empty")
+	}
+	if argCount < AnyArgs {
+		panic("where: invalid function argument count")
+	}
+
+	// Translations are keyed by Driver.Name() so they apply to every alias of the driver.
+	if d, err := GetDriver(driver); err == nil {
+		driver = d.Name()
+	}
+
+	translationsMu.Lock()
+	defer translationsMu.Unlock()
+
+	key := translationKey{driver: driver, function: strings.ToUpper(function), argCount: argCount}
+	versions := slices.DeleteFunc(translations[key], func(v versionedTranslation) bool {
+		return v.minVersion == minVersion
+	})
+	versions = append(versions, versionedTranslation{minVersion: minVersion, translation: t})
+
+	// Newest first, so lookups use the first translation the driver's version reaches.
+	slices.SortFunc(versions, func(a, b versionedTranslation) int { return b.minVersion.Compare(a.minVersion) })
+	translations[key] = versions
+}
+
+// lookupTranslation returns the translation registered for the function call on the driver,
+// preferring one registered for the exact argument count over AnyArgs, and otherwise the one for the
+// newest version the driver reaches.
+func lookupTranslation(driver Driver, fn *FunctionCall) (translation, bool) {
+	version := DriverVersion(driver)
+
+	translationsMu.RLock()
+	defer translationsMu.RUnlock()
+
+	key := translationKey{driver: driver.Name(), function: strings.ToUpper(fn.Name), argCount: len(fn.Args)}
+	for _, argCount := range []int{len(fn.Args), AnyArgs} {
+		key.argCount = argCount
+		for _, v := range translations[key] {
+			if version.AtLeast(v.minVersion) {
+				return v.translation, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func parseTemplate(template string) []templatePart {
+	var parts []templatePart
+	last := 0
+	for _, loc := range templateArgPattern.FindAllStringSubmatchIndex(template, -1) {
+		part := templatePart{text: template[last:loc[0]], ref: true}
+		if ref := template[loc[2]:loc[3]]; ref == "*" {
+			part.all = true
+		} else {
+			part.arg, _ = strconv.Atoi(ref)
+		}
+		parts = append(parts, part)
+		last = loc[1]
+	}
+	return append(parts, templatePart{text: template[last:]})
+}
@@ -0,0 +1,155 @@
+package where_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterFunctionTemplate(t *testing.T) {
+	where.RegisterFunctionTemplate("pg", "test_year", 1, "EXTRACT(YEAR FROM {0})")
+	where.RegisterFunctionTemplate("mysql", "test_swap", 2, "TEST_SWAP({1}, {0})")
+	where.RegisterFunctionTemplate("postgres", "test_coalesce", where.AnyArgs, "COALESCE({*}, 0)")
+
+	tests := []struct {
+		name     string
+		driver   string
+		input    string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "registered through alias",
+			driver:   "postgres",
+			input:    "TEST_YEAR(created_at) = 2024",
+			wantSQL:  "EXTRACT(YEAR FROM created_at) = $1",
+			wantArgs: []any{float64(2024)},
+		},
+		{
+			name:     "case-insensitive name",
+			driver:   "postgresql",
+			input:    "test_year(created_at) = 2024",
+			wantSQL:  "EXTRACT(YEAR FROM created_at) = $1",
+			wantArgs: []any{float64(2024)},
+		},
+		{
+			name:     "other drivers unchanged",
+			driver:   "mysql",
+			input:    "TEST_YEAR(created_at) = 2024",
+			wantSQL:  "TEST_YEAR(created_at) = ?",
+			wantArgs: []any{float64(2024)},
+		},
+		{
+			name:     "arg count must match",
+			driver:   "postgres",
+			input:    "TEST_YEAR(created_at, 'x') = 2024",
+			wantSQL:  "TEST_YEAR(created_at, $1) = $2",
+			wantArgs: []any{"x", float64(2024)},
+		},
+		{
+			name:     "reordered arguments keep positional params in order",
+			driver:   "mysql",
+			input:    "TEST_SWAP('a', 'b') = 1",
+			wantSQL:  "TEST_SWAP(?, ?) = ?",
+			wantArgs: []any{"b", "a", float64(1)},
+		},
+		{
+			name:     "any args",
+			driver:   "postgres",
+			input:    "TEST_COALESCE(a, b, 'c') > 1",
+			wantSQL:  "COALESCE(a, b, $1, 0) > $2",
+			wantArgs: []any{"c", float64(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			sql, args, err := filter.ToSQL(tt.driver)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+
+	require.PanicsWithValue(t, `where: function template "F({1})" references argument 1 of 1`, func() {
+		where.RegisterFunctionTemplate("postgres", "test_bad", 1, "F({1})")
+	})
+}
+
+func TestRegisterFunctionTranslator(t *testing.T) {
+	where.RegisterFunctionTranslator("clickhouse", "test_concat", where.AnyArgs, func(args []string) (string, error) {
+		return strings.Join(args, " || "), nil
+	})
+
+	filter, err := where.Parse("TEST_CONCAT(first, ' ', last) = 'a b'")
+	require.NoError(t, err)
+
+	sql, args, err := filter.ToSQL("clickhouse")
+	require.NoError(t, err)
+	require.Equal(t, "first || ? || last = ?", sql)
+	require.Equal(t, []any{" ", "a b"}, args)
+
+	// The validator checks the portable function name.
+	validator := where.NewValidator().AllowFields("first", "last")
+	_, _, err = filter.ToSQL("clickhouse", where.WithValidator(validator))
+	require.EqualError(t, err, `function "TEST_CONCAT" is not allowed`)
+}
+
+type versionedDriver struct {
+	*MockDriver
+	version where.Version
+}
+
+func (d versionedDriver) Version() where.Version { return d.version }
+
+func TestRegisterVersionedFunctionTemplate(t *testing.T) {
+	where.RegisterFunctionTemplate("versioned", "TEST_JSON", 2, "OLD_JSON({0}, {1})")
+	where.RegisterVersionedFunctionTemplate("versioned", "TEST_JSON", 2, where.Version{Major: 8, Patch: 21}, "NEW_JSON({0}, {1})")
+	where.RegisterVersionedFunctionTemplate("versioned", "TEST_JSON", 2, where.Version{Major: 8}, "MID_JSON({0}, {1})")
+	where.RegisterVersionedFunctionTemplate("versioned", "TEST_ONLY_NEW", 1, where.Version{Major: 8}, "NEW_ONLY({0})")
+
+	tests := []struct {
+		version where.Version
+		input   string
+		wantSQL string
+	}{
+		{where.Version{}, "TEST_JSON(a, b)", "NEW_JSON([a], [b])"},
+		{where.Version{Major: 9}, "TEST_JSON(a, b)", "NEW_JSON([a], [b])"},
+		{where.Version{Major: 8, Patch: 21}, "TEST_JSON(a, b)", "NEW_JSON([a], [b])"},
+		{where.Version{Major: 8, Patch: 3}, "TEST_JSON(a, b)", "MID_JSON([a], [b])"},
+		{where.Version{Major: 5, Minor: 7}, "TEST_JSON(a, b)", "OLD_JSON([a], [b])"},
+		{where.Version{Major: 8}, "TEST_ONLY_NEW(a)", "NEW_ONLY([a])"},
+		{where.Version{Major: 5, Minor: 7}, "TEST_ONLY_NEW(a)", "TEST_ONLY_NEW([a])"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version.String()+" "+tt.input, func(t *testing.T) {
+			registry := where.NewRegistry()
+			registry.Register("versioned", versionedDriver{MockDriver: &MockDriver{name: "versioned"}, version: tt.version})
+
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			sql, _, err := filter.ToSQL("versioned", where.WithRegistry(registry))
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+		})
+	}
+
+	// Registering a version again replaces its translation.
+	where.RegisterVersionedFunctionTemplate("versioned", "TEST_JSON", 2, where.Version{Major: 8}, "MID2_JSON({0}, {1})")
+
+	registry := where.NewRegistry()
+	registry.Register("versioned", versionedDriver{MockDriver: &MockDriver{name: "versioned"}, version: where.Version{Major: 8}})
+	filter, err := where.Parse("TEST_JSON(a, b)")
+	require.NoError(t, err)
+
+	sql, _, err := filter.ToSQL("versioned", where.WithRegistry(registry))
+	require.NoError(t, err)
+	require.Equal(t, "MID2_JSON([a], [b])", sql)
+}
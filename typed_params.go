@@ -0,0 +1,86 @@
+package where
+
+import (
+	"reflect"
+	"time"
+)
+
+type (
+	// Param describes a bound parameter returned by ToSQLTyped.
+	Param struct {
+		// Name is the placeholder for the parameter as it appears in the SQL (e.g. "$1" or "?").
+		Name string
+
+		// Value is the bound value, identical to the corresponding value returned by ToSQL.
+		Value any
+
+		// Field is the field the value is compared against, or empty when the parameter does not
+		// belong to a field (e.g. EXISTS subquery arguments or comparisons between literals).
+		Field string
+
+		// Kind is the kind of value: "string", "binary", "number", "boolean", "time", "null",
+		// "array", or "value" for other Go types.
+		Kind string
+	}
+)
+
+// ToSQLTyped converts the filter to SQL like ToSQL, but returns parameters annotated with their
+// placeholder, field, and kind so callers can audit, coerce, or log them with field context.
+//
+// Example:
+//
+//	filter, _ := where.Parse("age > 18 AND LOWER(email) = 'a@b.c'")
+//	sql, params, _ := filter.ToSQLTyped("postgres")
+//	// params[0]: {Name: "$1", Value: 18, Field: "age", Kind: "number"}
+//	// params[1]: {Name: "$2", Value: "a@b.c", Field: "email", Kind: "string"}
+func (f *Filter) ToSQLTyped(driverName string, options ...BuildOption) (string, []Param, error) {
+	options = append(options, func(b *SQLBuilder) { b.typed = true })
+
+	builder, sql, err := f.build(driverName, options)
+	if err != nil {
+		return "", nil, err
+	}
+
+	params := builder.typedParams
+	if params == nil {
+		params = make([]Param, 0)
+	}
+	return sql, params, nil
+}
+
+// predicateField returns the field a predicate's values are compared against: the left field, the
+// right field of a reversed comparison, or the first field used within the left value.
+func predicateField(pred *Predicate) string {
+	if pred.Left != nil && pred.Left.Field != nil {
+		return pred.Left.Field.String()
+	}
+	if pred.Operation.Compare != nil && pred.Operation.Compare.Right != nil && pred.Operation.Compare.Right.Field != nil {
+		return pred.Operation.Compare.Right.Field.String()
+	}
+
+	var name string
+	walkValue(pred.Left, func(val *Value) {
+		if name == "" && val.Field != nil {
+			name = val.Field.String()
+		}
+	})
+	return name
+}
+
+// paramKind returns the Param.Kind for a bound value.
+func paramKind(value any) string {
+	switch value.(type) {
+	case time.Time:
+		return "time"
+	case []byte:
+		return "binary"
+	}
+
+	if kind := boundType(value); kind != "value" {
+		return kind
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		return "array"
+	}
+	return "value"
+}
@@ -0,0 +1,69 @@
+package where_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSQLTyped(t *testing.T) {
+	filter := mustParse(t, `age > 18 AND LOWER(email) = 'a@b.c' AND 'x' = name AND
+		created_at >= '2024-01-01' AND status IN ('a', NULL) AND hash = 0xAB AND 1 = 1`)
+
+	sql, params, err := filter.ToSQLTyped("postgres",
+		where.WithTimeParsing(),
+		where.WithBindConstants(),
+		where.WithRequiredFilter(where.Exists("SELECT 1 FROM t WHERE t.v = ?", true)),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "((age > $1 AND LOWER(email) = $2 AND $3 = name AND created_at >= $4 AND "+
+		"status IN ($5, $6) AND hash = $7 AND $8 = $9) AND EXISTS (SELECT 1 FROM t WHERE t.v = $10))", sql)
+
+	require.Equal(t, []where.Param{
+		{Name: "$1", Value: float64(18), Field: "age", Kind: "number"},
+		{Name: "$2", Value: "a@b.c", Field: "email", Kind: "string"},
+		{Name: "$3", Value: "x", Field: "name", Kind: "string"},
+		{Name: "$4", Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Field: "created_at", Kind: "time"},
+		{Name: "$5", Value: "a", Field: "status", Kind: "string"},
+		{Name: "$6", Value: nil, Field: "status", Kind: "null"},
+		{Name: "$7", Value: []byte{0xAB}, Field: "hash", Kind: "binary"},
+		{Name: "$8", Value: float64(1), Kind: "number"},
+		{Name: "$9", Value: float64(1), Kind: "number"},
+		{Name: "$10", Value: true, Kind: "boolean"},
+	}, params)
+}
+
+func TestToSQLTypedMatchesToSQL(t *testing.T) {
+	filter := mustParse(t, "id IN (1, 2, 3) OR (status = 'a' AND id IN (1, 2, 3))")
+	options := []where.BuildOption{where.WithParamDeduplication(), where.WithArrayBinding()}
+
+	sql, values, err := filter.ToSQL("postgres", options...)
+	require.NoError(t, err)
+
+	typedSQL, params, err := filter.ToSQLTyped("postgres", options...)
+	require.NoError(t, err)
+	require.Equal(t, sql, typedSQL)
+	require.Len(t, params, len(values))
+
+	for i, param := range params {
+		require.Equal(t, values[i], param.Value)
+	}
+	require.Equal(t, where.Param{Name: "$1", Value: []float64{1, 2, 3}, Field: "id", Kind: "array"}, params[0])
+}
+
+func TestToSQLTypedMySQL(t *testing.T) {
+	sql, params, err := where.In("user_id", int64(7)).ToSQLTyped("mysql")
+	require.NoError(t, err)
+	require.Equal(t, "user_id IN (?)", sql)
+	require.Equal(t, []where.Param{{Name: "?", Value: int64(7), Field: "user_id", Kind: "number"}}, params)
+
+	_, params, err = mustParse(t, "active = true").ToSQLTyped("mysql")
+	require.NoError(t, err)
+	require.Empty(t, params)
+
+	_, _, err = mustParse(t, "secret = 1").ToSQLTyped("mysql",
+		where.WithValidator(where.NewValidator().AllowFields("id")))
+	require.EqualError(t, err, `field "secret" is not allowed`)
+}
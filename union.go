@@ -0,0 +1,184 @@
+package where
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxUnionBranches is the maximum number of filters UnionBranches produces unless
+// WithMaxUnionBranches is used.
+const DefaultMaxUnionBranches = 32
+
+type (
+	// unionOptions holds configuration options for UnionBranches.
+	unionOptions struct {
+		maxBranches int
+	}
+
+	// UnionOption is a function type for configuring UnionBranches.
+	UnionOption func(*unionOptions)
+)
+
+// WithMaxUnionBranches returns a UnionOption that sets the maximum number of filters UnionBranches
+// may produce, and so the number of queries to run. Zero or a negative value removes the limit.
+func WithMaxUnionBranches(max int) UnionOption {
+	return func(o *unionOptions) {
+		o.maxBranches = max
+	}
+}
+
+// UnionBranches splits a filter into filters that can run as separate queries and be combined with
+// UNION, for databases that cannot use an index for an OR or a large IN list but can for each part,
+// e.g. MySQL with an OR over different indexed columns.
+//
+// A filter with OR at its root is split into its branches. Otherwise the largest OR group or IN list
+// among the top-level AND conditions is split, and each filter keeps the other conditions: an OR
+// group into a filter per branch, and an IN list into at most the maximum number of filters, each
+// with an equal share of the values. Negated groups and NOT IN are never split, and a filter with
+// nothing to split is returned as is. The returned filters share AST nodes with f.
+//
+// Rows can match several branches of an OR, so the results must be combined with UNION rather than
+// UNION ALL, as UnionSQL does. An error is returned if an OR has more branches than
+// DefaultMaxUnionBranches, or the limit set with WithMaxUnionBranches.
+//
+// Example:
+//
+//	filter, _ := where.Parse("status = 'open' AND (owner_id = 7 OR assignee_id = 7)")
+//	filters, _ := filter.UnionBranches()
+//	// status = 'open' AND owner_id = 7
+//	// status = 'open' AND assignee_id = 7
+func (f *Filter) UnionBranches(opts ...UnionOption) ([]*Filter, error) {
+	options := &unionOptions{maxBranches: DefaultMaxUnionBranches}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if f == nil || f.Expression == nil {
+		return nil, nil
+	}
+
+	if len(f.Expression.Or) > 1 {
+		branches := f.Branches()
+		if err := options.checkBranches(len(branches)); err != nil {
+			return nil, err
+		}
+		return branches, nil
+	}
+
+	factors := f.Expression.Or[0].And
+	split, alternatives := -1, 0
+	for i, factor := range factors {
+		if n := splittable(factor); n > alternatives {
+			split, alternatives = i, n
+		}
+	}
+	if split < 0 {
+		return []*Filter{f}, nil
+	}
+
+	var parts [][]*Factor
+	if factor := factors[split]; factor.SubExpr != nil {
+		if err := options.checkBranches(alternatives); err != nil {
+			return nil, err
+		}
+		for _, term := range factor.SubExpr.Or {
+			part := term.And
+			// A parenthesized AND branch, e.g. (a = 1 AND b = 2) OR c = 3, joins the other conditions.
+			if len(part) == 1 && !part[0].Not && part[0].SubExpr != nil && len(part[0].SubExpr.Or) == 1 {
+				part = part[0].SubExpr.Or[0].And
+			}
+			parts = append(parts, part)
+		}
+	} else {
+		parts = options.chunkIn(factor.Predicate)
+	}
+
+	filters := make([]*Filter, len(parts))
+	for i, part := range parts {
+		and := make([]*Factor, 0, len(factors)+len(part)-1)
+		and = append(and, factors[:split]...)
+		and = append(and, part...)
+		and = append(and, factors[split+1:]...)
+		filters[i] = f.withFactors(and)
+	}
+	return filters, nil
+}
+
+// UnionSQL builds each filter into a query that appends it to selectSQL, which must end with WHERE,
+// and combines the queries with UNION. Placeholders are numbered across all of the queries, so the
+// returned parameters can be bound to the statement. selectSQL must not contain ORDER BY or LIMIT,
+// which apply to the whole UNION when appended to the result.
+//
+// Example:
+//
+//	filters, _ := filter.UnionBranches()
+//	sql, params, _ := where.UnionSQL("postgres", "SELECT id FROM tickets WHERE", filters)
+//	// SELECT id FROM tickets WHERE (status = $1 AND owner_id = $2)
+//	// UNION SELECT id FROM tickets WHERE (status = $3 AND assignee_id = $4)
+func UnionSQL(driverName, selectSQL string, filters []*Filter, options ...BuildOption) (string, []any, error) {
+	if len(filters) == 0 {
+		return "", nil, errors.New("no filters to combine")
+	}
+
+	queries := make([]string, len(filters))
+	params := make([]any, 0)
+	for i, filter := range filters {
+		base := len(params)
+		opts := append(options[:len(options):len(options)], func(b *SQLBuilder) { b.paramBase = base })
+
+		builder, sql, err := filter.build(driverName, opts)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "failed to build filter %d", i+1)
+		}
+		queries[i] = selectSQL + " " + sql
+		params = append(params, builder.params...)
+	}
+	return strings.Join(queries, " UNION "), params, nil
+}
+
+// checkBranches returns an error if n exceeds the maximum number of branches.
+func (o *unionOptions) checkBranches(n int) error {
+	if o.maxBranches > 0 && n > o.maxBranches {
+		return fmt.Errorf("filter has %d OR branches, exceeding the maximum of %d", n, o.maxBranches)
+	}
+	return nil
+}
+
+// chunkIn splits an IN predicate into predicates with an equal share of its values, at most the
+// maximum number of branches.
+func (o *unionOptions) chunkIn(pred *Predicate) [][]*Factor {
+	values := pred.Operation.In.Values
+	chunks := len(values)
+	if o.maxBranches > 0 && chunks > o.maxBranches {
+		chunks = o.maxBranches
+	}
+
+	parts := make([][]*Factor, chunks)
+	for i := range parts {
+		chunk := values[i*len(values)/chunks : (i+1)*len(values)/chunks]
+		parts[i] = []*Factor{{Predicate: &Predicate{
+			Pos:       pred.Pos,
+			Left:      pred.Left,
+			Operation: &Operation{In: &InOp{In: pred.Operation.In.In, Values: chunk}},
+		}}}
+	}
+	return parts
+}
+
+// splittable returns the number of alternatives a top-level AND condition can be split into, or 0
+// if it cannot be split.
+func splittable(factor *Factor) int {
+	switch {
+	case factor == nil || factor.Not:
+		return 0
+	case factor.SubExpr != nil && len(factor.SubExpr.Or) > 1:
+		return len(factor.SubExpr.Or)
+	case factor.Predicate != nil && factor.Predicate.Operation != nil:
+		if in := factor.Predicate.Operation.In; in != nil && !in.Not && len(in.Values) > 1 {
+			return len(in.Values)
+		}
+	}
+	return 0
+}
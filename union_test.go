@@ -0,0 +1,161 @@
+package where_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnionBranches(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []where.UnionOption
+		want  []string
+		err   string
+	}{
+		{
+			name:  "root OR",
+			input: "owner_id = 7 OR assignee_id = 7",
+			want:  []string{"owner_id = 7", "assignee_id = 7"},
+		},
+		{
+			name:  "OR group keeps other conditions",
+			input: "status = 'open' AND (owner_id = 7 OR assignee_id = 7) AND archived = FALSE",
+			want: []string{
+				"status = 'open' AND owner_id = 7 AND archived = FALSE",
+				"status = 'open' AND assignee_id = 7 AND archived = FALSE",
+			},
+		},
+		{
+			name:  "OR group of ANDs",
+			input: "tenant_id = 1 AND ((a = 1 AND b = 2) OR c = 3)",
+			want:  []string{"tenant_id = 1 AND a = 1 AND b = 2", "tenant_id = 1 AND c = 3"},
+		},
+		{
+			name:  "IN list",
+			input: "tenant_id = 1 AND id IN (1, 2, 3)",
+			want:  []string{"tenant_id = 1 AND id IN (1)", "tenant_id = 1 AND id IN (2)", "tenant_id = 1 AND id IN (3)"},
+		},
+		{
+			name:  "IN list capped",
+			input: "id IN (1, 2, 3, 4, 5)",
+			opts:  []where.UnionOption{where.WithMaxUnionBranches(2)},
+			want:  []string{"id IN (1, 2)", "id IN (3, 4, 5)"},
+		},
+		{
+			name:  "largest split",
+			input: "(a = 1 OR b = 2) AND id IN (1, 2, 3)",
+			want:  []string{"(a = 1 OR b = 2) AND id IN (1)", "(a = 1 OR b = 2) AND id IN (2)", "(a = 1 OR b = 2) AND id IN (3)"},
+		},
+		{
+			name:  "negated conditions are not split",
+			input: "NOT (a = 1 OR b = 2) AND id NOT IN (1, 2)",
+			want:  []string{"NOT (a = 1 OR b = 2) AND id NOT IN (1, 2)"},
+		},
+		{
+			name:  "nothing to split",
+			input: "a = 1 AND b IN (2)",
+			want:  []string{"a = 1 AND b IN (2)"},
+		},
+		{
+			name:  "too many OR branches",
+			input: "a = 1 OR b = 2 OR c = 3",
+			opts:  []where.UnionOption{where.WithMaxUnionBranches(2)},
+			err:   "filter has 3 OR branches, exceeding the maximum of 2",
+		},
+		{
+			name:  "too many branches in group",
+			input: "x = 1 AND (a = 1 OR b = 2 OR c = 3)",
+			opts:  []where.UnionOption{where.WithMaxUnionBranches(2)},
+			err:   "filter has 3 OR branches, exceeding the maximum of 2",
+		},
+		{
+			name:  "unlimited",
+			input: "a = 1 OR b = 2 OR c = 3",
+			opts:  []where.UnionOption{where.WithMaxUnionBranches(0)},
+			want:  []string{"a = 1", "b = 2", "c = 3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			filters, err := filter.UnionBranches(tt.opts...)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			got := make([]string, len(filters))
+			for i, f := range filters {
+				got[i] = f.String()
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnionBranchesDefaultLimit(t *testing.T) {
+	values := make([]string, 100)
+	for i := range values {
+		values[i] = fmt.Sprint(i)
+	}
+	filter, err := where.Parse("id IN (" + strings.Join(values, ", ") + ")")
+	require.NoError(t, err)
+
+	filters, err := filter.UnionBranches()
+	require.NoError(t, err)
+	require.Len(t, filters, where.DefaultMaxUnionBranches)
+
+	count := 0
+	for _, f := range filters {
+		_, params, err := f.ToSQL("postgres")
+		require.NoError(t, err)
+		count += len(params)
+	}
+	require.Equal(t, 100, count)
+}
+
+func TestUnionSQL(t *testing.T) {
+	filter, err := where.Parse("status = 'open' AND (owner_id = 7 OR assignee_id = 7)")
+	require.NoError(t, err)
+
+	filters, err := filter.UnionBranches()
+	require.NoError(t, err)
+
+	sql, params, err := where.UnionSQL("postgres", "SELECT id FROM tickets WHERE", filters)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT id FROM tickets WHERE (status = $1 AND owner_id = $2)"+
+		" UNION SELECT id FROM tickets WHERE (status = $3 AND assignee_id = $4)", sql)
+	require.Equal(t, []any{"open", float64(7), "open", float64(7)}, params)
+
+	sql, params, err = where.UnionSQL("mysql", "SELECT id FROM tickets WHERE", filters, where.WithParamDeduplication())
+	require.NoError(t, err)
+	require.Equal(t, "SELECT id FROM tickets WHERE (status = ? AND owner_id = ?)"+
+		" UNION SELECT id FROM tickets WHERE (status = ? AND assignee_id = ?)", sql)
+	require.Len(t, params, 4)
+}
+
+func TestUnionSQLErrors(t *testing.T) {
+	_, _, err := where.UnionSQL("postgres", "SELECT 1 WHERE", nil)
+	require.EqualError(t, err, "no filters to combine")
+
+	filter, err := where.Parse("id IN (1, 2, 3, 4)")
+	require.NoError(t, err)
+	filters, err := filter.UnionBranches()
+	require.NoError(t, err)
+
+	_, _, err = where.UnionSQL("postgres", "SELECT id FROM t WHERE", filters, where.WithMaxParams(3))
+	require.EqualError(t, err, "failed to build filter 4: filter requires 4 parameters, exceeding the maximum of 3 for driver postgres")
+
+	validator := where.NewValidator().AllowFields("name")
+	_, _, err = where.UnionSQL("postgres", "SELECT id FROM t WHERE", filters, where.WithValidator(validator))
+	require.EqualError(t, err, `failed to build filter 1: field "id" is not allowed`)
+}
@@ -0,0 +1,23 @@
+package where
+
+// Validate runs the checks ToSQL runs for the driver, such as the validator's field, function, and
+// value restrictions, required fields, and the driver's support for the operators and functions
+// used, without returning SQL. Request middleware can use it to reject a filter before the query is
+// built. The validator may be nil to only check the driver, and options such as WithVariables and
+// WithFieldTypes should match those used to build the query, since they affect the checks; a filter
+// using a variable without a value fails. Rejections are reported to the validator's OnRejection
+// handler as in ToSQL.
+//
+// Example:
+//
+//	filter, _ := where.Parse("email ILIKE '%@example.com' AND password = 'x'")
+//	err := filter.Validate("mysql", where.NewValidator().AllowFields("email"))
+//	// field "password" is not allowed
+func (f *Filter) Validate(driverName string, v *Validator, options ...BuildOption) error {
+	if v != nil {
+		options = append(options, WithValidator(v))
+	}
+
+	_, _, err := f.build(driverName, options)
+	return err
+}
@@ -0,0 +1,118 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+type restrictedDriver struct {
+	*MockDriver
+}
+
+func (d restrictedDriver) SupportsFunction(name string) bool { return name == "LOWER" }
+
+func TestFilterValidate(t *testing.T) {
+	where.RegisterDriver("restricted", restrictedDriver{&MockDriver{name: "restricted"}})
+
+	validator := func() *where.Validator {
+		return where.NewValidator().
+			AllowFields("name", "email", "age", "created_at", "id").
+			AllowFunctions("LOWER", "JSONB_EXTRACT_PATH", "YEAR")
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		driver    string
+		validator *where.Validator
+		options   []where.BuildOption
+		err       string
+	}{
+		{
+			name:      "valid",
+			input:     "LOWER(name) = 'bob' AND age > 18",
+			driver:    "postgres",
+			validator: validator(),
+		},
+		{
+			name:      "field not allowed",
+			input:     "name = 'bob' AND password = 'x'",
+			driver:    "postgres",
+			validator: validator(),
+			err:       `field "password" is not allowed`,
+		},
+		{
+			name:      "function not allowed",
+			input:     "UPPER(name) = 'BOB'",
+			driver:    "postgres",
+			validator: validator(),
+			err:       `function "UPPER" is not allowed`,
+		},
+		{
+			name:   "operator not supported by driver",
+			input:  "id <=> 1",
+			driver: "clickhouse",
+			err:    "operator <=> not supported by driver clickhouse",
+		},
+		{
+			name:   "function not supported by driver",
+			input:  "UPPER(name) = 'BOB'",
+			driver: "restricted",
+			err:    `function "UPPER" not supported by driver restricted`,
+		},
+		{
+			name:   "unknown driver",
+			input:  "age > 18",
+			driver: "nonexistent",
+			err:    `failed to get driver "nonexistent": driver "nonexistent" not registered`,
+		},
+		{
+			name:   "missing variable",
+			input:  "created_at > :since",
+			driver: "postgres",
+			err:    "missing value for variable :since",
+		},
+		{
+			name:    "variable supplied",
+			input:   "created_at > :since",
+			driver:  "postgres",
+			options: []where.BuildOption{where.WithVariables(map[string]any{"since": "2024-01-01"})},
+		},
+		{
+			name:    "field type",
+			input:   "id = 'not-a-uuid'",
+			driver:  "postgres",
+			options: []where.BuildOption{where.WithFieldTypes(map[string]where.FieldType{"id": where.FieldTypeUUID})},
+			err:     `invalid UUID "not-a-uuid" for field "id"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			err = filter.Validate(tt.driver, tt.validator, tt.options...)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestFilterValidateRejection(t *testing.T) {
+	var rejected []where.RejectionMeta
+	validator := where.NewValidator().AllowFields("name").OnRejection(func(_ string, _ error, meta where.RejectionMeta) {
+		rejected = append(rejected, meta)
+	})
+
+	filter, err := where.Parse("secret = 'x'")
+	require.NoError(t, err)
+
+	require.Error(t, filter.Validate("postgres", validator))
+	require.Equal(t, []where.RejectionMeta{{Rule: where.RuleField, Field: "secret"}}, rejected)
+}
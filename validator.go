@@ -1,43 +1,99 @@
 package where
 
 import (
+	"fmt"
+	"path"
+	"regexp"
 	"strings"
 )
 
-// Validator provides field and function allowlisting for security.
-// It can be used to restrict which fields and functions are allowed in filter expressions.
-type Validator struct {
-	allowedFields    map[string]bool
-	allowedFunctions map[string]bool
-	allowAll         bool
-}
+type (
+	// Validator provides field and function allowlisting for security.
+	// It can be used to restrict which fields and functions are allowed in filter expressions.
+	//
+	// Rules are evaluated with the following precedence:
+	//
+	//  1. Denied names and deny patterns always win, even when AllowAll is set.
+	//  2. AllowAll permits anything that was not denied.
+	//  3. Exact allowed names are checked before allow patterns.
+	//  4. Anything not matched by an allow rule is denied.
+	Validator struct {
+		allowedFields    map[string][]string
+		columns          map[string]string
+		allowedFunctions map[string]bool
+		deniedFields     map[string]bool
+		deniedFunctions  map[string]bool
+		fieldRules       []matchRule
+		functionRules    []matchRule
+		constraints      map[string]FieldConstraint
+		requirements     []requirement
+		onReject         RejectionHandler
+		allowAll         bool
+		caseSensitive    bool
+
+		maxIdentifierLength int
+		identifierPattern   *regexp.Regexp
+	}
+
+	// matchRule is a pattern based allow or deny rule for field or function names.
+	matchRule struct {
+		deny  bool
+		match func(name string) bool
+	}
+)
 
 // NewValidator creates a new validator with empty allowlists.
 // By default, all fields and functions are denied unless explicitly allowed.
 func NewValidator() *Validator {
 	return &Validator{
-		allowedFields:    make(map[string]bool),
+		allowedFields:    make(map[string][]string),
 		allowedFunctions: make(map[string]bool),
+		deniedFields:     make(map[string]bool),
+		deniedFunctions:  make(map[string]bool),
 		allowAll:         false,
 	}
 }
 
 // AllowAll configures the validator to allow all fields and functions.
 // This disables security restrictions and should be used with caution.
+// Explicit deny rules are still honored.
 func (v *Validator) AllowAll() *Validator {
 	v.allowAll = true
 	return v
 }
 
 // AllowFields adds the specified fields to the allowlist.
-// Field names are case-insensitive.
+// Field names are case-insensitive unless CaseSensitiveFields is set. Either way, the SQL builder
+// writes an allowed field with the case given here rather than the case used in the filter.
 func (v *Validator) AllowFields(fields ...string) *Validator {
 	for _, field := range fields {
-		v.allowedFields[strings.ToLower(field)] = true
+		key := strings.ToLower(field)
+		v.allowedFields[key] = append(v.allowedFields[key], field)
 	}
 	return v
 }
 
+// MapField adds a field to the allowlist that is written as a different column in the SQL, so the
+// names used in filters can differ from the schema, e.g. MapField("createdAt", "created_at").
+func (v *Validator) MapField(name, column string) *Validator {
+	v.AllowFields(name)
+	if v.columns == nil {
+		v.columns = make(map[string]string)
+	}
+	v.columns[name] = column
+	return v
+}
+
+// CaseSensitiveFields makes allowed field names case-sensitive, so that a filter must spell a field
+// exactly as it was allowed. Use it for databases where quoted columns such as "CreatedAt" and
+// createdat are distinct, e.g. PostgreSQL; drivers implementing CaseFolder then quote mixed-case
+// fields so the database does not fold them. Denied fields and field patterns remain
+// case-insensitive.
+func (v *Validator) CaseSensitiveFields() *Validator {
+	v.caseSensitive = true
+	return v
+}
+
 // AllowFunctions adds the specified functions to the allowlist.
 // Function names are case-insensitive.
 func (v *Validator) AllowFunctions(functions ...string) *Validator {
@@ -47,18 +103,160 @@ func (v *Validator) AllowFunctions(functions ...string) *Validator {
 	return v
 }
 
-// IsFieldAllowed returns true if the field is allowed by this validator.
-func (v *Validator) IsFieldAllowed(field string) bool {
-	if v.allowAll {
-		return true
+// DenyFields adds the specified fields to the denylist.
+// Denied fields are rejected even if they match an allow rule. Field names are case-insensitive.
+func (v *Validator) DenyFields(fields ...string) *Validator {
+	for _, field := range fields {
+		v.deniedFields[strings.ToLower(field)] = true
+	}
+	return v
+}
+
+// DenyFunctions adds the specified functions to the denylist.
+// Denied functions are rejected even if they match an allow rule. Function names are case-insensitive.
+func (v *Validator) DenyFunctions(functions ...string) *Validator {
+	for _, fn := range functions {
+		v.deniedFunctions[strings.ToUpper(fn)] = true
+	}
+	return v
+}
+
+// AllowFieldPattern allows all fields matching the given glob patterns (e.g. "metrics.*").
+// Patterns use path.Match syntax and are matched case-insensitively. Panics if a pattern is malformed.
+func (v *Validator) AllowFieldPattern(patterns ...string) *Validator {
+	for _, pattern := range patterns {
+		v.fieldRules = append(v.fieldRules, globRule(strings.ToLower(pattern), strings.ToLower, false))
+	}
+	return v
+}
+
+// DenyFieldPattern denies all fields matching the given glob patterns.
+// Patterns use path.Match syntax and are matched case-insensitively. Panics if a pattern is malformed.
+func (v *Validator) DenyFieldPattern(patterns ...string) *Validator {
+	for _, pattern := range patterns {
+		v.fieldRules = append(v.fieldRules, globRule(strings.ToLower(pattern), strings.ToLower, true))
+	}
+	return v
+}
+
+// AllowFieldRegexp allows all fields matching the given regular expression.
+// The expression is matched against the field name as it appears in the filter, without quotes.
+func (v *Validator) AllowFieldRegexp(re *regexp.Regexp) *Validator {
+	v.fieldRules = append(v.fieldRules, matchRule{match: re.MatchString})
+	return v
+}
+
+// DenyFieldRegexp denies all fields matching the given regular expression.
+// The expression is matched against the field name as it appears in the filter, without quotes.
+func (v *Validator) DenyFieldRegexp(re *regexp.Regexp) *Validator {
+	v.fieldRules = append(v.fieldRules, matchRule{deny: true, match: re.MatchString})
+	return v
+}
+
+// AllowFunctionPattern allows all functions matching the given glob patterns (e.g. "JSON_*").
+// Patterns use path.Match syntax and are matched case-insensitively. Panics if a pattern is malformed.
+func (v *Validator) AllowFunctionPattern(patterns ...string) *Validator {
+	for _, pattern := range patterns {
+		v.functionRules = append(v.functionRules, globRule(strings.ToUpper(pattern), strings.ToUpper, false))
 	}
-	return v.allowedFields[strings.ToLower(field)]
+	return v
+}
+
+// DenyFunctionPattern denies all functions matching the given glob patterns.
+// Patterns use path.Match syntax and are matched case-insensitively. Panics if a pattern is malformed.
+func (v *Validator) DenyFunctionPattern(patterns ...string) *Validator {
+	for _, pattern := range patterns {
+		v.functionRules = append(v.functionRules, globRule(strings.ToUpper(pattern), strings.ToUpper, true))
+	}
+	return v
+}
+
+// IsFieldAllowed returns true if the field is allowed by this validator. Quotes around the parts of
+// the field are ignored, so `users`."email" is checked as users.email.
+func (v *Validator) IsFieldAllowed(field string) bool {
+	field = fieldName(field)
+	_, listed := v.allowedField(field)
+	return v.isAllowed(field, strings.ToLower(field), listed, v.deniedFields, v.fieldRules)
 }
 
 // IsFunctionAllowed returns true if the function is allowed by this validator.
 func (v *Validator) IsFunctionAllowed(function string) bool {
-	if v.allowAll {
+	key := strings.ToUpper(function)
+	return v.isAllowed(function, key, v.allowedFunctions[key], v.deniedFunctions, v.functionRules)
+}
+
+// allowedField returns the field name as it was passed to AllowFields, preferring an exact match,
+// and whether the field is in the allowlist.
+func (v *Validator) allowedField(field string) (string, bool) {
+	field = fieldName(field)
+	names := v.allowedFields[strings.ToLower(field)]
+	for _, name := range names {
+		if name == field {
+			return name, true
+		}
+	}
+	if len(names) == 0 || v.caseSensitive {
+		return "", false
+	}
+	return names[0], true
+}
+
+// fieldName returns the field with the backticks or double quotes removed from each of its parts,
+// so that quoting a field does not change which rules apply to it.
+func fieldName(field string) string {
+	var parts []string
+	var quote rune
+	start := 0
+	for i, ch := range field {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '`' || ch == '"':
+			quote = ch
+		case ch == '.':
+			parts = append(parts, unquoteIdentifier(field[start:i]))
+			start = i + 1
+		}
+	}
+	return strings.Join(append(parts, unquoteIdentifier(field[start:])), ".")
+}
+
+func (v *Validator) isAllowed(raw, key string, listed bool, denied map[string]bool, rules []matchRule) bool {
+	if denied[key] {
+		return false
+	}
+
+	for _, rule := range rules {
+		if rule.deny && rule.match(raw) {
+			return false
+		}
+	}
+
+	if v.allowAll || listed {
 		return true
 	}
-	return v.allowedFunctions[strings.ToUpper(function)]
+
+	for _, rule := range rules {
+		if !rule.deny && rule.match(raw) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func globRule(pattern string, normalize func(string) string, deny bool) matchRule {
+	if _, err := path.Match(pattern, ""); err != nil {
+		panic(fmt.Sprintf("where: invalid pattern %q: %v", pattern, err))
+	}
+
+	return matchRule{
+		deny: deny,
+		match: func(name string) bool {
+			ok, _ := path.Match(pattern, normalize(name))
+			return ok
+		},
+	}
 }
@@ -1,6 +1,7 @@
 package where_test
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/pseudomuto/where"
@@ -246,3 +247,217 @@ func TestValidatorIntegrationWithFilters(t *testing.T) {
 		require.Contains(t, err.Error(), "function \"LENGTH\" is not allowed")
 	})
 }
+
+func TestValidatorDenyLists(t *testing.T) {
+	validator := where.NewValidator().
+		AllowFields("name", "password").
+		AllowFunctions("LOWER", "SLEEP").
+		DenyFields("Password").
+		DenyFunctions("sleep")
+
+	require.True(t, validator.IsFieldAllowed("name"))
+	require.False(t, validator.IsFieldAllowed("password"))
+	require.False(t, validator.IsFieldAllowed("PASSWORD"))
+	require.True(t, validator.IsFunctionAllowed("lower"))
+	require.False(t, validator.IsFunctionAllowed("SLEEP"))
+
+	// Deny wins over AllowAll
+	validator.AllowAll()
+	require.True(t, validator.IsFieldAllowed("anything"))
+	require.False(t, validator.IsFieldAllowed("password"))
+	require.False(t, validator.IsFunctionAllowed("sleep"))
+}
+
+func TestValidatorQuotedFields(t *testing.T) {
+	validator := where.NewValidator().
+		AllowAll().
+		DenyFields("secret", "users.password").
+		DenyFieldPattern("internal_*")
+
+	require.False(t, validator.IsFieldAllowed("`secret`"))
+	require.False(t, validator.IsFieldAllowed(`"SECRET"`))
+	require.False(t, validator.IsFieldAllowed("`users`.\"password\""))
+	require.False(t, validator.IsFieldAllowed("`internal_x`"))
+	require.True(t, validator.IsFieldAllowed("`name`"))
+
+	tests := []struct {
+		driver string
+		input  string
+	}{
+		{"mysql", "`secret` = 1"},
+		{"postgres", `"secret" = 1`},
+		{"postgres", "`Secret` = 1"},
+		{"mysql", "`internal_x` = 1"},
+		{"postgres", "users.`password` = 'x'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver+" "+tt.input, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			_, _, err = filter.ToSQL(tt.driver, where.WithValidator(validator))
+			require.ErrorContains(t, err, "is not allowed")
+		})
+	}
+
+	t.Run("allowed names", func(t *testing.T) {
+		validator := where.NewValidator().AllowFields("name").AllowFieldPattern("metrics.*")
+
+		sql, _, err := mustParse(t, "`name` = 'x' AND \"metrics\".`cpu` > 1").
+			ToSQL("postgres", where.WithValidator(validator))
+		require.NoError(t, err)
+		require.Equal(t, "(name = $1 AND metrics.cpu > $2)", sql)
+	})
+}
+
+func TestValidatorPatterns(t *testing.T) {
+	t.Run("glob field patterns", func(t *testing.T) {
+		validator := where.NewValidator().
+			AllowFieldPattern("metrics.*").
+			DenyFieldPattern("metrics.internal_*")
+
+		require.True(t, validator.IsFieldAllowed("metrics.cpu"))
+		require.True(t, validator.IsFieldAllowed("Metrics.Memory"))
+		require.False(t, validator.IsFieldAllowed("metrics.internal_cost"))
+		require.False(t, validator.IsFieldAllowed("users.name"))
+		require.False(t, validator.IsFieldAllowed("metrics"))
+	})
+
+	t.Run("deny pattern wins over exact allow", func(t *testing.T) {
+		validator := where.NewValidator().
+			AllowFields("secret_key").
+			DenyFieldPattern("secret_*")
+
+		require.False(t, validator.IsFieldAllowed("secret_key"))
+	})
+
+	t.Run("regexp field patterns", func(t *testing.T) {
+		validator := where.NewValidator().
+			AllowFieldRegexp(regexp.MustCompile(`^attr_[0-9]+$`)).
+			DenyFieldRegexp(regexp.MustCompile(`^attr_0$`))
+
+		require.True(t, validator.IsFieldAllowed("attr_12"))
+		require.False(t, validator.IsFieldAllowed("attr_0"))
+		require.False(t, validator.IsFieldAllowed("attr_x"))
+	})
+
+	t.Run("glob function patterns", func(t *testing.T) {
+		validator := where.NewValidator().
+			AllowFunctionPattern("json_*").
+			DenyFunctionPattern("JSON_SET")
+
+		require.True(t, validator.IsFunctionAllowed("JSON_EXTRACT"))
+		require.True(t, validator.IsFunctionAllowed("json_contains"))
+		require.False(t, validator.IsFunctionAllowed("json_set"))
+		require.False(t, validator.IsFunctionAllowed("LOWER"))
+	})
+
+	t.Run("invalid pattern panics", func(t *testing.T) {
+		require.Panics(t, func() {
+			where.NewValidator().AllowFieldPattern("[")
+		})
+	})
+
+	t.Run("with ToSQL", func(t *testing.T) {
+		filter, err := where.Parse("metrics.cpu > 90 AND metrics.internal_cost > 1")
+		require.NoError(t, err)
+
+		validator := where.NewValidator().
+			AllowFieldPattern("metrics.*").
+			DenyFieldPattern("metrics.internal_*")
+
+		_, _, err = filter.ToSQL("postgres", where.WithValidator(validator))
+		require.EqualError(t, err, `field "metrics.internal_cost" is not allowed`)
+	})
+}
+
+func TestValidatorCaseSensitiveFields(t *testing.T) {
+	validator := where.NewValidator().
+		CaseSensitiveFields().
+		AllowFields("CreatedAt", "createdat", "users.Email").
+		DenyFields("Password")
+
+	require.True(t, validator.IsFieldAllowed("CreatedAt"))
+	require.True(t, validator.IsFieldAllowed("createdat"))
+	require.True(t, validator.IsFieldAllowed("users.Email"))
+	require.False(t, validator.IsFieldAllowed("CREATEDAT"))
+	require.False(t, validator.IsFieldAllowed("users.email"))
+	require.False(t, validator.IsFieldAllowed("password"))
+}
+
+func TestValidatorFieldCase(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    string
+		driver    string
+		validator *where.Validator
+		wantSQL   string
+		wantErr   string
+	}{
+		{
+			name:      "allowlist case is used",
+			filter:    "createdat > 1 AND USERS.EMAIL = 'x'",
+			driver:    "clickhouse",
+			validator: where.NewValidator().AllowFields("CreatedAt", "users.Email"),
+			wantSQL:   "(CreatedAt > ? AND users.Email = ?)",
+		},
+		{
+			name:      "exact match is preferred",
+			filter:    "createdat > 1",
+			driver:    "clickhouse",
+			validator: where.NewValidator().AllowFields("CreatedAt", "createdat"),
+			wantSQL:   "createdat > ?",
+		},
+		{
+			name:      "postgres folds unquoted identifiers",
+			filter:    "createdat > 1",
+			driver:    "postgres",
+			validator: where.NewValidator().AllowFields("CreatedAt"),
+			wantSQL:   "CreatedAt > $1",
+		},
+		{
+			name:      "case-sensitive fields are quoted exactly",
+			filter:    "CreatedAt > 1 AND createdat < 5 AND users.Email = 'x'",
+			driver:    "postgres",
+			validator: where.NewValidator().CaseSensitiveFields().AllowFields("CreatedAt", "createdat", "users.Email"),
+			wantSQL:   `("CreatedAt" > $1 AND createdat < $2 AND users."Email" = $3)`,
+		},
+		{
+			name:      "case-sensitive fields reject other spellings",
+			filter:    "createdAt > 1",
+			driver:    "postgres",
+			validator: where.NewValidator().CaseSensitiveFields().AllowFields("CreatedAt"),
+			wantErr:   `field "createdAt" is not allowed; did you mean "CreatedAt"?`,
+		},
+		{
+			name:      "other databases keep case without quoting",
+			filter:    "CreatedAt > 1",
+			driver:    "clickhouse",
+			validator: where.NewValidator().CaseSensitiveFields().AllowFields("CreatedAt"),
+			wantSQL:   "CreatedAt > ?",
+		},
+		{
+			name:      "mapped fields are written as their columns",
+			filter:    "CREATEDAT > 1 AND owner = 'x'",
+			driver:    "postgres",
+			validator: where.NewValidator().MapField("createdAt", "created_at").MapField("owner", "users.email"),
+			wantSQL:   "(created_at > $1 AND users.email = $2)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.filter)
+			require.NoError(t, err)
+
+			sql, _, err := filter.ToSQL(tt.driver, where.WithValidator(tt.validator))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+		})
+	}
+}
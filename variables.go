@@ -0,0 +1,90 @@
+package where
+
+import "strings"
+
+// WithVariables returns a BuildOption that supplies the values of the :name variables in a filter
+// template, e.g. "created_at > :since AND status IN (:statuses)". Names are given without the colon.
+// Values are bound as parameters without conversion, except that field types from WithFieldTypes
+// still apply, and a slice bound to a variable in an IN list is expanded into its elements. Building
+// a filter that uses a variable without a value fails.
+//
+// Example:
+//
+//	filter, _ := where.Parse("created_at > :since AND status IN (:statuses)")
+//	sql, params, _ := filter.ToSQL("postgres", where.WithVariables(map[string]any{
+//		"since":    time.Now().Add(-24 * time.Hour),
+//		"statuses": []string{"active", "pending"},
+//	}))
+//	// (created_at > $1 AND status IN ($2, $3))
+func WithVariables(vars map[string]any) BuildOption {
+	return func(b *SQLBuilder) {
+		if b.variables == nil {
+			b.variables = make(map[string]any, len(vars))
+		}
+		for name, value := range vars {
+			b.variables[strings.TrimPrefix(name, ":")] = value
+		}
+	}
+}
+
+// Variables returns the names of the variables used in the filter, without the colon, in the order
+// they first appear.
+func (f *Filter) Variables() []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	if f != nil {
+		walkValues(f.Expression, func(val *Value) {
+			if val.Literal == nil || val.Literal.Variable == nil {
+				return
+			}
+			name := val.Literal.variableName()
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		})
+	}
+	return names
+}
+
+// variableName returns the name of a variable literal without the colon.
+func (l *LiteralValue) variableName() string {
+	return strings.TrimPrefix(*l.Variable, ":")
+}
+
+// variable returns the value supplied for a variable literal.
+func (b *SQLBuilder) variable(lit *LiteralValue) (any, error) {
+	value, ok := b.variables[lit.variableName()]
+	if !ok {
+		return nil, newMessage(MsgMissingVariable, "variable", *lit.Variable)
+	}
+	return value, nil
+}
+
+// resolveVariables returns the IN operation with its variables replaced by their values, expanding
+// slices into separate items. The operation is returned unchanged if it has no variables.
+func (b *SQLBuilder) resolveVariables(in *InOp) (*InOp, error) {
+	resolved := in
+	for i, item := range in.Values {
+		if item == nil || item.Literal == nil || item.Literal.Variable == nil {
+			if resolved != in {
+				resolved.Values = append(resolved.Values, item)
+			}
+			continue
+		}
+
+		if resolved == in {
+			resolved = &InOp{Not: in.Not, In: in.In, Values: append([]*Value(nil), in.Values[:i]...)}
+		}
+
+		value, err := b.variable(item.Literal)
+		if err != nil {
+			return nil, err
+		}
+		for _, elem := range expandValues([]any{value}) {
+			resolved.Values = append(resolved.Values, &Value{Literal: &LiteralValue{bound: true, param: elem}})
+		}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,127 @@
+package where_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariables(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   string
+		driver   string
+		vars     map[string]any
+		options  []where.BuildOption
+		wantSQL  string
+		wantArgs []any
+		wantErr  string
+	}{
+		{
+			name:     "scalar variables",
+			filter:   "created_at > :since AND status = :status",
+			driver:   "postgres",
+			vars:     map[string]any{"since": since, "status": "active"},
+			wantSQL:  "(created_at > $1 AND status = $2)",
+			wantArgs: []any{since, "active"},
+		},
+		{
+			name:     "names with a colon",
+			filter:   "age >= :min_age",
+			driver:   "mysql",
+			vars:     map[string]any{":min_age": 18},
+			wantSQL:  "age >= ?",
+			wantArgs: []any{18},
+		},
+		{
+			name:     "slices are expanded in IN lists",
+			filter:   "status IN (:statuses, 'archived') AND id NOT IN (:ids)",
+			driver:   "postgres",
+			vars:     map[string]any{"statuses": []string{"active", "pending"}, "ids": []int64{1, 2}},
+			wantSQL:  "(status IN ($1, $2, $3) AND id NOT IN ($4, $5))",
+			wantArgs: []any{"active", "pending", "archived", int64(1), int64(2)},
+		},
+		{
+			name:     "empty slice",
+			filter:   "id IN (:ids)",
+			driver:   "postgres",
+			vars:     map[string]any{"ids": []int{}},
+			wantSQL:  "FALSE",
+			wantArgs: []any{},
+		},
+		{
+			name:     "BETWEEN, LIKE, and function arguments",
+			filter:   "age BETWEEN :lo AND :hi AND name LIKE :pattern AND ROUND(score, :digits) > 1",
+			driver:   "clickhouse",
+			vars:     map[string]any{"lo": 18, "hi": 65, "pattern": "J%", "digits": 2},
+			wantSQL:  "(age BETWEEN ? AND ? AND name LIKE ? AND ROUND(score, ?) > ?)",
+			wantArgs: []any{18, 65, "J%", 2, float64(1)},
+		},
+		{
+			name:     "field types apply",
+			filter:   "id = :id",
+			driver:   "postgres",
+			vars:     map[string]any{"id": "550E8400-E29B-41D4-A716-446655440000"},
+			options:  []where.BuildOption{where.WithFieldTypes(map[string]where.FieldType{"id": where.FieldTypeUUID})},
+			wantSQL:  "id = $1",
+			wantArgs: []any{"550e8400-e29b-41d4-a716-446655440000"},
+		},
+		{
+			name:    "missing variable",
+			filter:  "status = :status",
+			driver:  "postgres",
+			wantErr: "missing value for variable :status",
+		},
+		{
+			name:    "missing variable in IN list",
+			filter:  "status IN ('a', :rest)",
+			driver:  "postgres",
+			wantErr: "missing value for variable :rest",
+		},
+		{
+			name:   "constraints apply to values",
+			filter: "status IN (:statuses)",
+			driver: "postgres",
+			vars:   map[string]any{"statuses": []string{"active", "deleted"}},
+			options: []where.BuildOption{where.WithValidator(where.NewValidator().
+				AllowFields("status").
+				ConstrainField("status", where.FieldConstraint{AllowedValues: []any{"active", "pending"}}))},
+			wantErr: `value deleted is not allowed for field "status"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := mustParse(t, tt.filter)
+
+			options := append([]where.BuildOption{where.WithVariables(tt.vars)}, tt.options...)
+			sql, args, err := filter.ToSQL(tt.driver, options...)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestVariablesFormatAndList(t *testing.T) {
+	filter := mustParse(t, "created_at>:since and (status=:status or owner IN (:owners, :status))")
+	require.Equal(t, "created_at > :since AND (status = :status OR owner IN (:owners, :status))", filter.String())
+	require.Equal(t, []string{"since", "status", "owners"}, filter.Variables())
+	require.Equal(t, filter.String(), filter.Clone().String())
+	require.Empty(t, mustParse(t, "a = 1").Variables())
+}
+
+func TestVariablesSyntax(t *testing.T) {
+	for _, input := range []string{"a = :", "a = :1x", "a = : b", ":a"} {
+		_, err := where.Parse(input)
+		require.Error(t, err, input)
+	}
+}
@@ -0,0 +1,89 @@
+package where
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Version is a database server version, e.g. 8.0.21. The zero Version means the version is unknown,
+// which drivers and translations treat as the newest version.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses a version such as "8", "16.2", or "8.0.21". Anything following the leading
+// numbers is ignored, so the output of SELECT VERSION() can be parsed, e.g. "8.0.36-log" or
+// "16.2 (Debian 16.2-1)".
+func ParseVersion(s string) (Version, error) {
+	var parts [3]int
+	rest := strings.TrimSpace(s)
+	for i := range parts {
+		end := 0
+		for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			if i == 0 {
+				return Version{}, errors.Errorf("invalid version %q", s)
+			}
+			break
+		}
+
+		n, err := strconv.Atoi(rest[:end])
+		if err != nil {
+			return Version{}, errors.Wrapf(err, "invalid version %q", s)
+		}
+		parts[i] = n
+
+		rest = rest[end:]
+		if !strings.HasPrefix(rest, ".") {
+			break
+		}
+		rest = rest[1:]
+	}
+
+	return Version{Major: parts[0], Minor: parts[1], Patch: parts[2]}, nil
+}
+
+// IsZero returns true if the version is unknown.
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+// Compare returns -1, 0, or 1 when v is older than, the same as, or newer than other.
+func (v Version) Compare(other Version) int {
+	switch {
+	case v.Major != other.Major:
+		return cmp.Compare(v.Major, other.Major)
+	case v.Minor != other.Minor:
+		return cmp.Compare(v.Minor, other.Minor)
+	default:
+		return cmp.Compare(v.Patch, other.Patch)
+	}
+}
+
+// AtLeast returns true if v is the same as or newer than minVersion. An unknown version is the
+// newest, so it is at least any version.
+func (v Version) AtLeast(minVersion Version) bool {
+	return v.IsZero() || v.Compare(minVersion) >= 0
+}
+
+// String returns the version as major.minor.patch.
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// DriverVersion returns the server version targeted by the driver, or the zero Version if the driver
+// does not implement Versioner.
+func DriverVersion(driver Driver) Version {
+	if versioner, ok := DriverAs[Versioner](driver); ok {
+		return versioner.Version()
+	}
+	return Version{}
+}
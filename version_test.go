@@ -0,0 +1,66 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  where.Version
+		err   string
+	}{
+		{input: "8", want: where.Version{Major: 8}},
+		{input: "16.2", want: where.Version{Major: 16, Minor: 2}},
+		{input: "8.0.21", want: where.Version{Major: 8, Patch: 21}},
+		{input: " 8.0.36-log", want: where.Version{Major: 8, Patch: 36}},
+		{input: "16.2 (Debian 16.2-1.pgdg120+2)", want: where.Version{Major: 16, Minor: 2}},
+		{input: "5.7.", want: where.Version{Major: 5, Minor: 7}},
+		{input: "", err: `invalid version ""`},
+		{input: "v8.0", err: `invalid version "v8.0"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := where.ParseVersion(tt.input)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	v57 := where.Version{Major: 5, Minor: 7}
+	v80 := where.Version{Major: 8}
+	v8021 := where.Version{Major: 8, Patch: 21}
+
+	require.Equal(t, -1, v57.Compare(v80))
+	require.Equal(t, 1, v8021.Compare(v80))
+	require.Equal(t, 0, v80.Compare(where.Version{Major: 8}))
+
+	require.True(t, v8021.AtLeast(v80))
+	require.False(t, v57.AtLeast(v80))
+	require.True(t, where.Version{}.AtLeast(v8021), "unknown versions are the newest")
+
+	require.True(t, where.Version{}.IsZero())
+	require.False(t, v57.IsZero())
+	require.Equal(t, "8.0.21", v8021.String())
+}
+
+func TestDriverVersion(t *testing.T) {
+	pg, err := where.GetDriver("postgres")
+	require.NoError(t, err)
+	require.True(t, where.DriverVersion(pg).IsZero())
+	require.True(t, where.DriverVersion(&MockDriver{name: "mock"}).IsZero())
+
+	driver := versionedDriver{MockDriver: &MockDriver{name: "mock"}, version: where.Version{Major: 5, Minor: 7}}
+	require.Equal(t, where.Version{Major: 5, Minor: 7}, where.DriverVersion(driver))
+	require.Equal(t, where.Version{Major: 5, Minor: 7}, where.DriverVersion(where.DriverWrapper{Driver: driver}))
+}
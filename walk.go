@@ -0,0 +1,58 @@
+package where
+
+// walkValues calls fn for every value in the expression, including function arguments
+// and values within nested expressions.
+func walkValues(expr *Expression, fn func(*Value)) {
+	if expr == nil {
+		return
+	}
+
+	for _, term := range expr.Or {
+		if term == nil {
+			continue
+		}
+		for _, factor := range term.And {
+			if factor == nil {
+				continue
+			}
+			if factor.SubExpr != nil {
+				walkValues(factor.SubExpr, fn)
+			}
+			if factor.Predicate != nil {
+				walkPredicateValues(factor.Predicate, fn)
+			}
+		}
+	}
+}
+
+func walkPredicateValues(pred *Predicate, fn func(*Value)) {
+	walkValue(pred.Left, fn)
+	if pred.Operation == nil {
+		return
+	}
+	for _, val := range pred.Operation.operands() {
+		walkValue(val, fn)
+	}
+}
+
+func walkValue(val *Value, fn func(*Value)) {
+	if val == nil {
+		return
+	}
+
+	fn(val)
+	if val.Function != nil {
+		for _, arg := range val.Function.arguments() {
+			walkValue(arg, fn)
+		}
+	}
+	if val.SubExpr != nil {
+		walkValues(val.SubExpr, fn)
+	}
+	if val.Bitwise != nil {
+		walkValue(val.Bitwise.Left, fn)
+		for _, op := range val.Bitwise.Ops {
+			walkValue(op.Right, fn)
+		}
+	}
+}
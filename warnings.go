@@ -0,0 +1,72 @@
+package where
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Build warning codes reported in BuildWarning.Code.
+const (
+	WarnILIKERewrite        = "ilike-rewrite"
+	WarnQuotedKeyword       = "quoted-keyword"
+	WarnArrayBindingSkipped = "array-binding-skipped"
+	WarnINChunkingSkipped   = "in-chunking-skipped"
+)
+
+// BuildWarning describes SQL that was generated differently than the filter or the build options
+// ask for, e.g. an ILIKE rewritten for a driver without it. Warnings do not affect the SQL, which
+// is still correct, but may be surfaced to operators since the query may be slower than expected.
+type BuildWarning struct {
+	// Code identifies the kind of warning, e.g. WarnILIKERewrite.
+	Code string
+
+	// Message is a human readable description of the warning.
+	Message string
+
+	// Field is the field of the predicate that produced the warning, if any.
+	Field string
+}
+
+// String returns the warning formatted as "code: message".
+func (w BuildWarning) String() string {
+	return fmt.Sprintf("%s: %s", w.Code, w.Message)
+}
+
+// ToSQLWithWarnings converts the filter to SQL like ToSQL, and also returns warnings for ILIKE
+// rewritten as LIKE of lowercased values, identifiers quoted because they are reserved keywords,
+// and options that could not be applied, such as WithArrayBinding for a driver that cannot bind
+// arrays or WithINChunkSize for an IN list on a function. Each warning is reported once.
+//
+// Example:
+//
+//	filter, _ := where.Parse("name ILIKE 'j%' AND order = 1")
+//	sql, params, warnings, _ := filter.ToSQLWithWarnings("mysql")
+//	// warnings[0]: ilike-rewrite: ILIKE is not supported by driver mysql and was rewritten as LIKE with LOWER()
+//	// warnings[1]: quoted-keyword: identifier order was quoted because it is a reserved keyword
+func (f *Filter) ToSQLWithWarnings(driverName string, options ...BuildOption) (string, []any, []BuildWarning, error) {
+	options = append(options, func(b *SQLBuilder) { b.warn = true })
+
+	builder, sql, err := f.build(driverName, options)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	warnings := builder.warnings
+	if warnings == nil {
+		warnings = make([]BuildWarning, 0)
+	}
+	return sql, builder.params, warnings, nil
+}
+
+// warnf records a warning for the predicate being built, unless warnings are not collected or the
+// same warning was already recorded.
+func (b *SQLBuilder) warnf(code, format string, args ...any) {
+	if !b.warn {
+		return
+	}
+
+	warning := BuildWarning{Code: code, Message: fmt.Sprintf(format, args...), Field: b.paramField}
+	if !slices.Contains(b.warnings, warning) {
+		b.warnings = append(b.warnings, warning)
+	}
+}
@@ -0,0 +1,131 @@
+package where_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/where"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSQLWithWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		driver   string
+		options  []where.BuildOption
+		wantSQL  string
+		warnings []where.BuildWarning
+	}{
+		{
+			name:     "no warnings",
+			input:    "age > 18",
+			driver:   "postgres",
+			wantSQL:  "age > $1",
+			warnings: []where.BuildWarning{},
+		},
+		{
+			name:    "ILIKE rewrite",
+			input:   "name ILIKE 'j%' OR email NOT ILIKE '%spam%'",
+			driver:  "mysql",
+			wantSQL: "(LOWER(name) LIKE LOWER(?) OR LOWER(email) NOT LIKE LOWER(?))",
+			warnings: []where.BuildWarning{
+				{Code: where.WarnILIKERewrite, Message: "ILIKE is not supported by driver mysql and was rewritten as LIKE with LOWER()", Field: "name"},
+				{Code: where.WarnILIKERewrite, Message: "ILIKE is not supported by driver mysql and was rewritten as LIKE with LOWER()", Field: "email"},
+			},
+		},
+		{
+			name:     "native ILIKE",
+			input:    "name ILIKE 'j%'",
+			driver:   "postgres",
+			wantSQL:  "name ILIKE $1",
+			warnings: []where.BuildWarning{},
+		},
+		{
+			name:    "quoted keyword reported once",
+			input:   "order = 1 OR order > 5",
+			driver:  "postgres",
+			wantSQL: `("order" = $1 OR "order" > $2)`,
+			warnings: []where.BuildWarning{
+				{Code: where.WarnQuotedKeyword, Message: "identifier order was quoted because it is a reserved keyword for driver postgres", Field: "order"},
+			},
+		},
+		{
+			name:     "quoted special characters",
+			input:    "`first name` = 'a'",
+			driver:   "postgres",
+			wantSQL:  `"first name" = $1`,
+			warnings: []where.BuildWarning{},
+		},
+		{
+			name:    "array binding unsupported",
+			input:   "id IN (1, 2)",
+			driver:  "mysql",
+			options: []where.BuildOption{where.WithArrayBinding()},
+			wantSQL: "id IN (?, ?)",
+			warnings: []where.BuildWarning{
+				{Code: where.WarnArrayBindingSkipped, Message: "IN list was not bound as an array because driver mysql cannot bind arrays", Field: "id"},
+			},
+		},
+		{
+			name:    "array binding with NULL",
+			input:   "id IN (1, NULL)",
+			driver:  "postgres",
+			options: []where.BuildOption{where.WithArrayBinding()},
+			wantSQL: "id IN ($1, NULL)",
+			warnings: []where.BuildWarning{
+				{Code: where.WarnArrayBindingSkipped, Message: "IN list was not bound as an array because it contains NULL or boolean values", Field: "id"},
+			},
+		},
+		{
+			name:    "array binding ignoring case",
+			input:   "status IN ('a', 'b')",
+			driver:  "postgres",
+			options: []where.BuildOption{where.WithArrayBinding(), where.WithCaseInsensitiveEquality("status")},
+			wantSQL: "LOWER(status) IN (LOWER($1), LOWER($2))",
+			warnings: []where.BuildWarning{
+				{Code: where.WarnArrayBindingSkipped, Message: "IN list was not bound as an array because it is compared ignoring case", Field: "status"},
+			},
+		},
+		{
+			name:    "IN chunking on a function",
+			input:   "LOWER(code) IN ('a', 'b', 'c')",
+			driver:  "postgres",
+			options: []where.BuildOption{where.WithINChunkSize(2)},
+			wantSQL: "LOWER(code) IN ($1, $2, $3)",
+			warnings: []where.BuildWarning{
+				{Code: where.WarnINChunkingSkipped, Message: "IN list of 3 values was not chunked because its left side is not a field", Field: "code"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := where.Parse(tt.input)
+			require.NoError(t, err)
+
+			sql, params, warnings, err := filter.ToSQLWithWarnings(tt.driver, tt.options...)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, sql)
+			require.Equal(t, tt.warnings, warnings)
+
+			// Warnings do not change the SQL.
+			wantSQL, wantParams, err := filter.ToSQL(tt.driver, tt.options...)
+			require.NoError(t, err)
+			require.Equal(t, wantSQL, sql)
+			require.Equal(t, wantParams, params)
+		})
+	}
+}
+
+func TestToSQLWithWarningsError(t *testing.T) {
+	filter, err := where.Parse("age > 18")
+	require.NoError(t, err)
+
+	_, _, _, err = filter.ToSQLWithWarnings("nonexistent")
+	require.EqualError(t, err, `failed to get driver "nonexistent": driver "nonexistent" not registered`)
+}
+
+func TestBuildWarningString(t *testing.T) {
+	warning := where.BuildWarning{Code: where.WarnQuotedKeyword, Message: "identifier order was quoted"}
+	require.Equal(t, "quoted-keyword: identifier order was quoted", warning.String())
+}